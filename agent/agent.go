@@ -82,17 +82,30 @@ type Options struct {
 	Logger                 slog.Logger
 	AgentPorts             map[int]string
 	SSHMaxTimeout          time.Duration
+	// PeerHostsFile is the hosts file the hostnames of peered workspaces are
+	// written to. Peering doesn't touch any hosts file if it's empty.
+	PeerHostsFile string
+	// TailnetListenPort and TailnetListenPortEnd are the range of UDP ports
+	// the agent listens on for direct connections. A random port is used if
+	// they're zero.
+	TailnetListenPort    uint16
+	TailnetListenPortEnd uint16
+	// TailnetStaticEndpoints are advertised to peers as endpoints of the
+	// agent, e.g. the address of a node port forwarding to the listen port.
+	TailnetStaticEndpoints []netip.AddrPort
 }
 
 type Client interface {
 	Manifest(ctx context.Context) (agentsdk.Manifest, error)
-	Listen(ctx context.Context) (net.Conn, error)
+	Listen(ctx context.Context, resumeVersion int64) (net.Conn, error)
 	ReportStats(ctx context.Context, log slog.Logger, statsChan <-chan *agentsdk.Stats, setInterval func(time.Duration)) (io.Closer, error)
 	PostLifecycle(ctx context.Context, state agentsdk.PostLifecycleRequest) error
 	PostAppHealth(ctx context.Context, req agentsdk.PostAppHealthsRequest) error
 	PostStartup(ctx context.Context, req agentsdk.PostStartupRequest) error
 	PostMetadata(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error
 	PatchStartupLogs(ctx context.Context, req agentsdk.PatchStartupLogs) error
+	Peers(ctx context.Context) ([]agentsdk.Peer, error)
+	ListenPeer(ctx context.Context, agentID uuid.UUID) (net.Conn, error)
 }
 
 func New(options Options) io.Closer {
@@ -133,6 +146,10 @@ func New(options Options) io.Closer {
 		ignorePorts:            options.AgentPorts,
 		connStatsChan:          make(chan *agentsdk.Stats, 1),
 		sshMaxTimeout:          options.SSHMaxTimeout,
+		peerHostsFile:          options.PeerHostsFile,
+		tailnetListenPort:      options.TailnetListenPort,
+		tailnetListenPortEnd:   options.TailnetListenPortEnd,
+		tailnetStaticEndpoints: options.TailnetStaticEndpoints,
 	}
 	a.init(ctx)
 	return a
@@ -164,6 +181,11 @@ type agent struct {
 	sessionToken  atomic.Pointer[string]
 	sshServer     *ssh.Server
 	sshMaxTimeout time.Duration
+	peerHostsFile string
+
+	tailnetListenPort      uint16
+	tailnetListenPortEnd   uint16
+	tailnetStaticEndpoints []netip.AddrPort
 
 	lifecycleUpdate   chan struct{}
 	lifecycleReported chan codersdk.WorkspaceAgentLifecycle
@@ -173,6 +195,10 @@ type agent struct {
 	network       *tailnet.Conn
 	connStatsChan chan *agentsdk.Stats
 	latestStat    atomic.Pointer[agentsdk.Stats]
+	// coordinatorVersion is the highest version of the client nodes received
+	// from the coordinator. The network keeps the nodes when the coordinator
+	// reconnects, so it resumes from this version.
+	coordinatorVersion atomic.Int64
 
 	connCountVSCode          atomic.Int64
 	connCountJetBrains       atomic.Int64
@@ -187,6 +213,9 @@ type agent struct {
 func (a *agent) runLoop(ctx context.Context) {
 	go a.reportLifecycleLoop(ctx)
 	go a.reportMetadataLoop(ctx)
+	_ = a.trackConnGoroutine(func() {
+		a.runPeering(ctx)
+	})
 
 	for retrier := retry.New(100*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
 		a.logger.Info(ctx, "connecting to coderd")
@@ -560,7 +589,7 @@ func (a *agent) run(ctx context.Context) error {
 	network := a.network
 	a.closeMutex.Unlock()
 	if network == nil {
-		network, err = a.createTailnet(ctx, manifest.DERPMap)
+		network, err = a.createTailnet(ctx, manifest.AgentID, manifest.DERPMap)
 		if err != nil {
 			return xerrors.Errorf("create tailnet: %w", err)
 		}
@@ -604,11 +633,20 @@ func (a *agent) trackConnGoroutine(fn func()) error {
 	return nil
 }
 
-func (a *agent) createTailnet(ctx context.Context, derpMap *tailcfg.DERPMap) (_ *tailnet.Conn, err error) {
+func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *tailcfg.DERPMap) (_ *tailnet.Conn, err error) {
+	addresses := []netip.Prefix{netip.PrefixFrom(codersdk.WorkspaceAgentIP, 128)}
+	if agentID != uuid.Nil {
+		// Every agent shares WorkspaceAgentIP, so peered agents dial each
+		// other at an address that's unique to the agent.
+		addresses = append(addresses, netip.PrefixFrom(tailnet.IPFromUUID(agentID), 128))
+	}
 	network, err := tailnet.NewConn(&tailnet.Options{
-		Addresses: []netip.Prefix{netip.PrefixFrom(codersdk.WorkspaceAgentIP, 128)},
-		DERPMap:   derpMap,
-		Logger:    a.logger.Named("tailnet"),
+		Addresses:       addresses,
+		DERPMap:         derpMap,
+		Logger:          a.logger.Named("tailnet"),
+		ListenPort:      a.tailnetListenPort,
+		ListenPortEnd:   a.tailnetListenPortEnd,
+		StaticEndpoints: a.tailnetStaticEndpoints,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -795,14 +833,24 @@ func (a *agent) runCoordinator(ctx context.Context, network *tailnet.Conn) error
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	coordinator, err := a.client.Listen(ctx)
+	resumeVersion := a.coordinatorVersion.Load()
+	coordinator, err := a.client.Listen(ctx, resumeVersion)
 	if err != nil {
 		return err
 	}
 	defer coordinator.Close()
-	a.logger.Info(ctx, "connected to coordination endpoint")
+	a.logger.Info(ctx, "connected to coordination endpoint", slog.F("resume_version", resumeVersion))
 	sendNodes, errChan := tailnet.ServeCoordinator(coordinator, func(nodes []*tailnet.Node) error {
-		return network.UpdateNodes(nodes, false)
+		err := network.UpdateNodes(nodes, false)
+		if err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			if node.Version > a.coordinatorVersion.Load() {
+				a.coordinatorVersion.Store(node.Version)
+			}
+		}
+		return nil
 	})
 	network.SetNodeCallback(sendNodes)
 	select {
@@ -1417,6 +1417,27 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	expectLine(matchEchoOutput)
 }
 
+func TestAgent_DebugInfo(t *testing.T) {
+	t.Parallel()
+
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	// Only the end of large log files is included.
+	logContent := strings.Repeat("a", 512<<10) + "end of log"
+	err := afero.WriteFile(fs, filepath.Join(os.TempDir(), "coder-test.log"), []byte(logContent), 0o600)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	info, err := conn.DebugInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, runtime.GOOS, info.OperatingSystem)
+	require.NotNil(t, info.Node)
+	require.NotNil(t, info.DERPMap)
+	require.Contains(t, info.Logs, "coder-test.log")
+	require.Len(t, info.Logs["coder-test.log"], 256<<10)
+	require.True(t, strings.HasSuffix(info.Logs["coder-test.log"], "end of log"))
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
@@ -1572,6 +1593,73 @@ func TestAgent_WriteVSCodeConfigs(t *testing.T) {
 	}, testutil.WaitShort, testutil.IntervalFast)
 }
 
+func TestAgent_WorkspacePeering(t *testing.T) {
+	t.Parallel()
+	if !agent.LoopbackForwardingSupported() {
+		t.Skip("Peer ports are only forwarded from loopback addresses on Linux and Windows")
+	}
+
+	coordinator := tailnet.NewCoordinator()
+	defer coordinator.Close()
+
+	peerID := uuid.New()
+	client := &client{
+		t:       t,
+		agentID: uuid.New(),
+		manifest: agentsdk.Manifest{
+			DERPMap:          tailnettest.RunDERPAndSTUN(t),
+			WorkspacePeering: true,
+		},
+		peers: []agentsdk.Peer{{
+			AgentID:       peerID,
+			AgentName:     "main",
+			WorkspaceName: "backend",
+			IP:            tailnet.IPFromUUID(peerID),
+			Hostnames:     []string{"backend.coder", "main.backend.coder"},
+		}},
+		statsChan:   make(chan *agentsdk.Stats),
+		coordinator: coordinator,
+	}
+	filesystem := afero.NewMemMapFs()
+	hostsFile := "/etc/hosts"
+	err := afero.WriteFile(filesystem, hostsFile, []byte("127.0.0.1 localhost\n"), 0o644)
+	require.NoError(t, err)
+	closer := agent.New(agent.Options{
+		ExchangeToken: func(ctx context.Context) (string, error) {
+			return "", nil
+		},
+		Client:        client,
+		Logger:        slogtest.Make(t, nil).Leveled(slog.LevelInfo),
+		Filesystem:    filesystem,
+		PeerHostsFile: hostsFile,
+	})
+
+	require.Eventually(t, func() bool {
+		content, err := afero.ReadFile(filesystem, hostsFile)
+		return err == nil && strings.Contains(string(content), "127.42.0.1 backend.coder main.backend.coder\n")
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// Disabling peering for the workspace disconnects the agent from its
+	// peers.
+	client.setPeeringDisabled(true)
+	require.Eventually(t, func() bool {
+		content, err := afero.ReadFile(filesystem, hostsFile)
+		return err == nil && !strings.Contains(string(content), "backend.coder")
+	}, testutil.WaitShort, testutil.IntervalFast)
+	client.setPeeringDisabled(false)
+	require.Eventually(t, func() bool {
+		content, err := afero.ReadFile(filesystem, hostsFile)
+		return err == nil && strings.Contains(string(content), " backend.coder main.backend.coder\n")
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// Closing the agent removes the peers from the hosts file.
+	err = closer.Close()
+	require.NoError(t, err)
+	content, err := afero.ReadFile(filesystem, hostsFile)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1 localhost\n", string(content))
+}
+
 func setupSSHCommand(t *testing.T, beforeArgs []string, afterArgs []string) *exec.Cmd {
 	//nolint:dogsled
 	agentConn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
@@ -1750,18 +1838,20 @@ type client struct {
 	coordinator        tailnet.Coordinator
 	lastWorkspaceAgent func()
 	patchWorkspaceLogs func() error
+	peers              []agentsdk.Peer
 
 	mu              sync.Mutex // Protects following.
 	lifecycleStates []codersdk.WorkspaceAgentLifecycle
 	startup         agentsdk.PostStartupRequest
 	logs            []agentsdk.StartupLog
+	peeringDisabled bool
 }
 
 func (c *client) Manifest(_ context.Context) (agentsdk.Manifest, error) {
 	return c.manifest, nil
 }
 
-func (c *client) Listen(_ context.Context) (net.Conn, error) {
+func (c *client) Listen(_ context.Context, _ int64) (net.Conn, error) {
 	clientConn, serverConn := net.Pipe()
 	closed := make(chan struct{})
 	c.lastWorkspaceAgent = func() {
@@ -1871,6 +1961,39 @@ func (c *client) PatchStartupLogs(_ context.Context, logs agentsdk.PatchStartupL
 	return nil
 }
 
+func (c *client) Peers(_ context.Context) ([]agentsdk.Peer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.peeringDisabled {
+		return nil, codersdk.ReadBodyAsError(&http.Response{
+			StatusCode: http.StatusForbidden,
+			Body:       io.NopCloser(strings.NewReader("")),
+		})
+	}
+	return c.peers, nil
+}
+
+func (c *client) setPeeringDisabled(disabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.peeringDisabled = disabled
+}
+
+func (c *client) ListenPeer(_ context.Context, agentID uuid.UUID) (net.Conn, error) {
+	clientConn, serverConn := net.Pipe()
+	closed := make(chan struct{})
+	c.t.Cleanup(func() {
+		_ = serverConn.Close()
+		_ = clientConn.Close()
+		<-closed
+	})
+	go func() {
+		_ = c.coordinator.ServeClient(serverConn, uuid.New(), agentID)
+		close(closed)
+	}()
+	return clientConn, nil
+}
+
 // tempDirUnixSocket returns a temporary directory that can safely hold unix
 // sockets (probably).
 //
@@ -28,6 +28,7 @@ func (a *agent) apiHandler() http.Handler {
 
 	lp := &listeningPortsHandler{ignorePorts: cpy}
 	r.Get("/api/v0/listening-ports", lp.handler)
+	r.Get("/api/v0/debug/info", a.debugInfo)
 
 	return r
 }
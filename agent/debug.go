@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/buildinfo"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
+
+// debugLogTailBytes is how much of the end of each log file is included in
+// the debug info.
+const debugLogTailBytes = 256 << 10
+
+// debugInfo serves the state of the agent for support bundles.
+func (a *agent) debugInfo(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	info := codersdk.WorkspaceAgentDebugInfo{
+		Version:         buildinfo.Version(),
+		OperatingSystem: runtime.GOOS,
+		Architecture:    runtime.GOARCH,
+		Logs:            map[string]string{},
+	}
+
+	a.closeMutex.Lock()
+	network := a.network
+	a.closeMutex.Unlock()
+	if network != nil {
+		info.Node = network.Node()
+		info.DERPMap = network.DERPMap()
+	}
+	if stats := a.latestStat.Load(); stats != nil {
+		raw, err := json.Marshal(stats)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		info.Stats = raw
+	}
+
+	paths, err := afero.Glob(a.filesystem, filepath.Join(a.logDir, "coder-*.log"))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	for _, path := range paths {
+		tail, err := a.tailFile(path, debugLogTailBytes)
+		if err != nil {
+			a.logger.Warn(ctx, "read log file for debug info", slog.F("path", path), slog.Error(err))
+			continue
+		}
+		info.Logs[filepath.Base(path)] = tail
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, info)
+}
+
+// tailFile returns up to the last n bytes of the file.
+func (a *agent) tailFile(path string, n int64) (string, error) {
+	f, err := a.filesystem.Open(path)
+	if err != nil {
+		return "", xerrors.Errorf("open: %w", err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return "", xerrors.Errorf("stat: %w", err)
+	}
+	if stat.Size() > n {
+		_, err = f.Seek(stat.Size()-n, io.SeekStart)
+		if err != nil {
+			return "", xerrors.Errorf("seek: %w", err)
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(f, n))
+	if err != nil {
+		return "", xerrors.Errorf("read: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,531 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/netip"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/tailnet"
+	"github.com/coder/retry"
+)
+
+const (
+	peerHostsBegin = "# Added by Coder for workspace peering. Do not edit."
+	peerHostsEnd   = "# End of section added by Coder."
+)
+
+// runPeering keeps connections to the agents of other workspaces while
+// workspace peering is enabled in the manifest. Workspaces opt in to peering
+// separately, and the agent has no peers until its workspace does.
+func (a *agent) runPeering(ctx context.Context) {
+	ticker := time.NewTicker(adjustIntervalForTests(10))
+	defer ticker.Stop()
+
+	var network *PeerNetwork
+	defer func() {
+		if network != nil {
+			network.Close()
+		}
+	}()
+	for {
+		manifest := a.manifest.Load()
+		switch {
+		case manifest != nil && manifest.WorkspacePeering:
+			if network == nil {
+				var err error
+				network, err = NewPeerNetwork(PeerNetworkOptions{
+					Logger:     a.logger.Named("peers"),
+					Client:     a.client,
+					Filesystem: a.filesystem,
+					HostsFile:  a.peerHostsFile,
+					DERPMap:    manifest.DERPMap,
+				})
+				if err != nil {
+					a.logger.Error(ctx, "create peer network", slog.Error(err))
+					break
+				}
+			}
+			network.SetDERPMap(manifest.DERPMap)
+			err := network.Refresh(ctx)
+			if err != nil && ctx.Err() == nil {
+				a.logger.Warn(ctx, "refresh workspace peers", slog.Error(err))
+			}
+		case network != nil:
+			network.Close()
+			network = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PeerClient lists the agents a PeerNetwork connects to and coordinates
+// with them.
+type PeerClient interface {
+	Peers(ctx context.Context) ([]agentsdk.Peer, error)
+	ListenPeer(ctx context.Context, agentID uuid.UUID) (net.Conn, error)
+}
+
+type PeerNetworkOptions struct {
+	Logger slog.Logger
+	Client PeerClient
+	// Filesystem is used to write HostsFile. It defaults to the OS
+	// filesystem.
+	Filesystem afero.Fs
+	// HostsFile is the hosts file the hostnames of peers are written to. No
+	// hosts file is touched if it's empty.
+	HostsFile  string
+	DERPMap    *tailcfg.DERPMap
+	DERPHeader *http.Header
+	// TUNName is passed to the tailnet. Peers are addressed by their tailnet
+	// IP if it's set. Otherwise, the ports every peer listens on are
+	// forwarded from a loopback address unique to the peer.
+	TUNName string
+}
+
+// LoopbackForwardingSupported reports whether the ports of peers can be
+// forwarded without a TUN device. Linux and Windows route all of 127.0.0.0/8
+// to the loopback interface, while macOS and the BSDs only assign 127.0.0.1
+// and listening on the other addresses fails.
+func LoopbackForwardingSupported() bool {
+	return runtime.GOOS == "linux" || runtime.GOOS == "windows"
+}
+
+// PeerNetwork dials the agents of workspaces. For agents, it's a tailnet
+// connection separate from the agent's own network, because every agent holds
+// WorkspaceAgentIP and a connection can't route to an address it holds itself.
+// Peers are dialed at their unique address instead.
+type PeerNetwork struct {
+	logger    slog.Logger
+	client    PeerClient
+	fs        afero.Fs
+	hostsFile string
+	tun       bool
+	conn      *tailnet.Conn
+
+	mutex         sync.Mutex
+	derpMap       *tailcfg.DERPMap
+	peers         map[uuid.UUID]*peer
+	nodeCallbacks map[uuid.UUID]func(node *tailnet.Node)
+	nextLoopback  netip.Addr
+	hostsErr      string
+}
+
+type peer struct {
+	agentsdk.Peer
+	// loopback is the local address the ports of the peer are forwarded
+	// from. The peer's hostnames resolve to it.
+	loopback  netip.Addr
+	listeners map[uint16]net.Listener
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewPeerNetwork(options PeerNetworkOptions) (*PeerNetwork, error) {
+	if options.TUNName == "" && !LoopbackForwardingSupported() {
+		return nil, xerrors.Errorf("forwarding peer ports from loopback addresses other than 127.0.0.1 is not supported on %s, use a TUN device instead", runtime.GOOS)
+	}
+	if options.Filesystem == nil {
+		options.Filesystem = afero.NewOsFs()
+	}
+	conn, err := tailnet.NewConn(&tailnet.Options{
+		Addresses:  []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+		DERPMap:    options.DERPMap,
+		DERPHeader: options.DERPHeader,
+		Logger:     options.Logger.Named("tailnet"),
+		TUNName:    options.TUNName,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("create tailnet: %w", err)
+	}
+	n := &PeerNetwork{
+		logger:        options.Logger,
+		client:        options.Client,
+		fs:            options.Filesystem,
+		hostsFile:     options.HostsFile,
+		tun:           options.TUNName != "",
+		conn:          conn,
+		derpMap:       options.DERPMap,
+		peers:         map[uuid.UUID]*peer{},
+		nodeCallbacks: map[uuid.UUID]func(node *tailnet.Node){},
+		// 127.0.0.0/8 is routed to the loopback interface, so these
+		// addresses can be listened on without any setup.
+		nextLoopback: netip.AddrFrom4([4]byte{127, 42, 0, 1}),
+	}
+	// The node is sent to the coordinator connection of every peer.
+	conn.SetNodeCallback(func(node *tailnet.Node) {
+		n.mutex.Lock()
+		callbacks := make([]func(node *tailnet.Node), 0, len(n.nodeCallbacks))
+		for _, callback := range n.nodeCallbacks {
+			callbacks = append(callbacks, callback)
+		}
+		n.mutex.Unlock()
+		for _, callback := range callbacks {
+			callback(node)
+		}
+	})
+	return n, nil
+}
+
+// SetDERPMap updates the DERP map of the tailnet if it changed.
+func (n *PeerNetwork) SetDERPMap(derpMap *tailcfg.DERPMap) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if derpMap == nil || derpMap == n.derpMap {
+		return
+	}
+	n.derpMap = derpMap
+	n.conn.SetDERPMap(derpMap)
+}
+
+// Refresh connects to new peers, disconnects from removed ones and forwards
+// the ports the peers listen on.
+func (n *PeerNetwork) Refresh(ctx context.Context) error {
+	peers, err := n.client.Peers(ctx)
+	var sdkErr *codersdk.Error
+	if errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusForbidden {
+		// Peering was disabled for the workspace or the deployment, so
+		// disconnect from every peer.
+		peers, err = nil, nil
+	}
+	if err != nil {
+		return xerrors.Errorf("get peers: %w", err)
+	}
+
+	n.mutex.Lock()
+	current := make(map[uuid.UUID]struct{}, len(peers))
+	for _, p := range peers {
+		current[p.AgentID] = struct{}{}
+		if existing, ok := n.peers[p.AgentID]; ok {
+			existing.Peer = p
+			continue
+		}
+		n.peers[p.AgentID] = n.startPeer(ctx, p)
+	}
+	var removed []*peer
+	for id, p := range n.peers {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		delete(n.peers, id)
+		removed = append(removed, p)
+	}
+	active := make([]*peer, 0, len(n.peers))
+	for _, p := range n.peers {
+		active = append(active, p)
+	}
+	n.mutex.Unlock()
+
+	for _, p := range removed {
+		n.closePeer(p)
+	}
+	// The tailnet addresses of peers are routed to the TUN device, so
+	// there's nothing to forward.
+	if !n.tun {
+		for _, p := range active {
+			n.forwardPorts(ctx, p)
+		}
+	}
+	n.writeHosts(ctx)
+	return nil
+}
+
+// Hosts returns the address each hostname of every peer resolves to.
+func (n *PeerNetwork) Hosts() map[string]netip.Addr {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	hosts := map[string]netip.Addr{}
+	for _, p := range n.peers {
+		for _, hostname := range p.Hostnames {
+			hosts[hostname] = n.address(p)
+		}
+	}
+	return hosts
+}
+
+// address is the address the hostnames of the peer resolve to.
+func (n *PeerNetwork) address(p *peer) netip.Addr {
+	if n.tun {
+		return p.IP
+	}
+	return p.loopback
+}
+
+// startPeer coordinates with the peer until it's closed. The mutex must be
+// held.
+func (n *PeerNetwork) startPeer(ctx context.Context, p agentsdk.Peer) *peer {
+	ctx, cancel := context.WithCancel(ctx)
+	started := &peer{
+		Peer:      p,
+		loopback:  n.nextLoopback,
+		listeners: map[uint16]net.Listener{},
+		cancel:    cancel,
+	}
+	n.nextLoopback = n.nextLoopback.Next()
+
+	logger := n.logger.With(slog.F("peer_id", p.AgentID))
+	started.wg.Add(1)
+	go func() {
+		defer started.wg.Done()
+		for retrier := retry.New(100*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
+			err := n.coordinate(ctx, p.AgentID)
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Debug(ctx, "peer coordinator disconnected", slog.Error(err))
+		}
+	}()
+	return started
+}
+
+func (n *PeerNetwork) coordinate(ctx context.Context, agentID uuid.UUID) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	coordinator, err := n.client.ListenPeer(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	defer coordinator.Close()
+	sendNode, errChan := tailnet.ServeCoordinator(coordinator, func(nodes []*tailnet.Node) error {
+		return n.conn.UpdateNodes(nodes, false)
+	})
+	n.mutex.Lock()
+	n.nodeCallbacks[agentID] = sendNode
+	n.mutex.Unlock()
+	defer func() {
+		n.mutex.Lock()
+		delete(n.nodeCallbacks, agentID)
+		n.mutex.Unlock()
+	}()
+	// The peer needs our node to establish a connection.
+	sendNode(n.conn.Node())
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errChan:
+		return err
+	}
+}
+
+// forwardPorts listens on the loopback address of the peer for every port
+// the peer listens on.
+func (n *PeerNetwork) forwardPorts(ctx context.Context, p *peer) {
+	logger := n.logger.With(slog.F("peer_id", p.AgentID))
+	ports, err := n.listeningPorts(ctx, p.IP)
+	if err != nil {
+		// The peer may not be reachable yet.
+		logger.Debug(ctx, "get peer listening ports", slog.Error(err))
+		return
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	listening := make(map[uint16]struct{}, len(ports))
+	for _, port := range ports {
+		if port.Network != "tcp" {
+			continue
+		}
+		listening[port.Port] = struct{}{}
+		if _, ok := p.listeners[port.Port]; ok {
+			continue
+		}
+		listener, err := net.Listen("tcp", netip.AddrPortFrom(p.loopback, port.Port).String())
+		if err != nil {
+			logger.Debug(ctx, "listen for peer port", slog.F("port", port.Port), slog.Error(err))
+			continue
+		}
+		p.listeners[port.Port] = listener
+		p.wg.Add(1)
+		go func(port uint16) {
+			defer p.wg.Done()
+			n.serveForward(ctx, listener, netip.AddrPortFrom(p.IP, port))
+		}(port.Port)
+	}
+	for port, listener := range p.listeners {
+		if _, ok := listening[port]; ok {
+			continue
+		}
+		_ = listener.Close()
+		delete(p.listeners, port)
+	}
+}
+
+func (n *PeerNetwork) serveForward(ctx context.Context, listener net.Listener, remote netip.AddrPort) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		local, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := n.conn.DialContextTCP(ctx, remote)
+			if err != nil {
+				_ = local.Close()
+				n.logger.Debug(ctx, "dial peer", slog.F("address", remote), slog.Error(err))
+				return
+			}
+			Bicopy(ctx, local, conn)
+		}()
+	}
+}
+
+// listeningPorts fetches the ports the peer listens on from its HTTP API.
+func (n *PeerNetwork) listeningPorts(ctx context.Context, ip netip.Addr) ([]codersdk.WorkspaceAgentListeningPort, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	client := &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return n.conn.DialContextTCP(ctx, netip.AddrPortFrom(ip, codersdk.WorkspaceAgentHTTPAPIServerPort))
+			},
+		},
+	}
+	host := net.JoinHostPort(ip.String(), strconv.Itoa(codersdk.WorkspaceAgentHTTPAPIServerPort))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/api/v0/listening-ports", host), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	var resp codersdk.WorkspaceAgentListeningPortsResponse
+	return resp.Ports, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// writeHosts maps the hostnames of every peer to its address in the hosts
+// file.
+func (n *PeerNetwork) writeHosts(ctx context.Context) {
+	if n.hostsFile == "" {
+		return
+	}
+	n.mutex.Lock()
+	lines := make([]string, 0, len(n.peers))
+	for _, p := range n.peers {
+		lines = append(lines, n.address(p).String()+" "+strings.Join(p.Hostnames, " "))
+	}
+	n.mutex.Unlock()
+	sort.Strings(lines)
+
+	err := n.updateHostsFile(lines)
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	n.mutex.Lock()
+	logErr := errMsg != "" && errMsg != n.hostsErr
+	n.hostsErr = errMsg
+	n.mutex.Unlock()
+	// Only log once, since an agent that isn't running as root usually can't
+	// write to the hosts file.
+	if logErr {
+		n.logger.Warn(ctx, "update hosts file for workspace peers", slog.F("path", n.hostsFile), slog.Error(err))
+	}
+}
+
+func (n *PeerNetwork) updateHostsFile(lines []string) error {
+	existing, err := afero.ReadFile(n.fs, n.hostsFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return xerrors.Errorf("read hosts file: %w", err)
+	}
+
+	var content bytes.Buffer
+	inSection := false
+	for _, line := range strings.SplitAfter(string(existing), "\n") {
+		switch strings.TrimSpace(line) {
+		case peerHostsBegin:
+			inSection = true
+			continue
+		case peerHostsEnd:
+			inSection = false
+			continue
+		}
+		if !inSection {
+			_, _ = content.WriteString(line)
+		}
+	}
+	if len(lines) > 0 {
+		if content.Len() > 0 && !bytes.HasSuffix(content.Bytes(), []byte("\n")) {
+			_ = content.WriteByte('\n')
+		}
+		_, _ = content.WriteString(peerHostsBegin + "\n")
+		for _, line := range lines {
+			_, _ = content.WriteString(line + "\n")
+		}
+		_, _ = content.WriteString(peerHostsEnd + "\n")
+	}
+	if bytes.Equal(content.Bytes(), existing) {
+		return nil
+	}
+	// The file is written in place since /etc/hosts is often bind mounted
+	// into containers and can't be replaced.
+	err = afero.WriteFile(n.fs, n.hostsFile, content.Bytes(), 0o644)
+	if err != nil {
+		return xerrors.Errorf("write hosts file: %w", err)
+	}
+	return nil
+}
+
+func (n *PeerNetwork) closePeer(p *peer) {
+	p.cancel()
+	n.mutex.Lock()
+	for port, listener := range p.listeners {
+		_ = listener.Close()
+		delete(p.listeners, port)
+	}
+	n.mutex.Unlock()
+	p.wg.Wait()
+}
+
+// Close disconnects from every peer and removes their hostnames from the
+// hosts file.
+func (n *PeerNetwork) Close() {
+	n.mutex.Lock()
+	peers := make([]*peer, 0, len(n.peers))
+	for id, p := range n.peers {
+		peers = append(peers, p)
+		delete(n.peers, id)
+	}
+	n.mutex.Unlock()
+	for _, p := range peers {
+		n.closePeer(p)
+	}
+	if n.hostsFile != "" {
+		_ = n.updateHostsFile(nil)
+	}
+	_ = n.conn.Close()
+}
@@ -6,12 +6,14 @@ import (
 	"io"
 	"net/http"
 	"net/http/pprof"
+	"net/netip"
 	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,6 +37,10 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		pprofAddress  string
 		noReap        bool
 		sshMaxTimeout time.Duration
+		peerHostsFile string
+
+		tailnetListenPort      string
+		tailnetStaticEndpoints []string
 	)
 	cmd := &clibase.Cmd{
 		Use:   "agent",
@@ -186,6 +192,19 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				return xerrors.Errorf("add executable to $PATH: %w", err)
 			}
 
+			listenPort, listenPortEnd, err := parseListenPortRange(tailnetListenPort)
+			if err != nil {
+				return xerrors.Errorf("parse tailnet listen port: %w", err)
+			}
+			staticEndpoints := make([]netip.AddrPort, 0, len(tailnetStaticEndpoints))
+			for _, endpoint := range tailnetStaticEndpoints {
+				addrPort, err := netip.ParseAddrPort(endpoint)
+				if err != nil {
+					return xerrors.Errorf("parse tailnet static endpoint %q: %w", endpoint, err)
+				}
+				staticEndpoints = append(staticEndpoints, addrPort)
+			}
+
 			closer := agent.New(agent.Options{
 				Client: client,
 				Logger: logger,
@@ -206,6 +225,11 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				},
 				AgentPorts:    agentPorts,
 				SSHMaxTimeout: sshMaxTimeout,
+				PeerHostsFile: peerHostsFile,
+
+				TailnetListenPort:      listenPort,
+				TailnetListenPortEnd:   listenPortEnd,
+				TailnetStaticEndpoints: staticEndpoints,
 			})
 			<-ctx.Done()
 			return closer.Close()
@@ -248,6 +272,25 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Description: "Specify the max timeout for a SSH connection.",
 			Value:       clibase.DurationOf(&sshMaxTimeout),
 		},
+		{
+			Flag:        "peer-hosts-file",
+			Default:     "/etc/hosts",
+			Env:         "CODER_AGENT_PEER_HOSTS_FILE",
+			Description: "The hosts file to add the hostnames of peered workspaces to. Set to an empty string to disable.",
+			Value:       clibase.StringOf(&peerHostsFile),
+		},
+		{
+			Flag:        "tailnet-listen-port",
+			Env:         "CODER_AGENT_TAILNET_LISTEN_PORT",
+			Description: "The UDP port, or range of ports like 41641-41650, to listen on for direct connections. The first free port of a range is used. A random port is used if it's empty.",
+			Value:       clibase.StringOf(&tailnetListenPort),
+		},
+		{
+			Flag:        "tailnet-static-endpoints",
+			Env:         "CODER_AGENT_TAILNET_STATIC_ENDPOINTS",
+			Description: "Addresses in the form ip:port that forward to the listen port, e.g. of a Kubernetes node port or load balancer. They are advertised to clients so direct connections work without NAT traversal.",
+			Value:       clibase.StringArrayOf(&tailnetStaticEndpoints),
+		},
 	}
 
 	return cmd
@@ -334,3 +377,24 @@ func urlPort(u string) (int, error) {
 	}
 	return -1, xerrors.Errorf("invalid port: %s", u)
 }
+
+// parseListenPortRange parses a port like "41641" or a range of ports like
+// "41641-41650". Both ports are zero if s is empty.
+func parseListenPortRange(s string) (start uint16, end uint16, err error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	startStr, endStr, isRange := strings.Cut(s, "-")
+	start64, err := strconv.ParseUint(strings.TrimSpace(startStr), 10, 16)
+	if err != nil || start64 == 0 {
+		return 0, 0, xerrors.Errorf("invalid port %q", startStr)
+	}
+	if !isRange {
+		return uint16(start64), uint16(start64), nil
+	}
+	end64, err := strconv.ParseUint(strings.TrimSpace(endStr), 10, 16)
+	if err != nil || end64 < start64 {
+		return 0, 0, xerrors.Errorf("invalid end of port range %q", endStr)
+	}
+	return uint16(start64), uint16(end64), nil
+}
@@ -61,3 +61,77 @@ func Test_extractPort(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseListenPortRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		value     string
+		wantStart uint16
+		wantEnd   uint16
+		wantErr   bool
+	}{
+		{
+			name:  "Empty",
+			value: "",
+		},
+		{
+			name:      "Port",
+			value:     "41641",
+			wantStart: 41641,
+			wantEnd:   41641,
+		},
+		{
+			name:      "Range",
+			value:     "41641-41650",
+			wantStart: 41641,
+			wantEnd:   41650,
+		},
+		{
+			name:      "RangeWithSpaces",
+			value:     "41641 - 41650",
+			wantStart: 41641,
+			wantEnd:   41650,
+		},
+		{
+			name:    "Zero",
+			value:   "0",
+			wantErr: true,
+		},
+		{
+			name:    "NotANumber",
+			value:   "port",
+			wantErr: true,
+		},
+		{
+			name:    "TooLarge",
+			value:   "65536",
+			wantErr: true,
+		},
+		{
+			name:    "EndBeforeStart",
+			value:   "41650-41641",
+			wantErr: true,
+		},
+		{
+			name:    "MissingEnd",
+			value:   "41641-",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			start, end, err := parseListenPortRange(tt.value)
+			if tt.wantErr {
+				require.Error(t, err, fmt.Sprintf("parseListenPortRange(%q)", tt.value))
+				return
+			}
+			require.NoError(t, err, fmt.Sprintf("parseListenPortRange(%q)", tt.value))
+			require.Equal(t, tt.wantStart, start)
+			require.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
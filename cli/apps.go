@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) apps() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "apps",
+		Short:       "Inspect the apps of a workspace",
+		Long: formatExamples(
+			example{
+				Description: "Show the latest requests to the apps and ports of a workspace",
+				Command:     "coder apps logs my-workspace",
+			},
+			example{
+				Description: "Show the requests to a single app as JSON",
+				Command:     "coder apps logs my-workspace --app code-server --output json",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.appsLogs(),
+		},
+	}
+	return cmd
+}
+
+type appAccessLogRow struct {
+	// For JSON format:
+	codersdk.WorkspaceAppAccessLog `table:"-"`
+
+	// For table format:
+	Time     time.Time `json:"-" table:"time,default_sort"`
+	User     string    `json:"-" table:"user"`
+	App      string    `json:"-" table:"app"`
+	Level    string    `json:"-" table:"level"`
+	IP       string    `json:"-" table:"ip"`
+	Method   string    `json:"-" table:"method"`
+	Path     string    `json:"-" table:"path"`
+	Status   int32     `json:"-" table:"status"`
+	Bytes    int64     `json:"-" table:"bytes"`
+	Duration string    `json:"-" table:"duration"`
+}
+
+func (r *RootCmd) appsLogs() *clibase.Cmd {
+	var (
+		app   string
+		limit int64
+	)
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]appAccessLogRow{}, []string{"time", "user", "app", "level", "method", "path", "status", "bytes", "duration"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "logs <workspace>",
+		Short: "Show the access logs of the apps and ports of a workspace",
+		Long: "Every request that is proxied to an app or a shared port of the workspace\n" +
+			"is logged, including unauthenticated requests to public apps. Access logs\n" +
+			"are deleted after the retention period configured by the administrator.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			logs, err := client.WorkspaceAppAccessLogs(ctx, workspace.ID, codersdk.WorkspaceAppAccessLogsRequest{
+				App: app,
+				Pagination: codersdk.Pagination{
+					Limit: int(limit),
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("get app access logs: %w", err)
+			}
+			if len(logs) == 0 {
+				cliui.Infof(inv.Stdout, "No requests have been made to the apps of this workspace.\n")
+				return nil
+			}
+
+			rows := make([]appAccessLogRow, 0, len(logs))
+			for _, log := range logs {
+				user := log.Username
+				if user == "" {
+					user = "anonymous"
+				}
+				rows = append(rows, appAccessLogRow{
+					WorkspaceAppAccessLog: log,
+					Time:                  log.CreatedAt,
+					User:                  user,
+					App:                   log.AppSlugOrPort,
+					Level:                 string(log.SharingLevel),
+					IP:                    log.IP,
+					Method:                log.Method,
+					Path:                  log.Path,
+					Status:                log.StatusCode,
+					Bytes:                 log.BytesWritten,
+					Duration:              (time.Duration(log.DurationMS) * time.Millisecond).String(),
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "app",
+			Description: "Only show the requests to this app slug or port.",
+			Value:       clibase.StringOf(&app),
+		},
+		{
+			Flag:        "limit",
+			Description: "The maximum number of requests to show, starting with the most recent.",
+			Default:     "100",
+			Value:       clibase.Int64Of(&limit),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
@@ -0,0 +1,82 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtestutil"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestAppsLogs(t *testing.T) {
+	t.Parallel()
+
+	db, pubsub := dbtestutil.NewDB(t)
+	client := coderdtest.New(t, &coderdtest.Options{
+		Database:                 db,
+		Pubsub:                   pubsub,
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	inv, root := clitest.New(t, "apps", "logs", workspace.Name)
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "No requests")
+
+	dbgen.WorkspaceAppAccessLog(t, db, database.WorkspaceAppAccessLog{
+		UserID:        uuid.NullUUID{UUID: user.UserID, Valid: true},
+		WorkspaceID:   uuid.NullUUID{UUID: workspace.ID, Valid: true},
+		AppSlugOrPort: "code-server",
+		Path:          "/login",
+	})
+	dbgen.WorkspaceAppAccessLog(t, db, database.WorkspaceAppAccessLog{
+		WorkspaceID:   uuid.NullUUID{UUID: workspace.ID, Valid: true},
+		AppSlugOrPort: "8080",
+		SharingLevel:  database.AppSharingLevelPublic,
+		StatusCode:    http.StatusNotFound,
+	})
+
+	inv, root = clitest.New(t, "apps", "logs", workspace.Name)
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "code-server")
+	require.Contains(t, buf.String(), "anonymous")
+
+	inv, root = clitest.New(t, "apps", "logs", workspace.Name, "--app", "code-server", "--output=json")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var logs []codersdk.WorkspaceAppAccessLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logs))
+	require.Len(t, logs, 1)
+	require.Equal(t, "/login", logs[0].Path)
+	require.Equal(t, coderdtest.FirstUserParams.Username, logs[0].Username)
+}
@@ -7,10 +7,13 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mattn/go-isatty"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/codersdk"
@@ -64,6 +67,16 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 		currentStage          = "Queued"
 		currentStageStartedAt = time.Now().UTC()
 		didLogBetweenStage    = false
+		// lastResourceAddress is the resource whose progress was written on
+		// the last line, so further progress for it can replace that line.
+		lastResourceAddress = ""
+		// Lines are only replaced on terminals. Elsewhere, like in CI logs,
+		// the escape codes would be printed as-is, so lines are appended.
+		replaceLines = isTerminal(writer)
+
+		// warnedUnmatched is set once the user has been told that no
+		// provisioner daemon can acquire the job.
+		warnedUnmatched = false
 
 		errChan  = make(chan error, 1)
 		job      codersdk.ProvisionerJob
@@ -77,7 +90,7 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 	updateStage := func(stage string, startedAt time.Time) {
 		if currentStage != "" {
 			prefix := ""
-			if !didLogBetweenStage {
+			if replaceLines && !didLogBetweenStage {
 				prefix = "\033[1A\r"
 			}
 			mark := Styles.Checkmark
@@ -92,6 +105,7 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 		currentStage = stage
 		currentStageStartedAt = startedAt
 		didLogBetweenStage = false
+		lastResourceAddress = ""
 		printStage()
 	}
 
@@ -105,6 +119,15 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 			return
 		}
 		if job.StartedAt == nil {
+			if !job.HasMatchingDaemon && !warnedUnmatched {
+				warnedUnmatched = true
+				didLogBetweenStage = true
+				Warn(writer,
+					"No online provisioner daemon matches this job's tags.",
+					"Tags: "+formatProvisionerTags(job.Tags),
+					"The job will stay queued until a matching daemon comes online.",
+				)
+			}
 			return
 		}
 		if currentStage != "Queued" {
@@ -222,7 +245,17 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 				jobMutex.Unlock()
 				continue
 			}
-			_, _ = fmt.Fprintf(logOutput, "%s %s\n", Styles.Placeholder.Render(" "), output)
+			if log.ResourceProgress != nil {
+				prefix := ""
+				if replaceLines && lastResourceAddress == log.ResourceProgress.Address {
+					prefix = "\033[1A\r\033[2K"
+				}
+				_, _ = fmt.Fprintf(logOutput, "%s%s %s\n", prefix, Styles.Placeholder.Render(" "), resourceProgress(*log.ResourceProgress))
+				lastResourceAddress = log.ResourceProgress.Address
+			} else {
+				_, _ = fmt.Fprintf(logOutput, "%s %s\n", Styles.Placeholder.Render(" "), output)
+				lastResourceAddress = ""
+			}
 			if !opts.Silent {
 				didLogBetweenStage = true
 			}
@@ -230,3 +263,36 @@ func ProvisionerJob(ctx context.Context, writer io.Writer, opts ProvisionerJobOp
 		}
 	}
 }
+
+// isTerminal returns whether the writer is a terminal that supports escape
+// codes to move the cursor.
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(file.Fd())
+}
+
+// resourceProgress renders the progress of an action on a single resource
+// with the time it has taken so far, e.g. "✔ docker_container.dev create [12s]".
+func resourceProgress(progress codersdk.ProvisionerJobResourceProgress) string {
+	mark := Styles.Prompt.Render("⧗")
+	switch progress.Status {
+	case codersdk.ProvisionerJobResourceStatusComplete:
+		mark = Styles.Checkmark.String()
+	case codersdk.ProvisionerJobResourceStatusErrored:
+		mark = Styles.Crossmark.String()
+	}
+	return fmt.Sprintf("%s %s %s", mark, progress.Address,
+		Styles.Placeholder.Render(fmt.Sprintf("%s [%ds]", progress.Action, progress.ElapsedSeconds)))
+}
+
+func formatProvisionerTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -1,6 +1,7 @@
 package cliui_test
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/cli/clibase"
 	"github.com/coder/coder/cli/cliui"
@@ -48,6 +50,31 @@ func TestProvisionerJob(t *testing.T) {
 		test.PTY.ExpectMatch("Running")
 	})
 
+	t.Run("Unmatched", func(t *testing.T) {
+		t.Parallel()
+
+		test := newProvisionerJob(t)
+		go func() {
+			<-test.Next
+			test.JobMutex.Lock()
+			test.Job.Status = codersdk.ProvisionerJobCanceled
+			now := database.Now()
+			test.Job.CanceledAt = &now
+			test.Job.CompletedAt = &now
+			close(test.Logs)
+			test.JobMutex.Unlock()
+		}()
+		test.PTY.ExpectMatch("Queued")
+		test.JobMutex.Lock()
+		test.Job.HasMatchingDaemon = false
+		test.Job.Tags = map[string]string{"scope": "organization", "region": "mars"}
+		test.JobMutex.Unlock()
+		test.PTY.ExpectMatch("No online provisioner daemon matches")
+		test.PTY.ExpectMatch("region=mars scope=organization")
+		test.Next <- struct{}{}
+		test.PTY.ExpectMatch("Queued")
+	})
+
 	t.Run("Stages", func(t *testing.T) {
 		t.Parallel()
 
@@ -79,6 +106,100 @@ func TestProvisionerJob(t *testing.T) {
 		test.PTY.ExpectMatch("Something")
 	})
 
+	t.Run("ResourceProgress", func(t *testing.T) {
+		t.Parallel()
+
+		test := newProvisionerJob(t)
+		go func() {
+			<-test.Next
+			test.JobMutex.Lock()
+			test.Job.Status = codersdk.ProvisionerJobRunning
+			now := database.Now()
+			test.Job.StartedAt = &now
+			test.JobMutex.Unlock()
+			for _, progress := range []codersdk.ProvisionerJobResourceProgress{{
+				Address: "docker_container.workspace[0]",
+				Action:  "create",
+				Status:  codersdk.ProvisionerJobResourceStatusStarted,
+			}, {
+				Address:        "docker_container.workspace[0]",
+				Action:         "create",
+				Status:         codersdk.ProvisionerJobResourceStatusComplete,
+				ElapsedSeconds: 12,
+			}} {
+				progress := progress
+				test.Logs <- codersdk.ProvisionerJobLog{
+					CreatedAt:        database.Now(),
+					Level:            codersdk.LogLevelInfo,
+					Output:           "docker_container.workspace[0]: Creating...",
+					ResourceProgress: &progress,
+				}
+			}
+			<-test.Next
+			test.JobMutex.Lock()
+			test.Job.Status = codersdk.ProvisionerJobSucceeded
+			now = database.Now()
+			test.Job.CompletedAt = &now
+			close(test.Logs)
+			test.JobMutex.Unlock()
+		}()
+		test.PTY.ExpectMatch("Queued")
+		test.Next <- struct{}{}
+		test.PTY.ExpectMatch("docker_container.workspace[0]")
+		test.PTY.ExpectMatch("create [0s]")
+		test.PTY.ExpectMatch("create [12s]")
+		test.Next <- struct{}{}
+	})
+
+	t.Run("NotTerminal", func(t *testing.T) {
+		t.Parallel()
+
+		now := database.Now()
+		logs := make(chan codersdk.ProvisionerJobLog, 2)
+		for _, progress := range []codersdk.ProvisionerJobResourceProgress{{
+			Address: "docker_container.workspace[0]",
+			Action:  "create",
+			Status:  codersdk.ProvisionerJobResourceStatusStarted,
+		}, {
+			Address:        "docker_container.workspace[0]",
+			Action:         "create",
+			Status:         codersdk.ProvisionerJobResourceStatusComplete,
+			ElapsedSeconds: 12,
+		}} {
+			progress := progress
+			logs <- codersdk.ProvisionerJobLog{
+				CreatedAt:        now,
+				Level:            codersdk.LogLevelInfo,
+				ResourceProgress: &progress,
+			}
+		}
+		close(logs)
+
+		var output bytes.Buffer
+		err := cliui.ProvisionerJob(context.Background(), &output, cliui.ProvisionerJobOptions{
+			FetchInterval: time.Millisecond,
+			Fetch: func() (codersdk.ProvisionerJob, error) {
+				return codersdk.ProvisionerJob{
+					Status:            codersdk.ProvisionerJobSucceeded,
+					StartedAt:         &now,
+					CompletedAt:       &now,
+					HasMatchingDaemon: true,
+				}, nil
+			},
+			Logs: func() (<-chan codersdk.ProvisionerJobLog, io.Closer, error) {
+				return logs, closeFunc(func() error {
+					return nil
+				}), nil
+			},
+		})
+		require.NoError(t, err)
+		// Every update is appended as a line, without escape codes that
+		// replace previous lines.
+		require.NotContains(t, output.String(), "\033[1A")
+		require.Contains(t, output.String(), "create [0s]")
+		require.Contains(t, output.String(), "create [12s]")
+	})
+
 	// This cannot be ran in parallel because it uses a signal.
 	// nolint:paralleltest
 	t.Run("Cancel", func(t *testing.T) {
@@ -120,8 +241,9 @@ type provisionerJobTest struct {
 
 func newProvisionerJob(t *testing.T) provisionerJobTest {
 	job := &codersdk.ProvisionerJob{
-		Status:    codersdk.ProvisionerJobPending,
-		CreatedAt: database.Now(),
+		Status:            codersdk.ProvisionerJobPending,
+		CreatedAt:         database.Now(),
+		HasMatchingDaemon: true,
 	}
 	jobLock := sync.Mutex{}
 	logs := make(chan codersdk.ProvisionerJobLog, 1)
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) externalAuth() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "external-auth",
+		Short: "Manage external authentication",
+		Long:  "Authenticate with external services, such as Git providers, inside of a workspace.",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.externalAuthAccessToken(),
+		},
+	}
+}
+
+func (r *RootCmd) externalAuthAccessToken() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "access-token <provider>",
+		Short: "Print an access token for an external auth provider",
+		Long: "Prints an access token of the workspace owner for the external auth\n" +
+			"provider with the given ID, refreshing it if it expired. If the owner\n" +
+			"hasn't authenticated with the provider, the URL to authenticate is\n" +
+			"printed instead and the command fails. Only works inside of a workspace.\n" + formatExamples(
+			example{
+				Description: "Use the GitHub CLI with the token of the \"github\" provider",
+				Command:     "GH_TOKEN=$(coder external-auth access-token github) gh repo list",
+			},
+			example{
+				Description: "Log in to a container registry hosted by the provider",
+				Command:     "coder external-auth access-token github | docker login ghcr.io --username oauth2 --password-stdin",
+			},
+		),
+		Middleware: clibase.RequireNArgs(1),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			client, err := r.createAgentClient()
+			if err != nil {
+				return xerrors.Errorf("create agent client: %w", err)
+			}
+
+			token, err := client.GitAuthAccessToken(ctx, inv.Args[0])
+			if err != nil {
+				var apiError *codersdk.Error
+				if errors.As(err, &apiError) && apiError.StatusCode() == http.StatusNotFound {
+					cliui.Errorf(inv.Stderr, "%s\n", apiError.Message)
+					return cliui.Canceled
+				}
+				return xerrors.Errorf("get access token: %w", err)
+			}
+			if token.URL != "" {
+				cliui.Errorf(inv.Stderr, "Open the following URL to authenticate with %q:\n\n\t%s\n\n", inv.Args[0], token.URL)
+				return cliui.Canceled
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, token.AccessToken)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/pty/ptytest"
+)
+
+func TestExternalAuthAccessToken(t *testing.T) {
+	t.Parallel()
+	t.Run("Token", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v2/workspaceagents/me/gitauth/github/access-token", r.URL.Path)
+			httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthAccessTokenResponse{
+				AccessToken: "bananas",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "external-auth", "access-token", "github")
+		pty := ptytest.New(t)
+		inv.Stdout = pty.Output()
+		clitest.Start(t, inv)
+		pty.ExpectMatch("bananas")
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthAccessTokenResponse{
+				URL: "https://example.com/gitauth/github",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "external-auth", "access-token", "github")
+		pty := ptytest.New(t)
+		inv.Stderr = pty.Output()
+		err := inv.Run()
+		require.ErrorIs(t, err, cliui.Canceled)
+		pty.ExpectMatch("https://example.com/gitauth/github")
+	})
+
+	t.Run("NoProvider", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httpapi.Write(context.Background(), w, http.StatusNotFound, codersdk.Response{
+				Message: "Nope!",
+			})
+		}))
+		t.Cleanup(srv.Close)
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "external-auth", "access-token", "github")
+		pty := ptytest.New(t)
+		inv.Stderr = pty.Output()
+		err := inv.Run()
+		require.ErrorIs(t, err, cliui.Canceled)
+		pty.ExpectMatch("Nope!")
+	})
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/retry"
 )
 
@@ -49,7 +51,12 @@ func (r *RootCmd) gitAskpass() *clibase.Cmd {
 				}
 				return xerrors.Errorf("get git token: %w", err)
 			}
-			if token.URL != "" {
+			if token.URL != "" && token.DeviceFlow {
+				token, err = gitAuthDevice(ctx, inv, client, host)
+				if err != nil {
+					return err
+				}
+			} else if token.URL != "" {
 				if err := openURL(inv, token.URL); err == nil {
 					cliui.Infof(inv.Stdout, "Your browser has been opened to authenticate with Git:\n\n\t%s\n\n", token.URL)
 				} else {
@@ -80,3 +87,47 @@ func (r *RootCmd) gitAskpass() *clibase.Cmd {
 		},
 	}
 }
+
+// gitAuthDevice authenticates with the device flow, which doesn't require a
+// browser on the machine running git. Instructions are written to stderr,
+// since git reads the credential from stdout.
+func gitAuthDevice(ctx context.Context, inv *clibase.Invocation, client *agentsdk.Client, host string) (agentsdk.GitAuthResponse, error) {
+	device, err := client.GitAuthDevice(ctx, host)
+	if err != nil {
+		return agentsdk.GitAuthResponse{}, xerrors.Errorf("begin device flow: %w", err)
+	}
+	cliui.Infof(inv.Stderr, "To authenticate with Git, open the following URL and enter the code %s\n\n\t%s\n\n", cliui.Styles.Code.Render(device.UserCode), device.VerificationURI)
+
+	interval := time.Duration(device.Interval) * time.Second
+	if device.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(device.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return agentsdk.GitAuthResponse{}, xerrors.Errorf("device code expired: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+		token, err := client.GitAuthDeviceExchange(ctx, agentsdk.GitAuthDeviceExchange{
+			URL:        host,
+			DeviceCode: device.DeviceCode,
+		})
+		if errors.Is(err, agentsdk.ErrGitAuthDevicePending) {
+			continue
+		}
+		var apiError *codersdk.Error
+		if errors.As(err, &apiError) && apiError.StatusCode() == http.StatusTooManyRequests {
+			// The specification requires increasing the interval by five
+			// seconds.
+			interval += 5 * time.Second
+			continue
+		}
+		if err != nil {
+			return agentsdk.GitAuthResponse{}, xerrors.Errorf("exchange device code: %w", err)
+		}
+		cliui.Infof(inv.Stderr, "You've been authenticated with Git!\n")
+		return token, nil
+	}
+}
@@ -99,4 +99,46 @@ func TestGitAskpass(t *testing.T) {
 		})
 		pty.ExpectMatch("username")
 	})
+	t.Run("DeviceFlow", func(t *testing.T) {
+		t.Parallel()
+		var exchanges atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/v2/workspaceagents/me/gitauth":
+				httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthResponse{
+					URL:        "https://something.org",
+					DeviceFlow: true,
+				})
+			case r.Method == http.MethodGet:
+				httpapi.Write(context.Background(), w, http.StatusOK, codersdk.GitAuthDevice{
+					DeviceCode:      "device",
+					UserCode:        "ABCD-1234",
+					VerificationURI: "https://something.org/device",
+					ExpiresIn:       60,
+				})
+			case exchanges.Add(1) == 1:
+				httpapi.Write(context.Background(), w, http.StatusAccepted, codersdk.Response{
+					Message: "Waiting for the device to be authorized.",
+				})
+			default:
+				httpapi.Write(context.Background(), w, http.StatusOK, agentsdk.GitAuthResponse{
+					Username: "username",
+					Password: "password",
+				})
+			}
+		}))
+		t.Cleanup(srv.Close)
+
+		inv, _ := clitest.New(t, "--agent-url", srv.URL, "Username for 'https://github.com':")
+		inv.Environ.Set("GIT_PREFIX", "/")
+		stdout := ptytest.New(t)
+		inv.Stdout = stdout.Output()
+		stderr := ptytest.New(t)
+		inv.Stderr = stderr.Output()
+		clitest.Start(t, inv)
+		stderr.ExpectMatch("ABCD-1234")
+		stderr.ExpectMatch("https://something.org/device")
+		stdout.ExpectMatch("username")
+		require.EqualValues(t, 2, exchanges.Load())
+	})
 }
@@ -45,6 +45,8 @@ func (r *RootCmd) login() *clibase.Cmd {
 		username string
 		password string
 		trial    bool
+
+		withPassword bool
 	)
 	cmd := &clibase.Cmd{
 		Use:        "login <url>",
@@ -219,6 +221,12 @@ func (r *RootCmd) login() *clibase.Cmd {
 			}
 
 			sessionToken, _ := inv.ParsedFlags().GetString(varToken)
+			if sessionToken == "" && withPassword {
+				sessionToken, err = loginWithPassword(inv, client)
+				if err != nil {
+					return err
+				}
+			}
 			if sessionToken == "" {
 				authURL := *serverURL
 				// Don't use filepath.Join, we don't want to use the os separator
@@ -293,10 +301,73 @@ func (r *RootCmd) login() *clibase.Cmd {
 			Description: "Specifies whether a trial license should be provisioned for the Coder deployment or not.",
 			Value:       clibase.BoolOf(&trial),
 		},
+		{
+			Flag:        "with-password",
+			Env:         "CODER_LOGIN_WITH_PASSWORD",
+			Description: "Log in with an email and password instead of a token from the browser. Users with two-factor authentication are prompted for a code.",
+			Value:       clibase.BoolOf(&withPassword),
+		},
 	}
 	return cmd
 }
 
+// loginWithPassword prompts for the email, password and, if the user set up
+// an authenticator app, the second factor of a user. It returns their session
+// token.
+func loginWithPassword(inv *clibase.Invocation, client *codersdk.Client) (string, error) {
+	email, err := cliui.Prompt(inv, cliui.PromptOptions{
+		Text:     "What's your " + cliui.Styles.Field.Render("email") + "?",
+		Validate: cliui.ValidateNotEmpty,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("email prompt: %w", err)
+	}
+	password, err := cliui.Prompt(inv, cliui.PromptOptions{
+		Text:     "Enter your " + cliui.Styles.Field.Render("password") + ":",
+		Secret:   true,
+		Validate: cliui.ValidateNotEmpty,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("password prompt: %w", err)
+	}
+
+	req := codersdk.LoginWithPasswordRequest{
+		Email:    email,
+		Password: password,
+	}
+	resp, err := client.LoginWithPassword(inv.Context(), req)
+	if codersdk.IsTwoFactorRequired(err) {
+		var apiErr *codersdk.Error
+		_ = errors.As(err, &apiErr)
+		totp := false
+		for _, validation := range apiErr.Validations {
+			totp = totp || validation.Field == "totp_code"
+		}
+		if !totp {
+			return "", xerrors.New("security keys can only be used to log in from the browser")
+		}
+
+		code, promptErr := cliui.Prompt(inv, cliui.PromptOptions{
+			Text:     "Enter a " + cliui.Styles.Field.Render("code") + " from your authenticator app or a recovery code:",
+			Validate: cliui.ValidateNotEmpty,
+		})
+		if promptErr != nil {
+			return "", xerrors.Errorf("two-factor code prompt: %w", promptErr)
+		}
+		code = strings.TrimSpace(code)
+		if len(code) == 6 && strings.Trim(code, "0123456789") == "" {
+			req.TOTPCode = code
+		} else {
+			req.RecoveryCode = code
+		}
+		resp, err = client.LoginWithPassword(inv.Context(), req)
+	}
+	if err != nil {
+		return "", xerrors.Errorf("login with password: %w", err)
+	}
+	return resp.SessionToken, nil
+}
+
 // isWSL determines if coder-cli is running within Windows Subsystem for Linux
 func isWSL() (bool, error) {
 	if runtime.GOOS == goosDarwin || runtime.GOOS == goosWindows {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,7 +12,10 @@ import (
 	"github.com/coder/coder/cli/clitest"
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/twofactor"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestLogin(t *testing.T) {
@@ -173,6 +177,42 @@ func TestLogin(t *testing.T) {
 		<-doneChan
 	})
 
+	t.Run("ExistingUserTwoFactorTTY", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancelFunc := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancelFunc()
+		key, err := client.CreateTOTPKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+		code, err := twofactor.TOTPCode(key.Secret, time.Now())
+		require.NoError(t, err)
+		_, err = client.ConfirmTOTP(ctx, codersdk.Me, codersdk.ConfirmTOTPRequest{Code: code})
+		require.NoError(t, err)
+
+		doneChan := make(chan struct{})
+		root, _ := clitest.New(t, "login", "--force-tty", client.URL.String(), "--with-password")
+		pty := ptytest.New(t).Attach(root)
+		go func() {
+			defer close(doneChan)
+			err := root.Run()
+			assert.NoError(t, err)
+		}()
+
+		pty.ExpectMatch("email")
+		pty.WriteLine(coderdtest.FirstUserParams.Email)
+		pty.ExpectMatch("password")
+		pty.WriteLine(coderdtest.FirstUserParams.Password)
+		pty.ExpectMatch("authenticator app")
+		// The code used to confirm can't be replayed.
+		code, err = twofactor.TOTPCode(key.Secret, time.Now().Add(twofactor.TOTPPeriod))
+		require.NoError(t, err)
+		pty.WriteLine(code)
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+	})
+
 	t.Run("ExistingUserInvalidTokenTTY", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
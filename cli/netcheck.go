@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/netcheck"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/logger"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/tailnet"
+)
+
+func (r *RootCmd) netcheck() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		&netcheckFormat{},
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "netcheck",
+		Short: "Print network connectivity information for the DERP regions of the deployment",
+		Long: "Measures the latency to every DERP region from this machine, and checks\n" +
+			"whether STUN is reachable and direct UDP connections to workspaces are\n" +
+			"possible. Connections relay through the preferred region when they\n" +
+			"can't be direct.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			derpMap, err := client.DERPMap(ctx)
+			if err != nil {
+				return xerrors.Errorf("get derp map: %w", err)
+			}
+
+			var logf logger.Logf = logger.Discard
+			if r.verbose {
+				logf = tailnet.Logger(slog.Make(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug))
+			}
+			report, err := runNetcheck(ctx, derpMap, logf, r.verbose)
+			if err != nil {
+				return err
+			}
+
+			out, err := formatter.Format(ctx, report)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// runNetcheck measures the connectivity of this machine to every region of the
+// DERP map.
+func runNetcheck(ctx context.Context, derpMap *tailcfg.DERPMap, logf logger.Logf, verbose bool) (netcheckReport, error) {
+	checker := &netcheck.Client{
+		Logf:    logf,
+		Verbose: verbose,
+	}
+	tsReport, err := checker.GetReport(ctx, derpMap)
+	if err != nil {
+		return netcheckReport{}, xerrors.Errorf("get report: %w", err)
+	}
+
+	report := netcheckReport{
+		UDP:               tsReport.UDP,
+		IPv4:              tsReport.IPv4,
+		IPv6:              tsReport.IPv6,
+		GlobalV4:          tsReport.GlobalV4,
+		GlobalV6:          tsReport.GlobalV6,
+		PreferredRegionID: tsReport.PreferredDERP,
+		Regions:           []netcheckRegion{},
+	}
+	if varies, ok := tsReport.MappingVariesByDestIP.Get(); ok {
+		report.MappingVariesByDestIP = &varies
+	}
+	report.DirectConnections = tsReport.UDP && !tsReport.MappingVariesByDestIP.EqualBool(true)
+	for _, region := range derpMap.Regions {
+		latency, ok := tsReport.RegionLatency[region.RegionID]
+		_, stun4 := tsReport.RegionV4Latency[region.RegionID]
+		_, stun6 := tsReport.RegionV6Latency[region.RegionID]
+		row := netcheckRegion{
+			RegionID:      region.RegionID,
+			RegionCode:    region.RegionCode,
+			RegionName:    region.RegionName,
+			EmbeddedRelay: region.EmbeddedRelay,
+			Reachable:     ok,
+			STUN:          stun4 || stun6,
+			Preferred:     region.RegionID == tsReport.PreferredDERP,
+		}
+		if ok {
+			row.LatencyMilliseconds = float64(latency.Microseconds()) / 1000
+		}
+		report.Regions = append(report.Regions, row)
+	}
+	sort.Slice(report.Regions, func(i, j int) bool {
+		return report.Regions[i].RegionID < report.Regions[j].RegionID
+	})
+	return report, nil
+}
+
+type netcheckReport struct {
+	UDP      bool   `json:"udp"`
+	IPv4     bool   `json:"ipv4"`
+	IPv6     bool   `json:"ipv6"`
+	GlobalV4 string `json:"global_v4,omitempty"`
+	GlobalV6 string `json:"global_v6,omitempty"`
+	// MappingVariesByDestIP is nil if it couldn't be determined.
+	MappingVariesByDestIP *bool `json:"mapping_varies_by_dest_ip"`
+	// DirectConnections is true if workspaces can be reached without a
+	// DERP relay.
+	DirectConnections bool             `json:"direct_connections"`
+	PreferredRegionID int              `json:"preferred_region_id"`
+	Regions           []netcheckRegion `json:"regions"`
+}
+
+type netcheckRegion struct {
+	RegionID            int     `json:"region_id"`
+	RegionCode          string  `json:"region_code"`
+	RegionName          string  `json:"region_name"`
+	EmbeddedRelay       bool    `json:"embedded_relay"`
+	Reachable           bool    `json:"reachable"`
+	LatencyMilliseconds float64 `json:"latency_ms"`
+	STUN                bool    `json:"stun"`
+	Preferred           bool    `json:"preferred"`
+}
+
+type netcheckFormat struct{}
+
+var _ cliui.OutputFormat = &netcheckFormat{}
+
+// ID implements OutputFormat.
+func (*netcheckFormat) ID() string {
+	return "table"
+}
+
+// AttachOptions implements OutputFormat.
+func (*netcheckFormat) AttachOptions(_ *clibase.OptionSet) {}
+
+// Format implements OutputFormat.
+func (*netcheckFormat) Format(_ context.Context, out interface{}) (string, error) {
+	report, ok := out.(netcheckReport)
+	if !ok {
+		return "", xerrors.Errorf("expected type %T, got %T", report, out)
+	}
+
+	yesNo := func(v bool) string {
+		if v {
+			return "yes"
+		}
+		return "no"
+	}
+	summary := cliui.Table()
+	summary.AppendRow(table.Row{"UDP:", yesNo(report.UDP)})
+	ipv4 := yesNo(report.IPv4)
+	if report.GlobalV4 != "" {
+		ipv4 += ", " + report.GlobalV4
+	}
+	summary.AppendRow(table.Row{"IPv4:", ipv4})
+	ipv6 := yesNo(report.IPv6)
+	if report.GlobalV6 != "" {
+		ipv6 += ", " + report.GlobalV6
+	}
+	summary.AppendRow(table.Row{"IPv6:", ipv6})
+	varies := "unknown"
+	if report.MappingVariesByDestIP != nil {
+		varies = yesNo(*report.MappingVariesByDestIP)
+	}
+	summary.AppendRow(table.Row{"Mapping varies by destination IP:", varies})
+	direct := "yes"
+	switch {
+	case !report.UDP:
+		direct = "no, UDP is blocked so connections are relayed"
+	case !report.DirectConnections:
+		direct = "only with peers behind an easy NAT"
+	}
+	summary.AppendRow(table.Row{"Direct connections:", direct})
+
+	regions := cliui.Table()
+	regions.AppendHeader(table.Row{"ID", "Code", "Name", "Latency", "STUN", "Preferred"})
+	for _, region := range report.Regions {
+		latency := "unreachable"
+		if region.Reachable {
+			latency = (time.Duration(region.LatencyMilliseconds*1000) * time.Microsecond).String()
+		}
+		name := region.RegionName
+		if region.EmbeddedRelay {
+			name += " (embedded)"
+		}
+		preferred := ""
+		if region.Preferred {
+			preferred = "*"
+		}
+		regions.AppendRow(table.Row{region.RegionID, region.RegionCode, name, latency, yesNo(region.STUN), preferred})
+	}
+	return summary.Render() + "\n\n" + regions.Render(), nil
+}
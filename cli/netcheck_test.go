@@ -0,0 +1,44 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+)
+
+func TestNetcheck(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	inv, root := clitest.New(t, "netcheck", "--output=json")
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.Run()
+	require.NoError(t, err)
+
+	var report struct {
+		UDP               bool `json:"udp"`
+		PreferredRegionID int  `json:"preferred_region_id"`
+		Regions           []struct {
+			RegionID      int  `json:"region_id"`
+			EmbeddedRelay bool `json:"embedded_relay"`
+			Reachable     bool `json:"reachable"`
+			STUN          bool `json:"stun"`
+		} `json:"regions"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.True(t, report.UDP)
+	require.Len(t, report.Regions, 1)
+	require.True(t, report.Regions[0].EmbeddedRelay)
+	require.True(t, report.Regions[0].Reachable)
+	require.True(t, report.Regions[0].STUN)
+	require.Equal(t, report.Regions[0].RegionID, report.PreferredRegionID)
+}
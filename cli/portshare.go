@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/util/ptr"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) portShare() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "port",
+		Short:       "Share workspace ports with other users",
+		Long: "Shared ports are reachable through subdomain URLs like\n" +
+			"\"8080--main--backend--kyle.apps.example.com\", which requires a wildcard\n" +
+			"access URL. Ports are only accessible by the workspace owner until they\n" +
+			"are shared.\n" + formatExamples(
+			example{
+				Description: "Share port 8080 with all signed-in users for two hours",
+				Command:     "coder port share my-workspace 8080 --level authenticated --expires 2h",
+			},
+			example{
+				Description: "Share a port of a specific agent with everyone",
+				Command:     "coder port share my-workspace.main 3000 --level public",
+			},
+			example{
+				Description: "Revoke the share of a port",
+				Command:     "coder port unshare my-workspace 8080",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.portShareCreate(),
+			r.portShareList(),
+			r.portShareRemove(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) portShareCreate() *clibase.Cmd {
+	var (
+		level   string
+		expires time.Duration
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "share <workspace>[.<agent>] <port>",
+		Short: "Share a workspace port",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			port, err := parsePortShareArg(inv.Args[1])
+			if err != nil {
+				return err
+			}
+			workspaceName, agentName, _ := strings.Cut(inv.Args[0], ".")
+			workspace, err := namedWorkspace(ctx, client, workspaceName)
+			if err != nil {
+				return err
+			}
+			if agentName == "" {
+				agentName, err = portShareDefaultAgent(workspace)
+				if err != nil {
+					return err
+				}
+			}
+
+			req := codersdk.UpsertWorkspacePortShareRequest{
+				AgentName:  agentName,
+				Port:       port,
+				ShareLevel: codersdk.WorkspaceAppSharingLevel(level),
+			}
+			if expires > 0 {
+				req.ExpiresAt = ptr.Ref(time.Now().Add(expires))
+			}
+			share, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, req)
+			if err != nil {
+				return xerrors.Errorf("share port: %w", err)
+			}
+
+			expiry := "until it is unshared"
+			if share.ExpiresAt != nil {
+				expiry = "until " + share.ExpiresAt.Local().Format(time.Stamp)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Port %d of %s is accessible by %s %s.\n",
+				share.Port, cliui.Styles.Keyword.Render(workspace.Name+"."+share.AgentName), portShareAudience(share.ShareLevel), expiry)
+			appHost, err := client.AppHost(ctx)
+			if err != nil {
+				return xerrors.Errorf("get app host: %w", err)
+			}
+			url := portShareURL(client, appHost.Host, workspace, share)
+			if url == "" {
+				cliui.Warn(inv.Stderr, "The deployment has no wildcard access URL, so shared ports can't be accessed.")
+				return nil
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, cliui.Styles.Code.Render(url))
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "level",
+			Description: "Who can access the port.",
+			Default:     string(codersdk.WorkspaceAppSharingLevelAuthenticated),
+			Value: clibase.EnumOf(&level,
+				string(codersdk.WorkspaceAppSharingLevelOwner),
+				string(codersdk.WorkspaceAppSharingLevelAuthenticated),
+				string(codersdk.WorkspaceAppSharingLevelPublic),
+			),
+		},
+		{
+			Flag:        "expires",
+			Description: "Revoke the share after this duration. The share never expires if unset.",
+			Value:       clibase.DurationOf(&expires),
+		},
+	}
+	return cmd
+}
+
+type portShareListRow struct {
+	// For JSON format:
+	codersdk.WorkspacePortShare `table:"-"`
+
+	// For table format:
+	Agent     string `json:"-" table:"agent,default_sort"`
+	Port      int32  `json:"-" table:"port"`
+	Level     string `json:"-" table:"level"`
+	ExpiresAt string `json:"-" table:"expires at"`
+	URL       string `json:"url" table:"url"`
+}
+
+func (r *RootCmd) portShareList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]portShareListRow{}, []string{"agent", "port", "level", "expires at", "url"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the shared ports of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list port shares: %w", err)
+			}
+			if len(shares) == 0 {
+				cliui.Infof(inv.Stdout, "No ports are shared.\n")
+				return nil
+			}
+
+			appHost, err := client.AppHost(ctx)
+			if err != nil {
+				return xerrors.Errorf("get app host: %w", err)
+			}
+			rows := make([]portShareListRow, 0, len(shares))
+			for _, share := range shares {
+				expiresAt := "never"
+				if share.ExpiresAt != nil {
+					expiresAt = share.ExpiresAt.Local().Format(time.Stamp)
+					if share.ExpiresAt.Before(time.Now()) {
+						expiresAt += " (expired)"
+					}
+				}
+				rows = append(rows, portShareListRow{
+					WorkspacePortShare: share,
+					Agent:              share.AgentName,
+					Port:               share.Port,
+					Level:              string(share.ShareLevel),
+					ExpiresAt:          expiresAt,
+					URL:                portShareURL(client, appHost.Host, workspace, share),
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) portShareRemove() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "unshare <workspace>[.<agent>] <port>",
+		Short: "Revoke the share of a workspace port",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			port, err := parsePortShareArg(inv.Args[1])
+			if err != nil {
+				return err
+			}
+			workspaceName, agentName, _ := strings.Cut(inv.Args[0], ".")
+			workspace, err := namedWorkspace(ctx, client, workspaceName)
+			if err != nil {
+				return err
+			}
+
+			// The workspace may be stopped, so the agent is looked up in the
+			// existing shares rather than the latest build.
+			shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list port shares: %w", err)
+			}
+			var matches []codersdk.WorkspacePortShare
+			for _, share := range shares {
+				if share.Port == port && (agentName == "" || share.AgentName == agentName) {
+					matches = append(matches, share)
+				}
+			}
+			switch len(matches) {
+			case 0:
+				return xerrors.Errorf("port %d of workspace %q is not shared", port, workspace.Name)
+			case 1:
+			default:
+				return xerrors.Errorf("port %d is shared by multiple agents, specify one with %q", port, workspace.Name+".<agent>")
+			}
+
+			err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+				AgentName: matches[0].AgentName,
+				Port:      port,
+			})
+			if err != nil {
+				return xerrors.Errorf("unshare port: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Port %d of %s is only accessible by its owner.\n",
+				port, cliui.Styles.Keyword.Render(workspace.Name+"."+matches[0].AgentName))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func parsePortShareArg(arg string) (int32, error) {
+	port, err := strconv.ParseUint(arg, 10, 16)
+	if err != nil || port == 0 {
+		return 0, xerrors.Errorf("invalid port %q", arg)
+	}
+	return int32(port), nil
+}
+
+// portShareDefaultAgent returns the name of the only agent in the workspace.
+func portShareDefaultAgent(workspace codersdk.Workspace) (string, error) {
+	var names []string
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			names = append(names, agent.Name)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return "", xerrors.Errorf("workspace %q has no agents", workspace.Name)
+	case 1:
+		return names[0], nil
+	default:
+		return "", xerrors.Errorf("workspace %q has multiple agents, specify one with %q", workspace.Name, workspace.Name+".<agent>")
+	}
+}
+
+func portShareAudience(level codersdk.WorkspaceAppSharingLevel) string {
+	switch level {
+	case codersdk.WorkspaceAppSharingLevelPublic:
+		return "everyone"
+	case codersdk.WorkspaceAppSharingLevelAuthenticated:
+		return "all signed-in users"
+	default:
+		return "its owner"
+	}
+}
+
+// portShareURL returns the subdomain URL of a shared port, or an empty string
+// if the deployment has no wildcard access URL.
+func portShareURL(client *codersdk.Client, appHost string, workspace codersdk.Workspace, share codersdk.WorkspacePortShare) string {
+	if appHost == "" {
+		return ""
+	}
+	subdomain := httpapi.ApplicationURL{
+		AppSlugOrPort: strconv.Itoa(int(share.Port)),
+		AgentName:     share.AgentName,
+		WorkspaceName: workspace.Name,
+		Username:      workspace.OwnerName,
+	}
+	return fmt.Sprintf("%s://%s.%s", client.URL.Scheme, subdomain.String(), appHost)
+}
@@ -0,0 +1,63 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/testutil"
+)
+
+func TestPortShare(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, _ := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Name = "main"
+		return agents
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	inv, root := clitest.New(t, "port", "share", workspace.Name, "8080", "--level", "public", "--expires", "2h")
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "accessible by everyone")
+
+	inv, root = clitest.New(t, "port", "ls", workspace.Name, "--output=json")
+	clitest.SetupConfig(t, client, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	var shares []codersdk.WorkspacePortShare
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &shares))
+	require.Len(t, shares, 1)
+	require.Equal(t, "main", shares[0].AgentName)
+	require.EqualValues(t, 8080, shares[0].Port)
+	require.Equal(t, codersdk.WorkspaceAppSharingLevelPublic, shares[0].ShareLevel)
+	require.NotNil(t, shares[0].ExpiresAt)
+
+	inv, root = clitest.New(t, "port", "unshare", workspace.Name+".main", "8080")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	shares, err = client.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, shares)
+
+	inv, root = clitest.New(t, "port", "unshare", workspace.Name, "8080")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "is not shared")
+}
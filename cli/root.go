@@ -73,12 +73,15 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 	// Please re-sort this list alphabetically if you change it!
 	return []*clibase.Cmd{
 		r.dotfiles(),
+		r.externalAuth(),
 		r.login(),
 		r.logout(),
+		r.netcheck(),
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
 		r.state(),
+		r.support(),
 		r.templates(),
 		r.users(),
 		r.tokens(),
@@ -100,6 +103,9 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.update(),
 		r.restart(),
 		r.parameters(),
+		r.portShare(),
+		r.apps(),
+		r.vpn(),
 
 		// Hidden
 		r.workspaceAgent(),
@@ -31,7 +31,10 @@ import (
 // make update-golden-files
 var updateGoldenFiles = flag.Bool("update", false, "update .golden files")
 
-var timestampRegex = regexp.MustCompile(`(?i)\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(.\d+)?Z`)
+var (
+	timestampRegex  = regexp.MustCompile(`(?i)\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(.\d+)?Z`)
+	durationMSRegex = regexp.MustCompile(`"duration_ms": \d+`)
+)
 
 func TestCommandHelp(t *testing.T) {
 	t.Parallel()
@@ -109,6 +112,8 @@ ExtractCommandPathsLoop:
 
 			// Replace any timestamps with a placeholder.
 			actual = timestampRegex.ReplaceAll(actual, []byte("[timestamp]"))
+			// Build stage durations depend on how quickly the test ran.
+			actual = durationMSRegex.ReplaceAll(actual, []byte(`"duration_ms": "[duration]"`))
 
 			homeDir, err := os.UserHomeDir()
 			require.NoError(t, err)
@@ -71,7 +71,12 @@ import (
 	"github.com/coder/coder/coderd/gitsshkey"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/importpolicy"
+	"github.com/coder/coder/coderd/jobmatch"
+	"github.com/coder/coder/coderd/ldap"
 	"github.com/coder/coder/coderd/prometheusmetrics"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/saml"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/coderd/updatecheck"
@@ -149,6 +154,14 @@ func ReadGitAuthProvidersFromEnv(environ []string) ([]codersdk.GitAuthConfig, er
 			provider.NoRefresh = b
 		case "SCOPES":
 			provider.Scopes = strings.Split(v.Value, " ")
+		case "DEVICE_FLOW":
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse bool: %s", v.Value)
+			}
+			provider.DeviceFlow = b
+		case "DEVICE_CODE_URL":
+			provider.DeviceCodeURL = v.Value
 		}
 		providers[providerNum] = provider
 	}
@@ -615,6 +628,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				Logger:                      logger.Named("coderd"),
 				Database:                    dbfake.New(),
 				DERPMap:                     derpMap,
+				DERPHealthCheckInterval:     cfg.DERP.Config.HealthCheckInterval.Value(),
 				Pubsub:                      database.NewPubsubInMemory(),
 				CacheDir:                    cacheDir,
 				GoogleTokenValidator:        googleTokenValidator,
@@ -711,6 +725,16 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				if slice.Contains(cfg.OIDC.Scopes, "groups") && cfg.OIDC.GroupField == "" {
 					cfg.OIDC.GroupField = "groups"
 				}
+				for value, roles := range cfg.OIDC.UserRoleMapping.Value {
+					for _, role := range roles {
+						if _, ok := rbac.IsOrgRole(role); ok {
+							return xerrors.Errorf("oidc user role mapping of %q: %q is not a site role", value, role)
+						}
+						if _, err := rbac.RoleByName(role); err != nil {
+							return xerrors.Errorf("oidc user role mapping of %q: %q is not a supported role", value, role)
+						}
+					}
+				}
 				options.OIDCConfig = &coderd.OIDCConfig{
 					OAuth2Config: &oauth2.Config{
 						ClientID:     cfg.OIDC.ClientID.String(),
@@ -730,12 +754,29 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 					AuthURLParams:       cfg.OIDC.AuthURLParams.Value,
 					GroupField:          cfg.OIDC.GroupField.String(),
 					GroupMapping:        cfg.OIDC.GroupMapping.Value,
+					UserRoleField:       cfg.OIDC.UserRoleField.String(),
+					UserRoleMapping:     cfg.OIDC.UserRoleMapping.Value,
+					RefreshInterval:     cfg.OIDC.RefreshInterval.Value(),
 					SignInText:          cfg.OIDC.SignInText.String(),
 					IconURL:             cfg.OIDC.IconURL.String(),
 					IgnoreEmailVerified: cfg.OIDC.IgnoreEmailVerified.Value(),
 				}
 			}
 
+			if cfg.SAML.IDPMetadataURL.String() != "" {
+				options.SAMLConfig, err = configureSAML(ctx, cfg.AccessURL.Value(), cfg.SAML)
+				if err != nil {
+					return xerrors.Errorf("configure saml: %w", err)
+				}
+			}
+
+			if cfg.LDAP.URL.String() != "" {
+				options.LDAPConfig, err = configureLDAP(cfg.LDAP)
+				if err != nil {
+					return xerrors.Errorf("configure ldap: %w", err)
+				}
+			}
+
 			if cfg.InMemoryDatabase {
 				options.Database = dbfake.New()
 				options.Pubsub = database.NewPubsubInMemory()
@@ -868,6 +909,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 				defer closeWorkspacesFunc()
 
+				closeProvisionerJobQueueFunc, err := prometheusmetrics.ProvisionerJobQueue(ctx, options.PrometheusRegistry, options.Database, 0)
+				if err != nil {
+					return xerrors.Errorf("register provisioner job queue prometheus metric: %w", err)
+				}
+				defer closeProvisionerJobQueueFunc()
+
 				//nolint:revive
 				defer serveHandler(ctx, logger, promhttp.InstrumentMetricHandler(
 					options.PrometheusRegistry, promhttp.HandlerFor(options.PrometheusRegistry, promhttp.HandlerOpts{}),
@@ -878,6 +925,13 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.SwaggerEndpoint = cfg.Swagger.Enable.Value()
 			}
 
+			if len(cfg.Provisioner.ImportPolicyFiles) > 0 {
+				options.TemplateImportPolicy, err = importpolicy.Load(ctx, cfg.Provisioner.ImportPolicyFiles.Value())
+				if err != nil {
+					return xerrors.Errorf("load template import policy: %w", err)
+				}
+			}
+
 			// We use a separate coderAPICloser so the Enterprise API
 			// can have it's own close functions. This is cleaner
 			// than abstracting the Coder API itself.
@@ -940,9 +994,14 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger, options.Database)
+			purger := dbpurge.New(ctx, logger, options.Database, cfg.WorkspaceAppAccessLogRetention.Value())
 			defer purger.Close()
 
+			// Flags pending jobs that no online provisioner daemon can
+			// acquire, and optionally fails them after a timeout.
+			jobMatcher := jobmatch.New(ctx, logger.Named("jobmatch"), options.Database, options.Pubsub, 10*time.Second, cfg.Provisioner.UnmatchedJobTimeout.Value())
+			defer jobMatcher.Close()
+
 			// Wrap the server in middleware that redirects to the access URL if
 			// the request is not to a local IP.
 			var handler http.Handler = coderAPI.RootHandler
@@ -1589,6 +1648,109 @@ func configureGithubOAuth2(accessURL *url.URL, clientID, clientSecret string, al
 	}, nil
 }
 
+// configureSAML fetches the metadata of the SAML identity provider and creates
+// a service provider that trusts it.
+func configureSAML(ctx context.Context, accessURL *url.URL, cfg codersdk.SAMLConfig) (*coderd.SAMLConfig, error) {
+	metadataURL, err := accessURL.Parse("/api/v2/users/saml/metadata")
+	if err != nil {
+		return nil, xerrors.Errorf("parse saml metadata url: %w", err)
+	}
+	acsURL, err := accessURL.Parse("/api/v2/users/saml/acs")
+	if err != nil {
+		return nil, xerrors.Errorf("parse saml acs url: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.IDPMetadataURL.String(), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create idp metadata request: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("fetch idp metadata: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("fetch idp metadata: unexpected status code %d", res.StatusCode)
+	}
+	// Metadata documents are small, so anything larger is likely a mistake.
+	data, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, xerrors.Errorf("read idp metadata: %w", err)
+	}
+	idp, err := saml.ParseMetadata(data)
+	if err != nil {
+		return nil, xerrors.Errorf("parse idp metadata: %w", err)
+	}
+
+	entityID := cfg.EntityID.String()
+	if entityID == "" {
+		entityID = metadataURL.String()
+	}
+	return &coderd.SAMLConfig{
+		ServiceProvider: &saml.ServiceProvider{
+			EntityID:         entityID,
+			ACSURL:           acsURL.String(),
+			IdentityProvider: idp,
+		},
+		EmailDomain:   cfg.EmailDomain,
+		AllowSignups:  cfg.AllowSignups.Value(),
+		UsernameField: cfg.UsernameField.String(),
+		EmailField:    cfg.EmailField.String(),
+		GroupField:    cfg.GroupField.String(),
+		GroupMapping:  cfg.GroupMapping.Value,
+		SignInText:    cfg.SignInText.String(),
+		IconURL:       cfg.IconURL.String(),
+	}, nil
+}
+
+// configureLDAP creates the config to authenticate users against an LDAP
+// directory with.
+func configureLDAP(cfg codersdk.LDAPConfig) (*coderd.LDAPConfig, error) {
+	switch cfg.URL.Scheme {
+	case "ldap", "ldaps":
+	default:
+		return nil, xerrors.Errorf("ldap url must use the ldap:// or ldaps:// scheme, got %q", cfg.URL.String())
+	}
+	if !strings.Contains(cfg.UserFilter.String(), ldap.EmailPlaceholder) {
+		return nil, xerrors.Errorf("ldap user filter %q must contain %s", cfg.UserFilter.String(), ldap.EmailPlaceholder)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// StartTLS upgrades an established connection, so the server name
+		// isn't inferred from the address.
+		ServerName: cfg.URL.Value().Hostname(),
+	}
+	if cfg.CAFile.String() != "" {
+		data, err := os.ReadFile(cfg.CAFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", cfg.CAFile.String(), err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(data) {
+			return nil, xerrors.Errorf("failed to parse CA certificate in ldap-ca-file")
+		}
+	}
+
+	return &coderd.LDAPConfig{
+		Directory: &ldap.Config{
+			URL:               cfg.URL.String(),
+			BindDN:            cfg.BindDN.String(),
+			BindPassword:      cfg.BindPassword.String(),
+			StartTLS:          cfg.StartTLS.Value(),
+			TLSConfig:         tlsConfig,
+			UserBaseDN:        cfg.UserBaseDN.String(),
+			UserFilter:        cfg.UserFilter.String(),
+			UsernameAttribute: cfg.UsernameField.String(),
+			EmailAttribute:    cfg.EmailField.String(),
+			GroupAttribute:    cfg.GroupField.String(),
+		},
+		AllowSignups: cfg.AllowSignups.Value(),
+		GroupMapping: cfg.GroupMapping.Value,
+		SyncInterval: cfg.SyncInterval.Value(),
+	}, nil
+}
+
 // embeddedPostgresURL returns the URL for the embedded PostgreSQL deployment.
 func embeddedPostgresURL(cfg config.Root) (string, error) {
 	pgPassword, err := cfg.PostgresPassword().Read()
@@ -80,6 +80,8 @@ func TestReadGitAuthProvidersFromEnv(t *testing.T) {
 			"CODER_GITAUTH_1_TOKEN_URL=google.com",
 			"CODER_GITAUTH_1_VALIDATE_URL=bing.com",
 			"CODER_GITAUTH_1_SCOPES=repo:read repo:write",
+			"CODER_GITAUTH_1_DEVICE_FLOW=true",
+			"CODER_GITAUTH_1_DEVICE_CODE_URL=duckduckgo.com",
 		})
 		require.NoError(t, err)
 		require.Len(t, providers, 2)
@@ -95,6 +97,8 @@ func TestReadGitAuthProvidersFromEnv(t *testing.T) {
 		assert.Equal(t, "google.com", providers[1].TokenURL)
 		assert.Equal(t, "bing.com", providers[1].ValidateURL)
 		assert.Equal(t, []string{"repo:read", "repo:write"}, providers[1].Scopes)
+		assert.True(t, providers[1].DeviceFlow)
+		assert.Equal(t, "duckduckgo.com", providers[1].DeviceCodeURL)
 	})
 }
 
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"tailscale.com/types/logger"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/buildinfo"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) support() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "support",
+		Short: "Commands for troubleshooting issues with a deployment",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.supportBundle(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) supportBundle() *clibase.Cmd {
+	var outputFile string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "bundle <workspace>",
+		Short:       "Collect diagnostics about the connection to a workspace into an archive",
+		Long: "The archive has the netcheck results and DERP map of this machine, the\n" +
+			"tailnet nodes of both ends of the connection, the stats, startup logs and\n" +
+			"recent logs of the agent, the deployment config with secrets removed and\n" +
+			"the client and server versions. The agent logs may contain the output of\n" +
+			"your scripts, so review the archive before sharing it.\n" + formatExamples(
+			example{
+				Description: "Collect diagnostics about the connection to my-workspace",
+				Command:     "coder support bundle my-workspace",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			if outputFile == "" {
+				outputFile = fmt.Sprintf("coder-support-%d.zip", time.Now().Unix())
+			}
+			f, err := os.OpenFile(outputFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+			if err != nil {
+				return xerrors.Errorf("create archive: %w", err)
+			}
+			defer f.Close()
+
+			b := &supportBundle{zip: zip.NewWriter(f), errors: map[string]string{}}
+			var dialLogger slog.Logger
+			if r.verbose {
+				dialLogger = slog.Make(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+			}
+			collectSupportBundle(ctx, inv, client, dialLogger, inv.Args[0], b)
+			err = b.close()
+			if err != nil {
+				return xerrors.Errorf("write archive: %w", err)
+			}
+			err = f.Close()
+			if err != nil {
+				return xerrors.Errorf("close archive: %w", err)
+			}
+
+			if len(b.errors) > 0 {
+				cliui.Warnf(inv.Stderr, "Some diagnostics couldn't be collected, see errors.txt in the archive.")
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Wrote support bundle to %s\n", cliui.Styles.Code.Render(outputFile))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "output-file",
+			FlagShorthand: "O",
+			Description:   "File to write the archive to. Defaults to coder-support-<timestamp>.zip in the working directory.",
+			Value:         clibase.StringOf(&outputFile),
+		},
+	}
+	return cmd
+}
+
+// supportBundle writes diagnostics to a zip archive. Diagnostics that can't be
+// collected are recorded in errors.txt instead of failing the bundle.
+type supportBundle struct {
+	zip    *zip.Writer
+	errors map[string]string
+}
+
+func (b *supportBundle) fail(name string, err error) {
+	b.errors[name] = err.Error()
+}
+
+func (b *supportBundle) writeFile(name string, data []byte) {
+	w, err := b.zip.Create(name)
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	if err != nil {
+		b.fail(name, err)
+	}
+}
+
+func (b *supportBundle) writeJSON(name string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		b.fail(name, err)
+		return
+	}
+	b.writeFile(name, data)
+}
+
+func (b *supportBundle) close() error {
+	if len(b.errors) > 0 {
+		names := make([]string, 0, len(b.errors))
+		for name := range b.errors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var out strings.Builder
+		for _, name := range names {
+			_, _ = fmt.Fprintf(&out, "%s: %s\n", name, b.errors[name])
+		}
+		b.writeFile("errors.txt", []byte(out.String()))
+	}
+	return b.zip.Close()
+}
+
+func collectSupportBundle(ctx context.Context, inv *clibase.Invocation, client *codersdk.Client, dialLogger slog.Logger, workspaceArg string, b *supportBundle) {
+	b.writeJSON("client.json", map[string]string{
+		"version":          buildinfo.Version(),
+		"operating_system": runtime.GOOS,
+		"architecture":     runtime.GOARCH,
+		"url":              client.URL.String(),
+		"collected_at":     time.Now().Format(time.RFC3339),
+	})
+	if buildInfo, err := client.BuildInfo(ctx); err != nil {
+		b.fail("deployment/buildinfo.json", err)
+	} else {
+		b.writeJSON("deployment/buildinfo.json", buildInfo)
+	}
+	// The server removes secrets from the config, and only owners can read it.
+	if config, err := client.DeploymentConfig(ctx); err != nil {
+		b.fail("deployment/config.json", err)
+	} else {
+		b.writeJSON("deployment/config.json", config.Values)
+	}
+
+	derpMap, err := client.DERPMap(ctx)
+	if err != nil {
+		b.fail("network/derp_map.json", err)
+	} else {
+		b.writeJSON("network/derp_map.json", derpMap)
+		report, err := runNetcheck(ctx, derpMap, logger.Discard, false)
+		if err != nil {
+			b.fail("network/netcheck.json", err)
+		} else {
+			b.writeJSON("network/netcheck.json", report)
+		}
+	}
+
+	workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, codersdk.Me, workspaceArg)
+	if err != nil {
+		b.fail("workspace.json", err)
+		return
+	}
+	b.writeJSON("workspace.json", workspace)
+	b.writeJSON("agent/agent.json", workspaceAgent)
+
+	startupLogs, err := client.WorkspaceAgentStartupLogs(ctx, workspaceAgent.ID)
+	if err != nil {
+		b.fail("agent/startup_logs.txt", err)
+	} else {
+		var out strings.Builder
+		for _, startupLog := range startupLogs {
+			_, _ = fmt.Fprintf(&out, "%s %s\n", startupLog.CreatedAt.Format(time.RFC3339), startupLog.Output)
+		}
+		b.writeFile("agent/startup_logs.txt", []byte(out.String()))
+	}
+
+	conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+		Logger: dialLogger,
+	})
+	if err != nil {
+		b.fail("agent/debug.json", xerrors.Errorf("dial agent: %w", err))
+		return
+	}
+	defer conn.Close()
+	b.writeJSON("network/client_node.json", conn.Node())
+
+	reachableCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if !conn.AwaitReachable(reachableCtx) {
+		b.fail("agent/debug.json", xerrors.New("agent is not reachable over tailnet"))
+		return
+	}
+	latency, p2p, pong, err := conn.Ping(ctx)
+	if err != nil {
+		b.fail("network/ping.json", err)
+	} else {
+		b.writeJSON("network/ping.json", map[string]any{
+			"latency_ms": float64(latency.Microseconds()) / 1000,
+			"p2p":        p2p,
+			"pong":       pong,
+		})
+	}
+	b.writeJSON("network/status.json", conn.Status())
+
+	info, err := conn.DebugInfo(ctx)
+	if err != nil {
+		b.fail("agent/debug.json", err)
+		return
+	}
+	b.writeJSON("network/agent_node.json", info.Node)
+	for name, content := range info.Logs {
+		b.writeFile("agent/logs/"+name, []byte(content))
+	}
+	info.Logs = nil
+	b.writeJSON("agent/debug.json", info)
+}
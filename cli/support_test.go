@@ -0,0 +1,87 @@
+package cli_test
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestSupportBundle(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, nil)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent"),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "bundle.zip")
+	inv, root := clitest.New(t, "support", "bundle", workspace.Name, "--output-file", path)
+	clitest.SetupConfig(t, client, root)
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	archive, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer archive.Close()
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	if f, ok := files["errors.txt"]; ok {
+		t.Logf("errors.txt:\n%s", readZipFile(t, f))
+	}
+	for _, name := range []string{
+		"client.json",
+		"deployment/buildinfo.json",
+		"deployment/config.json",
+		"network/derp_map.json",
+		"network/netcheck.json",
+		"network/client_node.json",
+		"network/agent_node.json",
+		"workspace.json",
+		"agent/agent.json",
+		"agent/startup_logs.txt",
+		"agent/debug.json",
+	} {
+		require.Contains(t, files, name)
+	}
+
+	var got codersdk.Workspace
+	err = json.Unmarshal(readZipFile(t, files["workspace.json"]), &got)
+	require.NoError(t, err)
+	require.Equal(t, workspace.ID, got.ID)
+}
+
+func readZipFile(t *testing.T, f *zip.File) []byte {
+	t.Helper()
+	r, err := f.Open()
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return data
+}
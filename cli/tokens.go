@@ -23,6 +23,10 @@ func (r *RootCmd) tokens() *clibase.Cmd {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			example{
+				Description: "Create a token for a service account you own",
+				Command:     "coder tokens create --user ci-bot --name deploy",
+			},
 			example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -49,6 +53,8 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 	var (
 		tokenLifetime time.Duration
 		name          string
+		scopes        []string
+		user          string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -59,9 +65,10 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			res, err := client.CreateToken(inv.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+			res, err := client.CreateToken(inv.Context(), user, codersdk.CreateTokenRequest{
 				Lifetime:  tokenLifetime,
 				TokenName: name,
+				Scopes:    scopes,
 			})
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
@@ -95,6 +102,13 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			Description:   "Specify a human-readable name.",
 			Value:         clibase.StringOf(&name),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Restrict the token to scope entries of the form <resource type>:<action>[:<resource id>], e.g. workspace:read or template:update:<template id>. Defaults to all permissions of the user.",
+			Value:       clibase.StringArrayOf(&scopes),
+		},
+		tokenUserOption(&user),
 	}
 
 	return cmd
@@ -112,10 +126,11 @@ type tokenListRow struct {
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
 	Owner     string    `json:"-" table:"owner"`
+	Scope     string    `json:"-" table:"scope"`
 }
 
 func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
-	return tokenListRow{
+	row := tokenListRow{
 		APIKey:    token.APIKey,
 		ID:        token.ID,
 		TokenName: token.TokenName,
@@ -123,7 +138,12 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
 		Owner:     token.Username,
+		Scope:     string(token.Scope),
+	}
+	if token.Scope == codersdk.APIKeyScopeCustom {
+		row.Scope = strings.Join(token.Scopes, " ")
 	}
+	return row
 }
 
 func (r *RootCmd) listTokens() *clibase.Cmd {
@@ -135,6 +155,7 @@ func (r *RootCmd) listTokens() *clibase.Cmd {
 
 	var (
 		all           bool
+		user          string
 		displayTokens []tokenListRow
 		formatter     = cliui.NewOutputFormatter(
 			cliui.TableFormat([]tokenListRow{}, defaultCols),
@@ -152,7 +173,7 @@ func (r *RootCmd) listTokens() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			tokens, err := client.Tokens(inv.Context(), codersdk.Me, codersdk.TokensFilter{
+			tokens, err := client.Tokens(inv.Context(), user, codersdk.TokensFilter{
 				IncludeAll: all,
 			})
 			if err != nil {
@@ -189,6 +210,7 @@ func (r *RootCmd) listTokens() *clibase.Cmd {
 			Description:   "Specifies whether all users' tokens will be listed or not (must have Owner role to see all tokens).",
 			Value:         clibase.BoolOf(&all),
 		},
+		tokenUserOption(&user),
 	}
 
 	formatter.AttachOptions(&cmd.Options)
@@ -196,6 +218,7 @@ func (r *RootCmd) listTokens() *clibase.Cmd {
 }
 
 func (r *RootCmd) removeToken() *clibase.Cmd {
+	var user string
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Use:     "remove <name>",
@@ -206,12 +229,12 @@ func (r *RootCmd) removeToken() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			token, err := client.APIKeyByName(inv.Context(), codersdk.Me, inv.Args[0])
+			token, err := client.APIKeyByName(inv.Context(), user, inv.Args[0])
 			if err != nil {
 				return xerrors.Errorf("fetch api key by name %s: %w", inv.Args[0], err)
 			}
 
-			err = client.DeleteAPIKey(inv.Context(), codersdk.Me, token.ID)
+			err = client.DeleteAPIKey(inv.Context(), user, token.ID)
 			if err != nil {
 				return xerrors.Errorf("delete api key: %w", err)
 			}
@@ -225,5 +248,20 @@ func (r *RootCmd) removeToken() *clibase.Cmd {
 		},
 	}
 
+	cmd.Options = clibase.OptionSet{
+		tokenUserOption(&user),
+	}
+
 	return cmd
 }
+
+// tokenUserOption selects whose tokens are managed. Owners of service
+// accounts manage their tokens.
+func tokenUserOption(user *string) clibase.Option {
+	return clibase.Option{
+		Flag:        "user",
+		Description: "Specifies the user whose tokens to manage, such as a service account you own.",
+		Default:     codersdk.Me,
+		Value:       clibase.StringOf(user),
+	}
+}
@@ -14,9 +14,12 @@ import (
 
 func (r *RootCmd) userCreate() *clibase.Cmd {
 	var (
-		email    string
-		username string
-		password string
+		email          string
+		username       string
+		password       string
+		serviceAccount bool
+		owner          string
+		ownerGroup     string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -53,6 +56,39 @@ func (r *RootCmd) userCreate() *clibase.Cmd {
 					return err
 				}
 			}
+			if serviceAccount {
+				req := codersdk.CreateUserRequest{
+					Email:          email,
+					Username:       username,
+					OrganizationID: organization.ID,
+					ServiceAccount: &codersdk.CreateServiceAccountRequest{},
+				}
+				if owner != "" {
+					ownerUser, err := client.User(inv.Context(), owner)
+					if err != nil {
+						return xerrors.Errorf("get owner: %w", err)
+					}
+					req.ServiceAccount.OwnerUserID = &ownerUser.ID
+				}
+				if ownerGroup != "" {
+					group, err := client.GroupByOrgAndName(inv.Context(), organization.ID, ownerGroup)
+					if err != nil {
+						return xerrors.Errorf("get owner group: %w", err)
+					}
+					req.ServiceAccount.OwnerGroupID = &group.ID
+				}
+				_, err = client.CreateUser(inv.Context(), req)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(inv.Stderr, `A new service account has been created!
+It cannot log in. Create tokens for it with `+cliui.Styles.Code.Render("coder tokens create --user "+username)+`.`)
+				return nil
+			}
+			if owner != "" || ownerGroup != "" {
+				return xerrors.New("owners can only be set for service accounts")
+			}
+
 			if password == "" {
 				password, err = cryptorand.StringCharset(cryptorand.Human, 20)
 				if err != nil {
@@ -103,6 +139,21 @@ Create a workspace  `+cliui.Styles.Code.Render("coder create")+`!`)
 			Description:   "Specifies a password for the new user.",
 			Value:         clibase.StringOf(&password),
 		},
+		{
+			Flag:        "service-account",
+			Description: "Create a service account that cannot log in and only authenticates with tokens.",
+			Value:       clibase.BoolOf(&serviceAccount),
+		},
+		{
+			Flag:        "owner",
+			Description: "Specifies the user that manages the tokens of the service account. Defaults to you.",
+			Value:       clibase.StringOf(&owner),
+		},
+		{
+			Flag:        "owner-group",
+			Description: "Specifies a group whose members manage the tokens of the service account.",
+			Value:       clibase.StringOf(&ownerGroup),
+		},
 	}
 	return cmd
 }
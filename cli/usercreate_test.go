@@ -1,13 +1,17 @@
 package cli_test
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/cli/clitest"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
 )
 
 func TestUserCreate(t *testing.T) {
@@ -37,4 +41,36 @@ func TestUserCreate(t *testing.T) {
 		}
 		<-doneChan
 	})
+	t.Run("ServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		inv, root := clitest.New(t, "users", "create", "--service-account", "--username", "ci-bot", "--email", "ci-bot@coder.com")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		user, err := client.User(ctx, "ci-bot")
+		require.NoError(t, err)
+		require.True(t, user.ServiceAccount)
+		account, err := client.ServiceAccount(ctx, user.ID.String())
+		require.NoError(t, err)
+		require.Equal(t, owner.UserID, *account.OwnerUserID)
+
+		inv, root = clitest.New(t, "tokens", "create", "--user", "ci-bot", "--name", "deploy")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.NotEmpty(t, buf.String())
+
+		key, err := client.APIKeyByName(ctx, user.ID.String(), "deploy")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, key.UserID)
+	})
 }
@@ -19,6 +19,7 @@ func (r *RootCmd) users() *clibase.Cmd {
 			r.userSingle(),
 			r.createUserStatusCommand(codersdk.UserStatusActive),
 			r.createUserStatusCommand(codersdk.UserStatusSuspended),
+			r.userResetTwoFactor(),
 		},
 	}
 	return cmd
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+// userResetTwoFactor removes the authenticator app, recovery codes and
+// security keys of a user that lost them.
+func (r *RootCmd) userResetTwoFactor() *clibase.Cmd {
+	client := new(codersdk.Client)
+
+	var columns []string
+	cmd := &clibase.Cmd{
+		Use:     "reset-two-factor <username|user_id>",
+		Short:   "Remove the two-factor authentication of a user. They can log in with only their password afterwards",
+		Aliases: []string{"reset-2fa"},
+		Long: formatExamples(
+			example{
+				Command: "coder users reset-two-factor example_user",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			identifier := inv.Args[0]
+			if identifier == "" {
+				return xerrors.Errorf("user identifier cannot be an empty string")
+			}
+
+			user, err := client.User(inv.Context(), identifier)
+			if err != nil {
+				return xerrors.Errorf("fetch user: %w", err)
+			}
+
+			// Display the user. This uses cliui.DisplayTable directly instead
+			// of cliui.NewOutputFormatter because we prompt immediately
+			// afterwards.
+			table, err := cliui.DisplayTable([]codersdk.User{user}, "", columns)
+			if err != nil {
+				return xerrors.Errorf("render user table: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, table)
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      "Are you sure you want to reset the two-factor authentication of this user?",
+				IsConfirm: true,
+				Default:   cliui.ConfirmYes,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.ResetTwoFactor(inv.Context(), user.ID.String())
+			if err != nil {
+				return xerrors.Errorf("reset two-factor authentication: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "\nTwo-factor authentication of %s has been reset!\n", cliui.Styles.Keyword.Render(user.Username))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "column",
+			FlagShorthand: "c",
+			Description:   "Specify a column to filter in the table.",
+			Default:       strings.Join([]string{"username", "email", "created_at", "status"}, ","),
+			Value:         clibase.StringArrayOf(&columns),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,44 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/twofactor"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestUserResetTwoFactor(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	admin := coderdtest.CreateFirstUser(t, client)
+	other, otherUser := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	key, err := other.CreateTOTPKey(ctx, codersdk.Me)
+	require.NoError(t, err)
+	code, err := twofactor.TOTPCode(key.Secret, time.Now())
+	require.NoError(t, err)
+	_, err = other.ConfirmTOTP(ctx, codersdk.Me, codersdk.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+
+	inv, root := clitest.New(t, "users", "reset-two-factor", otherUser.Username)
+	clitest.SetupConfig(t, client, root)
+	// Yes to the prompt
+	inv.Stdin = bytes.NewReader([]byte("yes\n"))
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	status, err := other.TwoFactor(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, status.TOTP)
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/tailnet"
+)
+
+// vpnDomain is the top-level domain of workspace hostnames, e.g.
+// "main.backend.kyle.coder".
+const vpnDomain = "coder"
+
+func (r *RootCmd) vpn() *clibase.Cmd {
+	var (
+		dnsAddress  string
+		tunName     string
+		searchQuery string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "vpn",
+		Short:       "Connect to every running workspace and resolve their hostnames",
+		Long: "Each workspace agent is reachable at \"<agent>.<workspace>.<owner>." + vpnDomain + "\"\n" +
+			"through the DNS server started by this command. Without --tun, the ports\n" +
+			"agents listen on are forwarded from loopback addresses, which doesn't\n" +
+			"require root but is only supported on Linux and Windows.\n" + formatExamples(
+			example{
+				Description: "Resolve workspace hostnames on a custom port",
+				Command:     "coder vpn --dns-address 127.0.0.1:5300",
+			},
+			example{
+				Description: "Route the tailnet through a TUN device (requires root)",
+				Command:     "sudo coder vpn --tun coder0",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, stop := signal.NotifyContext(inv.Context(), InterruptSignals...)
+			defer stop()
+
+			logger, ok := LoggerFromContext(ctx)
+			if !ok {
+				logger = slog.Make(sloghuman.Sink(inv.Stderr))
+			}
+			if r.verbose {
+				logger = logger.Leveled(slog.LevelDebug)
+			}
+
+			if tunName == "" && !agent.LoopbackForwardingSupported() {
+				return xerrors.Errorf("forwarding workspace ports is not supported on %s, use --tun instead", runtime.GOOS)
+			}
+
+			dnsConn, err := net.ListenPacket("udp", dnsAddress)
+			if err != nil {
+				return xerrors.Errorf("listen for dns on %q: %w", dnsAddress, err)
+			}
+			var network atomic.Pointer[agent.PeerNetwork]
+			defer func() {
+				if n := network.Load(); n != nil {
+					n.Close()
+				}
+			}()
+			dnsDone := make(chan error, 1)
+			go func() {
+				dnsDone <- tailnet.ServeDNS(ctx, logger.Named("dns"), dnsConn, func(hostname string) []netip.Addr {
+					n := network.Load()
+					if n == nil {
+						return nil
+					}
+					addr, ok := n.Hosts()[hostname]
+					if !ok {
+						return nil
+					}
+					return []netip.Addr{addr}
+				})
+			}()
+			_, _ = fmt.Fprintf(inv.Stderr, "Resolving *.%s hostnames at %s. Configure your system to use it for the %q domain.\n",
+				vpnDomain, cliui.Styles.Code.Render("udp://"+dnsConn.LocalAddr().String()), vpnDomain)
+
+			peers := &vpnPeerClient{
+				client: client,
+				filter: codersdk.WorkspaceFilter{FilterQuery: searchQuery},
+			}
+			ticker := time.NewTicker(10 * time.Second)
+			defer ticker.Stop()
+			for {
+				err := refreshVPN(ctx, client, peers, &network, logger, tunName)
+				if err != nil && ctx.Err() == nil {
+					_, _ = fmt.Fprintf(inv.Stderr, "Failed to refresh workspaces: %s\n", err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return <-dnsDone
+				case err := <-dnsDone:
+					return xerrors.Errorf("serve dns: %w", err)
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "dns-address",
+			Env:         "CODER_VPN_DNS_ADDRESS",
+			Description: "The UDP address to serve DNS for workspace hostnames on.",
+			Default:     "127.0.0.1:53535",
+			Value:       clibase.StringOf(&dnsAddress),
+		},
+		{
+			Flag:        "tun",
+			Env:         "CODER_VPN_TUN",
+			Description: "Create a TUN device with this name and route the tailnet through it, so workspace hostnames resolve to tailnet addresses. Requires root.",
+			Value:       clibase.StringOf(&tunName),
+		},
+		{
+			Flag:        "search",
+			Description: "Search for the workspaces to connect to with a query.",
+			Default:     "owner:me",
+			Value:       clibase.StringOf(&searchQuery),
+		},
+	}
+	return cmd
+}
+
+// refreshVPN creates the network once a workspace agent is running and
+// connects it to every running agent.
+func refreshVPN(ctx context.Context, client *codersdk.Client, peers *vpnPeerClient, network *atomic.Pointer[agent.PeerNetwork], logger slog.Logger, tunName string) error {
+	n := network.Load()
+	if n == nil {
+		list, err := peers.Peers(ctx)
+		if err != nil {
+			return err
+		}
+		// The DERP map is only served with the connection info of an agent.
+		if len(list) == 0 {
+			return nil
+		}
+		connInfo, err := client.WorkspaceAgentConnectionInfo(ctx, list[0].AgentID)
+		if err != nil {
+			return xerrors.Errorf("get connection info: %w", err)
+		}
+		n, err = agent.NewPeerNetwork(agent.PeerNetworkOptions{
+			Logger:     logger.Named("vpn"),
+			Client:     peers,
+			DERPMap:    connInfo.DERPMap,
+			DERPHeader: client.DERPHeader(),
+			TUNName:    tunName,
+		})
+		if err != nil {
+			return xerrors.Errorf("create network: %w", err)
+		}
+		network.Store(n)
+	}
+	return n.Refresh(ctx)
+}
+
+// vpnPeerClient connects a PeerNetwork to the agents of running workspaces.
+type vpnPeerClient struct {
+	client *codersdk.Client
+	filter codersdk.WorkspaceFilter
+}
+
+func (c *vpnPeerClient) Peers(ctx context.Context) ([]agentsdk.Peer, error) {
+	res, err := c.client.Workspaces(ctx, c.filter)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspaces: %w", err)
+	}
+	peers := []agentsdk.Peer{}
+	for _, workspace := range res.Workspaces {
+		if workspace.LatestBuild.Status != codersdk.WorkspaceStatusRunning {
+			continue
+		}
+		for _, resource := range workspace.LatestBuild.Resources {
+			for _, workspaceAgent := range resource.Agents {
+				peers = append(peers, agentsdk.Peer{
+					AgentID:       workspaceAgent.ID,
+					AgentName:     workspaceAgent.Name,
+					WorkspaceName: workspace.Name,
+					IP:            tailnet.IPFromUUID(workspaceAgent.ID),
+					Hostnames: []string{
+						strings.ToLower(fmt.Sprintf("%s.%s.%s.%s", workspaceAgent.Name, workspace.Name, workspace.OwnerName, vpnDomain)),
+					},
+				})
+			}
+		}
+	}
+	return peers, nil
+}
+
+func (c *vpnPeerClient) ListenPeer(ctx context.Context, agentID uuid.UUID) (net.Conn, error) {
+	return c.client.WorkspaceAgentCoordinate(ctx, agentID)
+}
@@ -0,0 +1,78 @@
+package cli_test
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/agent"
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisionersdk/proto"
+	"github.com/coder/coder/pty/ptytest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestVPN(t *testing.T) {
+	t.Parallel()
+	if !agent.LoopbackForwardingSupported() {
+		t.Skip("Peer ports are only forwarded from loopback addresses on Linux and Windows")
+	}
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Name = "main"
+		return agents
+	})
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent"),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+
+	// Reserve a port for the DNS server.
+	dnsConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dnsAddress := dnsConn.LocalAddr().String()
+	_ = dnsConn.Close()
+
+	inv, root := clitest.New(t, "vpn", "--dns-address", dnsAddress)
+	clitest.SetupConfig(t, client, root)
+	pty := ptytest.New(t)
+	inv.Stdin = pty.Input()
+	inv.Stderr = pty.Output()
+	inv.Stdout = pty.Output()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	cmdDone := tGo(t, func() {
+		err := inv.WithContext(ctx).Run()
+		assert.NoError(t, err)
+	})
+	pty.ExpectMatch("Resolving")
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", dnsAddress)
+		},
+	}
+	hostname := "main." + workspace.Name + "." + workspace.OwnerName + ".coder"
+	require.Eventually(t, func() bool {
+		addrs, err := resolver.LookupNetIP(ctx, "ip4", hostname)
+		return err == nil && len(addrs) == 1 && addrs[0] == netip.MustParseAddr("127.42.0.1")
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	cancel()
+	<-cmdDone
+}
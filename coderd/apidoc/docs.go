@@ -359,6 +359,31 @@ const docTemplate = `{
                 }
             }
         },
+        "/debug/derp": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Debug DERP health",
+                "operationId": "debug-derp-health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DERPHealthReport"
+                        }
+                    }
+                }
+            }
+        },
         "/deployment/config": {
             "get": {
                 "security": [
@@ -434,6 +459,31 @@ const docTemplate = `{
                 }
             }
         },
+        "/derp-map": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get DERP map",
+                "operationId": "get-derp-map",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tailcfg.DERPMap"
+                        }
+                    }
+                }
+            }
+        },
         "/entitlements": {
             "get": {
                 "security": [
@@ -820,81 +870,80 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations": {
-            "post": {
+        "/oauth2-provider/apps": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "OAuth2"
                 ],
-                "summary": "Create organization",
-                "operationId": "create-organization",
+                "summary": "Get OAuth2 apps",
+                "operationId": "get-oauth2-apps",
                 "parameters": [
                     {
-                        "description": "Create organization request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateOrganizationRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter by applications authorized for a user",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "OAuth2"
                 ],
-                "summary": "Get organization by ID",
-                "operationId": "get-organization-by-id",
+                "summary": "Create OAuth2 app",
+                "operationId": "create-oauth2-app",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
+                        "description": "The OAuth2 app to create.",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PostOAuth2ProviderAppRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/groups": {
+        "/oauth2-provider/apps/{app}": {
             "get": {
                 "security": [
                     {
@@ -905,16 +954,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "OAuth2"
                 ],
-                "summary": "Get groups by organization",
-                "operationId": "get-groups-by-organization",
+                "summary": "Get OAuth2 app",
+                "operationId": "get-oauth2-app",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
@@ -923,15 +971,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Group"
-                            }
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -944,81 +989,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "OAuth2"
                 ],
-                "summary": "Create group for organization",
-                "operationId": "create-group-for-organization",
+                "summary": "Update OAuth2 app",
+                "operationId": "update-oauth2-app",
                 "parameters": [
                     {
-                        "description": "Create group request",
+                        "type": "string",
+                        "description": "App ID",
+                        "name": "app",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update an OAuth2 app.",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateGroupRequest"
+                            "$ref": "#/definitions/codersdk.PutOAuth2ProviderAppRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Group"
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderApp"
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/groups/{groupName}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "OAuth2"
                 ],
-                "summary": "Get group by organization and group name",
-                "operationId": "get-group-by-organization-and-group-name",
+                "summary": "Delete OAuth2 app",
+                "operationId": "delete-oauth2-app",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Group name",
-                        "name": "groupName",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Group"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/organizations/{organization}/members/roles": {
+        "/oauth2-provider/apps/{app}/secrets": {
             "get": {
                 "security": [
                     {
@@ -1029,16 +1058,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "OAuth2"
                 ],
-                "summary": "Get member roles by organization",
-                "operationId": "get-member-roles-by-organization",
+                "summary": "Get OAuth2 app secrets",
+                "operationId": "get-oauth2-app-secrets",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
@@ -1049,188 +1077,148 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                                "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecret"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/members/{user}/roles": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "OAuth2"
                 ],
-                "summary": "Assign role to organization member",
-                "operationId": "assign-role-to-organization-member",
+                "summary": "Create OAuth2 app secret",
+                "operationId": "create-oauth2-app-secret",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update roles request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.OrganizationMember"
+                            "$ref": "#/definitions/codersdk.OAuth2ProviderAppSecretFull"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/members/{user}/workspaces": {
-            "post": {
+        "/oauth2-provider/apps/{app}/secrets/{secretID}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Workspaces"
+                    "OAuth2"
                 ],
-                "summary": "Create user workspace by organization",
-                "operationId": "create-user-workspace-by-organization",
+                "summary": "Delete OAuth2 app secret",
+                "operationId": "delete-oauth2-app-secret",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Username, UUID, or me",
-                        "name": "user",
+                        "description": "Secret ID",
+                        "name": "secretID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Create workspace request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/organizations/{organization}/provisionerdaemons": {
-            "get": {
+        "/oauth2-provider/apps/{app}/tokens": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "OAuth2"
                 ],
-                "summary": "Get provisioner daemons",
-                "operationId": "get-provisioner-daemons",
+                "summary": "Revoke OAuth2 app tokens",
+                "operationId": "revoke-oauth2-app-tokens",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "App ID",
+                        "name": "app",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/organizations/{organization}/provisionerdaemons/serve": {
-            "get": {
+        "/organizations": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Enterprise"
+                    "Organizations"
                 ],
-                "summary": "Serve provisioner daemon",
-                "operationId": "serve-provisioner-daemon",
+                "summary": "Create organization",
+                "operationId": "create-organization",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
+                        "description": "Create organization request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateOrganizationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Organization"
+                        }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates": {
+        "/organizations/{organization}": {
             "get": {
                 "security": [
                     {
@@ -1241,10 +1229,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Organizations"
                 ],
-                "summary": "Get templates by organization",
-                "operationId": "get-templates-by-organization",
+                "summary": "Get organization by ID",
+                "operationId": "get-organization-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -1259,43 +1247,31 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Template"
-                            }
+                            "$ref": "#/definitions/codersdk.Organization"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/organizations/{organization}/groups": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template by organization",
-                "operationId": "create-template-by-organization",
+                "summary": "Get groups by organization",
+                "operationId": "get-groups-by-organization",
                 "parameters": [
-                    {
-                        "description": "Request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
-                        }
-                    },
                     {
                         "type": "string",
+                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
@@ -1306,31 +1282,43 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Group"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/templates/examples": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template examples by organization",
-                "operationId": "get-template-examples-by-organization",
+                "summary": "Create group for organization",
+                "operationId": "create-group-for-organization",
                 "parameters": [
+                    {
+                        "description": "Create group request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateGroupRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
@@ -1338,19 +1326,16 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateExample"
-                            }
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}": {
+        "/organizations/{organization}/groups/{groupName}": {
             "get": {
                 "security": [
                     {
@@ -1361,10 +1346,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get templates by organization and template name",
-                "operationId": "get-templates-by-organization-and-template-name",
+                "summary": "Get group by organization and group name",
+                "operationId": "get-group-by-organization-and-group-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -1376,8 +1361,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
+                        "description": "Group name",
+                        "name": "groupName",
                         "in": "path",
                         "required": true
                     }
@@ -1386,13 +1371,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
+        "/organizations/{organization}/members/roles": {
             "get": {
                 "security": [
                     {
@@ -1403,10 +1388,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get template version by organization, template, and name",
-                "operationId": "get-template-version-by-organization-template-and-name",
+                "summary": "Get member roles by organization",
+                "operationId": "get-member-roles-by-organization",
                 "parameters": [
                     {
                         "type": "string",
@@ -1415,51 +1400,42 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
-            "get": {
+        "/organizations/{organization}/members/{user}/roles": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get previous template version by organization, template, and name",
-                "operationId": "get-previous-template-version-by-organization-template-and-name",
+                "summary": "Assign role to organization member",
+                "operationId": "assign-role-to-organization-member",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
@@ -1467,30 +1443,32 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Update roles request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.OrganizationMember"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templateversions": {
+        "/organizations/{organization}/members/{user}/workspaces": {
             "post": {
                 "security": [
                     {
@@ -1504,10 +1482,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Workspaces"
                 ],
-                "summary": "Create template version by organization",
-                "operationId": "create-template-version-by-organization",
+                "summary": "Create user workspace by organization",
+                "operationId": "create-user-workspace-by-organization",
                 "parameters": [
                     {
                         "type": "string",
@@ -1518,26 +1496,33 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create template version request",
+                        "type": "string",
+                        "description": "Username, UUID, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
             }
         },
-        "/parameters/{scope}/{id}": {
+        "/organizations/{organization}/provisionerdaemons": {
             "get": {
                 "security": [
                     {
@@ -1548,28 +1533,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parameters"
+                    "Enterprise"
                 ],
-                "summary": "Get parameters",
-                "operationId": "get-parameters",
+                "summary": "Get provisioner daemons",
+                "operationId": "get-provisioner-daemons",
                 "parameters": [
-                    {
-                        "enum": [
-                            "template",
-                            "workspace",
-                            "import_job"
-                        ],
-                        "type": "string",
-                        "description": "Scope",
-                        "name": "scope",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -1580,72 +1553,44 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Parameter"
+                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
                             }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/organizations/{organization}/provisionerdaemons/serve": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Parameters"
+                    "Enterprise"
                 ],
-                "summary": "Create parameter",
-                "operationId": "create-parameter",
+                "summary": "Serve provisioner daemon",
+                "operationId": "serve-provisioner-daemon",
                 "parameters": [
-                    {
-                        "description": "Parameter request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateParameterRequest"
-                        }
-                    },
-                    {
-                        "enum": [
-                            "template",
-                            "workspace",
-                            "import_job"
-                        ],
-                        "type": "string",
-                        "description": "Scope",
-                        "name": "scope",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Parameter"
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/parameters/{scope}/{id}/{name}": {
-            "delete": {
+        "/organizations/{organization}/templates": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1655,97 +1600,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Parameters"
+                    "Templates"
                 ],
-                "summary": "Delete parameter",
-                "operationId": "delete-parameter",
+                "summary": "Get templates by organization",
+                "operationId": "get-templates-by-organization",
                 "parameters": [
-                    {
-                        "enum": [
-                            "template",
-                            "workspace",
-                            "import_job"
-                        ],
-                        "type": "string",
-                        "description": "Scope",
-                        "name": "scope",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Name",
-                        "name": "name",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/replicas": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "Get active replicas",
-                "operationId": "get-active-replicas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Replica"
+                                "$ref": "#/definitions/codersdk.Template"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/scim/v2/Users": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/scim+json"
-                ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "SCIM 2.0: Get users",
-                "operationId": "scim-get-users",
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    }
-                }
             },
             "post": {
                 "security": [
@@ -1753,36 +1632,46 @@ const docTemplate = `{
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Create new user",
-                "operationId": "scim-create-new-user",
+                "summary": "Create template by organization",
+                "operationId": "create-template-by-organization",
                 "parameters": [
                     {
-                        "description": "New user",
+                        "description": "Request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users/{id}": {
+        "/organizations/{organization}/templates/examples": {
             "get": {
                 "security": [
                     {
@@ -1790,73 +1679,79 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Get user by ID",
-                "operationId": "scim-get-user-by-id",
+                "summary": "Get template examples by organization",
+                "operationId": "get-template-examples-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "404": {
-                        "description": "Not Found"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateExample"
+                            }
+                        }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/templates/{templatename}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Update user account",
-                "operationId": "scim-update-user-status",
+                "summary": "Get templates by organization and template name",
+                "operationId": "get-templates-by-organization-and-template-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update user request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
-                        }
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}": {
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
             "get": {
                 "security": [
                     {
@@ -1869,14 +1764,28 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template metadata by ID",
-                "operationId": "get-template-metadata-by-id",
+                "summary": "Get template version by organization, template, and name",
+                "operationId": "get-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
                         "in": "path",
                         "required": true
                     }
@@ -1885,12 +1794,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1902,14 +1813,28 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Delete template by ID",
-                "operationId": "delete-template-by-id",
+                "summary": "Get previous template version by organization, template, and name",
+                "operationId": "get-previous-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
                         "in": "path",
                         "required": true
                     }
@@ -1918,46 +1843,60 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/templateversions": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Update template metadata by ID",
-                "operationId": "update-template-metadata-by-id",
+                "summary": "Create template version by organization",
+                "operationId": "create-template-version-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Create template version request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/acl": {
+        "/parameters/{scope}/{id}": {
             "get": {
                 "security": [
                     {
@@ -1968,16 +1907,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Parameters"
                 ],
-                "summary": "Get template ACLs",
-                "operationId": "get-template-acls",
+                "summary": "Get parameters",
+                "operationId": "get-parameters",
                 "parameters": [
+                    {
+                        "enum": [
+                            "template",
+                            "workspace",
+                            "import_job"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1988,13 +1939,13 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateUser"
+                                "$ref": "#/definitions/codersdk.Parameter"
                             }
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2007,41 +1958,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Parameters"
                 ],
-                "summary": "Update template ACL",
-                "operationId": "update-template-acl",
+                "summary": "Create parameter",
+                "operationId": "create-parameter",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update template request",
+                        "description": "Parameter request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                            "$ref": "#/definitions/codersdk.CreateParameterRequest"
                         }
+                    },
+                    {
+                        "enum": [
+                            "template",
+                            "workspace",
+                            "import_job"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.Parameter"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/daus": {
-            "get": {
+        "/parameters/{scope}/{id}/{name}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2051,16 +2014,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Parameters"
                 ],
-                "summary": "Get template DAUs by ID",
-                "operationId": "get-template-daus-by-id",
+                "summary": "Delete parameter",
+                "operationId": "delete-parameter",
                 "parameters": [
+                    {
+                        "enum": [
+                            "template",
+                            "workspace",
+                            "import_job"
+                        ],
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -2069,13 +2051,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateDAUsResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions": {
+        "/provisionerdaemons": {
             "get": {
                 "security": [
                     {
@@ -2086,36 +2068,89 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "General"
                 ],
-                "summary": "List template versions by template ID",
-                "operationId": "list-template-versions-by-template-id",
+                "summary": "Get all provisioner daemons",
+                "operationId": "get-all-provisioner-daemons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/replicas": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get active replicas",
+                "operationId": "get-active-replicas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Replica"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Groups": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get groups",
+                "operationId": "scim-get-groups",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
+                        "description": "SCIM filter",
+                        "name": "filter",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
+                        "type": "integer",
+                        "description": "1-based index of the first result",
+                        "name": "startIndex",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
+                        "description": "Maximum number of results",
+                        "name": "count",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
+                        "type": "string",
+                        "description": "Attributes to exclude, e.g. members",
+                        "name": "excludedAttributes",
                         "in": "query"
                     }
                 ],
@@ -2123,61 +2158,47 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
-                            }
+                            "$ref": "#/definitions/coderd.SCIMListResponse"
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Update active template version by template ID",
-                "operationId": "update-active-template-version-by-template-id",
+                "summary": "SCIM 2.0: Create new group",
+                "operationId": "scim-create-new-group",
                 "parameters": [
                     {
-                        "description": "Modified template version",
+                        "description": "New group",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions/{templateversionname}": {
+        "/scim/v2/Groups/{id}": {
             "get": {
                 "security": [
                     {
@@ -2185,110 +2206,140 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version by template ID and name",
-                "operationId": "get-template-version-by-template-id-and-name",
+                "summary": "SCIM 2.0: Get group by ID",
+                "operationId": "scim-get-group-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Attributes to exclude, e.g. members",
+                        "name": "excludedAttributes",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
-                            }
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
-                    }
-                }
-            }
-        },
-        "/templateversions/{templateversion}": {
-            "get": {
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version by ID",
-                "operationId": "get-template-version-by-id",
+                "summary": "SCIM 2.0: Replace group",
+                "operationId": "scim-replace-group",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Replace group request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMGroup"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             },
-            "patch": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Delete group",
+                "operationId": "scim-delete-group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Patch template version by ID",
-                "operationId": "patch-template-version-by-id",
+                "summary": "SCIM 2.0: Update group",
+                "operationId": "scim-update-group",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Patch template version request",
+                        "description": "Update group request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
+                            "$ref": "#/definitions/coderd.SCIMPatchRequest"
                         }
                     }
                 ],
@@ -2296,81 +2347,78 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/cancel": {
-            "patch": {
+        "/scim/v2/Users": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Cancel template version by ID",
-                "operationId": "cancel-template-version-by-id",
+                "summary": "SCIM 2.0: Get users",
+                "operationId": "scim-get-users",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "description": "SCIM filter",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based index of the first result",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results",
+                        "name": "count",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMListResponse"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run": {
+            },
             "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template version dry-run",
-                "operationId": "create-template-version-dry-run",
+                "summary": "SCIM 2.0: Create new user",
+                "operationId": "scim-create-new-user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Dry-run request",
+                        "description": "New user",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 ],
@@ -2378,13 +2426,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}": {
+        "/scim/v2/Users/{id}": {
             "get": {
                 "security": [
                     {
@@ -2392,27 +2440,19 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version dry-run by job ID",
-                "operationId": "get-template-version-dry-run-by-job-id",
+                "summary": "SCIM 2.0: Get user by ID",
+                "operationId": "scim-get-user-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2421,120 +2461,100 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
-            "patch": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Cancel template version dry-run by job ID",
-                "operationId": "cancel-template-version-dry-run-by-job-id",
+                "summary": "SCIM 2.0: Replace user account",
+                "operationId": "scim-replace-user",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "description": "Replace user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version dry-run logs by job ID",
-                "operationId": "get-template-version-dry-run-logs-by-job-id",
+                "summary": "SCIM 2.0: Update user account",
+                "operationId": "scim-update-user-status",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Before Unix timestamp",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After Unix timestamp",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
+                        "description": "Update user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMPatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
+        "/templates/{template}": {
             "get": {
                 "security": [
                     {
@@ -2547,22 +2567,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template version dry-run resources by job ID",
-                "operationId": "get-template-version-dry-run-resources-by-job-id",
+                "summary": "Get template metadata by ID",
+                "operationId": "get-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -2571,17 +2583,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/gitauth": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2593,14 +2600,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get git auth by template version",
-                "operationId": "get-git-auth-by-template-version",
+                "summary": "Delete template by ID",
+                "operationId": "delete-template-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -2609,17 +2616,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionGitAuth"
-                            }
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/logs": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2631,50 +2633,29 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get logs by template version",
-                "operationId": "get-logs-by-template-version",
+                "summary": "Update template metadata by ID",
+                "operationId": "update-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/parameters": {
+        "/templates/{template}/acl": {
             "get": {
                 "security": [
                     {
@@ -2685,16 +2666,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get parameters by template version",
-                "operationId": "get-parameters-by-template-version",
+                "summary": "Get template ACLs",
+                "operationId": "get-template-acls",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -2705,52 +2686,59 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/parameter.ComputedValue"
+                                "$ref": "#/definitions/codersdk.TemplateUser"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/resources": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get resources by template version",
-                "operationId": "get-resources-by-template-version",
+                "summary": "Update template ACL",
+                "operationId": "update-template-acl",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update template request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/rich-parameters": {
+        "/templates/{template}/app-limits": {
             "get": {
                 "security": [
                     {
@@ -2763,14 +2751,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get rich parameters by template version",
-                "operationId": "get-rich-parameters-by-template-version",
+                "summary": "Get template app limits",
+                "operationId": "get-template-app-limits",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -2779,112 +2767,85 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateAppLimits"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/schema": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get schema by template version",
-                "operationId": "get-schema-by-template-version",
+                "summary": "Update template app limits",
+                "operationId": "update-template-app-limits",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Limits request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateTemplateAppLimitsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ParameterSchema"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateAppLimits"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/variables": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template variables by template version",
-                "operationId": "get-template-variables-by-template-version",
+                "summary": "Delete template app limits",
+                "operationId": "delete-template-app-limits",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/updatecheck": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "General"
-                ],
-                "summary": "Update check",
-                "operationId": "update-check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/users": {
+        "/templates/{template}/app-routing": {
             "get": {
                 "security": [
                     {
@@ -2895,47 +2856,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get users",
-                "operationId": "get-users",
+                "summary": "Get template app routing",
+                "operationId": "get-template-app-routing",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GetUsersResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateAppRouting"
+                            }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/templates/{template}/app-routing/{slug}": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2948,57 +2897,116 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create new user",
-                "operationId": "create-new-user",
+                "summary": "Upsert template app routing",
+                "operationId": "upsert-template-app-routing",
                 "parameters": [
                     {
-                        "description": "Create user request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Routing request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateUserRequest"
+                            "$ref": "#/definitions/codersdk.UpsertTemplateAppRoutingRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateAppRouting"
                         }
                     }
                 }
-            }
-        },
-        "/users/authmethods": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Users"
+                    "Templates"
+                ],
+                "summary": "Delete template app routing",
+                "operationId": "delete-template-app-routing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/templates/{template}/daus": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template DAUs by ID",
+                "operationId": "get-template-daus-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get authentication methods",
-                "operationId": "get-authentication-methods",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.AuthMethods"
+                            "$ref": "#/definitions/codersdk.TemplateDAUsResponse"
                         }
                     }
                 }
             }
         },
-        "/users/first": {
+        "/templates/{template}/versions": {
             "get": {
                 "security": [
                     {
@@ -3009,20 +3017,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
+                ],
+                "summary": "List template versions by template ID",
+                "operationId": "list-template-versions-by-template-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Check initial user created",
-                "operationId": "check-initial-user-created",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                            }
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3035,33 +3075,124 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create initial user",
-                "operationId": "create-initial-user",
+                "summary": "Update active template version by template ID",
+                "operationId": "update-active-template-version-by-template-id",
                 "parameters": [
                     {
-                        "description": "First user request",
+                        "description": "Modified template version",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
+                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/users/login": {
-            "post": {
+        "/templates/{template}/versions/{templateversionname}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version by template ID and name",
+                "operationId": "get-template-version-by-template-id-and-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version by ID",
+                "operationId": "get-template-version-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -3069,33 +3200,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authorization"
+                    "Templates"
                 ],
-                "summary": "Log in user",
-                "operationId": "log-in-user",
+                "summary": "Patch template version by ID",
+                "operationId": "patch-template-version-by-id",
                 "parameters": [
                     {
-                        "description": "Login request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch template version request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/users/logout": {
-            "post": {
+        "/templateversions/{templateversion}/cancel": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3105,10 +3244,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
+                ],
+                "summary": "Cancel template version by ID",
+                "operationId": "cancel-template-version-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Log out user",
-                "operationId": "log-out-user",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -3119,18 +3268,942 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/oauth2/github/callback": {
-            "get": {
+        "/templateversions/{templateversion}/dry-run": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "tags": [
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create template version dry-run",
+                "operationId": "create-template-version-dry-run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dry-run request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version dry-run by job ID",
+                "operationId": "get-template-version-dry-run-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Cancel template version dry-run by job ID",
+                "operationId": "cancel-template-version-dry-run-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version dry-run logs by job ID",
+                "operationId": "get-template-version-dry-run-logs-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template version dry-run resources by job ID",
+                "operationId": "get-template-version-dry-run-resources-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/gitauth": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get git auth by template version",
+                "operationId": "get-git-auth-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionGitAuth"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get logs by template version",
+                "operationId": "get-logs-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/parameters": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get parameters by template version",
+                "operationId": "get-parameters-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/parameter.ComputedValue"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get resources by template version",
+                "operationId": "get-resources-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/rich-parameters": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get rich parameters by template version",
+                "operationId": "get-rich-parameters-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/schema": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get schema by template version",
+                "operationId": "get-schema-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ParameterSchema"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/variables": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template variables by template version",
+                "operationId": "get-template-variables-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/updatecheck": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Update check",
+                "operationId": "update-check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get users",
+                "operationId": "get-users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GetUsersResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create new user",
+                "operationId": "create-new-user",
+                "parameters": [
+                    {
+                        "description": "Create user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/authmethods": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get authentication methods",
+                "operationId": "get-authentication-methods",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AuthMethods"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/first": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Check initial user created",
+                "operationId": "check-initial-user-created",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create initial user",
+                "operationId": "create-initial-user",
+                "parameters": [
+                    {
+                        "description": "First user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Log in user",
+                "operationId": "log-in-user",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login/webauthn": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Create security key login challenge",
+                "operationId": "create-security-key-login-challenge",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WebAuthnRequestOptions"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Log out user",
+                "operationId": "log-out-user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/oauth2/github/callback": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "OAuth 2.0 GitHub Callback",
+                "operationId": "oauth-20-github-callback",
+                "responses": {
+                    "307": {
+                        "description": "Temporary Redirect"
+                    }
+                }
+            }
+        },
+        "/users/oidc/callback": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "OpenID Connect Callback",
+                "operationId": "openid-connect-callback",
+                "responses": {
+                    "307": {
+                        "description": "Temporary Redirect"
+                    }
+                }
+            }
+        },
+        "/users/roles": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get site member roles",
+                "operationId": "get-site-member-roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/saml/acs": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
                     "Users"
                 ],
-                "summary": "OAuth 2.0 GitHub Callback",
-                "operationId": "oauth-20-github-callback",
+                "summary": "SAML assertion consumer service",
+                "operationId": "saml-assertion-consumer-service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base64 encoded SAML response",
+                        "name": "SAMLResponse",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Redirect after login",
+                        "name": "RelayState",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "303": {
+                        "description": "See Other"
+                    }
+                }
+            }
+        },
+        "/users/saml/login": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "SAML login",
+                "operationId": "saml-login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Redirect after login",
+                        "name": "redirect",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "307": {
                         "description": "Temporary Redirect"
@@ -3138,26 +4211,273 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/oidc/callback": {
+        "/users/saml/metadata": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "SAML service provider metadata",
+                "operationId": "saml-service-provider-metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/users/{user}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by name",
+                "operationId": "get-user-by-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user",
+                "operationId": "delete-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/gitsshkey": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user Git SSH key",
+                "operationId": "get-user-git-ssh-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Regenerate user SSH key",
+                "operationId": "regenerate-user-ssh-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create new session key",
+                "operationId": "create-new-session-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/tokens": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user tokens",
+                "operationId": "get-user-tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.APIKey"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "OpenID Connect Callback",
-                "operationId": "openid-connect-callback",
+                "summary": "Create token API key",
+                "operationId": "create-token-api-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                        }
+                    }
+                ],
                 "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                        }
                     }
                 }
             }
         },
-        "/users/roles": {
+        "/users/{user}/keys/tokens/tokenconfig": {
             "get": {
                 "security": [
                     {
@@ -3168,24 +4488,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "General"
+                ],
+                "summary": "Get token config",
+                "operationId": "get-token-config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get site member roles",
-                "operationId": "get-site-member-roles",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
-                            }
+                            "$ref": "#/definitions/codersdk.TokenConfig"
                         }
                     }
                 }
             }
         },
-        "/users/{user}": {
+        "/users/{user}/keys/tokens/{keyname}": {
             "get": {
                 "security": [
                     {
@@ -3198,8 +4524,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get user by name",
-                "operationId": "get-user-by-name",
+                "summary": "Get API key by token name",
+                "operationId": "get-api-key-by-token-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -3207,18 +4533,28 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "Key Name",
+                        "name": "keyname",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.APIKey"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{user}/keys/{keyid}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3230,8 +4566,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Delete user",
-                "operationId": "delete-user",
+                "summary": "Get API key by ID",
+                "operationId": "get-api-key-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -3239,33 +4575,36 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.APIKey"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/gitsshkey": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get user Git SSH key",
-                "operationId": "get-user-git-ssh-key",
+                "summary": "Delete API key",
+                "operationId": "delete-api-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3273,18 +4612,25 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/users/{user}/organizations": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3296,8 +4642,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Regenerate user SSH key",
-                "operationId": "regenerate-user-ssh-key",
+                "summary": "Get organizations by user",
+                "operationId": "get-organizations-by-user",
                 "parameters": [
                     {
                         "type": "string",
@@ -3311,14 +4657,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Organization"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys": {
-            "post": {
+        "/users/{user}/organizations/{organizationname}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3330,8 +4679,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Create new session key",
-                "operationId": "create-new-session-key",
+                "summary": "Get organization by user and organization name",
+                "operationId": "get-organization-by-user-and-organization-name",
                 "parameters": [
                     {
                         "type": "string",
@@ -3339,33 +4688,40 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization name",
+                        "name": "organizationname",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                            "$ref": "#/definitions/codersdk.Organization"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens": {
-            "get": {
+        "/users/{user}/password": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get user tokens",
-                "operationId": "get-user-tokens",
+                "summary": "Update user password",
+                "operationId": "update-user-password",
                 "parameters": [
                     {
                         "type": "string",
@@ -3373,21 +4729,26 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.APIKey"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/users/{user}/profile": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3402,8 +4763,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Create token API key",
-                "operationId": "create-token-api-key",
+                "summary": "Update user profile",
+                "operationId": "update-user-profile",
                 "parameters": [
                     {
                         "type": "string",
@@ -3413,26 +4774,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create token request",
+                        "description": "Updated profile",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens/tokenconfig": {
+        "/users/{user}/roles": {
             "get": {
                 "security": [
                     {
@@ -3443,10 +4804,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Users"
                 ],
-                "summary": "Get token config",
-                "operationId": "get-token-config",
+                "summary": "Get user roles",
+                "operationId": "get-user-roles",
                 "parameters": [
                     {
                         "type": "string",
@@ -3460,27 +4821,28 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TokenConfig"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/keys/tokens/{keyname}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get API key by token name",
-                "operationId": "get-api-key-by-token-name",
+                "summary": "Assign role to user",
+                "operationId": "assign-role-to-user",
                 "parameters": [
                     {
                         "type": "string",
@@ -3490,25 +4852,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "string",
-                        "description": "Key Name",
-                        "name": "keyname",
-                        "in": "path",
-                        "required": true
+                        "description": "Update roles request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/{keyid}": {
+        "/users/{user}/service-account": {
             "get": {
                 "security": [
                     {
@@ -3521,8 +4884,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get API key by ID",
-                "operationId": "get-api-key-by-id",
+                "summary": "Get service account",
+                "operationId": "get-service-account",
                 "parameters": [
                     {
                         "type": "string",
@@ -3530,36 +4893,33 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/codersdk.ServiceAccount"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{user}/status/activate": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Delete API key",
-                "operationId": "delete-api-key",
+                "summary": "Activate user account",
+                "operationId": "activate-user-account",
                 "parameters": [
                     {
                         "type": "string",
@@ -3567,25 +4927,20 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
                     }
                 }
             }
         },
-        "/users/{user}/organizations": {
-            "get": {
+        "/users/{user}/status/suspend": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3597,8 +4952,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get organizations by user",
-                "operationId": "get-organizations-by-user",
+                "summary": "Suspend user account",
+                "operationId": "suspend-user-account",
                 "parameters": [
                     {
                         "type": "string",
@@ -3612,16 +4967,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Organization"
-                            }
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/organizations/{organizationname}": {
+        "/users/{user}/two-factor": {
             "get": {
                 "security": [
                     {
@@ -3634,8 +4986,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get organization by user and organization name",
-                "operationId": "get-organization-by-user-and-organization-name",
+                "summary": "Get user two-factor authentication",
+                "operationId": "get-user-two-factor-authentication",
                 "parameters": [
                     {
                         "type": "string",
@@ -3643,40 +4995,28 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization name",
-                        "name": "organizationname",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.TwoFactor"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/password": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Update user password",
-                "operationId": "update-user-password",
+                "summary": "Reset user two-factor authentication",
+                "operationId": "reset-user-two-factor-authentication",
                 "parameters": [
                     {
                         "type": "string",
@@ -3684,15 +5024,6 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update password request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -3702,7 +5033,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user}/profile": {
+        "/users/{user}/two-factor/totp": {
             "put": {
                 "security": [
                     {
@@ -3718,8 +5049,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Update user profile",
-                "operationId": "update-user-profile",
+                "summary": "Confirm user TOTP key",
+                "operationId": "confirm-user-totp-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3729,12 +5060,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated profile",
+                        "description": "Confirm TOTP request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
+                            "$ref": "#/definitions/codersdk.ConfirmTOTPRequest"
                         }
                     }
                 ],
@@ -3742,14 +5073,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TOTPRecoveryCodes"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/roles": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3761,8 +5090,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get user roles",
-                "operationId": "get-user-roles",
+                "summary": "Create user TOTP key",
+                "operationId": "create-user-totp-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3773,15 +5102,43 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TOTPKey"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user TOTP key",
+                "operationId": "delete-user-totp-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/two-factor/webauthn": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3796,8 +5153,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Assign role to user",
-                "operationId": "assign-role-to-user",
+                "summary": "Create user security key",
+                "operationId": "create-user-security-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3807,27 +5164,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Update roles request",
+                        "description": "Create security key request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                            "$ref": "#/definitions/codersdk.CreateWebAuthnCredentialRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.WebAuthnCredential"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/status/activate": {
-            "put": {
+        "/users/{user}/two-factor/webauthn/challenge": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3839,8 +5196,8 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Activate user account",
-                "operationId": "activate-user-account",
+                "summary": "Create user security key challenge",
+                "operationId": "create-user-security-key-challenge",
                 "parameters": [
                     {
                         "type": "string",
@@ -3854,27 +5211,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.WebAuthnCreationOptions"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/status/suspend": {
-            "put": {
+        "/users/{user}/two-factor/webauthn/{credential}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Suspend user account",
-                "operationId": "suspend-user-account",
+                "summary": "Delete user security key",
+                "operationId": "delete-user-security-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -3882,14 +5236,19 @@ const docTemplate = `{
                         "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Security key ID",
+                        "name": "credential",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.User"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
@@ -4190,6 +5549,14 @@ const docTemplate = `{
                 ],
                 "summary": "Coordinate workspace agent via Tailnet",
                 "operationId": "coordinate-workspace-agent-via-tailnet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Highest node version received before reconnecting",
+                        "name": "resume_version",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "101": {
                         "description": "Switching Protocols"
@@ -4238,6 +5605,118 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaceagents/me/gitauth/device": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Begin workspace agent Git auth device flow",
+                "operationId": "begin-workspace-agent-git-auth-device-flow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uri",
+                        "description": "Git URL",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitAuthDevice"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Exchange workspace agent Git auth device code",
+                "operationId": "exchange-workspace-agent-git-auth-device-code",
+                "parameters": [
+                    {
+                        "description": "Device code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GitAuthDeviceExchange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GitAuthResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/gitauth/{gitauth}/access-token": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent Git auth access token",
+                "operationId": "get-workspace-agent-git-auth-access-token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Git auth provider ID",
+                        "name": "gitauth",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GitAuthAccessTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/workspaceagents/me/gitsshkey": {
             "get": {
                 "security": [
@@ -4332,6 +5811,63 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaceagents/me/peers": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent peers",
+                "operationId": "get-workspace-agent-peers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/agentsdk.Peer"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/peers/{workspaceagent}/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent peer",
+                "operationId": "coordinate-workspace-agent-peer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
         "/workspaceagents/me/report-lifecycle": {
             "post": {
                 "security": [
@@ -4578,6 +6114,12 @@ const docTemplate = `{
                         "name": "workspaceagent",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Highest node version received before reconnecting",
+                        "name": "resume_version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -5124,6 +6666,62 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaces/{workspace}/app-access-logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace app access logs",
+                "operationId": "get-workspace-app-access-logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "App slug or port",
+                        "name": "app",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAppAccessLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/workspaces/{workspace}/autostart": {
             "put": {
                 "security": [
@@ -5290,8 +6888,174 @@ const docTemplate = `{
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Extend workspace deadline by ID",
-                "operationId": "extend-workspace-deadline-by-id",
+                "summary": "Extend workspace deadline by ID",
+                "operationId": "extend-workspace-deadline-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Extend deadline update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/peering": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace peering by ID",
+                "operationId": "update-workspace-peering-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workspace peering update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspacePeeringRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/port-shares": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace port shares",
+                "operationId": "get-workspace-port-shares",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspacePortShare"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Upsert workspace port share",
+                "operationId": "upsert-workspace-port-share",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Port share request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpsertWorkspacePortShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspacePortShare"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Delete workspace port share",
+                "operationId": "delete-workspace-port-share",
                 "parameters": [
                     {
                         "type": "string",
@@ -5302,21 +7066,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Extend deadline update request",
+                        "description": "Port share request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
+                            "$ref": "#/definitions/codersdk.DeleteWorkspacePortShareRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
@@ -5437,9 +7198,40 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.GitAuthAccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expiry": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "url": {
+                    "description": "URL is set instead of the access token when the workspace owner must\nauthenticate with the provider.",
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.GitAuthDeviceExchange": {
+            "type": "object",
+            "properties": {
+                "device_code": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "agentsdk.GitAuthResponse": {
             "type": "object",
             "properties": {
+                "device_flow": {
+                    "description": "DeviceFlow is true when the user must authenticate with the device\nflow instead of opening the URL.",
+                    "type": "boolean"
+                },
                 "password": {
                     "type": "string"
                 },
@@ -5476,6 +7268,10 @@ const docTemplate = `{
         "agentsdk.Manifest": {
             "type": "object",
             "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "apps": {
                     "type": "array",
                     "items": {
@@ -5521,6 +7317,10 @@ const docTemplate = `{
                 },
                 "vscode_port_proxy_uri": {
                     "type": "string"
+                },
+                "workspace_peering": {
+                    "description": "WorkspacePeering is true when the agent may dial the agents of other\nworkspaces returned by Peers.",
+                    "type": "boolean"
                 }
             }
         },
@@ -5535,6 +7335,32 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.Peer": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "hostnames": {
+                    "description": "Hostnames are the names the peer is reachable at from inside the\nworkspace, e.g. \"backend.coder\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ip": {
+                    "description": "IP is the tailnet address unique to the peer agent.",
+                    "type": "string"
+                },
+                "workspace_name": {
+                    "type": "string"
+                }
+            }
+        },
         "agentsdk.PostAppHealthsRequest": {
             "type": "object",
             "properties": {
@@ -5828,6 +7654,108 @@ const docTemplate = `{
                 }
             }
         },
+        "coderd.SCIMGroup": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMGroupMember"
+                    }
+                },
+                "meta": {
+                    "type": "object",
+                    "properties": {
+                        "resourceType": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "coderd.SCIMGroupMember": {
+            "type": "object",
+            "properties": {
+                "display": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value is the ID of the user.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "coderd.SCIMListResponse": {
+            "type": "object",
+            "properties": {
+                "Resources": {
+                    "type": "array",
+                    "items": {}
+                },
+                "itemsPerPage": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startIndex": {
+                    "type": "integer"
+                },
+                "totalResults": {
+                    "type": "integer"
+                }
+            }
+        },
+        "coderd.SCIMPatchOperation": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "remove",
+                        "replace"
+                    ]
+                },
+                "path": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "coderd.SCIMPatchRequest": {
+            "type": "object",
+            "properties": {
+                "Operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMPatchOperation"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "coderd.SCIMUser": {
             "type": "object",
             "properties": {
@@ -5939,7 +7867,9 @@ const docTemplate = `{
                         "password",
                         "github",
                         "oidc",
-                        "token"
+                        "token",
+                        "saml",
+                        "ldap"
                     ],
                     "allOf": [
                         {
@@ -5950,7 +7880,8 @@ const docTemplate = `{
                 "scope": {
                     "enum": [
                         "all",
-                        "application_connect"
+                        "application_connect",
+                        "custom"
                     ],
                     "allOf": [
                         {
@@ -5958,6 +7889,13 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scopes": {
+                    "description": "Scopes are the fine-grained scope entries of keys with the custom scope.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 },
@@ -5975,11 +7913,13 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "all",
-                "application_connect"
+                "application_connect",
+                "custom"
             ],
             "x-enum-varnames": [
                 "APIKeyScopeAll",
-                "APIKeyScopeApplicationConnect"
+                "APIKeyScopeApplicationConnect",
+                "APIKeyScopeCustom"
             ]
         },
         "codersdk.AddLicenseRequest": {
@@ -6166,11 +8106,17 @@ const docTemplate = `{
                 "github": {
                     "$ref": "#/definitions/codersdk.AuthMethod"
                 },
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.AuthMethod"
+                },
                 "oidc": {
                     "$ref": "#/definitions/codersdk.OIDCAuthMethod"
                 },
                 "password": {
                     "$ref": "#/definitions/codersdk.AuthMethod"
+                },
+                "saml": {
+                    "$ref": "#/definitions/codersdk.SAMLAuthMethod"
                 }
             }
         },
@@ -6263,6 +8209,17 @@ const docTemplate = `{
                 "BuildReasonAutostop"
             ]
         },
+        "codersdk.ConfirmTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateFirstUserRequest": {
             "type": "object",
             "required": [
@@ -6369,6 +8326,20 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateServiceAccountRequest": {
+            "type": "object",
+            "properties": {
+                "owner_group_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "owner_user_id": {
+                    "description": "OwnerUserID defaults to the user creating the service account if no\nowner group is set.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.CreateTemplateRequest": {
             "type": "object",
             "required": [
@@ -6573,7 +8544,8 @@ const docTemplate = `{
                 "scope": {
                     "enum": [
                         "all",
-                        "application_connect"
+                        "application_connect",
+                        "custom"
                     ],
                     "allOf": [
                         {
@@ -6581,6 +8553,13 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scopes": {
+                    "description": "Scopes are fine-grained scope entries. Setting them implies the custom\nscope.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 }
@@ -6591,7 +8570,6 @@ const docTemplate = `{
             "required": [
                 "email",
                 "organization_id",
-                "password",
                 "username"
             ],
             "properties": {
@@ -6606,11 +8584,44 @@ const docTemplate = `{
                 "password": {
                     "type": "string"
                 },
+                "service_account": {
+                    "description": "ServiceAccount creates a user that cannot log in and only authenticates\nwith tokens. The password must be empty.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.CreateServiceAccountRequest"
+                        }
+                    ]
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
+        "codersdk.CreateWebAuthnCredentialRequest": {
+            "type": "object",
+            "required": [
+                "attestation_object",
+                "client_data_json",
+                "name"
+            ],
+            "properties": {
+                "attestation_object": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "client_data_json": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateWorkspaceBuildRequest": {
             "type": "object",
             "required": [
@@ -6734,6 +8745,9 @@ const docTemplate = `{
         "codersdk.DERPConfig": {
             "type": "object",
             "properties": {
+                "health_check_interval": {
+                    "type": "integer"
+                },
                 "path": {
                     "type": "string"
                 },
@@ -6742,14 +8756,95 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DERPHealthReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "healthy": {
+                    "description": "Healthy is true when every region has at least one healthy node.",
+                    "type": "boolean"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.DERPRegionReport"
+                    }
+                }
+            }
+        },
+        "codersdk.DERPNodeReport": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "description": "Healthy is true when the node relays messages, or answers STUN requests\nif it's a STUN-only node.",
+                    "type": "boolean"
+                },
+                "host_name": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "description": "LatencyMilliseconds is the round trip latency of a DERP ping.",
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "stun_enabled": {
+                    "type": "boolean"
+                },
+                "stun_error": {
+                    "type": "string"
+                },
+                "stun_reachable": {
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.DERPRegion": {
             "type": "object",
             "properties": {
                 "latency_ms": {
                     "type": "number"
                 },
-                "preferred": {
-                    "type": "boolean"
+                "preferred": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.DERPRegionReport": {
+            "type": "object",
+            "properties": {
+                "embedded_relay": {
+                    "type": "boolean"
+                },
+                "healthy": {
+                    "description": "Healthy is true when at least one node of the region is healthy.",
+                    "type": "boolean"
+                },
+                "latency_ms": {
+                    "description": "LatencyMilliseconds is the lowest round trip latency of the healthy\nnodes in the region.",
+                    "type": "number"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.DERPNodeReport"
+                    }
+                },
+                "region_code": {
+                    "type": "string"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "region_name": {
+                    "type": "string"
                 }
             }
         },
@@ -6790,6 +8885,21 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DeleteWorkspacePortShareRequest": {
+            "type": "object",
+            "required": [
+                "agent_name",
+                "port"
+            ],
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.DeploymentConfig": {
             "type": "object",
             "properties": {
@@ -6910,6 +9020,9 @@ const docTemplate = `{
                 "in_memory_database": {
                     "type": "boolean"
                 },
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.LDAPConfig"
+                },
                 "logging": {
                     "$ref": "#/definitions/codersdk.LoggingConfig"
                 },
@@ -6958,6 +9071,9 @@ const docTemplate = `{
                 "redirect_to_access_url": {
                     "type": "boolean"
                 },
+                "saml": {
+                    "$ref": "#/definitions/codersdk.SAMLConfig"
+                },
                 "scim_api_key": {
                     "type": "string"
                 },
@@ -6991,6 +9107,9 @@ const docTemplate = `{
                 "trace": {
                     "$ref": "#/definitions/codersdk.TraceConfig"
                 },
+                "two_factor_required": {
+                    "type": "boolean"
+                },
                 "update_check": {
                     "type": "boolean"
                 },
@@ -7003,6 +9122,12 @@ const docTemplate = `{
                 "wildcard_access_url": {
                     "$ref": "#/definitions/clibase.URL"
                 },
+                "workspace_app_access_log_retention": {
+                    "type": "integer"
+                },
+                "workspace_peering": {
+                    "type": "boolean"
+                },
                 "write_config": {
                     "type": "boolean"
                 }
@@ -7110,6 +9235,13 @@ const docTemplate = `{
                 "client_id": {
                     "type": "string"
                 },
+                "device_code_url": {
+                    "type": "string"
+                },
+                "device_flow": {
+                    "description": "DeviceFlow authenticates with the OAuth2 device authorization grant\ninstead of a browser redirect, so users on headless machines can\nauthenticate from the terminal.",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -7136,19 +9268,45 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.GitAuthDevice": {
+            "type": "object",
+            "properties": {
+                "device_code": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn is the number of seconds until the device code expires.",
+                    "type": "integer"
+                },
+                "interval": {
+                    "description": "Interval is the number of seconds to wait between exchange attempts.",
+                    "type": "integer"
+                },
+                "user_code": {
+                    "type": "string"
+                },
+                "verification_uri": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.GitProvider": {
             "type": "string",
             "enum": [
                 "azure-devops",
                 "github",
                 "gitlab",
-                "bitbucket"
+                "bitbucket",
+                "gitea",
+                "oauth2"
             ],
             "x-enum-varnames": [
                 "GitProviderAzureDevops",
                 "GitProviderGitHub",
                 "GitProviderGitLab",
-                "GitProviderBitBucket"
+                "GitProviderBitBucket",
+                "GitProviderGitea",
+                "GitProviderOAuth2"
             ]
         },
         "codersdk.GitSSHKey": {
@@ -7220,13 +9378,61 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "MISSING_TEMPLATE_PARAMETER",
-                "REQUIRED_TEMPLATE_VARIABLES"
+                "REQUIRED_TEMPLATE_VARIABLES",
+                "TEMPLATE_IMPORT_POLICY_VIOLATION",
+                "NO_MATCHING_PROVISIONER_DAEMON"
             ],
             "x-enum-varnames": [
                 "MissingTemplateParameter",
-                "RequiredTemplateVariables"
+                "RequiredTemplateVariables",
+                "TemplateImportPolicyViolation",
+                "NoMatchingProvisionerDaemon"
             ]
         },
+        "codersdk.LDAPConfig": {
+            "type": "object",
+            "properties": {
+                "allow_signups": {
+                    "type": "boolean"
+                },
+                "bind_dn": {
+                    "type": "string"
+                },
+                "bind_password": {
+                    "type": "string"
+                },
+                "ca_file": {
+                    "type": "string"
+                },
+                "email_field": {
+                    "type": "string"
+                },
+                "group_mapping": {
+                    "type": "object"
+                },
+                "groups_field": {
+                    "type": "string"
+                },
+                "start_tls": {
+                    "type": "boolean"
+                },
+                "sync_interval": {
+                    "type": "integer"
+                },
+                "url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "user_base_dn": {
+                    "type": "string"
+                },
+                "user_filter": {
+                    "type": "string"
+                },
+                "username_field": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.License": {
             "type": "object",
             "properties": {
@@ -7310,13 +9516,19 @@ const docTemplate = `{
                 "password",
                 "github",
                 "oidc",
-                "token"
+                "token",
+                "saml",
+                "ldap",
+                "none"
             ],
             "x-enum-varnames": [
                 "LoginTypePassword",
                 "LoginTypeGithub",
                 "LoginTypeOIDC",
-                "LoginTypeToken"
+                "LoginTypeToken",
+                "LoginTypeSAML",
+                "LoginTypeLDAP",
+                "LoginTypeNone"
             ]
         },
         "codersdk.LoginWithPasswordRequest": {
@@ -7332,6 +9544,15 @@ const docTemplate = `{
                 },
                 "password": {
                     "type": "string"
+                },
+                "recovery_code": {
+                    "type": "string"
+                },
+                "totp_code": {
+                    "type": "string"
+                },
+                "webauthn": {
+                    "$ref": "#/definitions/codersdk.WebAuthnAssertion"
                 }
             }
         },
@@ -7346,6 +9567,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.OAuth2AppEndpoints": {
+            "type": "object",
+            "properties": {
+                "authorization": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.OAuth2Config": {
             "type": "object",
             "properties": {
@@ -7386,6 +9618,64 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.OAuth2ProviderApp": {
+            "type": "object",
+            "properties": {
+                "endpoints": {
+                    "description": "Endpoints are included so admins can copy them into the configuration\nof the app.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.OAuth2AppEndpoints"
+                        }
+                    ]
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirect_uris": {
+                    "description": "RedirectURIs are the only URIs authorization codes are sent to.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecret": {
+            "type": "object",
+            "properties": {
+                "client_secret_truncated": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderAppSecretFull": {
+            "type": "object",
+            "properties": {
+                "client_secret_full": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.OIDCAuthMethod": {
             "type": "object",
             "properties": {
@@ -7439,6 +9729,9 @@ const docTemplate = `{
                 "issuer_url": {
                     "type": "string"
                 },
+                "refresh_interval": {
+                    "type": "integer"
+                },
                 "scopes": {
                     "type": "array",
                     "items": {
@@ -7448,6 +9741,12 @@ const docTemplate = `{
                 "sign_in_text": {
                     "type": "string"
                 },
+                "user_role_field": {
+                    "type": "string"
+                },
+                "user_role_mapping": {
+                    "type": "object"
+                },
                 "username_field": {
                     "type": "string"
                 }
@@ -7691,6 +9990,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PostOAuth2ProviderAppRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "redirect_uris"
+            ],
+            "properties": {
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirect_uris": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.PprofConfig": {
             "type": "object",
             "properties": {
@@ -7727,29 +10048,73 @@ const docTemplate = `{
                 },
                 "force_cancel_interval": {
                     "type": "integer"
+                },
+                "import_policy_files": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unmatched_job_timeout": {
+                    "type": "integer"
                 }
             }
         },
         "codersdk.ProvisionerDaemon": {
             "type": "object",
             "properties": {
+                "architecture": {
+                    "type": "string"
+                },
+                "concurrency": {
+                    "description": "Concurrency is the number of jobs the daemon can run at once.",
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string",
                     "format": "date-time"
                 },
+                "current_job_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "hostname": {
+                    "description": "Hostname, OperatingSystem and Architecture describe the host the\ndaemon runs on. They're empty until the first heartbeat.",
+                    "type": "string"
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
+                "last_seen_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "name": {
                     "type": "string"
                 },
+                "operating_system": {
+                    "type": "string"
+                },
                 "provisioners": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "status": {
+                    "enum": [
+                        "offline",
+                        "online",
+                        "busy",
+                        "unknown"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerDaemonStatus"
+                        }
+                    ]
+                },
                 "tags": {
                     "type": "object",
                     "additionalProperties": {
@@ -7763,9 +10128,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/sql.NullTime"
                         }
                     ]
+                },
+                "version": {
+                    "type": "string"
                 }
             }
         },
+        "codersdk.ProvisionerDaemonStatus": {
+            "type": "string",
+            "enum": [
+                "offline",
+                "online",
+                "busy",
+                "unknown"
+            ],
+            "x-enum-varnames": [
+                "ProvisionerDaemonOffline",
+                "ProvisionerDaemonOnline",
+                "ProvisionerDaemonBusy",
+                "ProvisionerDaemonUnknown"
+            ]
+        },
         "codersdk.ProvisionerJob": {
             "type": "object",
             "properties": {
@@ -7787,7 +10170,9 @@ const docTemplate = `{
                 "error_code": {
                     "enum": [
                         "MISSING_TEMPLATE_PARAMETER",
-                        "REQUIRED_TEMPLATE_VARIABLES"
+                        "REQUIRED_TEMPLATE_VARIABLES",
+                        "TEMPLATE_IMPORT_POLICY_VIOLATION",
+                        "NO_MATCHING_PROVISIONER_DAEMON"
                     ],
                     "allOf": [
                         {
@@ -7799,6 +10184,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "has_matching_daemon": {
+                    "description": "HasMatchingDaemon is false when no online provisioner daemon can\nacquire the job, e.g. because none has all of the job's tags. It's\nonly meaningful while the job is pending.",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
@@ -7841,34 +10230,84 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
-                "id": {
+                "id": {
+                    "type": "integer"
+                },
+                "log_level": {
+                    "enum": [
+                        "trace",
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.LogLevel"
+                        }
+                    ]
+                },
+                "log_source": {
+                    "$ref": "#/definitions/codersdk.LogSource"
+                },
+                "output": {
+                    "type": "string"
+                },
+                "resource_progress": {
+                    "description": "ResourceProgress is set when the log line reports progress for a\nspecific resource.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerJobResourceProgress"
+                        }
+                    ]
+                },
+                "stage": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ProvisionerJobResourceProgress": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "elapsed_seconds": {
                     "type": "integer"
                 },
-                "log_level": {
+                "status": {
                     "enum": [
-                        "trace",
-                        "debug",
-                        "info",
-                        "warn",
-                        "error"
+                        "started",
+                        "progress",
+                        "complete",
+                        "errored"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/codersdk.LogLevel"
+                            "$ref": "#/definitions/codersdk.ProvisionerJobResourceStatus"
                         }
                     ]
-                },
-                "log_source": {
-                    "$ref": "#/definitions/codersdk.LogSource"
-                },
-                "output": {
-                    "type": "string"
-                },
-                "stage": {
-                    "type": "string"
                 }
             }
         },
+        "codersdk.ProvisionerJobResourceStatus": {
+            "type": "string",
+            "enum": [
+                "started",
+                "progress",
+                "complete",
+                "errored"
+            ],
+            "x-enum-varnames": [
+                "ProvisionerJobResourceStatusStarted",
+                "ProvisionerJobResourceStatusProgress",
+                "ProvisionerJobResourceStatusComplete",
+                "ProvisionerJobResourceStatusErrored"
+            ]
+        },
         "codersdk.ProvisionerJobStatus": {
             "type": "string",
             "enum": [
@@ -7918,6 +10357,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PutOAuth2ProviderAppRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "redirect_uris"
+            ],
+            "properties": {
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirect_uris": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.RateLimitConfig": {
             "type": "object",
             "properties": {
@@ -7926,6 +10387,20 @@ const docTemplate = `{
                 },
                 "disable_all": {
                     "type": "boolean"
+                },
+                "shared_app": {
+                    "description": "SharedApp and SharedWorkspace limit the requests per minute to apps and\nports shared at the authenticated or public level.",
+                    "type": "integer"
+                },
+                "shared_app_bandwidth": {
+                    "description": "SharedAppBandwidth and SharedWorkspaceBandwidth limit the bytes per\nsecond of the responses of shared apps and ports.",
+                    "type": "integer"
+                },
+                "shared_workspace": {
+                    "type": "integer"
+                },
+                "shared_workspace_bandwidth": {
+                    "type": "integer"
                 }
             }
         },
@@ -8020,6 +10495,58 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.SAMLAuthMethod": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "iconUrl": {
+                    "type": "string"
+                },
+                "signInText": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.SAMLConfig": {
+            "type": "object",
+            "properties": {
+                "allow_signups": {
+                    "type": "boolean"
+                },
+                "email_domain": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "email_field": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "group_mapping": {
+                    "type": "object"
+                },
+                "groups_field": {
+                    "type": "string"
+                },
+                "icon_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "idp_metadata_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "sign_in_text": {
+                    "type": "string"
+                },
+                "username_field": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.SSHConfig": {
             "type": "object",
             "properties": {
@@ -8050,6 +10577,27 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ServiceAccount": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "owner_group_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "owner_user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.ServiceBannerConfig": {
             "type": "object",
             "properties": {
@@ -8138,6 +10686,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TOTPKey": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.TOTPRecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.TelemetryConfig": {
             "type": "object",
             "properties": {
@@ -8166,6 +10736,14 @@ const docTemplate = `{
                 "allow_user_cancel_workspace_jobs": {
                     "type": "boolean"
                 },
+                "build_stage_stats": {
+                    "description": "BuildStageStats breaks the start build time down by stage.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateBuildStageStats"
+                        }
+                    ]
+                },
                 "build_time_stats": {
                     "$ref": "#/definitions/codersdk.TemplateBuildTimeStats"
                 },
@@ -8219,6 +10797,84 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateAppLimits": {
+            "type": "object",
+            "properties": {
+                "app_bandwidth_bytes_per_second": {
+                    "type": "integer"
+                },
+                "app_requests_per_minute": {
+                    "description": "AppRequestsPerMinute and AppBandwidthBytesPerSecond limit each app and\nport.",
+                    "type": "integer"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "workspace_bandwidth_bytes_per_second": {
+                    "type": "integer"
+                },
+                "workspace_requests_per_minute": {
+                    "description": "WorkspaceRequestsPerMinute and WorkspaceBandwidthBytesPerSecond limit\nall apps and ports of a workspace combined.",
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.TemplateAppRouting": {
+            "type": "object",
+            "properties": {
+                "allowed_methods": {
+                    "description": "AllowedMethods are the HTTP methods that may reach the app. All methods\nare allowed if empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "app_slug": {
+                    "type": "string"
+                },
+                "base_path_header": {
+                    "description": "BasePathHeader is the name of a request header that is set to the base\npath of path-based apps, e.g. \"X-Forwarded-Prefix\".",
+                    "type": "string"
+                },
+                "headers": {
+                    "description": "Headers are static request headers set on every proxied request.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "strip_prefix": {
+                    "description": "StripPrefix removes the base path of path-based apps before proxying the\nrequest. Apps that are served from a sub-path, like Jupyter with a\nbase_url, should disable this to receive the full request path.",
+                    "type": "boolean"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "websocket_subprotocols": {
+                    "description": "WebsocketSubprotocols are the WebSocket subprotocols passed through to\nthe app. All subprotocols are passed through if empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateBuildStageStats": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/codersdk.TransitionStats"
+            }
+        },
         "codersdk.TemplateBuildTimeStats": {
             "type": "object",
             "additionalProperties": {
@@ -8552,6 +11208,27 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TwoFactor": {
+            "type": "object",
+            "properties": {
+                "recovery_codes_remaining": {
+                    "type": "integer"
+                },
+                "required": {
+                    "description": "Required is true if the deployment requires password users to set up\na second factor.",
+                    "type": "boolean"
+                },
+                "totp": {
+                    "type": "boolean"
+                },
+                "webauthn_credentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebAuthnCredential"
+                    }
+                }
+            }
+        },
         "codersdk.UpdateActiveTemplateVersion": {
             "type": "object",
             "required": [
@@ -8620,6 +11297,23 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateTemplateAppLimitsRequest": {
+            "type": "object",
+            "properties": {
+                "app_bandwidth_bytes_per_second": {
+                    "type": "integer"
+                },
+                "app_requests_per_minute": {
+                    "type": "integer"
+                },
+                "workspace_bandwidth_bytes_per_second": {
+                    "type": "integer"
+                },
+                "workspace_requests_per_minute": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.UpdateUserPasswordRequest": {
             "type": "object",
             "required": [
@@ -8661,6 +11355,14 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspacePeeringRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceTTLRequest": {
             "type": "object",
             "properties": {
@@ -8669,12 +11371,75 @@ const docTemplate = `{
                 }
             }
         },
-        "codersdk.UploadResponse": {
+        "codersdk.UploadResponse": {
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.UpsertTemplateAppRoutingRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_methods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "base_path_header": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "strip_prefix": {
+                    "description": "StripPrefix defaults to true, which is how apps without routing rules\nare proxied.",
+                    "type": "boolean"
+                },
+                "websocket_subprotocols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.UpsertWorkspacePortShareRequest": {
             "type": "object",
+            "required": [
+                "agent_name",
+                "port",
+                "share_level"
+            ],
             "properties": {
-                "hash": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is optional. The share never expires if it is unset.",
                     "type": "string",
-                    "format": "uuid"
+                    "format": "date-time"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "share_level": {
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppSharingLevel"
+                        }
+                    ]
                 }
             }
         },
@@ -8720,6 +11485,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.Role"
                     }
                 },
+                "service_account": {
+                    "description": "ServiceAccount is set for users that cannot log in and only\nauthenticate with tokens.",
+                    "type": "boolean"
+                },
                 "status": {
                     "enum": [
                         "active",
@@ -8784,6 +11553,132 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WebAuthnAssertion": {
+            "type": "object",
+            "required": [
+                "authenticator_data",
+                "client_data_json",
+                "credential_id",
+                "signature"
+            ],
+            "properties": {
+                "authenticator_data": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "client_data_json": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "credential_id": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "signature": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "codersdk.WebAuthnCreationOptions": {
+            "type": "object",
+            "properties": {
+                "algorithms": {
+                    "description": "Algorithms are the COSE identifiers of the supported public key\nalgorithms.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "challenge": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "exclude_credentials": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "rp_id": {
+                    "type": "string"
+                },
+                "rp_name": {
+                    "type": "string"
+                },
+                "timeout_ms": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WebAuthnCredential": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WebAuthnRequestOptions": {
+            "type": "object",
+            "properties": {
+                "allow_credentials": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "challenge": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "rp_id": {
+                    "type": "string"
+                },
+                "timeout_ms": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.Workspace": {
             "type": "object",
             "properties": {
@@ -8822,6 +11717,10 @@ const docTemplate = `{
                 "owner_name": {
                     "type": "string"
                 },
+                "peering_enabled": {
+                    "description": "PeeringEnabled is whether the agents of the workspace connect to the\nagents of the owner's other workspaces that enabled peering.",
+                    "type": "boolean"
+                },
                 "template_allow_user_cancel_workspace_jobs": {
                     "type": "boolean"
                 },
@@ -9125,6 +12024,76 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAppAccessLog": {
+            "type": "object",
+            "properties": {
+                "access_method": {
+                    "type": "string",
+                    "enum": [
+                        "path",
+                        "subdomain",
+                        "terminal"
+                    ]
+                },
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "app_slug_or_port": {
+                    "type": "string"
+                },
+                "bytes_written": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "sharing_level": {
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppSharingLevel"
+                        }
+                    ]
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "UserID and Username are empty for unauthenticated requests to public\napps.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceAppHealth": {
             "type": "string",
             "enum": [
@@ -9232,6 +12201,13 @@ const docTemplate = `{
                 "template_version_name": {
                     "type": "string"
                 },
+                "timings": {
+                    "description": "Timings are ordered by stage. Stages that haven't started yet, or that\ndon't apply to the build, are omitted.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildTiming"
+                    }
+                },
                 "transition": {
                     "enum": [
                         "start",
@@ -9275,6 +12251,57 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceBuildStage": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "init",
+                "plan",
+                "apply",
+                "agent_connect",
+                "startup_script"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceBuildStageQueued",
+                "WorkspaceBuildStageInit",
+                "WorkspaceBuildStagePlan",
+                "WorkspaceBuildStageApply",
+                "WorkspaceBuildStageAgentConnect",
+                "WorkspaceBuildStageStartupScript"
+            ]
+        },
+        "codersdk.WorkspaceBuildTiming": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "ended_at": {
+                    "description": "EndedAt is null while the stage is in progress.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "stage": {
+                    "enum": [
+                        "queued",
+                        "init",
+                        "plan",
+                        "apply",
+                        "agent_connect",
+                        "startup_script"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuildStage"
+                        }
+                    ]
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.WorkspaceConnectionLatencyMS": {
             "type": "object",
             "properties": {
@@ -9315,6 +12342,42 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspacePortShare": {
+            "type": "object",
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is nil if the share never expires.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "share_level": {
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppSharingLevel"
+                        }
+                    ]
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceQuota": {
             "type": "object",
             "properties": {
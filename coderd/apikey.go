@@ -60,9 +60,32 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	scope := database.APIKeyScopeAll
-	if scope != "" {
+	if createToken.Scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
+	if len(createToken.Scopes) > 0 {
+		if createToken.Scope != "" && scope != database.APIKeyScopeCustom {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Scope entries cannot be combined with the %q scope.", scope),
+			})
+			return
+		}
+		scope = database.APIKeyScopeCustom
+	}
+	if scope == database.APIKeyScopeCustom {
+		_, err := rbac.CustomScope(createToken.Scopes).Expand()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid scope entries.",
+				Detail:  err.Error(),
+				Validations: []codersdk.ValidationError{{
+					Field:  "scopes",
+					Detail: err.Error(),
+				}},
+			})
+			return
+		}
+	}
 
 	// default lifetime is 30 days
 	lifeTime := 30 * 24 * time.Hour
@@ -85,11 +108,13 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, _ = api.serviceAccountOwnerContext(r, user)
 	cookie, key, err := api.createAPIKey(ctx, createAPIKeyParams{
 		UserID:          user.ID,
 		LoginType:       database.LoginTypeToken,
 		ExpiresAt:       database.Now().Add(lifeTime),
 		Scope:           scope,
+		CustomScopes:    createToken.Scopes,
 		LifetimeSeconds: int64(lifeTime.Seconds()),
 		TokenName:       tokenName,
 	})
@@ -128,6 +153,13 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user := httpmw.UserParam(r)
 
+	if user.LoginType == database.LoginTypeNone {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Service accounts only authenticate with tokens.",
+		})
+		return
+	}
+
 	lifeTime := time.Hour * 24 * 7
 	cookie, _, err := api.createAPIKey(ctx, createAPIKeyParams{
 		UserID:     user.ID,
@@ -163,7 +195,7 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} codersdk.APIKey
 // @Router /users/{user}/keys/{keyid} [get]
 func (api *API) apiKeyByID(rw http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx, _ := api.serviceAccountOwnerContext(r, httpmw.UserParam(r))
 
 	keyID := chi.URLParam(r, "keyid")
 	key, err := api.Database.GetAPIKeyByID(ctx, keyID)
@@ -198,6 +230,7 @@ func (api *API) apiKeyByName(rw http.ResponseWriter, r *http.Request) {
 		tokenName = chi.URLParam(r, "keyname")
 	)
 
+	ctx, _ = api.serviceAccountOwnerContext(r, user)
 	token, err := api.Database.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
 		TokenName: tokenName,
 		UserID:    user.ID,
@@ -233,6 +266,7 @@ func (api *API) tokens(rw http.ResponseWriter, r *http.Request) {
 		err           error
 		queryStr      = r.URL.Query().Get("include_all")
 		includeAll, _ = strconv.ParseBool(queryStr)
+		owned         bool
 	)
 
 	if includeAll {
@@ -247,6 +281,7 @@ func (api *API) tokens(rw http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		// get user's tokens only
+		ctx, owned = api.serviceAccountOwnerContext(r, user)
 		keys, err = api.Database.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{LoginType: database.LoginTypeToken, UserID: user.ID})
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -257,13 +292,17 @@ func (api *API) tokens(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	keys, err = AuthorizeFilter(api.HTTPAuth, r, rbac.ActionRead, keys)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching keys.",
-			Detail:  err.Error(),
-		})
-		return
+	// The tokens of service accounts were fetched as the service account
+	// for its owners.
+	if !owned {
+		keys, err = AuthorizeFilter(api.HTTPAuth, r, rbac.ActionRead, keys)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching keys.",
+				Detail:  err.Error(),
+			})
+			return
+		}
 	}
 
 	var userIds []uuid.UUID
@@ -305,7 +344,7 @@ func (api *API) tokens(rw http.ResponseWriter, r *http.Request) {
 // @Router /users/{user}/keys/{keyid} [delete]
 func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 	var (
-		ctx               = r.Context()
+		ctx, _            = api.serviceAccountOwnerContext(r, httpmw.UserParam(r))
 		keyID             = chi.URLParam(r, "keyid")
 		auditor           = api.Auditor.Load()
 		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
@@ -385,7 +424,9 @@ type createAPIKeyParams struct {
 	ExpiresAt       time.Time
 	LifetimeSeconds int64
 	Scope           database.APIKeyScope
-	TokenName       string
+	// CustomScopes are required by the custom scope.
+	CustomScopes []string
+	TokenName    string
 }
 
 func (api *API) validateAPIKeyLifetime(lifetime time.Duration) error {
@@ -434,8 +475,15 @@ func (api *API) createAPIKey(ctx context.Context, params createAPIKeyParams) (*h
 	if params.Scope != "" {
 		scope = params.Scope
 	}
+	customScopes := []string{}
 	switch scope {
 	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+	case database.APIKeyScopeCustom:
+		_, err := rbac.CustomScope(params.CustomScopes).Expand()
+		if err != nil {
+			return nil, nil, xerrors.Errorf("invalid custom scope: %w", err)
+		}
+		customScopes = params.CustomScopes
 	default:
 		return nil, nil, xerrors.Errorf("invalid API key scope: %q", scope)
 	}
@@ -459,6 +507,7 @@ func (api *API) createAPIKey(ctx context.Context, params createAPIKeyParams) (*h
 		LoginType:    params.LoginType,
 		Scope:        scope,
 		TokenName:    params.TokenName,
+		CustomScopes: customScopes,
 	})
 	if err != nil {
 		return nil, nil, xerrors.Errorf("insert API key: %w", err)
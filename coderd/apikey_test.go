@@ -84,6 +84,197 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenCustomScoped(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		Scopes: []string{"user:read", "organization:read:" + owner.OrganizationID.String()},
+	})
+	require.NoError(t, err)
+
+	keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, codersdk.APIKeyScopeCustom, keys[0].Scope)
+	require.Equal(t, []string{"user:read", "organization:read:" + owner.OrganizationID.String()}, keys[0].Scopes)
+
+	scoped := codersdk.New(client.URL)
+	scoped.SetSessionToken(res.Key)
+
+	_, err = scoped.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	_, err = scoped.Organization(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	// The scope does not allow reading the deployment config.
+	_, err = scoped.DeploymentConfig(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes: []string{"notaresource:read"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:  codersdk.APIKeyScopeAll,
+			Scopes: []string{"user:read"},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+// Starting and stopping a workspace creates a workspace build, which is
+// authorized as an update of the workspace. There are no separate start and
+// stop actions for scopes.
+func TestTokenCustomScopedWorkspaceBuilds(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	scopedClient := func(scopes ...string) *codersdk.Client {
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes: scopes,
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+		return scoped
+	}
+
+	// Without workspace:update, the workspace can be read but not stopped.
+	readOnly := scopedClient("workspace:read:"+workspace.ID.String(), "template:read", "user:read")
+	_, err := readOnly.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	_, err = readOnly.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	// Unauthorized builds are reported as not found.
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	update := scopedClient(
+		"workspace:read:"+workspace.ID.String(),
+		"workspace:update:"+workspace.ID.String(),
+		"template:read",
+		"user:read",
+	)
+	for _, transition := range []codersdk.WorkspaceTransition{codersdk.WorkspaceTransitionStop, codersdk.WorkspaceTransitionStart} {
+		build, err := update.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: transition,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+	}
+	// Deleting a workspace requires workspace:delete.
+	_, err = update.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestTokenStartStopScopedWorkspaceBuilds(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		Scopes: []string{
+			"workspace:read:" + workspace.ID.String(),
+			"workspace:start:" + workspace.ID.String(),
+			"workspace:stop:" + workspace.ID.String(),
+			"template:read",
+			"user:read",
+		},
+	})
+	require.NoError(t, err)
+	startStop := codersdk.New(client.URL)
+	startStop.SetSessionToken(res.Key)
+
+	for _, transition := range []codersdk.WorkspaceTransition{codersdk.WorkspaceTransitionStop, codersdk.WorkspaceTransitionStart} {
+		build, err := startStop.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: transition,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+	}
+
+	// The token can't delete the workspace.
+	_, err = startStop.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Builds can't change the workspace either.
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+	_, err = startStop.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition:        codersdk.WorkspaceTransitionStart,
+		TemplateVersionID: newVersion.ID,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	_, err = startStop.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition:          codersdk.WorkspaceTransitionStart,
+		RichParameterValues: []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Nor can it call the other endpoints that update the workspace.
+	err = startStop.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{
+		Name: "renamed",
+	})
+	require.Error(t, err)
+	ttl := int64(3 * time.Hour / time.Millisecond)
+	err = startStop.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+		TTLMillis: &ttl,
+	})
+	require.Error(t, err)
+	err = startStop.UpdateWorkspaceAutostart(ctx, workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{})
+	require.Error(t, err)
+
+	updated, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, workspace.Name, updated.Name)
+	require.Equal(t, workspace.TTLMillis, updated.TTLMillis)
+	require.Equal(t, workspace.AutostartSchedule, updated.AutostartSchedule)
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
@@ -176,13 +176,14 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 
 	if dblog.UserUsername.Valid {
 		user = &codersdk.User{
-			ID:        dblog.UserID,
-			Username:  dblog.UserUsername.String,
-			Email:     dblog.UserEmail.String,
-			CreatedAt: dblog.UserCreatedAt.Time,
-			Status:    codersdk.UserStatus(dblog.UserStatus.UserStatus),
-			Roles:     []codersdk.Role{},
-			AvatarURL: dblog.UserAvatarUrl.String,
+			ID:             dblog.UserID,
+			Username:       dblog.UserUsername.String,
+			Email:          dblog.UserEmail.String,
+			CreatedAt:      dblog.UserCreatedAt.Time,
+			Status:         codersdk.UserStatus(dblog.UserStatus.UserStatus),
+			Roles:          []codersdk.Role{},
+			AvatarURL:      dblog.UserAvatarUrl.String,
+			ServiceAccount: dblog.UserLoginType.LoginType == database.LoginTypeNone,
 		}
 
 		for _, roleName := range dblog.UserRoles {
@@ -274,19 +274,25 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 		return xerrors.Errorf("fetch prior workspace build parameters: %w", err)
 	}
 
+	hasMatchingDaemon, err := provisionerdserver.HasMatchingDaemon(ctx, store, template.Provisioner, priorJob.Tags, now)
+	if err != nil {
+		return xerrors.Errorf("check provisioner daemons: %w", err)
+	}
+
 	return store.InTx(func(db database.Store) error {
 		newProvisionerJob, err := store.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
-			ID:             provisionerJobID,
-			CreatedAt:      now,
-			UpdatedAt:      now,
-			InitiatorID:    workspace.OwnerID,
-			OrganizationID: template.OrganizationID,
-			Provisioner:    template.Provisioner,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			StorageMethod:  priorJob.StorageMethod,
-			FileID:         priorJob.FileID,
-			Tags:           priorJob.Tags,
-			Input:          input,
+			ID:                provisionerJobID,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+			InitiatorID:       workspace.OwnerID,
+			OrganizationID:    template.OrganizationID,
+			Provisioner:       template.Provisioner,
+			Type:              database.ProvisionerJobTypeWorkspaceBuild,
+			StorageMethod:     priorJob.StorageMethod,
+			FileID:            priorJob.FileID,
+			Tags:              priorJob.Tags,
+			Input:             input,
+			HasMatchingDaemon: hasMatchingDaemon,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
@@ -44,16 +44,19 @@ import (
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/database/dbtype"
+	"github.com/coder/coder/coderd/derphealth"
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/coderd/gitsshkey"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/importpolicy"
 	"github.com/coder/coder/coderd/metricscache"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/tracing"
+	"github.com/coder/coder/coderd/twofactor"
 	"github.com/coder/coder/coderd/updatecheck"
 	"github.com/coder/coder/coderd/util/slice"
 	"github.com/coder/coder/coderd/workspaceapps"
@@ -104,6 +107,8 @@ type Options struct {
 	GoogleTokenValidator           *idtoken.Validator
 	GithubOAuth2Config             *GithubOAuth2Config
 	OIDCConfig                     *OIDCConfig
+	SAMLConfig                     *SAMLConfig
+	LDAPConfig                     *LDAPConfig
 	PrometheusRegistry             *prometheus.Registry
 	SecureAuthCookie               bool
 	StrictTransportSecurityCfg     httpmw.HSTSConfig
@@ -121,6 +126,9 @@ type Options struct {
 	SwaggerEndpoint       bool
 	SetUserGroups         func(ctx context.Context, tx database.Store, userID uuid.UUID, groupNames []string) error
 	TemplateScheduleStore schedule.TemplateScheduleStore
+	// TemplateImportPolicy is evaluated against the Terraform plans of
+	// template version imports. Violations fail the import.
+	TemplateImportPolicy *importpolicy.Policy
 	// AppSigningKey denotes the symmetric key to use for signing app tickets.
 	// The key must be 64 bytes long.
 	AppSigningKey []byte
@@ -134,6 +142,8 @@ type Options struct {
 
 	MetricsCacheRefreshInterval time.Duration
 	AgentStatsRefreshInterval   time.Duration
+	AppAccessLogFlushInterval   time.Duration
+	DERPHealthCheckInterval     time.Duration // Set non-zero to probe DERP regions.
 	DeploymentValues            *codersdk.DeploymentValues
 	UpdateCheckOptions          *updatecheck.Options // Set non-nil to enable update checking.
 
@@ -197,6 +207,9 @@ func New(options *Options) *API {
 	if options.MetricsCacheRefreshInterval == 0 {
 		options.MetricsCacheRefreshInterval = time.Hour
 	}
+	if options.AppAccessLogFlushInterval == 0 {
+		options.AppAccessLogFlushInterval = 5 * time.Second
+	}
 	if options.APIRateLimit == 0 {
 		options.APIRateLimit = 512
 	}
@@ -265,6 +278,22 @@ func New(options *Options) *API {
 		OIDC:   options.OIDCConfig,
 	}
 
+	// The limits of shared workspace apps are disabled along with all other
+	// rate limits.
+	var appsLimiterOptions workspaceapps.LimiterOptions
+	if !options.DeploymentValues.RateLimit.DisableAll {
+		appsLimiterOptions = workspaceapps.LimiterOptions{
+			App: workspaceapps.Limits{
+				RequestsPerMinute:       options.DeploymentValues.RateLimit.SharedApp.Value(),
+				BandwidthBytesPerSecond: options.DeploymentValues.RateLimit.SharedAppBandwidth.Value(),
+			},
+			Workspace: workspaceapps.Limits{
+				RequestsPerMinute:       options.DeploymentValues.RateLimit.SharedWorkspace.Value(),
+				BandwidthBytesPerSecond: options.DeploymentValues.RateLimit.SharedWorkspaceBandwidth.Value(),
+			},
+		}
+	}
+
 	r := chi.NewRouter()
 	ctx, cancel := context.WithCancel(context.Background())
 	api := &API{
@@ -289,10 +318,39 @@ func New(options *Options) *API {
 			options.AgentInactiveDisconnectTimeout,
 			options.AppSigningKey,
 		),
+		workspaceAppsLimiter:  workspaceapps.NewLimiter(options.PrometheusRegistry, appsLimiterOptions),
+		BuildStageMetrics:     provisionerdserver.NewBuildStageMetrics(options.PrometheusRegistry),
+		appAccessLogs:         newAppAccessLogBatcher(options.Database, options.Logger.Named("app_access_logs"), options.AppAccessLogFlushInterval),
 		metricsCache:          metricsCache,
 		Auditor:               atomic.Pointer[audit.Auditor]{},
 		TemplateScheduleStore: atomic.Pointer[schedule.TemplateScheduleStore]{},
 		Experiments:           experiments,
+		// Security keys are bound to the host of the access URL.
+		webAuthn: twofactor.RelyingParty{
+			ID:     options.AccessURL.Hostname(),
+			Origin: options.AccessURL.Scheme + "://" + options.AccessURL.Host,
+		},
+	}
+	if options.DERPHealthCheckInterval > 0 {
+		api.derpHealthChecker, err = derphealth.New(
+			options.Logger.Named("derp_health"),
+			options.DERPMap,
+			derphealth.Options{
+				Interval:   options.DERPHealthCheckInterval,
+				Registerer: options.PrometheusRegistry,
+			},
+		)
+		if err != nil {
+			panic(xerrors.Errorf("create derp health checker: %w", err))
+		}
+	}
+	if options.LDAPConfig != nil && options.LDAPConfig.SyncInterval > 0 {
+		api.ldapSyncDone = make(chan struct{})
+		go api.syncLDAP(ctx, options.LDAPConfig.SyncInterval)
+	}
+	if options.OIDCConfig != nil && options.OIDCConfig.RefreshInterval > 0 {
+		api.oidcRefreshDone = make(chan struct{})
+		go api.refreshOIDC(ctx, options.OIDCConfig.RefreshInterval)
 	}
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
@@ -327,6 +385,17 @@ func New(options *Options) *API {
 		DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
 		Optional:                    false,
 	})
+	if options.DeploymentValues.TwoFactorRequired.Value() {
+		// Sessions of password users without a second factor can only be
+		// used to set one up.
+		extractAPIKey, extractAPIKeyRedirect := apiKeyMiddleware, apiKeyMiddlewareRedirect
+		apiKeyMiddleware = func(next http.Handler) http.Handler {
+			return extractAPIKey(api.requireTwoFactor(next))
+		}
+		apiKeyMiddlewareRedirect = func(next http.Handler) http.Handler {
+			return extractAPIKeyRedirect(api.requireTwoFactor(next))
+		}
+	}
 
 	// API rate limit middleware. The counter is local and not shared between
 	// replicas or instances of this middleware.
@@ -400,6 +469,19 @@ func New(options *Options) *API {
 			})
 		}
 	})
+	// OAuth2 apps send users to the authorization endpoint and exchange
+	// codes on the token endpoint, so both live outside of the API.
+	r.Route("/oauth2", func(r chi.Router) {
+		r.Route("/authorize", func(r chi.Router) {
+			r.Use(apiKeyMiddlewareRedirect)
+			r.Get("/", api.getOAuth2ProviderAppAuthorize)
+			r.Post("/", api.postOAuth2ProviderAppAuthorize)
+		})
+		r.Route("/tokens", func(r chi.Router) {
+			r.Use(apiRateLimiter)
+			r.Post("/", api.postOAuth2ProviderAppToken)
+		})
+	})
 	r.Route("/api/v2", func(r chi.Router) {
 		api.APIHandler = r
 
@@ -425,6 +507,10 @@ func New(options *Options) *API {
 			r.Get("/", api.handleExperimentsGet)
 		})
 		r.Get("/updatecheck", api.updateCheck)
+		r.Route("/derp-map", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.derpMap)
+		})
 		r.Route("/audit", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -477,6 +563,28 @@ func New(options *Options) *API {
 				})
 			})
 		})
+		r.Route("/oauth2-provider", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Route("/apps", func(r chi.Router) {
+				r.Get("/", api.oAuth2ProviderApps)
+				r.Post("/", api.postOAuth2ProviderApp)
+				r.Route("/{app}", func(r chi.Router) {
+					r.Use(httpmw.ExtractOAuth2ProviderAppParam(options.Database))
+					r.Get("/", api.oAuth2ProviderApp)
+					r.Put("/", api.putOAuth2ProviderApp)
+					r.Delete("/", api.deleteOAuth2ProviderApp)
+					r.Delete("/tokens", api.deleteOAuth2ProviderAppTokens)
+					r.Route("/secrets", func(r chi.Router) {
+						r.Get("/", api.oAuth2ProviderAppSecrets)
+						r.Post("/", api.postOAuth2ProviderAppSecret)
+						r.Route("/{secretID}", func(r chi.Router) {
+							r.Use(httpmw.ExtractOAuth2ProviderAppSecretParam(options.Database))
+							r.Delete("/", api.deleteOAuth2ProviderAppSecret)
+						})
+					})
+				})
+			})
+		})
 		r.Route("/parameters/{scope}/{id}", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.postParameter)
@@ -485,6 +593,10 @@ func New(options *Options) *API {
 				r.Delete("/", api.deleteParameter)
 			})
 		})
+		r.Route("/provisionerdaemons", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.provisionerDaemons)
+		})
 		r.Route("/templates/{template}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -494,6 +606,16 @@ func New(options *Options) *API {
 			r.Get("/", api.template)
 			r.Delete("/", api.deleteTemplate)
 			r.Patch("/", api.patchTemplateMeta)
+			r.Route("/app-limits", func(r chi.Router) {
+				r.Get("/", api.templateAppLimits)
+				r.Put("/", api.putTemplateAppLimits)
+				r.Delete("/", api.deleteTemplateAppLimits)
+			})
+			r.Route("/app-routing", func(r chi.Router) {
+				r.Get("/", api.templateAppRouting)
+				r.Put("/{slug}", api.putTemplateAppRouting)
+				r.Delete("/{slug}", api.deleteTemplateAppRouting)
+			})
 			r.Route("/versions", func(r chi.Router) {
 				r.Get("/", api.templateVersionsByTemplate)
 				r.Patch("/", api.patchActiveTemplateVersion)
@@ -527,6 +649,7 @@ func New(options *Options) *API {
 			r.Get("/first", api.firstUser)
 			r.Post("/first", api.postFirstUser)
 			r.Get("/authmethods", api.userAuthMethods)
+			r.Get("/saml/metadata", api.userSAMLMetadata)
 			r.Group(func(r chi.Router) {
 				// We use a tight limit for password login to protect against
 				// audit-log write DoS, pbkdf2 DoS, and simple brute-force
@@ -535,6 +658,7 @@ func New(options *Options) *API {
 				// This value is intentionally increased during tests.
 				r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
 				r.Post("/login", api.postLogin)
+				r.Post("/login/webauthn", api.postLoginWebAuthn)
 				r.Route("/oauth2", func(r chi.Router) {
 					r.Route("/github", func(r chi.Router) {
 						r.Use(httpmw.ExtractOAuth2(options.GithubOAuth2Config, options.HTTPClient, nil))
@@ -545,6 +669,10 @@ func New(options *Options) *API {
 					r.Use(httpmw.ExtractOAuth2(options.OIDCConfig, options.HTTPClient, oidcAuthURLParams))
 					r.Get("/", api.userOIDC)
 				})
+				r.Route("/saml", func(r chi.Router) {
+					r.Get("/login", api.userSAMLLogin)
+					r.Post("/acs", api.userSAMLACS)
+				})
 			})
 			r.Group(func(r chi.Router) {
 				r.Use(
@@ -569,6 +697,20 @@ func New(options *Options) *API {
 					r.Route("/password", func(r chi.Router) {
 						r.Put("/", api.putUserPassword)
 					})
+					r.Route("/two-factor", func(r chi.Router) {
+						r.Get("/", api.twoFactor)
+						r.Delete("/", api.resetTwoFactor)
+						r.Route("/totp", func(r chi.Router) {
+							r.Post("/", api.postTOTPKey)
+							r.Put("/", api.putTOTPKey)
+							r.Delete("/", api.deleteTOTPKey)
+						})
+						r.Route("/webauthn", func(r chi.Router) {
+							r.Post("/", api.postWebAuthnCredential)
+							r.Post("/challenge", api.postWebAuthnChallenge)
+							r.Delete("/{credential}", api.deleteWebAuthnCredential)
+						})
+					})
 					// These roles apply to the site wide permissions.
 					r.Put("/roles", api.putUserRoles)
 					r.Get("/roles", api.userRoles)
@@ -599,6 +741,7 @@ func New(options *Options) *API {
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Get("/service-account", api.serviceAccount)
 				})
 			})
 		})
@@ -616,8 +759,13 @@ func New(options *Options) *API {
 				r.Patch("/startup-logs", api.patchWorkspaceAgentStartupLogs)
 				r.Post("/app-health", api.postWorkspaceAppHealth)
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
+				r.Get("/gitauth/device", api.workspaceAgentsGitAuthDevice)
+				r.Post("/gitauth/device", api.workspaceAgentsGitAuthDeviceExchange)
+				r.Get("/gitauth/{gitauth}/access-token", api.workspaceAgentsGitAuthAccessToken)
 				r.Get("/gitsshkey", api.agentGitSSHKey)
 				r.Get("/coordinate", api.workspaceAgentCoordinate)
+				r.Get("/peers", api.workspaceAgentPeers)
+				r.Get("/peers/{workspaceagent}/coordinate", api.workspaceAgentPeerCoordinate)
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadata)
@@ -661,8 +809,15 @@ func New(options *Options) *API {
 				r.Route("/ttl", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceTTL)
 				})
+				r.Put("/peering", api.putWorkspacePeering)
 				r.Get("/watch", api.watchWorkspace)
 				r.Put("/extend", api.putExtendWorkspace)
+				r.Route("/port-shares", func(r chi.Router) {
+					r.Get("/", api.workspacePortShares)
+					r.Put("/", api.putWorkspacePortShare)
+					r.Delete("/", api.deleteWorkspacePortShare)
+				})
+				r.Get("/app-access-logs", api.workspaceAppAccessLogs)
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -718,6 +873,7 @@ func New(options *Options) *API {
 			)
 
 			r.Get("/coordinator", api.debugCoordinator)
+			r.Get("/derp", api.debugDERP)
 		})
 	})
 
@@ -751,6 +907,8 @@ type API struct {
 	TailnetCoordinator                atomic.Pointer[tailnet.Coordinator]
 	QuotaCommitter                    atomic.Pointer[proto.QuotaCommitter]
 	TemplateScheduleStore             atomic.Pointer[schedule.TemplateScheduleStore]
+	// BuildStageMetrics observes the stages of workspace builds as they end.
+	BuildStageMetrics *provisionerdserver.BuildStageMetrics
 
 	HTTPAuth *HTTPAuthorizer
 
@@ -768,7 +926,13 @@ type API struct {
 	metricsCache          *metricscache.Cache
 	workspaceAgentCache   *wsconncache.Cache
 	updateChecker         *updatecheck.Checker
+	derpHealthChecker     *derphealth.Checker
+	ldapSyncDone          chan struct{}
+	oidcRefreshDone       chan struct{}
 	WorkspaceAppsProvider *workspaceapps.Provider
+	workspaceAppsLimiter  *workspaceapps.Limiter
+	appAccessLogs         *appAccessLogBatcher
+	webAuthn              twofactor.RelyingParty
 
 	// Experiments contains the list of experiments currently enabled.
 	// This is used to gate features that are not yet ready for production.
@@ -784,7 +948,17 @@ func (api *API) Close() error {
 	api.WebsocketWaitGroup.Wait()
 	api.WebsocketWaitMutex.Unlock()
 
+	api.appAccessLogs.Close()
 	api.metricsCache.Close()
+	if api.derpHealthChecker != nil {
+		_ = api.derpHealthChecker.Close()
+	}
+	if api.ldapSyncDone != nil {
+		<-api.ldapSyncDone
+	}
+	if api.oidcRefreshDone != nil {
+		<-api.oidcRefreshDone
+	}
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
@@ -861,6 +1035,8 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 		QuotaCommitter:        &api.QuotaCommitter,
 		Auditor:               &api.Auditor,
 		TemplateScheduleStore: &api.TemplateScheduleStore,
+		ImportPolicy:          api.TemplateImportPolicy,
+		BuildStageMetrics:     api.BuildStageMetrics,
 		AcquireJobDebounce:    debounce,
 		Logger:                api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
 	})
@@ -62,7 +62,7 @@ func AssertRBAC(t *testing.T, api *coderd.API, client *codersdk.Client) RBACAsse
 			ID:     key.UserID.String(),
 			Roles:  rbac.RoleNames(roles.Roles),
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  key.RBACScope(),
 		},
 		Recorder: recorder,
 	}
@@ -432,6 +432,9 @@ func randomRBACType() string {
 		rbac.ResourceDeploymentValues.Type,
 		rbac.ResourceReplicas.Type,
 		rbac.ResourceDebugInfo.Type,
+		rbac.ResourceOAuth2ProviderApp.Type,
+		rbac.ResourceOAuth2ProviderAppSecret.Type,
+		rbac.ResourceOAuth2ProviderAppCodeToken.Type,
 	}
 	return all[must(cryptorand.Intn(len(all)))]
 }
@@ -26,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -95,6 +96,8 @@ type Options struct {
 	GithubOAuth2Config    *coderd.GithubOAuth2Config
 	RealIPConfig          *httpmw.RealIPConfig
 	OIDCConfig            *coderd.OIDCConfig
+	SAMLConfig            *coderd.SAMLConfig
+	LDAPConfig            *coderd.LDAPConfig
 	GoogleTokenValidator  *idtoken.Validator
 	SSHKeygenAlgorithm    gitsshkey.Algorithm
 	AutobuildTicker       <-chan time.Time
@@ -114,6 +117,8 @@ type Options struct {
 	IncludeProvisionerDaemon    bool
 	MetricsCacheRefreshInterval time.Duration
 	AgentStatsRefreshInterval   time.Duration
+	AppAccessLogFlushInterval   time.Duration
+	DERPHealthCheckInterval     time.Duration
 	DeploymentValues            *codersdk.DeploymentValues
 
 	// Set update check options to enable update check.
@@ -208,6 +213,10 @@ func NewOptions(t *testing.T, options *Options) (func(http.Handler), context.Can
 	if options.FilesRateLimit == 0 {
 		options.FilesRateLimit = -1
 	}
+	// Insert access logs quickly so tests don't wait on them.
+	if options.AppAccessLogFlushInterval == 0 {
+		options.AppAccessLogFlushInterval = 100 * time.Millisecond
+	}
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	lifecycleExecutor := executor.New(
@@ -298,6 +307,8 @@ func NewOptions(t *testing.T, options *Options) (func(http.Handler), context.Can
 			GithubOAuth2Config:    options.GithubOAuth2Config,
 			RealIPConfig:          options.RealIPConfig,
 			OIDCConfig:            options.OIDCConfig,
+			SAMLConfig:            options.SAMLConfig,
+			LDAPConfig:            options.LDAPConfig,
 			GoogleTokenValidator:  options.GoogleTokenValidator,
 			SSHKeygenAlgorithm:    options.SSHKeygenAlgorithm,
 			DERPServer:            derpServer,
@@ -330,6 +341,8 @@ func NewOptions(t *testing.T, options *Options) (func(http.Handler), context.Can
 			},
 			MetricsCacheRefreshInterval: options.MetricsCacheRefreshInterval,
 			AgentStatsRefreshInterval:   options.AgentStatsRefreshInterval,
+			AppAccessLogFlushInterval:   options.AppAccessLogFlushInterval,
+			DERPHealthCheckInterval:     options.DERPHealthCheckInterval,
 			DeploymentValues:            options.DeploymentValues,
 			UpdateCheckOptions:          options.UpdateCheckOptions,
 			SwaggerEndpoint:             options.SwaggerEndpoint,
@@ -398,6 +411,21 @@ func NewProvisionerDaemon(t *testing.T, coderAPI *coderd.API) io.Closer {
 	t.Cleanup(func() {
 		_ = closer.Close()
 	})
+	// Wait for the first heartbeat so jobs created by the test are matched
+	// to an online daemon.
+	require.Eventually(t, func() bool {
+		// nolint:gocritic // Test helper reading daemon state.
+		daemons, err := coderAPI.Database.GetProvisionerDaemons(dbauthz.AsSystemRestricted(ctx))
+		if err != nil || len(daemons) == 0 {
+			return false
+		}
+		for _, daemon := range daemons {
+			if !daemon.LastSeenAt.Valid {
+				return false
+			}
+		}
+		return true
+	}, testutil.WaitShort, testutil.IntervalFast)
 	return closer
 }
 
@@ -894,6 +922,14 @@ func NewAWSInstanceIdentity(t *testing.T, instanceID string) (awsidentity.Certif
 type OIDCConfig struct {
 	key    *rsa.PrivateKey
 	issuer string
+	// revoked makes the provider reject all refresh tokens.
+	revoked atomic.Bool
+	// rotate makes the provider issue a new refresh token on every refresh
+	// and reject all others.
+	rotate       atomic.Bool
+	mutex        sync.Mutex
+	refreshToken string
+	rotations    int
 }
 
 func NewOIDCConfig(t *testing.T, issuer string) *OIDCConfig {
@@ -908,8 +944,9 @@ func NewOIDCConfig(t *testing.T, issuer string) *OIDCConfig {
 	}
 
 	return &OIDCConfig{
-		key:    pkey,
-		issuer: issuer,
+		key:          pkey,
+		issuer:       issuer,
+		refreshToken: "refresh",
 	}
 }
 
@@ -917,8 +954,57 @@ func (*OIDCConfig) AuthCodeURL(state string, _ ...oauth2.AuthCodeOption) string
 	return "/?state=" + url.QueryEscape(state)
 }
 
-func (*OIDCConfig) TokenSource(context.Context, *oauth2.Token) oauth2.TokenSource {
-	return nil
+func (o *OIDCConfig) TokenSource(_ context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return testutil.OAuth2TokenSource(func() (*oauth2.Token, error) {
+		invalidGrant := &oauth2.RetrieveError{
+			Response: &http.Response{
+				Status:     "400 Bad Request",
+				StatusCode: http.StatusBadRequest,
+			},
+			Body: []byte(`{"error":"invalid_grant"}`),
+		}
+		if o.revoked.Load() {
+			return nil, invalidGrant
+		}
+		refreshToken := "refresh"
+		if o.rotate.Load() {
+			// Responding slowly makes concurrent refreshes overlap.
+			time.Sleep(testutil.IntervalFast)
+			o.mutex.Lock()
+			defer o.mutex.Unlock()
+			if token.RefreshToken != o.refreshToken {
+				return nil, invalidGrant
+			}
+			o.rotations++
+			o.refreshToken = fmt.Sprintf("refresh-%d", o.rotations)
+			refreshToken = o.refreshToken
+		}
+		return &oauth2.Token{
+			AccessToken:  "token",
+			RefreshToken: refreshToken,
+			Expiry:       time.Now().Add(time.Hour),
+		}, nil
+	})
+}
+
+// RevokeRefreshTokens makes the provider reject the refresh tokens of all
+// users.
+func (o *OIDCConfig) RevokeRefreshTokens() {
+	o.revoked.Store(true)
+}
+
+// RotateRefreshTokens makes the provider slowly replace the refresh token on
+// every refresh, and reject every refresh token but the latest. Only a single
+// chain of refresh tokens is tracked, so it must be used with one user.
+func (o *OIDCConfig) RotateRefreshTokens() {
+	o.rotate.Store(true)
+}
+
+// RefreshTokenRotations returns how often the refresh token was rotated.
+func (o *OIDCConfig) RefreshTokenRotations() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.rotations
 }
 
 func (*OIDCConfig) Exchange(_ context.Context, code string, _ ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
@@ -927,7 +1013,8 @@ func (*OIDCConfig) Exchange(_ context.Context, code string, _ ...oauth2.AuthCode
 		return nil, xerrors.Errorf("decode code: %w", err)
 	}
 	return (&oauth2.Token{
-		AccessToken: "token",
+		AccessToken:  "token",
+		RefreshToken: "refresh",
 	}).WithExtra(map[string]interface{}{
 		"id_token": string(token),
 	}), nil
@@ -169,17 +169,19 @@ var (
 				Name:        "system",
 				DisplayName: "Coder",
 				Site: rbac.Permissions(map[string][]rbac.Action{
-					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
-					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate},
-					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
-					rbac.ResourceOrganization.Type:       {rbac.ActionCreate},
-					rbac.ResourceOrganizationMember.Type: {rbac.ActionCreate},
-					rbac.ResourceOrgRoleAssignment.Type:  {rbac.ActionCreate},
-					rbac.ResourceUser.Type:               {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceUserData.Type:           {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceWorkspace.Type:          {rbac.ActionUpdate},
+					rbac.ResourceWildcard.Type:                   {rbac.ActionRead},
+					rbac.ResourceAPIKey.Type:                     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceGroup.Type:                      {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceOAuth2ProviderAppSecret.Type:    {rbac.ActionUpdate},
+					rbac.ResourceOAuth2ProviderAppCodeToken.Type: {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceRoleAssignment.Type:             {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceSystem.Type:                     {rbac.WildcardSymbol},
+					rbac.ResourceOrganization.Type:               {rbac.ActionCreate},
+					rbac.ResourceOrganizationMember.Type:         {rbac.ActionCreate},
+					rbac.ResourceOrgRoleAssignment.Type:          {rbac.ActionCreate},
+					rbac.ResourceUser.Type:                       {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceUserData.Type:                   {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceWorkspace.Type:                  {rbac.ActionUpdate},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
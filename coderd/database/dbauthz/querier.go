@@ -1212,6 +1212,249 @@ func (q *querier) UpdateUserLink(ctx context.Context, arg database.UpdateUserLin
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserLink)(ctx, arg)
 }
 
+func (q *querier) GetUserTOTP(ctx context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	return fetch(q.log, q.auth, q.db.GetUserTOTP)(ctx, userID)
+}
+
+func (q *querier) UpsertUserTOTP(ctx context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID))
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.UpsertUserTOTP(ctx, arg)
+}
+
+func (q *querier) UpdateUserTOTPLastUsedStep(ctx context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (database.UserTOTP, error) {
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID))
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.UpdateUserTOTPLastUsedStep(ctx, arg)
+}
+
+func (q *querier) UpdateUserTOTPRemoveRecoveryCode(ctx context.Context, arg database.UpdateUserTOTPRemoveRecoveryCodeParams) (database.UserTOTP, error) {
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID))
+	if err != nil {
+		return database.UserTOTP{}, err
+	}
+	return q.db.UpdateUserTOTPRemoveRecoveryCode(ctx, arg)
+}
+
+func (q *querier) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error {
+	err := q.authorizeTwoFactorReset(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteUserTOTP(ctx, userID)
+}
+
+func (q *querier) GetUserWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (database.UserWebAuthnCredential, error) {
+	return fetch(q.log, q.auth, q.db.GetUserWebAuthnCredentialByID)(ctx, id)
+}
+
+func (q *querier) GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserWebAuthnCredential, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetUserWebAuthnCredentialsByUserID)(ctx, userID)
+}
+
+func (q *querier) InsertUserWebAuthnCredential(ctx context.Context, arg database.InsertUserWebAuthnCredentialParams) (database.UserWebAuthnCredential, error) {
+	return insert(q.log, q.auth, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID), q.db.InsertUserWebAuthnCredential)(ctx, arg)
+}
+
+func (q *querier) UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg database.UpdateUserWebAuthnCredentialSignCountParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateUserWebAuthnCredentialSignCountParams) (database.UserWebAuthnCredential, error) {
+		return q.db.GetUserWebAuthnCredentialByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateUserWebAuthnCredentialSignCount)(ctx, arg)
+}
+
+func (q *querier) DeleteUserWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	credential, err := q.db.GetUserWebAuthnCredentialByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = q.authorizeTwoFactorReset(ctx, credential.UserID)
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteUserWebAuthnCredential(ctx, id)
+}
+
+func (q *querier) InsertUserWebAuthnChallenge(ctx context.Context, arg database.InsertUserWebAuthnChallengeParams) error {
+	err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID))
+	if err != nil {
+		return err
+	}
+	return q.db.InsertUserWebAuthnChallenge(ctx, arg)
+}
+
+func (q *querier) DeleteUserWebAuthnChallenge(ctx context.Context, arg database.DeleteUserWebAuthnChallengeParams) (database.UserWebAuthnChallenge, error) {
+	// Answering a challenge is part of logging in, like using a TOTP code.
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID))
+	if err != nil {
+		return database.UserWebAuthnChallenge{}, err
+	}
+	return q.db.DeleteUserWebAuthnChallenge(ctx, arg)
+}
+
+// authorizeTwoFactorReset allows users to remove their own second factors, and
+// admins to remove those of users that lost them.
+func (q *querier) authorizeTwoFactorReset(ctx context.Context, userID uuid.UUID) error {
+	user, err := q.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionDelete, user.UserDataRBACObject())
+	if err != nil {
+		err = q.authorizeContext(ctx, rbac.ActionUpdate, user.RBACObject())
+	}
+	return err
+}
+
+func (q *querier) GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (database.ServiceAccount, error) {
+	return fetch(q.log, q.auth, q.db.GetServiceAccountByUserID)(ctx, userID)
+}
+
+func (q *querier) InsertServiceAccount(ctx context.Context, arg database.InsertServiceAccountParams) (database.ServiceAccount, error) {
+	return insert(q.log, q.auth, rbac.ResourceUser, q.db.InsertServiceAccount)(ctx, arg)
+}
+
+func (q *querier) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	fetch := func(ctx context.Context, _ interface{}) ([]database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderApps(ctx)
+	}
+	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
+}
+
+func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(userID.String()))
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetOAuth2ProviderAppsByUserID(ctx, userID)
+}
+
+func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderApp, q.db.InsertOAuth2ProviderApp)(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+		return q.db.GetOAuth2ProviderAppByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppByID)(ctx, arg)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppByID, q.db.DeleteOAuth2ProviderAppByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretByPrefix(ctx context.Context, secretPrefix string) (database.OAuth2ProviderAppSecret, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByPrefix)(ctx, secretPrefix)
+}
+
+func (q *querier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetOAuth2ProviderAppSecretsByAppID)(ctx, appID)
+}
+
+func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppSecret, q.db.InsertOAuth2ProviderAppSecret)(ctx, arg)
+}
+
+func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+		return q.db.GetOAuth2ProviderAppSecretByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOAuth2ProviderAppSecretByID)(ctx, arg)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppSecretByID, q.db.DeleteOAuth2ProviderAppSecretByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix string) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByPrefix)(ctx, secretPrefix)
+}
+
+func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	return insert(q.log, q.auth, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String()), q.db.InsertOAuth2ProviderAppCode)(ctx, arg)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	err := deleteQ(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByID, func(ctx context.Context, id uuid.UUID) error {
+		_, err := q.db.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+		return err
+	})(ctx, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (q *querier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, refreshPrefix string) (database.OAuth2ProviderAppToken, error) {
+	token, err := q.db.GetOAuth2ProviderAppTokenByPrefix(ctx, refreshPrefix)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	// The refresh token belongs to the owner of the API key it refreshes.
+	key, err := q.db.GetAPIKeyByID(ctx, token.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String()))
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return token, nil
+}
+
+func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	key, err := q.db.GetAPIKeyByID(ctx, arg.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String()))
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return q.db.InsertOAuth2ProviderAppToken(ctx, arg)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppTokenByPrefix(ctx context.Context, refreshPrefix string) (database.OAuth2ProviderAppToken, error) {
+	token, err := q.db.GetOAuth2ProviderAppTokenByPrefix(ctx, refreshPrefix)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	key, err := q.db.GetAPIKeyByID(ctx, token.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	err = q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String()))
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return q.db.DeleteOAuth2ProviderAppTokenByPrefix(ctx, refreshPrefix)
+}
+
+func (q *querier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String()))
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, arg)
+}
+
 // UpdateUserRoles updates the site roles of a user. The validation for this function include more than
 // just a basic RBAC check.
 func (q *querier) UpdateUserRoles(ctx context.Context, arg database.UpdateUserRolesParams) (database.User, error) {
@@ -1503,12 +1746,7 @@ func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertW
 		return database.WorkspaceBuild{}, err
 	}
 
-	var action rbac.Action = rbac.ActionUpdate
-	if arg.Transition == database.WorkspaceTransitionDelete {
-		action = rbac.ActionDelete
-	}
-
-	if err = q.authorizeContext(ctx, action, w); err != nil {
+	if err = q.authorizeWorkspaceTransition(ctx, arg.Transition, w); err != nil {
 		return database.WorkspaceBuild{}, err
 	}
 
@@ -1527,7 +1765,7 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 		return err
 	}
 
-	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	err = q.authorizeWorkspaceTransition(ctx, build.Transition, workspace)
 	if err != nil {
 		return err
 	}
@@ -1535,6 +1773,31 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+// authorizeWorkspaceTransition authorizes a build of the workspace. Delete
+// builds require ActionDelete. Start and stop builds are allowed with either
+// ActionUpdate or the action of the transition.
+func (q *querier) authorizeWorkspaceTransition(ctx context.Context, transition database.WorkspaceTransition, workspace database.Workspace) error {
+	var action rbac.Action
+	switch transition {
+	case database.WorkspaceTransitionDelete:
+		return q.authorizeContext(ctx, rbac.ActionDelete, workspace)
+	case database.WorkspaceTransitionStart:
+		action = rbac.ActionStart
+	case database.WorkspaceTransitionStop:
+		action = rbac.ActionStop
+	default:
+		return xerrors.Errorf("unknown workspace transition %q", transition)
+	}
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err == nil {
+		return nil
+	}
+	if q.authorizeContext(ctx, action, workspace) == nil {
+		return nil
+	}
+	return err
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -1674,6 +1937,13 @@ func (q *querier) UpdateWorkspaceTTLToBeWithinTemplateMax(ctx context.Context, a
 	return fetchAndExec(q.log, q.auth, rbac.ActionUpdate, fetch, q.db.UpdateWorkspaceTTLToBeWithinTemplateMax)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspacePeering(ctx context.Context, arg database.UpdateWorkspacePeeringParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspacePeeringParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspacePeering)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -1681,6 +1951,103 @@ func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWor
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceTTL)(ctx, arg)
 }
 
+func (q *querier) GetWorkspacePortShare(ctx context.Context, arg database.GetWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	// If we can fetch the workspace, we can fetch its port shares.
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+	return q.db.GetWorkspacePortShare(ctx, arg)
+}
+
+func (q *querier) GetWorkspacePortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShare, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePortSharesByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceAppAccessLogs(ctx context.Context, arg database.GetWorkspaceAppAccessLogsParams) ([]database.GetWorkspaceAppAccessLogsRow, error) {
+	// If we can fetch the workspace, we can fetch its app access logs.
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAppAccessLogs(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspacePortShare(ctx context.Context, arg database.UpsertWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+	return q.db.UpsertWorkspacePortShare(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspacePortShare(ctx context.Context, arg database.DeleteWorkspacePortShareParams) error {
+	fetch := func(ctx context.Context, arg database.DeleteWorkspacePortShareParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteWorkspacePortShare)(ctx, arg)
+}
+
+func (q *querier) GetTemplateAppLimits(ctx context.Context, templateID uuid.UUID) (database.TemplateAppLimit, error) {
+	// If we can fetch the template, we can fetch the limits of its apps.
+	if _, err := q.GetTemplateByID(ctx, templateID); err != nil {
+		return database.TemplateAppLimit{}, err
+	}
+	return q.db.GetTemplateAppLimits(ctx, templateID)
+}
+
+func (q *querier) UpsertTemplateAppLimits(ctx context.Context, arg database.UpsertTemplateAppLimitsParams) (database.TemplateAppLimit, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateAppLimit{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.TemplateAppLimit{}, err
+	}
+	return q.db.UpsertTemplateAppLimits(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateAppLimits(ctx context.Context, templateID uuid.UUID) error {
+	return update(q.log, q.auth, q.db.GetTemplateByID, q.db.DeleteTemplateAppLimits)(ctx, templateID)
+}
+
+func (q *querier) GetTemplateAppRouting(ctx context.Context, arg database.GetTemplateAppRoutingParams) (database.TemplateAppRouting, error) {
+	// If we can fetch the template, we can fetch the routing of its apps.
+	if _, err := q.GetTemplateByID(ctx, arg.TemplateID); err != nil {
+		return database.TemplateAppRouting{}, err
+	}
+	return q.db.GetTemplateAppRouting(ctx, arg)
+}
+
+func (q *querier) GetTemplateAppRoutingByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateAppRouting, error) {
+	if _, err := q.GetTemplateByID(ctx, templateID); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateAppRoutingByTemplateID(ctx, templateID)
+}
+
+func (q *querier) UpsertTemplateAppRouting(ctx context.Context, arg database.UpsertTemplateAppRoutingParams) (database.TemplateAppRouting, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateAppRouting{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.TemplateAppRouting{}, err
+	}
+	return q.db.UpsertTemplateAppRouting(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateAppRouting(ctx context.Context, arg database.DeleteTemplateAppRoutingParams) error {
+	fetch := func(ctx context.Context, arg database.DeleteTemplateAppRoutingParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.TemplateID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteTemplateAppRouting)(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
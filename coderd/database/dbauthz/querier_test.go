@@ -2,6 +2,7 @@ package dbauthz_test
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtype"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/util/slice"
 )
@@ -347,6 +349,172 @@ func (s *MethodTestSuite) TestLicense() {
 	}))
 }
 
+func (s *MethodTestSuite) TestOAuth2ProviderApps() {
+	s.Run("GetOAuth2ProviderApps", s.Subtest(func(db database.Store, check *expects) {
+		apps := []database.OAuth2ProviderApp{
+			dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "first"}),
+			dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{Name: "last"}),
+		}
+		check.Args().Asserts(apps[0], rbac.ActionRead, apps[1], rbac.ActionRead).Returns(apps)
+	}))
+	s.Run("GetOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionRead).Returns(app)
+	}))
+	s.Run("GetOAuth2ProviderAppsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(user.ID).
+			Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionRead).
+			Returns([]database.OAuth2ProviderApp{app})
+	}))
+	s.Run("InsertOAuth2ProviderApp", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertOAuth2ProviderAppParams{}).Asserts(rbac.ResourceOAuth2ProviderApp, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		app.Name = "updated"
+		check.Args(database.UpdateOAuth2ProviderAppByIDParams{
+			ID:           app.ID,
+			UpdatedAt:    app.UpdatedAt,
+			Name:         app.Name,
+			Icon:         app.Icon,
+			RedirectURIs: app.RedirectURIs,
+		}).Asserts(app, rbac.ActionUpdate).Returns(app)
+	}))
+	s.Run("DeleteOAuth2ProviderAppByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(app.ID).Asserts(app, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppSecrets() {
+	s.Run("GetOAuth2ProviderAppSecretsByAppID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(app.ID).Asserts(secret, rbac.ActionRead).Returns([]database.OAuth2ProviderAppSecret{secret})
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("GetOAuth2ProviderAppSecretByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.SecretPrefix).Asserts(secret, rbac.ActionRead).Returns(secret)
+	}))
+	s.Run("InsertOAuth2ProviderAppSecret", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppSecretParams{
+			AppID: app.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionCreate)
+	}))
+	s.Run("UpdateOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		secret.LastUsedAt = sql.NullTime{Time: database.Now(), Valid: true}
+		check.Args(database.UpdateOAuth2ProviderAppSecretByIDParams{
+			ID:         secret.ID,
+			LastUsedAt: secret.LastUsedAt,
+		}).Asserts(secret, rbac.ActionUpdate).Returns(secret)
+	}))
+	s.Run("DeleteOAuth2ProviderAppSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(secret.ID).Asserts(secret, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppCodes() {
+	s.Run("GetOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppCodeByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.SecretPrefix).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("InsertOAuth2ProviderAppCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppCodeParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+			Scope:  database.APIKeyScopeAll,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{AppID: app.ID, UserID: user.ID})
+		check.Args(code.ID).Asserts(code, rbac.ActionDelete).Returns(code.ID)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
+	s.Run("GetOAuth2ProviderAppTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(token.RefreshPrefix).
+			Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionRead).
+			Returns(token)
+	}))
+	s.Run("InsertOAuth2ProviderAppToken", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		check.Args(database.InsertOAuth2ProviderAppTokenParams{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(token.RefreshPrefix).
+			Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionDelete).
+			Returns(token)
+	}))
+	s.Run("DeleteOAuth2ProviderAppTokensByAppAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: user.ID})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{AppID: app.ID})
+		_ = dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionDelete).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestOrganization() {
 	s.Run("GetGroupsByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -642,6 +810,56 @@ func (s *MethodTestSuite) TestTemplate() {
 		}).Asserts(t1, rbac.ActionRead).
 			Returns(slice.New(a, b))
 	}))
+	s.Run("GetTemplateAppLimits", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		limits := dbgen.TemplateAppLimits(s.T(), db, database.TemplateAppLimit{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionRead).Returns(limits)
+	}))
+	s.Run("UpsertTemplateAppLimits", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateAppLimitsParams{
+			TemplateID:           t1.ID,
+			AppRequestsPerMinute: 60,
+			UpdatedAt:            time.Now(),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteTemplateAppLimits", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		_ = dbgen.TemplateAppLimits(s.T(), db, database.TemplateAppLimit{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetTemplateAppRouting", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		routing := dbgen.TemplateAppRouting(s.T(), db, database.TemplateAppRouting{TemplateID: t1.ID})
+		check.Args(database.GetTemplateAppRoutingParams{
+			TemplateID: t1.ID,
+			AppSlug:    routing.AppSlug,
+		}).Asserts(t1, rbac.ActionRead).Returns(routing)
+	}))
+	s.Run("GetTemplateAppRoutingByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		routing := dbgen.TemplateAppRouting(s.T(), db, database.TemplateAppRouting{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateAppRouting{routing})
+	}))
+	s.Run("UpsertTemplateAppRouting", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateAppRoutingParams{
+			TemplateID:            t1.ID,
+			AppSlug:               "jupyter",
+			Headers:               dbtype.StringMap{},
+			AllowedMethods:        []string{},
+			WebsocketSubprotocols: []string{},
+			UpdatedAt:             time.Now(),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteTemplateAppRouting", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		routing := dbgen.TemplateAppRouting(s.T(), db, database.TemplateAppRouting{TemplateID: t1.ID})
+		check.Args(database.DeleteTemplateAppRoutingParams{
+			TemplateID: t1.ID,
+			AppSlug:    routing.AppSlug,
+		}).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetTemplateVersionsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		now := time.Now()
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -812,6 +1030,17 @@ func (s *MethodTestSuite) TestUser() {
 			LoginType: database.LoginTypePassword,
 		}).Asserts(rbac.ResourceRoleAssignment, rbac.ActionCreate, rbac.ResourceUser, rbac.ActionCreate)
 	}))
+	s.Run("InsertServiceAccount", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{LoginType: database.LoginTypeNone})
+		check.Args(database.InsertServiceAccountParams{
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceUser, rbac.ActionCreate)
+	}))
+	s.Run("GetServiceAccountByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{LoginType: database.LoginTypeNone})
+		account := dbgen.ServiceAccount(s.T(), db, database.ServiceAccount{UserID: u.ID})
+		check.Args(u.ID).Asserts(account, rbac.ActionRead).Returns(account)
+	}))
 	s.Run("InsertUserLink", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.InsertUserLinkParams{
@@ -917,6 +1146,90 @@ func (s *MethodTestSuite) TestUser() {
 			LoginType:         link.LoginType,
 		}).Asserts(link, rbac.ActionUpdate).Returns(link)
 	}))
+	s.Run("GetUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		totp := dbgen.UserTOTP(s.T(), db, database.UserTOTP{})
+		check.Args(totp.UserID).Asserts(totp, rbac.ActionRead).Returns(totp)
+	}))
+	s.Run("UpsertUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserTOTPParams{
+			UserID:              u.ID,
+			HashedRecoveryCodes: []string{},
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("UpdateUserTOTPLastUsedStep", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserTOTP(s.T(), db, database.UserTOTP{UserID: u.ID, Confirmed: true})
+		check.Args(database.UpdateUserTOTPLastUsedStepParams{
+			UserID:       u.ID,
+			LastUsedStep: 1,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("UpdateUserTOTPRemoveRecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserTOTP(s.T(), db, database.UserTOTP{UserID: u.ID, Confirmed: true, HashedRecoveryCodes: []string{"code"}})
+		check.Args(database.UpdateUserTOTPRemoveRecoveryCodeParams{
+			UserID:             u.ID,
+			HashedRecoveryCode: "code",
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserTOTP", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.UserTOTP(s.T(), db, database.UserTOTP{UserID: u.ID})
+		check.Args(u.ID).Asserts(u.UserDataRBACObject(), rbac.ActionDelete).Returns()
+	}))
+	s.Run("GetUserWebAuthnCredentialByID", s.Subtest(func(db database.Store, check *expects) {
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{})
+		check.Args(credential.ID).Asserts(credential, rbac.ActionRead).Returns(credential)
+	}))
+	s.Run("GetUserWebAuthnCredentialsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{})
+		check.Args(credential.UserID).Asserts(credential, rbac.ActionRead).Returns([]database.UserWebAuthnCredential{credential})
+	}))
+	s.Run("InsertUserWebAuthnCredential", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserWebAuthnCredentialParams{
+			ID:     uuid.New(),
+			UserID: u.ID,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionCreate)
+	}))
+	s.Run("UpdateUserWebAuthnCredentialSignCount", s.Subtest(func(db database.Store, check *expects) {
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{})
+		check.Args(database.UpdateUserWebAuthnCredentialSignCountParams{
+			ID:        credential.ID,
+			SignCount: 1,
+		}).Asserts(credential, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteUserWebAuthnCredential", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		credential := dbgen.UserWebAuthnCredential(s.T(), db, database.UserWebAuthnCredential{UserID: u.ID})
+		check.Args(credential.ID).Asserts(u.UserDataRBACObject(), rbac.ActionDelete).Returns()
+	}))
+	s.Run("InsertUserWebAuthnChallenge", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserWebAuthnChallengeParams{
+			Challenge: []byte("challenge"),
+			UserID:    u.ID,
+			Ceremony:  "webauthn.get",
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionCreate).Returns()
+	}))
+	s.Run("DeleteUserWebAuthnChallenge", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		challenge := database.UserWebAuthnChallenge{
+			Challenge: []byte("challenge"),
+			UserID:    u.ID,
+			Ceremony:  "webauthn.get",
+			CreatedAt: database.Now(),
+			ExpiresAt: database.Now().Add(time.Minute),
+		}
+		err := db.InsertUserWebAuthnChallenge(context.Background(), database.InsertUserWebAuthnChallengeParams(challenge))
+		require.NoError(s.T(), err)
+		check.Args(database.DeleteUserWebAuthnChallengeParams{
+			Challenge: challenge.Challenge,
+			UserID:    u.ID,
+			Ceremony:  challenge.Ceremony,
+		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate).Returns(challenge)
+	}))
 	s.Run("UpdateUserRoles", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{RBACRoles: []string{rbac.RoleTemplateAdmin()}})
 		o := u
@@ -1200,12 +1513,58 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: ws.ID,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspacePeering", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspacePeeringParams{
+			ID:             ws.ID,
+			PeeringEnabled: true,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceTTL", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.UpdateWorkspaceTTLParams{
 			ID: ws.ID,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("GetWorkspacePortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		share := dbgen.WorkspacePortShare(s.T(), db, database.WorkspacePortShare{WorkspaceID: ws.ID})
+		check.Args(database.GetWorkspacePortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   share.AgentName,
+			Port:        share.Port,
+		}).Asserts(ws, rbac.ActionRead).Returns(share)
+	}))
+	s.Run("GetWorkspacePortSharesByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		share := dbgen.WorkspacePortShare(s.T(), db, database.WorkspacePortShare{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspacePortShare{share})
+	}))
+	s.Run("GetWorkspaceAppAccessLogs", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		_ = dbgen.WorkspaceAppAccessLog(s.T(), db, database.WorkspaceAppAccessLog{WorkspaceID: uuid.NullUUID{UUID: ws.ID, Valid: true}})
+		check.Args(database.GetWorkspaceAppAccessLogsParams{
+			WorkspaceID: ws.ID,
+		}).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("UpsertWorkspacePortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpsertWorkspacePortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "main",
+			Port:        8080,
+			ShareLevel:  database.AppSharingLevelPublic,
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspacePortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		share := dbgen.WorkspacePortShare(s.T(), db, database.WorkspacePortShare{WorkspaceID: ws.ID})
+		check.Args(database.DeleteWorkspacePortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   share.AgentName,
+			Port:        share.Port,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetWorkspaceByWorkspaceAppID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
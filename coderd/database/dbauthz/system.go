@@ -112,6 +112,13 @@ func (q *querier) GetWorkspaceAgentByAuthToken(ctx context.Context, authToken uu
 	return q.db.GetWorkspaceAgentByAuthToken(ctx, authToken)
 }
 
+func (q *querier) GetActiveServiceAccountCount(ctx context.Context) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.GetActiveServiceAccountCount(ctx)
+}
+
 func (q *querier) GetActiveUserCount(ctx context.Context) (int64, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -182,6 +189,69 @@ func (q *querier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Ti
 	return q.db.GetReplicasUpdatedAfter(ctx, updatedAt)
 }
 
+func (q *querier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (database.TailnetCoordinator, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.TailnetCoordinator{}, err
+	}
+	return q.db.UpsertTailnetCoordinator(ctx, id)
+}
+
+func (q *querier) DeleteTailnetAgentVersionsUpdatedBefore(ctx context.Context, updatedBefore time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteTailnetAgentVersionsUpdatedBefore(ctx, updatedBefore)
+}
+
+func (q *querier) DeleteTailnetCoordinator(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteTailnetCoordinator(ctx, id)
+}
+
+func (q *querier) DeleteTailnetCoordinatorsHeartbeatBefore(ctx context.Context, heartbeatAt time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteTailnetCoordinatorsHeartbeatBefore(ctx, heartbeatAt)
+}
+
+func (q *querier) UpsertTailnetAgentNode(ctx context.Context, arg database.UpsertTailnetAgentNodeParams) (database.TailnetNode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.TailnetNode{}, err
+	}
+	return q.db.UpsertTailnetAgentNode(ctx, arg)
+}
+
+func (q *querier) UpsertTailnetClientNode(ctx context.Context, arg database.UpsertTailnetClientNodeParams) (database.TailnetNode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.TailnetNode{}, err
+	}
+	return q.db.UpsertTailnetClientNode(ctx, arg)
+}
+
+func (q *querier) GetTailnetAgentNode(ctx context.Context, agentID uuid.UUID) (database.TailnetNode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.TailnetNode{}, err
+	}
+	return q.db.GetTailnetAgentNode(ctx, agentID)
+}
+
+func (q *querier) GetTailnetClientNodesAfterVersion(ctx context.Context, arg database.GetTailnetClientNodesAfterVersionParams) ([]database.TailnetNode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTailnetClientNodesAfterVersion(ctx, arg)
+}
+
+func (q *querier) DeleteTailnetNode(ctx context.Context, arg database.DeleteTailnetNodeParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteTailnetNode(ctx, arg)
+}
+
 func (q *querier) GetUserCount(ctx context.Context) (int64, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -204,6 +274,14 @@ func (q *querier) GetTemplateAverageBuildTime(ctx context.Context, arg database.
 	return q.db.GetTemplateAverageBuildTime(ctx, arg)
 }
 
+// Only used by metrics cache.
+func (q *querier) GetTemplateBuildStageStats(ctx context.Context, arg database.GetTemplateBuildStageStatsParams) ([]database.GetTemplateBuildStageStatsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateBuildStageStats(ctx, arg)
+}
+
 // Only used by metrics cache.
 func (q *querier) GetTemplateDAUs(ctx context.Context, templateID uuid.UUID) ([]database.GetTemplateDAUsRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
@@ -252,6 +330,27 @@ func (q *querier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetWorkspaceBuildsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceBuildTimingsByBuildIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceBuildTiming, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildTimingsByBuildIDs(ctx, ids)
+}
+
+func (q *querier) GetPendingProvisionerJobCountsByTags(ctx context.Context) ([]database.GetPendingProvisionerJobCountsByTagsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingProvisionerJobCountsByTags(ctx)
+}
+
+func (q *querier) GetPendingProvisionerJobs(ctx context.Context) ([]database.ProvisionerJob, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingProvisionerJobs(ctx)
+}
+
 func (q *querier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceAgent, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -287,6 +386,13 @@ func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	return q.db.DeleteOldWorkspaceAgentStats(ctx)
 }
 
+func (q *querier) DeleteExpiredUserWebAuthnChallenges(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteExpiredUserWebAuthnChallenges(ctx)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -294,6 +400,22 @@ func (q *querier) DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error
 	return q.db.DeleteOldWorkspaceAgentStartupLogs(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceAppAccessLogs(ctx context.Context, before time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAppAccessLogs(ctx, before)
+}
+
+// InsertWorkspaceAppAccessLog is called by the app proxy after it serves a
+// request, for which the requester may not be authenticated.
+func (q *querier) InsertWorkspaceAppAccessLog(ctx context.Context, arg database.InsertWorkspaceAppAccessLogParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceAppAccessLog(ctx, arg)
+}
+
 func (q *querier) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAfter time.Time) (database.GetDeploymentWorkspaceAgentStatsRow, error) {
 	return q.db.GetDeploymentWorkspaceAgentStats(ctx, createdAfter)
 }
@@ -368,6 +490,27 @@ func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.Upd
 	return q.db.UpdateProvisionerJobByID(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerDaemonHeartbeat(ctx context.Context, arg database.UpdateProvisionerDaemonHeartbeatParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerDaemonHeartbeat(ctx, arg)
+}
+
+func (q *querier) UpdateProvisionerJobHasMatchingDaemonByID(ctx context.Context, arg database.UpdateProvisionerJobHasMatchingDaemonByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerJobHasMatchingDaemonByID(ctx, arg)
+}
+
+func (q *querier) UpdatePendingProvisionerJobWithFailureByID(ctx context.Context, arg database.UpdatePendingProvisionerJobWithFailureByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdatePendingProvisionerJobWithFailureByID(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) InsertProvisionerJob(ctx context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
@@ -423,3 +566,17 @@ func (q *querier) InsertParameterSchema(ctx context.Context, arg database.Insert
 	}
 	return q.db.InsertParameterSchema(ctx, arg)
 }
+
+func (q *querier) UpdateWorkspaceBuildTimingObserved(ctx context.Context, arg database.UpdateWorkspaceBuildTimingObservedParams) (database.WorkspaceBuildTiming, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceBuildTiming{}, err
+	}
+	return q.db.UpdateWorkspaceBuildTimingObserved(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceBuildTiming(ctx context.Context, arg database.UpsertWorkspaceBuildTimingParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceBuildTiming(ctx, arg)
+}
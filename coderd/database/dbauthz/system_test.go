@@ -47,6 +47,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetActiveUserCount", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(int64(0))
 	}))
+	s.Run("GetActiveServiceAccountCount", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(int64(0))
+	}))
 	s.Run("GetUnexpiredLicenses", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
@@ -86,6 +89,63 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		require.NoError(s.T(), err)
 		check.Args(time.Now().Add(time.Hour*-1)).Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
+	s.Run("UpsertTailnetCoordinator", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteTailnetAgentVersionsUpdatedBefore", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Now().Add(-time.Hour)).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteTailnetCoordinator", s.Subtest(func(db database.Store, check *expects) {
+		coordinator, err := db.UpsertTailnetCoordinator(context.Background(), uuid.New())
+		require.NoError(s.T(), err)
+		check.Args(coordinator.ID).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteTailnetCoordinatorsHeartbeatBefore", s.Subtest(func(db database.Store, check *expects) {
+		_, err := db.UpsertTailnetCoordinator(context.Background(), uuid.New())
+		require.NoError(s.T(), err)
+		check.Args(time.Now().Add(-time.Hour)).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("UpsertTailnetAgentNode", s.Subtest(func(db database.Store, check *expects) {
+		coordinator, err := db.UpsertTailnetCoordinator(context.Background(), uuid.New())
+		require.NoError(s.T(), err)
+		check.Args(database.UpsertTailnetAgentNodeParams{
+			AgentID:       uuid.New(),
+			CoordinatorID: coordinator.ID,
+			Node:          []byte("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpsertTailnetClientNode", s.Subtest(func(db database.Store, check *expects) {
+		coordinator, err := db.UpsertTailnetCoordinator(context.Background(), uuid.New())
+		require.NoError(s.T(), err)
+		check.Args(database.UpsertTailnetClientNodeParams{
+			ID:            uuid.New(),
+			AgentID:       uuid.New(),
+			CoordinatorID: coordinator.ID,
+			Node:          []byte("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetTailnetAgentNode", s.Subtest(func(db database.Store, check *expects) {
+		coordinator, err := db.UpsertTailnetCoordinator(context.Background(), uuid.New())
+		require.NoError(s.T(), err)
+		node, err := db.UpsertTailnetAgentNode(context.Background(), database.UpsertTailnetAgentNodeParams{
+			AgentID:       uuid.New(),
+			CoordinatorID: coordinator.ID,
+			Node:          []byte("{}"),
+		})
+		require.NoError(s.T(), err)
+		check.Args(node.AgentID).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(node)
+	}))
+	s.Run("GetTailnetClientNodesAfterVersion", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetTailnetClientNodesAfterVersionParams{
+			AgentID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteTailnetNode", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteTailnetNodeParams{
+			ID:            uuid.New(),
+			CoordinatorID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("GetUserCount", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(int64(0))
 	}))
@@ -133,6 +193,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("DeleteExpiredUserWebAuthnChallenges", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("GetParameterSchemasCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.ParameterSchema(s.T(), db, database.ParameterSchema{CreatedAt: time.Now().Add(-time.Hour)})
 		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
@@ -225,6 +288,15 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			SharingLevel: database.AppSharingLevelOwner,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAppAccessLog", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAppAccessLogParams{
+			ID:           uuid.New(),
+			SharingLevel: database.AppSharingLevelPublic,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("DeleteOldWorkspaceAppAccessLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("InsertWorkspaceResourceMetadata", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceResourceMetadataParams{
 			WorkspaceResourceID: uuid.New(),
@@ -296,4 +368,63 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			ValidationTypeSystem:     database.ParameterTypeSystemNone,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("GetPendingProvisionerJobCountsByTags", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetPendingProvisionerJobs", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(slice.New(j))
+	}))
+	s.Run("UpdateProvisionerJobHasMatchingDaemonByID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args(database.UpdateProvisionerJobHasMatchingDaemonByIDParams{
+			ID: j.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdatePendingProvisionerJobWithFailureByID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args(database.UpdatePendingProvisionerJobWithFailureByIDParams{
+			ID:       j.ID,
+			FailedAt: time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateProvisionerDaemonHeartbeat", s.Subtest(func(db database.Store, check *expects) {
+		d, err := db.InsertProvisionerDaemon(context.Background(), database.InsertProvisionerDaemonParams{
+			ID: uuid.New(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateProvisionerDaemonHeartbeatParams{
+			ID:         d.ID,
+			LastSeenAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetTemplateBuildStageStats", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetTemplateBuildStageStatsParams{}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceBuildTimingsByBuildIDs", s.Subtest(func(db database.Store, check *expects) {
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{})
+		check.Args([]uuid.UUID{b.ID}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceBuildTimingObserved", s.Subtest(func(db database.Store, check *expects) {
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{})
+		err := db.UpsertWorkspaceBuildTiming(context.Background(), database.UpsertWorkspaceBuildTimingParams{
+			WorkspaceBuildID: b.ID,
+			Stage:            database.WorkspaceBuildStageAgentConnect,
+			StartedAt:        time.Now().Add(-time.Minute),
+			EndedAt:          sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceBuildTimingObservedParams{
+			WorkspaceBuildID: b.ID,
+			Stage:            database.WorkspaceBuildStageAgentConnect,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpsertWorkspaceBuildTiming", s.Subtest(func(db database.Store, check *expects) {
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{})
+		check.Args(database.UpsertWorkspaceBuildTimingParams{
+			WorkspaceBuildID: b.ID,
+			Stage:            database.WorkspaceBuildStageInit,
+			StartedAt:        time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 }
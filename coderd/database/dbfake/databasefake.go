@@ -1,6 +1,7 @@
 package dbfake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -34,6 +35,11 @@ var errDuplicateKey = &pq.Error{
 	Message: "duplicate key value violates unique constraint",
 }
 
+var errForeignKey = &pq.Error{
+	Code:    "23503",
+	Message: "insert or update violates foreign key constraint",
+}
+
 // New returns an in-memory fake of the database.
 func New() database.Store {
 	return &fakeQuerier{
@@ -113,22 +119,38 @@ type data struct {
 	groupMembers              []database.GroupMember
 	groups                    []database.Group
 	licenses                  []database.License
+	oauth2ProviderApps        []database.OAuth2ProviderApp
+	oauth2ProviderAppSecrets  []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppCodes    []database.OAuth2ProviderAppCode
+	oauth2ProviderAppTokens   []database.OAuth2ProviderAppToken
 	parameterSchemas          []database.ParameterSchema
 	parameterValues           []database.ParameterValue
 	provisionerDaemons        []database.ProvisionerDaemon
 	provisionerJobLogs        []database.ProvisionerJobLog
 	provisionerJobs           []database.ProvisionerJob
 	replicas                  []database.Replica
+	serviceAccounts           []database.ServiceAccount
+	tailnetAgentVersions      []database.TailnetAgentVersion
+	tailnetCoordinators       []database.TailnetCoordinator
+	tailnetNodes              []database.TailnetNode
+	templateAppLimits         []database.TemplateAppLimit
+	templateAppRouting        []database.TemplateAppRouting
 	templateVersions          []database.TemplateVersion
 	templateVersionParameters []database.TemplateVersionParameter
 	templateVersionVariables  []database.TemplateVersionVariable
 	templates                 []database.Template
+	userTOTPs                 []database.UserTOTP
+	userWebAuthnChallenges    []database.UserWebAuthnChallenge
+	userWebAuthnCredentials   []database.UserWebAuthnCredential
 	workspaceAgents           []database.WorkspaceAgent
 	workspaceAgentMetadata    []database.WorkspaceAgentMetadatum
 	workspaceAgentLogs        []database.WorkspaceAgentStartupLog
+	workspaceAppAccessLogs    []database.WorkspaceAppAccessLog
 	workspaceApps             []database.WorkspaceApp
 	workspaceBuilds           []database.WorkspaceBuild
 	workspaceBuildParameters  []database.WorkspaceBuildParameter
+	workspaceBuildTimings     []database.WorkspaceBuildTiming
+	workspacePortShares       []database.WorkspacePortShare
 	workspaceResourceMetadata []database.WorkspaceResourceMetadatum
 	workspaceResources        []database.WorkspaceResource
 	workspaces                []database.Workspace
@@ -564,6 +586,59 @@ func (q *fakeQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg datab
 	return row, nil
 }
 
+func (q *fakeQuerier) GetTemplateBuildStageStats(ctx context.Context, arg database.GetTemplateBuildStageStatsParams) ([]database.GetTemplateBuildStageStatsRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	durations := map[database.WorkspaceBuildStage][]float64{}
+	for _, timing := range q.workspaceBuildTimings {
+		if !timing.EndedAt.Valid || !timing.StartedAt.After(arg.StartTime) {
+			continue
+		}
+		var build database.WorkspaceBuild
+		for _, wb := range q.workspaceBuilds {
+			if wb.ID == timing.WorkspaceBuildID {
+				build = wb
+				break
+			}
+		}
+		if build.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		version, err := q.getTemplateVersionByIDNoLock(ctx, build.TemplateVersionID)
+		if err != nil {
+			return nil, err
+		}
+		if version.TemplateID != arg.TemplateID {
+			continue
+		}
+		durations[timing.Stage] = append(durations[timing.Stage], timing.EndedAt.Time.Sub(timing.StartedAt).Seconds())
+	}
+
+	percentile := func(fs []float64, p float64) float64 {
+		sort.Float64s(fs)
+		return fs[int(float64(len(fs))*p/100)]
+	}
+
+	rows := make([]database.GetTemplateBuildStageStatsRow, 0)
+	for _, stage := range database.AllWorkspaceBuildStageValues() {
+		fs, ok := durations[stage]
+		if !ok {
+			continue
+		}
+		rows = append(rows, database.GetTemplateBuildStageStatsRow{
+			Stage: stage,
+			P50:   percentile(fs, 50),
+			P95:   percentile(fs, 95),
+		})
+	}
+	return rows, nil
+}
+
 func (q *fakeQuerier) ParameterValue(_ context.Context, id uuid.UUID) (database.ParameterValue, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -596,6 +671,10 @@ func (q *fakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIK
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
+	return q.getAPIKeyByIDNoLock(id)
+}
+
+func (q *fakeQuerier) getAPIKeyByIDNoLock(id string) (database.APIKey, error) {
 	for _, apiKey := range q.apiKeys {
 		if apiKey.ID == id {
 			return apiKey, nil
@@ -818,6 +897,19 @@ func (q *fakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 	return active, nil
 }
 
+func (q *fakeQuerier) GetActiveServiceAccountCount(_ context.Context) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	active := int64(0)
+	for _, u := range q.users {
+		if u.Status == database.UserStatusActive && !u.Deleted && u.LoginType == database.LoginTypeNone {
+			active++
+		}
+	}
+	return active, nil
+}
+
 func (q *fakeQuerier) GetFilteredUserCount(ctx context.Context, arg database.GetFilteredUserCountParams) (int64, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return 0, err
@@ -1370,6 +1462,7 @@ func convertToWorkspaceRows(workspaces []database.Workspace, count int64) []data
 			AutostartSchedule: w.AutostartSchedule,
 			Ttl:               w.Ttl,
 			LastUsedAt:        w.LastUsedAt,
+			PeeringEnabled:    w.PeeringEnabled,
 			Count:             count,
 		}
 	}
@@ -1724,6 +1817,19 @@ func (q *fakeQuerier) GetWorkspaceBuildParameters(_ context.Context, workspaceBu
 	return params, nil
 }
 
+func (q *fakeQuerier) GetWorkspaceBuildTimingsByBuildIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceBuildTiming, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	timings := make([]database.WorkspaceBuildTiming, 0)
+	for _, timing := range q.workspaceBuildTimings {
+		if slices.Contains(ids, timing.WorkspaceBuildID) {
+			timings = append(timings, timing)
+		}
+	}
+	return timings, nil
+}
+
 func (q *fakeQuerier) GetWorkspaceBuildsCreatedAfter(_ context.Context, after time.Time) ([]database.WorkspaceBuild, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2445,6 +2551,51 @@ func (q *fakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) GetPendingProvisionerJobCountsByTags(_ context.Context) ([]database.GetPendingProvisionerJobCountsByTagsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetPendingProvisionerJobCountsByTagsRow, 0)
+	for _, job := range q.provisionerJobs {
+		if job.StartedAt.Valid || job.CanceledAt.Valid || job.CompletedAt.Valid {
+			continue
+		}
+		found := false
+		for i, row := range rows {
+			if row.Provisioner == job.Provisioner && maps.Equal(row.Tags, job.Tags) {
+				rows[i].Count++
+				found = true
+				break
+			}
+		}
+		if !found {
+			rows = append(rows, database.GetPendingProvisionerJobCountsByTagsRow{
+				Provisioner: job.Provisioner,
+				Tags:        job.Tags,
+				Count:       1,
+			})
+		}
+	}
+	return rows, nil
+}
+
+func (q *fakeQuerier) GetPendingProvisionerJobs(_ context.Context) ([]database.ProvisionerJob, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	jobs := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if job.StartedAt.Valid || job.CanceledAt.Valid || job.CompletedAt.Valid {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b database.ProvisionerJob) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return jobs, nil
+}
+
 func (q *fakeQuerier) GetProvisionerDaemons(_ context.Context) ([]database.ProvisionerDaemon, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2737,6 +2888,7 @@ func (q *fakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		LoginType:       arg.LoginType,
 		Scope:           arg.Scope,
 		TokenName:       arg.TokenName,
+		CustomScopes:    arg.CustomScopes,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -3001,13 +3153,17 @@ func (q *fakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.I
 	for index, output := range arg.Output {
 		id++
 		logs = append(logs, database.ProvisionerJobLog{
-			ID:        id,
-			JobID:     arg.JobID,
-			CreatedAt: arg.CreatedAt[index],
-			Source:    arg.Source[index],
-			Level:     arg.Level[index],
-			Stage:     arg.Stage[index],
-			Output:    output,
+			ID:                     id,
+			JobID:                  arg.JobID,
+			CreatedAt:              arg.CreatedAt[index],
+			Source:                 arg.Source[index],
+			Level:                  arg.Level[index],
+			Stage:                  arg.Stage[index],
+			Output:                 output,
+			ResourceAddress:        arg.ResourceAddress[index],
+			ResourceAction:         arg.ResourceAction[index],
+			ResourceStatus:         arg.ResourceStatus[index],
+			ResourceElapsedSeconds: arg.ResourceElapsedSeconds[index],
 		})
 	}
 	q.provisionerJobLogs = append(q.provisionerJobLogs, logs...)
@@ -3074,17 +3230,18 @@ func (q *fakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 	defer q.mutex.Unlock()
 
 	job := database.ProvisionerJob{
-		ID:             arg.ID,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		OrganizationID: arg.OrganizationID,
-		InitiatorID:    arg.InitiatorID,
-		Provisioner:    arg.Provisioner,
-		StorageMethod:  arg.StorageMethod,
-		FileID:         arg.FileID,
-		Type:           arg.Type,
-		Input:          arg.Input,
-		Tags:           arg.Tags,
+		ID:                arg.ID,
+		CreatedAt:         arg.CreatedAt,
+		UpdatedAt:         arg.UpdatedAt,
+		OrganizationID:    arg.OrganizationID,
+		InitiatorID:       arg.InitiatorID,
+		Provisioner:       arg.Provisioner,
+		StorageMethod:     arg.StorageMethod,
+		FileID:            arg.FileID,
+		Type:              arg.Type,
+		Input:             arg.Input,
+		Tags:              arg.Tags,
+		HasMatchingDaemon: arg.HasMatchingDaemon,
 	}
 	q.provisionerJobs = append(q.provisionerJobs, job)
 	return job, nil
@@ -3708,6 +3865,58 @@ func (q *fakeQuerier) InsertWorkspaceAgentStartupLogs(_ context.Context, arg dat
 	return logs, nil
 }
 
+func (q *fakeQuerier) UpdatePendingProvisionerJobWithFailureByID(_ context.Context, arg database.UpdatePendingProvisionerJobWithFailureByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, job := range q.provisionerJobs {
+		if arg.ID != job.ID {
+			continue
+		}
+		if job.StartedAt.Valid {
+			return nil
+		}
+		failedAt := sql.NullTime{Time: arg.FailedAt, Valid: true}
+		job.StartedAt = failedAt
+		job.UpdatedAt = arg.FailedAt
+		job.CompletedAt = failedAt
+		job.Error = arg.Error
+		job.ErrorCode = arg.ErrorCode
+		q.provisionerJobs[index] = job
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateProvisionerDaemonHeartbeat(_ context.Context, arg database.UpdateProvisionerDaemonHeartbeatParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, daemon := range q.provisionerDaemons {
+		if arg.ID != daemon.ID {
+			continue
+		}
+		daemon.LastSeenAt = arg.LastSeenAt
+		daemon.Version = arg.Version
+		daemon.Hostname = arg.Hostname
+		daemon.OperatingSystem = arg.OperatingSystem
+		daemon.Architecture = arg.Architecture
+		daemon.Concurrency = arg.Concurrency
+		daemon.CurrentJobID = arg.CurrentJobID
+		q.provisionerDaemons[index] = daemon
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -3727,6 +3936,25 @@ func (q *fakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.U
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateProvisionerJobHasMatchingDaemonByID(_ context.Context, arg database.UpdateProvisionerJobHasMatchingDaemonByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, job := range q.provisionerJobs {
+		if arg.ID != job.ID {
+			continue
+		}
+		job.HasMatchingDaemon = arg.HasMatchingDaemon
+		q.provisionerJobs[index] = job
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateProvisionerJobWithCancelByID(_ context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -3819,6 +4047,26 @@ func (q *fakeQuerier) UpdateWorkspaceAutostart(_ context.Context, arg database.U
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateWorkspacePeering(_ context.Context, arg database.UpdateWorkspacePeeringParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.PeeringEnabled = arg.PeeringEnabled
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4037,6 +4285,28 @@ func (q *fakeQuerier) UpdateWorkspaceBuildCostByID(_ context.Context, arg databa
 	return database.WorkspaceBuild{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateWorkspaceBuildTimingObserved(_ context.Context, arg database.UpdateWorkspaceBuildTimingObservedParams) (database.WorkspaceBuildTiming, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceBuildTiming{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, timing := range q.workspaceBuildTimings {
+		if timing.WorkspaceBuildID != arg.WorkspaceBuildID || timing.Stage != arg.Stage {
+			continue
+		}
+		if !timing.EndedAt.Valid || timing.Observed {
+			break
+		}
+		timing.Observed = true
+		q.workspaceBuildTimings[index] = timing
+		return timing, nil
+	}
+	return database.WorkspaceBuildTiming{}, sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4310,6 +4580,7 @@ func (q *fakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAudi
 			UserCreatedAt:    sql.NullTime{Time: user.CreatedAt, Valid: userValid},
 			UserStatus:       database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
 			UserRoles:        user.RBACRoles,
+			UserLoginType:    database.NullLoginType{LoginType: user.LoginType, Valid: userValid},
 			Count:            0,
 		})
 
@@ -4400,214 +4671,1241 @@ func (q *fakeQuerier) UpsertServiceBanner(_ context.Context, data string) error
 	return nil
 }
 
-func (q *fakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
-
-	if q.serviceBanner == nil {
-		return "", sql.ErrNoRows
+func (q *fakeQuerier) UpsertWorkspaceBuildTiming(_ context.Context, arg database.UpsertWorkspaceBuildTimingParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
 	}
 
-	return string(q.serviceBanner), nil
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, timing := range q.workspaceBuildTimings {
+		if timing.WorkspaceBuildID != arg.WorkspaceBuildID || timing.Stage != arg.Stage {
+			continue
+		}
+		if arg.StartedAt.Before(timing.StartedAt) {
+			timing.StartedAt = arg.StartedAt
+		}
+		if arg.EndedAt.Valid && (!timing.EndedAt.Valid || arg.EndedAt.Time.After(timing.EndedAt.Time)) {
+			timing.EndedAt = arg.EndedAt
+		}
+		q.workspaceBuildTimings[index] = timing
+		return nil
+	}
+	q.workspaceBuildTimings = append(q.workspaceBuildTimings, database.WorkspaceBuildTiming{
+		WorkspaceBuildID: arg.WorkspaceBuildID,
+		Stage:            arg.Stage,
+		StartedAt:        arg.StartedAt,
+		EndedAt:          arg.EndedAt,
+	})
+	return nil
 }
 
-func (q *fakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
+func (q *fakeQuerier) UpsertWorkspacePortShare(_ context.Context, arg database.UpsertWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspacePortShare{}, err
+	}
 
-	q.logoURL = data
-	return nil
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	share := database.WorkspacePortShare{
+		WorkspaceID: arg.WorkspaceID,
+		AgentName:   arg.AgentName,
+		Port:        arg.Port,
+		ShareLevel:  arg.ShareLevel,
+		CreatedAt:   arg.CreatedAt,
+		ExpiresAt:   arg.ExpiresAt,
+	}
+	for index, existing := range q.workspacePortShares {
+		if existing.WorkspaceID == arg.WorkspaceID && existing.AgentName == arg.AgentName && existing.Port == arg.Port {
+			q.workspacePortShares[index] = share
+			return share, nil
+		}
+	}
+	q.workspacePortShares = append(q.workspacePortShares, share)
+	return share, nil
 }
 
-func (q *fakeQuerier) GetLogoURL(_ context.Context) (string, error) {
+func (q *fakeQuerier) GetWorkspacePortShare(_ context.Context, arg database.GetWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	if q.logoURL == "" {
-		return "", sql.ErrNoRows
+	for _, share := range q.workspacePortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			return share, nil
+		}
 	}
-
-	return q.logoURL, nil
+	return database.WorkspacePortShare{}, sql.ErrNoRows
 }
 
-func (q *fakeQuerier) GetAppSigningKey(_ context.Context) (string, error) {
+func (q *fakeQuerier) GetWorkspacePortSharesByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShare, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	return q.appSigningKey, nil
+	shares := make([]database.WorkspacePortShare, 0)
+	for _, share := range q.workspacePortShares {
+		if share.WorkspaceID == workspaceID {
+			shares = append(shares, share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].AgentName != shares[j].AgentName {
+			return shares[i].AgentName < shares[j].AgentName
+		}
+		return shares[i].Port < shares[j].Port
+	})
+	return shares, nil
 }
 
-func (q *fakeQuerier) InsertAppSigningKey(_ context.Context, data string) error {
+func (q *fakeQuerier) DeleteWorkspacePortShare(_ context.Context, arg database.DeleteWorkspacePortShareParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	q.appSigningKey = data
+	for index, share := range q.workspacePortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			q.workspacePortShares = append(q.workspacePortShares[:index], q.workspacePortShares[index+1:]...)
+			return nil
+		}
+	}
 	return nil
 }
 
-func (q *fakeQuerier) InsertLicense(
-	_ context.Context, arg database.InsertLicenseParams,
-) (database.License, error) {
+func (q *fakeQuerier) UpsertTemplateAppLimits(_ context.Context, arg database.UpsertTemplateAppLimitsParams) (database.TemplateAppLimit, error) {
 	if err := validateDatabaseType(arg); err != nil {
-		return database.License{}, err
+		return database.TemplateAppLimit{}, err
 	}
 
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	l := database.License{
-		ID:         q.lastLicenseID + 1,
-		UploadedAt: arg.UploadedAt,
-		JWT:        arg.JWT,
-		Exp:        arg.Exp,
+	limits := database.TemplateAppLimit{
+		TemplateID:                       arg.TemplateID,
+		AppRequestsPerMinute:             arg.AppRequestsPerMinute,
+		AppBandwidthBytesPerSecond:       arg.AppBandwidthBytesPerSecond,
+		WorkspaceRequestsPerMinute:       arg.WorkspaceRequestsPerMinute,
+		WorkspaceBandwidthBytesPerSecond: arg.WorkspaceBandwidthBytesPerSecond,
+		UpdatedAt:                        arg.UpdatedAt,
 	}
-	q.lastLicenseID = l.ID
-	q.licenses = append(q.licenses, l)
-	return l, nil
+	for index, existing := range q.templateAppLimits {
+		if existing.TemplateID == arg.TemplateID {
+			q.templateAppLimits[index] = limits
+			return limits, nil
+		}
+	}
+	q.templateAppLimits = append(q.templateAppLimits, limits)
+	return limits, nil
 }
 
-func (q *fakeQuerier) GetLicenseByID(_ context.Context, id int32) (database.License, error) {
+func (q *fakeQuerier) GetTemplateAppLimits(_ context.Context, templateID uuid.UUID) (database.TemplateAppLimit, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	for _, license := range q.licenses {
+	for _, limits := range q.templateAppLimits {
+		if limits.TemplateID == templateID {
+			return limits, nil
+		}
+	}
+	return database.TemplateAppLimit{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteTemplateAppLimits(_ context.Context, templateID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, limits := range q.templateAppLimits {
+		if limits.TemplateID == templateID {
+			q.templateAppLimits = append(q.templateAppLimits[:index], q.templateAppLimits[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) UpsertTemplateAppRouting(_ context.Context, arg database.UpsertTemplateAppRoutingParams) (database.TemplateAppRouting, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TemplateAppRouting{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	routing := database.TemplateAppRouting{
+		TemplateID:            arg.TemplateID,
+		AppSlug:               arg.AppSlug,
+		StripPrefix:           arg.StripPrefix,
+		BasePathHeader:        arg.BasePathHeader,
+		Headers:               arg.Headers,
+		AllowedMethods:        arg.AllowedMethods,
+		WebsocketSubprotocols: arg.WebsocketSubprotocols,
+		UpdatedAt:             arg.UpdatedAt,
+	}
+	for index, existing := range q.templateAppRouting {
+		if existing.TemplateID == arg.TemplateID && existing.AppSlug == arg.AppSlug {
+			q.templateAppRouting[index] = routing
+			return routing, nil
+		}
+	}
+	q.templateAppRouting = append(q.templateAppRouting, routing)
+	return routing, nil
+}
+
+func (q *fakeQuerier) GetTemplateAppRouting(_ context.Context, arg database.GetTemplateAppRoutingParams) (database.TemplateAppRouting, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TemplateAppRouting{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, routing := range q.templateAppRouting {
+		if routing.TemplateID == arg.TemplateID && routing.AppSlug == arg.AppSlug {
+			return routing, nil
+		}
+	}
+	return database.TemplateAppRouting{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetTemplateAppRoutingByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplateAppRouting, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	routings := make([]database.TemplateAppRouting, 0)
+	for _, routing := range q.templateAppRouting {
+		if routing.TemplateID == templateID {
+			routings = append(routings, routing)
+		}
+	}
+	sort.Slice(routings, func(i, j int) bool {
+		return routings[i].AppSlug < routings[j].AppSlug
+	})
+	return routings, nil
+}
+
+func (q *fakeQuerier) DeleteTemplateAppRouting(_ context.Context, arg database.DeleteTemplateAppRoutingParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, routing := range q.templateAppRouting {
+		if routing.TemplateID == arg.TemplateID && routing.AppSlug == arg.AppSlug {
+			q.templateAppRouting = append(q.templateAppRouting[:index], q.templateAppRouting[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) InsertWorkspaceAppAccessLog(_ context.Context, arg database.InsertWorkspaceAppAccessLogParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceAppAccessLogs = append(q.workspaceAppAccessLogs, database.WorkspaceAppAccessLog(arg))
+	return nil
+}
+
+func (q *fakeQuerier) GetWorkspaceAppAccessLogs(_ context.Context, arg database.GetWorkspaceAppAccessLogsParams) ([]database.GetWorkspaceAppAccessLogsRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.GetWorkspaceAppAccessLogsRow, 0)
+	for _, accessLog := range q.workspaceAppAccessLogs {
+		if !accessLog.WorkspaceID.Valid || accessLog.WorkspaceID.UUID != arg.WorkspaceID {
+			continue
+		}
+		if arg.AppSlugOrPort != "" && accessLog.AppSlugOrPort != arg.AppSlugOrPort {
+			continue
+		}
+		row := database.GetWorkspaceAppAccessLogsRow{
+			ID:            accessLog.ID,
+			CreatedAt:     accessLog.CreatedAt,
+			UserID:        accessLog.UserID,
+			WorkspaceID:   accessLog.WorkspaceID,
+			AgentID:       accessLog.AgentID,
+			AppSlugOrPort: accessLog.AppSlugOrPort,
+			AccessMethod:  accessLog.AccessMethod,
+			SharingLevel:  accessLog.SharingLevel,
+			Ip:            accessLog.Ip,
+			Method:        accessLog.Method,
+			Path:          accessLog.Path,
+			StatusCode:    accessLog.StatusCode,
+			BytesWritten:  accessLog.BytesWritten,
+			DurationMs:    accessLog.DurationMs,
+		}
+		if accessLog.UserID.Valid {
+			for _, user := range q.users {
+				if user.ID == accessLog.UserID.UUID {
+					row.UserUsername = sql.NullString{String: user.Username, Valid: true}
+					break
+				}
+			}
+		}
+		logs = append(logs, row)
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].CreatedAt.After(logs[j].CreatedAt)
+	})
+
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(logs) {
+			return []database.GetWorkspaceAppAccessLogsRow{}, nil
+		}
+		logs = logs[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(logs) {
+		logs = logs[:arg.LimitOpt]
+	}
+	return logs, nil
+}
+
+func (q *fakeQuerier) DeleteOldWorkspaceAppAccessLogs(_ context.Context, before time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logs := q.workspaceAppAccessLogs[:0]
+	for _, accessLog := range q.workspaceAppAccessLogs {
+		if accessLog.CreatedAt.Before(before) {
+			continue
+		}
+		logs = append(logs, accessLog)
+	}
+	q.workspaceAppAccessLogs = logs
+	return nil
+}
+
+func (q *fakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.serviceBanner == nil {
+		return "", sql.ErrNoRows
+	}
+
+	return string(q.serviceBanner), nil
+}
+
+func (q *fakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	q.logoURL = data
+	return nil
+}
+
+func (q *fakeQuerier) GetLogoURL(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.logoURL == "" {
+		return "", sql.ErrNoRows
+	}
+
+	return q.logoURL, nil
+}
+
+func (q *fakeQuerier) GetAppSigningKey(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.appSigningKey, nil
+}
+
+func (q *fakeQuerier) InsertAppSigningKey(_ context.Context, data string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.appSigningKey = data
+	return nil
+}
+
+func (q *fakeQuerier) InsertLicense(
+	_ context.Context, arg database.InsertLicenseParams,
+) (database.License, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.License{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	l := database.License{
+		ID:         q.lastLicenseID + 1,
+		UploadedAt: arg.UploadedAt,
+		JWT:        arg.JWT,
+		Exp:        arg.Exp,
+	}
+	q.lastLicenseID = l.ID
+	q.licenses = append(q.licenses, l)
+	return l, nil
+}
+
+func (q *fakeQuerier) GetLicenseByID(_ context.Context, id int32) (database.License, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, license := range q.licenses {
 		if license.ID == id {
 			return license, nil
 		}
 	}
-	return database.License{}, sql.ErrNoRows
+	return database.License{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetLicenses(_ context.Context) ([]database.License, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	results := append([]database.License{}, q.licenses...)
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	return results, nil
+}
+
+func (q *fakeQuerier) GetUnexpiredLicenses(_ context.Context) ([]database.License, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	now := time.Now()
+	var results []database.License
+	for _, l := range q.licenses {
+		if l.Exp.After(now) {
+			results = append(results, l)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	return results, nil
+}
+
+func (q *fakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, l := range q.licenses {
+		if l.ID == id {
+			q.licenses[index] = q.licenses[len(q.licenses)-1]
+			q.licenses = q.licenses[:len(q.licenses)-1]
+			return id, nil
+		}
+	}
+	return 0, sql.ErrNoRows
+}
+
+func (*fakeQuerier) DeleteOldWorkspaceAgentStartupLogs(_ context.Context) error {
+	// noop
+	return nil
+}
+
+func (q *fakeQuerier) GetUserLinkByLinkedID(_ context.Context, id string) (database.UserLink, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, link := range q.userLinks {
+		if link.LinkedID == id {
+			return link, nil
+		}
+	}
+	return database.UserLink{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetUserLinkByUserIDLoginType(_ context.Context, params database.GetUserLinkByUserIDLoginTypeParams) (database.UserLink, error) {
+	if err := validateDatabaseType(params); err != nil {
+		return database.UserLink{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, link := range q.userLinks {
+		if link.UserID == params.UserID && link.LoginType == params.LoginType {
+			return link, nil
+		}
+	}
+	return database.UserLink{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) InsertUserLink(_ context.Context, args database.InsertUserLinkParams) (database.UserLink, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	//nolint:gosimple
+	link := database.UserLink{
+		UserID:            args.UserID,
+		LoginType:         args.LoginType,
+		LinkedID:          args.LinkedID,
+		OAuthAccessToken:  args.OAuthAccessToken,
+		OAuthRefreshToken: args.OAuthRefreshToken,
+		OAuthExpiry:       args.OAuthExpiry,
+	}
+
+	q.userLinks = append(q.userLinks, link)
+
+	return link, nil
+}
+
+func (q *fakeQuerier) UpdateUserLinkedID(_ context.Context, params database.UpdateUserLinkedIDParams) (database.UserLink, error) {
+	if err := validateDatabaseType(params); err != nil {
+		return database.UserLink{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for i, link := range q.userLinks {
+		if link.UserID == params.UserID && link.LoginType == params.LoginType {
+			link.LinkedID = params.LinkedID
+
+			q.userLinks[i] = link
+			return link, nil
+		}
+	}
+
+	return database.UserLink{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateUserLink(_ context.Context, params database.UpdateUserLinkParams) (database.UserLink, error) {
+	if err := validateDatabaseType(params); err != nil {
+		return database.UserLink{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for i, link := range q.userLinks {
+		if link.UserID == params.UserID && link.LoginType == params.LoginType {
+			link.OAuthAccessToken = params.OAuthAccessToken
+			link.OAuthRefreshToken = params.OAuthRefreshToken
+			link.OAuthExpiry = params.OAuthExpiry
+
+			q.userLinks[i] = link
+			return link, nil
+		}
+	}
+
+	return database.UserLink{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetUserTOTP(_ context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, totp := range q.userTOTPs {
+		if totp.UserID == userID {
+			return totp, nil
+		}
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpsertUserTOTP(_ context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	totp := database.UserTOTP{
+		UserID:              arg.UserID,
+		Secret:              arg.Secret,
+		Confirmed:           arg.Confirmed,
+		HashedRecoveryCodes: arg.HashedRecoveryCodes,
+		LastUsedStep:        arg.LastUsedStep,
+		CreatedAt:           arg.CreatedAt,
+		UpdatedAt:           arg.UpdatedAt,
+	}
+	for i, existing := range q.userTOTPs {
+		if existing.UserID == arg.UserID {
+			totp.CreatedAt = existing.CreatedAt
+			q.userTOTPs[i] = totp
+			return totp, nil
+		}
+	}
+	q.userTOTPs = append(q.userTOTPs, totp)
+	return totp, nil
+}
+
+func (q *fakeQuerier) UpdateUserTOTPLastUsedStep(_ context.Context, arg database.UpdateUserTOTPLastUsedStepParams) (database.UserTOTP, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID != arg.UserID || !totp.Confirmed || totp.LastUsedStep >= arg.LastUsedStep {
+			continue
+		}
+		totp.LastUsedStep = arg.LastUsedStep
+		totp.UpdatedAt = arg.UpdatedAt
+		q.userTOTPs[i] = totp
+		return totp, nil
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateUserTOTPRemoveRecoveryCode(_ context.Context, arg database.UpdateUserTOTPRemoveRecoveryCodeParams) (database.UserTOTP, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserTOTP{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID != arg.UserID || !totp.Confirmed || !slices.Contains(totp.HashedRecoveryCodes, arg.HashedRecoveryCode) {
+			continue
+		}
+		remaining := make([]string, 0, len(totp.HashedRecoveryCodes))
+		for _, code := range totp.HashedRecoveryCodes {
+			if code != arg.HashedRecoveryCode {
+				remaining = append(remaining, code)
+			}
+		}
+		totp.HashedRecoveryCodes = remaining
+		totp.UpdatedAt = arg.UpdatedAt
+		q.userTOTPs[i] = totp
+		return totp, nil
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteUserTOTP(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, totp := range q.userTOTPs {
+		if totp.UserID == userID {
+			q.userTOTPs = append(q.userTOTPs[:i], q.userTOTPs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) GetUserWebAuthnCredentialByID(_ context.Context, id uuid.UUID) (database.UserWebAuthnCredential, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, credential := range q.userWebAuthnCredentials {
+		if credential.ID == id {
+			return credential, nil
+		}
+	}
+	return database.UserWebAuthnCredential{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetUserWebAuthnCredentialsByUserID(_ context.Context, userID uuid.UUID) ([]database.UserWebAuthnCredential, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	credentials := make([]database.UserWebAuthnCredential, 0)
+	for _, credential := range q.userWebAuthnCredentials {
+		if credential.UserID == userID {
+			credentials = append(credentials, credential)
+		}
+	}
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].CreatedAt.Before(credentials[j].CreatedAt)
+	})
+	return credentials, nil
+}
+
+func (q *fakeQuerier) InsertUserWebAuthnCredential(_ context.Context, arg database.InsertUserWebAuthnCredentialParams) (database.UserWebAuthnCredential, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserWebAuthnCredential{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, credential := range q.userWebAuthnCredentials {
+		if bytes.Equal(credential.CredentialID, arg.CredentialID) {
+			return database.UserWebAuthnCredential{}, &pq.Error{
+				Code:       "23505",
+				Message:    "duplicate key value violates unique constraint",
+				Constraint: string(database.UniqueUserWebauthnCredentialsCredentialIDKey),
+			}
+		}
+	}
+
+	//nolint:gosimple
+	credential := database.UserWebAuthnCredential{
+		ID:           arg.ID,
+		UserID:       arg.UserID,
+		CredentialID: arg.CredentialID,
+		Name:         arg.Name,
+		PublicKey:    arg.PublicKey,
+		SignCount:    arg.SignCount,
+		CreatedAt:    arg.CreatedAt,
+		LastUsedAt:   arg.LastUsedAt,
+	}
+	q.userWebAuthnCredentials = append(q.userWebAuthnCredentials, credential)
+	return credential, nil
+}
+
+func (q *fakeQuerier) UpdateUserWebAuthnCredentialSignCount(_ context.Context, arg database.UpdateUserWebAuthnCredentialSignCountParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, credential := range q.userWebAuthnCredentials {
+		if credential.ID == arg.ID {
+			credential.SignCount = arg.SignCount
+			credential.LastUsedAt = arg.LastUsedAt
+			q.userWebAuthnCredentials[i] = credential
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteUserWebAuthnCredential(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, credential := range q.userWebAuthnCredentials {
+		if credential.ID == id {
+			q.userWebAuthnCredentials = append(q.userWebAuthnCredentials[:i], q.userWebAuthnCredentials[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) InsertUserWebAuthnChallenge(_ context.Context, arg database.InsertUserWebAuthnChallengeParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	q.userWebAuthnChallenges = append(q.userWebAuthnChallenges, database.UserWebAuthnChallenge{
+		Challenge: arg.Challenge,
+		UserID:    arg.UserID,
+		Ceremony:  arg.Ceremony,
+		CreatedAt: arg.CreatedAt,
+		ExpiresAt: arg.ExpiresAt,
+	})
+	return nil
+}
+
+func (q *fakeQuerier) DeleteUserWebAuthnChallenge(_ context.Context, arg database.DeleteUserWebAuthnChallengeParams) (database.UserWebAuthnChallenge, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserWebAuthnChallenge{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, challenge := range q.userWebAuthnChallenges {
+		if bytes.Equal(challenge.Challenge, arg.Challenge) && challenge.UserID == arg.UserID && challenge.Ceremony == arg.Ceremony {
+			q.userWebAuthnChallenges = append(q.userWebAuthnChallenges[:i], q.userWebAuthnChallenges[i+1:]...)
+			return challenge, nil
+		}
+	}
+	return database.UserWebAuthnChallenge{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteExpiredUserWebAuthnChallenges(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := database.Now()
+	challenges := q.userWebAuthnChallenges[:0]
+	for _, challenge := range q.userWebAuthnChallenges {
+		if challenge.ExpiresAt.Before(now) {
+			continue
+		}
+		challenges = append(challenges, challenge)
+	}
+	q.userWebAuthnChallenges = challenges
+	return nil
+}
+
+func (q *fakeQuerier) GetServiceAccountByUserID(_ context.Context, userID uuid.UUID) (database.ServiceAccount, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, account := range q.serviceAccounts {
+		if account.UserID == userID {
+			return account, nil
+		}
+	}
+	return database.ServiceAccount{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) InsertServiceAccount(_ context.Context, arg database.InsertServiceAccountParams) (database.ServiceAccount, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ServiceAccount{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, account := range q.serviceAccounts {
+		if account.UserID == arg.UserID {
+			return database.ServiceAccount{}, errDuplicateKey
+		}
+	}
+
+	account := database.ServiceAccount{
+		UserID:       arg.UserID,
+		OwnerUserID:  arg.OwnerUserID,
+		OwnerGroupID: arg.OwnerGroupID,
+		CreatedAt:    arg.CreatedAt,
+	}
+	q.serviceAccounts = append(q.serviceAccounts, account)
+	return account, nil
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderApps(_ context.Context) ([]database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	apps := slices.Clone(q.oauth2ProviderApps)
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Name != apps[j].Name {
+			return apps[i].Name < apps[j].Name
+		}
+		return apps[i].ID.String() < apps[j].ID.String()
+	})
+	return apps, nil
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == id {
+			return app, nil
+		}
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppsByUserID(_ context.Context, userID uuid.UUID) ([]database.OAuth2ProviderApp, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	appIDs := map[uuid.UUID]struct{}{}
+	for _, token := range q.oauth2ProviderAppTokens {
+		key, err := q.getAPIKeyByIDNoLock(token.APIKeyID)
+		if err != nil || key.UserID != userID {
+			continue
+		}
+		for _, secret := range q.oauth2ProviderAppSecrets {
+			if secret.ID == token.AppSecretID {
+				appIDs[secret.AppID] = struct{}{}
+			}
+		}
+	}
+
+	apps := make([]database.OAuth2ProviderApp, 0)
+	for _, app := range q.oauth2ProviderApps {
+		if _, ok := appIDs[app.ID]; ok {
+			apps = append(apps, app)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Name != apps[j].Name {
+			return apps[i].Name < apps[j].Name
+		}
+		return apps[i].ID.String() < apps[j].ID.String()
+	})
+	return apps, nil
+}
+
+func (q *fakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.Name == arg.Name {
+			return database.OAuth2ProviderApp{}, &pq.Error{
+				Code:       "23505",
+				Message:    "duplicate key value violates unique constraint",
+				Constraint: string(database.UniqueOauth2ProviderAppsNameKey),
+			}
+		}
+	}
+
+	//nolint:gosimple
+	app := database.OAuth2ProviderApp{
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		UpdatedAt:    arg.UpdatedAt,
+		Name:         arg.Name,
+		Icon:         arg.Icon,
+		RedirectURIs: arg.RedirectURIs,
+	}
+	q.oauth2ProviderApps = append(q.oauth2ProviderApps, app)
+	return app, nil
+}
+
+func (q *fakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OAuth2ProviderApp{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.Name == arg.Name && app.ID != arg.ID {
+			return database.OAuth2ProviderApp{}, &pq.Error{
+				Code:       "23505",
+				Message:    "duplicate key value violates unique constraint",
+				Constraint: string(database.UniqueOauth2ProviderAppsNameKey),
+			}
+		}
+	}
+
+	for i, app := range q.oauth2ProviderApps {
+		if app.ID == arg.ID {
+			app.UpdatedAt = arg.UpdatedAt
+			app.Name = arg.Name
+			app.Icon = arg.Icon
+			app.RedirectURIs = arg.RedirectURIs
+			q.oauth2ProviderApps[i] = app
+			return app, nil
+		}
+	}
+	return database.OAuth2ProviderApp{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	index := -1
+	for i, app := range q.oauth2ProviderApps {
+		if app.ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return sql.ErrNoRows
+	}
+	q.oauth2ProviderApps = append(q.oauth2ProviderApps[:index], q.oauth2ProviderApps[index+1:]...)
+
+	for i := len(q.oauth2ProviderAppCodes) - 1; i >= 0; i-- {
+		if q.oauth2ProviderAppCodes[i].AppID == id {
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes[:i], q.oauth2ProviderAppCodes[i+1:]...)
+		}
+	}
+	for i := len(q.oauth2ProviderAppSecrets) - 1; i >= 0; i-- {
+		secret := q.oauth2ProviderAppSecrets[i]
+		if secret.AppID == id {
+			q.deleteOAuth2ProviderAppSecretNoLock(secret.ID)
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppSecretByPrefix(_ context.Context, secretPrefix string) (database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.SecretPrefix == secretPrefix {
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppSecretsByAppID(_ context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	secrets := make([]database.OAuth2ProviderAppSecret, 0)
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID == appID {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].CreatedAt.Before(secrets[j].CreatedAt)
+	})
+	return secrets, nil
+}
+
+func (q *fakeQuerier) InsertOAuth2ProviderAppSecret(_ context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.SecretPrefix == arg.SecretPrefix {
+			return database.OAuth2ProviderAppSecret{}, errDuplicateKey
+		}
+	}
+
+	secret := database.OAuth2ProviderAppSecret{
+		ID:            arg.ID,
+		AppID:         arg.AppID,
+		CreatedAt:     arg.CreatedAt,
+		SecretPrefix:  arg.SecretPrefix,
+		HashedSecret:  arg.HashedSecret,
+		DisplaySecret: arg.DisplaySecret,
+	}
+	q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets, secret)
+	return secret, nil
+}
+
+func (q *fakeQuerier) UpdateOAuth2ProviderAppSecretByID(_ context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OAuth2ProviderAppSecret{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == arg.ID {
+			secret.LastUsedAt = arg.LastUsedAt
+			q.oauth2ProviderAppSecrets[i] = secret
+			return secret, nil
+		}
+	}
+	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
 }
 
-func (q *fakeQuerier) GetLicenses(_ context.Context) ([]database.License, error) {
+func (q *fakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !q.deleteOAuth2ProviderAppSecretNoLock(id) {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// deleteOAuth2ProviderAppSecretNoLock deletes the secret along with its
+// tokens and the API keys they refresh.
+func (q *fakeQuerier) deleteOAuth2ProviderAppSecretNoLock(id uuid.UUID) bool {
+	deleted := false
+	for i, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == id {
+			q.oauth2ProviderAppSecrets = append(q.oauth2ProviderAppSecrets[:i], q.oauth2ProviderAppSecrets[i+1:]...)
+			deleted = true
+			break
+		}
+	}
+	for i := len(q.oauth2ProviderAppTokens) - 1; i >= 0; i-- {
+		token := q.oauth2ProviderAppTokens[i]
+		if token.AppSecretID != id {
+			continue
+		}
+		q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens[:i], q.oauth2ProviderAppTokens[i+1:]...)
+		for j, key := range q.apiKeys {
+			if key.ID == token.APIKeyID {
+				q.apiKeys = append(q.apiKeys[:j], q.apiKeys[j+1:]...)
+				break
+			}
+		}
+	}
+	return deleted
+}
+
+func (q *fakeQuerier) GetOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	results := append([]database.License{}, q.licenses...)
-	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
-	return results, nil
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
 }
 
-func (q *fakeQuerier) GetUnexpiredLicenses(_ context.Context) ([]database.License, error) {
+func (q *fakeQuerier) GetOAuth2ProviderAppCodeByPrefix(_ context.Context, secretPrefix string) (database.OAuth2ProviderAppCode, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	now := time.Now()
-	var results []database.License
-	for _, l := range q.licenses {
-		if l.Exp.After(now) {
-			results = append(results, l)
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.SecretPrefix == secretPrefix {
+			return code, nil
 		}
 	}
-	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
-	return results, nil
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
 }
 
-func (q *fakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error) {
+func (q *fakeQuerier) InsertOAuth2ProviderAppCode(_ context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OAuth2ProviderAppCode{}, err
+	}
+
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for index, l := range q.licenses {
-		if l.ID == id {
-			q.licenses[index] = q.licenses[len(q.licenses)-1]
-			q.licenses = q.licenses[:len(q.licenses)-1]
-			return id, nil
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.SecretPrefix == arg.SecretPrefix {
+			return database.OAuth2ProviderAppCode{}, errDuplicateKey
 		}
 	}
-	return 0, sql.ErrNoRows
-}
 
-func (*fakeQuerier) DeleteOldWorkspaceAgentStartupLogs(_ context.Context) error {
-	// noop
-	return nil
+	//nolint:gosimple
+	code := database.OAuth2ProviderAppCode{
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		ExpiresAt:    arg.ExpiresAt,
+		SecretPrefix: arg.SecretPrefix,
+		HashedSecret: arg.HashedSecret,
+		UserID:       arg.UserID,
+		AppID:        arg.AppID,
+		Scope:        arg.Scope,
+		RedirectURI:  arg.RedirectURI,
+	}
+	q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+	return code, nil
 }
 
-func (q *fakeQuerier) GetUserLinkByLinkedID(_ context.Context, id string) (database.UserLink, error) {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
+func (q *fakeQuerier) DeleteOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 
-	for _, link := range q.userLinks {
-		if link.LinkedID == id {
-			return link, nil
+	for i, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes[:i], q.oauth2ProviderAppCodes[i+1:]...)
+			return id, nil
 		}
 	}
-	return database.UserLink{}, sql.ErrNoRows
+	return uuid.Nil, sql.ErrNoRows
 }
 
-func (q *fakeQuerier) GetUserLinkByUserIDLoginType(_ context.Context, params database.GetUserLinkByUserIDLoginTypeParams) (database.UserLink, error) {
-	if err := validateDatabaseType(params); err != nil {
-		return database.UserLink{}, err
-	}
-
+func (q *fakeQuerier) GetOAuth2ProviderAppTokenByPrefix(_ context.Context, refreshPrefix string) (database.OAuth2ProviderAppToken, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	for _, link := range q.userLinks {
-		if link.UserID == params.UserID && link.LoginType == params.LoginType {
-			return link, nil
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.RefreshPrefix != refreshPrefix {
+			continue
+		}
+		// Tokens are deleted along with their API key.
+		if _, err := q.getAPIKeyByIDNoLock(token.APIKeyID); err != nil {
+			return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
 		}
+		return token, nil
 	}
-	return database.UserLink{}, sql.ErrNoRows
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
 }
 
-func (q *fakeQuerier) InsertUserLink(_ context.Context, args database.InsertUserLinkParams) (database.UserLink, error) {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
-
-	//nolint:gosimple
-	link := database.UserLink{
-		UserID:            args.UserID,
-		LoginType:         args.LoginType,
-		LinkedID:          args.LinkedID,
-		OAuthAccessToken:  args.OAuthAccessToken,
-		OAuthRefreshToken: args.OAuthRefreshToken,
-		OAuthExpiry:       args.OAuthExpiry,
+func (q *fakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
 	}
 
-	q.userLinks = append(q.userLinks, link)
-
-	return link, nil
-}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 
-func (q *fakeQuerier) UpdateUserLinkedID(_ context.Context, params database.UpdateUserLinkedIDParams) (database.UserLink, error) {
-	if err := validateDatabaseType(params); err != nil {
-		return database.UserLink{}, err
+	for _, token := range q.oauth2ProviderAppTokens {
+		if token.RefreshPrefix == arg.RefreshPrefix {
+			return database.OAuth2ProviderAppToken{}, errDuplicateKey
+		}
 	}
 
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
+	//nolint:gosimple
+	token := database.OAuth2ProviderAppToken{
+		ID:                  arg.ID,
+		CreatedAt:           arg.CreatedAt,
+		ExpiresAt:           arg.ExpiresAt,
+		RefreshPrefix:       arg.RefreshPrefix,
+		HashedRefreshSecret: arg.HashedRefreshSecret,
+		AppSecretID:         arg.AppSecretID,
+		APIKeyID:            arg.APIKeyID,
+	}
+	q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
+	return token, nil
+}
 
-	for i, link := range q.userLinks {
-		if link.UserID == params.UserID && link.LoginType == params.LoginType {
-			link.LinkedID = params.LinkedID
+func (q *fakeQuerier) DeleteOAuth2ProviderAppTokenByPrefix(_ context.Context, refreshPrefix string) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 
-			q.userLinks[i] = link
-			return link, nil
+	for i, token := range q.oauth2ProviderAppTokens {
+		if token.RefreshPrefix == refreshPrefix {
+			q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens[:i], q.oauth2ProviderAppTokens[i+1:]...)
+			return token, nil
 		}
 	}
-
-	return database.UserLink{}, sql.ErrNoRows
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
 }
 
-func (q *fakeQuerier) UpdateUserLink(_ context.Context, params database.UpdateUserLinkParams) (database.UserLink, error) {
-	if err := validateDatabaseType(params); err != nil {
-		return database.UserLink{}, err
+func (q *fakeQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(_ context.Context, arg database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
 	}
 
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
-
-	for i, link := range q.userLinks {
-		if link.UserID == params.UserID && link.LoginType == params.LoginType {
-			link.OAuthAccessToken = params.OAuthAccessToken
-			link.OAuthRefreshToken = params.OAuthRefreshToken
-			link.OAuthExpiry = params.OAuthExpiry
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
 
-			q.userLinks[i] = link
-			return link, nil
+	secretIDs := map[uuid.UUID]struct{}{}
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.AppID == arg.AppID {
+			secretIDs[secret.ID] = struct{}{}
 		}
 	}
-
-	return database.UserLink{}, sql.ErrNoRows
+	for i := len(q.oauth2ProviderAppTokens) - 1; i >= 0; i-- {
+		token := q.oauth2ProviderAppTokens[i]
+		if _, ok := secretIDs[token.AppSecretID]; !ok {
+			continue
+		}
+		for j, key := range q.apiKeys {
+			if key.ID == token.APIKeyID && key.UserID == arg.UserID {
+				q.apiKeys = append(q.apiKeys[:j], q.apiKeys[j+1:]...)
+				q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens[:i], q.oauth2ProviderAppTokens[i+1:]...)
+				break
+			}
+		}
+	}
+	return nil
 }
 
 func (q *fakeQuerier) GetGroupByID(_ context.Context, id uuid.UUID) (database.Group, error) {
@@ -4807,6 +6105,235 @@ func (q *fakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.
 	return replicas, nil
 }
 
+func (q *fakeQuerier) UpsertTailnetCoordinator(_ context.Context, id uuid.UUID) (database.TailnetCoordinator, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	coordinator := database.TailnetCoordinator{
+		ID:          id,
+		HeartbeatAt: database.Now(),
+	}
+	for i, existing := range q.tailnetCoordinators {
+		if existing.ID == id {
+			q.tailnetCoordinators[i] = coordinator
+			return coordinator, nil
+		}
+	}
+	q.tailnetCoordinators = append(q.tailnetCoordinators, coordinator)
+	return coordinator, nil
+}
+
+func (q *fakeQuerier) DeleteTailnetCoordinator(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.deleteTailnetCoordinatorsNoLock(func(coordinator database.TailnetCoordinator) bool {
+		return coordinator.ID == id
+	})
+	return nil
+}
+
+func (q *fakeQuerier) DeleteTailnetAgentVersionsUpdatedBefore(ctx context.Context, updatedBefore time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	versions := make([]database.TailnetAgentVersion, 0, len(q.tailnetAgentVersions))
+	for _, version := range q.tailnetAgentVersions {
+		if !version.UpdatedAt.Before(updatedBefore) || q.tailnetAgentActiveNoLock(ctx, version.AgentID) {
+			versions = append(versions, version)
+		}
+	}
+	q.tailnetAgentVersions = versions
+	return nil
+}
+
+// tailnetAgentActiveNoLock returns whether the agent has nodes or is in the
+// latest build of a workspace.
+func (q *fakeQuerier) tailnetAgentActiveNoLock(ctx context.Context, agentID uuid.UUID) bool {
+	for _, node := range q.tailnetNodes {
+		if node.AgentID == agentID {
+			return true
+		}
+	}
+	for _, agent := range q.workspaceAgents {
+		if agent.ID != agentID {
+			continue
+		}
+		for _, resource := range q.workspaceResources {
+			if resource.ID != agent.ResourceID {
+				continue
+			}
+			for _, build := range q.workspaceBuilds {
+				if build.JobID != resource.JobID {
+					continue
+				}
+				latest, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, build.WorkspaceID)
+				if err == nil && latest.ID == build.ID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (q *fakeQuerier) DeleteTailnetCoordinatorsHeartbeatBefore(_ context.Context, heartbeatAt time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.deleteTailnetCoordinatorsNoLock(func(coordinator database.TailnetCoordinator) bool {
+		return coordinator.HeartbeatAt.Before(heartbeatAt)
+	})
+	return nil
+}
+
+// deleteTailnetCoordinatorsNoLock deletes the matching coordinators and
+// cascades to their nodes.
+func (q *fakeQuerier) deleteTailnetCoordinatorsNoLock(match func(coordinator database.TailnetCoordinator) bool) {
+	deleted := map[uuid.UUID]struct{}{}
+	coordinators := make([]database.TailnetCoordinator, 0, len(q.tailnetCoordinators))
+	for _, coordinator := range q.tailnetCoordinators {
+		if match(coordinator) {
+			deleted[coordinator.ID] = struct{}{}
+			continue
+		}
+		coordinators = append(coordinators, coordinator)
+	}
+	q.tailnetCoordinators = coordinators
+
+	nodes := make([]database.TailnetNode, 0, len(q.tailnetNodes))
+	for _, node := range q.tailnetNodes {
+		if _, ok := deleted[node.CoordinatorID]; ok {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	q.tailnetNodes = nodes
+}
+
+func (q *fakeQuerier) UpsertTailnetAgentNode(_ context.Context, arg database.UpsertTailnetAgentNodeParams) (database.TailnetNode, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TailnetNode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.upsertTailnetNodeNoLock(arg.AgentID, arg.AgentID, arg.CoordinatorID, arg.Node, false)
+}
+
+func (q *fakeQuerier) UpsertTailnetClientNode(_ context.Context, arg database.UpsertTailnetClientNodeParams) (database.TailnetNode, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TailnetNode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.upsertTailnetNodeNoLock(arg.ID, arg.AgentID, arg.CoordinatorID, arg.Node, true)
+}
+
+func (q *fakeQuerier) upsertTailnetNodeNoLock(id, agentID, coordinatorID uuid.UUID, node json.RawMessage, peer bool) (database.TailnetNode, error) {
+	coordinatorExists := false
+	for _, coordinator := range q.tailnetCoordinators {
+		if coordinator.ID == coordinatorID {
+			coordinatorExists = true
+			break
+		}
+	}
+	if !coordinatorExists {
+		return database.TailnetNode{}, errForeignKey
+	}
+
+	versionIndex := -1
+	for i, version := range q.tailnetAgentVersions {
+		if version.AgentID == agentID {
+			versionIndex = i
+			break
+		}
+	}
+	if versionIndex == -1 {
+		q.tailnetAgentVersions = append(q.tailnetAgentVersions, database.TailnetAgentVersion{AgentID: agentID})
+		versionIndex = len(q.tailnetAgentVersions) - 1
+	}
+	q.tailnetAgentVersions[versionIndex].UpdatedAt = database.Now()
+	var version int64
+	if peer {
+		q.tailnetAgentVersions[versionIndex].PeerVersion++
+		version = q.tailnetAgentVersions[versionIndex].PeerVersion
+	} else {
+		q.tailnetAgentVersions[versionIndex].NodeVersion++
+		version = q.tailnetAgentVersions[versionIndex].NodeVersion
+	}
+
+	tailnetNode := database.TailnetNode{
+		ID:            id,
+		CoordinatorID: coordinatorID,
+		AgentID:       agentID,
+		Node:          node,
+		Version:       version,
+		UpdatedAt:     database.Now(),
+	}
+	for i, existing := range q.tailnetNodes {
+		if existing.ID == id {
+			q.tailnetNodes[i] = tailnetNode
+			return tailnetNode, nil
+		}
+	}
+	q.tailnetNodes = append(q.tailnetNodes, tailnetNode)
+	return tailnetNode, nil
+}
+
+func (q *fakeQuerier) GetTailnetAgentNode(_ context.Context, agentID uuid.UUID) (database.TailnetNode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, node := range q.tailnetNodes {
+		if node.ID == agentID && node.AgentID == agentID {
+			return node, nil
+		}
+	}
+	return database.TailnetNode{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetTailnetClientNodesAfterVersion(_ context.Context, arg database.GetTailnetClientNodesAfterVersionParams) ([]database.TailnetNode, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	nodes := make([]database.TailnetNode, 0)
+	for _, node := range q.tailnetNodes {
+		if node.AgentID != arg.AgentID || node.ID == arg.AgentID || node.Version <= arg.AfterVersion {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Version < nodes[j].Version
+	})
+	return nodes, nil
+}
+
+func (q *fakeQuerier) DeleteTailnetNode(_ context.Context, arg database.DeleteTailnetNodeParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, node := range q.tailnetNodes {
+		if node.ID == arg.ID && node.CoordinatorID == arg.CoordinatorID {
+			q.tailnetNodes = append(q.tailnetNodes[:i], q.tailnetNodes[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *fakeQuerier) GetGitAuthLink(_ context.Context, arg database.GetGitAuthLinkParams) (database.GitAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.GitAuthLink{}, err
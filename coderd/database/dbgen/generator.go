@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/tabbed/pqtype"
 
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbtype"
 	"github.com/coder/coder/cryptorand"
 )
 
@@ -91,6 +93,7 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
 		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
 		TokenName:       takeFirst(seed.TokenName),
+		CustomScopes:    takeFirstSlice(seed.CustomScopes, []string{}),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -251,17 +254,18 @@ func GroupMember(t testing.TB, db database.Store, orig database.GroupMember) dat
 
 func ProvisionerJob(t testing.TB, db database.Store, orig database.ProvisionerJob) database.ProvisionerJob {
 	job, err := db.InsertProvisionerJob(context.Background(), database.InsertProvisionerJobParams{
-		ID:             takeFirst(orig.ID, uuid.New()),
-		CreatedAt:      takeFirst(orig.CreatedAt, database.Now()),
-		UpdatedAt:      takeFirst(orig.UpdatedAt, database.Now()),
-		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
-		InitiatorID:    takeFirst(orig.InitiatorID, uuid.New()),
-		Provisioner:    takeFirst(orig.Provisioner, database.ProvisionerTypeEcho),
-		StorageMethod:  takeFirst(orig.StorageMethod, database.ProvisionerStorageMethodFile),
-		FileID:         takeFirst(orig.FileID, uuid.New()),
-		Type:           takeFirst(orig.Type, database.ProvisionerJobTypeWorkspaceBuild),
-		Input:          takeFirstSlice(orig.Input, []byte("{}")),
-		Tags:           orig.Tags,
+		ID:                takeFirst(orig.ID, uuid.New()),
+		CreatedAt:         takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt:         takeFirst(orig.UpdatedAt, database.Now()),
+		OrganizationID:    takeFirst(orig.OrganizationID, uuid.New()),
+		InitiatorID:       takeFirst(orig.InitiatorID, uuid.New()),
+		Provisioner:       takeFirst(orig.Provisioner, database.ProvisionerTypeEcho),
+		StorageMethod:     takeFirst(orig.StorageMethod, database.ProvisionerStorageMethodFile),
+		FileID:            takeFirst(orig.FileID, uuid.New()),
+		Type:              takeFirst(orig.Type, database.ProvisionerJobTypeWorkspaceBuild),
+		Input:             takeFirstSlice(orig.Input, []byte("{}")),
+		Tags:              orig.Tags,
+		HasMatchingDaemon: orig.HasMatchingDaemon,
 	})
 	require.NoError(t, err, "insert job")
 	return job
@@ -295,6 +299,69 @@ func WorkspaceApp(t testing.TB, db database.Store, orig database.WorkspaceApp) d
 	return resource
 }
 
+func WorkspacePortShare(t testing.TB, db database.Store, orig database.WorkspacePortShare) database.WorkspacePortShare {
+	share, err := db.UpsertWorkspacePortShare(context.Background(), database.UpsertWorkspacePortShareParams{
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentName:   takeFirst(orig.AgentName, namesgenerator.GetRandomName(1)),
+		Port:        takeFirst(orig.Port, 8080),
+		ShareLevel:  takeFirst(orig.ShareLevel, database.AppSharingLevelAuthenticated),
+		CreatedAt:   takeFirst(orig.CreatedAt, database.Now()),
+		ExpiresAt:   orig.ExpiresAt,
+	})
+	require.NoError(t, err, "insert port share")
+	return share
+}
+
+func TemplateAppLimits(t testing.TB, db database.Store, orig database.TemplateAppLimit) database.TemplateAppLimit {
+	limits, err := db.UpsertTemplateAppLimits(context.Background(), database.UpsertTemplateAppLimitsParams{
+		TemplateID:                       takeFirst(orig.TemplateID, uuid.New()),
+		AppRequestsPerMinute:             takeFirst(orig.AppRequestsPerMinute, 600),
+		AppBandwidthBytesPerSecond:       orig.AppBandwidthBytesPerSecond,
+		WorkspaceRequestsPerMinute:       orig.WorkspaceRequestsPerMinute,
+		WorkspaceBandwidthBytesPerSecond: orig.WorkspaceBandwidthBytesPerSecond,
+		UpdatedAt:                        takeFirst(orig.UpdatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert template app limits")
+	return limits
+}
+
+func TemplateAppRouting(t testing.TB, db database.Store, orig database.TemplateAppRouting) database.TemplateAppRouting {
+	routing, err := db.UpsertTemplateAppRouting(context.Background(), database.UpsertTemplateAppRoutingParams{
+		TemplateID:            takeFirst(orig.TemplateID, uuid.New()),
+		AppSlug:               takeFirst(orig.AppSlug, namesgenerator.GetRandomName(1)),
+		StripPrefix:           orig.StripPrefix,
+		BasePathHeader:        orig.BasePathHeader,
+		Headers:               takeFirstMap(orig.Headers, dbtype.StringMap{}),
+		AllowedMethods:        takeFirstSlice(orig.AllowedMethods, []string{}),
+		WebsocketSubprotocols: takeFirstSlice(orig.WebsocketSubprotocols, []string{}),
+		UpdatedAt:             takeFirst(orig.UpdatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert template app routing")
+	return routing
+}
+
+func WorkspaceAppAccessLog(t testing.TB, db database.Store, orig database.WorkspaceAppAccessLog) database.WorkspaceAppAccessLog {
+	accessLog := database.WorkspaceAppAccessLog{
+		ID:            takeFirst(orig.ID, uuid.New()),
+		CreatedAt:     takeFirst(orig.CreatedAt, database.Now()),
+		UserID:        orig.UserID,
+		WorkspaceID:   takeFirst(orig.WorkspaceID, uuid.NullUUID{UUID: uuid.New(), Valid: true}),
+		AgentID:       takeFirst(orig.AgentID, uuid.New()),
+		AppSlugOrPort: takeFirst(orig.AppSlugOrPort, namesgenerator.GetRandomName(1)),
+		AccessMethod:  takeFirst(orig.AccessMethod, "path"),
+		SharingLevel:  takeFirst(orig.SharingLevel, database.AppSharingLevelOwner),
+		Ip:            orig.Ip,
+		Method:        takeFirst(orig.Method, http.MethodGet),
+		Path:          takeFirst(orig.Path, "/"),
+		StatusCode:    takeFirst(orig.StatusCode, http.StatusOK),
+		BytesWritten:  orig.BytesWritten,
+		DurationMs:    orig.DurationMs,
+	}
+	err := db.InsertWorkspaceAppAccessLog(context.Background(), database.InsertWorkspaceAppAccessLogParams(accessLog))
+	require.NoError(t, err, "insert workspace app access log")
+	return accessLog
+}
+
 func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceResource) database.WorkspaceResource {
 	resource, err := db.InsertWorkspaceResource(context.Background(), database.InsertWorkspaceResourceParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -353,6 +420,106 @@ func UserLink(t testing.TB, db database.Store, orig database.UserLink) database.
 	return link
 }
 
+func ServiceAccount(t testing.TB, db database.Store, orig database.ServiceAccount) database.ServiceAccount {
+	account, err := db.InsertServiceAccount(context.Background(), database.InsertServiceAccountParams{
+		UserID:       takeFirst(orig.UserID, uuid.New()),
+		OwnerUserID:  orig.OwnerUserID,
+		OwnerGroupID: orig.OwnerGroupID,
+		CreatedAt:    takeFirst(orig.CreatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert service account")
+	return account
+}
+
+func UserTOTP(t testing.TB, db database.Store, orig database.UserTOTP) database.UserTOTP {
+	totp, err := db.UpsertUserTOTP(context.Background(), database.UpsertUserTOTPParams{
+		UserID:              takeFirst(orig.UserID, uuid.New()),
+		Secret:              takeFirst(orig.Secret, "JBSWY3DPEHPK3PXP"),
+		Confirmed:           takeFirst(orig.Confirmed),
+		HashedRecoveryCodes: takeFirstSlice(orig.HashedRecoveryCodes, []string{}),
+		LastUsedStep:        takeFirst(orig.LastUsedStep),
+		CreatedAt:           takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt:           takeFirst(orig.UpdatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert totp")
+	return totp
+}
+
+func UserWebAuthnCredential(t testing.TB, db database.Store, orig database.UserWebAuthnCredential) database.UserWebAuthnCredential {
+	id := uuid.New()
+	credential, err := db.InsertUserWebAuthnCredential(context.Background(), database.InsertUserWebAuthnCredentialParams{
+		ID:           takeFirst(orig.ID, id),
+		UserID:       takeFirst(orig.UserID, uuid.New()),
+		CredentialID: takeFirstSlice(orig.CredentialID, id[:]),
+		Name:         takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		PublicKey:    takeFirstSlice(orig.PublicKey, []byte("public-key")),
+		SignCount:    takeFirst(orig.SignCount),
+		CreatedAt:    takeFirst(orig.CreatedAt, database.Now()),
+		LastUsedAt:   takeFirst(orig.LastUsedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert webauthn credential")
+	return credential
+}
+
+func OAuth2ProviderApp(t testing.TB, db database.Store, seed database.OAuth2ProviderApp) database.OAuth2ProviderApp {
+	app, err := db.InsertOAuth2ProviderApp(context.Background(), database.InsertOAuth2ProviderAppParams{
+		ID:           takeFirst(seed.ID, uuid.New()),
+		CreatedAt:    takeFirst(seed.CreatedAt, database.Now()),
+		UpdatedAt:    takeFirst(seed.UpdatedAt, database.Now()),
+		Name:         takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		Icon:         takeFirst(seed.Icon, ""),
+		RedirectURIs: takeFirstSlice(seed.RedirectURIs, []string{"http://localhost"}),
+	})
+	require.NoError(t, err, "insert oauth2 app")
+	return app
+}
+
+func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAuth2ProviderAppSecret) database.OAuth2ProviderAppSecret {
+	prefix, _ := cryptorand.String(10)
+	secret, err := db.InsertOAuth2ProviderAppSecret(context.Background(), database.InsertOAuth2ProviderAppSecretParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, database.Now()),
+		SecretPrefix:  takeFirst(seed.SecretPrefix, prefix),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		DisplaySecret: takeFirst(seed.DisplaySecret, "secret"),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+	})
+	require.NoError(t, err, "insert oauth2 app secret")
+	return secret
+}
+
+func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
+	prefix, _ := cryptorand.String(10)
+	code, err := db.InsertOAuth2ProviderAppCode(context.Background(), database.InsertOAuth2ProviderAppCodeParams{
+		ID:           takeFirst(seed.ID, uuid.New()),
+		CreatedAt:    takeFirst(seed.CreatedAt, database.Now()),
+		ExpiresAt:    takeFirst(seed.ExpiresAt, database.Now().Add(time.Minute)),
+		SecretPrefix: takeFirst(seed.SecretPrefix, prefix),
+		HashedSecret: takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		UserID:       takeFirst(seed.UserID, uuid.New()),
+		AppID:        takeFirst(seed.AppID, uuid.New()),
+		Scope:        takeFirst(seed.Scope, database.APIKeyScopeAll),
+		RedirectURI:  takeFirst(seed.RedirectURI, "http://localhost"),
+	})
+	require.NoError(t, err, "insert oauth2 app code")
+	return code
+}
+
+func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth2ProviderAppToken) database.OAuth2ProviderAppToken {
+	prefix, _ := cryptorand.String(10)
+	token, err := db.InsertOAuth2ProviderAppToken(context.Background(), database.InsertOAuth2ProviderAppTokenParams{
+		ID:                  takeFirst(seed.ID, uuid.New()),
+		CreatedAt:           takeFirst(seed.CreatedAt, database.Now()),
+		ExpiresAt:           takeFirst(seed.ExpiresAt, database.Now().Add(time.Hour)),
+		RefreshPrefix:       takeFirst(seed.RefreshPrefix, prefix),
+		HashedRefreshSecret: takeFirstSlice(seed.HashedRefreshSecret, []byte("hashed-secret")),
+		AppSecretID:         takeFirst(seed.AppSecretID, uuid.New()),
+		APIKeyID:            takeFirst(seed.APIKeyID),
+	})
+	require.NoError(t, err, "insert oauth2 app token")
+	return token
+}
+
 func GitAuthLink(t testing.TB, db database.Store, orig database.GitAuthLink) database.GitAuthLink {
 	link, err := db.InsertGitAuthLink(context.Background(), database.InsertGitAuthLinkParams{
 		ProviderID:        takeFirst(orig.ProviderID, uuid.New().String()),
@@ -68,6 +68,45 @@ func TestGenerator(t *testing.T) {
 		require.Equal(t, exp, must(db.GetWorkspaceAppsByAgentID(context.Background(), exp.AgentID))[0])
 	})
 
+	t.Run("WorkspacePortShare", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		exp := dbgen.WorkspacePortShare(t, db, database.WorkspacePortShare{})
+		require.Equal(t, exp, must(db.GetWorkspacePortShare(context.Background(), database.GetWorkspacePortShareParams{
+			WorkspaceID: exp.WorkspaceID,
+			AgentName:   exp.AgentName,
+			Port:        exp.Port,
+		})))
+	})
+
+	t.Run("TemplateAppLimits", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		exp := dbgen.TemplateAppLimits(t, db, database.TemplateAppLimit{})
+		require.Equal(t, exp, must(db.GetTemplateAppLimits(context.Background(), exp.TemplateID)))
+	})
+
+	t.Run("TemplateAppRouting", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		exp := dbgen.TemplateAppRouting(t, db, database.TemplateAppRouting{})
+		require.Equal(t, exp, must(db.GetTemplateAppRouting(context.Background(), database.GetTemplateAppRoutingParams{
+			TemplateID: exp.TemplateID,
+			AppSlug:    exp.AppSlug,
+		})))
+	})
+
+	t.Run("WorkspaceAppAccessLog", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		exp := dbgen.WorkspaceAppAccessLog(t, db, database.WorkspaceAppAccessLog{})
+		logs := must(db.GetWorkspaceAppAccessLogs(context.Background(), database.GetWorkspaceAppAccessLogsParams{
+			WorkspaceID: exp.WorkspaceID.UUID,
+		}))
+		require.Len(t, logs, 1)
+		require.Equal(t, exp.ID, logs[0].ID)
+	})
+
 	t.Run("WorkspaceResourceMetadata", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
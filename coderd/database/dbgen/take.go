@@ -16,6 +16,14 @@ func takeFirstSlice[T any](values ...[]T) []T {
 	})
 }
 
+// takeFirstMap implements takeFirst for maps.
+// Maps are not a comparable type.
+func takeFirstMap[K comparable, V any, M ~map[K]V](values ...M) M {
+	return takeFirstF(values, func(v M) bool {
+		return len(v) != 0
+	})
+}
+
 // takeFirstF takes the first value that returns true
 func takeFirstF[Value any](values []Value, take func(v Value) bool) Value {
 	for _, v := range values {
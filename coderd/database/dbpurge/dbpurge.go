@@ -16,7 +16,9 @@ import (
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
+// Workspace app access logs older than appAccessLogRetention are deleted, unless
+// it is zero.
+func New(ctx context.Context, logger slog.Logger, db database.Store, appAccessLogRetention time.Duration) io.Closer {
 	closed := make(chan struct{})
 	ctx, cancelFunc := context.WithCancel(ctx)
 	go func() {
@@ -37,6 +39,14 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 			eg.Go(func() error {
 				return db.DeleteOldWorkspaceAgentStats(ctx)
 			})
+			eg.Go(func() error {
+				return db.DeleteExpiredUserWebAuthnChallenges(ctx)
+			})
+			if appAccessLogRetention > 0 {
+				eg.Go(func() error {
+					return db.DeleteOldWorkspaceAppAccessLogs(ctx, database.Now().Add(-appAccessLogRetention))
+				})
+			}
 			err := eg.Wait()
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
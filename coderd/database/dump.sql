@@ -2,7 +2,8 @@
 
 CREATE TYPE api_key_scope AS ENUM (
     'all',
-    'application_connect'
+    'application_connect',
+    'custom'
 );
 
 CREATE TYPE app_sharing_level AS ENUM (
@@ -44,7 +45,10 @@ CREATE TYPE login_type AS ENUM (
     'password',
     'github',
     'oidc',
-    'token'
+    'token',
+    'saml',
+    'ldap',
+    'none'
 );
 
 CREATE TYPE parameter_destination_scheme AS ENUM (
@@ -121,6 +125,15 @@ CREATE TYPE workspace_app_health AS ENUM (
     'unhealthy'
 );
 
+CREATE TYPE workspace_build_stage AS ENUM (
+    'queued',
+    'init',
+    'plan',
+    'apply',
+    'agent_connect',
+    'startup_script'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
@@ -139,11 +152,14 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    custom_scopes text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.custom_scopes IS 'Fine-grained scope entries of keys with the custom scope, of the form <resource type>:<action>[:<resource id>].';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -222,6 +238,59 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE oauth2_provider_app_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    secret_prefix text NOT NULL,
+    hashed_secret bytea NOT NULL,
+    user_id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    scope api_key_scope NOT NULL,
+    redirect_uri text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes that a user approved and an app has yet to exchange for tokens.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+    id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone,
+    secret_prefix text NOT NULL,
+    hashed_secret bytea NOT NULL,
+    display_secret text NOT NULL
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail of the secret, which lets admins tell secrets apart without revealing them.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    refresh_prefix text NOT NULL,
+    hashed_refresh_secret bytea NOT NULL,
+    app_secret_id uuid NOT NULL,
+    api_key_id text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'Refresh tokens of the API keys issued to apps. Deleting the API key revokes the refresh token.';
+
+COMMENT ON COLUMN oauth2_provider_app_tokens.expires_at IS 'When the refresh token expires. The access token expires with its API key.';
+
+CREATE TABLE oauth2_provider_apps (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name character varying(64) NOT NULL,
+    icon character varying(256) NOT NULL,
+    redirect_uris text[] NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Applications that may obtain API keys of users through the OAuth2 authorization code flow.';
+
+COMMENT ON COLUMN oauth2_provider_apps.redirect_uris IS 'Authorization codes are only sent to one of these URIs.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -277,9 +346,22 @@ CREATE TABLE provisioner_daemons (
     name character varying(64) NOT NULL,
     provisioners provisioner_type[] NOT NULL,
     replica_id uuid,
-    tags jsonb DEFAULT '{}'::jsonb NOT NULL
+    tags jsonb DEFAULT '{}'::jsonb NOT NULL,
+    last_seen_at timestamp with time zone,
+    version text DEFAULT ''::text NOT NULL,
+    hostname text DEFAULT ''::text NOT NULL,
+    operating_system text DEFAULT ''::text NOT NULL,
+    architecture text DEFAULT ''::text NOT NULL,
+    concurrency integer DEFAULT 0 NOT NULL,
+    current_job_id uuid
 );
 
+COMMENT ON COLUMN provisioner_daemons.last_seen_at IS 'The last time the daemon sent a heartbeat, null if it never has';
+
+COMMENT ON COLUMN provisioner_daemons.concurrency IS 'The number of jobs the daemon can run at once';
+
+COMMENT ON COLUMN provisioner_daemons.current_job_id IS 'The job the daemon reported running in its last heartbeat';
+
 CREATE TABLE provisioner_job_logs (
     job_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -287,9 +369,21 @@ CREATE TABLE provisioner_job_logs (
     level log_level NOT NULL,
     stage character varying(128) NOT NULL,
     output character varying(1024) NOT NULL,
-    id bigint NOT NULL
+    id bigint NOT NULL,
+    resource_address text DEFAULT ''::text NOT NULL,
+    resource_action text DEFAULT ''::text NOT NULL,
+    resource_status text DEFAULT ''::text NOT NULL,
+    resource_elapsed_seconds integer DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN provisioner_job_logs.resource_address IS 'Address of the resource this log line reports progress for, empty if the line is not a progress event';
+
+COMMENT ON COLUMN provisioner_job_logs.resource_action IS 'Action being performed on the resource (e.g. create, update, delete, read)';
+
+COMMENT ON COLUMN provisioner_job_logs.resource_status IS 'Progress status of the resource action (started, progress, complete, errored)';
+
+COMMENT ON COLUMN provisioner_job_logs.resource_elapsed_seconds IS 'Seconds elapsed since the resource action started';
+
 CREATE SEQUENCE provisioner_job_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -316,9 +410,12 @@ CREATE TABLE provisioner_jobs (
     worker_id uuid,
     file_id uuid NOT NULL,
     tags jsonb DEFAULT '{"scope": "organization"}'::jsonb NOT NULL,
-    error_code text
+    error_code text,
+    has_matching_daemon boolean DEFAULT true NOT NULL
 );
 
+COMMENT ON COLUMN provisioner_jobs.has_matching_daemon IS 'Whether an online provisioner daemon could acquire the job when it was last checked. Only meaningful while the job is pending.';
+
 CREATE TABLE replicas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -333,11 +430,91 @@ CREATE TABLE replicas (
     error text DEFAULT ''::text NOT NULL
 );
 
+CREATE TABLE service_accounts (
+    user_id uuid NOT NULL,
+    owner_user_id uuid,
+    owner_group_id uuid,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE service_accounts IS 'Service accounts are users with the none login type that only authenticate with tokens.';
+
+COMMENT ON COLUMN service_accounts.owner_user_id IS 'The user that manages the tokens of the service account.';
+
+COMMENT ON COLUMN service_accounts.owner_group_id IS 'The group whose members manage the tokens of the service account.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value character varying(8192) NOT NULL
 );
 
+CREATE TABLE tailnet_agent_versions (
+    agent_id uuid NOT NULL,
+    node_version bigint DEFAULT 0 NOT NULL,
+    peer_version bigint DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE tailnet_agent_versions IS 'Versions are never reset while the agent can connect, so connections can resume from the version they last received.';
+
+COMMENT ON COLUMN tailnet_agent_versions.node_version IS 'Incremented on every update of the node of the agent.';
+
+COMMENT ON COLUMN tailnet_agent_versions.peer_version IS 'Incremented on every update of the node of a client connected to the agent.';
+
+COMMENT ON COLUMN tailnet_agent_versions.updated_at IS 'Versions of agents that aren''t in the latest build of a workspace are deleted once they haven''t been updated for a while.';
+
+CREATE TABLE tailnet_coordinators (
+    id uuid NOT NULL,
+    heartbeat_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE tailnet_nodes (
+    id uuid NOT NULL,
+    coordinator_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    node jsonb NOT NULL,
+    version bigint NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN tailnet_nodes.coordinator_id IS 'The coordinator the agent or client is connected to. Nodes are deleted with coordinators that stop sending heartbeats.';
+
+COMMENT ON COLUMN tailnet_nodes.agent_id IS 'Equal to id for the nodes of agents, and the agent the client is connected to for the nodes of clients.';
+
+COMMENT ON COLUMN tailnet_nodes.version IS 'The node_version of the agent for the nodes of agents, and the peer_version of the agent for the nodes of clients.';
+
+CREATE TABLE template_app_limits (
+    template_id uuid NOT NULL,
+    app_requests_per_minute bigint DEFAULT 0 NOT NULL,
+    app_bandwidth_bytes_per_second bigint DEFAULT 0 NOT NULL,
+    workspace_requests_per_minute bigint DEFAULT 0 NOT NULL,
+    workspace_bandwidth_bytes_per_second bigint DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_app_limits IS 'Request rate and bandwidth limits of shared apps in the workspaces of a template. They can only lower the deployment-wide limits.';
+
+COMMENT ON COLUMN template_app_limits.app_requests_per_minute IS 'Maximum number of requests per minute to each app or port. Zero is unlimited.';
+
+COMMENT ON COLUMN template_app_limits.app_bandwidth_bytes_per_second IS 'Maximum response bandwidth in bytes per second of each app or port. Zero is unlimited.';
+
+COMMENT ON COLUMN template_app_limits.workspace_requests_per_minute IS 'Maximum number of requests per minute to all apps and ports of a workspace. Zero is unlimited.';
+
+COMMENT ON COLUMN template_app_limits.workspace_bandwidth_bytes_per_second IS 'Maximum response bandwidth in bytes per second of all apps and ports of a workspace. Zero is unlimited.';
+
+CREATE TABLE template_app_routing (
+    template_id uuid NOT NULL,
+    app_slug text NOT NULL,
+    strip_prefix boolean DEFAULT true NOT NULL,
+    base_path_header text DEFAULT ''::text NOT NULL,
+    headers jsonb DEFAULT '{}'::jsonb NOT NULL,
+    allowed_methods text[] DEFAULT '{}'::text[] NOT NULL,
+    websocket_subprotocols text[] DEFAULT '{}'::text[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_app_routing IS 'Routing rules for the apps of a template by slug, set through the API. They apply to every workspace of the template.';
+
 CREATE TABLE template_version_parameters (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -460,6 +637,45 @@ CREATE TABLE user_links (
     oauth_expiry timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE user_totp (
+    user_id uuid NOT NULL,
+    secret text NOT NULL,
+    confirmed boolean DEFAULT false NOT NULL,
+    hashed_recovery_codes text[] DEFAULT '{}'::text[] NOT NULL,
+    last_used_step bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN user_totp.confirmed IS 'Whether the user entered a code to finish setting up TOTP. Unconfirmed secrets are not accepted at login.';
+
+COMMENT ON COLUMN user_totp.hashed_recovery_codes IS 'SHA-256 hashes of the recovery codes that were not used yet.';
+
+COMMENT ON COLUMN user_totp.last_used_step IS 'The time step of the last accepted code, which prevents codes from being used twice.';
+
+CREATE TABLE user_webauthn_challenges (
+    challenge bytea NOT NULL,
+    user_id uuid NOT NULL,
+    ceremony text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_webauthn_challenges IS 'Challenges sent to security keys. They are deleted when answered, so a response can only be used once.';
+
+CREATE TABLE user_webauthn_credentials (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    credential_id bytea NOT NULL,
+    name text NOT NULL,
+    public_key bytea NOT NULL,
+    sign_count bigint NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN user_webauthn_credentials.public_key IS 'COSE encoded public key of the credential.';
+
 CREATE TABLE users (
     id uuid NOT NULL,
     email text NOT NULL,
@@ -581,6 +797,31 @@ COMMENT ON COLUMN workspace_agents.startup_logs_length IS 'Total length of start
 
 COMMENT ON COLUMN workspace_agents.startup_logs_overflowed IS 'Whether the startup logs overflowed in length';
 
+CREATE TABLE workspace_app_access_logs (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    user_id uuid,
+    workspace_id uuid,
+    agent_id uuid NOT NULL,
+    app_slug_or_port text NOT NULL,
+    access_method text NOT NULL,
+    sharing_level app_sharing_level NOT NULL,
+    ip inet,
+    method text NOT NULL,
+    path text NOT NULL,
+    status_code integer NOT NULL,
+    bytes_written bigint NOT NULL,
+    duration_ms bigint NOT NULL
+);
+
+COMMENT ON TABLE workspace_app_access_logs IS 'Requests proxied to workspace apps and ports.';
+
+COMMENT ON COLUMN workspace_app_access_logs.user_id IS 'The user that made the request, null for unauthenticated requests to public apps.';
+
+COMMENT ON COLUMN workspace_app_access_logs.workspace_id IS 'The workspace of the app, null once the workspace is deleted.';
+
+COMMENT ON COLUMN workspace_app_access_logs.sharing_level IS 'The sharing level of the app when the request was made.';
+
 CREATE TABLE workspace_apps (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -609,6 +850,20 @@ COMMENT ON COLUMN workspace_build_parameters.name IS 'Parameter name';
 
 COMMENT ON COLUMN workspace_build_parameters.value IS 'Parameter value';
 
+CREATE TABLE workspace_build_timings (
+    workspace_build_id uuid NOT NULL,
+    stage workspace_build_stage NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    observed boolean DEFAULT false NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_timings IS 'Records how long each stage of a workspace build took. Agent stages span all agents of the build.';
+
+COMMENT ON COLUMN workspace_build_timings.ended_at IS 'Null while the stage is still in progress';
+
+COMMENT ON COLUMN workspace_build_timings.observed IS 'Whether the stage was observed in metrics. Agent stages can end on several replicas at once, so they are claimed to be observed once.';
+
 CREATE TABLE workspace_builds (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -626,6 +881,19 @@ CREATE TABLE workspace_builds (
     max_deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE workspace_port_shares (
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    port integer NOT NULL,
+    share_level app_sharing_level NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_port_shares IS 'Sharing levels of ports that are not defined as apps, accessed through port-based subdomain URLs.';
+
+COMMENT ON COLUMN workspace_port_shares.expires_at IS 'Null if the share never expires';
+
 CREATE TABLE workspace_resource_metadata (
     workspace_resource_id uuid NOT NULL,
     key character varying(1024) NOT NULL,
@@ -667,9 +935,12 @@ CREATE TABLE workspaces (
     name character varying(64) NOT NULL,
     autostart_schedule text,
     ttl bigint,
-    last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL
+    last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
+    peering_enabled boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN workspaces.peering_enabled IS 'Whether the agents of the workspace may connect to the agents of other workspaces of the owner that enabled peering. Requires workspace peering to be enabled for the deployment.';
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('licenses_id_seq'::regclass);
 
 ALTER TABLE ONLY provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
@@ -714,6 +985,30 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_secret_prefix_key UNIQUE (secret_prefix);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_refresh_prefix_key UNIQUE (refresh_prefix);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
+
+ALTER TABLE ONLY oauth2_provider_apps
+    ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -744,9 +1039,27 @@ ALTER TABLE ONLY provisioner_job_logs
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
+ALTER TABLE ONLY tailnet_agent_versions
+    ADD CONSTRAINT tailnet_agent_versions_pkey PRIMARY KEY (agent_id);
+
+ALTER TABLE ONLY tailnet_coordinators
+    ADD CONSTRAINT tailnet_coordinators_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY tailnet_nodes
+    ADD CONSTRAINT tailnet_nodes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_app_limits
+    ADD CONSTRAINT template_app_limits_pkey PRIMARY KEY (template_id);
+
+ALTER TABLE ONLY template_app_routing
+    ADD CONSTRAINT template_app_routing_pkey PRIMARY KEY (template_id, app_slug);
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -765,6 +1078,18 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_webauthn_challenges
+    ADD CONSTRAINT user_webauthn_challenges_pkey PRIMARY KEY (challenge);
+
+ALTER TABLE ONLY user_webauthn_credentials
+    ADD CONSTRAINT user_webauthn_credentials_credential_id_key UNIQUE (credential_id);
+
+ALTER TABLE ONLY user_webauthn_credentials
+    ADD CONSTRAINT user_webauthn_credentials_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
@@ -777,6 +1102,9 @@ ALTER TABLE ONLY workspace_agent_startup_logs
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_app_access_logs
+    ADD CONSTRAINT workspace_app_access_logs_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_slug_idx UNIQUE (agent_id, slug);
 
@@ -786,6 +1114,9 @@ ALTER TABLE ONLY workspace_apps
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
 
+ALTER TABLE ONLY workspace_build_timings
+    ADD CONSTRAINT workspace_build_timings_pkey PRIMARY KEY (workspace_build_id, stage);
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
 
@@ -795,6 +1126,9 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_port_shares
+    ADD CONSTRAINT workspace_port_shares_pkey PRIMARY KEY (workspace_id, agent_name, port);
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 
@@ -835,12 +1169,22 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX oauth2_provider_app_secrets_app_id_idx ON oauth2_provider_app_secrets USING btree (app_id);
+
+CREATE INDEX oauth2_provider_app_tokens_api_key_id_idx ON oauth2_provider_app_tokens USING btree (api_key_id);
+
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
+CREATE INDEX tailnet_nodes_agent_id_idx ON tailnet_nodes USING btree (agent_id);
+
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
+CREATE INDEX user_webauthn_challenges_expires_at_idx ON user_webauthn_challenges USING btree (expires_at);
+
+CREATE INDEX user_webauthn_credentials_user_id_idx ON user_webauthn_credentials USING btree (user_id);
+
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
@@ -851,6 +1195,10 @@ CREATE INDEX workspace_agents_auth_token_idx ON workspace_agents USING btree (au
 
 CREATE INDEX workspace_agents_resource_id_idx ON workspace_agents USING btree (resource_id);
 
+CREATE INDEX workspace_app_access_logs_created_at_idx ON workspace_app_access_logs USING btree (created_at);
+
+CREATE INDEX workspace_app_access_logs_workspace_id_created_at_idx ON workspace_app_access_logs USING btree (workspace_id, created_at DESC);
+
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
@@ -870,6 +1218,21 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_secrets
+    ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -885,6 +1248,24 @@ ALTER TABLE ONLY provisioner_job_logs
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_owner_group_id_fkey FOREIGN KEY (owner_group_id) REFERENCES groups(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_owner_user_id_fkey FOREIGN KEY (owner_user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY service_accounts
+    ADD CONSTRAINT service_accounts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY tailnet_nodes
+    ADD CONSTRAINT tailnet_nodes_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_app_limits
+    ADD CONSTRAINT template_app_limits_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_app_routing
+    ADD CONSTRAINT template_app_routing_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -909,6 +1290,15 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_webauthn_challenges
+    ADD CONSTRAINT user_webauthn_challenges_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_webauthn_credentials
+    ADD CONSTRAINT user_webauthn_credentials_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -918,12 +1308,21 @@ ALTER TABLE ONLY workspace_agent_startup_logs
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_resource_id_fkey FOREIGN KEY (resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_app_access_logs
+    ADD CONSTRAINT workspace_app_access_logs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_app_access_logs
+    ADD CONSTRAINT workspace_app_access_logs_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_timings
+    ADD CONSTRAINT workspace_build_timings_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -933,6 +1332,9 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_port_shares
+    ADD CONSTRAINT workspace_port_shares_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
@@ -5,4 +5,5 @@ package database
 // same ID.
 const (
 	LockIDDeploymentSetup = iota + 1
+	LockIDOIDCRefresh
 )
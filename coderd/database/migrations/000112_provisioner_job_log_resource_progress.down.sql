@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE provisioner_job_logs DROP COLUMN resource_address;
+ALTER TABLE provisioner_job_logs DROP COLUMN resource_action;
+ALTER TABLE provisioner_job_logs DROP COLUMN resource_status;
+ALTER TABLE provisioner_job_logs DROP COLUMN resource_elapsed_seconds;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE provisioner_job_logs ADD COLUMN resource_address text NOT NULL DEFAULT '';
+ALTER TABLE provisioner_job_logs ADD COLUMN resource_action text NOT NULL DEFAULT '';
+ALTER TABLE provisioner_job_logs ADD COLUMN resource_status text NOT NULL DEFAULT '';
+ALTER TABLE provisioner_job_logs ADD COLUMN resource_elapsed_seconds integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN provisioner_job_logs.resource_address IS 'Address of the resource this log line reports progress for, empty if the line is not a progress event';
+COMMENT ON COLUMN provisioner_job_logs.resource_action IS 'Action being performed on the resource (e.g. create, update, delete, read)';
+COMMENT ON COLUMN provisioner_job_logs.resource_status IS 'Progress status of the resource action (started, progress, complete, errored)';
+COMMENT ON COLUMN provisioner_job_logs.resource_elapsed_seconds IS 'Seconds elapsed since the resource action started';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE workspace_build_timings;
+DROP TYPE workspace_build_stage;
+
+COMMIT;
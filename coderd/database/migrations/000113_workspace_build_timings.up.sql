@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TYPE workspace_build_stage AS ENUM (
+	'queued',
+	'init',
+	'plan',
+	'apply',
+	'agent_connect',
+	'startup_script'
+);
+
+CREATE TABLE workspace_build_timings (
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	stage workspace_build_stage NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone,
+	observed boolean NOT NULL DEFAULT false,
+	PRIMARY KEY (workspace_build_id, stage)
+);
+
+COMMENT ON TABLE workspace_build_timings IS 'Records how long each stage of a workspace build took. Agent stages span all agents of the build.';
+COMMENT ON COLUMN workspace_build_timings.ended_at IS 'Null while the stage is still in progress';
+COMMENT ON COLUMN workspace_build_timings.observed IS 'Whether the stage was observed in metrics. Agent stages can end on several replicas at once, so they are claimed to be observed once.';
+
+COMMIT;
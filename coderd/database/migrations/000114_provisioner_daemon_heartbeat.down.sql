@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE provisioner_daemons
+	DROP COLUMN last_seen_at,
+	DROP COLUMN version,
+	DROP COLUMN hostname,
+	DROP COLUMN operating_system,
+	DROP COLUMN architecture,
+	DROP COLUMN concurrency,
+	DROP COLUMN current_job_id;
+
+COMMIT;
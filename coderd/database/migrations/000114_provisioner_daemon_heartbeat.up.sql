@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE provisioner_daemons
+	ADD COLUMN last_seen_at timestamp with time zone,
+	ADD COLUMN version text NOT NULL DEFAULT '',
+	ADD COLUMN hostname text NOT NULL DEFAULT '',
+	ADD COLUMN operating_system text NOT NULL DEFAULT '',
+	ADD COLUMN architecture text NOT NULL DEFAULT '',
+	ADD COLUMN concurrency integer NOT NULL DEFAULT 0,
+	ADD COLUMN current_job_id uuid;
+
+COMMENT ON COLUMN provisioner_daemons.last_seen_at IS 'The last time the daemon sent a heartbeat, null if it never has';
+COMMENT ON COLUMN provisioner_daemons.concurrency IS 'The number of jobs the daemon can run at once';
+COMMENT ON COLUMN provisioner_daemons.current_job_id IS 'The job the daemon reported running in its last heartbeat';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE provisioner_jobs
+	DROP COLUMN has_matching_daemon;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE provisioner_jobs
+	ADD COLUMN has_matching_daemon boolean NOT NULL DEFAULT true;
+
+COMMENT ON COLUMN provisioner_jobs.has_matching_daemon IS 'Whether an online provisioner daemon could acquire the job when it was last checked. Only meaningful while the job is pending.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE workspace_port_shares;
+
+COMMIT;
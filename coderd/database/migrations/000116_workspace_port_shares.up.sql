@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE workspace_port_shares (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	port integer NOT NULL,
+	share_level app_sharing_level NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone,
+	PRIMARY KEY (workspace_id, agent_name, port)
+);
+
+COMMENT ON TABLE workspace_port_shares IS 'Sharing levels of ports that are not defined as apps, accessed through port-based subdomain URLs.';
+COMMENT ON COLUMN workspace_port_shares.expires_at IS 'Null if the share never expires';
+
+COMMIT;
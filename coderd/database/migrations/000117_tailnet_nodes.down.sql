@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE tailnet_nodes;
+DROP TABLE tailnet_agent_versions;
+DROP TABLE tailnet_coordinators;
+
+COMMIT;
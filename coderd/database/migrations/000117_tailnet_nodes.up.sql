@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE tailnet_coordinators (
+	id uuid NOT NULL PRIMARY KEY,
+	heartbeat_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE tailnet_agent_versions (
+	agent_id uuid NOT NULL PRIMARY KEY,
+	node_version bigint NOT NULL DEFAULT 0,
+	peer_version bigint NOT NULL DEFAULT 0,
+	updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE tailnet_agent_versions IS 'Versions are never reset while the agent can connect, so connections can resume from the version they last received.';
+COMMENT ON COLUMN tailnet_agent_versions.node_version IS 'Incremented on every update of the node of the agent.';
+COMMENT ON COLUMN tailnet_agent_versions.peer_version IS 'Incremented on every update of the node of a client connected to the agent.';
+COMMENT ON COLUMN tailnet_agent_versions.updated_at IS 'Versions of agents that aren''t in the latest build of a workspace are deleted once they haven''t been updated for a while.';
+
+CREATE TABLE tailnet_nodes (
+	id uuid NOT NULL PRIMARY KEY,
+	coordinator_id uuid NOT NULL REFERENCES tailnet_coordinators (id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL,
+	node jsonb NOT NULL,
+	version bigint NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN tailnet_nodes.coordinator_id IS 'The coordinator the agent or client is connected to. Nodes are deleted with coordinators that stop sending heartbeats.';
+COMMENT ON COLUMN tailnet_nodes.agent_id IS 'Equal to id for the nodes of agents, and the agent the client is connected to for the nodes of clients.';
+COMMENT ON COLUMN tailnet_nodes.version IS 'The node_version of the agent for the nodes of agents, and the peer_version of the agent for the nodes of clients.';
+
+CREATE INDEX tailnet_nodes_agent_id_idx ON tailnet_nodes (agent_id);
+
+COMMIT;
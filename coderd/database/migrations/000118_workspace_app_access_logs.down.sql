@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE workspace_app_access_logs;
+
+COMMIT;
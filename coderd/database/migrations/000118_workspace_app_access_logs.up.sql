@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE workspace_app_access_logs (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	user_id uuid REFERENCES users (id) ON DELETE SET NULL,
+	workspace_id uuid REFERENCES workspaces (id) ON DELETE SET NULL,
+	agent_id uuid NOT NULL,
+	app_slug_or_port text NOT NULL,
+	access_method text NOT NULL,
+	sharing_level app_sharing_level NOT NULL,
+	ip inet,
+	method text NOT NULL,
+	path text NOT NULL,
+	status_code integer NOT NULL,
+	bytes_written bigint NOT NULL,
+	duration_ms bigint NOT NULL
+);
+
+COMMENT ON TABLE workspace_app_access_logs IS 'Requests proxied to workspace apps and ports.';
+COMMENT ON COLUMN workspace_app_access_logs.user_id IS 'The user that made the request, null for unauthenticated requests to public apps.';
+COMMENT ON COLUMN workspace_app_access_logs.workspace_id IS 'The workspace of the app, null once the workspace is deleted.';
+COMMENT ON COLUMN workspace_app_access_logs.sharing_level IS 'The sharing level of the app when the request was made.';
+
+CREATE INDEX workspace_app_access_logs_workspace_id_created_at_idx ON workspace_app_access_logs (workspace_id, created_at DESC);
+CREATE INDEX workspace_app_access_logs_created_at_idx ON workspace_app_access_logs (created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE user_webauthn_challenges;
+DROP TABLE user_webauthn_credentials;
+DROP TABLE user_totp;
+
+COMMIT;
@@ -0,0 +1,44 @@
+BEGIN;
+
+CREATE TABLE user_totp (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	secret text NOT NULL,
+	confirmed boolean NOT NULL DEFAULT false,
+	hashed_recovery_codes text[] NOT NULL DEFAULT '{}'::text[],
+	last_used_step bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN user_totp.confirmed IS 'Whether the user entered a code to finish setting up TOTP. Unconfirmed secrets are not accepted at login.';
+COMMENT ON COLUMN user_totp.hashed_recovery_codes IS 'SHA-256 hashes of the recovery codes that were not used yet.';
+COMMENT ON COLUMN user_totp.last_used_step IS 'The time step of the last accepted code, which prevents codes from being used twice.';
+
+CREATE TABLE user_webauthn_credentials (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	credential_id bytea NOT NULL UNIQUE,
+	name text NOT NULL,
+	public_key bytea NOT NULL,
+	sign_count bigint NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN user_webauthn_credentials.public_key IS 'COSE encoded public key of the credential.';
+
+CREATE INDEX user_webauthn_credentials_user_id_idx ON user_webauthn_credentials USING btree (user_id);
+
+CREATE TABLE user_webauthn_challenges (
+	challenge bytea NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	ceremony text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_webauthn_challenges IS 'Challenges sent to security keys. They are deleted when answered, so a response can only be used once.';
+
+CREATE INDEX user_webauthn_challenges_expires_at_idx ON user_webauthn_challenges USING btree (expires_at);
+
+COMMIT;
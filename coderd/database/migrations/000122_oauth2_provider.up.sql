@@ -0,0 +1,58 @@
+BEGIN;
+
+CREATE TABLE oauth2_provider_apps (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	name varchar(64) NOT NULL UNIQUE,
+	icon varchar(256) NOT NULL,
+	redirect_uris text[] NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_apps IS 'Applications that may obtain API keys of users through the OAuth2 authorization code flow.';
+COMMENT ON COLUMN oauth2_provider_apps.redirect_uris IS 'Authorization codes are only sent to one of these URIs.';
+
+CREATE TABLE oauth2_provider_app_secrets (
+	id uuid NOT NULL PRIMARY KEY,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone,
+	secret_prefix text NOT NULL UNIQUE,
+	hashed_secret bytea NOT NULL,
+	display_secret text NOT NULL
+);
+
+COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail of the secret, which lets admins tell secrets apart without revealing them.';
+
+CREATE INDEX oauth2_provider_app_secrets_app_id_idx ON oauth2_provider_app_secrets USING btree (app_id);
+
+CREATE TABLE oauth2_provider_app_codes (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	secret_prefix text NOT NULL UNIQUE,
+	hashed_secret bytea NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	app_id uuid NOT NULL REFERENCES oauth2_provider_apps (id) ON DELETE CASCADE,
+	scope api_key_scope NOT NULL,
+	redirect_uri text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Authorization codes that a user approved and an app has yet to exchange for tokens.';
+
+CREATE TABLE oauth2_provider_app_tokens (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	refresh_prefix text NOT NULL UNIQUE,
+	hashed_refresh_secret bytea NOT NULL,
+	app_secret_id uuid NOT NULL REFERENCES oauth2_provider_app_secrets (id) ON DELETE CASCADE,
+	api_key_id text NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE oauth2_provider_app_tokens IS 'Refresh tokens of the API keys issued to apps. Deleting the API key revokes the refresh token.';
+COMMENT ON COLUMN oauth2_provider_app_tokens.expires_at IS 'When the refresh token expires. The access token expires with its API key.';
+
+CREATE INDEX oauth2_provider_app_tokens_api_key_id_idx ON oauth2_provider_app_tokens USING btree (api_key_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Keys with the custom scope cannot be represented without their entries.
+DELETE FROM api_keys WHERE scope = 'custom';
+
+ALTER TABLE api_keys DROP COLUMN custom_scopes;
+
+-- You cannot safely remove values from enums https://www.postgresql.org/docs/current/datatype-enum.html
+-- You cannot create a new type and do a rename because objects depend on this type now.
@@ -0,0 +1,5 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'custom';
+
+ALTER TABLE api_keys ADD COLUMN custom_scopes text[] DEFAULT '{}'::text[] NOT NULL;
+
+COMMENT ON COLUMN api_keys.custom_scopes IS 'Fine-grained scope entries of keys with the custom scope, of the form <resource type>:<action>[:<resource id>].';
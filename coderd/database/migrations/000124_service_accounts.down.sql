@@ -0,0 +1,8 @@
+DROP TABLE service_accounts;
+
+-- Service accounts become regular users that cannot log in until they are
+-- given a password.
+UPDATE users SET login_type = 'password' WHERE login_type = 'none';
+
+-- You cannot safely remove values from enums https://www.postgresql.org/docs/current/datatype-enum.html
+-- You cannot create a new type and do a rename because objects depend on this type now.
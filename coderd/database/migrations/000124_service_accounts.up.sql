@@ -0,0 +1,12 @@
+ALTER TYPE login_type ADD VALUE IF NOT EXISTS 'none';
+
+CREATE TABLE service_accounts (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	owner_user_id uuid REFERENCES users (id) ON DELETE SET NULL,
+	owner_group_id uuid REFERENCES groups (id) ON DELETE SET NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE service_accounts IS 'Service accounts are users with the none login type that only authenticate with tokens.';
+COMMENT ON COLUMN service_accounts.owner_user_id IS 'The user that manages the tokens of the service account.';
+COMMENT ON COLUMN service_accounts.owner_group_id IS 'The group whose members manage the tokens of the service account.';
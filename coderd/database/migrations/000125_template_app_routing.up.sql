@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE template_app_routing (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	app_slug text NOT NULL,
+	strip_prefix boolean NOT NULL DEFAULT true,
+	base_path_header text NOT NULL DEFAULT '',
+	headers jsonb NOT NULL DEFAULT '{}'::jsonb,
+	allowed_methods text[] NOT NULL DEFAULT '{}'::text[],
+	websocket_subprotocols text[] NOT NULL DEFAULT '{}'::text[],
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, app_slug)
+);
+
+COMMENT ON TABLE template_app_routing IS 'Routing rules for the apps of a template by slug, set through the API. They apply to every workspace of the template.';
+
+COMMIT;
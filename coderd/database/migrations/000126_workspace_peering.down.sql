@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE workspaces DROP COLUMN peering_enabled;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE workspaces ADD COLUMN peering_enabled boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspaces.peering_enabled IS 'Whether the agents of the workspace may connect to the agents of other workspaces of the owner that enabled peering. Requires workspace peering to be enabled for the deployment.';
+
+COMMIT;
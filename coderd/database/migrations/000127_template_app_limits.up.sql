@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE template_app_limits (
+	template_id uuid NOT NULL PRIMARY KEY REFERENCES templates (id) ON DELETE CASCADE,
+	app_requests_per_minute bigint NOT NULL DEFAULT 0,
+	app_bandwidth_bytes_per_second bigint NOT NULL DEFAULT 0,
+	workspace_requests_per_minute bigint NOT NULL DEFAULT 0,
+	workspace_bandwidth_bytes_per_second bigint NOT NULL DEFAULT 0,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_app_limits IS 'Request rate and bandwidth limits of shared apps in the workspaces of a template. They can only lower the deployment-wide limits.';
+COMMENT ON COLUMN template_app_limits.app_requests_per_minute IS 'Maximum number of requests per minute to each app or port. Zero is unlimited.';
+COMMENT ON COLUMN template_app_limits.app_bandwidth_bytes_per_second IS 'Maximum response bandwidth in bytes per second of each app or port. Zero is unlimited.';
+COMMENT ON COLUMN template_app_limits.workspace_requests_per_minute IS 'Maximum number of requests per minute to all apps and ports of a workspace. Zero is unlimited.';
+COMMENT ON COLUMN template_app_limits.workspace_bandwidth_bytes_per_second IS 'Maximum response bandwidth in bytes per second of all apps and ports of a workspace. Zero is unlimited.';
+
+COMMIT;
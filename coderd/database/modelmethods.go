@@ -97,6 +97,15 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 	}
 }
 
+// RBACScope returns the scope the key is authorized with. Keys with the custom
+// scope are authorized with their fine-grained scope entries.
+func (k APIKey) RBACScope() rbac.ExpandableScope {
+	if k.Scope == APIKeyScopeCustom {
+		return rbac.CustomScope(k.CustomScopes)
+	}
+	return rbac.ScopeName(k.Scope)
+}
+
 func (k APIKey) RBACObject() rbac.Object {
 	return rbac.ResourceAPIKey.WithIDString(k.ID).
 		WithOwner(k.UserID.String())
@@ -206,6 +215,31 @@ func (u GitSSHKey) RBACObject() rbac.Object {
 	return rbac.ResourceUserData.WithID(u.UserID).WithOwner(u.UserID.String())
 }
 
+// RBACObject returns the RBAC object of the user the service account is.
+func (a ServiceAccount) RBACObject() rbac.Object {
+	return rbac.ResourceUser.WithID(a.UserID)
+}
+
+func (t UserTOTP) RBACObject() rbac.Object {
+	return rbac.ResourceUserData.WithID(t.UserID).WithOwner(t.UserID.String())
+}
+
+func (c UserWebAuthnCredential) RBACObject() rbac.Object {
+	return rbac.ResourceUserData.WithID(c.UserID).WithOwner(c.UserID.String())
+}
+
+func (a OAuth2ProviderApp) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderApp.WithID(a.ID)
+}
+
+func (s OAuth2ProviderAppSecret) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppSecret.WithID(s.ID)
+}
+
+func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppCodeToken.WithID(c.ID).WithOwner(c.UserID.String())
+}
+
 func (u GitAuthLink) RBACObject() rbac.Object {
 	// I assume UserData is ok?
 	return rbac.ResourceUserData.WithID(u.UserID).WithOwner(u.UserID.String())
@@ -312,6 +346,7 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			AutostartSchedule: r.AutostartSchedule,
 			Ttl:               r.Ttl,
 			LastUsedAt:        r.LastUsedAt,
+			PeeringEnabled:    r.PeeringEnabled,
 		}
 	}
 
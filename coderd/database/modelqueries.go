@@ -230,6 +230,7 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.AutostartSchedule,
 			&i.Ttl,
 			&i.LastUsedAt,
+			&i.PeeringEnabled,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -22,6 +22,7 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeCustom             APIKeyScope = "custom"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -62,7 +63,8 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom:
 		return true
 	}
 	return false
@@ -72,6 +74,7 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom,
 	}
 }
 
@@ -402,6 +405,9 @@ const (
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
 	LoginTypeToken    LoginType = "token"
+	LoginTypeSAML     LoginType = "saml"
+	LoginTypeLDAP     LoginType = "ldap"
+	LoginTypeNone     LoginType = "none"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -444,7 +450,10 @@ func (e LoginType) Valid() bool {
 	case LoginTypePassword,
 		LoginTypeGithub,
 		LoginTypeOIDC,
-		LoginTypeToken:
+		LoginTypeToken,
+		LoginTypeSAML,
+		LoginTypeLDAP,
+		LoginTypeNone:
 		return true
 	}
 	return false
@@ -456,6 +465,9 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeGithub,
 		LoginTypeOIDC,
 		LoginTypeToken,
+		LoginTypeSAML,
+		LoginTypeLDAP,
+		LoginTypeNone,
 	}
 }
 
@@ -1154,6 +1166,76 @@ func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
 	}
 }
 
+type WorkspaceBuildStage string
+
+const (
+	WorkspaceBuildStageQueued        WorkspaceBuildStage = "queued"
+	WorkspaceBuildStageInit          WorkspaceBuildStage = "init"
+	WorkspaceBuildStagePlan          WorkspaceBuildStage = "plan"
+	WorkspaceBuildStageApply         WorkspaceBuildStage = "apply"
+	WorkspaceBuildStageAgentConnect  WorkspaceBuildStage = "agent_connect"
+	WorkspaceBuildStageStartupScript WorkspaceBuildStage = "startup_script"
+)
+
+func (e *WorkspaceBuildStage) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceBuildStage(s)
+	case string:
+		*e = WorkspaceBuildStage(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceBuildStage: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceBuildStage struct {
+	WorkspaceBuildStage WorkspaceBuildStage
+	Valid               bool // Valid is true if WorkspaceBuildStage is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceBuildStage) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceBuildStage, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceBuildStage.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceBuildStage) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceBuildStage), nil
+}
+
+func (e WorkspaceBuildStage) Valid() bool {
+	switch e {
+	case WorkspaceBuildStageQueued,
+		WorkspaceBuildStageInit,
+		WorkspaceBuildStagePlan,
+		WorkspaceBuildStageApply,
+		WorkspaceBuildStageAgentConnect,
+		WorkspaceBuildStageStartupScript:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceBuildStageValues() []WorkspaceBuildStage {
+	return []WorkspaceBuildStage{
+		WorkspaceBuildStageQueued,
+		WorkspaceBuildStageInit,
+		WorkspaceBuildStagePlan,
+		WorkspaceBuildStageApply,
+		WorkspaceBuildStageAgentConnect,
+		WorkspaceBuildStageStartupScript,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -1229,6 +1311,8 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// Fine-grained scope entries of keys with the custom scope, of the form <resource type>:<action>[:<resource id>].
+	CustomScopes []string `db:"custom_scopes" json:"custom_scopes"`
 }
 
 type AuditLog struct {
@@ -1298,6 +1382,53 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// Authorization codes that a user approved and an app has yet to exchange for tokens.
+type OAuth2ProviderAppCode struct {
+	ID           uuid.UUID   `db:"id" json:"id"`
+	CreatedAt    time.Time   `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time   `db:"expires_at" json:"expires_at"`
+	SecretPrefix string      `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte      `db:"hashed_secret" json:"hashed_secret"`
+	UserID       uuid.UUID   `db:"user_id" json:"user_id"`
+	AppID        uuid.UUID   `db:"app_id" json:"app_id"`
+	Scope        APIKeyScope `db:"scope" json:"scope"`
+	RedirectURI  string      `db:"redirect_uri" json:"redirect_uri"`
+}
+
+type OAuth2ProviderAppSecret struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	AppID        uuid.UUID    `db:"app_id" json:"app_id"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt   sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	SecretPrefix string       `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte       `db:"hashed_secret" json:"hashed_secret"`
+	// The tail of the secret, which lets admins tell secrets apart without revealing them.
+	DisplaySecret string `db:"display_secret" json:"display_secret"`
+}
+
+// Refresh tokens of the API keys issued to apps. Deleting the API key revokes the refresh token.
+type OAuth2ProviderAppToken struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// When the refresh token expires. The access token expires with its API key.
+	ExpiresAt           time.Time `db:"expires_at" json:"expires_at"`
+	RefreshPrefix       string    `db:"refresh_prefix" json:"refresh_prefix"`
+	HashedRefreshSecret []byte    `db:"hashed_refresh_secret" json:"hashed_refresh_secret"`
+	AppSecretID         uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID            string    `db:"api_key_id" json:"api_key_id"`
+}
+
+// Applications that may obtain API keys of users through the OAuth2 authorization code flow.
+type OAuth2ProviderApp struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
+	Icon      string    `db:"icon" json:"icon"`
+	// Authorization codes are only sent to one of these URIs.
+	RedirectURIs []string `db:"redirect_uris" json:"redirect_uris"`
+}
+
 type Organization struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -1354,6 +1485,16 @@ type ProvisionerDaemon struct {
 	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
 	ReplicaID    uuid.NullUUID     `db:"replica_id" json:"replica_id"`
 	Tags         dbtype.StringMap  `db:"tags" json:"tags"`
+	// The last time the daemon sent a heartbeat, null if it never has
+	LastSeenAt      sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
+	Version         string       `db:"version" json:"version"`
+	Hostname        string       `db:"hostname" json:"hostname"`
+	OperatingSystem string       `db:"operating_system" json:"operating_system"`
+	Architecture    string       `db:"architecture" json:"architecture"`
+	// The number of jobs the daemon can run at once
+	Concurrency int32 `db:"concurrency" json:"concurrency"`
+	// The job the daemon reported running in its last heartbeat
+	CurrentJobID uuid.NullUUID `db:"current_job_id" json:"current_job_id"`
 }
 
 type ProvisionerJob struct {
@@ -1374,6 +1515,8 @@ type ProvisionerJob struct {
 	FileID         uuid.UUID                `db:"file_id" json:"file_id"`
 	Tags           dbtype.StringMap         `db:"tags" json:"tags"`
 	ErrorCode      sql.NullString           `db:"error_code" json:"error_code"`
+	// Whether an online provisioner daemon could acquire the job when it was last checked. Only meaningful while the job is pending.
+	HasMatchingDaemon bool `db:"has_matching_daemon" json:"has_matching_daemon"`
 }
 
 type ProvisionerJobLog struct {
@@ -1384,6 +1527,14 @@ type ProvisionerJobLog struct {
 	Stage     string    `db:"stage" json:"stage"`
 	Output    string    `db:"output" json:"output"`
 	ID        int64     `db:"id" json:"id"`
+	// Address of the resource this log line reports progress for, empty if the line is not a progress event
+	ResourceAddress string `db:"resource_address" json:"resource_address"`
+	// Action being performed on the resource (e.g. create, update, delete, read)
+	ResourceAction string `db:"resource_action" json:"resource_action"`
+	// Progress status of the resource action (started, progress, complete, errored)
+	ResourceStatus string `db:"resource_status" json:"resource_status"`
+	// Seconds elapsed since the resource action started
+	ResourceElapsedSeconds int32 `db:"resource_elapsed_seconds" json:"resource_elapsed_seconds"`
 }
 
 type Replica struct {
@@ -1400,11 +1551,49 @@ type Replica struct {
 	Error           string       `db:"error" json:"error"`
 }
 
+// Service accounts are users with the none login type that only authenticate with tokens.
+type ServiceAccount struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// The user that manages the tokens of the service account.
+	OwnerUserID uuid.NullUUID `db:"owner_user_id" json:"owner_user_id"`
+	// The group whose members manage the tokens of the service account.
+	OwnerGroupID uuid.NullUUID `db:"owner_group_id" json:"owner_group_id"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
 }
 
+// Versions are never reset while the agent can connect, so connections can resume from the version they last received.
+type TailnetAgentVersion struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	// Incremented on every update of the node of the agent.
+	NodeVersion int64 `db:"node_version" json:"node_version"`
+	// Incremented on every update of the node of a client connected to the agent.
+	PeerVersion int64 `db:"peer_version" json:"peer_version"`
+	// Versions of agents that aren't in the latest build of a workspace are deleted once they haven't been updated for a while.
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type TailnetCoordinator struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	HeartbeatAt time.Time `db:"heartbeat_at" json:"heartbeat_at"`
+}
+
+type TailnetNode struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// The coordinator the agent or client is connected to. Nodes are deleted with coordinators that stop sending heartbeats.
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	// Equal to id for the nodes of agents, and the agent the client is connected to for the nodes of clients.
+	AgentID uuid.UUID       `db:"agent_id" json:"agent_id"`
+	Node    json.RawMessage `db:"node" json:"node"`
+	// The node_version of the agent for the nodes of agents, and the peer_version of the agent for the nodes of clients.
+	Version   int64     `db:"version" json:"version"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type Template struct {
 	ID              uuid.UUID       `db:"id" json:"id"`
 	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
@@ -1428,6 +1617,32 @@ type Template struct {
 	MaxTTL                       int64 `db:"max_ttl" json:"max_ttl"`
 }
 
+// Request rate and bandwidth limits of shared apps in the workspaces of a template. They can only lower the deployment-wide limits.
+type TemplateAppLimit struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// Maximum number of requests per minute to each app or port. Zero is unlimited.
+	AppRequestsPerMinute int64 `db:"app_requests_per_minute" json:"app_requests_per_minute"`
+	// Maximum response bandwidth in bytes per second of each app or port. Zero is unlimited.
+	AppBandwidthBytesPerSecond int64 `db:"app_bandwidth_bytes_per_second" json:"app_bandwidth_bytes_per_second"`
+	// Maximum number of requests per minute to all apps and ports of a workspace. Zero is unlimited.
+	WorkspaceRequestsPerMinute int64 `db:"workspace_requests_per_minute" json:"workspace_requests_per_minute"`
+	// Maximum response bandwidth in bytes per second of all apps and ports of a workspace. Zero is unlimited.
+	WorkspaceBandwidthBytesPerSecond int64     `db:"workspace_bandwidth_bytes_per_second" json:"workspace_bandwidth_bytes_per_second"`
+	UpdatedAt                        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Routing rules for the apps of a template by slug, set through the API. They apply to every workspace of the template.
+type TemplateAppRouting struct {
+	TemplateID            uuid.UUID        `db:"template_id" json:"template_id"`
+	AppSlug               string           `db:"app_slug" json:"app_slug"`
+	StripPrefix           bool             `db:"strip_prefix" json:"strip_prefix"`
+	BasePathHeader        string           `db:"base_path_header" json:"base_path_header"`
+	Headers               dbtype.StringMap `db:"headers" json:"headers"`
+	AllowedMethods        []string         `db:"allowed_methods" json:"allowed_methods"`
+	WebsocketSubprotocols []string         `db:"websocket_subprotocols" json:"websocket_subprotocols"`
+	UpdatedAt             time.Time        `db:"updated_at" json:"updated_at"`
+}
+
 type TemplateVersion struct {
 	ID             uuid.UUID     `db:"id" json:"id"`
 	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
@@ -1516,6 +1731,40 @@ type UserLink struct {
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
 }
 
+type UserTOTP struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Secret string    `db:"secret" json:"secret"`
+	// Whether the user entered a code to finish setting up TOTP. Unconfirmed secrets are not accepted at login.
+	Confirmed bool `db:"confirmed" json:"confirmed"`
+	// SHA-256 hashes of the recovery codes that were not used yet.
+	HashedRecoveryCodes []string `db:"hashed_recovery_codes" json:"hashed_recovery_codes"`
+	// The time step of the last accepted code, which prevents codes from being used twice.
+	LastUsedStep int64     `db:"last_used_step" json:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Challenges sent to security keys. They are deleted when answered, so a response can only be used once.
+type UserWebAuthnChallenge struct {
+	Challenge []byte    `db:"challenge" json:"challenge"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Ceremony  string    `db:"ceremony" json:"ceremony"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
+type UserWebAuthnCredential struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	CredentialID []byte    `db:"credential_id" json:"credential_id"`
+	Name         string    `db:"name" json:"name"`
+	// COSE encoded public key of the credential.
+	PublicKey  []byte    `db:"public_key" json:"public_key"`
+	SignCount  int64     `db:"sign_count" json:"sign_count"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	LastUsedAt time.Time `db:"last_used_at" json:"last_used_at"`
+}
+
 type Workspace struct {
 	ID                uuid.UUID      `db:"id" json:"id"`
 	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
@@ -1528,6 +1777,8 @@ type Workspace struct {
 	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
 	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
 	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
+	// Whether the agents of the workspace may connect to the agents of other workspaces of the owner that enabled peering. Requires workspace peering to be enabled for the deployment.
+	PeeringEnabled bool `db:"peering_enabled" json:"peering_enabled"`
 }
 
 type WorkspaceAgent struct {
@@ -1614,6 +1865,27 @@ type WorkspaceAgentStat struct {
 	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
 }
 
+// Requests proxied to workspace apps and ports.
+type WorkspaceAppAccessLog struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// The user that made the request, null for unauthenticated requests to public apps.
+	UserID uuid.NullUUID `db:"user_id" json:"user_id"`
+	// The workspace of the app, null once the workspace is deleted.
+	WorkspaceID   uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	AgentID       uuid.UUID     `db:"agent_id" json:"agent_id"`
+	AppSlugOrPort string        `db:"app_slug_or_port" json:"app_slug_or_port"`
+	AccessMethod  string        `db:"access_method" json:"access_method"`
+	// The sharing level of the app when the request was made.
+	SharingLevel AppSharingLevel `db:"sharing_level" json:"sharing_level"`
+	Ip           pqtype.Inet     `db:"ip" json:"ip"`
+	Method       string          `db:"method" json:"method"`
+	Path         string          `db:"path" json:"path"`
+	StatusCode   int32           `db:"status_code" json:"status_code"`
+	BytesWritten int64           `db:"bytes_written" json:"bytes_written"`
+	DurationMs   int64           `db:"duration_ms" json:"duration_ms"`
+}
+
 type WorkspaceApp struct {
 	ID                   uuid.UUID          `db:"id" json:"id"`
 	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
@@ -1657,6 +1929,28 @@ type WorkspaceBuildParameter struct {
 	Value string `db:"value" json:"value"`
 }
 
+// Records how long each stage of a workspace build took. Agent stages span all agents of the build.
+type WorkspaceBuildTiming struct {
+	WorkspaceBuildID uuid.UUID           `db:"workspace_build_id" json:"workspace_build_id"`
+	Stage            WorkspaceBuildStage `db:"stage" json:"stage"`
+	StartedAt        time.Time           `db:"started_at" json:"started_at"`
+	// Null while the stage is still in progress
+	EndedAt sql.NullTime `db:"ended_at" json:"ended_at"`
+	// Whether the stage was observed in metrics. Agent stages can end on several replicas at once, so they are claimed to be observed once.
+	Observed bool `db:"observed" json:"observed"`
+}
+
+// Sharing levels of ports that are not defined as apps, accessed through port-based subdomain URLs.
+type WorkspacePortShare struct {
+	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	AgentName   string          `db:"agent_name" json:"agent_name"`
+	Port        int32           `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel `db:"share_level" json:"share_level"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+	// Null if the share never expires
+	ExpiresAt sql.NullTime `db:"expires_at" json:"expires_at"`
+}
+
 type WorkspaceResource struct {
 	ID           uuid.UUID           `db:"id" json:"id"`
 	CreatedAt    time.Time           `db:"created_at" json:"created_at"`
@@ -33,18 +33,46 @@ type sqlcQuerier interface {
 	DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error
 	DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error
 	DeleteLicense(ctx context.Context, id int32) (int32, error)
+	// The API keys issued to the app are deleted too, as deleting the refresh
+	// tokens alone would leave them usable until they expire.
+	DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error
+	// Returns no rows if the code was already deleted, so concurrent requests
+	// can't both redeem it.
+	DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	// The API keys issued with the secret are deleted too.
+	DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error
+	// Returns no rows if the refresh token was already deleted, so concurrent
+	// requests can't both redeem it.
+	DeleteOAuth2ProviderAppTokenByPrefix(ctx context.Context, refreshPrefix string) (OAuth2ProviderAppToken, error)
+	// Deleting the API keys revokes both the access and the refresh tokens.
+	DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error
+	DeleteExpiredUserWebAuthnChallenges(ctx context.Context) error
 	// If an agent hasn't connected in the last 7 days, we purge it's logs.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
 	DeleteOldWorkspaceAgentStartupLogs(ctx context.Context) error
 	DeleteOldWorkspaceAgentStats(ctx context.Context) error
+	DeleteOldWorkspaceAppAccessLogs(ctx context.Context, before time.Time) error
 	DeleteParameterValueByID(ctx context.Context, id uuid.UUID) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
+	DeleteTailnetAgentVersionsUpdatedBefore(ctx context.Context, updatedBefore time.Time) error
+	DeleteTailnetCoordinator(ctx context.Context, id uuid.UUID) error
+	DeleteTailnetCoordinatorsHeartbeatBefore(ctx context.Context, heartbeatAt time.Time) error
+	DeleteTailnetNode(ctx context.Context, arg DeleteTailnetNodeParams) error
+	DeleteTemplateAppLimits(ctx context.Context, templateID uuid.UUID) error
+	DeleteTemplateAppRouting(ctx context.Context, arg DeleteTemplateAppRoutingParams) error
+	DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error
+	// Returns no rows if the challenge was already answered, so a response can't
+	// be replayed.
+	DeleteUserWebAuthnChallenge(ctx context.Context, arg DeleteUserWebAuthnChallengeParams) (UserWebAuthnChallenge, error)
+	DeleteUserWebAuthnCredential(ctx context.Context, id uuid.UUID) error
+	DeleteWorkspacePortShare(ctx context.Context, arg DeleteWorkspacePortShareParams) error
 	GetAPIKeyByID(ctx context.Context, id string) (APIKey, error)
 	// there is no unique constraint on empty token names
 	GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNameParams) (APIKey, error)
 	GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error)
 	GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error)
 	GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error)
+	GetActiveServiceAccountCount(ctx context.Context) (int64, error)
 	GetActiveUserCount(ctx context.Context) (int64, error)
 	GetAppSigningKey(ctx context.Context) (string, error)
 	// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
@@ -77,6 +105,16 @@ type sqlcQuerier interface {
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
 	GetLicenses(ctx context.Context) ([]License, error)
 	GetLogoURL(ctx context.Context) (string, error)
+	GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error)
+	GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error)
+	GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix string) (OAuth2ProviderAppCode, error)
+	GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error)
+	GetOAuth2ProviderAppSecretByPrefix(ctx context.Context, secretPrefix string) (OAuth2ProviderAppSecret, error)
+	GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error)
+	GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, refreshPrefix string) (OAuth2ProviderAppToken, error)
+	GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error)
+	// Apps that hold at least one API key of the user.
+	GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]OAuth2ProviderApp, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, name string) (Organization, error)
 	GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error)
@@ -87,6 +125,10 @@ type sqlcQuerier interface {
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
 	GetParameterSchemasCreatedAfter(ctx context.Context, createdAt time.Time) ([]ParameterSchema, error)
 	GetParameterValueByScopeAndName(ctx context.Context, arg GetParameterValueByScopeAndNameParams) (ParameterValue, error)
+	// Counts jobs waiting for a daemon, grouped by the provisioner and tags
+	// a daemon must satisfy to acquire them.
+	GetPendingProvisionerJobCountsByTags(ctx context.Context) ([]GetPendingProvisionerJobCountsByTagsRow, error)
+	GetPendingProvisionerJobs(ctx context.Context) ([]ProvisionerJob, error)
 	GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error)
 	GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error)
 	GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
@@ -96,8 +138,15 @@ type sqlcQuerier interface {
 	GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error)
 	GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error)
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
+	GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (ServiceAccount, error)
 	GetServiceBanner(ctx context.Context) (string, error)
+	GetTailnetAgentNode(ctx context.Context, agentID uuid.UUID) (TailnetNode, error)
+	GetTailnetClientNodesAfterVersion(ctx context.Context, arg GetTailnetClientNodesAfterVersionParams) ([]TailnetNode, error)
+	GetTemplateAppLimits(ctx context.Context, templateID uuid.UUID) (TemplateAppLimit, error)
+	GetTemplateAppRouting(ctx context.Context, arg GetTemplateAppRoutingParams) (TemplateAppRouting, error)
+	GetTemplateAppRoutingByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateAppRouting, error)
 	GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error)
+	GetTemplateBuildStageStats(ctx context.Context, arg GetTemplateBuildStageStatsParams) ([]GetTemplateBuildStageStatsRow, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
 	GetTemplateDAUs(ctx context.Context, templateID uuid.UUID) ([]GetTemplateDAUsRow, error)
@@ -117,6 +166,9 @@ type sqlcQuerier interface {
 	GetUserCount(ctx context.Context) (int64, error)
 	GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error)
 	GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error)
+	GetUserTOTP(ctx context.Context, userID uuid.UUID) (UserTOTP, error)
+	GetUserWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (UserWebAuthnCredential, error)
+	GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]UserWebAuthnCredential, error)
 	// This will never return deleted users.
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
 	// This shouldn't check for deleted, because it's frequently used
@@ -132,6 +184,7 @@ type sqlcQuerier interface {
 	GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgent, error)
+	GetWorkspaceAppAccessLogs(ctx context.Context, arg GetWorkspaceAppAccessLogsParams) ([]GetWorkspaceAppAccessLogsRow, error)
 	GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error)
 	GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error)
@@ -140,12 +193,15 @@ type sqlcQuerier interface {
 	GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error)
 	GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error)
+	GetWorkspaceBuildTimingsByBuildIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuildTiming, error)
 	GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error)
 	GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error)
 	GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (Workspace, error)
 	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	GetWorkspacePortShare(ctx context.Context, arg GetWorkspacePortShareParams) (WorkspacePortShare, error)
+	GetWorkspacePortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShare, error)
 	GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (WorkspaceResource, error)
 	GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourceMetadataCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResourceMetadatum, error)
@@ -168,6 +224,10 @@ type sqlcQuerier interface {
 	InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error)
 	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error
 	InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error)
+	InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error)
+	InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error)
+	InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error)
+	InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error)
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
 	InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error)
 	InsertParameterSchema(ctx context.Context, arg InsertParameterSchemaParams) (ParameterSchema, error)
@@ -176,6 +236,7 @@ type sqlcQuerier interface {
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	InsertServiceAccount(ctx context.Context, arg InsertServiceAccountParams) (ServiceAccount, error)
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) (Template, error)
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) (TemplateVersion, error)
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
@@ -184,12 +245,15 @@ type sqlcQuerier interface {
 	// InsertUserGroupsByName adds a user to all provided groups, if they exist.
 	InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error
 	InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error)
+	InsertUserWebAuthnChallenge(ctx context.Context, arg InsertUserWebAuthnChallengeParams) error
+	InsertUserWebAuthnCredential(ctx context.Context, arg InsertUserWebAuthnCredentialParams) (UserWebAuthnCredential, error)
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
 	InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error
 	InsertWorkspaceAgentStartupLogs(ctx context.Context, arg InsertWorkspaceAgentStartupLogsParams) ([]WorkspaceAgentStartupLog, error)
 	InsertWorkspaceAgentStat(ctx context.Context, arg InsertWorkspaceAgentStatParams) (WorkspaceAgentStat, error)
 	InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error)
+	InsertWorkspaceAppAccessLog(ctx context.Context, arg InsertWorkspaceAppAccessLogParams) error
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) (WorkspaceBuild, error)
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
@@ -208,7 +272,14 @@ type sqlcQuerier interface {
 	UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error)
 	UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error)
 	UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error)
+	UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error)
+	UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error)
+	// Fails a job that no daemon has acquired yet. The started_at guard avoids
+	// racing with a daemon acquiring the job.
+	UpdatePendingProvisionerJobWithFailureByID(ctx context.Context, arg UpdatePendingProvisionerJobWithFailureByIDParams) error
+	UpdateProvisionerDaemonHeartbeat(ctx context.Context, arg UpdateProvisionerDaemonHeartbeatParams) error
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
+	UpdateProvisionerJobHasMatchingDaemonByID(ctx context.Context, arg UpdateProvisionerJobHasMatchingDaemonByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
 	UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error)
@@ -228,6 +299,13 @@ type sqlcQuerier interface {
 	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
 	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
+	// Returns no rows if a code of the same or a later time step was already
+	// used, so concurrent logins can't both use a code.
+	UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (UserTOTP, error)
+	// Returns no rows if the recovery code was already used, so concurrent logins
+	// can't both use it.
+	UpdateUserTOTPRemoveRecoveryCode(ctx context.Context, arg UpdateUserTOTPRemoveRecoveryCodeParams) (UserTOTP, error)
+	UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg UpdateUserWebAuthnCredentialSignCountParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error
@@ -238,13 +316,25 @@ type sqlcQuerier interface {
 	UpdateWorkspaceAutostart(ctx context.Context, arg UpdateWorkspaceAutostartParams) error
 	UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWorkspaceBuildByIDParams) (WorkspaceBuild, error)
 	UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) (WorkspaceBuild, error)
+	// Claims an ended stage to be observed in metrics. No rows are returned if the
+	// stage hasn't ended or was already claimed, so only one caller observes it.
+	UpdateWorkspaceBuildTimingObserved(ctx context.Context, arg UpdateWorkspaceBuildTimingObservedParams) (WorkspaceBuildTiming, error)
 	UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateWorkspaceDeletedByIDParams) error
 	UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWorkspaceLastUsedAtParams) error
+	UpdateWorkspacePeering(ctx context.Context, arg UpdateWorkspacePeeringParams) error
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
 	UpdateWorkspaceTTLToBeWithinTemplateMax(ctx context.Context, arg UpdateWorkspaceTTLToBeWithinTemplateMaxParams) error
 	UpsertLastUpdateCheck(ctx context.Context, value string) error
 	UpsertLogoURL(ctx context.Context, value string) error
 	UpsertServiceBanner(ctx context.Context, value string) error
+	UpsertTailnetAgentNode(ctx context.Context, arg UpsertTailnetAgentNodeParams) (TailnetNode, error)
+	UpsertTailnetClientNode(ctx context.Context, arg UpsertTailnetClientNodeParams) (TailnetNode, error)
+	UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error)
+	UpsertTemplateAppLimits(ctx context.Context, arg UpsertTemplateAppLimitsParams) (TemplateAppLimit, error)
+	UpsertTemplateAppRouting(ctx context.Context, arg UpsertTemplateAppRoutingParams) (TemplateAppRouting, error)
+	UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTOTP, error)
+	UpsertWorkspaceBuildTiming(ctx context.Context, arg UpsertWorkspaceBuildTimingParams) error
+	UpsertWorkspacePortShare(ctx context.Context, arg UpsertWorkspacePortShareParams) (WorkspacePortShare, error)
 }
 
 var _ sqlcQuerier = (*sqlQuerier)(nil)
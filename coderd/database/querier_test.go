@@ -4,6 +4,7 @@ package database_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -125,3 +126,82 @@ func TestInsertWorkspaceAgentStartupLogs(t *testing.T) {
 	})
 	require.True(t, database.IsStartupLogsLimitError(err))
 }
+
+func TestDeleteTailnetAgentVersionsUpdatedBefore(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.SkipNow()
+	}
+	sqlDB := testSQLDB(t)
+	ctx := context.Background()
+	err := migrations.Up(sqlDB)
+	require.NoError(t, err)
+	db := database.New(sqlDB)
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	template := dbgen.Template(t, db, database.Template{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	templateVersion := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		OrganizationID: org.ID,
+		TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+		CreatedBy:      user.ID,
+	})
+	workspace := dbgen.Workspace(t, db, database.Workspace{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+		TemplateID:     template.ID,
+	})
+	// agentOfBuild creates an agent in a new build of the workspace.
+	agentOfBuild := func(buildNumber int32) database.WorkspaceAgent {
+		job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			OrganizationID: org.ID,
+		})
+		dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       workspace.ID,
+			TemplateVersionID: templateVersion.ID,
+			JobID:             job.ID,
+			BuildNumber:       buildNumber,
+		})
+		resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+			JobID: job.ID,
+		})
+		return dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{
+			ResourceID: resource.ID,
+		})
+	}
+	oldAgentID := agentOfBuild(1).ID
+	latestAgentID := agentOfBuild(2).ID
+	connectedAgentID := uuid.New()
+
+	coordinator, err := db.UpsertTailnetCoordinator(ctx, uuid.New())
+	require.NoError(t, err)
+	upsertNode := func(agentID uuid.UUID) database.TailnetNode {
+		node, err := db.UpsertTailnetAgentNode(ctx, database.UpsertTailnetAgentNodeParams{
+			AgentID:       agentID,
+			CoordinatorID: coordinator.ID,
+			Node:          json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+		return node
+	}
+	for _, agentID := range []uuid.UUID{oldAgentID, latestAgentID, connectedAgentID} {
+		upsertNode(agentID)
+	}
+	for _, agentID := range []uuid.UUID{oldAgentID, latestAgentID} {
+		err = db.DeleteTailnetNode(ctx, database.DeleteTailnetNodeParams{
+			ID:            agentID,
+			CoordinatorID: coordinator.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	err = db.DeleteTailnetAgentVersionsUpdatedBefore(ctx, database.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	// Only the versions of the agent of the old build start over.
+	require.EqualValues(t, 1, upsertNode(oldAgentID).Version)
+	require.EqualValues(t, 2, upsertNode(latestAgentID).Version)
+	require.EqualValues(t, 2, upsertNode(connectedAgentID).Version)
+}
@@ -43,7 +43,7 @@ func (q *sqlQuerier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, custom_scopes
 FROM
 	api_keys
 WHERE
@@ -68,13 +68,14 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.CustomScopes),
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, custom_scopes
 FROM
 	api_keys
 WHERE
@@ -107,12 +108,13 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.CustomScopes),
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, custom_scopes FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -137,6 +139,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.CustomScopes),
 		); err != nil {
 			return nil, err
 		}
@@ -152,7 +155,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, custom_scopes FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -182,6 +185,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.CustomScopes),
 		); err != nil {
 			return nil, err
 		}
@@ -197,7 +201,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, custom_scopes FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -222,6 +226,7 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.CustomScopes),
 		); err != nil {
 			return nil, err
 		}
@@ -250,7 +255,8 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		custom_scopes
 	)
 VALUES
 	($1,
@@ -259,7 +265,7 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, custom_scopes
 `
 
 type InsertAPIKeyParams struct {
@@ -275,6 +281,7 @@ type InsertAPIKeyParams struct {
 	LoginType       LoginType   `db:"login_type" json:"login_type"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	CustomScopes    []string    `db:"custom_scopes" json:"custom_scopes"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -291,6 +298,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		pq.Array(arg.CustomScopes),
 	)
 	var i APIKey
 	err := row.Scan(
@@ -306,6 +314,7 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.CustomScopes),
 	)
 	return i, err
 }
@@ -347,6 +356,7 @@ SELECT
     users.status AS user_status,
     users.rbac_roles AS user_roles,
     users.avatar_url AS user_avatar_url,
+    users.login_type AS user_login_type,
     COUNT(audit_logs.*) OVER () AS count
 FROM
     audit_logs
@@ -474,6 +484,7 @@ type GetAuditLogsOffsetRow struct {
 	UserStatus       NullUserStatus  `db:"user_status" json:"user_status"`
 	UserRoles        []string        `db:"user_roles" json:"user_roles"`
 	UserAvatarUrl    sql.NullString  `db:"user_avatar_url" json:"user_avatar_url"`
+	UserLoginType    NullLoginType   `db:"user_login_type" json:"user_login_type"`
 	Count            int64           `db:"count" json:"count"`
 }
 
@@ -522,6 +533,7 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 			&i.UserStatus,
 			pq.Array(&i.UserRoles),
 			&i.UserAvatarUrl,
+			&i.UserLoginType,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -1532,6 +1544,616 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
+const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
+WITH deleted_api_keys AS (
+	DELETE FROM
+		api_keys
+	USING
+		oauth2_provider_app_tokens, oauth2_provider_app_secrets
+	WHERE
+		oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+		AND api_keys.id = oauth2_provider_app_tokens.api_key_id
+		AND oauth2_provider_app_secrets.app_id = $1
+)
+DELETE FROM oauth2_provider_apps WHERE id = $1
+`
+
+// The API keys issued to the app are deleted too, as deleting the refresh
+// tokens alone would leave them usable until they expire.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppCodeByID = `-- name: DeleteOAuth2ProviderAppCodeByID :one
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING id
+`
+
+// Returns no rows if the code was already deleted, so concurrent requests
+// can't both redeem it.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, deleteOAuth2ProviderAppCodeByID, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
+WITH deleted_api_keys AS (
+	DELETE FROM
+		api_keys
+	USING
+		oauth2_provider_app_tokens
+	WHERE
+		api_keys.id = oauth2_provider_app_tokens.api_key_id
+		AND oauth2_provider_app_tokens.app_secret_id = $1
+)
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+// The API keys issued with the secret are deleted too.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
+	return err
+}
+
+const deleteOAuth2ProviderAppTokenByPrefix = `-- name: DeleteOAuth2ProviderAppTokenByPrefix :one
+DELETE FROM oauth2_provider_app_tokens WHERE refresh_prefix = $1 RETURNING id, created_at, expires_at, refresh_prefix, hashed_refresh_secret, app_secret_id, api_key_id
+`
+
+// Returns no rows if the refresh token was already deleted, so concurrent
+// requests can't both redeem it.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppTokenByPrefix(ctx context.Context, refreshPrefix string) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, deleteOAuth2ProviderAppTokenByPrefix, refreshPrefix)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RefreshPrefix,
+		&i.HashedRefreshSecret,
+		&i.AppSecretID,
+		&i.APIKeyID,
+	)
+	return i, err
+}
+
+const deleteOAuth2ProviderAppTokensByAppAndUserID = `-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+DELETE FROM
+	api_keys
+USING
+	oauth2_provider_app_tokens, oauth2_provider_app_secrets
+WHERE
+	oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+	AND api_keys.id = oauth2_provider_app_tokens.api_key_id
+	AND oauth2_provider_app_secrets.app_id = $1
+	AND api_keys.user_id = $2
+`
+
+type DeleteOAuth2ProviderAppTokensByAppAndUserIDParams struct {
+	AppID  uuid.UUID `db:"app_id" json:"app_id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Deleting the API keys revokes both the access and the refresh tokens.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx context.Context, arg DeleteOAuth2ProviderAppTokensByAppAndUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppTokensByAppAndUserID, arg.AppID, arg.UserID)
+	return err
+}
+
+const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
+SELECT id, created_at, updated_at, name, icon, redirect_uris FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		pq.Array(&i.RedirectURIs),
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, scope, redirect_uri FROM oauth2_provider_app_codes WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.Scope,
+		&i.RedirectURI,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppCodeByPrefix = `-- name: GetOAuth2ProviderAppCodeByPrefix :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, scope, redirect_uri FROM oauth2_provider_app_codes WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix string) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByPrefix, secretPrefix)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.Scope,
+		&i.RedirectURI,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT id, app_id, created_at, last_used_at, secret_prefix, hashed_secret, display_secret FROM oauth2_provider_app_secrets WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretByPrefix = `-- name: GetOAuth2ProviderAppSecretByPrefix :one
+SELECT id, app_id, created_at, last_used_at, secret_prefix, hashed_secret, display_secret FROM oauth2_provider_app_secrets WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByPrefix(ctx context.Context, secretPrefix string) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByPrefix, secretPrefix)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderAppSecretsByAppID = `-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT id, app_id, created_at, last_used_at, secret_prefix, hashed_secret, display_secret FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppSecretsByAppID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderAppSecret
+	for rows.Next() {
+		var i OAuth2ProviderAppSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppID,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.SecretPrefix,
+			&i.HashedSecret,
+			&i.DisplaySecret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOAuth2ProviderAppTokenByPrefix = `-- name: GetOAuth2ProviderAppTokenByPrefix :one
+SELECT id, created_at, expires_at, refresh_prefix, hashed_refresh_secret, app_secret_id, api_key_id FROM oauth2_provider_app_tokens WHERE refresh_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, refreshPrefix string) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppTokenByPrefix, refreshPrefix)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RefreshPrefix,
+		&i.HashedRefreshSecret,
+		&i.AppSecretID,
+		&i.APIKeyID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
+SELECT id, created_at, updated_at, name, icon, redirect_uris FROM oauth2_provider_apps ORDER BY (name, id) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderApps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderApp
+	for rows.Next() {
+		var i OAuth2ProviderApp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Icon,
+			pq.Array(&i.RedirectURIs),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOAuth2ProviderAppsByUserID = `-- name: GetOAuth2ProviderAppsByUserID :many
+SELECT
+	oauth2_provider_apps.id, oauth2_provider_apps.created_at, oauth2_provider_apps.updated_at, oauth2_provider_apps.name, oauth2_provider_apps.icon, oauth2_provider_apps.redirect_uris
+FROM
+	oauth2_provider_apps
+WHERE
+	id IN (
+		SELECT
+			oauth2_provider_app_secrets.app_id
+		FROM
+			oauth2_provider_app_tokens
+		INNER JOIN oauth2_provider_app_secrets
+			ON oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+		INNER JOIN api_keys
+			ON api_keys.id = oauth2_provider_app_tokens.api_key_id
+		WHERE
+			api_keys.user_id = $1
+	)
+ORDER BY (name, id) ASC
+`
+
+// Apps that hold at least one API key of the user.
+func (q *sqlQuerier) GetOAuth2ProviderAppsByUserID(ctx context.Context, userID uuid.UUID) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderApp
+	for rows.Next() {
+		var i OAuth2ProviderApp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Icon,
+			pq.Array(&i.RedirectURIs),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertOAuth2ProviderApp = `-- name: InsertOAuth2ProviderApp :one
+INSERT INTO oauth2_provider_apps (
+	id,
+	created_at,
+	updated_at,
+	name,
+	icon,
+	redirect_uris
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING id, created_at, updated_at, name, icon, redirect_uris
+`
+
+type InsertOAuth2ProviderAppParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	Name         string    `db:"name" json:"name"`
+	Icon         string    `db:"icon" json:"icon"`
+	RedirectURIs []string  `db:"redirect_uris" json:"redirect_uris"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderApp,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		pq.Array(arg.RedirectURIs),
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		pq.Array(&i.RedirectURIs),
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppCode = `-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO oauth2_provider_app_codes (
+	id,
+	created_at,
+	expires_at,
+	secret_prefix,
+	hashed_secret,
+	user_id,
+	app_id,
+	scope,
+	redirect_uri
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9
+) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, scope, redirect_uri
+`
+
+type InsertOAuth2ProviderAppCodeParams struct {
+	ID           uuid.UUID   `db:"id" json:"id"`
+	CreatedAt    time.Time   `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time   `db:"expires_at" json:"expires_at"`
+	SecretPrefix string      `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte      `db:"hashed_secret" json:"hashed_secret"`
+	UserID       uuid.UUID   `db:"user_id" json:"user_id"`
+	AppID        uuid.UUID   `db:"app_id" json:"app_id"`
+	Scope        APIKeyScope `db:"scope" json:"scope"`
+	RedirectURI  string      `db:"redirect_uri" json:"redirect_uri"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppCode,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.SecretPrefix,
+		arg.HashedSecret,
+		arg.UserID,
+		arg.AppID,
+		arg.Scope,
+		arg.RedirectURI,
+	)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.Scope,
+		&i.RedirectURI,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppSecret = `-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO oauth2_provider_app_secrets (
+	id,
+	created_at,
+	secret_prefix,
+	hashed_secret,
+	display_secret,
+	app_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING id, app_id, created_at, last_used_at, secret_prefix, hashed_secret, display_secret
+`
+
+type InsertOAuth2ProviderAppSecretParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	SecretPrefix  string    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppSecret,
+		arg.ID,
+		arg.CreatedAt,
+		arg.SecretPrefix,
+		arg.HashedSecret,
+		arg.DisplaySecret,
+		arg.AppID,
+	)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppToken = `-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO oauth2_provider_app_tokens (
+	id,
+	created_at,
+	expires_at,
+	refresh_prefix,
+	hashed_refresh_secret,
+	app_secret_id,
+	api_key_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7
+) RETURNING id, created_at, expires_at, refresh_prefix, hashed_refresh_secret, app_secret_id, api_key_id
+`
+
+type InsertOAuth2ProviderAppTokenParams struct {
+	ID                  uuid.UUID `db:"id" json:"id"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt           time.Time `db:"expires_at" json:"expires_at"`
+	RefreshPrefix       string    `db:"refresh_prefix" json:"refresh_prefix"`
+	HashedRefreshSecret []byte    `db:"hashed_refresh_secret" json:"hashed_refresh_secret"`
+	AppSecretID         uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID            string    `db:"api_key_id" json:"api_key_id"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppToken,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.RefreshPrefix,
+		arg.HashedRefreshSecret,
+		arg.AppSecretID,
+		arg.APIKeyID,
+	)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RefreshPrefix,
+		&i.HashedRefreshSecret,
+		&i.AppSecretID,
+		&i.APIKeyID,
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppByID = `-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE oauth2_provider_apps SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	redirect_uris = $5
+WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, redirect_uris
+`
+
+type UpdateOAuth2ProviderAppByIDParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	Name         string    `db:"name" json:"name"`
+	Icon         string    `db:"icon" json:"icon"`
+	RedirectURIs []string  `db:"redirect_uris" json:"redirect_uris"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		pq.Array(arg.RedirectURIs),
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		pq.Array(&i.RedirectURIs),
+	)
+	return i, err
+}
+
+const updateOAuth2ProviderAppSecretByID = `-- name: UpdateOAuth2ProviderAppSecretByID :one
+UPDATE oauth2_provider_app_secrets SET
+	last_used_at = $2
+WHERE id = $1 RETURNING id, app_id, created_at, last_used_at, secret_prefix, hashed_secret, display_secret
+`
+
+type UpdateOAuth2ProviderAppSecretByIDParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppSecretByID, arg.ID, arg.LastUsedAt)
+	var i OAuth2ProviderAppSecret
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+	)
+	return i, err
+}
+
 const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
 SELECT
     user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
@@ -2277,7 +2899,7 @@ func (q *sqlQuerier) ParameterValues(ctx context.Context, arg ParameterValuesPar
 
 const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
 SELECT
-	id, created_at, updated_at, name, provisioners, replica_id, tags
+	id, created_at, updated_at, name, provisioners, replica_id, tags, last_seen_at, version, hostname, operating_system, architecture, concurrency, current_job_id
 FROM
 	provisioner_daemons
 `
@@ -2299,6 +2921,13 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 			pq.Array(&i.Provisioners),
 			&i.ReplicaID,
 			&i.Tags,
+			&i.LastSeenAt,
+			&i.Version,
+			&i.Hostname,
+			&i.OperatingSystem,
+			&i.Architecture,
+			&i.Concurrency,
+			&i.CurrentJobID,
 		); err != nil {
 			return nil, err
 		}
@@ -2323,7 +2952,7 @@ INSERT INTO
 		tags
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags
+	($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, name, provisioners, replica_id, tags, last_seen_at, version, hostname, operating_system, architecture, concurrency, current_job_id
 `
 
 type InsertProvisionerDaemonParams struct {
@@ -2351,13 +2980,60 @@ func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProv
 		pq.Array(&i.Provisioners),
 		&i.ReplicaID,
 		&i.Tags,
+		&i.LastSeenAt,
+		&i.Version,
+		&i.Hostname,
+		&i.OperatingSystem,
+		&i.Architecture,
+		&i.Concurrency,
+		&i.CurrentJobID,
 	)
 	return i, err
 }
 
+const updateProvisionerDaemonHeartbeat = `-- name: UpdateProvisionerDaemonHeartbeat :exec
+UPDATE
+	provisioner_daemons
+SET
+	last_seen_at = $1,
+	version = $2,
+	hostname = $3,
+	operating_system = $4,
+	architecture = $5,
+	concurrency = $6,
+	current_job_id = $7
+WHERE
+	id = $8
+`
+
+type UpdateProvisionerDaemonHeartbeatParams struct {
+	LastSeenAt      sql.NullTime  `db:"last_seen_at" json:"last_seen_at"`
+	Version         string        `db:"version" json:"version"`
+	Hostname        string        `db:"hostname" json:"hostname"`
+	OperatingSystem string        `db:"operating_system" json:"operating_system"`
+	Architecture    string        `db:"architecture" json:"architecture"`
+	Concurrency     int32         `db:"concurrency" json:"concurrency"`
+	CurrentJobID    uuid.NullUUID `db:"current_job_id" json:"current_job_id"`
+	ID              uuid.UUID     `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerDaemonHeartbeat(ctx context.Context, arg UpdateProvisionerDaemonHeartbeatParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonHeartbeat,
+		arg.LastSeenAt,
+		arg.Version,
+		arg.Hostname,
+		arg.OperatingSystem,
+		arg.Architecture,
+		arg.Concurrency,
+		arg.CurrentJobID,
+		arg.ID,
+	)
+	return err
+}
+
 const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
 SELECT
-	job_id, created_at, source, level, stage, output, id
+	job_id, created_at, source, level, stage, output, id, resource_address, resource_action, resource_status, resource_elapsed_seconds
 FROM
 	provisioner_job_logs
 WHERE
@@ -2389,6 +3065,10 @@ func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvi
 			&i.Stage,
 			&i.Output,
 			&i.ID,
+			&i.ResourceAddress,
+			&i.ResourceAction,
+			&i.ResourceStatus,
+			&i.ResourceElapsedSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -2405,23 +3085,31 @@ func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvi
 
 const insertProvisionerJobLogs = `-- name: InsertProvisionerJobLogs :many
 INSERT INTO
-	provisioner_job_logs
+	provisioner_job_logs (job_id, created_at, source, level, stage, output, resource_address, resource_action, resource_status, resource_elapsed_seconds)
 SELECT
 	$1 :: uuid AS job_id,
 	unnest($2 :: timestamptz [ ]) AS created_at,
 	unnest($3 :: log_source [ ]) AS source,
 	unnest($4 :: log_level [ ]) AS LEVEL,
 	unnest($5 :: VARCHAR(128) [ ]) AS stage,
-	unnest($6 :: VARCHAR(1024) [ ]) AS output RETURNING job_id, created_at, source, level, stage, output, id
+	unnest($6 :: VARCHAR(1024) [ ]) AS output,
+	unnest($7 :: text [ ]) AS resource_address,
+	unnest($8 :: text [ ]) AS resource_action,
+	unnest($9 :: text [ ]) AS resource_status,
+	unnest($10 :: integer [ ]) AS resource_elapsed_seconds RETURNING job_id, created_at, source, level, stage, output, id, resource_address, resource_action, resource_status, resource_elapsed_seconds
 `
 
 type InsertProvisionerJobLogsParams struct {
-	JobID     uuid.UUID   `db:"job_id" json:"job_id"`
-	CreatedAt []time.Time `db:"created_at" json:"created_at"`
-	Source    []LogSource `db:"source" json:"source"`
-	Level     []LogLevel  `db:"level" json:"level"`
-	Stage     []string    `db:"stage" json:"stage"`
-	Output    []string    `db:"output" json:"output"`
+	JobID                  uuid.UUID   `db:"job_id" json:"job_id"`
+	CreatedAt              []time.Time `db:"created_at" json:"created_at"`
+	Source                 []LogSource `db:"source" json:"source"`
+	Level                  []LogLevel  `db:"level" json:"level"`
+	Stage                  []string    `db:"stage" json:"stage"`
+	Output                 []string    `db:"output" json:"output"`
+	ResourceAddress        []string    `db:"resource_address" json:"resource_address"`
+	ResourceAction         []string    `db:"resource_action" json:"resource_action"`
+	ResourceStatus         []string    `db:"resource_status" json:"resource_status"`
+	ResourceElapsedSeconds []int32     `db:"resource_elapsed_seconds" json:"resource_elapsed_seconds"`
 }
 
 func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error) {
@@ -2432,6 +3120,10 @@ func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertPro
 		pq.Array(arg.Level),
 		pq.Array(arg.Stage),
 		pq.Array(arg.Output),
+		pq.Array(arg.ResourceAddress),
+		pq.Array(arg.ResourceAction),
+		pq.Array(arg.ResourceStatus),
+		pq.Array(arg.ResourceElapsedSeconds),
 	)
 	if err != nil {
 		return nil, err
@@ -2448,6 +3140,10 @@ func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertPro
 			&i.Stage,
 			&i.Output,
 			&i.ID,
+			&i.ResourceAddress,
+			&i.ResourceAction,
+			&i.ResourceStatus,
+			&i.ResourceElapsedSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -2487,7 +3183,7 @@ WHERE
 		SKIP LOCKED
 		LIMIT
 			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, has_matching_daemon
 `
 
 type AcquireProvisionerJobParams struct {
@@ -2529,13 +3225,116 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		&i.FileID,
 		&i.Tags,
 		&i.ErrorCode,
+		&i.HasMatchingDaemon,
 	)
 	return i, err
 }
 
+const getPendingProvisionerJobCountsByTags = `-- name: GetPendingProvisionerJobCountsByTags :many
+SELECT
+	provisioner,
+	tags,
+	COUNT(*) AS count
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL
+GROUP BY
+	provisioner,
+	tags
+`
+
+type GetPendingProvisionerJobCountsByTagsRow struct {
+	Provisioner ProvisionerType  `db:"provisioner" json:"provisioner"`
+	Tags        dbtype.StringMap `db:"tags" json:"tags"`
+	Count       int64            `db:"count" json:"count"`
+}
+
+// Counts jobs waiting for a daemon, grouped by the provisioner and tags
+// a daemon must satisfy to acquire them.
+func (q *sqlQuerier) GetPendingProvisionerJobCountsByTags(ctx context.Context) ([]GetPendingProvisionerJobCountsByTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingProvisionerJobCountsByTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPendingProvisionerJobCountsByTagsRow
+	for rows.Next() {
+		var i GetPendingProvisionerJobCountsByTagsRow
+		if err := rows.Scan(&i.Provisioner, &i.Tags, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPendingProvisionerJobs = `-- name: GetPendingProvisionerJobs :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, has_matching_daemon
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL
+ORDER BY
+	created_at
+`
+
+func (q *sqlQuerier) GetPendingProvisionerJobs(ctx context.Context) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingProvisionerJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJob
+	for rows.Next() {
+		var i ProvisionerJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.HasMatchingDaemon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, has_matching_daemon
 FROM
 	provisioner_jobs
 WHERE
@@ -2563,13 +3362,14 @@ func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (P
 		&i.FileID,
 		&i.Tags,
 		&i.ErrorCode,
+		&i.HasMatchingDaemon,
 	)
 	return i, err
 }
 
 const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, has_matching_daemon
 FROM
 	provisioner_jobs
 WHERE
@@ -2603,6 +3403,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 			&i.FileID,
 			&i.Tags,
 			&i.ErrorCode,
+			&i.HasMatchingDaemon,
 		); err != nil {
 			return nil, err
 		}
@@ -2618,7 +3419,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 }
 
 const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code FROM provisioner_jobs WHERE created_at > $1
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, has_matching_daemon FROM provisioner_jobs WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
@@ -2648,6 +3449,7 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 			&i.FileID,
 			&i.Tags,
 			&i.ErrorCode,
+			&i.HasMatchingDaemon,
 		); err != nil {
 			return nil, err
 		}
@@ -2675,24 +3477,26 @@ INSERT INTO
 		file_id,
 		"type",
 		"input",
-		tags
+		tags,
+		has_matching_daemon
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, has_matching_daemon
 `
 
 type InsertProvisionerJobParams struct {
-	ID             uuid.UUID                `db:"id" json:"id"`
-	CreatedAt      time.Time                `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time                `db:"updated_at" json:"updated_at"`
-	OrganizationID uuid.UUID                `db:"organization_id" json:"organization_id"`
-	InitiatorID    uuid.UUID                `db:"initiator_id" json:"initiator_id"`
-	Provisioner    ProvisionerType          `db:"provisioner" json:"provisioner"`
-	StorageMethod  ProvisionerStorageMethod `db:"storage_method" json:"storage_method"`
-	FileID         uuid.UUID                `db:"file_id" json:"file_id"`
-	Type           ProvisionerJobType       `db:"type" json:"type"`
-	Input          json.RawMessage          `db:"input" json:"input"`
-	Tags           dbtype.StringMap         `db:"tags" json:"tags"`
+	ID                uuid.UUID                `db:"id" json:"id"`
+	CreatedAt         time.Time                `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time                `db:"updated_at" json:"updated_at"`
+	OrganizationID    uuid.UUID                `db:"organization_id" json:"organization_id"`
+	InitiatorID       uuid.UUID                `db:"initiator_id" json:"initiator_id"`
+	Provisioner       ProvisionerType          `db:"provisioner" json:"provisioner"`
+	StorageMethod     ProvisionerStorageMethod `db:"storage_method" json:"storage_method"`
+	FileID            uuid.UUID                `db:"file_id" json:"file_id"`
+	Type              ProvisionerJobType       `db:"type" json:"type"`
+	Input             json.RawMessage          `db:"input" json:"input"`
+	Tags              dbtype.StringMap         `db:"tags" json:"tags"`
+	HasMatchingDaemon bool                     `db:"has_matching_daemon" json:"has_matching_daemon"`
 }
 
 func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
@@ -2708,6 +3512,7 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		arg.Type,
 		arg.Input,
 		arg.Tags,
+		arg.HasMatchingDaemon,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -2728,10 +3533,44 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		&i.FileID,
 		&i.Tags,
 		&i.ErrorCode,
+		&i.HasMatchingDaemon,
 	)
 	return i, err
 }
 
+const updatePendingProvisionerJobWithFailureByID = `-- name: UpdatePendingProvisionerJobWithFailureByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	started_at = $1 :: timestamptz,
+	updated_at = $1 :: timestamptz,
+	completed_at = $1 :: timestamptz,
+	error = $2,
+	error_code = $3
+WHERE
+	id = $4
+	AND started_at IS NULL
+`
+
+type UpdatePendingProvisionerJobWithFailureByIDParams struct {
+	FailedAt  time.Time      `db:"failed_at" json:"failed_at"`
+	Error     sql.NullString `db:"error" json:"error"`
+	ErrorCode sql.NullString `db:"error_code" json:"error_code"`
+	ID        uuid.UUID      `db:"id" json:"id"`
+}
+
+// Fails a job that no daemon has acquired yet. The started_at guard avoids
+// racing with a daemon acquiring the job.
+func (q *sqlQuerier) UpdatePendingProvisionerJobWithFailureByID(ctx context.Context, arg UpdatePendingProvisionerJobWithFailureByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updatePendingProvisionerJobWithFailureByID,
+		arg.FailedAt,
+		arg.Error,
+		arg.ErrorCode,
+		arg.ID,
+	)
+	return err
+}
+
 const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
 UPDATE
 	provisioner_jobs
@@ -2751,6 +3590,25 @@ func (q *sqlQuerier) UpdateProvisionerJobByID(ctx context.Context, arg UpdatePro
 	return err
 }
 
+const updateProvisionerJobHasMatchingDaemonByID = `-- name: UpdateProvisionerJobHasMatchingDaemonByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	has_matching_daemon = $2
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobHasMatchingDaemonByIDParams struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	HasMatchingDaemon bool      `db:"has_matching_daemon" json:"has_matching_daemon"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobHasMatchingDaemonByID(ctx context.Context, arg UpdateProvisionerJobHasMatchingDaemonByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobHasMatchingDaemonByID, arg.ID, arg.HasMatchingDaemon)
+	return err
+}
+
 const updateProvisionerJobWithCancelByID = `-- name: UpdateProvisionerJobWithCancelByID :exec
 UPDATE
 	provisioner_jobs
@@ -3010,6 +3868,64 @@ func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams)
 	return i, err
 }
 
+const getServiceAccountByUserID = `-- name: GetServiceAccountByUserID :one
+SELECT
+	user_id, owner_user_id, owner_group_id, created_at
+FROM
+	service_accounts
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetServiceAccountByUserID(ctx context.Context, userID uuid.UUID) (ServiceAccount, error) {
+	row := q.db.QueryRowContext(ctx, getServiceAccountByUserID, userID)
+	var i ServiceAccount
+	err := row.Scan(
+		&i.UserID,
+		&i.OwnerUserID,
+		&i.OwnerGroupID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertServiceAccount = `-- name: InsertServiceAccount :one
+INSERT INTO
+	service_accounts (
+		user_id,
+		owner_user_id,
+		owner_group_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+RETURNING user_id, owner_user_id, owner_group_id, created_at
+`
+
+type InsertServiceAccountParams struct {
+	UserID       uuid.UUID     `db:"user_id" json:"user_id"`
+	OwnerUserID  uuid.NullUUID `db:"owner_user_id" json:"owner_user_id"`
+	OwnerGroupID uuid.NullUUID `db:"owner_group_id" json:"owner_group_id"`
+	CreatedAt    time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertServiceAccount(ctx context.Context, arg InsertServiceAccountParams) (ServiceAccount, error) {
+	row := q.db.QueryRowContext(ctx, insertServiceAccount,
+		arg.UserID,
+		arg.OwnerUserID,
+		arg.OwnerGroupID,
+		arg.CreatedAt,
+	)
+	var i ServiceAccount
+	err := row.Scan(
+		&i.UserID,
+		&i.OwnerUserID,
+		&i.OwnerGroupID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getAppSigningKey = `-- name: GetAppSigningKey :one
 SELECT value FROM site_configs WHERE key = 'app_signing_key'
 `
@@ -3128,9 +4044,463 @@ INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
 `
 
-func (q *sqlQuerier) UpsertServiceBanner(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertServiceBanner, value)
-	return err
+func (q *sqlQuerier) UpsertServiceBanner(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertServiceBanner, value)
+	return err
+}
+
+const deleteTailnetAgentVersionsUpdatedBefore = `-- name: DeleteTailnetAgentVersionsUpdatedBefore :exec
+DELETE FROM tailnet_agent_versions
+WHERE
+	updated_at < $1
+	-- Connections can't resume to agents without nodes that aren't in the
+	-- latest build of a workspace, so their versions are no longer needed.
+	AND NOT EXISTS (
+		SELECT 1 FROM tailnet_nodes WHERE tailnet_nodes.agent_id = tailnet_agent_versions.agent_id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM workspace_agents
+		JOIN workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+		JOIN workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+		WHERE
+			workspace_agents.id = tailnet_agent_versions.agent_id
+			AND workspace_builds.build_number = (
+				SELECT MAX(latest_builds.build_number) FROM workspace_builds AS latest_builds
+				WHERE latest_builds.workspace_id = workspace_builds.workspace_id
+			)
+	)
+`
+
+func (q *sqlQuerier) DeleteTailnetAgentVersionsUpdatedBefore(ctx context.Context, updatedBefore time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteTailnetAgentVersionsUpdatedBefore, updatedBefore)
+	return err
+}
+
+const deleteTailnetCoordinator = `-- name: DeleteTailnetCoordinator :exec
+DELETE FROM tailnet_coordinators WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteTailnetCoordinator(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTailnetCoordinator, id)
+	return err
+}
+
+const deleteTailnetCoordinatorsHeartbeatBefore = `-- name: DeleteTailnetCoordinatorsHeartbeatBefore :exec
+DELETE FROM tailnet_coordinators WHERE heartbeat_at < $1
+`
+
+func (q *sqlQuerier) DeleteTailnetCoordinatorsHeartbeatBefore(ctx context.Context, heartbeatAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteTailnetCoordinatorsHeartbeatBefore, heartbeatAt)
+	return err
+}
+
+const deleteTailnetNode = `-- name: DeleteTailnetNode :exec
+DELETE FROM tailnet_nodes WHERE id = $1 AND coordinator_id = $2
+`
+
+type DeleteTailnetNodeParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetNode(ctx context.Context, arg DeleteTailnetNodeParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTailnetNode, arg.ID, arg.CoordinatorID)
+	return err
+}
+
+const getTailnetAgentNode = `-- name: GetTailnetAgentNode :one
+SELECT id, coordinator_id, agent_id, node, version, updated_at FROM tailnet_nodes WHERE id = $1 AND agent_id = $1
+`
+
+func (q *sqlQuerier) GetTailnetAgentNode(ctx context.Context, agentID uuid.UUID) (TailnetNode, error) {
+	row := q.db.QueryRowContext(ctx, getTailnetAgentNode, agentID)
+	var i TailnetNode
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.AgentID,
+		&i.Node,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTailnetClientNodesAfterVersion = `-- name: GetTailnetClientNodesAfterVersion :many
+SELECT id, coordinator_id, agent_id, node, version, updated_at FROM tailnet_nodes
+WHERE agent_id = $1 AND id != $1 AND version > $2
+ORDER BY version
+`
+
+type GetTailnetClientNodesAfterVersionParams struct {
+	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
+	AfterVersion int64     `db:"after_version" json:"after_version"`
+}
+
+func (q *sqlQuerier) GetTailnetClientNodesAfterVersion(ctx context.Context, arg GetTailnetClientNodesAfterVersionParams) ([]TailnetNode, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetClientNodesAfterVersion, arg.AgentID, arg.AfterVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetNode
+	for rows.Next() {
+		var i TailnetNode
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.AgentID,
+			&i.Node,
+			&i.Version,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTailnetAgentNode = `-- name: UpsertTailnetAgentNode :one
+WITH agent_version AS (
+	INSERT INTO tailnet_agent_versions (agent_id, node_version, updated_at) VALUES ($1, 1, NOW())
+	ON CONFLICT (agent_id) DO UPDATE SET
+		node_version = tailnet_agent_versions.node_version + 1,
+		updated_at = NOW()
+	RETURNING node_version
+)
+INSERT INTO tailnet_nodes (id, coordinator_id, agent_id, node, version, updated_at)
+VALUES ($1, $2, $1, $3, (SELECT node_version FROM agent_version), NOW())
+ON CONFLICT (id) DO UPDATE SET
+	coordinator_id = EXCLUDED.coordinator_id,
+	node = EXCLUDED.node,
+	version = EXCLUDED.version,
+	updated_at = EXCLUDED.updated_at
+RETURNING id, coordinator_id, agent_id, node, version, updated_at
+`
+
+type UpsertTailnetAgentNodeParams struct {
+	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
+	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
+	Node          json.RawMessage `db:"node" json:"node"`
+}
+
+func (q *sqlQuerier) UpsertTailnetAgentNode(ctx context.Context, arg UpsertTailnetAgentNodeParams) (TailnetNode, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetAgentNode, arg.AgentID, arg.CoordinatorID, arg.Node)
+	var i TailnetNode
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.AgentID,
+		&i.Node,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTailnetClientNode = `-- name: UpsertTailnetClientNode :one
+WITH agent_version AS (
+	INSERT INTO tailnet_agent_versions (agent_id, peer_version, updated_at) VALUES ($1, 1, NOW())
+	ON CONFLICT (agent_id) DO UPDATE SET
+		peer_version = tailnet_agent_versions.peer_version + 1,
+		updated_at = NOW()
+	RETURNING peer_version
+)
+INSERT INTO tailnet_nodes (id, coordinator_id, agent_id, node, version, updated_at)
+VALUES ($2, $3, $1, $4, (SELECT peer_version FROM agent_version), NOW())
+ON CONFLICT (id) DO UPDATE SET
+	coordinator_id = EXCLUDED.coordinator_id,
+	agent_id = EXCLUDED.agent_id,
+	node = EXCLUDED.node,
+	version = EXCLUDED.version,
+	updated_at = EXCLUDED.updated_at
+RETURNING id, coordinator_id, agent_id, node, version, updated_at
+`
+
+type UpsertTailnetClientNodeParams struct {
+	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
+	Node          json.RawMessage `db:"node" json:"node"`
+}
+
+func (q *sqlQuerier) UpsertTailnetClientNode(ctx context.Context, arg UpsertTailnetClientNodeParams) (TailnetNode, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetClientNode,
+		arg.AgentID,
+		arg.ID,
+		arg.CoordinatorID,
+		arg.Node,
+	)
+	var i TailnetNode
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.AgentID,
+		&i.Node,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTailnetCoordinator = `-- name: UpsertTailnetCoordinator :one
+INSERT INTO tailnet_coordinators (id, heartbeat_at) VALUES ($1, NOW())
+ON CONFLICT (id) DO UPDATE SET heartbeat_at = NOW()
+RETURNING id, heartbeat_at
+`
+
+func (q *sqlQuerier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetCoordinator, id)
+	var i TailnetCoordinator
+	err := row.Scan(&i.ID, &i.HeartbeatAt)
+	return i, err
+}
+
+const deleteTemplateAppLimits = `-- name: DeleteTemplateAppLimits :exec
+DELETE FROM
+	template_app_limits
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplateAppLimits(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateAppLimits, templateID)
+	return err
+}
+
+const getTemplateAppLimits = `-- name: GetTemplateAppLimits :one
+SELECT
+	template_id, app_requests_per_minute, app_bandwidth_bytes_per_second, workspace_requests_per_minute, workspace_bandwidth_bytes_per_second, updated_at
+FROM
+	template_app_limits
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateAppLimits(ctx context.Context, templateID uuid.UUID) (TemplateAppLimit, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAppLimits, templateID)
+	var i TemplateAppLimit
+	err := row.Scan(
+		&i.TemplateID,
+		&i.AppRequestsPerMinute,
+		&i.AppBandwidthBytesPerSecond,
+		&i.WorkspaceRequestsPerMinute,
+		&i.WorkspaceBandwidthBytesPerSecond,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplateAppLimits = `-- name: UpsertTemplateAppLimits :one
+INSERT INTO
+	template_app_limits (
+		template_id,
+		app_requests_per_minute,
+		app_bandwidth_bytes_per_second,
+		workspace_requests_per_minute,
+		workspace_bandwidth_bytes_per_second,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+ON CONFLICT (template_id) DO UPDATE
+SET
+	app_requests_per_minute = EXCLUDED.app_requests_per_minute,
+	app_bandwidth_bytes_per_second = EXCLUDED.app_bandwidth_bytes_per_second,
+	workspace_requests_per_minute = EXCLUDED.workspace_requests_per_minute,
+	workspace_bandwidth_bytes_per_second = EXCLUDED.workspace_bandwidth_bytes_per_second,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, app_requests_per_minute, app_bandwidth_bytes_per_second, workspace_requests_per_minute, workspace_bandwidth_bytes_per_second, updated_at
+`
+
+type UpsertTemplateAppLimitsParams struct {
+	TemplateID                       uuid.UUID `db:"template_id" json:"template_id"`
+	AppRequestsPerMinute             int64     `db:"app_requests_per_minute" json:"app_requests_per_minute"`
+	AppBandwidthBytesPerSecond       int64     `db:"app_bandwidth_bytes_per_second" json:"app_bandwidth_bytes_per_second"`
+	WorkspaceRequestsPerMinute       int64     `db:"workspace_requests_per_minute" json:"workspace_requests_per_minute"`
+	WorkspaceBandwidthBytesPerSecond int64     `db:"workspace_bandwidth_bytes_per_second" json:"workspace_bandwidth_bytes_per_second"`
+	UpdatedAt                        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertTemplateAppLimits(ctx context.Context, arg UpsertTemplateAppLimitsParams) (TemplateAppLimit, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateAppLimits,
+		arg.TemplateID,
+		arg.AppRequestsPerMinute,
+		arg.AppBandwidthBytesPerSecond,
+		arg.WorkspaceRequestsPerMinute,
+		arg.WorkspaceBandwidthBytesPerSecond,
+		arg.UpdatedAt,
+	)
+	var i TemplateAppLimit
+	err := row.Scan(
+		&i.TemplateID,
+		&i.AppRequestsPerMinute,
+		&i.AppBandwidthBytesPerSecond,
+		&i.WorkspaceRequestsPerMinute,
+		&i.WorkspaceBandwidthBytesPerSecond,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTemplateAppRouting = `-- name: DeleteTemplateAppRouting :exec
+DELETE FROM
+	template_app_routing
+WHERE
+	template_id = $1
+	AND app_slug = $2
+`
+
+type DeleteTemplateAppRoutingParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	AppSlug    string    `db:"app_slug" json:"app_slug"`
+}
+
+func (q *sqlQuerier) DeleteTemplateAppRouting(ctx context.Context, arg DeleteTemplateAppRoutingParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateAppRouting, arg.TemplateID, arg.AppSlug)
+	return err
+}
+
+const getTemplateAppRouting = `-- name: GetTemplateAppRouting :one
+SELECT
+	template_id, app_slug, strip_prefix, base_path_header, headers, allowed_methods, websocket_subprotocols, updated_at
+FROM
+	template_app_routing
+WHERE
+	template_id = $1
+	AND app_slug = $2
+`
+
+type GetTemplateAppRoutingParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	AppSlug    string    `db:"app_slug" json:"app_slug"`
+}
+
+func (q *sqlQuerier) GetTemplateAppRouting(ctx context.Context, arg GetTemplateAppRoutingParams) (TemplateAppRouting, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAppRouting, arg.TemplateID, arg.AppSlug)
+	var i TemplateAppRouting
+	err := row.Scan(
+		&i.TemplateID,
+		&i.AppSlug,
+		&i.StripPrefix,
+		&i.BasePathHeader,
+		&i.Headers,
+		pq.Array(&i.AllowedMethods),
+		pq.Array(&i.WebsocketSubprotocols),
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateAppRoutingByTemplateID = `-- name: GetTemplateAppRoutingByTemplateID :many
+SELECT
+	template_id, app_slug, strip_prefix, base_path_header, headers, allowed_methods, websocket_subprotocols, updated_at
+FROM
+	template_app_routing
+WHERE
+	template_id = $1
+ORDER BY
+	app_slug ASC
+`
+
+func (q *sqlQuerier) GetTemplateAppRoutingByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateAppRouting, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAppRoutingByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateAppRouting
+	for rows.Next() {
+		var i TemplateAppRouting
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.AppSlug,
+			&i.StripPrefix,
+			&i.BasePathHeader,
+			&i.Headers,
+			pq.Array(&i.AllowedMethods),
+			pq.Array(&i.WebsocketSubprotocols),
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateAppRouting = `-- name: UpsertTemplateAppRouting :one
+INSERT INTO
+	template_app_routing (
+		template_id,
+		app_slug,
+		strip_prefix,
+		base_path_header,
+		headers,
+		allowed_methods,
+		websocket_subprotocols,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (template_id, app_slug) DO UPDATE
+SET
+	strip_prefix = EXCLUDED.strip_prefix,
+	base_path_header = EXCLUDED.base_path_header,
+	headers = EXCLUDED.headers,
+	allowed_methods = EXCLUDED.allowed_methods,
+	websocket_subprotocols = EXCLUDED.websocket_subprotocols,
+	updated_at = EXCLUDED.updated_at
+RETURNING template_id, app_slug, strip_prefix, base_path_header, headers, allowed_methods, websocket_subprotocols, updated_at
+`
+
+type UpsertTemplateAppRoutingParams struct {
+	TemplateID            uuid.UUID        `db:"template_id" json:"template_id"`
+	AppSlug               string           `db:"app_slug" json:"app_slug"`
+	StripPrefix           bool             `db:"strip_prefix" json:"strip_prefix"`
+	BasePathHeader        string           `db:"base_path_header" json:"base_path_header"`
+	Headers               dbtype.StringMap `db:"headers" json:"headers"`
+	AllowedMethods        []string         `db:"allowed_methods" json:"allowed_methods"`
+	WebsocketSubprotocols []string         `db:"websocket_subprotocols" json:"websocket_subprotocols"`
+	UpdatedAt             time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertTemplateAppRouting(ctx context.Context, arg UpsertTemplateAppRoutingParams) (TemplateAppRouting, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateAppRouting,
+		arg.TemplateID,
+		arg.AppSlug,
+		arg.StripPrefix,
+		arg.BasePathHeader,
+		arg.Headers,
+		pq.Array(arg.AllowedMethods),
+		pq.Array(arg.WebsocketSubprotocols),
+		arg.UpdatedAt,
+	)
+	var i TemplateAppRouting
+	err := row.Scan(
+		&i.TemplateID,
+		&i.AppSlug,
+		&i.StripPrefix,
+		&i.BasePathHeader,
+		&i.Headers,
+		pq.Array(&i.AllowedMethods),
+		pq.Array(&i.WebsocketSubprotocols),
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
 const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
@@ -4505,6 +5875,413 @@ func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinke
 	return i, err
 }
 
+const deleteExpiredUserWebAuthnChallenges = `-- name: DeleteExpiredUserWebAuthnChallenges :exec
+DELETE FROM
+	user_webauthn_challenges
+WHERE
+	expires_at < NOW()
+`
+
+func (q *sqlQuerier) DeleteExpiredUserWebAuthnChallenges(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredUserWebAuthnChallenges)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserTOTP(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTOTP, userID)
+	return err
+}
+
+const deleteUserWebAuthnChallenge = `-- name: DeleteUserWebAuthnChallenge :one
+DELETE FROM
+	user_webauthn_challenges
+WHERE
+	challenge = $1
+	AND user_id = $2
+	AND ceremony = $3
+RETURNING challenge, user_id, ceremony, created_at, expires_at
+`
+
+type DeleteUserWebAuthnChallengeParams struct {
+	Challenge []byte    `db:"challenge" json:"challenge"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Ceremony  string    `db:"ceremony" json:"ceremony"`
+}
+
+// Returns no rows if the challenge was already answered, so a response can't
+// be replayed.
+func (q *sqlQuerier) DeleteUserWebAuthnChallenge(ctx context.Context, arg DeleteUserWebAuthnChallengeParams) (UserWebAuthnChallenge, error) {
+	row := q.db.QueryRowContext(ctx, deleteUserWebAuthnChallenge, arg.Challenge, arg.UserID, arg.Ceremony)
+	var i UserWebAuthnChallenge
+	err := row.Scan(
+		&i.Challenge,
+		&i.UserID,
+		&i.Ceremony,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const deleteUserWebAuthnCredential = `-- name: DeleteUserWebAuthnCredential :exec
+DELETE FROM
+	user_webauthn_credentials
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteUserWebAuthnCredential(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserWebAuthnCredential, id)
+	return err
+}
+
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT
+	user_id, secret, confirmed, hashed_recovery_codes, last_used_step, created_at, updated_at
+FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserTOTP(ctx context.Context, userID uuid.UUID) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTP, userID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Confirmed,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserWebAuthnCredentialByID = `-- name: GetUserWebAuthnCredentialByID :one
+SELECT
+	id, user_id, credential_id, name, public_key, sign_count, created_at, last_used_at
+FROM
+	user_webauthn_credentials
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetUserWebAuthnCredentialByID(ctx context.Context, id uuid.UUID) (UserWebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, getUserWebAuthnCredentialByID, id)
+	var i UserWebAuthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.Name,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getUserWebAuthnCredentialsByUserID = `-- name: GetUserWebAuthnCredentialsByUserID :many
+SELECT
+	id, user_id, credential_id, name, public_key, sign_count, created_at, last_used_at
+FROM
+	user_webauthn_credentials
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetUserWebAuthnCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]UserWebAuthnCredential, error) {
+	rows, err := q.db.QueryContext(ctx, getUserWebAuthnCredentialsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserWebAuthnCredential
+	for rows.Next() {
+		var i UserWebAuthnCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CredentialID,
+			&i.Name,
+			&i.PublicKey,
+			&i.SignCount,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserWebAuthnChallenge = `-- name: InsertUserWebAuthnChallenge :exec
+INSERT INTO
+	user_webauthn_challenges (
+		challenge,
+		user_id,
+		ceremony,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+`
+
+type InsertUserWebAuthnChallengeParams struct {
+	Challenge []byte    `db:"challenge" json:"challenge"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Ceremony  string    `db:"ceremony" json:"ceremony"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) InsertUserWebAuthnChallenge(ctx context.Context, arg InsertUserWebAuthnChallengeParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserWebAuthnChallenge,
+		arg.Challenge,
+		arg.UserID,
+		arg.Ceremony,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const insertUserWebAuthnCredential = `-- name: InsertUserWebAuthnCredential :one
+INSERT INTO
+	user_webauthn_credentials (
+		id,
+		user_id,
+		credential_id,
+		name,
+		public_key,
+		sign_count,
+		created_at,
+		last_used_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, user_id, credential_id, name, public_key, sign_count, created_at, last_used_at
+`
+
+type InsertUserWebAuthnCredentialParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	CredentialID []byte    `db:"credential_id" json:"credential_id"`
+	Name         string    `db:"name" json:"name"`
+	PublicKey    []byte    `db:"public_key" json:"public_key"`
+	SignCount    int64     `db:"sign_count" json:"sign_count"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	LastUsedAt   time.Time `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) InsertUserWebAuthnCredential(ctx context.Context, arg InsertUserWebAuthnCredentialParams) (UserWebAuthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, insertUserWebAuthnCredential,
+		arg.ID,
+		arg.UserID,
+		arg.CredentialID,
+		arg.Name,
+		arg.PublicKey,
+		arg.SignCount,
+		arg.CreatedAt,
+		arg.LastUsedAt,
+	)
+	var i UserWebAuthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.Name,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const updateUserTOTPLastUsedStep = `-- name: UpdateUserTOTPLastUsedStep :one
+UPDATE
+	user_totp
+SET
+	last_used_step = $1,
+	updated_at = $2
+WHERE
+	user_id = $3
+	AND confirmed
+	AND last_used_step < $1
+RETURNING user_id, secret, confirmed, hashed_recovery_codes, last_used_step, created_at, updated_at
+`
+
+type UpdateUserTOTPLastUsedStepParams struct {
+	LastUsedStep int64     `db:"last_used_step" json:"last_used_step"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Returns no rows if a code of the same or a later time step was already
+// used, so concurrent logins can't both use a code.
+func (q *sqlQuerier) UpdateUserTOTPLastUsedStep(ctx context.Context, arg UpdateUserTOTPLastUsedStepParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTOTPLastUsedStep, arg.LastUsedStep, arg.UpdatedAt, arg.UserID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Confirmed,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateUserTOTPRemoveRecoveryCode = `-- name: UpdateUserTOTPRemoveRecoveryCode :one
+UPDATE
+	user_totp
+SET
+	hashed_recovery_codes = array_remove(hashed_recovery_codes, $1 :: text),
+	updated_at = $2
+WHERE
+	user_id = $3
+	AND confirmed
+	AND $1 :: text = ANY(hashed_recovery_codes)
+RETURNING user_id, secret, confirmed, hashed_recovery_codes, last_used_step, created_at, updated_at
+`
+
+type UpdateUserTOTPRemoveRecoveryCodeParams struct {
+	HashedRecoveryCode string    `db:"hashed_recovery_code" json:"hashed_recovery_code"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
+	UserID             uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Returns no rows if the recovery code was already used, so concurrent logins
+// can't both use it.
+func (q *sqlQuerier) UpdateUserTOTPRemoveRecoveryCode(ctx context.Context, arg UpdateUserTOTPRemoveRecoveryCodeParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTOTPRemoveRecoveryCode, arg.HashedRecoveryCode, arg.UpdatedAt, arg.UserID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Confirmed,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateUserWebAuthnCredentialSignCount = `-- name: UpdateUserWebAuthnCredentialSignCount :exec
+UPDATE
+	user_webauthn_credentials
+SET
+	sign_count = $2,
+	last_used_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateUserWebAuthnCredentialSignCountParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	SignCount  int64     `db:"sign_count" json:"sign_count"`
+	LastUsedAt time.Time `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) UpdateUserWebAuthnCredentialSignCount(ctx context.Context, arg UpdateUserWebAuthnCredentialSignCountParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserWebAuthnCredentialSignCount, arg.ID, arg.SignCount, arg.LastUsedAt)
+	return err
+}
+
+const upsertUserTOTP = `-- name: UpsertUserTOTP :one
+INSERT INTO
+	user_totp (
+		user_id,
+		secret,
+		confirmed,
+		hashed_recovery_codes,
+		last_used_step,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id) DO UPDATE SET
+	secret = $2,
+	confirmed = $3,
+	hashed_recovery_codes = $4,
+	last_used_step = $5,
+	updated_at = $7
+RETURNING user_id, secret, confirmed, hashed_recovery_codes, last_used_step, created_at, updated_at
+`
+
+type UpsertUserTOTPParams struct {
+	UserID              uuid.UUID `db:"user_id" json:"user_id"`
+	Secret              string    `db:"secret" json:"secret"`
+	Confirmed           bool      `db:"confirmed" json:"confirmed"`
+	HashedRecoveryCodes []string  `db:"hashed_recovery_codes" json:"hashed_recovery_codes"`
+	LastUsedStep        int64     `db:"last_used_step" json:"last_used_step"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserTOTP,
+		arg.UserID,
+		arg.Secret,
+		arg.Confirmed,
+		pq.Array(arg.HashedRecoveryCodes),
+		arg.LastUsedStep,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Confirmed,
+		pq.Array(&i.HashedRecoveryCodes),
+		&i.LastUsedStep,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getActiveServiceAccountCount = `-- name: GetActiveServiceAccountCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+    status = 'active'::user_status AND deleted = false AND login_type = 'none'::login_type
+`
+
+func (q *sqlQuerier) GetActiveServiceAccountCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveServiceAccountCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getActiveUserCount = `-- name: GetActiveUserCount :one
 SELECT
 	COUNT(*)
@@ -6224,6 +8001,165 @@ func (q *sqlQuerier) InsertWorkspaceAgentStat(ctx context.Context, arg InsertWor
 	return i, err
 }
 
+const deleteOldWorkspaceAppAccessLogs = `-- name: DeleteOldWorkspaceAppAccessLogs :exec
+DELETE FROM workspace_app_access_logs WHERE created_at < $1 :: timestamp with time zone
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAppAccessLogs(ctx context.Context, before time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAppAccessLogs, before)
+	return err
+}
+
+const getWorkspaceAppAccessLogs = `-- name: GetWorkspaceAppAccessLogs :many
+SELECT
+	workspace_app_access_logs.id, workspace_app_access_logs.created_at, workspace_app_access_logs.user_id, workspace_app_access_logs.workspace_id, workspace_app_access_logs.agent_id, workspace_app_access_logs.app_slug_or_port, workspace_app_access_logs.access_method, workspace_app_access_logs.sharing_level, workspace_app_access_logs.ip, workspace_app_access_logs.method, workspace_app_access_logs.path, workspace_app_access_logs.status_code, workspace_app_access_logs.bytes_written, workspace_app_access_logs.duration_ms,
+	users.username AS user_username
+FROM
+	workspace_app_access_logs
+	LEFT JOIN users ON workspace_app_access_logs.user_id = users.id
+WHERE
+	workspace_app_access_logs.workspace_id = $1 :: uuid
+	-- Filter by app slug or port
+	AND CASE
+		WHEN $2 :: text != '' THEN
+			workspace_app_access_logs.app_slug_or_port = $2
+		ELSE true
+	END
+ORDER BY
+	workspace_app_access_logs.created_at DESC OFFSET $3
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($4 :: int, 0)
+`
+
+type GetWorkspaceAppAccessLogsParams struct {
+	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AppSlugOrPort string    `db:"app_slug_or_port" json:"app_slug_or_port"`
+	OffsetOpt     int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt      int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetWorkspaceAppAccessLogsRow struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	UserID        uuid.NullUUID   `db:"user_id" json:"user_id"`
+	WorkspaceID   uuid.NullUUID   `db:"workspace_id" json:"workspace_id"`
+	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
+	AppSlugOrPort string          `db:"app_slug_or_port" json:"app_slug_or_port"`
+	AccessMethod  string          `db:"access_method" json:"access_method"`
+	SharingLevel  AppSharingLevel `db:"sharing_level" json:"sharing_level"`
+	Ip            pqtype.Inet     `db:"ip" json:"ip"`
+	Method        string          `db:"method" json:"method"`
+	Path          string          `db:"path" json:"path"`
+	StatusCode    int32           `db:"status_code" json:"status_code"`
+	BytesWritten  int64           `db:"bytes_written" json:"bytes_written"`
+	DurationMs    int64           `db:"duration_ms" json:"duration_ms"`
+	UserUsername  sql.NullString  `db:"user_username" json:"user_username"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAppAccessLogs(ctx context.Context, arg GetWorkspaceAppAccessLogsParams) ([]GetWorkspaceAppAccessLogsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppAccessLogs,
+		arg.WorkspaceID,
+		arg.AppSlugOrPort,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAppAccessLogsRow
+	for rows.Next() {
+		var i GetWorkspaceAppAccessLogsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.AppSlugOrPort,
+			&i.AccessMethod,
+			&i.SharingLevel,
+			&i.Ip,
+			&i.Method,
+			&i.Path,
+			&i.StatusCode,
+			&i.BytesWritten,
+			&i.DurationMs,
+			&i.UserUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceAppAccessLog = `-- name: InsertWorkspaceAppAccessLog :exec
+INSERT INTO
+	workspace_app_access_logs (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		agent_id,
+		app_slug_or_port,
+		access_method,
+		sharing_level,
+		ip,
+		method,
+		path,
+		status_code,
+		bytes_written,
+		duration_ms
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+`
+
+type InsertWorkspaceAppAccessLogParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	UserID        uuid.NullUUID   `db:"user_id" json:"user_id"`
+	WorkspaceID   uuid.NullUUID   `db:"workspace_id" json:"workspace_id"`
+	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
+	AppSlugOrPort string          `db:"app_slug_or_port" json:"app_slug_or_port"`
+	AccessMethod  string          `db:"access_method" json:"access_method"`
+	SharingLevel  AppSharingLevel `db:"sharing_level" json:"sharing_level"`
+	Ip            pqtype.Inet     `db:"ip" json:"ip"`
+	Method        string          `db:"method" json:"method"`
+	Path          string          `db:"path" json:"path"`
+	StatusCode    int32           `db:"status_code" json:"status_code"`
+	BytesWritten  int64           `db:"bytes_written" json:"bytes_written"`
+	DurationMs    int64           `db:"duration_ms" json:"duration_ms"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAppAccessLog(ctx context.Context, arg InsertWorkspaceAppAccessLogParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAppAccessLog,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.AppSlugOrPort,
+		arg.AccessMethod,
+		arg.SharingLevel,
+		arg.Ip,
+		arg.Method,
+		arg.Path,
+		arg.StatusCode,
+		arg.BytesWritten,
+		arg.DurationMs,
+	)
+	return err
+}
+
 const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
 SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 AND slug = $2
 `
@@ -7068,6 +9004,299 @@ func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg Updat
 	return i, err
 }
 
+const getTemplateBuildStageStats = `-- name: GetTemplateBuildStageStats :many
+SELECT
+	workspace_build_timings.stage,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY EXTRACT(EPOCH FROM (workspace_build_timings.ended_at - workspace_build_timings.started_at)))), -1)::FLOAT AS p50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY EXTRACT(EPOCH FROM (workspace_build_timings.ended_at - workspace_build_timings.started_at)))), -1)::FLOAT AS p95
+FROM
+	workspace_build_timings
+JOIN workspace_builds ON
+	workspace_builds.id = workspace_build_timings.workspace_build_id
+JOIN template_versions ON
+	template_versions.id = workspace_builds.template_version_id
+WHERE
+	template_versions.template_id = $1 AND
+	workspace_builds.transition = 'start' AND
+	workspace_build_timings.ended_at IS NOT NULL AND
+	workspace_build_timings.started_at > $2
+GROUP BY
+	workspace_build_timings.stage
+ORDER BY
+	workspace_build_timings.stage
+`
+
+type GetTemplateBuildStageStatsParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	StartTime  time.Time     `db:"start_time" json:"start_time"`
+}
+
+type GetTemplateBuildStageStatsRow struct {
+	Stage WorkspaceBuildStage `db:"stage" json:"stage"`
+	P50   float64             `db:"p50" json:"p50"`
+	P95   float64             `db:"p95" json:"p95"`
+}
+
+func (q *sqlQuerier) GetTemplateBuildStageStats(ctx context.Context, arg GetTemplateBuildStageStatsParams) ([]GetTemplateBuildStageStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateBuildStageStats, arg.TemplateID, arg.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateBuildStageStatsRow
+	for rows.Next() {
+		var i GetTemplateBuildStageStatsRow
+		if err := rows.Scan(&i.Stage, &i.P50, &i.P95); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBuildTimingsByBuildIDs = `-- name: GetWorkspaceBuildTimingsByBuildIDs :many
+SELECT
+	workspace_build_id, stage, started_at, ended_at, observed
+FROM
+	workspace_build_timings
+WHERE
+	workspace_build_id = ANY($1 :: uuid [ ])
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildTimingsByBuildIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuildTiming, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildTimingsByBuildIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuildTiming
+	for rows.Next() {
+		var i WorkspaceBuildTiming
+		if err := rows.Scan(
+			&i.WorkspaceBuildID,
+			&i.Stage,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Observed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWorkspaceBuildTimingObserved = `-- name: UpdateWorkspaceBuildTimingObserved :one
+UPDATE
+	workspace_build_timings
+SET
+	observed = true
+WHERE
+	workspace_build_id = $1
+	AND stage = $2
+	AND ended_at IS NOT NULL
+	AND NOT observed
+RETURNING
+	workspace_build_id, stage, started_at, ended_at, observed
+`
+
+type UpdateWorkspaceBuildTimingObservedParams struct {
+	WorkspaceBuildID uuid.UUID           `db:"workspace_build_id" json:"workspace_build_id"`
+	Stage            WorkspaceBuildStage `db:"stage" json:"stage"`
+}
+
+// Claims an ended stage to be observed in metrics. No rows are returned if the
+// stage hasn't ended or was already claimed, so only one caller observes it.
+func (q *sqlQuerier) UpdateWorkspaceBuildTimingObserved(ctx context.Context, arg UpdateWorkspaceBuildTimingObservedParams) (WorkspaceBuildTiming, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceBuildTimingObserved, arg.WorkspaceBuildID, arg.Stage)
+	var i WorkspaceBuildTiming
+	err := row.Scan(
+		&i.WorkspaceBuildID,
+		&i.Stage,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Observed,
+	)
+	return i, err
+}
+
+const upsertWorkspaceBuildTiming = `-- name: UpsertWorkspaceBuildTiming :exec
+INSERT INTO
+	workspace_build_timings (workspace_build_id, stage, started_at, ended_at)
+VALUES
+	($1, $2, $3, $4)
+-- Stages that span several agents are reported once per agent, so the stage
+-- is widened to cover the earliest start and the latest end.
+ON CONFLICT (workspace_build_id, stage) DO UPDATE
+SET
+	started_at = LEAST(workspace_build_timings.started_at, EXCLUDED.started_at),
+	ended_at = GREATEST(workspace_build_timings.ended_at, EXCLUDED.ended_at)
+`
+
+type UpsertWorkspaceBuildTimingParams struct {
+	WorkspaceBuildID uuid.UUID           `db:"workspace_build_id" json:"workspace_build_id"`
+	Stage            WorkspaceBuildStage `db:"stage" json:"stage"`
+	StartedAt        time.Time           `db:"started_at" json:"started_at"`
+	EndedAt          sql.NullTime        `db:"ended_at" json:"ended_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceBuildTiming(ctx context.Context, arg UpsertWorkspaceBuildTimingParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceBuildTiming,
+		arg.WorkspaceBuildID,
+		arg.Stage,
+		arg.StartedAt,
+		arg.EndedAt,
+	)
+	return err
+}
+
+const deleteWorkspacePortShare = `-- name: DeleteWorkspacePortShare :exec
+DELETE FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type DeleteWorkspacePortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) DeleteWorkspacePortShare(ctx context.Context, arg DeleteWorkspacePortShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	return err
+}
+
+const getWorkspacePortShare = `-- name: GetWorkspacePortShare :one
+SELECT
+	workspace_id, agent_name, port, share_level, created_at, expires_at
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type GetWorkspacePortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) GetWorkspacePortShare(ctx context.Context, arg GetWorkspacePortShareParams) (WorkspacePortShare, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspacePortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	var i WorkspacePortShare
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getWorkspacePortSharesByWorkspaceID = `-- name: GetWorkspacePortSharesByWorkspaceID :many
+SELECT
+	workspace_id, agent_name, port, share_level, created_at, expires_at
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name ASC, port ASC
+`
+
+func (q *sqlQuerier) GetWorkspacePortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShare, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePortSharesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePortShare
+	for rows.Next() {
+		var i WorkspacePortShare
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.ShareLevel,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspacePortShare = `-- name: UpsertWorkspacePortShare :one
+INSERT INTO
+	workspace_port_shares (workspace_id, agent_name, port, share_level, created_at, expires_at)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+ON CONFLICT (workspace_id, agent_name, port) DO UPDATE
+SET
+	share_level = EXCLUDED.share_level,
+	created_at = EXCLUDED.created_at,
+	expires_at = EXCLUDED.expires_at
+RETURNING workspace_id, agent_name, port, share_level, created_at, expires_at
+`
+
+type UpsertWorkspacePortShareParams struct {
+	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	AgentName   string          `db:"agent_name" json:"agent_name"`
+	Port        int32           `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel `db:"share_level" json:"share_level"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+	ExpiresAt   sql.NullTime    `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspacePortShare(ctx context.Context, arg UpsertWorkspacePortShareParams) (WorkspacePortShare, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspacePortShare,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.ShareLevel,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i WorkspacePortShare
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
 	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
@@ -7478,7 +9707,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, peering_enabled
 FROM
 	workspaces
 WHERE
@@ -7521,13 +9750,14 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.PeeringEnabled,
 	)
 	return i, err
 }
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, peering_enabled
 FROM
 	workspaces
 WHERE
@@ -7551,13 +9781,14 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.PeeringEnabled,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, peering_enabled
 FROM
 	workspaces
 WHERE
@@ -7588,13 +9819,14 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.PeeringEnabled,
 	)
 	return i, err
 }
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, peering_enabled
 FROM
 	workspaces
 WHERE
@@ -7644,13 +9876,14 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.PeeringEnabled,
 	)
 	return i, err
 }
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, COUNT(*) OVER () as count
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.peering_enabled, COUNT(*) OVER () as count
 FROM
 	workspaces
 LEFT JOIN LATERAL (
@@ -7849,6 +10082,7 @@ type GetWorkspacesRow struct {
 	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
 	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
 	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
+	PeeringEnabled    bool           `db:"peering_enabled" json:"peering_enabled"`
 	Count             int64          `db:"count" json:"count"`
 }
 
@@ -7885,6 +10119,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.AutostartSchedule,
 			&i.Ttl,
 			&i.LastUsedAt,
+			&i.PeeringEnabled,
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -7914,7 +10149,7 @@ INSERT INTO
 		ttl
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, peering_enabled
 `
 
 type InsertWorkspaceParams struct {
@@ -7954,6 +10189,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.PeeringEnabled,
 	)
 	return i, err
 }
@@ -7966,7 +10202,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, peering_enabled
 `
 
 type UpdateWorkspaceParams struct {
@@ -7989,6 +10225,7 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.AutostartSchedule,
 		&i.Ttl,
 		&i.LastUsedAt,
+		&i.PeeringEnabled,
 	)
 	return i, err
 }
@@ -8050,6 +10287,25 @@ func (q *sqlQuerier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWo
 	return err
 }
 
+const updateWorkspacePeering = `-- name: UpdateWorkspacePeering :exec
+UPDATE
+	workspaces
+SET
+	peering_enabled = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspacePeeringParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	PeeringEnabled bool      `db:"peering_enabled" json:"peering_enabled"`
+}
+
+func (q *sqlQuerier) UpdateWorkspacePeering(ctx context.Context, arg UpdateWorkspacePeeringParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspacePeering, arg.ID, arg.PeeringEnabled)
+	return err
+}
+
 const updateWorkspaceTTL = `-- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
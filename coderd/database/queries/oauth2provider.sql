@@ -0,0 +1,183 @@
+-- name: GetOAuth2ProviderApps :many
+SELECT * FROM oauth2_provider_apps ORDER BY (name, id) ASC;
+
+-- name: GetOAuth2ProviderAppByID :one
+SELECT * FROM oauth2_provider_apps WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppsByUserID :many
+-- Apps that hold at least one API key of the user.
+SELECT
+	oauth2_provider_apps.*
+FROM
+	oauth2_provider_apps
+WHERE
+	id IN (
+		SELECT
+			oauth2_provider_app_secrets.app_id
+		FROM
+			oauth2_provider_app_tokens
+		INNER JOIN oauth2_provider_app_secrets
+			ON oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+		INNER JOIN api_keys
+			ON api_keys.id = oauth2_provider_app_tokens.api_key_id
+		WHERE
+			api_keys.user_id = @user_id
+	)
+ORDER BY (name, id) ASC;
+
+-- name: InsertOAuth2ProviderApp :one
+INSERT INTO oauth2_provider_apps (
+	id,
+	created_at,
+	updated_at,
+	name,
+	icon,
+	redirect_uris
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE oauth2_provider_apps SET
+	updated_at = $2,
+	name = $3,
+	icon = $4,
+	redirect_uris = $5
+WHERE id = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppByID :exec
+-- The API keys issued to the app are deleted too, as deleting the refresh
+-- tokens alone would leave them usable until they expire.
+WITH deleted_api_keys AS (
+	DELETE FROM
+		api_keys
+	USING
+		oauth2_provider_app_tokens, oauth2_provider_app_secrets
+	WHERE
+		oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+		AND api_keys.id = oauth2_provider_app_tokens.api_key_id
+		AND oauth2_provider_app_secrets.app_id = $1
+)
+DELETE FROM oauth2_provider_apps WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT * FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT * FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC;
+
+-- name: GetOAuth2ProviderAppSecretByPrefix :one
+SELECT * FROM oauth2_provider_app_secrets WHERE secret_prefix = $1;
+
+-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO oauth2_provider_app_secrets (
+	id,
+	created_at,
+	secret_prefix,
+	hashed_secret,
+	display_secret,
+	app_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+) RETURNING *;
+
+-- name: UpdateOAuth2ProviderAppSecretByID :one
+UPDATE oauth2_provider_app_secrets SET
+	last_used_at = $2
+WHERE id = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppSecretByID :exec
+-- The API keys issued with the secret are deleted too.
+WITH deleted_api_keys AS (
+	DELETE FROM
+		api_keys
+	USING
+		oauth2_provider_app_tokens
+	WHERE
+		api_keys.id = oauth2_provider_app_tokens.api_key_id
+		AND oauth2_provider_app_tokens.app_secret_id = $1
+)
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT * FROM oauth2_provider_app_codes WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByPrefix :one
+SELECT * FROM oauth2_provider_app_codes WHERE secret_prefix = $1;
+
+-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO oauth2_provider_app_codes (
+	id,
+	created_at,
+	expires_at,
+	secret_prefix,
+	hashed_secret,
+	user_id,
+	app_id,
+	scope,
+	redirect_uri
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8,
+	$9
+) RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppCodeByID :one
+-- Returns no rows if the code was already deleted, so concurrent requests
+-- can't both redeem it.
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING id;
+
+-- name: GetOAuth2ProviderAppTokenByPrefix :one
+SELECT * FROM oauth2_provider_app_tokens WHERE refresh_prefix = $1;
+
+-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO oauth2_provider_app_tokens (
+	id,
+	created_at,
+	expires_at,
+	refresh_prefix,
+	hashed_refresh_secret,
+	app_secret_id,
+	api_key_id
+) VALUES(
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7
+) RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppTokenByPrefix :one
+-- Returns no rows if the refresh token was already deleted, so concurrent
+-- requests can't both redeem it.
+DELETE FROM oauth2_provider_app_tokens WHERE refresh_prefix = $1 RETURNING *;
+
+-- name: DeleteOAuth2ProviderAppTokensByAppAndUserID :exec
+-- Deleting the API keys revokes both the access and the refresh tokens.
+DELETE FROM
+	api_keys
+USING
+	oauth2_provider_app_tokens, oauth2_provider_app_secrets
+WHERE
+	oauth2_provider_app_secrets.id = oauth2_provider_app_tokens.app_secret_id
+	AND api_keys.id = oauth2_provider_app_tokens.api_key_id
+	AND oauth2_provider_app_secrets.app_id = @app_id
+	AND api_keys.user_id = @user_id;
@@ -15,3 +15,17 @@ INSERT INTO
 	)
 VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateProvisionerDaemonHeartbeat :exec
+UPDATE
+	provisioner_daemons
+SET
+	last_seen_at = @last_seen_at,
+	version = @version,
+	hostname = @hostname,
+	operating_system = @operating_system,
+	architecture = @architecture,
+	concurrency = @concurrency,
+	current_job_id = @current_job_id
+WHERE
+	id = @id;
@@ -11,11 +11,15 @@ WHERE
 
 -- name: InsertProvisionerJobLogs :many
 INSERT INTO
-	provisioner_job_logs
+	provisioner_job_logs (job_id, created_at, source, level, stage, output, resource_address, resource_action, resource_status, resource_elapsed_seconds)
 SELECT
 	@job_id :: uuid AS job_id,
 	unnest(@created_at :: timestamptz [ ]) AS created_at,
 	unnest(@source :: log_source [ ]) AS source,
 	unnest(@level :: log_level [ ]) AS LEVEL,
 	unnest(@stage :: VARCHAR(128) [ ]) AS stage,
-	unnest(@output :: VARCHAR(1024) [ ]) AS output RETURNING *;
+	unnest(@output :: VARCHAR(1024) [ ]) AS output,
+	unnest(@resource_address :: text [ ]) AS resource_address,
+	unnest(@resource_action :: text [ ]) AS resource_action,
+	unnest(@resource_status :: text [ ]) AS resource_status,
+	unnest(@resource_elapsed_seconds :: integer [ ]) AS resource_elapsed_seconds RETURNING *;
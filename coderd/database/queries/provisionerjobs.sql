@@ -31,6 +31,35 @@ WHERE
 			1
 	) RETURNING *;
 
+-- Counts jobs waiting for a daemon, grouped by the provisioner and tags
+-- a daemon must satisfy to acquire them.
+-- name: GetPendingProvisionerJobCountsByTags :many
+SELECT
+	provisioner,
+	tags,
+	COUNT(*) AS count
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL
+GROUP BY
+	provisioner,
+	tags;
+
+-- name: GetPendingProvisionerJobs :many
+SELECT
+	*
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+	AND completed_at IS NULL
+ORDER BY
+	created_at;
+
 -- name: GetProvisionerJobByID :one
 SELECT
 	*
@@ -63,10 +92,26 @@ INSERT INTO
 		file_id,
 		"type",
 		"input",
-		tags
+		tags,
+		has_matching_daemon
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+
+-- Fails a job that no daemon has acquired yet. The started_at guard avoids
+-- racing with a daemon acquiring the job.
+-- name: UpdatePendingProvisionerJobWithFailureByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	started_at = @failed_at :: timestamptz,
+	updated_at = @failed_at :: timestamptz,
+	completed_at = @failed_at :: timestamptz,
+	error = @error,
+	error_code = @error_code
+WHERE
+	id = @id
+	AND started_at IS NULL;
 
 -- name: UpdateProvisionerJobByID :exec
 UPDATE
@@ -76,6 +121,14 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateProvisionerJobHasMatchingDaemonByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	has_matching_daemon = $2
+WHERE
+	id = $1;
+
 -- name: UpdateProvisionerJobWithCancelByID :exec
 UPDATE
 	provisioner_jobs
@@ -0,0 +1,19 @@
+-- name: GetServiceAccountByUserID :one
+SELECT
+	*
+FROM
+	service_accounts
+WHERE
+	user_id = $1;
+
+-- name: InsertServiceAccount :one
+INSERT INTO
+	service_accounts (
+		user_id,
+		owner_user_id,
+		owner_group_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+RETURNING *;
@@ -0,0 +1,77 @@
+-- name: UpsertTailnetCoordinator :one
+INSERT INTO tailnet_coordinators (id, heartbeat_at) VALUES ($1, NOW())
+ON CONFLICT (id) DO UPDATE SET heartbeat_at = NOW()
+RETURNING *;
+
+-- name: DeleteTailnetCoordinator :exec
+DELETE FROM tailnet_coordinators WHERE id = $1;
+
+-- name: DeleteTailnetCoordinatorsHeartbeatBefore :exec
+DELETE FROM tailnet_coordinators WHERE heartbeat_at < $1;
+
+-- name: DeleteTailnetAgentVersionsUpdatedBefore :exec
+DELETE FROM tailnet_agent_versions
+WHERE
+	updated_at < @updated_before
+	-- Connections can't resume to agents without nodes that aren't in the
+	-- latest build of a workspace, so their versions are no longer needed.
+	AND NOT EXISTS (
+		SELECT 1 FROM tailnet_nodes WHERE tailnet_nodes.agent_id = tailnet_agent_versions.agent_id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM workspace_agents
+		JOIN workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+		JOIN workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+		WHERE
+			workspace_agents.id = tailnet_agent_versions.agent_id
+			AND workspace_builds.build_number = (
+				SELECT MAX(latest_builds.build_number) FROM workspace_builds AS latest_builds
+				WHERE latest_builds.workspace_id = workspace_builds.workspace_id
+			)
+	);
+
+-- name: UpsertTailnetAgentNode :one
+WITH agent_version AS (
+	INSERT INTO tailnet_agent_versions (agent_id, node_version, updated_at) VALUES (@agent_id, 1, NOW())
+	ON CONFLICT (agent_id) DO UPDATE SET
+		node_version = tailnet_agent_versions.node_version + 1,
+		updated_at = NOW()
+	RETURNING node_version
+)
+INSERT INTO tailnet_nodes (id, coordinator_id, agent_id, node, version, updated_at)
+VALUES (@agent_id, @coordinator_id, @agent_id, @node, (SELECT node_version FROM agent_version), NOW())
+ON CONFLICT (id) DO UPDATE SET
+	coordinator_id = EXCLUDED.coordinator_id,
+	node = EXCLUDED.node,
+	version = EXCLUDED.version,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: UpsertTailnetClientNode :one
+WITH agent_version AS (
+	INSERT INTO tailnet_agent_versions (agent_id, peer_version, updated_at) VALUES (@agent_id, 1, NOW())
+	ON CONFLICT (agent_id) DO UPDATE SET
+		peer_version = tailnet_agent_versions.peer_version + 1,
+		updated_at = NOW()
+	RETURNING peer_version
+)
+INSERT INTO tailnet_nodes (id, coordinator_id, agent_id, node, version, updated_at)
+VALUES (@id, @coordinator_id, @agent_id, @node, (SELECT peer_version FROM agent_version), NOW())
+ON CONFLICT (id) DO UPDATE SET
+	coordinator_id = EXCLUDED.coordinator_id,
+	agent_id = EXCLUDED.agent_id,
+	node = EXCLUDED.node,
+	version = EXCLUDED.version,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetTailnetAgentNode :one
+SELECT * FROM tailnet_nodes WHERE id = @agent_id AND agent_id = @agent_id;
+
+-- name: GetTailnetClientNodesAfterVersion :many
+SELECT * FROM tailnet_nodes
+WHERE agent_id = @agent_id AND id != @agent_id AND version > @after_version
+ORDER BY version;
+
+-- name: DeleteTailnetNode :exec
+DELETE FROM tailnet_nodes WHERE id = @id AND coordinator_id = @coordinator_id;
@@ -0,0 +1,34 @@
+-- name: GetTemplateAppLimits :one
+SELECT
+	*
+FROM
+	template_app_limits
+WHERE
+	template_id = $1;
+
+-- name: UpsertTemplateAppLimits :one
+INSERT INTO
+	template_app_limits (
+		template_id,
+		app_requests_per_minute,
+		app_bandwidth_bytes_per_second,
+		workspace_requests_per_minute,
+		workspace_bandwidth_bytes_per_second,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+ON CONFLICT (template_id) DO UPDATE
+SET
+	app_requests_per_minute = EXCLUDED.app_requests_per_minute,
+	app_bandwidth_bytes_per_second = EXCLUDED.app_bandwidth_bytes_per_second,
+	workspace_requests_per_minute = EXCLUDED.workspace_requests_per_minute,
+	workspace_bandwidth_bytes_per_second = EXCLUDED.workspace_bandwidth_bytes_per_second,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateAppLimits :exec
+DELETE FROM
+	template_app_limits
+WHERE
+	template_id = $1;
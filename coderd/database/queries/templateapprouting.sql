@@ -0,0 +1,49 @@
+-- name: GetTemplateAppRoutingByTemplateID :many
+SELECT
+	*
+FROM
+	template_app_routing
+WHERE
+	template_id = $1
+ORDER BY
+	app_slug ASC;
+
+-- name: GetTemplateAppRouting :one
+SELECT
+	*
+FROM
+	template_app_routing
+WHERE
+	template_id = $1
+	AND app_slug = $2;
+
+-- name: UpsertTemplateAppRouting :one
+INSERT INTO
+	template_app_routing (
+		template_id,
+		app_slug,
+		strip_prefix,
+		base_path_header,
+		headers,
+		allowed_methods,
+		websocket_subprotocols,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (template_id, app_slug) DO UPDATE
+SET
+	strip_prefix = EXCLUDED.strip_prefix,
+	base_path_header = EXCLUDED.base_path_header,
+	headers = EXCLUDED.headers,
+	allowed_methods = EXCLUDED.allowed_methods,
+	websocket_subprotocols = EXCLUDED.websocket_subprotocols,
+	updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteTemplateAppRouting :exec
+DELETE FROM
+	template_app_routing
+WHERE
+	template_id = $1
+	AND app_slug = $2;
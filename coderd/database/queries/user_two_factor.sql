@@ -0,0 +1,139 @@
+-- name: GetUserTOTP :one
+SELECT
+	*
+FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserTOTP :one
+INSERT INTO
+	user_totp (
+		user_id,
+		secret,
+		confirmed,
+		hashed_recovery_codes,
+		last_used_step,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id) DO UPDATE SET
+	secret = $2,
+	confirmed = $3,
+	hashed_recovery_codes = $4,
+	last_used_step = $5,
+	updated_at = $7
+RETURNING *;
+
+-- name: UpdateUserTOTPLastUsedStep :one
+-- Returns no rows if a code of the same or a later time step was already
+-- used, so concurrent logins can't both use a code.
+UPDATE
+	user_totp
+SET
+	last_used_step = @last_used_step,
+	updated_at = @updated_at
+WHERE
+	user_id = @user_id
+	AND confirmed
+	AND last_used_step < @last_used_step
+RETURNING *;
+
+-- name: UpdateUserTOTPRemoveRecoveryCode :one
+-- Returns no rows if the recovery code was already used, so concurrent logins
+-- can't both use it.
+UPDATE
+	user_totp
+SET
+	hashed_recovery_codes = array_remove(hashed_recovery_codes, @hashed_recovery_code :: text),
+	updated_at = @updated_at
+WHERE
+	user_id = @user_id
+	AND confirmed
+	AND @hashed_recovery_code :: text = ANY(hashed_recovery_codes)
+RETURNING *;
+
+-- name: DeleteUserTOTP :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: GetUserWebAuthnCredentialByID :one
+SELECT
+	*
+FROM
+	user_webauthn_credentials
+WHERE
+	id = $1;
+
+-- name: GetUserWebAuthnCredentialsByUserID :many
+SELECT
+	*
+FROM
+	user_webauthn_credentials
+WHERE
+	user_id = $1
+ORDER BY
+	created_at ASC;
+
+-- name: InsertUserWebAuthnCredential :one
+INSERT INTO
+	user_webauthn_credentials (
+		id,
+		user_id,
+		credential_id,
+		name,
+		public_key,
+		sign_count,
+		created_at,
+		last_used_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: UpdateUserWebAuthnCredentialSignCount :exec
+UPDATE
+	user_webauthn_credentials
+SET
+	sign_count = $2,
+	last_used_at = $3
+WHERE
+	id = $1;
+
+-- name: DeleteUserWebAuthnCredential :exec
+DELETE FROM
+	user_webauthn_credentials
+WHERE
+	id = $1;
+
+-- name: InsertUserWebAuthnChallenge :exec
+INSERT INTO
+	user_webauthn_challenges (
+		challenge,
+		user_id,
+		ceremony,
+		created_at,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5);
+
+-- name: DeleteUserWebAuthnChallenge :one
+-- Returns no rows if the challenge was already answered, so a response can't
+-- be replayed.
+DELETE FROM
+	user_webauthn_challenges
+WHERE
+	challenge = $1
+	AND user_id = $2
+	AND ceremony = $3
+RETURNING *;
+
+-- name: DeleteExpiredUserWebAuthnChallenges :exec
+DELETE FROM
+	user_webauthn_challenges
+WHERE
+	expires_at < NOW();
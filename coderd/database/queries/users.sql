@@ -41,6 +41,14 @@ FROM
 WHERE
     status = 'active'::user_status AND deleted = false;
 
+-- name: GetActiveServiceAccountCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+    status = 'active'::user_status AND deleted = false AND login_type = 'none'::login_type;
+
 -- name: GetFilteredUserCount :one
 -- This will never count deleted users.
 SELECT
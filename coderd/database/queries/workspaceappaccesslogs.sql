@@ -0,0 +1,44 @@
+-- name: InsertWorkspaceAppAccessLog :exec
+INSERT INTO
+	workspace_app_access_logs (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		agent_id,
+		app_slug_or_port,
+		access_method,
+		sharing_level,
+		ip,
+		method,
+		path,
+		status_code,
+		bytes_written,
+		duration_ms
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+
+-- name: GetWorkspaceAppAccessLogs :many
+SELECT
+	workspace_app_access_logs.*,
+	users.username AS user_username
+FROM
+	workspace_app_access_logs
+	LEFT JOIN users ON workspace_app_access_logs.user_id = users.id
+WHERE
+	workspace_app_access_logs.workspace_id = @workspace_id :: uuid
+	-- Filter by app slug or port
+	AND CASE
+		WHEN @app_slug_or_port :: text != '' THEN
+			workspace_app_access_logs.app_slug_or_port = @app_slug_or_port
+		ELSE true
+	END
+ORDER BY
+	workspace_app_access_logs.created_at DESC OFFSET @offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: DeleteOldWorkspaceAppAccessLogs :exec
+DELETE FROM workspace_app_access_logs WHERE created_at < @before :: timestamp with time zone;
@@ -0,0 +1,55 @@
+-- name: UpsertWorkspaceBuildTiming :exec
+INSERT INTO
+	workspace_build_timings (workspace_build_id, stage, started_at, ended_at)
+VALUES
+	($1, $2, $3, $4)
+-- Stages that span several agents are reported once per agent, so the stage
+-- is widened to cover the earliest start and the latest end.
+ON CONFLICT (workspace_build_id, stage) DO UPDATE
+SET
+	started_at = LEAST(workspace_build_timings.started_at, EXCLUDED.started_at),
+	ended_at = GREATEST(workspace_build_timings.ended_at, EXCLUDED.ended_at);
+
+-- name: GetWorkspaceBuildTimingsByBuildIDs :many
+SELECT
+	*
+FROM
+	workspace_build_timings
+WHERE
+	workspace_build_id = ANY(@ids :: uuid [ ]);
+
+-- name: UpdateWorkspaceBuildTimingObserved :one
+-- Claims an ended stage to be observed in metrics. No rows are returned if the
+-- stage hasn't ended or was already claimed, so only one caller observes it.
+UPDATE
+	workspace_build_timings
+SET
+	observed = true
+WHERE
+	workspace_build_id = $1
+	AND stage = $2
+	AND ended_at IS NOT NULL
+	AND NOT observed
+RETURNING
+	*;
+
+-- name: GetTemplateBuildStageStats :many
+SELECT
+	workspace_build_timings.stage,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY EXTRACT(EPOCH FROM (workspace_build_timings.ended_at - workspace_build_timings.started_at)))), -1)::FLOAT AS p50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY EXTRACT(EPOCH FROM (workspace_build_timings.ended_at - workspace_build_timings.started_at)))), -1)::FLOAT AS p95
+FROM
+	workspace_build_timings
+JOIN workspace_builds ON
+	workspace_builds.id = workspace_build_timings.workspace_build_id
+JOIN template_versions ON
+	template_versions.id = workspace_builds.template_version_id
+WHERE
+	template_versions.template_id = @template_id AND
+	workspace_builds.transition = 'start' AND
+	workspace_build_timings.ended_at IS NOT NULL AND
+	workspace_build_timings.started_at > @start_time
+GROUP BY
+	workspace_build_timings.stage
+ORDER BY
+	workspace_build_timings.stage;
@@ -0,0 +1,39 @@
+-- name: UpsertWorkspacePortShare :one
+INSERT INTO
+	workspace_port_shares (workspace_id, agent_name, port, share_level, created_at, expires_at)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+ON CONFLICT (workspace_id, agent_name, port) DO UPDATE
+SET
+	share_level = EXCLUDED.share_level,
+	created_at = EXCLUDED.created_at,
+	expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: GetWorkspacePortShare :one
+SELECT
+	*
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
+
+-- name: GetWorkspacePortSharesByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name ASC, port ASC;
+
+-- name: DeleteWorkspacePortShare :exec
+DELETE FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
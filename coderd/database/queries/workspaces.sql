@@ -309,6 +309,14 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspacePeering :exec
+UPDATE
+	workspaces
+SET
+	peering_enabled = $2
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceLastUsedAt :exec
 UPDATE
 	workspaces
@@ -1,6 +1,11 @@
 package coderd
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/codersdk"
+)
 
 // @Summary Debug Info Wireguard Coordinator
 // @ID debug-info-wireguard-coordinator
@@ -12,3 +17,28 @@ import "net/http"
 func (api *API) debugCoordinator(rw http.ResponseWriter, r *http.Request) {
 	(*api.TailnetCoordinator.Load()).ServeHTTPDebug(rw, r)
 }
+
+// @Summary Debug DERP health
+// @ID debug-derp-health
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 200 {object} codersdk.DERPHealthReport
+// @Router /debug/derp [get]
+func (api *API) debugDERP(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if api.derpHealthChecker == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "DERP health checks are disabled.",
+			Detail:  "Set --derp-health-check-interval to probe DERP regions.",
+		})
+		return
+	}
+	report, err := api.derpHealthChecker.Report(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
@@ -0,0 +1,64 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestDebugDERP(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		DERPHealthCheckInterval: 100 * time.Millisecond,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	// The first check may run before the server handles requests.
+	var report codersdk.DERPHealthReport
+	require.Eventually(t, func() bool {
+		var err error
+		report, err = client.DebugDERP(ctx)
+		return err == nil && report.Healthy
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Len(t, report.Regions, 1)
+	require.True(t, report.Regions[0].EmbeddedRelay)
+	require.Len(t, report.Regions[0].Nodes, 1)
+	require.True(t, report.Regions[0].Nodes[0].STUNReachable)
+
+	// Only owners can see the report.
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	_, err := memberClient.DebugDERP(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	derpMap, err := memberClient.DERPMap(ctx)
+	require.NoError(t, err)
+	require.Contains(t, derpMap.Regions, report.Regions[0].RegionID)
+}
+
+func TestDebugDERPDisabled(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	_, err := client.DebugDERP(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -0,0 +1,342 @@
+// Package derphealth periodically probes every region of a DERP map and
+// reports which regions are healthy from the point of view of coderd.
+package derphealth
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/net/stun"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/tailnet"
+)
+
+// Options set optional parameters of the checker.
+type Options struct {
+	// Interval is the interval at which regions are probed, default 1m.
+	Interval time.Duration
+	// ProbeTimeout is the timeout of probing a single node, default 10s.
+	ProbeTimeout time.Duration
+	// Registerer exposes the health and latency of each region as
+	// Prometheus metrics if set.
+	Registerer prometheus.Registerer
+}
+
+// Checker is responsible for periodically probing DERP regions.
+type Checker struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	log     slog.Logger
+	derpMap *tailcfg.DERPMap
+	opts    Options
+
+	healthy *prometheus.GaugeVec
+	latency *prometheus.GaugeVec
+
+	mu         sync.RWMutex
+	report     codersdk.DERPHealthReport
+	firstCheck chan struct{}
+	closed     chan struct{}
+}
+
+// New returns a Checker that probes every region of the DERP map until it is
+// closed.
+func New(log slog.Logger, derpMap *tailcfg.DERPMap, opts Options) (*Checker, error) {
+	if opts.Interval == 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.ProbeTimeout == 0 {
+		opts.ProbeTimeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Checker{
+		ctx:        ctx,
+		cancel:     cancel,
+		log:        log,
+		derpMap:    derpMap,
+		opts:       opts,
+		firstCheck: make(chan struct{}),
+		closed:     make(chan struct{}),
+	}
+	if opts.Registerer != nil {
+		c.healthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "derp",
+			Name:      "region_healthy",
+			Help:      "Whether coderd can reach at least one node of the DERP region.",
+		}, []string{"region_id", "region_code"})
+		c.latency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "derp",
+			Name:      "region_latency_seconds",
+			Help:      "The lowest round trip latency from coderd to a healthy node of the DERP region.",
+		}, []string{"region_id", "region_code"})
+		for _, collector := range []prometheus.Collector{c.healthy, c.latency} {
+			err := opts.Registerer.Register(collector)
+			if err != nil {
+				cancel()
+				return nil, xerrors.Errorf("register metrics: %w", err)
+			}
+		}
+	}
+	go c.start()
+	return c, nil
+}
+
+// Report returns the latest health report, waiting for the first check to
+// complete.
+func (c *Checker) Report(ctx context.Context) (codersdk.DERPHealthReport, error) {
+	select {
+	case <-c.ctx.Done():
+		return codersdk.DERPHealthReport{}, c.ctx.Err()
+	case <-ctx.Done():
+		return codersdk.DERPHealthReport{}, ctx.Err()
+	case <-c.firstCheck:
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.report, nil
+}
+
+// Close stops probing regions.
+func (c *Checker) Close() error {
+	c.cancel()
+	<-c.closed
+	return nil
+}
+
+func (c *Checker) start() {
+	defer close(c.closed)
+
+	t := time.NewTicker(c.opts.Interval)
+	defer t.Stop()
+
+	c.check()
+	close(c.firstCheck)
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-t.C:
+		}
+		c.check()
+	}
+}
+
+func (c *Checker) check() {
+	report := codersdk.DERPHealthReport{
+		Healthy:   true,
+		CheckedAt: time.Now(),
+		Regions:   []codersdk.DERPRegionReport{},
+	}
+	if c.derpMap != nil {
+		regions := make([]codersdk.DERPRegionReport, len(c.derpMap.Regions))
+		var wg sync.WaitGroup
+		i := 0
+		for _, region := range c.derpMap.Regions {
+			wg.Add(1)
+			go func(i int, region *tailcfg.DERPRegion) {
+				defer wg.Done()
+				regions[i] = c.checkRegion(region)
+			}(i, region)
+			i++
+		}
+		wg.Wait()
+		sort.Slice(regions, func(i, j int) bool {
+			return regions[i].RegionID < regions[j].RegionID
+		})
+		report.Regions = regions
+	}
+	if c.ctx.Err() != nil {
+		return
+	}
+
+	if c.healthy != nil {
+		c.healthy.Reset()
+		c.latency.Reset()
+	}
+	for _, region := range report.Regions {
+		if !region.Healthy {
+			report.Healthy = false
+			c.log.Warn(c.ctx, "derp region is unhealthy",
+				slog.F("region_id", region.RegionID),
+				slog.F("region_code", region.RegionCode),
+			)
+		}
+		if c.healthy == nil {
+			continue
+		}
+		labels := []string{strconv.Itoa(region.RegionID), region.RegionCode}
+		healthy := 0.0
+		if region.Healthy {
+			healthy = 1
+			c.latency.WithLabelValues(labels...).Set(region.LatencyMilliseconds / 1000)
+		}
+		c.healthy.WithLabelValues(labels...).Set(healthy)
+	}
+
+	c.mu.Lock()
+	c.report = report
+	c.mu.Unlock()
+}
+
+func (c *Checker) checkRegion(region *tailcfg.DERPRegion) codersdk.DERPRegionReport {
+	report := codersdk.DERPRegionReport{
+		RegionID:      region.RegionID,
+		RegionCode:    region.RegionCode,
+		RegionName:    region.RegionName,
+		EmbeddedRelay: region.EmbeddedRelay,
+		Nodes:         make([]codersdk.DERPNodeReport, len(region.Nodes)),
+	}
+	var wg sync.WaitGroup
+	for i, node := range region.Nodes {
+		wg.Add(1)
+		go func(i int, node *tailcfg.DERPNode) {
+			defer wg.Done()
+			report.Nodes[i] = c.checkNode(region, node)
+		}(i, node)
+	}
+	wg.Wait()
+
+	for _, node := range report.Nodes {
+		if !node.Healthy {
+			continue
+		}
+		report.Healthy = true
+		// STUN-only nodes have no DERP latency.
+		if node.LatencyMilliseconds > 0 && (report.LatencyMilliseconds == 0 || node.LatencyMilliseconds < report.LatencyMilliseconds) {
+			report.LatencyMilliseconds = node.LatencyMilliseconds
+		}
+	}
+	return report
+}
+
+func (c *Checker) checkNode(region *tailcfg.DERPRegion, node *tailcfg.DERPNode) codersdk.DERPNodeReport {
+	ctx, cancel := context.WithTimeout(c.ctx, c.opts.ProbeTimeout)
+	defer cancel()
+
+	report := codersdk.DERPNodeReport{
+		Name:        node.Name,
+		HostName:    node.HostName,
+		STUNEnabled: node.STUNPort >= 0,
+	}
+	if report.STUNEnabled {
+		err := probeSTUN(ctx, node)
+		if err != nil {
+			report.STUNError = err.Error()
+		} else {
+			report.STUNReachable = true
+		}
+	}
+	if node.STUNOnly {
+		report.Healthy = report.STUNReachable
+		return report
+	}
+
+	latency, err := c.probeDERP(ctx, region, node)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Healthy = true
+	report.LatencyMilliseconds = float64(latency.Microseconds()) / 1000
+	return report
+}
+
+// probeDERP connects to a single DERP node and measures the round trip of a
+// ping.
+func (c *Checker) probeDERP(ctx context.Context, region *tailcfg.DERPRegion, node *tailcfg.DERPNode) (time.Duration, error) {
+	client := derphttp.NewRegionClient(key.NewNode(), tailnet.Logger(c.log.Named("derp")), func() *tailcfg.DERPRegion {
+		// Only the probed node is in the region, so the client can't fall
+		// back to another one.
+		return &tailcfg.DERPRegion{
+			RegionID:      region.RegionID,
+			RegionCode:    region.RegionCode,
+			RegionName:    region.RegionName,
+			EmbeddedRelay: region.EmbeddedRelay,
+			Nodes:         []*tailcfg.DERPNode{node},
+		}
+	})
+	client.IsProber = true
+	defer client.Close()
+
+	err := client.Connect(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf("connect: %w", err)
+	}
+	// Pongs are only handled while receiving.
+	go func() {
+		for {
+			_, err := client.Recv()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	err = client.Ping(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf("ping: %w", err)
+	}
+	return time.Since(start), nil
+}
+
+// probeSTUN sends a STUN binding request to the node and waits for the
+// response.
+func probeSTUN(ctx context.Context, node *tailcfg.DERPNode) error {
+	host := node.HostName
+	switch {
+	case node.STUNTestIP != "":
+		host = node.STUNTestIP
+	case node.IPv4 != "" && node.IPv4 != "none":
+		host = node.IPv4
+	case node.IPv6 != "" && node.IPv6 != "none":
+		host = node.IPv6
+	}
+	port := node.STUNPort
+	if port == 0 {
+		port = 3478
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return xerrors.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	txID := stun.NewTxID()
+	_, err = conn.Write(stun.Request(txID))
+	if err != nil {
+		return xerrors.Errorf("write request: %w", err)
+	}
+	buf := make([]byte, 1024)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return xerrors.Errorf("read response: %w", err)
+		}
+		gotID, _, err := stun.ParseResponse(buf[:n])
+		if err == nil && gotID == txID {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package derphealth_test
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"tailscale.com/derp"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/net/stun/stuntest"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
+	"tailscale.com/types/nettype"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/coderd/derphealth"
+	"github.com/coder/coder/tailnet"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestChecker(t *testing.T) {
+	t.Parallel()
+
+	logger := slogtest.Make(t, nil)
+	derpServer := derp.NewServer(key.NewNode(), tailnet.Logger(logger.Named("derp")))
+	t.Cleanup(func() {
+		_ = derpServer.Close()
+	})
+	srv := httptest.NewServer(derphttp.Handler(derpServer))
+	t.Cleanup(srv.Close)
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	derpPort, err := strconv.Atoi(srvURL.Port())
+	require.NoError(t, err)
+	stunAddr, stunCleanup := stuntest.ServeWithPacketListener(t, nettype.Std{})
+	t.Cleanup(stunCleanup)
+
+	// Nothing listens on the port of the closed listener.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1: {
+				RegionID:      1,
+				RegionCode:    "coder",
+				RegionName:    "Coder",
+				EmbeddedRelay: true,
+				Nodes: []*tailcfg.DERPNode{{
+					Name:      "1a",
+					RegionID:  1,
+					IPv4:      "127.0.0.1",
+					DERPPort:  derpPort,
+					STUNPort:  stunAddr.Port,
+					ForceHTTP: true,
+				}},
+			},
+			2: {
+				RegionID:   2,
+				RegionCode: "down",
+				RegionName: "Down",
+				Nodes: []*tailcfg.DERPNode{{
+					Name:      "2a",
+					RegionID:  2,
+					IPv4:      "127.0.0.1",
+					DERPPort:  closedPort,
+					STUNPort:  -1,
+					ForceHTTP: true,
+				}},
+			},
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	checker, err := derphealth.New(logger, derpMap, derphealth.Options{
+		Registerer: registry,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = checker.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	report, err := checker.Report(ctx)
+	require.NoError(t, err)
+	require.False(t, report.Healthy)
+	require.Len(t, report.Regions, 2)
+
+	healthy := report.Regions[0]
+	require.Equal(t, 1, healthy.RegionID)
+	require.True(t, healthy.EmbeddedRelay)
+	require.True(t, healthy.Healthy)
+	require.Greater(t, healthy.LatencyMilliseconds, 0.0)
+	require.Len(t, healthy.Nodes, 1)
+	require.Empty(t, healthy.Nodes[0].Error)
+	require.True(t, healthy.Nodes[0].STUNEnabled)
+	require.True(t, healthy.Nodes[0].STUNReachable)
+
+	unhealthy := report.Regions[1]
+	require.Equal(t, 2, unhealthy.RegionID)
+	require.False(t, unhealthy.Healthy)
+	require.Len(t, unhealthy.Nodes, 1)
+	require.NotEmpty(t, unhealthy.Nodes[0].Error)
+	require.False(t, unhealthy.Nodes[0].STUNEnabled)
+
+	metrics, err := registry.Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range metrics {
+		if family.GetName() != "coderd_derp_region_healthy" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "region_code" {
+					values[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	require.Equal(t, map[string]float64{"coder": 1, "down": 0}, values)
+}
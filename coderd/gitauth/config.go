@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
@@ -22,7 +23,8 @@ type Config struct {
 	httpmw.OAuth2Config
 	// ID is a unique identifier for the authenticator.
 	ID string
-	// Regex is a regexp that URLs will match against.
+	// Regex is a regexp that URLs will match against. If nil, the
+	// provider is not used to authenticate Git operations.
 	Regex *regexp.Regexp
 	// Type is the type of provider.
 	Type codersdk.GitProvider
@@ -36,6 +38,9 @@ type Config struct {
 	// returning it to the user. If omitted, tokens will
 	// not be validated before being returned.
 	ValidateURL string
+	// DeviceAuth is set when users authenticate with the device flow
+	// rather than a browser redirect.
+	DeviceAuth *DeviceAuth
 }
 
 // RefreshToken automatically refreshes the token if expired and permitted.
@@ -125,6 +130,10 @@ func ConvertConfig(entries []codersdk.GitAuthConfig, accessURL *url.URL) ([]*Con
 			typ = codersdk.GitProviderGitHub
 		case codersdk.GitProviderGitLab:
 			typ = codersdk.GitProviderGitLab
+		case codersdk.GitProviderGitea:
+			typ = codersdk.GitProviderGitea
+		case codersdk.GitProviderOAuth2:
+			typ = codersdk.GitProviderOAuth2
 		default:
 			return nil, xerrors.Errorf("unknown git provider type: %q", entry.Type)
 		}
@@ -151,11 +160,33 @@ func ConvertConfig(entries []codersdk.GitAuthConfig, accessURL *url.URL) ([]*Con
 		if entry.ClientSecret == "" {
 			return nil, xerrors.Errorf("%q git auth provider: client_secret must be provided", entry.ID)
 		}
+		if _, ok := endpoint[typ]; !ok && (entry.AuthURL == "" || entry.TokenURL == "") {
+			return nil, xerrors.Errorf("%q git auth provider: auth_url and token_url must be provided", entry.ID)
+		}
 		authRedirect, err := accessURL.Parse(fmt.Sprintf("/gitauth/%s/callback", entry.ID))
 		if err != nil {
 			return nil, xerrors.Errorf("parse gitauth callback url: %w", err)
 		}
 		regex := regex[typ]
+		if paths, ok := selfManagedPaths[typ]; ok && strings.HasSuffix(entry.AuthURL, paths.Auth) {
+			// A self-managed deployment serves the remaining endpoints
+			// relative to the same base URL.
+			base := strings.TrimSuffix(entry.AuthURL, paths.Auth)
+			baseURL, err := url.Parse(base)
+			if err != nil {
+				return nil, xerrors.Errorf("parse auth url for git auth provider %q: %w", entry.ID, err)
+			}
+			if entry.TokenURL == "" {
+				entry.TokenURL = base + paths.Token
+			}
+			if entry.ValidateURL == "" {
+				entry.ValidateURL = base + paths.Validate
+			}
+			if entry.DeviceCodeURL == "" && paths.DeviceCode != "" {
+				entry.DeviceCodeURL = base + paths.DeviceCode
+			}
+			regex = regexp.MustCompile(`^(https?://)?` + regexp.QuoteMeta(baseURL.Host+baseURL.Path) + `(/.*)?$`)
+		}
 		if entry.Regex != "" {
 			regex, err = regexp.Compile(entry.Regex)
 			if err != nil {
@@ -184,6 +215,23 @@ func ConvertConfig(entries []codersdk.GitAuthConfig, accessURL *url.URL) ([]*Con
 			entry.ValidateURL = validateURL[typ]
 		}
 
+		var deviceAuth *DeviceAuth
+		if entry.DeviceFlow {
+			if entry.DeviceCodeURL == "" {
+				entry.DeviceCodeURL = deviceCodeURL[typ]
+			}
+			if entry.DeviceCodeURL == "" {
+				return nil, xerrors.Errorf("%q git auth provider: device_code_url must be provided to use the device flow", entry.ID)
+			}
+			deviceAuth = &DeviceAuth{
+				ClientID:     oauth2Config.ClientID,
+				ClientSecret: oauth2Config.ClientSecret,
+				CodeURL:      entry.DeviceCodeURL,
+				TokenURL:     oauth2Config.Endpoint.TokenURL,
+				Scopes:       oauth2Config.Scopes,
+			}
+		}
+
 		var oauthConfig httpmw.OAuth2Config = oauth2Config
 		// Azure DevOps uses JWT token authentication!
 		if typ == codersdk.GitProviderAzureDevops {
@@ -197,6 +245,7 @@ func ConvertConfig(entries []codersdk.GitAuthConfig, accessURL *url.URL) ([]*Con
 			Type:         typ,
 			NoRefresh:    entry.NoRefresh,
 			ValidateURL:  entry.ValidateURL,
+			DeviceAuth:   deviceAuth,
 		})
 	}
 	return configs, nil
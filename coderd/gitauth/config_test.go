@@ -169,6 +169,23 @@ func TestConvertYAML(t *testing.T) {
 			Regex:        `\K`,
 		}},
 		Error: "compile regex for git auth provider",
+	}, {
+		Name: "NoDeviceCodeURL",
+		Input: []codersdk.GitAuthConfig{{
+			Type:         string(codersdk.GitProviderGitea),
+			ClientID:     "example",
+			ClientSecret: "example",
+			DeviceFlow:   true,
+		}},
+		Error: "device_code_url must be provided",
+	}, {
+		Name: "OAuth2NoEndpoint",
+		Input: []codersdk.GitAuthConfig{{
+			Type:         string(codersdk.GitProviderOAuth2),
+			ClientID:     "example",
+			ClientSecret: "example",
+		}},
+		Error: "auth_url and token_url must be provided",
 	}} {
 		tc := tc
 		t.Run(tc.Name, func(t *testing.T) {
@@ -196,4 +213,62 @@ func TestConvertYAML(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "https://auth.com?client_id=id&redirect_uri=%2Fgitauth%2Fgitlab%2Fcallback&response_type=code&scope=read", config[0].AuthCodeURL(""))
 	})
+	t.Run("SelfManaged", func(t *testing.T) {
+		t.Parallel()
+		config, err := gitauth.ConvertConfig([]codersdk.GitAuthConfig{{
+			Type:         string(codersdk.GitProviderGitLab),
+			ClientID:     "id",
+			ClientSecret: "secret",
+			AuthURL:      "https://git.example.com/gitlab/oauth/authorize",
+			DeviceFlow:   true,
+		}, {
+			Type:         string(codersdk.GitProviderGitea),
+			ClientID:     "id",
+			ClientSecret: "secret",
+			AuthURL:      "https://forgejo.example.com/login/oauth/authorize",
+		}}, &url.URL{})
+		require.NoError(t, err)
+		require.Len(t, config, 2)
+
+		gitlab := config[0]
+		require.Equal(t, "https://git.example.com/gitlab/oauth/token/info", gitlab.ValidateURL)
+		require.NotNil(t, gitlab.DeviceAuth)
+		require.Equal(t, "https://git.example.com/gitlab/oauth/authorize_device", gitlab.DeviceAuth.CodeURL)
+		require.Equal(t, "https://git.example.com/gitlab/oauth/token", gitlab.DeviceAuth.TokenURL)
+		require.True(t, gitlab.Regex.MatchString("https://git.example.com/gitlab/org/repo.git"))
+		require.False(t, gitlab.Regex.MatchString("https://gitlab.com/org/repo.git"))
+
+		gitea := config[1]
+		require.Equal(t, codersdk.GitProviderGitea, gitea.Type)
+		require.Equal(t, "https://forgejo.example.com/api/v1/user", gitea.ValidateURL)
+		require.Nil(t, gitea.DeviceAuth)
+		require.True(t, gitea.Regex.MatchString("forgejo.example.com/org/repo"))
+	})
+	t.Run("OAuth2", func(t *testing.T) {
+		t.Parallel()
+		config, err := gitauth.ConvertConfig([]codersdk.GitAuthConfig{{
+			ID:           "jira",
+			Type:         string(codersdk.GitProviderOAuth2),
+			ClientID:     "id",
+			ClientSecret: "secret",
+			AuthURL:      "https://auth.atlassian.com/authorize",
+			TokenURL:     "https://auth.atlassian.com/oauth/token",
+		}}, &url.URL{})
+		require.NoError(t, err)
+		// Generic providers are not used for Git operations.
+		require.Nil(t, config[0].Regex)
+		require.Empty(t, config[0].ValidateURL)
+	})
+	t.Run("DefaultDeviceCodeURL", func(t *testing.T) {
+		t.Parallel()
+		config, err := gitauth.ConvertConfig([]codersdk.GitAuthConfig{{
+			Type:         string(codersdk.GitProviderGitHub),
+			ClientID:     "id",
+			ClientSecret: "secret",
+			DeviceFlow:   true,
+		}}, &url.URL{})
+		require.NoError(t, err)
+		require.Equal(t, "https://github.com/login/device/code", config[0].DeviceAuth.CodeURL)
+		require.Equal(t, "https://github.com/login/oauth/access_token", config[0].DeviceAuth.TokenURL)
+	})
 }
@@ -2,11 +2,19 @@ package gitauth
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/codersdk"
@@ -27,6 +35,17 @@ var endpoint = map[codersdk.GitProvider]oauth2.Endpoint{
 		TokenURL: "https://gitlab.com/oauth/token",
 	},
 	codersdk.GitProviderGitHub: github.Endpoint,
+	codersdk.GitProviderGitea: {
+		AuthURL:  "https://gitea.com/login/oauth/authorize",
+		TokenURL: "https://gitea.com/login/oauth/access_token",
+	},
+}
+
+// deviceCodeURL contains the default device authorization endpoint for each
+// Git provider that supports the device flow.
+var deviceCodeURL = map[codersdk.GitProvider]string{
+	codersdk.GitProviderGitHub: "https://github.com/login/device/code",
+	codersdk.GitProviderGitLab: "https://gitlab.com/oauth/authorize_device",
 }
 
 // validateURL contains defaults for each provider.
@@ -34,6 +53,7 @@ var validateURL = map[codersdk.GitProvider]string{
 	codersdk.GitProviderGitHub:    "https://api.github.com/user",
 	codersdk.GitProviderGitLab:    "https://gitlab.com/oauth/token/info",
 	codersdk.GitProviderBitBucket: "https://api.bitbucket.org/2.0/user",
+	codersdk.GitProviderGitea:     "https://gitea.com/api/v1/user",
 }
 
 // scope contains defaults for each Git provider.
@@ -52,6 +72,31 @@ var regex = map[codersdk.GitProvider]*regexp.Regexp{
 	codersdk.GitProviderBitBucket:   regexp.MustCompile(`^(https?://)?bitbucket\.org(/.*)?$`),
 	codersdk.GitProviderGitLab:      regexp.MustCompile(`^(https?://)?gitlab\.com(/.*)?$`),
 	codersdk.GitProviderGitHub:      regexp.MustCompile(`^(https?://)?github\.com(/.*)?$`),
+	codersdk.GitProviderGitea:       regexp.MustCompile(`^(https?://)?gitea\.com(/.*)?$`),
+}
+
+// selfManagedPaths contains the endpoint paths of Git providers that serve
+// the same paths when self-managed. The remaining endpoints of a self-managed
+// deployment are derived from its auth URL.
+var selfManagedPaths = map[codersdk.GitProvider]struct {
+	Auth       string
+	Token      string
+	Validate   string
+	DeviceCode string
+}{
+	codersdk.GitProviderGitLab: {
+		Auth:       "/oauth/authorize",
+		Token:      "/oauth/token",
+		Validate:   "/oauth/token/info",
+		DeviceCode: "/oauth/authorize_device",
+	},
+	// Forgejo is a fork of Gitea and serves the same paths. Neither supports
+	// the device flow.
+	codersdk.GitProviderGitea: {
+		Auth:     "/login/oauth/authorize",
+		Token:    "/login/oauth/access_token",
+		Validate: "/api/v1/user",
+	},
 }
 
 // newJWTOAuthConfig creates a new OAuth2 config that uses a custom
@@ -89,3 +134,146 @@ func (c *jwtConfig) Exchange(ctx context.Context, code string, opts ...oauth2.Au
 		)...,
 	)
 }
+
+// DeviceAuth authenticates with the OAuth2 device authorization grant. See:
+// https://datatracker.ietf.org/doc/html/rfc8628
+type DeviceAuth struct {
+	ClientID     string
+	ClientSecret string
+	CodeURL      string
+	TokenURL     string
+	Scopes       []string
+}
+
+// DeviceAuthError is an error returned by the token endpoint while
+// exchanging a device code.
+type DeviceAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *DeviceAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// Pending returns whether the user has yet to authorize the device. SlowDown
+// is also pending, but requires the interval to be increased.
+func (e *DeviceAuthError) Pending() bool {
+	return e.Code == "authorization_pending" || e.SlowDown()
+}
+
+// SlowDown returns whether the device code is being exchanged too often.
+func (e *DeviceAuthError) SlowDown() bool {
+	return e.Code == "slow_down"
+}
+
+// AuthorizeDevice begins the device flow. The user must enter the returned
+// user code at the verification URI before the device code can be exchanged.
+func (c *DeviceAuth) AuthorizeDevice(ctx context.Context) (*codersdk.GitAuthDevice, error) {
+	v := url.Values{
+		"client_id": {c.ClientID},
+	}
+	if len(c.Scopes) > 0 {
+		v.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	res, err := c.post(ctx, c.CodeURL, v)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(res.Body)
+		return nil, xerrors.Errorf("status %d: body: %s", res.StatusCode, data)
+	}
+
+	var device struct {
+		codersdk.GitAuthDevice
+		// Some providers, e.g. Google, use the verification URL name from
+		// earlier drafts of the specification.
+		VerificationURL string `json:"verification_url"`
+	}
+	err = decodeJSON(res, &device)
+	if err != nil {
+		return nil, xerrors.Errorf("decode device authorization: %w", err)
+	}
+	if device.VerificationURI == "" {
+		device.VerificationURI = device.VerificationURL
+	}
+	if device.DeviceCode == "" || device.UserCode == "" || device.VerificationURI == "" {
+		return nil, xerrors.New("device authorization is missing the device code, user code or verification uri")
+	}
+	if device.Interval == 0 {
+		// The default from the specification.
+		device.Interval = 5
+	}
+	return &device.GitAuthDevice, nil
+}
+
+// ExchangeDeviceCode exchanges the device code for a token. While the user
+// has yet to authorize the device, a pending *DeviceAuthError is returned.
+func (c *DeviceAuth) ExchangeDeviceCode(ctx context.Context, deviceCode string) (*oauth2.Token, error) {
+	v := url.Values{
+		"client_id":   {c.ClientID},
+		"device_code": {deviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+	if c.ClientSecret != "" {
+		v.Set("client_secret", c.ClientSecret)
+	}
+	res, err := c.post(ctx, c.TokenURL, v)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		DeviceAuthError
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	err = decodeJSON(res, &body)
+	if err != nil {
+		return nil, xerrors.Errorf("status %d: decode token: %w", res.StatusCode, err)
+	}
+	// GitHub responds with an OK status for errors.
+	if body.Code != "" {
+		return nil, &body.DeviceAuthError
+	}
+	if res.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, xerrors.Errorf("status %d: no access token returned", res.StatusCode)
+	}
+	token := &oauth2.Token{
+		AccessToken:  body.AccessToken,
+		TokenType:    body.TokenType,
+		RefreshToken: body.RefreshToken,
+	}
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+func (*DeviceAuth) post(ctx context.Context, endpoint string, v url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// GitHub responds with a form-encoded body unless JSON is requested.
+	req.Header.Set("Accept", "application/json")
+	return http.DefaultClient.Do(req)
+}
+
+func decodeJSON(res *http.Response, v interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		data, _ := io.ReadAll(res.Body)
+		return xerrors.Errorf("unexpected content type %q: %s", mediaType, data)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
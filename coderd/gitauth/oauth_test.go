@@ -1,9 +1,87 @@
 package gitauth_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/gitauth"
+	"github.com/coder/coder/testutil"
 )
 
 func TestOAuthJWTConfig(t *testing.T) {
 	t.Parallel()
 }
+
+func TestDeviceAuth(t *testing.T) {
+	t.Parallel()
+	t.Run("Authorize", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "client", r.FormValue("client_id"))
+			assert.Equal(t, "repo workflow", r.FormValue("scope"))
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"device_code":"device","user_code":"ABCD-1234","verification_url":"https://example.com/device","expires_in":900}`))
+		}))
+		defer srv.Close()
+		auth := &gitauth.DeviceAuth{
+			ClientID: "client",
+			CodeURL:  srv.URL,
+			Scopes:   []string{"repo", "workflow"},
+		}
+		device, err := auth.AuthorizeDevice(testutil.Context(t, testutil.WaitShort))
+		require.NoError(t, err)
+		require.Equal(t, "device", device.DeviceCode)
+		require.Equal(t, "ABCD-1234", device.UserCode)
+		require.Equal(t, "https://example.com/device", device.VerificationURI)
+		// The interval defaults to five seconds.
+		require.Equal(t, 5, device.Interval)
+	})
+	t.Run("Exchange", func(t *testing.T) {
+		t.Parallel()
+		var body atomic.Value
+		body.Store(`{"error":"authorization_pending"}`)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", r.FormValue("grant_type"))
+			assert.Equal(t, "device", r.FormValue("device_code"))
+			assert.Equal(t, "secret", r.FormValue("client_secret"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body.Load().(string)))
+		}))
+		defer srv.Close()
+		auth := &gitauth.DeviceAuth{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     srv.URL,
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := auth.ExchangeDeviceCode(ctx, "device")
+		var deviceErr *gitauth.DeviceAuthError
+		require.ErrorAs(t, err, &deviceErr)
+		require.True(t, deviceErr.Pending())
+		require.False(t, deviceErr.SlowDown())
+
+		body.Store(`{"error":"slow_down"}`)
+		_, err = auth.ExchangeDeviceCode(ctx, "device")
+		require.ErrorAs(t, err, &deviceErr)
+		require.True(t, deviceErr.SlowDown())
+
+		body.Store(`{"error":"access_denied","error_description":"The user denied the request."}`)
+		_, err = auth.ExchangeDeviceCode(ctx, "device")
+		require.ErrorAs(t, err, &deviceErr)
+		require.False(t, deviceErr.Pending())
+		require.EqualError(t, err, "access_denied: The user denied the request.")
+
+		body.Store(`{"access_token":"token","refresh_token":"refresh","expires_in":3600}`)
+		token, err := auth.ExchangeDeviceCode(ctx, "device")
+		require.NoError(t, err)
+		require.Equal(t, "token", token.AccessToken)
+		require.Equal(t, "refresh", token.RefreshToken)
+		require.False(t, token.Expiry.IsZero())
+	})
+}
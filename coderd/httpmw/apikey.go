@@ -369,7 +369,7 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 			ID:     key.UserID.String(),
 			Roles:  rbac.RoleNames(roles.Roles),
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  key.RBACScope(),
 		},
 	}
 
@@ -383,6 +383,7 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 // 3: The old cookie
 // 4. The coder_session_token query parameter
 // 5. The custom auth header
+// 6. The bearer token of the authorization header, as sent by OAuth2 apps
 func apiTokenFromRequest(r *http.Request) string {
 	cookie, err := r.Cookie(codersdk.SessionTokenCookie)
 	if err == nil && cookie.Value != "" {
@@ -399,6 +400,13 @@ func apiTokenFromRequest(r *http.Request) string {
 		return headerValue
 	}
 
+	// Access tokens issued to OAuth2 apps are API keys, which apps send the
+	// standard way.
+	scheme, bearer, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "bearer") && bearer != "" {
+		return bearer
+	}
+
 	cookie, err = r.Cookie(codersdk.DevURLSessionTokenCookie)
 	if err == nil && cookie.Value != "" {
 		return cookie.Value
@@ -23,6 +23,10 @@ func CSRF(secureCookie bool) func(next http.Handler) http.Handler {
 		// Exempt all requests that do not require CSRF protection.
 		// All GET requests are exempt by default.
 		mw.ExemptPath("/api/v2/csp/reports")
+		// SAML identity providers post responses cross-site.
+		mw.ExemptPath("/api/v2/users/saml/acs")
+		// OAuth2 apps exchange codes for tokens without cookies.
+		mw.ExemptPath("/oauth2/tokens")
 
 		// Top level agent routes.
 		mw.ExemptRegexp(regexp.MustCompile("api/v2/workspaceagents/[^/]*$"))
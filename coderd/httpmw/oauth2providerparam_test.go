@@ -0,0 +1,110 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/httpmw"
+)
+
+func TestOAuth2ProviderAppParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func() *http.Request {
+		r := httptest.NewRequest("GET", "/", nil)
+		ctx := chi.NewRouteContext()
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+	}
+
+	t.Run("None", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractOAuth2ProviderAppParam(db))
+		rtr.Get("/", nil)
+		rw := httptest.NewRecorder()
+		rtr.ServeHTTP(rw, setup())
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractOAuth2ProviderAppParam(db))
+		rtr.Get("/", nil)
+		r := setup()
+		chi.RouteContext(r.Context()).URLParams.Add("app", uuid.NewString())
+		rw := httptest.NewRecorder()
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("SecretOfOtherApp", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		app := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+		other := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(t, db, database.OAuth2ProviderAppSecret{
+			AppID: other.ID,
+		})
+		rtr := chi.NewRouter()
+		rtr.Use(
+			httpmw.ExtractOAuth2ProviderAppParam(db),
+			httpmw.ExtractOAuth2ProviderAppSecretParam(db),
+		)
+		rtr.Get("/", nil)
+		r := setup()
+		chi.RouteContext(r.Context()).URLParams.Add("app", app.ID.String())
+		chi.RouteContext(r.Context()).URLParams.Add("secretID", secret.ID.String())
+		rw := httptest.NewRecorder()
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Found", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		app := dbgen.OAuth2ProviderApp(t, db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(t, db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		rtr := chi.NewRouter()
+		rtr.Use(
+			httpmw.ExtractOAuth2ProviderAppParam(db),
+			httpmw.ExtractOAuth2ProviderAppSecretParam(db),
+		)
+		rtr.Get("/", func(rw http.ResponseWriter, r *http.Request) {
+			require.Equal(t, app.ID, httpmw.OAuth2ProviderApp(r).ID)
+			require.Equal(t, secret.ID, httpmw.OAuth2ProviderAppSecret(r).ID)
+			rw.WriteHeader(http.StatusOK)
+		})
+		r := setup()
+		chi.RouteContext(r.Context()).URLParams.Add("app", app.ID.String())
+		chi.RouteContext(r.Context()).URLParams.Add("secretID", secret.ID.String())
+		rw := httptest.NewRecorder()
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
@@ -0,0 +1,130 @@
+// Package importpolicy evaluates administrator-defined Rego policies against
+// the Terraform plans produced when a template version is imported.
+//
+// Policies are written in the "coder.template_import" package and produce a
+// set of violation messages from the "deny" rule:
+//
+//	package coder.template_import
+//
+//	deny[msg] {
+//		rc := input.plan.resource_changes[_]
+//		rc.type == "aws_instance"
+//		not rc.change.after.tags.owner
+//		msg := sprintf("%s must have an owner tag", [rc.address])
+//	}
+//
+// The input document contains the workspace transition being planned
+// ("start" or "stop") and the plan in Terraform's JSON output format.
+package importpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/open-policy-agent/opa/rego"
+	"golang.org/x/xerrors"
+)
+
+// ErrNoPlan is returned when there's no plan to evaluate, e.g. because the
+// provisioner doesn't report plans. Callers should reject the import, since
+// the policy can't be enforced.
+var ErrNoPlan = xerrors.New("no plan to evaluate")
+
+// Query is the Rego query evaluated for every plan. Each value in the
+// resulting set is reported as a separate violation.
+const Query = "data.coder.template_import.deny"
+
+// Policy is a set of compiled Rego modules that can be evaluated against
+// Terraform plans.
+type Policy struct {
+	query rego.PreparedEvalQuery
+}
+
+// Input is the document passed to the policy as "input".
+type Input struct {
+	Transition string          `json:"transition"`
+	Plan       json.RawMessage `json:"plan"`
+}
+
+// Load reads and compiles the Rego files at the provided paths.
+func Load(ctx context.Context, paths []string) (*Policy, error) {
+	modules := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, xerrors.Errorf("read policy %q: %w", path, err)
+		}
+		modules[filepath.Clean(path)] = string(data)
+	}
+	return New(ctx, modules)
+}
+
+// New compiles the provided Rego modules, keyed by filename.
+func New(ctx context.Context, modules map[string]string) (*Policy, error) {
+	if len(modules) == 0 {
+		return nil, xerrors.New("at least one policy module is required")
+	}
+	options := []func(*rego.Rego){rego.Query(Query)}
+	for name, module := range modules {
+		options = append(options, rego.Module(name, module))
+	}
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("prepare policy: %w", err)
+	}
+	return &Policy{query: query}, nil
+}
+
+// Evaluate runs the policy against a Terraform plan in JSON format and
+// returns the sorted list of violations. A nil slice means the plan is
+// allowed. ErrNoPlan is returned if the plan is empty.
+func (p *Policy) Evaluate(ctx context.Context, transition string, planJSON []byte) ([]string, error) {
+	if len(planJSON) == 0 {
+		return nil, ErrNoPlan
+	}
+	raw, err := json.Marshal(Input{
+		Transition: transition,
+		Plan:       planJSON,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("marshal input: %w", err)
+	}
+	// Decode into a generic value so OPA sees plain maps and slices.
+	var input interface{}
+	err = json.Unmarshal(raw, &input)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal input: %w", err)
+	}
+
+	results, err := p.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, xerrors.Errorf("eval policy: %w", err)
+	}
+
+	var violations []string
+	for _, result := range results {
+		for _, expr := range result.Expressions {
+			values, ok := expr.Value.([]interface{})
+			if !ok {
+				return nil, xerrors.Errorf("%s must be a set, got %T", Query, expr.Value)
+			}
+			for _, value := range values {
+				switch v := value.(type) {
+				case string:
+					violations = append(violations, v)
+				default:
+					data, err := json.Marshal(v)
+					if err != nil {
+						return nil, xerrors.Errorf("marshal violation: %w", err)
+					}
+					violations = append(violations, string(data))
+				}
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations, nil
+}
@@ -0,0 +1,117 @@
+package importpolicy_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/importpolicy"
+)
+
+const requireOwnerTag = `package coder.template_import
+
+deny[msg] {
+	rc := input.plan.resource_changes[_]
+	rc.type == "aws_instance"
+	not rc.change.after.tags.owner
+	msg := sprintf("%s must have an owner tag", [rc.address])
+}
+
+deny[msg] {
+	input.transition == "start"
+	rc := input.plan.resource_changes[_]
+	rc.type == "null_resource"
+	msg := sprintf("%s: null_resource is forbidden", [rc.address])
+}
+`
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	policy, err := importpolicy.New(ctx, map[string]string{
+		"policy.rego": requireOwnerTag,
+	})
+	require.NoError(t, err)
+
+	t.Run("Allowed", func(t *testing.T) {
+		t.Parallel()
+		violations, err := policy.Evaluate(ctx, "start", []byte(`{
+			"resource_changes": [{
+				"address": "aws_instance.dev",
+				"type": "aws_instance",
+				"change": {"after": {"tags": {"owner": "admin"}}}
+			}]
+		}`))
+		require.NoError(t, err)
+		require.Empty(t, violations)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		t.Parallel()
+		violations, err := policy.Evaluate(ctx, "start", []byte(`{
+			"resource_changes": [{
+				"address": "aws_instance.dev",
+				"type": "aws_instance",
+				"change": {"after": {"tags": {}}}
+			}, {
+				"address": "null_resource.dev",
+				"type": "null_resource",
+				"change": {"after": {}}
+			}]
+		}`))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"aws_instance.dev must have an owner tag",
+			"null_resource.dev: null_resource is forbidden",
+		}, violations)
+	})
+
+	t.Run("Transition", func(t *testing.T) {
+		t.Parallel()
+		violations, err := policy.Evaluate(ctx, "stop", []byte(`{
+			"resource_changes": [{
+				"address": "null_resource.dev",
+				"type": "null_resource",
+				"change": {"after": {}}
+			}]
+		}`))
+		require.NoError(t, err)
+		require.Empty(t, violations)
+	})
+
+	t.Run("EmptyPlan", func(t *testing.T) {
+		t.Parallel()
+		_, err := policy.Evaluate(ctx, "start", nil)
+		require.ErrorIs(t, err, importpolicy.ErrNoPlan)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "policy.rego")
+		require.NoError(t, os.WriteFile(path, []byte(requireOwnerTag), 0o600))
+		_, err := importpolicy.Load(context.Background(), []string{path})
+		require.NoError(t, err)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+		_, err := importpolicy.Load(context.Background(), []string{filepath.Join(t.TempDir(), "missing.rego")})
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := importpolicy.New(context.Background(), map[string]string{
+			"policy.rego": "package coder.template_import\ndeny[msg] {",
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,151 @@
+// Package jobmatch periodically checks whether pending provisioner jobs can
+// be acquired by an online provisioner daemon.
+package jobmatch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/provisionerdserver"
+)
+
+// New starts checking pending jobs every interval. Jobs that no online daemon
+// can acquire are marked as unmatched and, if timeout is non-zero, failed once
+// they've been pending for longer than timeout.
+// It is the caller's responsibility to call Close on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, pubsub database.Pubsub, interval, timeout time.Duration) io.Closer {
+	closed := make(chan struct{})
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // Matching jobs to daemons is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	go func() {
+		defer close(closed)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := check(ctx, logger, db, pubsub, timeout, database.Now())
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return
+				}
+				logger.Error(ctx, "failed to match pending provisioner jobs", slog.Error(err))
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+func check(ctx context.Context, logger slog.Logger, db database.Store, pubsub database.Pubsub, timeout time.Duration, now time.Time) error {
+	jobs, err := db.GetPendingProvisionerJobs(ctx)
+	if err != nil {
+		return xerrors.Errorf("get pending provisioner jobs: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	daemons, err := db.GetProvisionerDaemons(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get provisioner daemons: %w", err)
+	}
+
+	for _, job := range jobs {
+		matched := false
+		for _, daemon := range daemons {
+			if provisionerdserver.DaemonMatchesJob(daemon, job.Provisioner, job.Tags, now) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched && timeout > 0 && now.Sub(job.CreatedAt) > timeout {
+			logger.Info(ctx, "failing unmatched provisioner job", slog.F("job_id", job.ID), slog.F("tags", job.Tags))
+			err = db.UpdatePendingProvisionerJobWithFailureByID(ctx, database.UpdatePendingProvisionerJobWithFailureByIDParams{
+				ID:       job.ID,
+				FailedAt: now,
+				Error: sql.NullString{
+					String: fmt.Sprintf("No provisioner daemon matched the job's tags (%s) within %s.", formatTags(job.Tags), timeout),
+					Valid:  true,
+				},
+				ErrorCode: sql.NullString{
+					String: provisionerdserver.NoMatchingDaemonErrorCode,
+					Valid:  true,
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("fail job %s: %w", job.ID, err)
+			}
+			// A daemon may have acquired the job since it was listed, in
+			// which case the update didn't apply and the job keeps running.
+			job, err = db.GetProvisionerJobByID(ctx, job.ID)
+			if err != nil {
+				return xerrors.Errorf("get job %s: %w", job.ID, err)
+			}
+			if job.WorkerID.Valid {
+				continue
+			}
+			// Close any log streams waiting on the job.
+			data, err := json.Marshal(provisionerdserver.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
+			if err != nil {
+				return xerrors.Errorf("marshal end of logs: %w", err)
+			}
+			err = pubsub.Publish(provisionerdserver.ProvisionerJobLogsNotifyChannel(job.ID), data)
+			if err != nil {
+				return xerrors.Errorf("publish end of logs: %w", err)
+			}
+			continue
+		}
+
+		if matched == job.HasMatchingDaemon {
+			continue
+		}
+		err = db.UpdateProvisionerJobHasMatchingDaemonByID(ctx, database.UpdateProvisionerJobHasMatchingDaemonByIDParams{
+			ID:                job.ID,
+			HasMatchingDaemon: matched,
+		})
+		if err != nil {
+			return xerrors.Errorf("update job %s: %w", job.ID, err)
+		}
+	}
+	return nil
+}
+
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	return nil
+}
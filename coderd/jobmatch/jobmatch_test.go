@@ -0,0 +1,123 @@
+package jobmatch_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtype"
+	"github.com/coder/coder/coderd/jobmatch"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestJobMatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	db := dbfake.New()
+
+	daemon, err := db.InsertProvisionerDaemon(ctx, database.InsertProvisionerDaemonParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		Name:         "test",
+		Provisioners: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		Tags:         dbtype.StringMap{"scope": "organization", "region": "eu"},
+	})
+	require.NoError(t, err)
+	err = db.UpdateProvisionerDaemonHeartbeat(ctx, database.UpdateProvisionerDaemonHeartbeatParams{
+		ID:         daemon.ID,
+		LastSeenAt: sql.NullTime{Time: database.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	matched := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+		Provisioner: database.ProvisionerTypeEcho,
+		Tags:        dbtype.StringMap{"scope": "organization", "region": "eu"},
+	})
+	recent := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+		Provisioner:       database.ProvisionerTypeEcho,
+		Tags:              dbtype.StringMap{"scope": "organization", "region": "us"},
+		HasMatchingDaemon: true,
+	})
+	expired := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+		CreatedAt:   database.Now().Add(-time.Hour),
+		Provisioner: database.ProvisionerTypeEcho,
+		Tags:        dbtype.StringMap{"scope": "organization", "region": "us"},
+	})
+
+	closer := jobmatch.New(ctx, slogtest.Make(t, nil), db, database.NewPubsubInMemory(), testutil.IntervalFast, time.Minute)
+	defer closer.Close()
+
+	require.Eventually(t, func() bool {
+		job, err := db.GetProvisionerJobByID(ctx, matched.ID)
+		return err == nil && job.HasMatchingDaemon
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	require.Eventually(t, func() bool {
+		job, err := db.GetProvisionerJobByID(ctx, recent.ID)
+		return err == nil && !job.HasMatchingDaemon
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	require.Eventually(t, func() bool {
+		job, err := db.GetProvisionerJobByID(ctx, expired.ID)
+		return err == nil && job.CompletedAt.Valid
+	}, testutil.WaitShort, testutil.IntervalFast)
+	job, err := db.GetProvisionerJobByID(ctx, expired.ID)
+	require.NoError(t, err)
+	require.True(t, job.StartedAt.Valid)
+	require.Equal(t, string(codersdk.NoMatchingProvisionerDaemon), job.ErrorCode.String)
+	require.Contains(t, job.Error.String, "region=us")
+
+	// The recent job hasn't reached the timeout yet.
+	job, err = db.GetProvisionerJobByID(ctx, recent.ID)
+	require.NoError(t, err)
+	require.False(t, job.CompletedAt.Valid)
+}
+
+func TestJobMatchWithoutHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	db := dbfake.New()
+
+	// Daemons older than the server never send heartbeats, which doesn't
+	// mean they're offline.
+	_, err := db.InsertProvisionerDaemon(ctx, database.InsertProvisionerDaemonParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		Name:         "old",
+		Provisioners: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		Tags:         dbtype.StringMap{"scope": "organization"},
+	})
+	require.NoError(t, err)
+
+	pending := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+		CreatedAt:   database.Now().Add(-time.Hour),
+		Provisioner: database.ProvisionerTypeEcho,
+		Tags:        dbtype.StringMap{"scope": "organization"},
+	})
+
+	closer := jobmatch.New(ctx, slogtest.Make(t, nil), db, database.NewPubsubInMemory(), testutil.IntervalFast, time.Minute)
+	defer closer.Close()
+
+	require.Eventually(t, func() bool {
+		job, err := db.GetProvisionerJobByID(ctx, pending.ID)
+		return err == nil && job.HasMatchingDaemon
+	}, testutil.WaitShort, testutil.IntervalFast)
+	job, err := db.GetProvisionerJobByID(ctx, pending.ID)
+	require.NoError(t, err)
+	require.False(t, job.CompletedAt.Valid)
+}
@@ -0,0 +1,207 @@
+// Package ldap authenticates users against an LDAP directory, such as Active
+// Directory.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+)
+
+// EmailPlaceholder is replaced with the escaped email a user logs in with in
+// Config.UserFilter.
+const EmailPlaceholder = "{email}"
+
+var (
+	// ErrInvalidCredentials is returned when the password of a user is wrong,
+	// or the user doesn't exist.
+	ErrInvalidCredentials = xerrors.New("invalid credentials")
+	// ErrUserNotFound is returned when a user is no longer in the directory,
+	// or no longer matches the user filter.
+	ErrUserNotFound = xerrors.New("user not found")
+	// ErrUnavailable is returned when the directory can't be reached, or
+	// rejects the search credentials.
+	ErrUnavailable = xerrors.New("directory unavailable")
+)
+
+// unavailableError marks an error as ErrUnavailable while keeping the cause.
+type unavailableError struct {
+	err error
+}
+
+func (e unavailableError) Error() string { return e.err.Error() }
+
+func (e unavailableError) Unwrap() error { return e.err }
+
+func (unavailableError) Is(target error) bool { return target == ErrUnavailable }
+
+// Config describes how users are found in the directory.
+type Config struct {
+	// URL of the directory, e.g. ldaps://ldap.example.com.
+	URL string
+	// BindDN and BindPassword authenticate the searches for users.
+	BindDN       string
+	BindPassword string
+	// StartTLS upgrades ldap:// connections to TLS before binding.
+	StartTLS  bool
+	TLSConfig *tls.Config
+
+	// UserBaseDN is the subtree users are searched in.
+	UserBaseDN string
+	// UserFilter selects the user that logs in, e.g. "(mail={email})".
+	UserFilter string
+	// UsernameAttribute, EmailAttribute and GroupAttribute select the
+	// attributes of a user entry to read. GroupAttribute is optional.
+	UsernameAttribute string
+	EmailAttribute    string
+	GroupAttribute    string
+}
+
+// User is an entry of the directory that matched the user filter.
+type User struct {
+	DN       string
+	Username string
+	Email    string
+	// Groups are the raw values of the group attribute, which are commonly
+	// DNs of groups.
+	Groups []string
+}
+
+// Authenticate finds the user with the email and binds as them to verify
+// their password.
+func (c *Config) Authenticate(ctx context.Context, email, password string) (User, error) {
+	conn, closeConn, err := c.connect(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	defer closeConn()
+
+	filter := strings.ReplaceAll(c.UserFilter, EmailPlaceholder, goldap.EscapeFilter(email))
+	user, err := c.search(conn, c.UserBaseDN, goldap.ScopeWholeSubtree, filter)
+	if xerrors.Is(err, ErrUserNotFound) {
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	err = conn.Bind(user.DN, password)
+	if goldap.IsErrorAnyOf(err, goldap.LDAPResultInvalidCredentials, goldap.ErrorEmptyPassword) {
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, xerrors.Errorf("bind as user: %w", err)
+	}
+	return user, nil
+}
+
+// Lookup reads the entry of a user that authenticated before. ErrUserNotFound
+// is returned if the entry was removed, or no longer matches the user filter.
+func (c *Config) Lookup(ctx context.Context, dn string) (User, error) {
+	conn, closeConn, err := c.connect(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	defer closeConn()
+
+	// Any email matches, so the filter only applies the other conditions,
+	// e.g. excluding disabled accounts.
+	filter := strings.ReplaceAll(c.UserFilter, EmailPlaceholder, "*")
+	return c.search(conn, dn, goldap.ScopeBaseObject, filter)
+}
+
+// connect dials the directory and binds with the search credentials. The
+// connection is closed early if the context is canceled.
+func (c *Config) connect(ctx context.Context) (*goldap.Conn, func(), error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := goldap.DialURL(c.URL, goldap.DialWithDialer(dialer), goldap.DialWithTLSConfig(c.TLSConfig))
+	if err != nil {
+		return nil, nil, unavailableError{xerrors.Errorf("dial: %w", err)}
+	}
+	conn.SetTimeout(30 * time.Second)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+	closeConn := func() {
+		close(done)
+		conn.Close()
+	}
+
+	if c.StartTLS {
+		err = conn.StartTLS(c.TLSConfig)
+		if err != nil {
+			closeConn()
+			return nil, nil, unavailableError{xerrors.Errorf("start tls: %w", err)}
+		}
+	}
+	if c.BindDN == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(c.BindDN, c.BindPassword)
+	}
+	if err != nil {
+		closeConn()
+		return nil, nil, unavailableError{xerrors.Errorf("bind: %w", err)}
+	}
+	return conn, closeConn, nil
+}
+
+func (c *Config) search(conn *goldap.Conn, baseDN string, scope int, filter string) (User, error) {
+	attributes := []string{c.UsernameAttribute, c.EmailAttribute}
+	if c.GroupAttribute != "" {
+		attributes = append(attributes, c.GroupAttribute)
+	}
+	res, err := conn.Search(goldap.NewSearchRequest(
+		baseDN, scope, goldap.NeverDerefAliases,
+		// Two entries are enough to detect an ambiguous filter.
+		2, 0, false, filter, attributes, nil,
+	))
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) {
+		return User{}, ErrUserNotFound
+	}
+	if goldap.IsErrorWithCode(err, goldap.ErrorNetwork) {
+		return User{}, unavailableError{xerrors.Errorf("search: %w", err)}
+	}
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return User{}, xerrors.Errorf("search: %w", err)
+	}
+	switch {
+	case res == nil || len(res.Entries) == 0:
+		return User{}, ErrUserNotFound
+	case len(res.Entries) > 1:
+		return User{}, xerrors.Errorf("user filter %q matched more than one entry", filter)
+	}
+
+	entry := res.Entries[0]
+	user := User{
+		DN:       entry.DN,
+		Username: entry.GetEqualFoldAttributeValue(c.UsernameAttribute),
+		Email:    entry.GetEqualFoldAttributeValue(c.EmailAttribute),
+	}
+	if c.GroupAttribute != "" {
+		user.Groups = entry.GetEqualFoldAttributeValues(c.GroupAttribute)
+	}
+	return user, nil
+}
+
+// GroupName returns the name of a group from a value of the group attribute.
+// Directories commonly list the DNs of groups, e.g.
+// "cn=developers,ou=groups,dc=example,dc=com", so the value of the first
+// component of a DN is used.
+func GroupName(value string) string {
+	dn, err := goldap.ParseDN(value)
+	if err != nil || len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+		return value
+	}
+	return dn.RDNs[0].Attributes[0].Value
+}
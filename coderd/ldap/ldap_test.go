@@ -0,0 +1,145 @@
+package ldap_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/ldap"
+	"github.com/coder/coder/coderd/ldap/ldaptest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+
+	const kyleDN = "uid=kyle,ou=people," + ldaptest.BaseDN
+	setup := func(t *testing.T) (*ldaptest.Directory, *ldap.Config) {
+		dir := ldaptest.New(t)
+		dir.Put(kyleDN, "password", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"kyle"},
+			"mail":        {"kyle@coder.com"},
+			"memberOf":    {"cn=admins,ou=groups," + ldaptest.BaseDN, "cn=devs,ou=groups," + ldaptest.BaseDN},
+		})
+		return dir, &ldap.Config{
+			URL:               dir.URL,
+			BindDN:            ldaptest.BindDN,
+			BindPassword:      ldaptest.BindPassword,
+			UserBaseDN:        ldaptest.BaseDN,
+			UserFilter:        "(&(objectClass=person)(mail={email}))",
+			UsernameAttribute: "uid",
+			EmailAttribute:    "mail",
+			GroupAttribute:    "memberOf",
+		}
+	}
+
+	t.Run("Authenticate", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, config := setup(t)
+
+		user, err := config.Authenticate(ctx, "Kyle@coder.com", "password")
+		require.NoError(t, err)
+		require.Equal(t, kyleDN, user.DN)
+		require.Equal(t, "kyle", user.Username)
+		require.Equal(t, "kyle@coder.com", user.Email)
+		require.Len(t, user.Groups, 2)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, config := setup(t)
+
+		_, err := config.Authenticate(ctx, "kyle@coder.com", "wrong")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+		_, err = config.Authenticate(ctx, "kyle@coder.com", "")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("UnknownEmail", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, config := setup(t)
+
+		_, err := config.Authenticate(ctx, "someone@coder.com", "password")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+		// The email must not be able to alter the filter.
+		_, err = config.Authenticate(ctx, "*", "password")
+		require.ErrorIs(t, err, ldap.ErrInvalidCredentials)
+	})
+
+	t.Run("AmbiguousFilter", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		dir, config := setup(t)
+		dir.Put("uid=other,ou=people,"+ldaptest.BaseDN, "password", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"other"},
+			"mail":        {"kyle@coder.com"},
+		})
+
+		_, err := config.Authenticate(ctx, "kyle@coder.com", "password")
+		require.ErrorContains(t, err, "matched more than one entry")
+	})
+
+	t.Run("WrongBindPassword", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, config := setup(t)
+		config.BindPassword = "wrong"
+
+		_, err := config.Authenticate(ctx, "kyle@coder.com", "password")
+		require.ErrorIs(t, err, ldap.ErrUnavailable)
+		require.NotErrorIs(t, err, ldap.ErrInvalidCredentials)
+		_, err = config.Lookup(ctx, kyleDN)
+		require.ErrorIs(t, err, ldap.ErrUnavailable)
+		require.NotErrorIs(t, err, ldap.ErrUserNotFound)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		_, config := setup(t)
+		// Nothing listens on the address once the listener is closed.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		config.URL = "ldap://" + listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		_, err = config.Lookup(ctx, kyleDN)
+		require.ErrorIs(t, err, ldap.ErrUnavailable)
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		dir, config := setup(t)
+
+		user, err := config.Lookup(ctx, kyleDN)
+		require.NoError(t, err)
+		require.Equal(t, "kyle", user.Username)
+
+		dir.Delete(kyleDN)
+		_, err = config.Lookup(ctx, kyleDN)
+		require.ErrorIs(t, err, ldap.ErrUserNotFound)
+	})
+}
+
+func TestGroupName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "admins", ldap.GroupName("cn=admins,ou=groups,dc=coder,dc=com"))
+	require.Equal(t, "admins", ldap.GroupName("admins"))
+	require.Equal(t, "Domain Users", ldap.GroupName("CN=Domain Users,CN=Users,DC=corp,DC=com"))
+}
@@ -0,0 +1,294 @@
+// Package ldaptest provides an in-memory LDAP directory that stands in for a
+// real one in tests. It supports simple binds and searches, which is all that
+// authenticating users needs.
+package ldaptest
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// BindDN and BindPassword are the credentials of the service account
+	// that searches for users.
+	BindDN       = "cn=coder,dc=coder,dc=com"
+	BindPassword = "coder"
+	// BaseDN is the parent of all entries.
+	BaseDN = "dc=coder,dc=com"
+)
+
+// Directory is an LDAP server holding a set of entries.
+type Directory struct {
+	// URL is the ldap:// URL the directory listens on.
+	URL string
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// New starts a directory that is closed when the test finishes.
+func New(t testing.TB) *Directory {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dir := &Directory{
+		URL:     "ldap://" + listener.Addr().String(),
+		entries: map[string]entry{},
+	}
+
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		_ = listener.Close()
+		wg.Wait()
+	})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer conn.Close()
+				dir.serve(conn)
+			}()
+		}
+	}()
+	return dir
+}
+
+// Put adds or replaces the entry with the DN. Users bind with the password.
+func (d *Directory) Put(dn, password string, attributes map[string][]string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[strings.ToLower(dn)] = entry{
+		dn:         dn,
+		password:   password,
+		attributes: attributes,
+	}
+}
+
+// Delete removes the entry with the DN.
+func (d *Directory) Delete(dn string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, strings.ToLower(dn))
+}
+
+func (d *Directory) serve(conn net.Conn) {
+	var bound bool
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		id, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			code := d.bind(op)
+			bound = code == goldap.LDAPResultSuccess
+			responses = append(responses, result(goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationSearchRequest:
+			if !bound {
+				responses = append(responses, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultInsufficientAccessRights))
+				break
+			}
+			responses = d.search(op)
+		case goldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = append(responses, result(goldap.ApplicationExtendedResponse, goldap.LDAPResultUnwillingToPerform))
+		}
+
+		for _, response := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+			envelope.AppendChild(response)
+			_, err = conn.Write(envelope.Bytes())
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (d *Directory) bind(op *ber.Packet) uint16 {
+	if len(op.Children) < 3 {
+		return goldap.LDAPResultProtocolError
+	}
+	dn, _ := op.Children[1].Value.(string)
+	password := op.Children[2].Data.String()
+	if password == "" {
+		// Unauthenticated binds don't grant access to anything.
+		return goldap.LDAPResultInvalidCredentials
+	}
+	if dn == BindDN && password == BindPassword {
+		return goldap.LDAPResultSuccess
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.entries[strings.ToLower(dn)]
+	if !ok || e.password != password {
+		return goldap.LDAPResultInvalidCredentials
+	}
+	return goldap.LDAPResultSuccess
+}
+
+func (d *Directory) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{result(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError)}
+	}
+	base, _ := op.Children[0].Value.(string)
+	base = strings.ToLower(base)
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.entries[base]; !ok && scope == goldap.ScopeBaseObject {
+		return []*ber.Packet{result(goldap.ApplicationSearchResultDone, goldap.LDAPResultNoSuchObject)}
+	}
+
+	var responses []*ber.Packet
+	for key, e := range d.entries {
+		switch scope {
+		case goldap.ScopeBaseObject:
+			if key != base {
+				continue
+			}
+		default:
+			if key != base && !strings.HasSuffix(key, ","+base) {
+				continue
+			}
+		}
+		if !matches(filter, e) {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(responses)) == sizeLimit {
+			return append(responses, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSizeLimitExceeded))
+		}
+
+		res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "Object Name"))
+		attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for name, values := range e.attributes {
+			attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			attribute.AppendChild(set)
+			attributes.AppendChild(attribute)
+		}
+		res.AppendChild(attributes)
+		responses = append(responses, res)
+	}
+	return append(responses, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess))
+}
+
+// matches evaluates the subset of filters that user filters commonly use.
+func matches(filter *ber.Packet, e entry) bool {
+	values := func(attribute string) []string {
+		for name, values := range e.attributes {
+			if strings.EqualFold(name, attribute) {
+				return values
+			}
+		}
+		return nil
+	}
+
+	switch filter.Tag {
+	case goldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matches(child, e) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterOr:
+		for _, child := range filter.Children {
+			if matches(child, e) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterNot:
+		return len(filter.Children) == 1 && !matches(filter.Children[0], e)
+	case goldap.FilterPresent:
+		return len(values(ber.DecodeString(filter.Data.Bytes()))) > 0
+	case goldap.FilterEqualityMatch:
+		attribute := ber.DecodeString(filter.Children[0].Data.Bytes())
+		want := ber.DecodeString(filter.Children[1].Data.Bytes())
+		for _, value := range values(attribute) {
+			if strings.EqualFold(value, want) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterSubstrings:
+		attribute := ber.DecodeString(filter.Children[0].Data.Bytes())
+		for _, value := range values(attribute) {
+			value = strings.ToLower(value)
+			ok := true
+			for _, part := range filter.Children[1].Children {
+				s := strings.ToLower(ber.DecodeString(part.Data.Bytes()))
+				switch part.Tag {
+				case goldap.FilterSubstringsInitial:
+					ok = strings.HasPrefix(value, s)
+					value = strings.TrimPrefix(value, s)
+				case goldap.FilterSubstringsFinal:
+					ok = strings.HasSuffix(value, s)
+				default:
+					i := strings.Index(value, s)
+					ok = i >= 0
+					if ok {
+						value = value[i+len(s):]
+					}
+				}
+				if !ok {
+					break
+				}
+			}
+			if ok {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return res
+}
@@ -0,0 +1,113 @@
+package coderd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/ldap"
+)
+
+// syncLDAP periodically syncs LDAP users with the directory until the context
+// is canceled.
+func (api *API) syncLDAP(ctx context.Context, interval time.Duration) {
+	defer close(api.ldapSyncDone)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := api.syncLDAPUsers(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			api.Logger.Error(ctx, "failed to sync ldap users", slog.Error(err))
+		}
+	}
+}
+
+// syncLDAPUsers updates the groups of active LDAP users from the directory,
+// and suspends users that were removed from it. Failing to sync one user
+// doesn't stop the others from being synced, but the sync stops if the
+// directory is unavailable.
+func (api *API) syncLDAPUsers(ctx context.Context) error {
+	//nolint:gocritic // Syncing is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	users, err := api.Database.GetUsers(ctx, database.GetUsersParams{
+		Status: []database.UserStatus{database.UserStatusActive},
+	})
+	if err != nil {
+		return xerrors.Errorf("get users: %w", err)
+	}
+
+	for _, user := range users {
+		if user.LoginType != database.LoginTypeLDAP {
+			continue
+		}
+		err := api.syncLDAPUser(ctx, user)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if xerrors.Is(err, ldap.ErrUnavailable) {
+				return xerrors.Errorf("sync %q: %w", user.Username, err)
+			}
+			api.Logger.Warn(ctx, "failed to sync ldap user",
+				slog.F("username", user.Username), slog.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// syncLDAPUser updates the groups of a single LDAP user, or suspends the user
+// if they were removed from the directory.
+func (api *API) syncLDAPUser(ctx context.Context, user database.GetUsersRow) error {
+	link, err := api.Database.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
+		UserID:    user.ID,
+		LoginType: database.LoginTypeLDAP,
+	})
+	if err != nil {
+		return xerrors.Errorf("get user link: %w", err)
+	}
+
+	entry, err := api.LDAPConfig.Directory.Lookup(ctx, link.LinkedID)
+	if xerrors.Is(err, ldap.ErrUserNotFound) {
+		_, err = api.Database.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("suspend: %w", err)
+		}
+		api.Logger.Info(ctx, "suspended user removed from the ldap directory",
+			slog.F("username", user.Username), slog.F("dn", link.LinkedID),
+		)
+		return nil
+	}
+	if err != nil {
+		// The directory may be unavailable, in which case the user must not
+		// be suspended.
+		return xerrors.Errorf("look up %q: %w", link.LinkedID, err)
+	}
+
+	if api.LDAPConfig.Directory.GroupAttribute == "" {
+		return nil
+	}
+	err = api.Options.SetUserGroups(ctx, api.Database, user.ID, api.LDAPConfig.groups(entry))
+	if err != nil {
+		return xerrors.Errorf("set groups: %w", err)
+	}
+	return nil
+}
@@ -33,6 +33,7 @@ type Cache struct {
 	templateDAUResponses     atomic.Pointer[map[uuid.UUID]codersdk.TemplateDAUsResponse]
 	templateUniqueUsers      atomic.Pointer[map[uuid.UUID]int]
 	templateAverageBuildTime atomic.Pointer[map[uuid.UUID]database.GetTemplateAverageBuildTimeRow]
+	templateBuildStageStats  atomic.Pointer[map[uuid.UUID][]database.GetTemplateBuildStageStatsRow]
 	deploymentStatsResponse  atomic.Pointer[codersdk.DeploymentStats]
 
 	done   chan struct{}
@@ -178,6 +179,7 @@ func (c *Cache) refreshTemplateDAUs(ctx context.Context) error {
 		templateDAUs              = make(map[uuid.UUID]codersdk.TemplateDAUsResponse, len(templates))
 		templateUniqueUsers       = make(map[uuid.UUID]int)
 		templateAverageBuildTimes = make(map[uuid.UUID]database.GetTemplateAverageBuildTimeRow)
+		templateBuildStageStats   = make(map[uuid.UUID][]database.GetTemplateBuildStageStatsRow)
 	)
 
 	rows, err := c.database.GetDeploymentDAUs(ctx)
@@ -209,10 +211,23 @@ func (c *Cache) refreshTemplateDAUs(ctx context.Context) error {
 			return err
 		}
 		templateAverageBuildTimes[template.ID] = templateAvgBuildTime
+
+		stageStats, err := c.database.GetTemplateBuildStageStats(ctx, database.GetTemplateBuildStageStatsParams{
+			TemplateID: uuid.NullUUID{
+				UUID:  template.ID,
+				Valid: true,
+			},
+			StartTime: database.Time(time.Now().AddDate(0, 0, -30)),
+		})
+		if err != nil {
+			return err
+		}
+		templateBuildStageStats[template.ID] = stageStats
 	}
 	c.templateDAUResponses.Store(&templateDAUs)
 	c.templateUniqueUsers.Store(&templateUniqueUsers)
 	c.templateAverageBuildTime.Store(&templateAverageBuildTimes)
+	c.templateBuildStageStats.Store(&templateBuildStageStats)
 
 	return nil
 }
@@ -376,6 +391,41 @@ func (c *Cache) TemplateBuildTimeStats(id uuid.UUID) codersdk.TemplateBuildTimeS
 	}
 }
 
+// TemplateBuildStageStats returns the duration percentiles of each stage of
+// recent start builds for a template.
+func (c *Cache) TemplateBuildStageStats(id uuid.UUID) codersdk.TemplateBuildStageStats {
+	stats := codersdk.TemplateBuildStageStats{
+		codersdk.WorkspaceBuildStageQueued:        {},
+		codersdk.WorkspaceBuildStageInit:          {},
+		codersdk.WorkspaceBuildStagePlan:          {},
+		codersdk.WorkspaceBuildStageApply:         {},
+		codersdk.WorkspaceBuildStageAgentConnect:  {},
+		codersdk.WorkspaceBuildStageStartupScript: {},
+	}
+
+	m := c.templateBuildStageStats.Load()
+	if m == nil {
+		// Data loading.
+		return stats
+	}
+
+	convertMillis := func(m float64) *int64 {
+		if m < 0 {
+			return nil
+		}
+		i := int64(m * 1000)
+		return &i
+	}
+
+	for _, row := range (*m)[id] {
+		stats[codersdk.WorkspaceBuildStage(row.Stage)] = codersdk.TransitionStats{
+			P50: convertMillis(row.P50),
+			P95: convertMillis(row.P95),
+		}
+	}
+	return stats
+}
+
 func (c *Cache) DeploymentStats() (codersdk.DeploymentStats, bool) {
 	deploymentStats := c.deploymentStatsResponse.Load()
 	if deploymentStats == nil {
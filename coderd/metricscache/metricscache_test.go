@@ -375,6 +375,82 @@ func TestCache_BuildTime(t *testing.T) {
 	}
 }
 
+func TestCache_BuildStageStats(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var (
+		db    = dbfake.New()
+		cache = metricscache.New(db, slogtest.Make(t, nil), metricscache.Intervals{
+			TemplateDAUs: testutil.IntervalFast,
+		})
+	)
+	defer cache.Close()
+
+	template := dbgen.Template(t, db, database.Template{})
+	templateVersion := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+		TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+	})
+	for _, transition := range []database.WorkspaceTransition{
+		database.WorkspaceTransitionStart,
+		// Only start builds are aggregated.
+		database.WorkspaceTransitionStop,
+	} {
+		job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{})
+		build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			TemplateVersionID: templateVersion.ID,
+			JobID:             job.ID,
+			Transition:        transition,
+		})
+		startedAt := database.Now().Add(-time.Hour)
+		err := db.UpsertWorkspaceBuildTiming(ctx, database.UpsertWorkspaceBuildTimingParams{
+			WorkspaceBuildID: build.ID,
+			Stage:            database.WorkspaceBuildStageInit,
+			StartedAt:        startedAt,
+			EndedAt: sql.NullTime{
+				Time:  startedAt.Add(time.Duration(len(transition)) * time.Minute),
+				Valid: true,
+			},
+		})
+		require.NoError(t, err)
+	}
+	// Only builds of the last 30 days are aggregated.
+	job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{})
+	build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+		TemplateVersionID: templateVersion.ID,
+		JobID:             job.ID,
+		Transition:        database.WorkspaceTransitionStart,
+	})
+	startedAt := database.Now().AddDate(0, 0, -31)
+	err := db.UpsertWorkspaceBuildTiming(ctx, database.UpsertWorkspaceBuildTimingParams{
+		WorkspaceBuildID: build.ID,
+		Stage:            database.WorkspaceBuildStageInit,
+		StartedAt:        startedAt,
+		EndedAt: sql.NullTime{
+			Time:  startedAt.Add(time.Hour),
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		stats := cache.TemplateBuildStageStats(template.ID)
+		return stats[codersdk.WorkspaceBuildStageInit] != codersdk.TransitionStats{}
+	}, testutil.WaitLong, testutil.IntervalMedium, "BuildStageStats never populated")
+
+	stats := cache.TemplateBuildStageStats(template.ID)
+	require.Len(t, stats, len(database.AllWorkspaceBuildStageValues()))
+	for stage, stat := range stats {
+		if stage != codersdk.WorkspaceBuildStageInit {
+			require.Empty(t, stat, "%v", stage)
+			continue
+		}
+		// len("start") minutes.
+		require.Equal(t, (5 * time.Minute).Milliseconds(), *stat.P50)
+		require.Equal(t, (5 * time.Minute).Milliseconds(), *stat.P95)
+	}
+}
+
 func TestCache_DeploymentStats(t *testing.T) {
 	t.Parallel()
 	db := dbfake.New()
@@ -0,0 +1,780 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/oauth2provider"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/site"
+)
+
+const (
+	// oauth2CodeLifetime is how long apps have to exchange an authorization
+	// code for tokens.
+	oauth2CodeLifetime = 10 * time.Minute
+	// oauth2AccessTokenLifetime is kept short, as apps get new access tokens
+	// with their refresh token. It's also too short for the API key middleware
+	// to extend it.
+	oauth2AccessTokenLifetime = time.Hour
+	// oauth2RefreshTokenLifetime is how long an app may go without using
+	// its refresh token before the user has to authorize it again.
+	oauth2RefreshTokenLifetime = 30 * 24 * time.Hour
+)
+
+// @Summary Get OAuth2 apps
+// @ID get-oauth2-apps
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param user_id query string false "Filter by applications authorized for a user"
+// @Success 200 {array} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [get]
+func (api *API) oAuth2ProviderApps(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var (
+		apps []database.OAuth2ProviderApp
+		err  error
+	)
+	rawUserID := r.URL.Query().Get("user_id")
+	if rawUserID == "" {
+		apps, err = api.Database.GetOAuth2ProviderApps(ctx)
+	} else {
+		userID, parseErr := uuid.Parse(rawUserID)
+		if parseErr != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid user UUID.",
+				Detail:  parseErr.Error(),
+			})
+			return
+		}
+		apps, err = api.Database.GetOAuth2ProviderAppsByUserID(ctx, userID)
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 apps.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApps(apps))
+}
+
+// @Summary Get OAuth2 app
+// @ID get-oauth2-app
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [get]
+func (api *API) oAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	app := httpmw.OAuth2ProviderApp(r)
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Create OAuth2 app
+// @ID create-oauth2-app
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param request body codersdk.PostOAuth2ProviderAppRequest true "The OAuth2 app to create."
+// @Success 201 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps [post]
+func (api *API) postOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req codersdk.PostOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !validOAuth2RedirectURIs(ctx, rw, req.RedirectURIs) {
+		return
+	}
+
+	app, err := api.Database.InsertOAuth2ProviderApp(ctx, database.InsertOAuth2ProviderAppParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		UpdatedAt:    database.Now(),
+		Name:         req.Name,
+		Icon:         req.Icon,
+		RedirectURIs: req.RedirectURIs,
+	})
+	if database.IsUniqueViolation(err, database.UniqueOauth2ProviderAppsNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("An OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, api.convertOAuth2ProviderApp(app))
+}
+
+// @Summary Update OAuth2 app
+// @ID update-oauth2-app
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Param request body codersdk.PutOAuth2ProviderAppRequest true "Update an OAuth2 app."
+// @Success 200 {object} codersdk.OAuth2ProviderApp
+// @Router /oauth2-provider/apps/{app} [put]
+func (api *API) putOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		app = httpmw.OAuth2ProviderApp(r)
+	)
+	var req codersdk.PutOAuth2ProviderAppRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !validOAuth2RedirectURIs(ctx, rw, req.RedirectURIs) {
+		return
+	}
+
+	app, err := api.Database.UpdateOAuth2ProviderAppByID(ctx, database.UpdateOAuth2ProviderAppByIDParams{
+		ID:           app.ID,
+		UpdatedAt:    database.Now(),
+		Name:         req.Name,
+		Icon:         req.Icon,
+		RedirectURIs: req.RedirectURIs,
+	})
+	if database.IsUniqueViolation(err, database.UniqueOauth2ProviderAppsNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("An OAuth2 app with name %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertOAuth2ProviderApp(app))
+}
+
+// Deleting an app revokes all API keys it holds.
+//
+// @Summary Delete OAuth2 app
+// @ID delete-oauth2-app
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 204
+// @Router /oauth2-provider/apps/{app} [delete]
+func (api *API) deleteOAuth2ProviderApp(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		app = httpmw.OAuth2ProviderApp(r)
+	)
+	err := api.Database.DeleteOAuth2ProviderAppByID(ctx, app.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 app.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get OAuth2 app secrets
+// @ID get-oauth2-app-secrets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 200 {array} codersdk.OAuth2ProviderAppSecret
+// @Router /oauth2-provider/apps/{app}/secrets [get]
+func (api *API) oAuth2ProviderAppSecrets(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		app = httpmw.OAuth2ProviderApp(r)
+	)
+	dbSecrets, err := api.Database.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching OAuth2 app secrets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	secrets := make([]codersdk.OAuth2ProviderAppSecret, 0, len(dbSecrets))
+	for _, secret := range dbSecrets {
+		secrets = append(secrets, codersdk.OAuth2ProviderAppSecret{
+			ID:                    secret.ID,
+			LastUsedAt:            codersdk.NullTime{NullTime: secret.LastUsedAt},
+			ClientSecretTruncated: secret.DisplaySecret,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, secrets)
+}
+
+// The full secret is only returned once, so it has to be copied into the
+// configuration of the app right away.
+//
+// @Summary Create OAuth2 app secret
+// @ID create-oauth2-app-secret
+// @Security CoderSessionToken
+// @Produce json
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 201 {object} codersdk.OAuth2ProviderAppSecretFull
+// @Router /oauth2-provider/apps/{app}/secrets [post]
+func (api *API) postOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		app = httpmw.OAuth2ProviderApp(r)
+	)
+	secret, err := oauth2provider.GenerateSecret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating OAuth2 app secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	dbSecret, err := api.Database.InsertOAuth2ProviderAppSecret(ctx, database.InsertOAuth2ProviderAppSecretParams{
+		ID:            uuid.New(),
+		CreatedAt:     database.Now(),
+		SecretPrefix:  secret.Prefix,
+		HashedSecret:  secret.Hashed,
+		DisplaySecret: secret.Display,
+		AppID:         app.ID,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating OAuth2 app secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.OAuth2ProviderAppSecretFull{
+		ID:               dbSecret.ID,
+		ClientSecretFull: secret.Formatted,
+	})
+}
+
+// Deleting a secret revokes the API keys that were issued with it.
+//
+// @Summary Delete OAuth2 app secret
+// @ID delete-oauth2-app-secret
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Param secretID path string true "Secret ID"
+// @Success 204
+// @Router /oauth2-provider/apps/{app}/secrets/{secretID} [delete]
+func (api *API) deleteOAuth2ProviderAppSecret(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		secret = httpmw.OAuth2ProviderAppSecret(r)
+	)
+	err := api.Database.DeleteOAuth2ProviderAppSecretByID(ctx, secret.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting OAuth2 app secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// Users revoke the access they granted an app. The API keys also show up in
+// the tokens of the user, where they can be deleted one by one.
+//
+// @Summary Revoke OAuth2 app tokens
+// @ID revoke-oauth2-app-tokens
+// @Security CoderSessionToken
+// @Tags OAuth2
+// @Param app path string true "App ID"
+// @Success 204
+// @Router /oauth2-provider/apps/{app}/tokens [delete]
+func (api *API) deleteOAuth2ProviderAppTokens(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		app    = httpmw.OAuth2ProviderApp(r)
+		apiKey = httpmw.APIKey(r)
+	)
+	err := api.Database.DeleteOAuth2ProviderAppTokensByAppAndUserID(ctx, database.DeleteOAuth2ProviderAppTokensByAppAndUserIDParams{
+		AppID:  app.ID,
+		UserID: apiKey.UserID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking OAuth2 app tokens.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// oauth2AuthorizeRequest are the validated parameters of an authorization
+// request (RFC 6749, section 4.1.1).
+type oauth2AuthorizeRequest struct {
+	app         database.OAuth2ProviderApp
+	redirectURI string
+	state       string
+	scope       database.APIKeyScope
+}
+
+// parseOAuth2AuthorizeRequest validates the authorization request. Until the
+// redirect URI is known to be registered, errors are rendered instead of sent
+// to the redirect URI, so the endpoint can't be used as an open redirect.
+func (api *API) parseOAuth2AuthorizeRequest(rw http.ResponseWriter, r *http.Request) (oauth2AuthorizeRequest, bool) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	renderError := func(status int, title, description string) (oauth2AuthorizeRequest, bool) {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       status,
+			Title:        title,
+			Description:  description,
+			RetryEnabled: false,
+			DashboardURL: api.AccessURL.String(),
+		})
+		return oauth2AuthorizeRequest{}, false
+	}
+
+	appID, err := uuid.Parse(query.Get("client_id"))
+	if err != nil {
+		return renderError(http.StatusBadRequest, "Invalid client", "The client_id parameter must be the ID of an OAuth2 app.")
+	}
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return renderError(http.StatusNotFound, "Invalid client", "The OAuth2 app does not exist.")
+	}
+	if err != nil {
+		return renderError(http.StatusInternalServerError, "Internal error", "Failed to fetch the OAuth2 app: "+err.Error())
+	}
+
+	redirectURI := query.Get("redirect_uri")
+	if redirectURI == "" && len(app.RedirectURIs) == 1 {
+		redirectURI = app.RedirectURIs[0]
+	}
+	if !oauth2provider.MatchRedirectURI(app.RedirectURIs, redirectURI) {
+		return renderError(http.StatusBadRequest, "Invalid redirect URI", "The redirect_uri parameter is not registered for the OAuth2 app.")
+	}
+
+	req := oauth2AuthorizeRequest{
+		app:         app,
+		redirectURI: redirectURI,
+		state:       query.Get("state"),
+	}
+	if codersdk.OAuth2ProviderResponseType(query.Get("response_type")) != codersdk.OAuth2ProviderResponseTypeCode {
+		redirectOAuth2Error(rw, r, req, "unsupported_response_type")
+		return oauth2AuthorizeRequest{}, false
+	}
+	switch scope := database.APIKeyScope(query.Get("scope")); scope {
+	case "":
+		req.scope = database.APIKeyScopeAll
+	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+		req.scope = scope
+	default:
+		redirectOAuth2Error(rw, r, req, "invalid_scope")
+		return oauth2AuthorizeRequest{}, false
+	}
+	return req, true
+}
+
+// redirectOAuth2Error sends an error to the redirect URI of the app (RFC 6749,
+// section 4.1.2.1).
+func redirectOAuth2Error(rw http.ResponseWriter, r *http.Request, req oauth2AuthorizeRequest, code string) {
+	redirectOAuth2(rw, r, req, url.Values{"error": {code}})
+}
+
+func redirectOAuth2(rw http.ResponseWriter, r *http.Request, req oauth2AuthorizeRequest, values url.Values) {
+	// The redirect URI is registered and was validated on registration.
+	redirectURL, _ := url.Parse(req.redirectURI)
+	query := redirectURL.Query()
+	for key, value := range values {
+		query[key] = value
+	}
+	if req.state != "" {
+		query.Set("state", req.state)
+	}
+	redirectURL.RawQuery = query.Encode()
+	http.Redirect(rw, r, redirectURL.String(), http.StatusFound)
+}
+
+// getOAuth2ProviderAppAuthorize renders the page users allow apps to access
+// Coder on. Apps send users here to start the authorization code flow.
+func (api *API) getOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	req, ok := api.parseOAuth2AuthorizeRequest(rw, r)
+	if !ok {
+		return
+	}
+
+	// The redirect URI is registered, so it's trusted even when native apps
+	// use custom schemes.
+	cancelURL, _ := url.Parse(req.redirectURI)
+	query := cancelURL.Query()
+	query.Set("error", "access_denied")
+	if req.state != "" {
+		query.Set("state", req.state)
+	}
+	cancelURL.RawQuery = query.Encode()
+
+	site.RenderOAuthAllowPage(rw, r, site.RenderOAuthAllowData{
+		AppName:   req.app.Name,
+		AppIcon:   req.app.Icon,
+		Username:  httpmw.UserAuthorization(r).Username,
+		Scope:     string(req.scope),
+		CancelURI: template.URL(cancelURL.String()), //nolint:gosec // The URI is registered by admins.
+	})
+}
+
+// postOAuth2ProviderAppAuthorize is posted by the allow page and sends users
+// to the redirect URI with an authorization code.
+func (api *API) postOAuth2ProviderAppAuthorize(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+	req, ok := api.parseOAuth2AuthorizeRequest(rw, r)
+	if !ok {
+		return
+	}
+
+	code, err := oauth2provider.GenerateSecret()
+	if err != nil {
+		redirectOAuth2Error(rw, r, req, "server_error")
+		return
+	}
+	_, err = api.Database.InsertOAuth2ProviderAppCode(ctx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		ExpiresAt:    database.Now().Add(oauth2CodeLifetime),
+		SecretPrefix: code.Prefix,
+		HashedSecret: code.Hashed,
+		UserID:       apiKey.UserID,
+		AppID:        req.app.ID,
+		Scope:        req.scope,
+		RedirectURI:  req.redirectURI,
+	})
+	if err != nil {
+		api.Logger.Error(ctx, "insert oauth2 authorization code", slog.Error(err))
+		redirectOAuth2Error(rw, r, req, "server_error")
+		return
+	}
+
+	redirectOAuth2(rw, r, req, url.Values{"code": {code.Formatted}})
+}
+
+// postOAuth2ProviderAppToken exchanges authorization codes and refresh tokens
+// for access tokens (RFC 6749, sections 4.1.3 and 6). Apps authenticate with
+// their client ID and a client secret, either in the form or with HTTP basic
+// authentication.
+func (api *API) postOAuth2ProviderAppToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	writeError := func(status int, code, description string) {
+		httpapi.Write(ctx, rw, status, codersdk.OAuth2ProviderError{
+			Error:            code,
+			ErrorDescription: description,
+		})
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(http.StatusBadRequest, "invalid_request", "Failed to parse the form: "+err.Error())
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	// Apps don't authenticate as a user, and the user the tokens are issued
+	// to is only known once the code or refresh token was validated.
+	//nolint:gocritic // The app is authenticated with its client secret below.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	appSecret, ok := api.authenticateOAuth2ProviderApp(ctx, clientID, clientSecret)
+	if !ok {
+		writeError(http.StatusUnauthorized, "invalid_client", "The client ID or secret is invalid.")
+		return
+	}
+	app, err := api.Database.GetOAuth2ProviderAppByID(ctx, appSecret.AppID)
+	if err != nil {
+		writeError(http.StatusInternalServerError, "server_error", "Failed to fetch the OAuth2 app.")
+		return
+	}
+
+	var (
+		userID uuid.UUID
+		scope  database.APIKeyScope
+	)
+	switch codersdk.OAuth2ProviderGrantType(r.PostForm.Get("grant_type")) {
+	case codersdk.OAuth2ProviderGrantTypeAuthorizationCode:
+		code, err := api.redeemOAuth2ProviderAppCode(ctx, app, r.PostForm.Get("code"), r.PostForm.Get("redirect_uri"))
+		if err != nil {
+			writeError(http.StatusBadRequest, "invalid_grant", err.Error())
+			return
+		}
+		userID, scope = code.UserID, code.Scope
+	case codersdk.OAuth2ProviderGrantTypeRefreshToken:
+		key, err := api.redeemOAuth2ProviderAppRefreshToken(ctx, app, r.PostForm.Get("refresh_token"))
+		if err != nil {
+			writeError(http.StatusBadRequest, "invalid_grant", err.Error())
+			return
+		}
+		userID, scope = key.UserID, key.Scope
+	default:
+		writeError(http.StatusBadRequest, "unsupported_grant_type", "The grant_type must be authorization_code or refresh_token.")
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, userID)
+	if err != nil {
+		writeError(http.StatusInternalServerError, "server_error", "Failed to fetch the user.")
+		return
+	}
+	if user.Status == database.UserStatusSuspended || user.Deleted {
+		writeError(http.StatusBadRequest, "invalid_grant", "The user is suspended.")
+		return
+	}
+
+	refresh, err := oauth2provider.GenerateSecret()
+	if err != nil {
+		writeError(http.StatusInternalServerError, "server_error", "Failed to generate the refresh token.")
+		return
+	}
+	cookie, key, err := api.createAPIKey(ctx, createAPIKeyParams{
+		UserID:          userID,
+		RemoteAddr:      r.RemoteAddr,
+		LoginType:       database.LoginTypeToken,
+		LifetimeSeconds: int64(oauth2AccessTokenLifetime.Seconds()),
+		Scope:           scope,
+		// Token names are unique per user, and users revoke apps by the name.
+		TokenName: fmt.Sprintf("oauth2-%s-%s", app.Name, refresh.Prefix),
+	})
+	if err != nil {
+		writeError(http.StatusInternalServerError, "server_error", "Failed to create the access token.")
+		return
+	}
+	_, err = api.Database.InsertOAuth2ProviderAppToken(ctx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:                  uuid.New(),
+		CreatedAt:           database.Now(),
+		ExpiresAt:           database.Now().Add(oauth2RefreshTokenLifetime),
+		RefreshPrefix:       refresh.Prefix,
+		HashedRefreshSecret: refresh.Hashed,
+		AppSecretID:         appSecret.ID,
+		APIKeyID:            key.ID,
+	})
+	if err != nil {
+		writeError(http.StatusInternalServerError, "server_error", "Failed to create the refresh token.")
+		return
+	}
+
+	// Tokens must not be cached (RFC 6749, section 5.1).
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.OAuth2ProviderToken{
+		AccessToken:  cookie.Value,
+		TokenType:    "Bearer",
+		RefreshToken: refresh.Formatted,
+		ExpiresIn:    int64(oauth2AccessTokenLifetime.Seconds()),
+	})
+}
+
+// authenticateOAuth2ProviderApp returns the client secret the app
+// authenticated with.
+func (api *API) authenticateOAuth2ProviderApp(ctx context.Context, clientID, clientSecret string) (database.OAuth2ProviderAppSecret, bool) {
+	appID, err := uuid.Parse(clientID)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, false
+	}
+	prefix, secret, err := oauth2provider.ParseSecret(clientSecret)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, false
+	}
+	dbSecret, err := api.Database.GetOAuth2ProviderAppSecretByPrefix(ctx, prefix)
+	if err != nil {
+		return database.OAuth2ProviderAppSecret{}, false
+	}
+	if dbSecret.AppID != appID || !oauth2provider.ValidateSecret(dbSecret.HashedSecret, secret) {
+		return database.OAuth2ProviderAppSecret{}, false
+	}
+
+	dbSecret, err = api.Database.UpdateOAuth2ProviderAppSecretByID(ctx, database.UpdateOAuth2ProviderAppSecretByIDParams{
+		ID:         dbSecret.ID,
+		LastUsedAt: sql.NullTime{Time: database.Now(), Valid: true},
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "update oauth2 app secret last used", slog.Error(err))
+	}
+	return dbSecret, true
+}
+
+// redeemOAuth2ProviderAppCode validates an authorization code and deletes it,
+// as codes may only be used once.
+func (api *API) redeemOAuth2ProviderAppCode(ctx context.Context, app database.OAuth2ProviderApp, formatted, redirectURI string) (database.OAuth2ProviderAppCode, error) {
+	invalid := xerrors.New("authorization code is invalid or expired")
+	prefix, secret, err := oauth2provider.ParseSecret(formatted)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, invalid
+	}
+	code, err := api.Database.GetOAuth2ProviderAppCodeByPrefix(ctx, prefix)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, invalid
+	}
+	if code.AppID != app.ID || !oauth2provider.ValidateSecret(code.HashedSecret, secret) {
+		return database.OAuth2ProviderAppCode{}, invalid
+	}
+	// Deleting the code first ensures concurrent requests can't both
+	// redeem it. Only the request that deleted it gets a row back.
+	_, err = api.Database.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, invalid
+	}
+	if database.Now().After(code.ExpiresAt) {
+		return database.OAuth2ProviderAppCode{}, invalid
+	}
+	// The redirect URI must match the one the code was sent to, if the app
+	// included it in the authorization request (RFC 6749, section 4.1.3).
+	if redirectURI != "" && redirectURI != code.RedirectURI {
+		return database.OAuth2ProviderAppCode{}, xerrors.New("redirect_uri does not match the authorization request")
+	}
+	return code, nil
+}
+
+// redeemOAuth2ProviderAppRefreshToken validates a refresh token and deletes it
+// along with the API key it was issued with. It returns the deleted API key.
+func (api *API) redeemOAuth2ProviderAppRefreshToken(ctx context.Context, app database.OAuth2ProviderApp, formatted string) (database.APIKey, error) {
+	invalid := xerrors.New("refresh token is invalid or expired")
+	prefix, secret, err := oauth2provider.ParseSecret(formatted)
+	if err != nil {
+		return database.APIKey{}, invalid
+	}
+	token, err := api.Database.GetOAuth2ProviderAppTokenByPrefix(ctx, prefix)
+	if err != nil {
+		return database.APIKey{}, invalid
+	}
+	if !oauth2provider.ValidateSecret(token.HashedRefreshSecret, secret) || database.Now().After(token.ExpiresAt) {
+		return database.APIKey{}, invalid
+	}
+	tokenSecret, err := api.Database.GetOAuth2ProviderAppSecretByID(ctx, token.AppSecretID)
+	if err != nil || tokenSecret.AppID != app.ID {
+		return database.APIKey{}, invalid
+	}
+	key, err := api.Database.GetAPIKeyByID(ctx, token.APIKeyID)
+	if err != nil {
+		return database.APIKey{}, invalid
+	}
+	// Deleting the refresh token first ensures concurrent requests can't
+	// both redeem it. Only the request that deleted it gets a row back.
+	_, err = api.Database.DeleteOAuth2ProviderAppTokenByPrefix(ctx, prefix)
+	if err != nil {
+		return database.APIKey{}, invalid
+	}
+	err = api.Database.DeleteAPIKeyByID(ctx, key.ID)
+	if err != nil {
+		return database.APIKey{}, invalid
+	}
+	return key, nil
+}
+
+func validOAuth2RedirectURIs(ctx context.Context, rw http.ResponseWriter, uris []string) bool {
+	var validations []codersdk.ValidationError
+	for _, uri := range uris {
+		err := oauth2provider.ValidRedirectURI(uri)
+		if err != nil {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "redirect_uris",
+				Detail: fmt.Sprintf("Invalid redirect URI %q: %s.", uri, err.Error()),
+			})
+		}
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid redirect URIs.",
+			Validations: validations,
+		})
+		return false
+	}
+	return true
+}
+
+func (api *API) convertOAuth2ProviderApp(app database.OAuth2ProviderApp) codersdk.OAuth2ProviderApp {
+	return codersdk.OAuth2ProviderApp{
+		ID:           app.ID,
+		Name:         app.Name,
+		Icon:         app.Icon,
+		RedirectURIs: app.RedirectURIs,
+		Endpoints: codersdk.OAuth2AppEndpoints{
+			Authorization: api.AccessURL.ResolveReference(&url.URL{Path: "/oauth2/authorize"}).String(),
+			Token:         api.AccessURL.ResolveReference(&url.URL{Path: "/oauth2/tokens"}).String(),
+		},
+	}
+}
+
+func (api *API) convertOAuth2ProviderApps(apps []database.OAuth2ProviderApp) []codersdk.OAuth2ProviderApp {
+	converted := make([]codersdk.OAuth2ProviderApp, 0, len(apps))
+	for _, app := range apps {
+		converted = append(converted, api.convertOAuth2ProviderApp(app))
+	}
+	return converted
+}
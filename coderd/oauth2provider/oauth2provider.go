@@ -0,0 +1,107 @@
+// Package oauth2provider implements the parts of the OAuth2 authorization
+// server that don't depend on the API: the secrets handed out to apps and the
+// validation of redirect URIs.
+package oauth2provider
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cryptorand"
+)
+
+const (
+	secretScheme = "coder"
+	prefixLength = 10
+	secretLength = 40
+	// displayLength is how many characters of a client secret are shown to
+	// admins after it was created, so secrets in use can be told apart.
+	displayLength = 6
+)
+
+// Secret is a client secret, authorization code or refresh token. Only the
+// prefix and the hash are stored.
+type Secret struct {
+	// Formatted is handed out once and looks like coder_<prefix>_<secret>.
+	Formatted string
+	// Prefix identifies the secret in the database.
+	Prefix string
+	// Hashed is the SHA-256 hash of the secret.
+	Hashed []byte
+	// Display is the tail of the secret shown to admins.
+	Display string
+}
+
+// GenerateSecret returns a random secret.
+func GenerateSecret() (Secret, error) {
+	prefix, err := cryptorand.String(prefixLength)
+	if err != nil {
+		return Secret{}, xerrors.Errorf("generate prefix: %w", err)
+	}
+	secret, err := cryptorand.String(secretLength)
+	if err != nil {
+		return Secret{}, xerrors.Errorf("generate secret: %w", err)
+	}
+	hashed := sha256.Sum256([]byte(secret))
+	return Secret{
+		Formatted: fmt.Sprintf("%s_%s_%s", secretScheme, prefix, secret),
+		Prefix:    prefix,
+		Hashed:    hashed[:],
+		Display:   secret[len(secret)-displayLength:],
+	}, nil
+}
+
+// ParseSecret returns the prefix and the secret of a formatted secret.
+func ParseSecret(formatted string) (prefix string, secret string, err error) {
+	parts := strings.Split(formatted, "_")
+	if len(parts) != 3 || parts[0] != secretScheme {
+		return "", "", xerrors.New("incorrectly formatted secret")
+	}
+	if len(parts[1]) != prefixLength || len(parts[2]) != secretLength {
+		return "", "", xerrors.New("invalid secret length")
+	}
+	return parts[1], parts[2], nil
+}
+
+// ValidateSecret reports whether the secret matches the stored hash.
+func ValidateSecret(hashed []byte, secret string) bool {
+	got := sha256.Sum256([]byte(secret))
+	return subtle.ConstantTimeCompare(hashed, got[:]) == 1
+}
+
+// ValidRedirectURI returns an error if the URI can't be registered as a
+// redirect URI. Native apps may use custom schemes, but URIs must be absolute
+// and can't contain a fragment (RFC 6749, section 3.1.2).
+func ValidRedirectURI(uri string) error {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return xerrors.Errorf("parse: %w", err)
+	}
+	if !parsed.IsAbs() {
+		return xerrors.New("must be an absolute URI")
+	}
+	if (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host == "" {
+		return xerrors.New("must have a host")
+	}
+	if parsed.Fragment != "" || strings.Contains(uri, "#") {
+		return xerrors.New("must not contain a fragment")
+	}
+	return nil
+}
+
+// MatchRedirectURI reports whether the URI is one of the registered redirect
+// URIs. URIs must match exactly, as anything looser lets attackers receive
+// authorization codes on paths they control.
+func MatchRedirectURI(registered []string, uri string) bool {
+	for _, r := range registered {
+		if r == uri {
+			return true
+		}
+	}
+	return false
+}
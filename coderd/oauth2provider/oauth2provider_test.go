@@ -0,0 +1,60 @@
+package oauth2provider_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/oauth2provider"
+)
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	secret, err := oauth2provider.GenerateSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret.Formatted, "coder_"+secret.Prefix+"_"))
+	require.True(t, strings.HasSuffix(secret.Formatted, secret.Display))
+
+	prefix, raw, err := oauth2provider.ParseSecret(secret.Formatted)
+	require.NoError(t, err)
+	require.Equal(t, secret.Prefix, prefix)
+	require.True(t, oauth2provider.ValidateSecret(secret.Hashed, raw))
+	require.False(t, oauth2provider.ValidateSecret(secret.Hashed, raw+"x"))
+
+	for _, invalid := range []string{
+		"",
+		"coder_abc_def",
+		strings.Replace(secret.Formatted, "coder_", "other_", 1),
+		secret.Formatted + "_extra",
+	} {
+		_, _, err := oauth2provider.ParseSecret(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestRedirectURI(t *testing.T) {
+	t.Parallel()
+
+	for _, valid := range []string{
+		"https://example.com/callback",
+		"http://localhost:3000/callback",
+		"vscode://coder.coder-remote/oauth",
+	} {
+		require.NoError(t, oauth2provider.ValidRedirectURI(valid), valid)
+	}
+	for _, invalid := range []string{
+		"/callback",
+		"https://",
+		"https://example.com/callback#fragment",
+		"://example.com",
+	} {
+		require.Error(t, oauth2provider.ValidRedirectURI(invalid), invalid)
+	}
+
+	registered := []string{"https://example.com/callback"}
+	require.True(t, oauth2provider.MatchRedirectURI(registered, "https://example.com/callback"))
+	require.False(t, oauth2provider.MatchRedirectURI(registered, "https://example.com/callback/other"))
+	require.False(t, oauth2provider.MatchRedirectURI(registered, "https://example.com/callback?query=1"))
+}
@@ -0,0 +1,331 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestOAuth2ProviderApps(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	req := codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "vscode",
+		Icon:         "/icon/code.svg",
+		RedirectURIs: []string{"http://localhost:3000/callback"},
+	}
+	var apiErr *codersdk.Error
+	_, err := member.PostOAuth2ProviderApp(ctx, req)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	_, err = client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         req.Name,
+		RedirectURIs: []string{"/callback#fragment"},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	app, err := client.PostOAuth2ProviderApp(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, req.RedirectURIs, app.RedirectURIs)
+	require.Equal(t, client.URL.String()+"/oauth2/authorize", app.Endpoints.Authorization)
+
+	_, err = client.PostOAuth2ProviderApp(ctx, req)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	// Members see the apps they may authorize, but can't change them.
+	apps, err := member.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{})
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	_, err = member.PutOAuth2ProviderApp(ctx, app.ID, codersdk.PutOAuth2ProviderAppRequest{
+		Name:         "other",
+		RedirectURIs: req.RedirectURIs,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	app, err = client.PutOAuth2ProviderApp(ctx, app.ID, codersdk.PutOAuth2ProviderAppRequest{
+		Name:         "other",
+		RedirectURIs: []string{"vscode://coder.coder-remote/oauth"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "other", app.Name)
+
+	err = client.DeleteOAuth2ProviderApp(ctx, app.ID)
+	require.NoError(t, err)
+	_, err = client.OAuth2ProviderApp(ctx, app.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestOAuth2ProviderAppSecrets(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "app",
+		RedirectURIs: []string{"http://localhost:3000/callback"},
+	})
+	require.NoError(t, err)
+
+	var apiErr *codersdk.Error
+	_, err = member.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+
+	secrets, err := client.OAuth2ProviderAppSecrets(ctx, app.ID)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	require.Equal(t, secret.ID, secrets[0].ID)
+	require.NotEqual(t, secret.ClientSecretFull, secrets[0].ClientSecretTruncated)
+	require.Contains(t, secret.ClientSecretFull, secrets[0].ClientSecretTruncated)
+	require.False(t, secrets[0].LastUsedAt.Valid)
+
+	err = client.DeleteOAuth2ProviderAppSecret(ctx, app.ID, secret.ID)
+	require.NoError(t, err)
+	secrets, err = client.OAuth2ProviderAppSecrets(ctx, app.ID)
+	require.NoError(t, err)
+	require.Empty(t, secrets)
+}
+
+func TestOAuth2ProviderTokenExchange(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "app",
+		RedirectURIs: []string{"http://localhost:3000/callback"},
+	})
+	require.NoError(t, err)
+	secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+
+	config := &oauth2.Config{
+		ClientID:     app.ID.String(),
+		ClientSecret: secret.ClientSecretFull,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  app.Endpoints.Authorization,
+			TokenURL: app.Endpoints.Token,
+		},
+		RedirectURL: app.RedirectURIs[0],
+	}
+	// authorize allows the app as the member and returns the URL the member
+	// is redirected to.
+	authorize := func(t *testing.T, authURL string) *url.URL {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, nil)
+		require.NoError(t, err)
+		req.Header.Set(codersdk.SessionTokenHeader, member.SessionToken())
+		httpClient := &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		res, err := httpClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		location, err := res.Location()
+		require.NoError(t, err)
+		return location
+	}
+
+	// Unregistered redirect URIs are never redirected to.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.AuthCodeURL("state", oauth2.SetAuthURLParam("redirect_uri", "http://evil.com")), nil)
+	require.NoError(t, err)
+	req.Header.Set(codersdk.SessionTokenHeader, member.SessionToken())
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	location := authorize(t, config.AuthCodeURL("state", oauth2.SetAuthURLParam("scope", "invalid")))
+	require.Equal(t, "invalid_scope", location.Query().Get("error"))
+	require.Equal(t, "state", location.Query().Get("state"))
+
+	location = authorize(t, config.AuthCodeURL("state"))
+	require.Equal(t, "state", location.Query().Get("state"))
+	code := location.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	_, err = (&oauth2.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: "coder_invalid_secret",
+		Endpoint:     config.Endpoint,
+		RedirectURL:  config.RedirectURL,
+	}).Exchange(ctx, code)
+	require.Error(t, err)
+
+	token, err := config.Exchange(ctx, code)
+	require.NoError(t, err)
+	require.Equal(t, "Bearer", token.TokenType)
+	require.NotEmpty(t, token.RefreshToken)
+	require.WithinDuration(t, time.Now().Add(time.Hour), token.Expiry, time.Minute)
+
+	// Codes can only be redeemed once.
+	_, err = config.Exchange(ctx, code)
+	require.Error(t, err)
+
+	appClient := codersdk.New(client.URL)
+	appClient.SetSessionToken(token.AccessToken)
+	me, err := appClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, me.ID)
+
+	// The access token is listed with the tokens of the user, and the app
+	// with the apps the user authorized.
+	tokens, err := member.Tokens(ctx, user.ID.String(), codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	apps, err := member.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	require.Equal(t, app.ID, apps[0].ID)
+
+	// Refreshing replaces the access token.
+	token.Expiry = time.Now().Add(-time.Minute)
+	refreshed, err := config.TokenSource(ctx, token).Token()
+	require.NoError(t, err)
+	require.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+	_, err = appClient.User(ctx, codersdk.Me)
+	require.Error(t, err)
+	appClient.SetSessionToken(refreshed.AccessToken)
+	_, err = appClient.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	secrets, err := client.OAuth2ProviderAppSecrets(ctx, app.ID)
+	require.NoError(t, err)
+	require.True(t, secrets[0].LastUsedAt.Valid)
+
+	// Revoking deletes the access token, and the refresh token with it.
+	err = member.RevokeOAuth2ProviderApp(ctx, app.ID)
+	require.NoError(t, err)
+	_, err = appClient.User(ctx, codersdk.Me)
+	require.Error(t, err)
+	refreshed.Expiry = time.Now().Add(-time.Minute)
+	_, err = config.TokenSource(ctx, refreshed).Token()
+	require.Error(t, err)
+	apps, err = member.OAuth2ProviderApps(ctx, codersdk.OAuth2ProviderAppFilter{UserID: user.ID})
+	require.NoError(t, err)
+	require.Empty(t, apps)
+}
+
+func TestOAuth2ProviderConcurrentRedeem(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	app, err := client.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:         "app",
+		RedirectURIs: []string{"http://localhost:3000/callback"},
+	})
+	require.NoError(t, err)
+	secret, err := client.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+	config := &oauth2.Config{
+		ClientID:     app.ID.String(),
+		ClientSecret: secret.ClientSecretFull,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  app.Endpoints.Authorization,
+			TokenURL: app.Endpoints.Token,
+		},
+		RedirectURL: app.RedirectURIs[0],
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.AuthCodeURL("state"), nil)
+	require.NoError(t, err)
+	req.Header.Set(codersdk.SessionTokenHeader, member.SessionToken())
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := httpClient.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	location, err := res.Location()
+	require.NoError(t, err)
+	code := location.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	// redeemConcurrently runs redeem from several goroutines at once and
+	// returns the tokens of the ones that succeeded.
+	redeemConcurrently := func(redeem func() (*oauth2.Token, error)) []*oauth2.Token {
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			tokens []*oauth2.Token
+		)
+		start := make(chan struct{})
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				token, err := redeem()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				tokens = append(tokens, token)
+				mu.Unlock()
+			}()
+		}
+		close(start)
+		wg.Wait()
+		return tokens
+	}
+
+	// Only one of the requests redeeming the same code gets a token.
+	tokens := redeemConcurrently(func() (*oauth2.Token, error) {
+		return config.Exchange(ctx, code)
+	})
+	require.Len(t, tokens, 1)
+
+	// The same goes for refresh tokens.
+	expired := *tokens[0]
+	expired.Expiry = time.Now().Add(-time.Minute)
+	tokens = redeemConcurrently(func() (*oauth2.Token, error) {
+		token := expired
+		return config.TokenSource(ctx, &token).Token()
+	})
+	require.Len(t, tokens, 1)
+}
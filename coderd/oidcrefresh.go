@@ -0,0 +1,200 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+)
+
+// refreshOIDC periodically refreshes the tokens of OIDC users until the
+// context is canceled.
+func (api *API) refreshOIDC(ctx context.Context, interval time.Duration) {
+	defer close(api.oidcRefreshDone)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := api.refreshOIDCUsers(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			api.Logger.Error(ctx, "failed to refresh oidc users", slog.Error(err))
+		}
+	}
+}
+
+// refreshOIDCUsers refreshes the stored tokens of active OIDC users, updates
+// their roles from the user info of the provider, and suspends users whose
+// refresh tokens were revoked. Failing to refresh one user doesn't stop the
+// others from being refreshed.
+func (api *API) refreshOIDCUsers(ctx context.Context) error {
+	//nolint:gocritic // Refreshing is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	users, err := api.Database.GetUsers(ctx, database.GetUsersParams{
+		Status: []database.UserStatus{database.UserStatusActive},
+	})
+	if err != nil {
+		return xerrors.Errorf("get users: %w", err)
+	}
+
+	for _, user := range users {
+		if user.LoginType != database.LoginTypeOIDC {
+			continue
+		}
+		err := api.refreshOIDCUser(ctx, user)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			api.Logger.Warn(ctx, "failed to refresh oidc user",
+				slog.F("username", user.Username), slog.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// refreshOIDCUser refreshes the stored tokens and roles of a single OIDC user,
+// or suspends the user if the refresh token was revoked.
+//
+// Every replica refreshes the same users, and providers that rotate refresh
+// tokens reject a token once it was used. The refresh holds a lock so that
+// replicas take turns and always use the token stored by the last refresh.
+func (api *API) refreshOIDCUser(ctx context.Context, user database.GetUsersRow) error {
+	return api.Database.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, database.LockIDOIDCRefresh)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		return api.refreshOIDCUserTx(ctx, tx, user)
+	}, nil)
+}
+
+func (api *API) refreshOIDCUserTx(ctx context.Context, tx database.Store, user database.GetUsersRow) error {
+	link, err := tx.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
+		UserID:    user.ID,
+		LoginType: database.LoginTypeOIDC,
+	})
+	if err != nil {
+		return xerrors.Errorf("get user link: %w", err)
+	}
+	// Providers only return refresh tokens if they were requested, e.g.
+	// with the offline_access scope.
+	if link.OAuthRefreshToken == "" {
+		return nil
+	}
+
+	token, err := api.OIDCConfig.TokenSource(ctx, &oauth2.Token{
+		RefreshToken: link.OAuthRefreshToken,
+	}).Token()
+	if oauth2Revoked(err) {
+		// Logging in stores a new refresh token without taking the lock,
+		// which must not get the user suspended.
+		current, err := tx.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
+			UserID:    user.ID,
+			LoginType: database.LoginTypeOIDC,
+		})
+		if err != nil {
+			return xerrors.Errorf("get user link: %w", err)
+		}
+		if current.OAuthRefreshToken != link.OAuthRefreshToken {
+			return nil
+		}
+		_, err = tx.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("suspend: %w", err)
+		}
+		api.Logger.Info(ctx, "suspended user with a revoked oidc refresh token",
+			slog.F("username", user.Username), slog.F("linked_id", link.LinkedID),
+		)
+		return nil
+	}
+	if err != nil {
+		// The provider may be unavailable or reject the client, in which
+		// case the user must not be suspended.
+		return xerrors.Errorf("refresh token: %w", err)
+	}
+	_, err = tx.UpdateUserLink(ctx, database.UpdateUserLinkParams{
+		UserID:            user.ID,
+		LoginType:         database.LoginTypeOIDC,
+		OAuthAccessToken:  token.AccessToken,
+		OAuthRefreshToken: token.RefreshToken,
+		OAuthExpiry:       token.Expiry,
+	})
+	if err != nil {
+		return xerrors.Errorf("update user link: %w", err)
+	}
+
+	if api.OIDCConfig.UserRoleField == "" {
+		return nil
+	}
+	userInfo, err := api.OIDCConfig.Provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+	if err != nil {
+		if strings.Contains(err.Error(), "user info endpoint is not supported by this provider") {
+			return nil
+		}
+		return xerrors.Errorf("get user info: %w", err)
+	}
+	claims := map[string]interface{}{}
+	err = userInfo.Claims(&claims)
+	if err != nil {
+		return xerrors.Errorf("unmarshal user info claims: %w", err)
+	}
+	// The role claim may only be part of the ID token, in which case
+	// roles are synced on the next login.
+	if _, ok := claims[api.OIDCConfig.UserRoleField]; !ok {
+		return nil
+	}
+	_, err = tx.UpdateUserRoles(ctx, database.UpdateUserRolesParams{
+		GrantedRoles: api.OIDCConfig.userRoles(claims),
+		ID:           user.ID,
+	})
+	if err != nil {
+		return xerrors.Errorf("set roles: %w", err)
+	}
+	return nil
+}
+
+// oauth2Revoked returns whether the provider rejected a refresh token, as
+// opposed to failing to respond or rejecting the client.
+func oauth2Revoked(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) {
+		return false
+	}
+	// Revoked and expired refresh tokens are an invalid_grant error, while
+	// a wrong client secret is an invalid_client error.
+	// See https://www.rfc-editor.org/rfc/rfc6749#section-5.2
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(retrieveErr.Body, &body) != nil {
+		// Some providers respond with a form encoded body.
+		values, err := url.ParseQuery(string(retrieveErr.Body))
+		if err != nil {
+			return false
+		}
+		body.Error = values.Get("error")
+	}
+	return body.Error == "invalid_grant"
+}
@@ -0,0 +1,65 @@
+package coderd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
+)
+
+func Test_oauth2Revoked(t *testing.T) {
+	t.Parallel()
+
+	retrieveError := func(status int, body string) error {
+		return xerrors.Errorf("refresh: %w", &oauth2.RetrieveError{
+			Response: &http.Response{StatusCode: status},
+			Body:     []byte(body),
+		})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "NoError",
+		},
+		{
+			name: "NotRetrieveError",
+			err:  xerrors.New("connection refused"),
+		},
+		{
+			name: "InvalidGrant",
+			err:  retrieveError(http.StatusBadRequest, `{"error":"invalid_grant","error_description":"Token has been revoked."}`),
+			want: true,
+		},
+		{
+			name: "InvalidGrantForm",
+			err:  retrieveError(http.StatusBadRequest, "error=invalid_grant&error_description=revoked"),
+			want: true,
+		},
+		{
+			// A wrong client secret must not suspend every user.
+			name: "InvalidClient",
+			err:  retrieveError(http.StatusUnauthorized, `{"error":"invalid_client"}`),
+		},
+		{
+			name: "ServerError",
+			err:  retrieveError(http.StatusInternalServerError, "<html>Internal Server Error</html>"),
+		},
+		{
+			name: "EmptyBody",
+			err:  retrieveError(http.StatusBadRequest, ""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, oauth2Revoked(tt.err))
+		})
+	}
+}
@@ -2,6 +2,10 @@ package prometheusmetrics
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +13,7 @@ import (
 
 	"github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/provisionerdserver"
 )
 
 // ActiveUsers tracks the number of users that have authenticated within the past hour.
@@ -106,3 +111,78 @@ func Workspaces(ctx context.Context, registerer prometheus.Registerer, db databa
 	}()
 	return cancelFunc, nil
 }
+
+// ProvisionerJobQueue tracks the number of pending jobs per provisioner and
+// tag set, and the number of provisioner daemons in each status. Comparing
+// the two shows when jobs are waiting on tags no daemon satisfies.
+func ProvisionerJobQueue(ctx context.Context, registerer prometheus.Registerer, db database.Store, duration time.Duration) (context.CancelFunc, error) {
+	if duration == 0 {
+		duration = 15 * time.Second
+	}
+
+	pending := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_jobs",
+		Name:      "pending",
+		Help:      "The number of provisioner jobs waiting for a daemon, by provisioner and tags.",
+	}, []string{"provisioner", "tags"})
+	err := registerer.Register(pending)
+	if err != nil {
+		return nil, err
+	}
+	daemons := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_daemons",
+		Name:      "total",
+		Help:      "The number of provisioner daemons by status and tags.",
+	}, []string{"status", "tags"})
+	err = registerer.Register(daemons)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	ticker := time.NewTicker(duration)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			counts, err := db.GetPendingProvisionerJobCountsByTags(ctx)
+			if err == nil {
+				pending.Reset()
+				for _, count := range counts {
+					pending.WithLabelValues(string(count.Provisioner), formatTags(count.Tags)).Add(float64(count.Count))
+				}
+			}
+
+			rows, err := db.GetProvisionerDaemons(ctx)
+			if errors.Is(err, sql.ErrNoRows) {
+				err = nil
+			}
+			if err == nil {
+				now := database.Now()
+				daemons.Reset()
+				for _, daemon := range rows {
+					status := provisionerdserver.DaemonStatus(daemon, now)
+					daemons.WithLabelValues(string(status), formatTags(daemon.Tags)).Add(1)
+				}
+			}
+		}
+	}()
+	return cancelFunc, nil
+}
+
+// formatTags renders tags as a stable "key=value" list for use as a label.
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbfake"
 	"github.com/coder/coder/coderd/database/dbgen"
 	"github.com/coder/coder/coderd/prometheusmetrics"
+	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/testutil"
 )
@@ -239,3 +240,57 @@ func TestWorkspaces(t *testing.T) {
 		})
 	}
 }
+
+func TestProvisionerJobQueue(t *testing.T) {
+	t.Parallel()
+
+	db := dbfake.New()
+	registry := prometheus.NewRegistry()
+	cancel, err := prometheusmetrics.ProvisionerJobQueue(context.Background(), registry, db, time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(cancel)
+
+	tags := map[string]string{
+		provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+		"region":                    "eu",
+	}
+	for i := 0; i < 2; i++ {
+		dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			Provisioner: database.ProvisionerTypeTerraform,
+			Tags:        tags,
+		})
+	}
+	daemon, err := db.InsertProvisionerDaemon(context.Background(), database.InsertProvisionerDaemonParams{
+		ID:           uuid.New(),
+		CreatedAt:    database.Now(),
+		Name:         "test",
+		Provisioners: []database.ProvisionerType{database.ProvisionerTypeTerraform},
+		Tags:         tags,
+	})
+	require.NoError(t, err)
+	err = db.UpdateProvisionerDaemonHeartbeat(context.Background(), database.UpdateProvisionerDaemonHeartbeatParams{
+		ID: daemon.ID,
+		LastSeenAt: sql.NullTime{
+			Time:  database.Now(),
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		values := map[string]float64{}
+		for _, family := range metrics {
+			for _, metric := range family.Metric {
+				labels := family.GetName()
+				for _, label := range metric.Label {
+					labels += "," + label.GetName() + "=" + label.GetValue()
+				}
+				values[labels] = metric.Gauge.GetValue()
+			}
+		}
+		return values["coderd_provisioner_jobs_pending,provisioner=terraform,tags=region=eu,scope=organization"] == 2 &&
+			values["coderd_provisioner_daemons_total,status=online,tags=region=eu,scope=organization"] == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
@@ -0,0 +1,79 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/provisionerdserver"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get all provisioner daemons
+// @ID get-all-provisioner-daemons
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {array} codersdk.ProvisionerDaemon
+// @Router /provisionerdaemons [get]
+func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	daemons, err := api.Database.GetProvisionerDaemons(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner daemons.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	daemons, err = AuthorizeFilter(api.HTTPAuth, r, rbac.ActionRead, daemons)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner daemons.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := database.Now()
+	apiDaemons := make([]codersdk.ProvisionerDaemon, 0, len(daemons))
+	for _, daemon := range daemons {
+		apiDaemons = append(apiDaemons, ConvertProvisionerDaemon(daemon, now))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiDaemons)
+}
+
+// ConvertProvisionerDaemon converts a daemon to its API representation. The
+// status is computed relative to now.
+func ConvertProvisionerDaemon(daemon database.ProvisionerDaemon, now time.Time) codersdk.ProvisionerDaemon {
+	result := codersdk.ProvisionerDaemon{
+		ID:              daemon.ID,
+		CreatedAt:       daemon.CreatedAt,
+		UpdatedAt:       daemon.UpdatedAt,
+		Name:            daemon.Name,
+		Tags:            daemon.Tags,
+		Status:          provisionerdserver.DaemonStatus(daemon, now),
+		Version:         daemon.Version,
+		Hostname:        daemon.Hostname,
+		OperatingSystem: daemon.OperatingSystem,
+		Architecture:    daemon.Architecture,
+		Concurrency:     int(daemon.Concurrency),
+	}
+	if daemon.LastSeenAt.Valid {
+		result.LastSeenAt = &daemon.LastSeenAt.Time
+	}
+	if daemon.CurrentJobID.Valid {
+		result.CurrentJobID = &daemon.CurrentJobID.UUID
+	}
+	for _, provisionerType := range daemon.Provisioners {
+		result.Provisioners = append(result.Provisioners, codersdk.ProvisionerType(provisionerType))
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/buildinfo"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestProvisionerDaemons(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	var daemon codersdk.ProvisionerDaemon
+	require.Eventually(t, func() bool {
+		daemons, err := client.ProvisionerDaemons(ctx)
+		if err != nil || len(daemons) != 1 {
+			return false
+		}
+		daemon = daemons[0]
+		return daemon.LastSeenAt != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+
+	require.Equal(t, codersdk.ProvisionerDaemonOnline, daemon.Status)
+	require.Equal(t, buildinfo.Version(), daemon.Version)
+	require.NotEmpty(t, daemon.OperatingSystem)
+	require.NotEmpty(t, daemon.Architecture)
+	require.Equal(t, 1, daemon.Concurrency)
+	require.Nil(t, daemon.CurrentJobID)
+}
@@ -0,0 +1,43 @@
+package provisionerdserver
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// BuildStageMetrics observes how long each stage of workspace builds took.
+// Stages are observed where their end is recorded, so every stage is counted
+// once no matter how many replicas are running.
+type BuildStageMetrics struct {
+	durations *prometheus.HistogramVec
+}
+
+// NewBuildStageMetrics creates and registers the build stage histogram.
+func NewBuildStageMetrics(registerer prometheus.Registerer) *BuildStageMetrics {
+	m := &BuildStageMetrics{
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Subsystem: "workspace_builds",
+			Name:      "stage_duration_seconds",
+			Help:      "The duration of each stage of workspace builds.",
+			// Stages range from sub-second queueing to startup scripts that
+			// run for many minutes.
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		}, []string{"template_name", "stage"}),
+	}
+	registerer.MustRegister(m.durations)
+	return m
+}
+
+// Observe records how long a stage of a build of the template took. It's a
+// no-op on a nil receiver, so callers don't need to check if metrics are
+// enabled.
+func (m *BuildStageMetrics) Observe(templateName string, stage database.WorkspaceBuildStage, startedAt, endedAt time.Time) {
+	if m == nil {
+		return
+	}
+	m.durations.WithLabelValues(templateName, string(stage)).Observe(endedAt.Sub(startedAt).Seconds())
+}
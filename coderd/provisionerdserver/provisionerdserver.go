@@ -30,6 +30,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/gitauth"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/importpolicy"
 	"github.com/coder/coder/coderd/parameter"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
@@ -40,6 +41,18 @@ import (
 	sdkproto "github.com/coder/coder/provisionersdk/proto"
 )
 
+// ImportPolicyViolationErrorCode is set on template import jobs that were
+// rejected by the configured import policy.
+const ImportPolicyViolationErrorCode = string(codersdk.TemplateImportPolicyViolation)
+
+// NoMatchingDaemonErrorCode is set on jobs that were failed because no online
+// provisioner daemon could acquire them.
+const NoMatchingDaemonErrorCode = string(codersdk.NoMatchingProvisionerDaemon)
+
+// DaemonHeartbeatTimeout is how long a daemon can go without sending a
+// heartbeat before it's considered offline.
+const DaemonHeartbeatTimeout = time.Minute
+
 var (
 	lastAcquire      time.Time
 	lastAcquireMutex sync.RWMutex
@@ -58,6 +71,12 @@ type Server struct {
 	QuotaCommitter        *atomic.Pointer[proto.QuotaCommitter]
 	Auditor               *atomic.Pointer[audit.Auditor]
 	TemplateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
+	// ImportPolicy is evaluated against the plans of template version
+	// imports. Imports are not checked if nil.
+	ImportPolicy *importpolicy.Policy
+	// BuildStageMetrics observes the stages of workspace builds that end in
+	// this server. Stages aren't observed if nil.
+	BuildStageMetrics *BuildStageMetrics
 
 	AcquireJobDebounce time.Duration
 	OIDCConfig         httpmw.OAuth2Config
@@ -146,6 +165,20 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get workspace build: %s", err))
 		}
+		err = server.Database.UpsertWorkspaceBuildTiming(ctx, database.UpsertWorkspaceBuildTimingParams{
+			WorkspaceBuildID: workspaceBuild.ID,
+			Stage:            database.WorkspaceBuildStageQueued,
+			StartedAt:        job.CreatedAt,
+			EndedAt:          job.StartedAt,
+		})
+		if err != nil {
+			// Timings are only informational, so the build must go on.
+			server.Logger.Warn(ctx, "failed to record workspace build timing",
+				slog.F("workspace_build_id", workspaceBuild.ID),
+				slog.F("stage", database.WorkspaceBuildStageQueued),
+				slog.Error(err),
+			)
+		}
 		workspace, err := server.Database.GetWorkspaceByID(ctx, workspaceBuild.WorkspaceID)
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get workspace: %s", err))
@@ -162,6 +195,7 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get template: %s", err))
 		}
+		server.BuildStageMetrics.Observe(template.Name, database.WorkspaceBuildStageQueued, job.CreatedAt, job.StartedAt.Time)
 		owner, err := server.Database.GetUserByID(ctx, workspace.OwnerID)
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get owner: %s", err))
@@ -485,6 +519,11 @@ func (server *Server) UpdateJob(ctx context.Context, request *proto.UpdateJobReq
 			insertParams.Stage = append(insertParams.Stage, log.Stage)
 			insertParams.Source = append(insertParams.Source, logSource)
 			insertParams.Output = append(insertParams.Output, log.Output)
+			resourceProgress := convertResourceProgress(log.ResourceProgress)
+			insertParams.ResourceAddress = append(insertParams.ResourceAddress, resourceProgress.Address)
+			insertParams.ResourceAction = append(insertParams.ResourceAction, resourceProgress.Action)
+			insertParams.ResourceStatus = append(insertParams.ResourceStatus, string(resourceProgress.Status))
+			insertParams.ResourceElapsedSeconds = append(insertParams.ResourceElapsedSeconds, int32(resourceProgress.ElapsedSeconds))
 			server.Logger.Debug(ctx, "job log",
 				slog.F("job_id", parsedID),
 				slog.F("stage", log.Stage),
@@ -917,6 +956,31 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 			}
 		}
 
+		var completedErrorCode sql.NullString
+
+		violations, err := server.checkImportPolicy(ctx, jobType.TemplateImport)
+		if err != nil {
+			return nil, xerrors.Errorf("check import policy: %w", err)
+		}
+		if len(violations) > 0 {
+			err = server.logImportPolicyViolations(ctx, jobID, violations)
+			if err != nil {
+				return nil, xerrors.Errorf("log import policy violations: %w", err)
+			}
+			msg := fmt.Sprintf("template violates %d import policy rules, see the job logs for details", len(violations))
+			if len(violations) == 1 {
+				msg = "template violates import policy: " + violations[0]
+			}
+			completedError = sql.NullString{
+				String: msg,
+				Valid:  true,
+			}
+			completedErrorCode = sql.NullString{
+				String: ImportPolicyViolationErrorCode,
+				Valid:  true,
+			}
+		}
+
 		err = server.Database.UpdateTemplateVersionGitAuthProvidersByJobID(ctx, database.UpdateTemplateVersionGitAuthProvidersByJobIDParams{
 			JobID:            jobID,
 			GitAuthProviders: jobType.TemplateImport.GitAuthProviders,
@@ -933,7 +997,8 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 				Time:  database.Now(),
 				Valid: true,
 			},
-			Error: completedError,
+			Error:     completedError,
+			ErrorCode: completedErrorCode,
 		})
 		if err != nil {
 			return nil, xerrors.Errorf("update provisioner job: %w", err)
@@ -1021,6 +1086,25 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 			if err != nil {
 				return xerrors.Errorf("update workspace build: %w", err)
 			}
+			for _, timing := range jobType.WorkspaceBuild.Timings {
+				stage := database.WorkspaceBuildStage(timing.Stage)
+				if !stage.Valid() {
+					// Provisioners may time stages we don't track.
+					continue
+				}
+				err = db.UpsertWorkspaceBuildTiming(ctx, database.UpsertWorkspaceBuildTimingParams{
+					WorkspaceBuildID: workspaceBuild.ID,
+					Stage:            stage,
+					StartedAt:        time.UnixMilli(timing.StartedAt),
+					EndedAt: sql.NullTime{
+						Time:  time.UnixMilli(timing.EndedAt),
+						Valid: true,
+					},
+				})
+				if err != nil {
+					return xerrors.Errorf("insert workspace build timing: %w", err)
+				}
+			}
 
 			agentTimeouts := make(map[time.Duration]bool) // A set of agent timeouts.
 			// This could be a bulk insert to improve performance.
@@ -1092,6 +1176,21 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 			return nil, xerrors.Errorf("complete job: %w", err)
 		}
 
+		if getWorkspaceError == nil && server.BuildStageMetrics != nil {
+			template, err := server.Database.GetTemplateByID(ctx, workspace.TemplateID)
+			if err != nil {
+				server.Logger.Warn(ctx, "failed to get template to observe build stages", slog.Error(err))
+			} else {
+				for _, timing := range jobType.WorkspaceBuild.Timings {
+					stage := database.WorkspaceBuildStage(timing.Stage)
+					if !stage.Valid() {
+						continue
+					}
+					server.BuildStageMetrics.Observe(template.Name, stage, time.UnixMilli(timing.StartedAt), time.UnixMilli(timing.EndedAt))
+				}
+			}
+		}
+
 		// audit the outcome of the workspace build
 		if getWorkspaceError == nil {
 			auditor := server.Auditor.Load()
@@ -1187,6 +1286,41 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 	return &proto.Empty{}, nil
 }
 
+// Heartbeat records that the daemon is alive, along with its host information
+// and the job it's currently running.
+func (server *Server) Heartbeat(ctx context.Context, request *proto.HeartbeatRequest) (*proto.Empty, error) {
+	//nolint:gocritic // Provisionerd has specific authz rules.
+	ctx = dbauthz.AsProvisionerd(ctx)
+	var currentJobID uuid.NullUUID
+	if request.CurrentJobId != "" {
+		jobID, err := uuid.Parse(request.CurrentJobId)
+		if err != nil {
+			return nil, xerrors.Errorf("parse current job id: %w", err)
+		}
+		currentJobID = uuid.NullUUID{
+			UUID:  jobID,
+			Valid: true,
+		}
+	}
+	err := server.Database.UpdateProvisionerDaemonHeartbeat(ctx, database.UpdateProvisionerDaemonHeartbeatParams{
+		ID: server.ID,
+		LastSeenAt: sql.NullTime{
+			Time:  database.Now(),
+			Valid: true,
+		},
+		Version:         request.Version,
+		Hostname:        request.Hostname,
+		OperatingSystem: request.OperatingSystem,
+		Architecture:    request.Architecture,
+		Concurrency:     request.Concurrency,
+		CurrentJobID:    currentJobID,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("update provisioner daemon heartbeat: %w", err)
+	}
+	return &proto.Empty{}, nil
+}
+
 func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.UUID, transition database.WorkspaceTransition, protoResource *sdkproto.Resource, snapshot *telemetry.Snapshot) error {
 	resource, err := db.InsertWorkspaceResource(ctx, database.InsertWorkspaceResourceParams{
 		ID:         uuid.New(),
@@ -1477,6 +1611,30 @@ func convertLogSource(logSource proto.LogSource) (database.LogSource, error) {
 	}
 }
 
+// convertResourceProgress returns the zero value when the log doesn't report
+// resource progress, which matches the column defaults.
+func convertResourceProgress(progress *sdkproto.ResourceProgress) codersdk.ProvisionerJobResourceProgress {
+	if progress == nil {
+		return codersdk.ProvisionerJobResourceProgress{}
+	}
+	converted := codersdk.ProvisionerJobResourceProgress{
+		Address:        progress.Address,
+		Action:         progress.Action,
+		ElapsedSeconds: progress.ElapsedSeconds,
+	}
+	switch progress.Status {
+	case sdkproto.ResourceProgress_STARTED:
+		converted.Status = codersdk.ProvisionerJobResourceStatusStarted
+	case sdkproto.ResourceProgress_PROGRESS:
+		converted.Status = codersdk.ProvisionerJobResourceStatusProgress
+	case sdkproto.ResourceProgress_COMPLETE:
+		converted.Status = codersdk.ProvisionerJobResourceStatusComplete
+	case sdkproto.ResourceProgress_ERRORED:
+		converted.Status = codersdk.ProvisionerJobResourceStatusErrored
+	}
+	return converted
+}
+
 func convertRichParameterValues(workspaceBuildParameters []database.WorkspaceBuildParameter) []*sdkproto.RichParameterValue {
 	protoParameters := make([]*sdkproto.RichParameterValue, len(workspaceBuildParameters))
 	for i, buildParameter := range workspaceBuildParameters {
@@ -1626,3 +1784,116 @@ func redactTemplateVariable(templateVariable *sdkproto.TemplateVariable) *sdkpro
 	}
 	return maybeRedacted
 }
+
+// checkImportPolicy evaluates the configured import policy against the start
+// and stop plans of a template version import. Violations are prefixed with
+// the transition they were found in. Missing plans are violations, since the
+// policy can't be enforced without them.
+func (server *Server) checkImportPolicy(ctx context.Context, templateImport *proto.CompletedJob_TemplateImport) ([]string, error) {
+	if server.ImportPolicy == nil {
+		return nil, nil
+	}
+	var violations []string
+	for _, plan := range []struct {
+		transition database.WorkspaceTransition
+		json       []byte
+	}{
+		{database.WorkspaceTransitionStart, templateImport.StartPlanJson},
+		{database.WorkspaceTransitionStop, templateImport.StopPlanJson},
+	} {
+		denied, err := server.ImportPolicy.Evaluate(ctx, string(plan.transition), plan.json)
+		if errors.Is(err, importpolicy.ErrNoPlan) {
+			// Provisioner daemons that predate import policies, and
+			// provisioners other than Terraform, don't report plans.
+			violations = append(violations, fmt.Sprintf("%s: the provisioner didn't report a plan to check against the policy", plan.transition))
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("evaluate %s plan: %w", plan.transition, err)
+		}
+		for _, msg := range denied {
+			violations = append(violations, fmt.Sprintf("%s: %s", plan.transition, msg))
+		}
+	}
+	return violations, nil
+}
+
+// logImportPolicyViolations writes policy violations to the job logs so they
+// are shown alongside the rest of the import output.
+func (server *Server) logImportPolicyViolations(ctx context.Context, jobID uuid.UUID, violations []string) error {
+	server.Logger.Info(ctx, "template import rejected by policy",
+		slog.F("job_id", jobID),
+		slog.F("violations", violations),
+	)
+	logs := make([]*proto.Log, 0, len(violations))
+	for _, violation := range violations {
+		logs = append(logs, &proto.Log{
+			Source:    proto.LogSource_PROVISIONER_DAEMON,
+			Level:     sdkproto.LogLevel_ERROR,
+			Stage:     "Checking template policies",
+			CreatedAt: time.Now().UnixMilli(),
+			Output:    violation,
+		})
+	}
+	_, err := server.UpdateJob(ctx, &proto.UpdateJobRequest{
+		JobId: jobID.String(),
+		Logs:  logs,
+	})
+	return err
+}
+
+// DaemonStatus returns the status of a daemon based on its last heartbeat.
+// Daemons older than the server never send heartbeats, so their status is
+// unknown rather than offline.
+func DaemonStatus(daemon database.ProvisionerDaemon, now time.Time) codersdk.ProvisionerDaemonStatus {
+	if !daemon.LastSeenAt.Valid {
+		return codersdk.ProvisionerDaemonUnknown
+	}
+	if now.Sub(daemon.LastSeenAt.Time) > DaemonHeartbeatTimeout {
+		return codersdk.ProvisionerDaemonOffline
+	}
+	if daemon.CurrentJobID.Valid {
+		return codersdk.ProvisionerDaemonBusy
+	}
+	return codersdk.ProvisionerDaemonOnline
+}
+
+// DaemonMatchesJob returns whether the daemon isn't offline and could acquire
+// a job for the provisioner and tags. Like AcquireProvisionerJob, the daemon
+// must serve the provisioner and have every tag set on the job. Daemons with
+// an unknown status match, so jobs aren't failed while only daemons that don't
+// send heartbeats are connected.
+func DaemonMatchesJob(daemon database.ProvisionerDaemon, provisioner database.ProvisionerType, tags map[string]string, now time.Time) bool {
+	if DaemonStatus(daemon, now) == codersdk.ProvisionerDaemonOffline {
+		return false
+	}
+	if !slices.Contains(daemon.Provisioners, provisioner) {
+		return false
+	}
+	for key, value := range tags {
+		daemonValue, ok := daemon.Tags[key]
+		if !ok || daemonValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// HasMatchingDaemon returns whether any daemon that isn't offline could
+// acquire a job for the provisioner and tags.
+func HasMatchingDaemon(ctx context.Context, db database.Store, provisioner database.ProvisionerType, tags map[string]string, now time.Time) (bool, error) {
+	// nolint:gocritic // Users creating jobs can't necessarily read daemons.
+	daemons, err := db.GetProvisionerDaemons(dbauthz.AsSystemRestricted(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get provisioner daemons: %w", err)
+	}
+	for _, daemon := range daemons {
+		if DaemonMatchesJob(daemon, provisioner, tags, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
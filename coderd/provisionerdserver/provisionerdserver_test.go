@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/coder/coder/coderd/database/dbfake"
 	"github.com/coder/coder/coderd/database/dbgen"
 	"github.com/coder/coder/coderd/gitauth"
+	"github.com/coder/coder/coderd/importpolicy"
 	"github.com/coder/coder/coderd/provisionerdserver"
 	"github.com/coder/coder/coderd/schedule"
 	"github.com/coder/coder/coderd/telemetry"
@@ -251,6 +253,12 @@ func TestAcquireJob(t *testing.T) {
 		require.NoError(t, err)
 
 		require.JSONEq(t, string(want), string(got))
+
+		timings, err := srv.Database.GetWorkspaceBuildTimingsByBuildIDs(ctx, []uuid.UUID{build.ID})
+		require.NoError(t, err)
+		require.Len(t, timings, 1)
+		require.Equal(t, database.WorkspaceBuildStageQueued, timings[0].Stage)
+		require.True(t, timings[0].EndedAt.Valid)
 	})
 	t.Run("TemplateVersionDryRun", func(t *testing.T) {
 		t.Parallel()
@@ -477,6 +485,42 @@ func TestUpdateJob(t *testing.T) {
 
 		<-published
 	})
+	t.Run("ResourceProgress", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		job := setupJob(t, srv)
+
+		_, err := srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId: job.String(),
+			Logs: []*proto.Log{{
+				Source: proto.LogSource_PROVISIONER,
+				Level:  sdkproto.LogLevel_INFO,
+				Output: "hi",
+			}, {
+				Source: proto.LogSource_PROVISIONER,
+				Level:  sdkproto.LogLevel_INFO,
+				Output: "docker_container.dev: Creation complete after 12s",
+				ResourceProgress: &sdkproto.ResourceProgress{
+					Address:        "docker_container.dev",
+					Action:         "create",
+					Status:         sdkproto.ResourceProgress_COMPLETE,
+					ElapsedSeconds: 12,
+				},
+			}},
+		})
+		require.NoError(t, err)
+
+		logs, err := srv.Database.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+			JobID: job,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		require.Empty(t, logs[0].ResourceAddress)
+		require.Equal(t, "docker_container.dev", logs[1].ResourceAddress)
+		require.Equal(t, "create", logs[1].ResourceAction)
+		require.Equal(t, string(codersdk.ProvisionerJobResourceStatusComplete), logs[1].ResourceStatus)
+		require.EqualValues(t, 12, logs[1].ResourceElapsedSeconds)
+	})
 	t.Run("Readme", func(t *testing.T) {
 		t.Parallel()
 		srv := setup(t, false)
@@ -824,6 +868,134 @@ func TestCompleteJob(t *testing.T) {
 		require.False(t, job.Error.Valid)
 	})
 
+	t.Run("TemplateImportPolicy", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		policy, err := importpolicy.New(ctx, map[string]string{
+			"policy.rego": `package coder.template_import
+
+deny[msg] {
+	rc := input.plan.resource_changes[_]
+	not rc.change.after.tags.owner
+	msg := sprintf("%s must have an owner tag", [rc.address])
+}`,
+		})
+		require.NoError(t, err)
+		srv.ImportPolicy = policy
+
+		jobID := uuid.New()
+		version, err := srv.Database.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+			ID:    uuid.New(),
+			JobID: jobID,
+		})
+		require.NoError(t, err)
+		job, err := srv.Database.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:            jobID,
+			Provisioner:   database.ProvisionerTypeEcho,
+			Input:         []byte(`{"template_version_id": "` + version.ID.String() + `"}`),
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			Type:          database.ProvisionerJobTypeTemplateVersionImport,
+		})
+		require.NoError(t, err)
+		_, err = srv.Database.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srv.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_TemplateImport_{
+				TemplateImport: &proto.CompletedJob_TemplateImport{
+					StartResources: []*sdkproto.Resource{{
+						Name: "dev",
+						Type: "aws_instance",
+					}},
+					StartPlanJson: []byte(`{"resource_changes": [{"address": "aws_instance.dev", "change": {"after": {"tags": {}}}}]}`),
+					StopPlanJson:  []byte(`{"resource_changes": [{"address": "aws_instance.dev", "change": {"after": {"tags": {"owner": "admin"}}}}]}`),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		job, err = srv.Database.GetProvisionerJobByID(ctx, job.ID)
+		require.NoError(t, err)
+		require.True(t, job.CompletedAt.Valid)
+		require.Equal(t, "template violates import policy: start: aws_instance.dev must have an owner tag", job.Error.String)
+		require.Equal(t, provisionerdserver.ImportPolicyViolationErrorCode, job.ErrorCode.String)
+
+		logs, err := srv.Database.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+			JobID: job.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, database.LogLevelError, logs[0].Level)
+		require.Equal(t, "start: aws_instance.dev must have an owner tag", logs[0].Output)
+	})
+
+	t.Run("TemplateImportPolicyMissingPlan", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		policy, err := importpolicy.New(ctx, map[string]string{
+			"policy.rego": `package coder.template_import
+
+deny[msg] {
+	rc := input.plan.resource_changes[_]
+	rc.type == "aws_instance"
+	msg := sprintf("%s is not allowed", [rc.address])
+}`,
+		})
+		require.NoError(t, err)
+		srv.ImportPolicy = policy
+
+		jobID := uuid.New()
+		version, err := srv.Database.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+			ID:    uuid.New(),
+			JobID: jobID,
+		})
+		require.NoError(t, err)
+		job, err := srv.Database.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:            jobID,
+			Provisioner:   database.ProvisionerTypeEcho,
+			Input:         []byte(`{"template_version_id": "` + version.ID.String() + `"}`),
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			Type:          database.ProvisionerJobTypeTemplateVersionImport,
+		})
+		require.NoError(t, err)
+		_, err = srv.Database.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srv.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		// Older provisioner daemons don't report plans, which must not pass
+		// the policy.
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_TemplateImport_{
+				TemplateImport: &proto.CompletedJob_TemplateImport{
+					StartResources: []*sdkproto.Resource{{
+						Name: "dev",
+						Type: "aws_instance",
+					}},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		job, err = srv.Database.GetProvisionerJobByID(ctx, job.ID)
+		require.NoError(t, err)
+		require.True(t, job.CompletedAt.Valid)
+		require.Equal(t, "template violates 2 import policy rules, see the job logs for details", job.Error.String)
+		require.Equal(t, provisionerdserver.ImportPolicyViolationErrorCode, job.ErrorCode.String)
+	})
+
 	t.Run("WorkspaceBuild", func(t *testing.T) {
 		t.Parallel()
 
@@ -1036,6 +1208,93 @@ func TestCompleteJob(t *testing.T) {
 			})
 		}
 	})
+	t.Run("WorkspaceBuildTimings", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		registry := prometheus.NewRegistry()
+		srv.BuildStageMetrics = provisionerdserver.NewBuildStageMetrics(registry)
+		template := dbgen.Template(t, srv.Database, database.Template{})
+		workspace := dbgen.Workspace(t, srv.Database, database.Workspace{TemplateID: template.ID})
+		build := dbgen.WorkspaceBuild(t, srv.Database, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			Transition:  database.WorkspaceTransitionStart,
+			Reason:      database.BuildReasonInitiator,
+		})
+		job := dbgen.ProvisionerJob(t, srv.Database, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+			Input: must(json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+				WorkspaceBuildID: build.ID,
+			})),
+		})
+		_, err := srv.Database.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srv.ID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+
+		start := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_WorkspaceBuild_{
+				WorkspaceBuild: &proto.CompletedJob_WorkspaceBuild{
+					State: []byte{},
+					Timings: []*sdkproto.Timing{{
+						Stage:     "init",
+						StartedAt: start.UnixMilli(),
+						EndedAt:   start.Add(40 * time.Second).UnixMilli(),
+					}, {
+						Stage:     "apply",
+						StartedAt: start.Add(40 * time.Second).UnixMilli(),
+						EndedAt:   start.Add(time.Minute).UnixMilli(),
+					}, {
+						// Unknown stages are ignored.
+						Stage:     "refresh",
+						StartedAt: start.UnixMilli(),
+						EndedAt:   start.UnixMilli(),
+					}},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		timings, err := srv.Database.GetWorkspaceBuildTimingsByBuildIDs(ctx, []uuid.UUID{build.ID})
+		require.NoError(t, err)
+		require.Len(t, timings, 2)
+		for _, timing := range timings {
+			switch timing.Stage {
+			case database.WorkspaceBuildStageInit:
+				require.Equal(t, 40*time.Second, timing.EndedAt.Time.Sub(timing.StartedAt))
+			case database.WorkspaceBuildStageApply:
+				require.Equal(t, 20*time.Second, timing.EndedAt.Time.Sub(timing.StartedAt))
+			default:
+				t.Fatalf("unexpected stage %q", timing.Stage)
+			}
+		}
+
+		// Each stage is observed once, when the job completes.
+		metrics, err := registry.Gather()
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		observed := map[string][2]float64{}
+		for _, metric := range metrics[0].Metric {
+			labels := map[string]string{}
+			for _, label := range metric.Label {
+				labels[label.GetName()] = label.GetValue()
+			}
+			require.Equal(t, template.Name, labels["template_name"])
+			observed[labels["stage"]] = [2]float64{
+				float64(metric.Histogram.GetSampleCount()),
+				metric.Histogram.GetSampleSum(),
+			}
+		}
+		require.Equal(t, map[string][2]float64{
+			string(database.WorkspaceBuildStageInit):  {1, 40},
+			string(database.WorkspaceBuildStageApply): {1, 20},
+		}, observed)
+	})
 	t.Run("TemplateDryRun", func(t *testing.T) {
 		t.Parallel()
 		srv := setup(t, false)
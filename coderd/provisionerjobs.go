@@ -297,7 +297,7 @@ func convertProvisionerJobLogs(provisionerJobLogs []database.ProvisionerJobLog)
 }
 
 func convertProvisionerJobLog(provisionerJobLog database.ProvisionerJobLog) codersdk.ProvisionerJobLog {
-	return codersdk.ProvisionerJobLog{
+	log := codersdk.ProvisionerJobLog{
 		ID:        provisionerJobLog.ID,
 		CreatedAt: provisionerJobLog.CreatedAt,
 		Source:    codersdk.LogSource(provisionerJobLog.Source),
@@ -305,16 +305,26 @@ func convertProvisionerJobLog(provisionerJobLog database.ProvisionerJobLog) code
 		Stage:     provisionerJobLog.Stage,
 		Output:    provisionerJobLog.Output,
 	}
+	if provisionerJobLog.ResourceAddress != "" {
+		log.ResourceProgress = &codersdk.ProvisionerJobResourceProgress{
+			Address:        provisionerJobLog.ResourceAddress,
+			Action:         provisionerJobLog.ResourceAction,
+			Status:         codersdk.ProvisionerJobResourceStatus(provisionerJobLog.ResourceStatus),
+			ElapsedSeconds: int64(provisionerJobLog.ResourceElapsedSeconds),
+		}
+	}
+	return log
 }
 
 func convertProvisionerJob(provisionerJob database.ProvisionerJob) codersdk.ProvisionerJob {
 	job := codersdk.ProvisionerJob{
-		ID:        provisionerJob.ID,
-		CreatedAt: provisionerJob.CreatedAt,
-		Error:     provisionerJob.Error.String,
-		ErrorCode: codersdk.JobErrorCode(provisionerJob.ErrorCode.String),
-		FileID:    provisionerJob.FileID,
-		Tags:      provisionerJob.Tags,
+		ID:                provisionerJob.ID,
+		CreatedAt:         provisionerJob.CreatedAt,
+		Error:             provisionerJob.Error.String,
+		ErrorCode:         codersdk.JobErrorCode(provisionerJob.ErrorCode.String),
+		FileID:            provisionerJob.FileID,
+		Tags:              provisionerJob.Tags,
+		HasMatchingDaemon: provisionerJob.HasMatchingDaemon,
 	}
 	// Applying values optional to the struct.
 	if provisionerJob.StartedAt.Valid {
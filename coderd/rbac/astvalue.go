@@ -163,6 +163,16 @@ func (s Scope) regoValue() ast.Value {
 		ast.StringTerm("allow_list"),
 		ast.NewTerm(regoSliceString(s.AllowIDList...)),
 	)
+	if len(s.AllowTypeIDList) > 0 {
+		typeList := ast.NewObject()
+		for k, ids := range s.AllowTypeIDList {
+			typeList.Insert(ast.StringTerm(k), ast.NewTerm(regoSliceString(ids...)))
+		}
+		r.Insert(
+			ast.StringTerm("allow_type_list"),
+			ast.NewTerm(typeList),
+		)
+	}
 	return r
 }
 
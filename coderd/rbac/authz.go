@@ -27,6 +27,12 @@ const (
 	ActionRead   Action = "read"
 	ActionUpdate Action = "update"
 	ActionDelete Action = "delete"
+
+	// ActionStart and ActionStop only apply to workspaces. They allow running
+	// start and stop builds without the other changes ActionUpdate allows,
+	// like renaming the workspace or changing its schedule.
+	ActionStart Action = "start"
+	ActionStop  Action = "stop"
 )
 
 // AllActions is a helper function to return all the possible actions types.
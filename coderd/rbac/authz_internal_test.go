@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/rbac/regosql"
 	"github.com/coder/coder/testutil"
 )
 
@@ -941,6 +942,157 @@ func TestAuthorizeScope(t *testing.T) {
 			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), actions: []Action{ActionCreate}, allow: false},
 		},
 	)
+
+	// This scope can read workspaces and push to a single template.
+	templateID := uuid.New()
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleOwner())),
+			must(RoleByName(RoleMember())),
+			must(RoleByName(RoleOrgMember(defOrg))),
+		},
+		Scope: CustomScope{
+			"workspace:read",
+			"template:read:" + templateID.String(),
+			"template:update:" + templateID.String(),
+		},
+	}
+
+	testAuthorize(t, "CustomScope", user,
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionCreate, ActionUpdate, ActionDelete}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner("not-me")},
+			{resource: ResourceAuditLog},
+		}),
+		// Templates other than the allowed one are denied.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionRead, ActionUpdate, ActionDelete}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceTemplate.WithID(uuid.New()).InOrg(defOrg)},
+			{resource: ResourceTemplate.InOrg(defOrg)},
+		}),
+		[]authTestCase{
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner("not-me"), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []Action{ActionRead, ActionUpdate}, allow: true},
+			{resource: ResourceTemplate.WithID(templateID).InOrg(defOrg), actions: []Action{ActionCreate, ActionDelete}, allow: false},
+		},
+	)
+
+	// This scope can only start and stop a single workspace.
+	workspaceID = uuid.New()
+	user = Subject{
+		ID: "me",
+		Roles: Roles{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(RoleOrgMember(defOrg))),
+		},
+		Scope: CustomScope{
+			"workspace:start:" + workspaceID.String(),
+			"workspace:stop:" + workspaceID.String(),
+		},
+	}
+
+	testAuthorize(t, "WorkspaceStartStopScope", user, []authTestCase{
+		{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionStart, ActionStop}, allow: true},
+		{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionCreate, ActionRead, ActionUpdate, ActionDelete}, allow: false},
+		{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionStart, ActionStop}, allow: false},
+		// The roles must allow the action too.
+		{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner("not-me"), actions: []Action{ActionStart, ActionStop}, allow: false},
+	})
+}
+
+func TestCustomScope(t *testing.T) {
+	t.Parallel()
+
+	templateID := uuid.NewString()
+	for _, tc := range []struct {
+		Name  string
+		Scope CustomScope
+		Error string
+	}{
+		{Name: "Empty", Scope: CustomScope{}, Error: "no entries"},
+		{Name: "MissingAction", Scope: CustomScope{"workspace"}, Error: "must be of the form"},
+		{Name: "UnknownResource", Scope: CustomScope{"system:read"}, Error: "unknown resource type"},
+		{Name: "UnknownAction", Scope: CustomScope{"workspace:restart"}, Error: "unknown action"},
+		{Name: "TemplateStart", Scope: CustomScope{"template:start"}, Error: `only applies to "workspace"`},
+		{Name: "WorkspaceStart", Scope: CustomScope{"workspace:start"}},
+		{Name: "WorkspaceStop", Scope: CustomScope{"workspace:stop:" + templateID}},
+		{Name: "WildcardResourceID", Scope: CustomScope{"*:read:" + templateID}, Error: "cannot restrict"},
+		{Name: "MixedResourceIDs", Scope: CustomScope{"template:read", "template:update:" + templateID}, Error: "either all or none"},
+		{Name: "Wildcards", Scope: CustomScope{"*:read", "workspace:*"}},
+		{Name: "ResourceID", Scope: CustomScope{"template:read:" + templateID, "template:update:" + templateID}},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			scope, err := tc.Scope.Expand()
+			if tc.Error != "" {
+				require.ErrorContains(t, err, tc.Error)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{WildcardSymbol}, scope.AllowIDList)
+		})
+	}
+}
+
+// TestCustomScopeSQLFilter ensures resource IDs in custom scopes end up in the
+// SQL filters of prepared authorizers, which are used to list resources.
+func TestCustomScopeSQLFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	defOrg := uuid.New()
+	allowedIDs := []string{uuid.NewString(), uuid.NewString()}
+	authorizer := NewAuthorizer(prometheus.NewRegistry())
+	subject := func(scope CustomScope) Subject {
+		return Subject{
+			ID: "me",
+			Roles: Roles{
+				must(RoleByName(RoleMember())),
+				must(RoleByName(RoleOrgAdmin(defOrg))),
+			},
+			Scope: scope,
+		}
+	}
+
+	prepared, err := authorizer.Prepare(ctx, subject(CustomScope{
+		"template:read:" + allowedIDs[0],
+		"template:read:" + allowedIDs[1],
+	}), ActionRead, ResourceTemplate.Type)
+	require.NoError(t, err)
+	filter, err := prepared.CompileToSQL(ctx, regosql.ConvertConfig{
+		VariableConverter: regosql.TemplateConverter(),
+	})
+	require.NoError(t, err)
+	for _, id := range allowedIDs {
+		require.Contains(t, filter, id)
+	}
+	for _, id := range allowedIDs {
+		require.NoError(t, prepared.Authorize(ctx, ResourceTemplate.WithID(uuid.MustParse(id)).InOrg(defOrg)))
+	}
+	require.Error(t, prepared.Authorize(ctx, ResourceTemplate.WithID(uuid.New()).InOrg(defOrg)))
+
+	// Resource types the scope doesn't restrict aren't filtered by ID.
+	prepared, err = authorizer.Prepare(ctx, subject(CustomScope{
+		"template:read",
+		"workspace:read:" + allowedIDs[0],
+	}), ActionRead, ResourceTemplate.Type)
+	require.NoError(t, err)
+	filter, err = prepared.CompileToSQL(ctx, regosql.ConvertConfig{
+		VariableConverter: regosql.TemplateConverter(),
+	})
+	require.NoError(t, err)
+	require.NotContains(t, filter, allowedIDs[0])
+	require.NoError(t, prepared.Authorize(ctx, ResourceTemplate.WithID(uuid.New()).InOrg(defOrg)))
 }
 
 // cases applies a given function to all test cases. This makes generalities easier to create.
@@ -160,6 +160,25 @@ var (
 		Type: "debug_info",
 	}
 
+	// ResourceOAuth2ProviderApp is an app registered by admins that may obtain
+	// API keys of users through the OAuth2 authorization code flow.
+	//	create/update/delete = manage the app
+	//	read = view the app, which every user does before authorizing it
+	ResourceOAuth2ProviderApp = Object{
+		Type: "oauth2_app",
+	}
+
+	// ResourceOAuth2ProviderAppSecret is a client secret of an OAuth2 app.
+	ResourceOAuth2ProviderAppSecret = Object{
+		Type: "oauth2_app_secret",
+	}
+
+	// ResourceOAuth2ProviderAppCodeToken is an authorization code or a refresh
+	// token that a user granted to an OAuth2 app. It is owned by the user.
+	ResourceOAuth2ProviderAppCodeToken = Object{
+		Type: "oauth2_app_code_token",
+	}
+
 	// ResourceSystem is a pseudo-resource only used for system-level actions.
 	ResourceSystem = Object{
 		Type: "system",
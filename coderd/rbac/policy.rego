@@ -148,9 +148,18 @@ user_allow(roles) := num {
 }
 
 # Scope allow_list is a list of resource IDs explicitly allowed by the scope.
-# If the list is '*', then all resources are allowed.
+# If the list is '*', then all resources are allowed, except for the resource
+# types the allow_type_list restricts to specific IDs.
 scope_allow_list {
 	"*" in input.subject.scope.allow_list
+	not input.subject.scope.allow_type_list[input.object.type]
+}
+
+scope_allow_list {
+	"*" in input.subject.scope.allow_list
+	# The object type is always known, so partial compilations only need the
+	# object.id if the type is restricted.
+	input.object.id in input.subject.scope.allow_type_list[input.object.type]
 }
 
 scope_allow_list {
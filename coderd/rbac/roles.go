@@ -95,6 +95,8 @@ var builtInRoles = map[string]func(orgID string) Role{
 				ResourceRoleAssignment.Type: {ActionRead},
 				// All users can see the provisioner daemons.
 				ResourceProvisionerDaemon.Type: {ActionRead},
+				// All users can see the OAuth2 apps they may authorize.
+				ResourceOAuth2ProviderApp.Type: {ActionRead},
 			}),
 			Org: map[string][]Permission{},
 			User: Permissions(map[string][]Action{
@@ -218,10 +220,13 @@ var builtInRoles = map[string]func(orgID string) Role{
 //	map[actor_role][assign_role]<can_assign>
 var assignRoles = map[string]map[string]bool{
 	"system": {
-		owner:     true,
-		member:    true,
-		orgAdmin:  true,
-		orgMember: true,
+		owner:         true,
+		auditor:       true,
+		member:        true,
+		orgAdmin:      true,
+		orgMember:     true,
+		templateAdmin: true,
+		userAdmin:     true,
 	},
 	owner: {
 		owner:         true,
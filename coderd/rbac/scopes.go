@@ -2,6 +2,7 @@ package rbac
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -33,6 +34,10 @@ func (name ScopeName) Name() string {
 type Scope struct {
 	Role
 	AllowIDList []string `json:"allow_list"`
+	// AllowTypeIDList restricts the listed resource types to the given
+	// resource IDs. Resource types that are not listed are unaffected. It is
+	// only applied if the AllowIDList is a wildcard.
+	AllowTypeIDList map[string][]string `json:"allow_type_list,omitempty"`
 }
 
 func (s Scope) Expand() (Scope, error) {
@@ -69,6 +74,9 @@ func WorkspaceAgentScope(workspaceID, ownerID uuid.UUID) Scope {
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
+	// ScopeCustom is the name of scopes composed of fine-grained entries. It
+	// cannot be expanded by name, see CustomScope.
+	ScopeCustom ScopeName = "custom"
 )
 
 // TODO: Support passing in scopeID list for allowlisting resources.
@@ -102,6 +110,145 @@ var builtinScopes = map[ScopeName]Scope{
 	},
 }
 
+// CustomScope is a scope composed of fine-grained entries of the form
+// "<resource type>:<action>[:<resource id>]", eg: "workspace:read" or
+// "template:update:<template id>". The resource type and action may be a
+// wildcard. A resource ID restricts every action the scope grants on that
+// resource type to the listed resources.
+//
+// Workspaces have two more actions: "workspace:start" and "workspace:stop"
+// only allow start and stop builds of the workspace, while "workspace:update"
+// allows them along with every other change. Deleting a workspace requires
+// "workspace:delete".
+type CustomScope []string
+
+func (s CustomScope) Expand() (Scope, error) {
+	perms := map[string][]Action{}
+	ids := map[string][]string{}
+	unrestricted := map[string]bool{}
+	for _, entry := range s {
+		e, err := ParseScopeEntry(entry)
+		if err != nil {
+			return Scope{}, err
+		}
+		perms[e.ResourceType] = append(perms[e.ResourceType], e.Action)
+		if e.ResourceID == "" {
+			unrestricted[e.ResourceType] = true
+		} else {
+			ids[e.ResourceType] = append(ids[e.ResourceType], e.ResourceID)
+		}
+	}
+	if len(perms) == 0 {
+		return Scope{}, xerrors.New("custom scope has no entries")
+	}
+	for resourceType := range ids {
+		if unrestricted[resourceType] {
+			return Scope{}, xerrors.Errorf("scope entries for %q must either all or none name a resource ID", resourceType)
+		}
+	}
+
+	return Scope{
+		Role: Role{
+			Name:        fmt.Sprintf("Scope_%s", ScopeCustom),
+			DisplayName: "Custom: " + strings.Join(s, ", "),
+			Site:        Permissions(perms),
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		},
+		AllowIDList:     []string{WildcardSymbol},
+		AllowTypeIDList: ids,
+	}, nil
+}
+
+func (s CustomScope) Name() string {
+	return fmt.Sprintf("%s(%s)", ScopeCustom, strings.Join(s, ","))
+}
+
+// ScopeEntry is a single entry of a CustomScope.
+type ScopeEntry struct {
+	ResourceType string
+	Action       Action
+	// ResourceID is optional.
+	ResourceID string
+}
+
+// scopeResources are the resource types a custom scope may grant access to.
+var scopeResources = []Object{
+	ResourceWildcard,
+	ResourceWorkspace,
+	ResourceWorkspaceExecution,
+	ResourceWorkspaceApplicationConnect,
+	ResourceAuditLog,
+	ResourceTemplate,
+	ResourceGroup,
+	ResourceFile,
+	ResourceProvisionerDaemon,
+	ResourceOrganization,
+	ResourceRoleAssignment,
+	ResourceOrgRoleAssignment,
+	ResourceAPIKey,
+	ResourceUser,
+	ResourceUserData,
+	ResourceOrganizationMember,
+	ResourceLicense,
+	ResourceDeploymentValues,
+	ResourceDeploymentStats,
+	ResourceReplicas,
+	ResourceDebugInfo,
+	ResourceOAuth2ProviderApp,
+	ResourceOAuth2ProviderAppSecret,
+}
+
+// ParseScopeEntry parses an entry of a CustomScope.
+func ParseScopeEntry(entry string) (ScopeEntry, error) {
+	parts := strings.Split(entry, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return ScopeEntry{}, xerrors.Errorf("scope entry %q must be of the form <resource type>:<action>[:<resource id>]", entry)
+	}
+	e := ScopeEntry{
+		ResourceType: parts[0],
+		Action:       Action(parts[1]),
+	}
+	if len(parts) == 3 {
+		e.ResourceID = parts[2]
+		if e.ResourceID == "" || e.ResourceID == WildcardSymbol {
+			return ScopeEntry{}, xerrors.Errorf("scope entry %q has an invalid resource ID", entry)
+		}
+		if e.ResourceType == WildcardSymbol {
+			return ScopeEntry{}, xerrors.Errorf("scope entry %q cannot restrict all resource types to a resource ID", entry)
+		}
+	}
+
+	validResource := false
+	for _, r := range scopeResources {
+		if r.Type == e.ResourceType {
+			validResource = true
+			break
+		}
+	}
+	if !validResource {
+		return ScopeEntry{}, xerrors.Errorf("scope entry %q has an unknown resource type %q", entry, e.ResourceType)
+	}
+
+	validAction := e.Action == WildcardSymbol
+	for _, a := range AllActions() {
+		if a == e.Action {
+			validAction = true
+			break
+		}
+	}
+	if e.Action == ActionStart || e.Action == ActionStop {
+		if e.ResourceType != ResourceWorkspace.Type {
+			return ScopeEntry{}, xerrors.Errorf("scope entry %q has an action %q that only applies to %q", entry, e.Action, ResourceWorkspace.Type)
+		}
+		validAction = true
+	}
+	if !validAction {
+		return ScopeEntry{}, xerrors.Errorf("scope entry %q has an unknown action %q", entry, e.Action)
+	}
+	return e, nil
+}
+
 func ExpandScope(scope ScopeName) (Scope, error) {
 	role, ok := builtinScopes[scope]
 	if !ok {
@@ -0,0 +1,457 @@
+// Package saml implements a SAML 2.0 service provider, which lets users log in
+// to Coder with identity providers that don't support OpenID Connect.
+//
+// Only the HTTP-Redirect binding for authentication requests and the HTTP-POST
+// binding for responses are supported. Responses must be signed by the
+// identity provider, and encrypted assertions are not supported.
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cryptorand"
+)
+
+const (
+	NamespaceProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	NamespaceAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	NamespaceMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	BindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	BindingHTTPPOST     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+
+	StatusSuccess             = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	SubjectConfirmationBearer = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+	NameIDFormatUnspecified   = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+
+	// maxClockSkew is the difference between the clocks of the identity
+	// provider and Coder that is tolerated when validating assertions.
+	maxClockSkew = 3 * time.Minute
+)
+
+// IdentityProvider is the configuration of an identity provider, usually
+// read from its metadata with ParseMetadata.
+type IdentityProvider struct {
+	EntityID string
+	// SSOURL is the location of the single sign-on service that accepts
+	// authentication requests with the HTTP-Redirect binding.
+	SSOURL string
+	// Certificates are the certificates the identity provider signs responses
+	// and assertions with.
+	Certificates []*x509.Certificate
+}
+
+// ServiceProvider is Coder's side of the SAML configuration.
+type ServiceProvider struct {
+	// EntityID identifies Coder to the identity provider. This is usually the
+	// URL of the metadata endpoint.
+	EntityID string
+	// ACSURL is the assertion consumer service the identity provider posts
+	// responses to.
+	ACSURL           string
+	IdentityProvider IdentityProvider
+	// Now is used to validate the conditions of assertions. Defaults to
+	// time.Now.
+	Now func() time.Time
+}
+
+// Assertion contains the details of a user that were asserted by the identity
+// provider.
+type Assertion struct {
+	// Issuer is the entity ID of the identity provider.
+	Issuer string
+	// NameID identifies the user at the identity provider.
+	NameID string
+	// Attributes are the values of the attributes of the user by name.
+	// Attributes with a friendly name can also be looked up by it.
+	Attributes map[string][]string
+}
+
+// Attribute returns the first value of an attribute, or the empty string if
+// the assertion doesn't contain the attribute.
+func (a Assertion) Attribute(name string) string {
+	values := a.Attributes[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (sp *ServiceProvider) now() time.Time {
+	if sp.Now != nil {
+		return sp.Now()
+	}
+	return time.Now()
+}
+
+// NewRequestID generates the ID of an authentication request. The ID must
+// start with a letter or underscore to be a valid XML ID.
+func NewRequestID() (string, error) {
+	id, err := cryptorand.HexString(40)
+	if err != nil {
+		return "", xerrors.Errorf("generate random string: %w", err)
+	}
+	return "_" + id, nil
+}
+
+// Metadata returns the metadata of the service provider, which is uploaded to
+// the identity provider to register Coder.
+func (sp *ServiceProvider) Metadata() ([]byte, error) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	entity := doc.CreateElement("md:EntityDescriptor")
+	entity.CreateAttr("xmlns:md", NamespaceMetadata)
+	entity.CreateAttr("entityID", sp.EntityID)
+
+	descriptor := entity.CreateElement("md:SPSSODescriptor")
+	descriptor.CreateAttr("AuthnRequestsSigned", "false")
+	descriptor.CreateAttr("WantAssertionsSigned", "true")
+	descriptor.CreateAttr("protocolSupportEnumeration", NamespaceProtocol)
+	descriptor.CreateElement("md:NameIDFormat").SetText(NameIDFormatUnspecified)
+	acs := descriptor.CreateElement("md:AssertionConsumerService")
+	acs.CreateAttr("Binding", BindingHTTPPOST)
+	acs.CreateAttr("Location", sp.ACSURL)
+	acs.CreateAttr("index", "1")
+
+	doc.Indent(2)
+	return doc.WriteToBytes()
+}
+
+// AuthnRequestURL returns the URL of the identity provider that the user is
+// redirected to in order to authenticate. The relay state is passed back
+// unchanged in the response.
+func (sp *ServiceProvider) AuthnRequestURL(requestID, relayState string) (string, error) {
+	doc := etree.NewDocument()
+	req := doc.CreateElement("samlp:AuthnRequest")
+	req.CreateAttr("xmlns:samlp", NamespaceProtocol)
+	req.CreateAttr("xmlns:saml", NamespaceAssertion)
+	req.CreateAttr("ID", requestID)
+	req.CreateAttr("Version", "2.0")
+	req.CreateAttr("IssueInstant", sp.now().UTC().Format(time.RFC3339))
+	req.CreateAttr("Destination", sp.IdentityProvider.SSOURL)
+	req.CreateAttr("AssertionConsumerServiceURL", sp.ACSURL)
+	req.CreateAttr("ProtocolBinding", BindingHTTPPOST)
+	req.CreateElement("saml:Issuer").SetText(sp.EntityID)
+	policy := req.CreateElement("samlp:NameIDPolicy")
+	policy.CreateAttr("Format", NameIDFormatUnspecified)
+	policy.CreateAttr("AllowCreate", "true")
+
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		return "", xerrors.Errorf("write request: %w", err)
+	}
+	var compressed bytes.Buffer
+	writer, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+	if err != nil {
+		return "", xerrors.Errorf("create flate writer: %w", err)
+	}
+	_, err = writer.Write(raw)
+	if err != nil {
+		return "", xerrors.Errorf("compress request: %w", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", xerrors.Errorf("close flate writer: %w", err)
+	}
+
+	ssoURL, err := url.Parse(sp.IdentityProvider.SSOURL)
+	if err != nil {
+		return "", xerrors.Errorf("parse sso url: %w", err)
+	}
+	query := ssoURL.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	if relayState != "" {
+		query.Set("RelayState", relayState)
+	}
+	ssoURL.RawQuery = query.Encode()
+	return ssoURL.String(), nil
+}
+
+// ParseResponse validates a base64 encoded response that was posted to the
+// assertion consumer service in reply to the authentication request with the
+// given ID, and returns its assertion.
+func (sp *ServiceProvider) ParseResponse(encoded, requestID string) (*Assertion, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, xerrors.Errorf("decode response: %w", err)
+	}
+	doc := etree.NewDocument()
+	err = doc.ReadFromBytes(raw)
+	if err != nil {
+		return nil, xerrors.Errorf("parse response: %w", err)
+	}
+	el := doc.Root()
+	if el == nil || el.Tag != "Response" || el.NamespaceURI() != NamespaceProtocol {
+		return nil, xerrors.New("not a SAML response")
+	}
+
+	now := sp.now()
+	validation := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{
+		Roots: sp.IdentityProvider.Certificates,
+	})
+	validation.Clock = dsig.NewFakeClockAt(now)
+
+	// Identity providers sign the response, the assertion or both. Only the
+	// elements returned by the validation are trusted from here on.
+	responseSigned := false
+	validated, err := validation.Validate(el)
+	if err == nil {
+		el = validated
+		responseSigned = true
+	} else if !xerrors.Is(err, dsig.ErrMissingSignature) {
+		return nil, xerrors.Errorf("validate response signature: %w", err)
+	}
+
+	var response xmlResponse
+	err = unmarshalElement(el, &response)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal response: %w", err)
+	}
+	if response.Status.StatusCode.Value != StatusSuccess {
+		if response.Status.StatusMessage != "" {
+			return nil, xerrors.Errorf("identity provider returned status %q: %s", response.Status.StatusCode.Value, response.Status.StatusMessage)
+		}
+		return nil, xerrors.Errorf("identity provider returned status %q", response.Status.StatusCode.Value)
+	}
+	if response.Destination != "" && response.Destination != sp.ACSURL {
+		return nil, xerrors.Errorf("response destination %q doesn't match %q", response.Destination, sp.ACSURL)
+	}
+	if response.InResponseTo != requestID {
+		return nil, xerrors.Errorf("response is not in response to request %q", requestID)
+	}
+
+	var assertionEl *etree.Element
+	err = etreeutils.NSFindChildrenIterateCtx(etreeutils.NewDefaultNSContext(), el, NamespaceAssertion, "EncryptedAssertion", func(etreeutils.NSContext, *etree.Element) error {
+		return xerrors.New("encrypted assertions are not supported")
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = etreeutils.NSFindChildrenIterateCtx(etreeutils.NewDefaultNSContext(), el, NamespaceAssertion, "Assertion", func(ctx etreeutils.NSContext, child *etree.Element) error {
+		if assertionEl != nil {
+			return xerrors.New("response contains more than one assertion")
+		}
+		// Copy the namespaces declared by the response into the assertion, so
+		// it can be validated on its own.
+		detached, err := etreeutils.NSDetatch(ctx, child)
+		if err != nil {
+			return xerrors.Errorf("detach assertion: %w", err)
+		}
+		assertionEl = detached
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if assertionEl == nil {
+		return nil, xerrors.New("response doesn't contain an assertion")
+	}
+
+	validated, err = validation.Validate(assertionEl)
+	if err == nil {
+		assertionEl = validated
+	} else if !xerrors.Is(err, dsig.ErrMissingSignature) {
+		return nil, xerrors.Errorf("validate assertion signature: %w", err)
+	} else if !responseSigned {
+		return nil, xerrors.New("neither the response nor the assertion is signed")
+	}
+
+	var assertion xmlAssertion
+	err = unmarshalElement(assertionEl, &assertion)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal assertion: %w", err)
+	}
+	err = sp.validateAssertion(assertion, requestID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string][]string{}
+	for _, attribute := range assertion.Attributes {
+		values := make([]string, 0, len(attribute.Values))
+		for _, value := range attribute.Values {
+			values = append(values, strings.TrimSpace(value))
+		}
+		attributes[attribute.Name] = append(attributes[attribute.Name], values...)
+		if attribute.FriendlyName != "" && attribute.FriendlyName != attribute.Name {
+			attributes[attribute.FriendlyName] = append(attributes[attribute.FriendlyName], values...)
+		}
+	}
+	return &Assertion{
+		Issuer:     strings.TrimSpace(assertion.Issuer),
+		NameID:     strings.TrimSpace(assertion.Subject.NameID),
+		Attributes: attributes,
+	}, nil
+}
+
+func (sp *ServiceProvider) validateAssertion(assertion xmlAssertion, requestID string, now time.Time) error {
+	issuer := strings.TrimSpace(assertion.Issuer)
+	if sp.IdentityProvider.EntityID != "" && issuer != sp.IdentityProvider.EntityID {
+		return xerrors.Errorf("assertion issuer %q doesn't match %q", issuer, sp.IdentityProvider.EntityID)
+	}
+	if strings.TrimSpace(assertion.Subject.NameID) == "" {
+		return xerrors.New("assertion doesn't contain a name ID")
+	}
+
+	confirmed := false
+	for _, confirmation := range assertion.Subject.SubjectConfirmations {
+		if confirmation.Method != SubjectConfirmationBearer {
+			continue
+		}
+		data := confirmation.Data
+		if data.Recipient != sp.ACSURL {
+			continue
+		}
+		if data.InResponseTo != requestID {
+			continue
+		}
+		if data.NotOnOrAfter.IsZero() || !now.Before(data.NotOnOrAfter.Add(maxClockSkew)) {
+			continue
+		}
+		confirmed = true
+		break
+	}
+	if !confirmed {
+		return xerrors.New("assertion doesn't contain a valid bearer subject confirmation")
+	}
+
+	conditions := assertion.Conditions
+	if !conditions.NotBefore.IsZero() && now.Add(maxClockSkew).Before(conditions.NotBefore) {
+		return xerrors.Errorf("assertion is not valid before %s", conditions.NotBefore)
+	}
+	if !conditions.NotOnOrAfter.IsZero() && !now.Before(conditions.NotOnOrAfter.Add(maxClockSkew)) {
+		return xerrors.Errorf("assertion expired at %s", conditions.NotOnOrAfter)
+	}
+	audience := false
+	for _, a := range conditions.Audiences {
+		if strings.TrimSpace(a) == sp.EntityID {
+			audience = true
+			break
+		}
+	}
+	if !audience {
+		return xerrors.Errorf("assertion is not intended for audience %q", sp.EntityID)
+	}
+	return nil
+}
+
+// ParseMetadata parses the metadata of an identity provider.
+func ParseMetadata(data []byte) (IdentityProvider, error) {
+	var entity xmlEntityDescriptor
+	err := xml.Unmarshal(data, &entity)
+	if err != nil {
+		return IdentityProvider{}, xerrors.Errorf("unmarshal metadata: %w", err)
+	}
+	if len(entity.IDPSSODescriptors) == 0 {
+		return IdentityProvider{}, xerrors.New("metadata doesn't contain an identity provider")
+	}
+
+	idp := IdentityProvider{
+		EntityID: entity.EntityID,
+	}
+	descriptor := entity.IDPSSODescriptors[0]
+	for _, service := range descriptor.SingleSignOnServices {
+		if service.Binding == BindingHTTPRedirect {
+			idp.SSOURL = service.Location
+			break
+		}
+	}
+	if idp.SSOURL == "" {
+		return IdentityProvider{}, xerrors.New("identity provider doesn't support the HTTP-Redirect binding")
+	}
+	for _, key := range descriptor.KeyDescriptors {
+		if key.Use != "" && key.Use != "signing" {
+			continue
+		}
+		for _, encoded := range key.Certificates {
+			raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+			if err != nil {
+				return IdentityProvider{}, xerrors.Errorf("decode certificate: %w", err)
+			}
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return IdentityProvider{}, xerrors.Errorf("parse certificate: %w", err)
+			}
+			idp.Certificates = append(idp.Certificates, cert)
+		}
+	}
+	if len(idp.Certificates) == 0 {
+		return IdentityProvider{}, xerrors.New("identity provider doesn't have a signing certificate")
+	}
+	return idp, nil
+}
+
+func unmarshalElement(el *etree.Element, v interface{}) error {
+	doc := etree.NewDocument()
+	doc.SetRoot(el.Copy())
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(raw, v)
+}
+
+type xmlResponse struct {
+	XMLName      xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol Response"`
+	Destination  string   `xml:"Destination,attr"`
+	InResponseTo string   `xml:"InResponseTo,attr"`
+	Status       struct {
+		StatusCode struct {
+			Value string `xml:"Value,attr"`
+		} `xml:"StatusCode"`
+		StatusMessage string `xml:"StatusMessage"`
+	} `xml:"Status"`
+}
+
+type xmlAssertion struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:assertion Assertion"`
+	Issuer  string   `xml:"Issuer"`
+	Subject struct {
+		NameID               string `xml:"NameID"`
+		SubjectConfirmations []struct {
+			Method string `xml:"Method,attr"`
+			Data   struct {
+				InResponseTo string    `xml:"InResponseTo,attr"`
+				NotOnOrAfter time.Time `xml:"NotOnOrAfter,attr"`
+				Recipient    string    `xml:"Recipient,attr"`
+			} `xml:"SubjectConfirmationData"`
+		} `xml:"SubjectConfirmation"`
+	} `xml:"Subject"`
+	Conditions struct {
+		NotBefore    time.Time `xml:"NotBefore,attr"`
+		NotOnOrAfter time.Time `xml:"NotOnOrAfter,attr"`
+		Audiences    []string  `xml:"AudienceRestriction>Audience"`
+	} `xml:"Conditions"`
+	Attributes []struct {
+		Name         string   `xml:"Name,attr"`
+		FriendlyName string   `xml:"FriendlyName,attr"`
+		Values       []string `xml:"AttributeValue"`
+	} `xml:"AttributeStatement>Attribute"`
+}
+
+type xmlEntityDescriptor struct {
+	XMLName           xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID          string   `xml:"entityID,attr"`
+	IDPSSODescriptors []struct {
+		KeyDescriptors []struct {
+			Use          string   `xml:"use,attr"`
+			Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+		} `xml:"KeyDescriptor"`
+		SingleSignOnServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"SingleSignOnService"`
+	} `xml:"IDPSSODescriptor"`
+}
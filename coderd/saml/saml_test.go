@@ -0,0 +1,125 @@
+package saml_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/saml"
+	"github.com/coder/coder/coderd/saml/samltest"
+)
+
+func TestParseMetadata(t *testing.T) {
+	t.Parallel()
+
+	idp := samltest.New(t)
+	parsed, err := saml.ParseMetadata(idp.Metadata(t))
+	require.NoError(t, err)
+	require.Equal(t, idp.Config(), parsed)
+
+	_, err = saml.ParseMetadata([]byte(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="sp"/>`))
+	require.ErrorContains(t, err, "doesn't contain an identity provider")
+}
+
+func TestServiceProvider(t *testing.T) {
+	t.Parallel()
+
+	const acsURL = "https://coder.com/api/v2/users/saml/acs"
+	setup := func(t *testing.T) (*samltest.IdentityProvider, *saml.ServiceProvider, samltest.Request) {
+		idp := samltest.New(t)
+		sp := &saml.ServiceProvider{
+			EntityID:         "https://coder.com/api/v2/users/saml/metadata",
+			ACSURL:           acsURL,
+			IdentityProvider: idp.Config(),
+		}
+		id, err := saml.NewRequestID()
+		require.NoError(t, err)
+		redirect, err := sp.AuthnRequestURL(id, "/workspaces")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(redirect, idp.SSOURL+"?"))
+		req := samltest.ParseRequest(t, redirect)
+		require.Equal(t, id, req.ID)
+		require.Equal(t, sp.EntityID, req.Issuer)
+		require.Equal(t, acsURL, req.ACSURL)
+		require.Equal(t, "/workspaces", req.RelayState)
+		return idp, sp, req
+	}
+
+	t.Run("SignedAssertion", func(t *testing.T) {
+		t.Parallel()
+		idp, sp, req := setup(t)
+
+		assertion, err := sp.ParseResponse(idp.Sign(t, req.RespondTo("kyle", map[string][]string{
+			"email":  {"kyle@coder.com"},
+			"groups": {"admins", "devs"},
+		})), req.ID)
+		require.NoError(t, err)
+		require.Equal(t, idp.EntityID, assertion.Issuer)
+		require.Equal(t, "kyle", assertion.NameID)
+		require.Equal(t, "kyle@coder.com", assertion.Attribute("email"))
+		require.Equal(t, []string{"admins", "devs"}, assertion.Attributes["groups"])
+		require.Empty(t, assertion.Attribute("missing"))
+	})
+
+	t.Run("SignedResponse", func(t *testing.T) {
+		t.Parallel()
+		idp, sp, req := setup(t)
+
+		res := req.RespondTo("kyle", nil)
+		res.SignResponse = true
+		assertion, err := sp.ParseResponse(idp.Sign(t, res), req.ID)
+		require.NoError(t, err)
+		require.Equal(t, "kyle", assertion.NameID)
+	})
+
+	t.Run("UntrustedCertificate", func(t *testing.T) {
+		t.Parallel()
+		_, sp, req := setup(t)
+
+		other := samltest.New(t)
+		_, err := sp.ParseResponse(other.Sign(t, req.RespondTo("kyle", nil)), req.ID)
+		require.ErrorContains(t, err, "validate assertion signature")
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		t.Parallel()
+		idp, sp, req := setup(t)
+
+		raw, err := base64.StdEncoding.DecodeString(idp.Sign(t, req.RespondTo("kyle", nil)))
+		require.NoError(t, err)
+		tampered := strings.Replace(string(raw), ">kyle<", ">admin<", 1)
+		_, err = sp.ParseResponse(base64.StdEncoding.EncodeToString([]byte(tampered)), req.ID)
+		require.ErrorContains(t, err, "validate assertion signature")
+	})
+
+	t.Run("WrongRequest", func(t *testing.T) {
+		t.Parallel()
+		idp, sp, req := setup(t)
+
+		_, err := sp.ParseResponse(idp.Sign(t, req.RespondTo("kyle", nil)), "_other")
+		require.ErrorContains(t, err, "is not in response to request")
+	})
+
+	t.Run("WrongAudience", func(t *testing.T) {
+		t.Parallel()
+		idp, sp, req := setup(t)
+
+		res := req.RespondTo("kyle", nil)
+		res.Audience = "https://other.coder.com"
+		_, err := sp.ParseResponse(idp.Sign(t, res), req.ID)
+		require.ErrorContains(t, err, "is not intended for audience")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+		idp, sp, req := setup(t)
+
+		res := req.RespondTo("kyle", nil)
+		res.NotOnOrAfter = time.Now().Add(-time.Hour)
+		_, err := sp.ParseResponse(idp.Sign(t, res), req.ID)
+		require.ErrorContains(t, err, "subject confirmation")
+	})
+}
@@ -0,0 +1,248 @@
+// Package samltest provides a SAML identity provider that stands in for a real
+// one in tests.
+package samltest
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/google/uuid"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/saml"
+)
+
+// IdentityProvider signs responses with a self-signed certificate.
+type IdentityProvider struct {
+	EntityID string
+	SSOURL   string
+
+	cert tls.Certificate
+}
+
+// New creates an identity provider with a new signing certificate.
+func New(t testing.TB) *IdentityProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	raw, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2023),
+		Subject: pkix.Name{
+			CommonName: "idp.coder.com",
+		},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(24 * time.Hour),
+	}, &x509.Certificate{
+		SerialNumber: big.NewInt(2023),
+		Subject: pkix.Name{
+			CommonName: "idp.coder.com",
+		},
+	}, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+
+	return &IdentityProvider{
+		EntityID: "https://idp.coder.com/metadata",
+		SSOURL:   "https://idp.coder.com/sso",
+		cert: tls.Certificate{
+			Certificate: [][]byte{raw},
+			PrivateKey:  key,
+			Leaf:        cert,
+		},
+	}
+}
+
+// Config returns the configuration a service provider uses to trust the
+// identity provider.
+func (idp *IdentityProvider) Config() saml.IdentityProvider {
+	return saml.IdentityProvider{
+		EntityID:     idp.EntityID,
+		SSOURL:       idp.SSOURL,
+		Certificates: []*x509.Certificate{idp.cert.Leaf},
+	}
+}
+
+// Metadata returns the metadata of the identity provider.
+func (idp *IdentityProvider) Metadata(t testing.TB) []byte {
+	t.Helper()
+
+	doc := etree.NewDocument()
+	entity := doc.CreateElement("md:EntityDescriptor")
+	entity.CreateAttr("xmlns:md", saml.NamespaceMetadata)
+	entity.CreateAttr("xmlns:ds", dsig.Namespace)
+	entity.CreateAttr("entityID", idp.EntityID)
+	descriptor := entity.CreateElement("md:IDPSSODescriptor")
+	descriptor.CreateAttr("protocolSupportEnumeration", saml.NamespaceProtocol)
+	key := descriptor.CreateElement("md:KeyDescriptor")
+	key.CreateAttr("use", "signing")
+	key.CreateElement("ds:KeyInfo").
+		CreateElement("ds:X509Data").
+		CreateElement("ds:X509Certificate").
+		SetText(base64.StdEncoding.EncodeToString(idp.cert.Certificate[0]))
+	for _, binding := range []string{saml.BindingHTTPPOST, saml.BindingHTTPRedirect} {
+		service := descriptor.CreateElement("md:SingleSignOnService")
+		service.CreateAttr("Binding", binding)
+		service.CreateAttr("Location", idp.SSOURL)
+	}
+
+	raw, err := doc.WriteToBytes()
+	require.NoError(t, err)
+	return raw
+}
+
+// Request is an authentication request that a service provider redirected a
+// user to the identity provider with.
+type Request struct {
+	ID         string
+	Issuer     string
+	ACSURL     string
+	RelayState string
+}
+
+// ParseRequest parses the authentication request in the URL a service
+// provider redirected to.
+func ParseRequest(t testing.TB, redirectURL string) Request {
+	t.Helper()
+
+	parsed, err := url.Parse(redirectURL)
+	require.NoError(t, err)
+	compressed, err := base64.StdEncoding.DecodeString(parsed.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	require.NoError(t, err)
+
+	doc := etree.NewDocument()
+	require.NoError(t, doc.ReadFromBytes(raw))
+	root := doc.Root()
+	require.Equal(t, "AuthnRequest", root.Tag)
+	req := Request{
+		ID:         root.SelectAttrValue("ID", ""),
+		ACSURL:     root.SelectAttrValue("AssertionConsumerServiceURL", ""),
+		RelayState: parsed.Query().Get("RelayState"),
+	}
+	if issuer := root.SelectElement("Issuer"); issuer != nil {
+		req.Issuer = issuer.Text()
+	}
+	return req
+}
+
+// Response describes the response the identity provider sends after a user
+// authenticated.
+type Response struct {
+	// InResponseTo is the ID of the authentication request.
+	InResponseTo string
+	// ACSURL is the assertion consumer service of the service provider.
+	ACSURL string
+	// Audience is the entity ID of the service provider.
+	Audience   string
+	NameID     string
+	Attributes map[string][]string
+	// NotOnOrAfter defaults to five minutes from now.
+	NotOnOrAfter time.Time
+	// SignResponse signs the response instead of the assertion.
+	SignResponse bool
+}
+
+// RespondTo returns a response to the request with the given assertion
+// details.
+func (req Request) RespondTo(nameID string, attributes map[string][]string) Response {
+	return Response{
+		InResponseTo: req.ID,
+		ACSURL:       req.ACSURL,
+		Audience:     req.Issuer,
+		NameID:       nameID,
+		Attributes:   attributes,
+	}
+}
+
+// Sign returns the base64 encoded response, as it is posted to the assertion
+// consumer service.
+func (idp *IdentityProvider) Sign(t testing.TB, res Response) string {
+	t.Helper()
+
+	now := time.Now().UTC()
+	notOnOrAfter := res.NotOnOrAfter
+	if notOnOrAfter.IsZero() {
+		notOnOrAfter = now.Add(5 * time.Minute)
+	}
+	signer := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(idp.cert))
+	signer.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("xmlns:saml", saml.NamespaceAssertion)
+	assertion.CreateAttr("ID", "_"+uuid.NewString())
+	assertion.CreateAttr("Version", "2.0")
+	assertion.CreateAttr("IssueInstant", now.Format(time.RFC3339))
+	assertion.CreateElement("saml:Issuer").SetText(idp.EntityID)
+	subject := assertion.CreateElement("saml:Subject")
+	nameID := subject.CreateElement("saml:NameID")
+	nameID.CreateAttr("Format", saml.NameIDFormatUnspecified)
+	nameID.SetText(res.NameID)
+	confirmation := subject.CreateElement("saml:SubjectConfirmation")
+	confirmation.CreateAttr("Method", saml.SubjectConfirmationBearer)
+	data := confirmation.CreateElement("saml:SubjectConfirmationData")
+	data.CreateAttr("InResponseTo", res.InResponseTo)
+	data.CreateAttr("NotOnOrAfter", notOnOrAfter.Format(time.RFC3339))
+	data.CreateAttr("Recipient", res.ACSURL)
+	conditions := assertion.CreateElement("saml:Conditions")
+	conditions.CreateAttr("NotBefore", now.Add(-time.Minute).Format(time.RFC3339))
+	conditions.CreateAttr("NotOnOrAfter", notOnOrAfter.Format(time.RFC3339))
+	conditions.CreateElement("saml:AudienceRestriction").
+		CreateElement("saml:Audience").
+		SetText(res.Audience)
+	statement := assertion.CreateElement("saml:AttributeStatement")
+	for name, values := range res.Attributes {
+		attribute := statement.CreateElement("saml:Attribute")
+		attribute.CreateAttr("Name", name)
+		for _, value := range values {
+			attribute.CreateElement("saml:AttributeValue").SetText(value)
+		}
+	}
+
+	if !res.SignResponse {
+		var err error
+		assertion, err = signer.SignEnveloped(assertion)
+		require.NoError(t, err)
+	}
+
+	response := etree.NewElement("samlp:Response")
+	response.CreateAttr("xmlns:samlp", saml.NamespaceProtocol)
+	response.CreateAttr("xmlns:saml", saml.NamespaceAssertion)
+	response.CreateAttr("ID", "_"+uuid.NewString())
+	response.CreateAttr("Version", "2.0")
+	response.CreateAttr("IssueInstant", now.Format(time.RFC3339))
+	response.CreateAttr("Destination", res.ACSURL)
+	response.CreateAttr("InResponseTo", res.InResponseTo)
+	response.CreateElement("saml:Issuer").SetText(idp.EntityID)
+	response.CreateElement("samlp:Status").
+		CreateElement("samlp:StatusCode").
+		CreateAttr("Value", saml.StatusSuccess)
+	response.AddChild(assertion)
+
+	if res.SignResponse {
+		var err error
+		response, err = signer.SignEnveloped(response)
+		require.NoError(t, err)
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(response)
+	raw, err := doc.WriteToBytes()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
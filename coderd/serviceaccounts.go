@@ -0,0 +1,133 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get service account
+// @ID get-service-account
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.ServiceAccount
+// @Router /users/{user}/service-account [get]
+func (api *API) serviceAccount(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	account, err := api.Database.GetServiceAccountByUserID(ctx, user.ID)
+	if errors.Is(err, sql.ErrNoRows) || dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching service account.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertServiceAccount(account))
+}
+
+// validateServiceAccountOwner checks the owner of a new service account, and
+// defaults it to the actor if no owner is set.
+func (api *API) validateServiceAccountOwner(ctx context.Context, actorID string, req *codersdk.CreateServiceAccountRequest) error {
+	if req.OwnerUserID != nil && req.OwnerGroupID != nil {
+		return xerrors.New("a service account is owned by either a user or a group")
+	}
+	if req.OwnerGroupID != nil {
+		_, err := api.Database.GetGroupByID(ctx, *req.OwnerGroupID)
+		if err != nil {
+			return xerrors.Errorf("get owner group: %w", err)
+		}
+		return nil
+	}
+	if req.OwnerUserID == nil {
+		id, err := uuid.Parse(actorID)
+		if err != nil {
+			return xerrors.Errorf("parse actor id: %w", err)
+		}
+		req.OwnerUserID = &id
+	}
+	_, err := api.Database.GetUserByID(ctx, *req.OwnerUserID)
+	if err != nil {
+		return xerrors.Errorf("get owner user: %w", err)
+	}
+	return nil
+}
+
+// serviceAccountOwnerContext returns a context that acts as the service
+// account, which lets its owners manage its tokens. ok is false if the user is
+// not a service account owned by the actor, in which case the request context
+// is returned.
+func (api *API) serviceAccountOwnerContext(r *http.Request, user database.User) (context.Context, bool) {
+	ctx := r.Context()
+	if user.LoginType != database.LoginTypeNone {
+		return ctx, false
+	}
+	actor := httpmw.UserAuthorization(r).Actor
+	// Scoped tokens of the owner do not carry over to the service account.
+	if actor.SafeScopeName() != string(rbac.ScopeAll) {
+		return ctx, false
+	}
+
+	//nolint:gocritic // The owners of service accounts are not known to RBAC.
+	systemCtx := dbauthz.AsSystemRestricted(ctx)
+	account, err := api.Database.GetServiceAccountByUserID(systemCtx, user.ID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			api.Logger.Warn(ctx, "get service account for owner check", slog.Error(err))
+		}
+		return ctx, false
+	}
+	owned := (account.OwnerUserID.Valid && account.OwnerUserID.UUID.String() == actor.ID) ||
+		(account.OwnerGroupID.Valid && slices.Contains(actor.Groups, account.OwnerGroupID.UUID.String()))
+	if !owned {
+		return ctx, false
+	}
+
+	roles, err := api.Database.GetAuthorizationUserRoles(systemCtx, user.ID)
+	if err != nil || roles.Status != database.UserStatusActive {
+		return ctx, false
+	}
+	return dbauthz.As(ctx, rbac.Subject{
+		ID:     user.ID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}), true
+}
+
+func convertServiceAccount(account database.ServiceAccount) codersdk.ServiceAccount {
+	converted := codersdk.ServiceAccount{
+		UserID:    account.UserID,
+		CreatedAt: account.CreatedAt,
+	}
+	if account.OwnerUserID.Valid {
+		converted.OwnerUserID = &account.OwnerUserID.UUID
+	}
+	if account.OwnerGroupID.Valid {
+		converted.OwnerGroupID = &account.OwnerGroupID.UUID
+	}
+	return converted
+}
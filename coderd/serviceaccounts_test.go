@@ -0,0 +1,147 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestServiceAccount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateAndAuthenticate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		user, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "ci@coder.com",
+			Username:       "ci",
+			OrganizationID: owner.OrganizationID,
+			ServiceAccount: &codersdk.CreateServiceAccountRequest{},
+		})
+		require.NoError(t, err)
+		require.True(t, user.ServiceAccount)
+
+		// The owner defaults to the creator.
+		account, err := client.ServiceAccount(ctx, user.ID.String())
+		require.NoError(t, err)
+		require.NotNil(t, account.OwnerUserID)
+		require.Equal(t, owner.UserID, *account.OwnerUserID)
+		require.Nil(t, account.OwnerGroupID)
+
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "ci@coder.com",
+			Password: "",
+		})
+		require.Error(t, err)
+
+		token, err := client.CreateToken(ctx, user.ID.String(), codersdk.CreateTokenRequest{
+			TokenName: "deploy",
+		})
+		require.NoError(t, err)
+		ci := codersdk.New(client.URL)
+		ci.SetSessionToken(token.Key)
+		me, err := ci.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, me.ID)
+		require.True(t, me.ServiceAccount)
+
+		// Service accounts cannot create sessions for themselves.
+		_, err = ci.CreateAPIKey(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Password", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "ci@coder.com",
+			Username:       "ci",
+			Password:       "SomeSecurePassword!",
+			OrganizationID: owner.OrganizationID,
+			ServiceAccount: &codersdk.CreateServiceAccountRequest{},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NotServiceAccount", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.ServiceAccount(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("OwnerManagesTokens", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		user, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "ci@coder.com",
+			Username:       "ci",
+			OrganizationID: owner.OrganizationID,
+			ServiceAccount: &codersdk.CreateServiceAccountRequest{
+				OwnerUserID: &member.ID,
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = memberClient.CreateToken(ctx, user.Username, codersdk.CreateTokenRequest{
+			TokenName: "deploy",
+		})
+		require.NoError(t, err)
+		_, err = memberClient.CreateToken(ctx, user.Username, codersdk.CreateTokenRequest{
+			TokenName: "audit",
+			Scopes:    []string{"audit_log:read"},
+		})
+		require.NoError(t, err)
+
+		tokens, err := memberClient.Tokens(ctx, user.Username, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+
+		key, err := memberClient.APIKeyByName(ctx, user.Username, "deploy")
+		require.NoError(t, err)
+		err = memberClient.DeleteAPIKey(ctx, user.Username, key.ID)
+		require.NoError(t, err)
+
+		// Users that don't own the service account cannot manage its tokens.
+		_, err = otherClient.CreateToken(ctx, user.Username, codersdk.CreateTokenRequest{
+			TokenName: "stolen",
+		})
+		require.Error(t, err)
+		_, err = otherClient.APIKeyByName(ctx, user.Username, "audit")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,155 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get template app limits
+// @ID get-template-app-limits
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateAppLimits
+// @Router /templates/{template}/app-limits [get]
+func (api *API) templateAppLimits(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	limits, err := api.Database.GetTemplateAppLimits(ctx, template.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template app limits.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateAppLimits(limits))
+}
+
+// @Summary Update template app limits
+// @ID update-template-app-limits
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpdateTemplateAppLimitsRequest true "Limits request"
+// @Success 200 {object} codersdk.TemplateAppLimits
+// @Router /templates/{template}/app-limits [put]
+func (api *API) putTemplateAppLimits(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateTemplateAppLimitsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	var validations []codersdk.ValidationError
+	for _, limit := range []struct {
+		field string
+		value int64
+	}{
+		{"app_requests_per_minute", req.AppRequestsPerMinute},
+		{"app_bandwidth_bytes_per_second", req.AppBandwidthBytesPerSecond},
+		{"workspace_requests_per_minute", req.WorkspaceRequestsPerMinute},
+		{"workspace_bandwidth_bytes_per_second", req.WorkspaceBandwidthBytesPerSecond},
+	} {
+		if limit.value < 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  limit.field,
+				Detail: "Must not be negative. Use 0 for no limit.",
+			})
+		}
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid app limits.",
+			Validations: validations,
+		})
+		return
+	}
+
+	limits, err := api.Database.UpsertTemplateAppLimits(ctx, database.UpsertTemplateAppLimitsParams{
+		TemplateID:                       template.ID,
+		AppRequestsPerMinute:             req.AppRequestsPerMinute,
+		AppBandwidthBytesPerSecond:       req.AppBandwidthBytesPerSecond,
+		WorkspaceRequestsPerMinute:       req.WorkspaceRequestsPerMinute,
+		WorkspaceBandwidthBytesPerSecond: req.WorkspaceBandwidthBytesPerSecond,
+		UpdatedAt:                        database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template app limits.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateAppLimits(limits))
+}
+
+// @Summary Delete template app limits
+// @ID delete-template-app-limits
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 204
+// @Router /templates/{template}/app-limits [delete]
+func (api *API) deleteTemplateAppLimits(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	_, err := api.Database.GetTemplateAppLimits(ctx, template.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template app limits.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.DeleteTemplateAppLimits(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template app limits.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertTemplateAppLimits(limits database.TemplateAppLimit) codersdk.TemplateAppLimits {
+	return codersdk.TemplateAppLimits{
+		TemplateID:                       limits.TemplateID,
+		AppRequestsPerMinute:             limits.AppRequestsPerMinute,
+		AppBandwidthBytesPerSecond:       limits.AppBandwidthBytesPerSecond,
+		WorkspaceRequestsPerMinute:       limits.WorkspaceRequestsPerMinute,
+		WorkspaceBandwidthBytesPerSecond: limits.WorkspaceBandwidthBytesPerSecond,
+		UpdatedAt:                        limits.UpdatedAt,
+	}
+}
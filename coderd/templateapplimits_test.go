@@ -0,0 +1,88 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestTemplateAppLimits(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		_, err := client.TemplateAppLimits(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		limits, err := client.UpdateTemplateAppLimits(ctx, template.ID, codersdk.UpdateTemplateAppLimitsRequest{
+			AppRequestsPerMinute:             600,
+			WorkspaceBandwidthBytesPerSecond: 1 << 20,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(600), limits.AppRequestsPerMinute)
+		require.Equal(t, int64(1<<20), limits.WorkspaceBandwidthBytesPerSecond)
+
+		// Updates replace every limit.
+		limits, err = client.UpdateTemplateAppLimits(ctx, template.ID, codersdk.UpdateTemplateAppLimitsRequest{
+			WorkspaceRequestsPerMinute: 1200,
+		})
+		require.NoError(t, err)
+		require.Zero(t, limits.AppRequestsPerMinute)
+		require.Equal(t, int64(1200), limits.WorkspaceRequestsPerMinute)
+
+		fetched, err := client.TemplateAppLimits(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, limits, fetched)
+
+		err = client.DeleteTemplateAppLimits(ctx, template.ID)
+		require.NoError(t, err)
+		err = client.DeleteTemplateAppLimits(ctx, template.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		_, err := client.UpdateTemplateAppLimits(ctx, template.ID, codersdk.UpdateTemplateAppLimitsRequest{
+			AppRequestsPerMinute:       -1,
+			WorkspaceRequestsPerMinute: -1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := member.UpdateTemplateAppLimits(ctx, template.ID, codersdk.UpdateTemplateAppLimitsRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = member.DeleteTemplateAppLimits(ctx, template.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,220 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/net/http/httpguts"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbtype"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisioner"
+)
+
+// @Summary Get template app routing
+// @ID get-template-app-routing
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateAppRouting
+// @Router /templates/{template}/app-routing [get]
+func (api *API) templateAppRouting(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	routing, err := api.Database.GetTemplateAppRoutingByTemplateID(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template app routing.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.TemplateAppRouting, 0, len(routing))
+	for _, appRouting := range routing {
+		converted = append(converted, convertTemplateAppRouting(appRouting))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Upsert template app routing
+// @ID upsert-template-app-routing
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param slug path string true "App slug"
+// @Param request body codersdk.UpsertTemplateAppRoutingRequest true "Routing request"
+// @Success 200 {object} codersdk.TemplateAppRouting
+// @Router /templates/{template}/app-routing/{slug} [put]
+func (api *API) putTemplateAppRouting(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	slug := chi.URLParam(r, "slug")
+	if !provisioner.AppSlugRegex.MatchString(slug) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid app slug.",
+			Detail:  fmt.Sprintf("App slugs must match the regex %q.", provisioner.AppSlugRegex.String()),
+		})
+		return
+	}
+	var req codersdk.UpsertTemplateAppRoutingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	validations := validateTemplateAppRouting(req)
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid routing rules.",
+			Validations: validations,
+		})
+		return
+	}
+
+	stripPrefix := true
+	if req.StripPrefix != nil {
+		stripPrefix = *req.StripPrefix
+	}
+	headers := dbtype.StringMap{}
+	for name, value := range req.Headers {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	allowedMethods := make([]string, 0, len(req.AllowedMethods))
+	for _, method := range req.AllowedMethods {
+		allowedMethods = append(allowedMethods, strings.ToUpper(method))
+	}
+	websocketSubprotocols := req.WebsocketSubprotocols
+	if websocketSubprotocols == nil {
+		websocketSubprotocols = []string{}
+	}
+
+	routing, err := api.Database.UpsertTemplateAppRouting(ctx, database.UpsertTemplateAppRoutingParams{
+		TemplateID:            template.ID,
+		AppSlug:               slug,
+		StripPrefix:           stripPrefix,
+		BasePathHeader:        http.CanonicalHeaderKey(req.BasePathHeader),
+		Headers:               headers,
+		AllowedMethods:        allowedMethods,
+		WebsocketSubprotocols: websocketSubprotocols,
+		UpdatedAt:             database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template app routing.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateAppRouting(routing))
+}
+
+// @Summary Delete template app routing
+// @ID delete-template-app-routing
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param slug path string true "App slug"
+// @Success 204
+// @Router /templates/{template}/app-routing/{slug} [delete]
+func (api *API) deleteTemplateAppRouting(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	params := database.GetTemplateAppRoutingParams{
+		TemplateID: template.ID,
+		AppSlug:    chi.URLParam(r, "slug"),
+	}
+	_, err := api.Database.GetTemplateAppRouting(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template app routing.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.DeleteTemplateAppRouting(ctx, database.DeleteTemplateAppRoutingParams(params))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template app routing.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// validateTemplateAppRouting checks that routing rules only contain valid
+// header names and values, methods and subprotocols.
+func validateTemplateAppRouting(req codersdk.UpsertTemplateAppRoutingRequest) []codersdk.ValidationError {
+	var validations []codersdk.ValidationError
+	if req.BasePathHeader != "" && !httpguts.ValidHeaderFieldName(req.BasePathHeader) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "base_path_header",
+			Detail: fmt.Sprintf("%q is not a valid header name.", req.BasePathHeader),
+		})
+	}
+	for name, value := range req.Headers {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "headers",
+				Detail: fmt.Sprintf("%q is not a valid header.", name),
+			})
+		}
+	}
+	// Methods and subprotocols are tokens, like header names.
+	for _, method := range req.AllowedMethods {
+		if !httpguts.ValidHeaderFieldName(method) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "allowed_methods",
+				Detail: fmt.Sprintf("%q is not a valid method.", method),
+			})
+		}
+	}
+	for _, protocol := range req.WebsocketSubprotocols {
+		if !httpguts.ValidHeaderFieldName(protocol) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "websocket_subprotocols",
+				Detail: fmt.Sprintf("%q is not a valid subprotocol.", protocol),
+			})
+		}
+	}
+	return validations
+}
+
+func convertTemplateAppRouting(routing database.TemplateAppRouting) codersdk.TemplateAppRouting {
+	return codersdk.TemplateAppRouting{
+		TemplateID:            routing.TemplateID,
+		AppSlug:               routing.AppSlug,
+		StripPrefix:           routing.StripPrefix,
+		BasePathHeader:        routing.BasePathHeader,
+		Headers:               routing.Headers,
+		AllowedMethods:        routing.AllowedMethods,
+		WebsocketSubprotocols: routing.WebsocketSubprotocols,
+		UpdatedAt:             routing.UpdatedAt,
+	}
+}
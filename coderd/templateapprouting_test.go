@@ -0,0 +1,89 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestTemplateAppRouting(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		routing, err := client.UpsertTemplateAppRouting(ctx, template.ID, "jupyter", codersdk.UpsertTemplateAppRoutingRequest{
+			BasePathHeader: "x-forwarded-prefix",
+			AllowedMethods: []string{"get", "post"},
+		})
+		require.NoError(t, err)
+		// Apps are proxied like apps without routing rules unless set.
+		require.True(t, routing.StripPrefix)
+		require.Equal(t, "X-Forwarded-Prefix", routing.BasePathHeader)
+		require.Equal(t, []string{http.MethodGet, http.MethodPost}, routing.AllowedMethods)
+
+		stripPrefix := false
+		routing, err = client.UpsertTemplateAppRouting(ctx, template.ID, "jupyter", codersdk.UpsertTemplateAppRoutingRequest{
+			StripPrefix: &stripPrefix,
+		})
+		require.NoError(t, err)
+		require.False(t, routing.StripPrefix)
+		require.Empty(t, routing.AllowedMethods)
+
+		routings, err := client.TemplateAppRouting(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.TemplateAppRouting{routing}, routings)
+
+		err = client.DeleteTemplateAppRouting(ctx, template.ID, "jupyter")
+		require.NoError(t, err)
+		routings, err = client.TemplateAppRouting(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, routings)
+
+		err = client.DeleteTemplateAppRouting(ctx, template.ID, "jupyter")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpsertTemplateAppRouting(ctx, template.ID, "Not_A_Slug", codersdk.UpsertTemplateAppRoutingRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.UpsertTemplateAppRouting(ctx, template.ID, "code", codersdk.UpsertTemplateAppRoutingRequest{
+			Headers:        map[string]string{"Bad Header": "value"},
+			AllowedMethods: []string{"GET POST"},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := member.UpsertTemplateAppRouting(ctx, template.ID, "code", codersdk.UpsertTemplateAppRoutingRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
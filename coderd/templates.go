@@ -643,6 +643,7 @@ func (api *API) convertTemplate(
 	activeCount, _ := api.metricsCache.TemplateUniqueUsers(template.ID)
 
 	buildTimeStats := api.metricsCache.TemplateBuildTimeStats(template.ID)
+	buildStageStats := api.metricsCache.TemplateBuildStageStats(template.ID)
 
 	return codersdk.Template{
 		ID:                           template.ID,
@@ -655,6 +656,7 @@ func (api *API) convertTemplate(
 		ActiveVersionID:              template.ActiveVersionID,
 		ActiveUserCount:              activeCount,
 		BuildTimeStats:               buildTimeStats,
+		BuildStageStats:              buildStageStats,
 		Description:                  template.Description,
 		Icon:                         template.Icon,
 		DefaultTTLMillis:             time.Duration(template.DefaultTTL).Milliseconds(),
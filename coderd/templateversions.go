@@ -574,6 +574,15 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	hasMatchingDaemon, err := provisionerdserver.HasMatchingDaemon(ctx, api.Database, job.Provisioner, job.Tags, database.Now())
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error checking provisioner daemons.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	// Create a dry-run job
 	jobID := uuid.New()
 	provisionerJob, err := api.Database.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
@@ -588,7 +597,8 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		Type:           database.ProvisionerJobTypeTemplateVersionDryRun,
 		Input:          input,
 		// Copy tags from the previous run.
-		Tags: job.Tags,
+		Tags:              job.Tags,
+		HasMatchingDaemon: hasMatchingDaemon,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -1409,18 +1419,24 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 			return xerrors.Errorf("marshal job input: %w", err)
 		}
 
+		hasMatchingDaemon, err := provisionerdserver.HasMatchingDaemon(ctx, tx, database.ProvisionerType(req.Provisioner), tags, database.Now())
+		if err != nil {
+			return xerrors.Errorf("check provisioner daemons: %w", err)
+		}
+
 		provisionerJob, err = tx.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
-			ID:             jobID,
-			CreatedAt:      database.Now(),
-			UpdatedAt:      database.Now(),
-			OrganizationID: organization.ID,
-			InitiatorID:    apiKey.UserID,
-			Provisioner:    database.ProvisionerType(req.Provisioner),
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeTemplateVersionImport,
-			Input:          jobInput,
-			Tags:           tags,
+			ID:                jobID,
+			CreatedAt:         database.Now(),
+			UpdatedAt:         database.Now(),
+			OrganizationID:    organization.ID,
+			InitiatorID:       apiKey.UserID,
+			Provisioner:       database.ProvisionerType(req.Provisioner),
+			StorageMethod:     database.ProvisionerStorageMethodFile,
+			FileID:            file.ID,
+			Type:              database.ProvisionerJobTypeTemplateVersionImport,
+			Input:             jobInput,
+			Tags:              tags,
+			HasMatchingDaemon: hasMatchingDaemon,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
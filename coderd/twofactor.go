@@ -0,0 +1,846 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/twofactor"
+	"github.com/coder/coder/codersdk"
+)
+
+// totpIssuer is displayed by authenticator apps next to the email of the user.
+const totpIssuer = "Coder"
+
+// @Summary Get user two-factor authentication
+// @ID get-user-two-factor-authentication
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.TwoFactor
+// @Router /users/{user}/two-factor [get]
+func (api *API) twoFactor(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	totp, err := api.Database.GetUserTOTP(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching security keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TwoFactor{
+		TOTP:                   totp.Confirmed,
+		RecoveryCodesRemaining: len(totp.HashedRecoveryCodes),
+		WebAuthnCredentials:    convertWebAuthnCredentials(credentials),
+		Required:               api.DeploymentValues.TwoFactorRequired.Value(),
+	})
+}
+
+// Admins reset the two-factor authentication of users that lost their
+// authenticator app, recovery codes and security keys.
+//
+// @Summary Reset user two-factor authentication
+// @ID reset-user-two-factor-authentication
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/two-factor [delete]
+func (api *API) resetTwoFactor(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	err := api.Database.DeleteUserTOTP(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Admins that may reset the second factors of a user can't necessarily
+	// read them. Deleting each credential is still authorized as the admin.
+	//nolint:gocritic // The credentials are only listed to be deleted.
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching security keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, credential := range credentials {
+		err = api.Database.DeleteUserWebAuthnCredential(ctx, credential.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error deleting security key.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// The key isn't accepted at login until it's confirmed with a code from the
+// authenticator app.
+//
+// @Summary Create user TOTP key
+// @ID create-user-totp-key
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.TOTPKey
+// @Router /users/{user}/two-factor/totp [post]
+func (api *API) postTOTPKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+	if !api.canSetUpTwoFactor(rw, r, user) {
+		return
+	}
+
+	totp, err := api.Database.GetUserTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if totp.Confirmed {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "TOTP is already set up. Remove it before creating a new key.",
+		})
+		return
+	}
+
+	secret, err := twofactor.GenerateTOTPSecret()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating TOTP key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	now := database.Now()
+	_, err = api.Database.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+		UserID:              user.ID,
+		Secret:              secret,
+		Confirmed:           false,
+		HashedRecoveryCodes: []string{},
+		LastUsedStep:        0,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving TOTP key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.TOTPKey{
+		Secret: secret,
+		URL:    twofactor.TOTPURL(totpIssuer, user.Email, secret),
+	})
+}
+
+// The recovery codes are only returned once, and each of them can be used
+// once to log in without the authenticator app.
+//
+// @Summary Confirm user TOTP key
+// @ID confirm-user-totp-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.ConfirmTOTPRequest true "Confirm TOTP request"
+// @Success 200 {object} codersdk.TOTPRecoveryCodes
+// @Router /users/{user}/two-factor/totp [put]
+func (api *API) putTOTPKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+		req  codersdk.ConfirmTOTPRequest
+	)
+	if !api.canSetUpTwoFactor(rw, r, user) {
+		return
+	}
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	totp, err := api.Database.GetUserTOTP(ctx, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Create a TOTP key before confirming it.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if totp.Confirmed {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "TOTP is already set up.",
+		})
+		return
+	}
+
+	step, ok := twofactor.ValidateTOTP(totp.Secret, req.Code, database.Now())
+	if !ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid TOTP code.",
+			Validations: []codersdk.ValidationError{
+				{Field: "code", Detail: "The code doesn't match the TOTP key."},
+			},
+		})
+		return
+	}
+
+	recoveryCodes, err := twofactor.GenerateRecoveryCodes()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	hashedRecoveryCodes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashedRecoveryCodes = append(hashedRecoveryCodes, twofactor.HashRecoveryCode(code))
+	}
+
+	_, err = api.Database.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+		UserID:              user.ID,
+		Secret:              totp.Secret,
+		Confirmed:           true,
+		HashedRecoveryCodes: hashedRecoveryCodes,
+		// The code used to confirm can't be used to log in.
+		LastUsedStep: step,
+		CreatedAt:    totp.CreatedAt,
+		UpdatedAt:    database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TOTPRecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// @Summary Delete user TOTP key
+// @ID delete-user-totp-key
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/two-factor/totp [delete]
+func (api *API) deleteTOTPKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	err := api.Database.DeleteUserTOTP(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// The options are passed to navigator.credentials.create() in the browser.
+//
+// @Summary Create user security key challenge
+// @ID create-user-security-key-challenge
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.WebAuthnCreationOptions
+// @Router /users/{user}/two-factor/webauthn/challenge [post]
+func (api *API) postWebAuthnChallenge(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+	if !api.canSetUpTwoFactor(rw, r, user) {
+		return
+	}
+
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching security keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	challenge, err := api.issueWebAuthnChallenge(ctx, user.ID, twofactor.CeremonyCreate)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating challenge.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Registering the same security key twice is prevented by the browser.
+	excludeCredentials := make([][]byte, 0, len(credentials))
+	for _, credential := range credentials {
+		excludeCredentials = append(excludeCredentials, credential.CredentialID)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WebAuthnCreationOptions{
+		Challenge:          challenge,
+		RelyingPartyID:     api.webAuthn.ID,
+		RelyingPartyName:   totpIssuer,
+		UserID:             user.ID[:],
+		Username:           user.Username,
+		Algorithms:         twofactor.Algorithms,
+		ExcludeCredentials: excludeCredentials,
+		TimeoutMS:          twofactor.ChallengeTimeout.Milliseconds(),
+	})
+}
+
+// @Summary Create user security key
+// @ID create-user-security-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateWebAuthnCredentialRequest true "Create security key request"
+// @Success 201 {object} codersdk.WebAuthnCredential
+// @Router /users/{user}/two-factor/webauthn [post]
+func (api *API) postWebAuthnCredential(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+		req  codersdk.CreateWebAuthnCredentialRequest
+	)
+	if !api.canSetUpTwoFactor(rw, r, user) {
+		return
+	}
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	challenge, err := api.consumeWebAuthnChallenge(ctx, user.ID, twofactor.CeremonyCreate, req.ClientDataJSON)
+	if err != nil && !errors.Is(err, twofactor.ErrInvalidAssertion) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching challenge.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	var credential twofactor.Credential
+	if err == nil {
+		credential, err = api.webAuthn.VerifyRegistration(challenge, req.ClientDataJSON, req.AttestationObject)
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid security key response.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := database.Now()
+	inserted, err := api.Database.InsertUserWebAuthnCredential(ctx, database.InsertUserWebAuthnCredentialParams{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		CredentialID: credential.ID,
+		Name:         req.Name,
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.SignCount),
+		CreatedAt:    now,
+		LastUsedAt:   now,
+	})
+	if database.IsUniqueViolation(err, database.UniqueUserWebauthnCredentialsCredentialIDKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The security key is already registered.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving security key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWebAuthnCredential(inserted))
+}
+
+// @Summary Delete user security key
+// @ID delete-user-security-key
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param credential path string true "Security key ID" format(uuid)
+// @Success 204
+// @Router /users/{user}/two-factor/webauthn/{credential} [delete]
+func (api *API) deleteWebAuthnCredential(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	id, err := uuid.Parse(chi.URLParam(r, "credential"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid security key ID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	credential, err := api.Database.GetUserWebAuthnCredentialByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) || dbauthz.IsNotAuthorizedError(err) || (err == nil && credential.UserID != user.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching security key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.DeleteUserWebAuthnCredential(ctx, credential.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting security key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// The email and password are verified before a security key is asked for.
+// The response is passed to navigator.credentials.get() in the browser, and
+// its result is sent along with the email and password to log in.
+//
+// @Summary Create security key login challenge
+// @ID create-security-key-login-challenge
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithPasswordRequest true "Login request"
+// @Success 200 {object} codersdk.WebAuthnRequestOptions
+// @Router /users/login/webauthn [post]
+func (api *API) postLoginWebAuthn(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		loginWithPassword codersdk.LoginWithPasswordRequest
+	)
+	if !httpapi.Read(ctx, rw, r, &loginWithPassword) {
+		return
+	}
+
+	//nolint:gocritic // In order to login, we need to get the user first!
+	user, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+		Email: loginWithPassword.Email,
+	})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	if _, ok := api.verifyPasswordLogin(ctx, rw, user, loginWithPassword.Password); !ok {
+		return
+	}
+
+	//nolint:gocritic // The user isn't logged in yet.
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching security keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(credentials) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "No security keys are registered.",
+		})
+		return
+	}
+	//nolint:gocritic // The user isn't logged in yet.
+	challenge, err := api.issueWebAuthnChallenge(dbauthz.AsSystemRestricted(ctx), user.ID, twofactor.CeremonyGet)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating challenge.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	allowCredentials := make([][]byte, 0, len(credentials))
+	for _, credential := range credentials {
+		allowCredentials = append(allowCredentials, credential.CredentialID)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WebAuthnRequestOptions{
+		Challenge:        challenge,
+		RelyingPartyID:   api.webAuthn.ID,
+		AllowCredentials: allowCredentials,
+		TimeoutMS:        twofactor.ChallengeTimeout.Milliseconds(),
+	})
+}
+
+// verifySecondFactor checks the TOTP code, recovery code or security key sent
+// by a password user that's logging in. Users without second factors pass.
+// A response is written if the login isn't allowed.
+func (api *API) verifySecondFactor(ctx context.Context, rw http.ResponseWriter, user database.User, req codersdk.LoginWithPasswordRequest) bool {
+	//nolint:gocritic // The user isn't logged in yet.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	totp, err := api.Database.GetUserTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return false
+	}
+	credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return false
+	}
+	if !totp.Confirmed && len(credentials) == 0 {
+		return true
+	}
+
+	invalid := func() bool {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid two-factor authentication code or security key.",
+		})
+		return false
+	}
+	now := database.Now()
+
+	switch {
+	case req.TOTPCode != "" && totp.Confirmed:
+		step, ok := twofactor.ValidateTOTP(totp.Secret, req.TOTPCode, now)
+		if !ok {
+			return invalid()
+		}
+		// Codes can't be replayed within the window they're valid in. The
+		// step is only stored if it's later than the last used one, so
+		// concurrent logins can't both use a code.
+		_, err = api.Database.UpdateUserTOTPLastUsedStep(ctx, database.UpdateUserTOTPLastUsedStepParams{
+			UserID:       user.ID,
+			LastUsedStep: step,
+			UpdatedAt:    now,
+		})
+	case req.RecoveryCode != "" && totp.Confirmed:
+		// The code is only removed if it's still there, so concurrent logins
+		// can't both use it.
+		_, err = api.Database.UpdateUserTOTPRemoveRecoveryCode(ctx, database.UpdateUserTOTPRemoveRecoveryCodeParams{
+			UserID:             user.ID,
+			HashedRecoveryCode: twofactor.HashRecoveryCode(req.RecoveryCode),
+			UpdatedAt:          now,
+		})
+	case req.WebAuthn != nil && len(credentials) > 0:
+		var credential *database.UserWebAuthnCredential
+		for i := range credentials {
+			if bytes.Equal(credentials[i].CredentialID, req.WebAuthn.CredentialID) {
+				credential = &credentials[i]
+				break
+			}
+		}
+		if credential == nil {
+			return invalid()
+		}
+		// The challenge is deleted before the response is verified, so a
+		// response can only be tried once, even by authenticators that
+		// don't count signatures.
+		challenge, err := api.consumeWebAuthnChallenge(ctx, user.ID, twofactor.CeremonyGet, req.WebAuthn.ClientDataJSON)
+		if errors.Is(err, twofactor.ErrInvalidAssertion) {
+			return invalid()
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return false
+		}
+		signCount, err := api.webAuthn.VerifyAssertion(challenge, twofactor.Credential{
+			ID:        credential.CredentialID,
+			PublicKey: credential.PublicKey,
+			SignCount: uint32(credential.SignCount),
+		}, req.WebAuthn.ClientDataJSON, req.WebAuthn.AuthenticatorData, req.WebAuthn.Signature)
+		if err != nil {
+			return invalid()
+		}
+		err = api.Database.UpdateUserWebAuthnCredentialSignCount(ctx, database.UpdateUserWebAuthnCredentialSignCountParams{
+			ID:         credential.ID,
+			SignCount:  int64(signCount),
+			LastUsedAt: now,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return false
+		}
+		return true
+	default:
+		// The validations tell clients which second factors they can ask for.
+		var validations []codersdk.ValidationError
+		if totp.Confirmed {
+			validations = append(validations,
+				codersdk.ValidationError{Field: "totp_code", Detail: "A code from the authenticator app is required."},
+				codersdk.ValidationError{Field: "recovery_code", Detail: "A recovery code can be used instead of the authenticator app."},
+			)
+		}
+		if len(credentials) > 0 {
+			validations = append(validations, codersdk.ValidationError{Field: "webauthn", Detail: "A security key can be used."})
+		}
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message:     "Two-factor authentication is required.",
+			Validations: validations,
+		})
+		return false
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return invalid()
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return false
+	}
+	return true
+}
+
+// issueWebAuthnChallenge returns a challenge for a ceremony of the user, which
+// is stored until it's answered or expires.
+func (api *API) issueWebAuthnChallenge(ctx context.Context, userID uuid.UUID, ceremony twofactor.Ceremony) ([]byte, error) {
+	challenge, err := twofactor.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	now := database.Now()
+	err = api.Database.InsertUserWebAuthnChallenge(ctx, database.InsertUserWebAuthnChallengeParams{
+		Challenge: challenge,
+		UserID:    userID,
+		Ceremony:  string(ceremony),
+		CreatedAt: now,
+		ExpiresAt: now.Add(twofactor.ChallengeTimeout),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("insert challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// consumeWebAuthnChallenge deletes the challenge answered in the client data,
+// so the response can't be replayed. twofactor.ErrInvalidAssertion is
+// returned if the challenge wasn't issued to the user, was already answered or
+// expired.
+func (api *API) consumeWebAuthnChallenge(ctx context.Context, userID uuid.UUID, ceremony twofactor.Ceremony, clientDataJSON []byte) ([]byte, error) {
+	challenge, err := twofactor.ClientDataChallenge(clientDataJSON)
+	if err != nil {
+		return nil, xerrors.Errorf("%w: %s", twofactor.ErrInvalidAssertion, err)
+	}
+	stored, err := api.Database.DeleteUserWebAuthnChallenge(ctx, database.DeleteUserWebAuthnChallengeParams{
+		Challenge: challenge,
+		UserID:    userID,
+		Ceremony:  string(ceremony),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("%w: unknown challenge", twofactor.ErrInvalidAssertion)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("delete challenge: %w", err)
+	}
+	if database.Now().After(stored.ExpiresAt) {
+		return nil, xerrors.Errorf("%w: challenge expired", twofactor.ErrInvalidAssertion)
+	}
+	return stored.Challenge, nil
+}
+
+// canSetUpTwoFactor only lets password users set up their own second factors.
+func (api *API) canSetUpTwoFactor(rw http.ResponseWriter, r *http.Request, user database.User) bool {
+	ctx := r.Context()
+	if httpmw.APIKey(r).UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Users can only set up their own two-factor authentication.",
+		})
+		return false
+	}
+	if user.LoginType != database.LoginTypePassword {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Two-factor authentication is only supported for password logins.",
+		})
+		return false
+	}
+	return true
+}
+
+// requireTwoFactor only lets sessions of password users without a second
+// factor fetch themselves, log out and set one up. It's used when the
+// deployment requires two-factor authentication.
+func (api *API) requireTwoFactor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		key := httpmw.APIKey(r)
+		if key.LoginType != database.LoginTypePassword && key.LoginType != database.LoginTypeToken {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		if twoFactorSetupPath(r) {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		//nolint:gocritic // The API key is already authenticated.
+		ctx = dbauthz.AsSystemRestricted(ctx)
+		if key.LoginType == database.LoginTypeToken {
+			user, err := api.Database.GetUserByID(ctx, key.UserID)
+			if err != nil {
+				httpapi.InternalServerError(rw, err)
+				return
+			}
+			if user.LoginType != database.LoginTypePassword {
+				next.ServeHTTP(rw, r)
+				return
+			}
+		}
+
+		totp, err := api.Database.GetUserTOTP(ctx, key.UserID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if totp.Confirmed {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		credentials, err := api.Database.GetUserWebAuthnCredentialsByUserID(ctx, key.UserID)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if len(credentials) > 0 {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Two-factor authentication is required.",
+			Detail:  "Set up an authenticator app or a security key before using this deployment.",
+		})
+	})
+}
+
+// twoFactorSetupPath returns true for the routes sessions without a second
+// factor can use when it's required.
+func twoFactorSetupPath(r *http.Request) bool {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodGet && path == "/api/v2/users/me":
+		return true
+	case r.Method == http.MethodPost && path == "/api/v2/users/logout":
+		return true
+	}
+	userPath, ok := strings.CutPrefix(path, "/api/v2/users/")
+	if !ok {
+		return false
+	}
+	_, rest, _ := strings.Cut(userPath, "/")
+	return rest == "two-factor" || strings.HasPrefix(rest, "two-factor/")
+}
+
+func convertWebAuthnCredentials(credentials []database.UserWebAuthnCredential) []codersdk.WebAuthnCredential {
+	converted := make([]codersdk.WebAuthnCredential, 0, len(credentials))
+	for _, credential := range credentials {
+		converted = append(converted, convertWebAuthnCredential(credential))
+	}
+	return converted
+}
+
+func convertWebAuthnCredential(credential database.UserWebAuthnCredential) codersdk.WebAuthnCredential {
+	return codersdk.WebAuthnCredential{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
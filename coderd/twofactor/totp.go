@@ -0,0 +1,142 @@
+// Package twofactor implements the second factors password users can log in
+// with: time-based one-time passwords (RFC 6238), recovery codes and WebAuthn
+// security keys.
+package twofactor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //#nosec // RFC 6238 authenticator apps only support SHA-1.
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TOTPPeriod is how long a code is valid for.
+	TOTPPeriod = 30 * time.Second
+	// TOTPDigits is the length of a code.
+	TOTPDigits = 6
+	// totpSkew is the number of periods before and after the current one
+	// that codes are accepted from, which allows for clock drift.
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random base32 encoded secret.
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", xerrors.Errorf("read random: %w", err)
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPURL returns the otpauth:// URL authenticator apps are set up with,
+// usually by scanning it as a QR code.
+func TOTPURL(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// TOTPCode returns the code for the secret at the time.
+func TOTPCode(secret string, now time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return totpCode(key, totpStep(now)), nil
+}
+
+// ValidateTOTP checks the code against the secret at the time. The step the
+// code was generated for is returned, so callers can reject codes that were
+// already used by requiring it to be greater than the last one accepted.
+func ValidateTOTP(secret, code string, now time.Time) (step int64, ok bool) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+	current := totpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, xerrors.Errorf("decode secret: %w", err)
+	}
+	return key, nil
+}
+
+func totpStep(now time.Time) int64 {
+	return now.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// totpCode implements the dynamic truncation of RFC 4226.
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, value%1000000)
+}
+
+// RecoveryCodeCount is the number of recovery codes users get when they set
+// up TOTP.
+const RecoveryCodeCount = 10
+
+// GenerateRecoveryCodes returns random single-use codes that users can log in
+// with when they lose their authenticator.
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	for len(codes) < RecoveryCodeCount {
+		raw := make([]byte, 5)
+		_, err := rand.Read(raw)
+		if err != nil {
+			return nil, xerrors.Errorf("read random: %w", err)
+		}
+		code := hex.EncodeToString(raw)
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode returns the hash a recovery code is stored as. The codes
+// are random, so a fast hash is as good as a password hash.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,80 @@
+package twofactor_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/twofactor"
+)
+
+func TestTOTP(t *testing.T) {
+	t.Parallel()
+
+	// The SHA-1 test vectors of RFC 6238, truncated to six digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for unix, code := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		got, err := twofactor.TOTPCode(secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, code, got, "time %d", unix)
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+		secret, err := twofactor.GenerateTOTPSecret()
+		require.NoError(t, err)
+		now := time.Now()
+
+		code, err := twofactor.TOTPCode(secret, now)
+		require.NoError(t, err)
+		step, ok := twofactor.ValidateTOTP(secret, code, now)
+		require.True(t, ok)
+		// Codes of the previous period are accepted for clock drift.
+		previousStep, ok := twofactor.ValidateTOTP(secret, code, now.Add(twofactor.TOTPPeriod))
+		require.True(t, ok)
+		require.Equal(t, step, previousStep)
+
+		_, ok = twofactor.ValidateTOTP(secret, code, now.Add(3*twofactor.TOTPPeriod))
+		require.False(t, ok)
+		_, ok = twofactor.ValidateTOTP(secret, "", now)
+		require.False(t, ok)
+		_, ok = twofactor.ValidateTOTP("not base32!", code, now)
+		require.False(t, ok)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		t.Parallel()
+		parsed, err := url.Parse(twofactor.TOTPURL("Coder", "kyle@coder.com", "SECRET"))
+		require.NoError(t, err)
+		require.Equal(t, "otpauth", parsed.Scheme)
+		require.Equal(t, "totp", parsed.Host)
+		require.Equal(t, "/Coder:kyle@coder.com", parsed.Path)
+		require.Equal(t, "SECRET", parsed.Query().Get("secret"))
+		require.Equal(t, "Coder", parsed.Query().Get("issuer"))
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, err := twofactor.GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, twofactor.RecoveryCodeCount)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		require.Len(t, code, 11)
+		hash := twofactor.HashRecoveryCode(code)
+		require.False(t, seen[hash])
+		seen[hash] = true
+	}
+	// Codes are typed by hand, so their case and whitespace don't matter.
+	require.Equal(t, twofactor.HashRecoveryCode("abcde-01234"), twofactor.HashRecoveryCode(" ABCDE-01234 "))
+}
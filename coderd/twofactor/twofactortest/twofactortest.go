@@ -0,0 +1,113 @@
+// Package twofactortest provides a software security key that stands in for a
+// real one in tests.
+package twofactortest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/twofactor"
+)
+
+// Authenticator holds a single ES256 credential, and signs with it like a
+// security key that counts signatures.
+type Authenticator struct {
+	// RelyingPartyID and Origin are what a browser would report.
+	RelyingPartyID string
+	Origin         string
+	// NoSignCount makes the authenticator always report a zero signature
+	// counter, like authenticators that don't count signatures.
+	NoSignCount bool
+
+	CredentialID []byte
+	key          *ecdsa.PrivateKey
+	signCount    uint32
+}
+
+// New returns an authenticator for a relying party.
+func New(t testing.TB, relyingPartyID, origin string) *Authenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+	return &Authenticator{
+		RelyingPartyID: relyingPartyID,
+		Origin:         origin,
+		CredentialID:   id,
+		key:            key,
+	}
+}
+
+// Create returns the client data and attestation object of registering the
+// credential in answer to the challenge.
+func (a *Authenticator) Create(t testing.TB, challenge []byte) (clientDataJSON, attestationObject []byte) {
+	t.Helper()
+
+	publicKey, err := cbor.Marshal(map[int]interface{}{
+		1:  2, // EC2
+		3:  twofactor.AlgorithmES256,
+		-1: 1, // P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	credentialData := make([]byte, 18)
+	binary.BigEndian.PutUint16(credentialData[16:], uint16(len(a.CredentialID)))
+	credentialData = append(credentialData, a.CredentialID...)
+	credentialData = append(credentialData, publicKey...)
+
+	attestationObject, err = cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authenticatorData(0x41, credentialData),
+	})
+	require.NoError(t, err)
+	return a.clientData(t, twofactor.CeremonyCreate, challenge), attestationObject
+}
+
+// Get returns the client data, authenticator data and signature of signing
+// the challenge with the credential.
+func (a *Authenticator) Get(t testing.TB, challenge []byte) (clientDataJSON, authenticatorData, signature []byte) {
+	t.Helper()
+
+	if !a.NoSignCount {
+		a.signCount++
+	}
+	clientDataJSON = a.clientData(t, twofactor.CeremonyGet, challenge)
+	authenticatorData = a.authenticatorData(0x01, nil)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+	return clientDataJSON, authenticatorData, signature
+}
+
+func (a *Authenticator) clientData(t testing.TB, ceremony twofactor.Ceremony, challenge []byte) []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    a.Origin,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func (a *Authenticator) authenticatorData(flags byte, credentialData []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RelyingPartyID))
+	data := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[33:], a.signCount)
+	return append(data, credentialData...)
+}
@@ -0,0 +1,173 @@
+package twofactor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"golang.org/x/xerrors"
+)
+
+// Ceremony is the type of a WebAuthn operation, which is included in the
+// client data signed by authenticators.
+type Ceremony string
+
+const (
+	CeremonyCreate = Ceremony(protocol.CreateCeremony)
+	CeremonyGet    = Ceremony(protocol.AssertCeremony)
+)
+
+// COSE algorithm identifiers of the public keys that are supported.
+const (
+	AlgorithmES256 = int64(webauthncose.AlgES256)
+	AlgorithmEdDSA = int64(webauthncose.AlgEdDSA)
+	AlgorithmRS256 = int64(webauthncose.AlgRS256)
+)
+
+// Algorithms are the COSE algorithms credentials may use, in order of
+// preference.
+var Algorithms = []int64{AlgorithmES256, AlgorithmEdDSA, AlgorithmRS256}
+
+// ChallengeTimeout is how long a challenge can be answered for.
+const ChallengeTimeout = 5 * time.Minute
+
+// ErrInvalidAssertion is returned when a WebAuthn response doesn't verify.
+var ErrInvalidAssertion = xerrors.New("invalid webauthn response")
+
+// RelyingParty verifies WebAuthn responses for a deployment.
+//
+// Challenges aren't tracked here. Callers store the challenges they issue and
+// delete them when they're answered, so that a response can't be replayed.
+// Attestation statements are verified if present, but any security key is
+// trusted.
+type RelyingParty struct {
+	// ID is the domain credentials are scoped to, e.g. "coder.example.com".
+	ID string
+	// Origin is the origin of the dashboard, e.g. "https://coder.example.com".
+	Origin string
+}
+
+// Credential is a public key credential registered by a user.
+type Credential struct {
+	ID []byte
+	// PublicKey is COSE encoded.
+	PublicKey []byte
+	SignCount uint32
+}
+
+// NewChallenge returns a random challenge.
+func NewChallenge() ([]byte, error) {
+	challenge, err := protocol.CreateChallenge()
+	if err != nil {
+		return nil, xerrors.Errorf("create challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// ClientDataChallenge returns the challenge an authenticator answered, so the
+// stored challenge can be looked up.
+func ClientDataChallenge(clientDataJSON []byte) ([]byte, error) {
+	var clientData protocol.CollectedClientData
+	err := json.Unmarshal(clientDataJSON, &clientData)
+	if err != nil {
+		return nil, xerrors.Errorf("decode client data: %w", err)
+	}
+	challenge, err := base64.RawURLEncoding.DecodeString(clientData.Challenge)
+	if err != nil {
+		return nil, xerrors.Errorf("%w: malformed challenge", ErrInvalidAssertion)
+	}
+	return challenge, nil
+}
+
+// VerifyRegistration verifies the response of an authenticator that created a
+// credential in answer to the challenge, and returns the credential.
+func (rp RelyingParty) VerifyRegistration(challenge, clientDataJSON, attestationObject []byte) (Credential, error) {
+	response := protocol.AuthenticatorAttestationResponse{
+		AuthenticatorResponse: protocol.AuthenticatorResponse{
+			ClientDataJSON: clientDataJSON,
+		},
+		AttestationObject: attestationObject,
+	}
+	parsed, err := response.Parse()
+	if err != nil {
+		return Credential{}, xerrors.Errorf("%w: %s", ErrInvalidAssertion, errorDetails(err))
+	}
+	creation := protocol.ParsedCredentialCreationData{
+		Response: *parsed,
+		Raw: protocol.CredentialCreationResponse{
+			AttestationResponse: response,
+		},
+	}
+	err = creation.Verify(encodeChallenge(challenge), false, rp.ID, rp.Origin)
+	if err != nil {
+		return Credential{}, xerrors.Errorf("%w: %s", ErrInvalidAssertion, errorDetails(err))
+	}
+
+	authData := parsed.AttestationObject.AuthData
+	_, err = webauthncose.ParsePublicKey(authData.AttData.CredentialPublicKey)
+	if err != nil {
+		return Credential{}, xerrors.Errorf("%w: unsupported public key: %s", ErrInvalidAssertion, err)
+	}
+	return Credential{
+		ID:        authData.AttData.CredentialID,
+		PublicKey: authData.AttData.CredentialPublicKey,
+		SignCount: authData.Counter,
+	}, nil
+}
+
+// VerifyAssertion verifies the response of an authenticator that signed the
+// challenge with the credential. The new signature counter of the credential
+// is returned.
+func (rp RelyingParty) VerifyAssertion(challenge []byte, credential Credential, clientDataJSON, authenticatorData, signature []byte) (uint32, error) {
+	assertion := protocol.ParsedCredentialAssertionData{
+		Response: protocol.ParsedAssertionResponse{
+			Signature: signature,
+		},
+		Raw: protocol.CredentialAssertionResponse{
+			AssertionResponse: protocol.AuthenticatorAssertionResponse{
+				AuthenticatorResponse: protocol.AuthenticatorResponse{
+					ClientDataJSON: clientDataJSON,
+				},
+				AuthenticatorData: authenticatorData,
+				Signature:         signature,
+			},
+		},
+	}
+	err := json.Unmarshal(clientDataJSON, &assertion.Response.CollectedClientData)
+	if err != nil {
+		return 0, xerrors.Errorf("decode client data: %w", err)
+	}
+	err = assertion.Response.AuthenticatorData.Unmarshal(authenticatorData)
+	if err != nil {
+		return 0, xerrors.Errorf("%w: %s", ErrInvalidAssertion, errorDetails(err))
+	}
+	err = assertion.Verify(encodeChallenge(challenge), rp.ID, rp.Origin, "", false, credential.PublicKey)
+	if err != nil {
+		return 0, xerrors.Errorf("%w: %s", ErrInvalidAssertion, errorDetails(err))
+	}
+
+	// Authenticators that don't count signatures always report zero.
+	// Otherwise, a counter that didn't increase indicates a cloned key.
+	signCount := assertion.Response.AuthenticatorData.Counter
+	if (signCount != 0 || credential.SignCount != 0) && signCount <= credential.SignCount {
+		return 0, xerrors.Errorf("%w: signature counter didn't increase", ErrInvalidAssertion)
+	}
+	return signCount, nil
+}
+
+// encodeChallenge encodes a challenge like browsers do in the client data.
+func encodeChallenge(challenge []byte) string {
+	return base64.RawURLEncoding.EncodeToString(challenge)
+}
+
+// errorDetails includes the details of protocol errors, which are left out of
+// their messages.
+func errorDetails(err error) string {
+	var protocolErr *protocol.Error
+	if xerrors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return protocolErr.Details + ": " + protocolErr.DevInfo
+	}
+	return err.Error()
+}
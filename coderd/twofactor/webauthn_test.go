@@ -0,0 +1,105 @@
+package twofactor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/twofactor"
+	"github.com/coder/coder/coderd/twofactor/twofactortest"
+)
+
+func TestRelyingParty(t *testing.T) {
+	t.Parallel()
+
+	rp := twofactor.RelyingParty{
+		ID:     "coder.example.com",
+		Origin: "https://coder.example.com",
+	}
+	register := func(t *testing.T) (*twofactortest.Authenticator, twofactor.Credential) {
+		authenticator := twofactortest.New(t, rp.ID, rp.Origin)
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		clientData, attestation := authenticator.Create(t, challenge)
+		credential, err := rp.VerifyRegistration(challenge, clientData, attestation)
+		require.NoError(t, err)
+		require.Equal(t, authenticator.CredentialID, credential.ID)
+		return authenticator, credential
+	}
+
+	t.Run("Assertion", func(t *testing.T) {
+		t.Parallel()
+		authenticator, credential := register(t)
+
+		for i := 0; i < 2; i++ {
+			challenge, err := twofactor.NewChallenge()
+			require.NoError(t, err)
+			clientData, authData, signature := authenticator.Get(t, challenge)
+			signCount, err := rp.VerifyAssertion(challenge, credential, clientData, authData, signature)
+			require.NoError(t, err)
+			require.Greater(t, signCount, credential.SignCount)
+			credential.SignCount = signCount
+		}
+	})
+
+	t.Run("ClonedKey", func(t *testing.T) {
+		t.Parallel()
+		authenticator, credential := register(t)
+		credential.SignCount = 10
+
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		clientData, authData, signature := authenticator.Get(t, challenge)
+		_, err = rp.VerifyAssertion(challenge, credential, clientData, authData, signature)
+		require.ErrorIs(t, err, twofactor.ErrInvalidAssertion)
+	})
+
+	t.Run("BadSignature", func(t *testing.T) {
+		t.Parallel()
+		authenticator, credential := register(t)
+
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		clientData, authData, signature := authenticator.Get(t, challenge)
+		signature[len(signature)-1] ^= 0xff
+		_, err = rp.VerifyAssertion(challenge, credential, clientData, authData, signature)
+		require.ErrorIs(t, err, twofactor.ErrInvalidAssertion)
+	})
+
+	t.Run("Challenge", func(t *testing.T) {
+		t.Parallel()
+		authenticator, credential := register(t)
+
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		clientData, authData, signature := authenticator.Get(t, challenge)
+		answered, err := twofactor.ClientDataChallenge(clientData)
+		require.NoError(t, err)
+		require.Equal(t, challenge, answered)
+
+		// Responses only verify against the challenge they answered.
+		other, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		_, err = rp.VerifyAssertion(other, credential, clientData, authData, signature)
+		require.ErrorIs(t, err, twofactor.ErrInvalidAssertion)
+		// Assertions can't be used to register credentials.
+		_, err = rp.VerifyRegistration(challenge, clientData, authData)
+		require.ErrorIs(t, err, twofactor.ErrInvalidAssertion)
+	})
+
+	t.Run("OtherRelyingParty", func(t *testing.T) {
+		t.Parallel()
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+
+		authenticator := twofactortest.New(t, "evil.example.com", rp.Origin)
+		clientData, attestation := authenticator.Create(t, challenge)
+		_, err = rp.VerifyRegistration(challenge, clientData, attestation)
+		require.ErrorIs(t, err, twofactor.ErrInvalidAssertion)
+
+		authenticator = twofactortest.New(t, rp.ID, "https://evil.example.com")
+		clientData, attestation = authenticator.Create(t, challenge)
+		_, err = rp.VerifyRegistration(challenge, clientData, attestation)
+		require.ErrorIs(t, err, twofactor.ErrInvalidAssertion)
+	})
+}
@@ -0,0 +1,332 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/twofactor"
+	"github.com/coder/coder/coderd/twofactor/twofactortest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/testutil"
+)
+
+func TestTwoFactorTOTP(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	login := codersdk.LoginWithPasswordRequest{
+		Email:    coderdtest.FirstUserParams.Email,
+		Password: coderdtest.FirstUserParams.Password,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	key, err := client.CreateTOTPKey(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Contains(t, key.URL, key.Secret)
+
+	// The key isn't used until it's confirmed.
+	_, err = client.LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+
+	_, err = client.ConfirmTOTP(ctx, codersdk.Me, codersdk.ConfirmTOTPRequest{Code: "000000"})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	code, err := twofactor.TOTPCode(key.Secret, time.Now())
+	require.NoError(t, err)
+	codes, err := client.ConfirmTOTP(ctx, codersdk.Me, codersdk.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+	require.Len(t, codes.RecoveryCodes, twofactor.RecoveryCodeCount)
+
+	_, err = client.CreateTOTPKey(ctx, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	status, err := client.TwoFactor(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.True(t, status.TOTP)
+	require.Equal(t, twofactor.RecoveryCodeCount, status.RecoveryCodesRemaining)
+
+	_, err = client.LoginWithPassword(ctx, login)
+	require.True(t, codersdk.IsTwoFactorRequired(err))
+
+	t.Run("Code", func(t *testing.T) {
+		// The code used to confirm can't be replayed, so the next one is
+		// used.
+		login := login
+		login.TOTPCode = code
+		_, err = client.LoginWithPassword(ctx, login)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.False(t, codersdk.IsTwoFactorRequired(err))
+
+		// Only one of concurrent logins can use the code.
+		login.TOTPCode, err = twofactor.TOTPCode(key.Secret, time.Now().Add(twofactor.TOTPPeriod))
+		require.NoError(t, err)
+		require.Equal(t, 1, concurrentLogins(ctx, client, login))
+
+		_, err = client.LoginWithPassword(ctx, login)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("RecoveryCode", func(t *testing.T) {
+		// Only one of concurrent logins can use the code.
+		login := login
+		login.RecoveryCode = codes.RecoveryCodes[0]
+		require.Equal(t, 1, concurrentLogins(ctx, client, login))
+
+		_, err = client.LoginWithPassword(ctx, login)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		status, err := client.TwoFactor(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, twofactor.RecoveryCodeCount-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := client.DeleteTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		_, err = client.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+	})
+}
+
+func TestTwoFactorWebAuthn(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	login := codersdk.LoginWithPasswordRequest{
+		Email:    coderdtest.FirstUserParams.Email,
+		Password: coderdtest.FirstUserParams.Password,
+	}
+	authenticator := twofactortest.New(t, client.URL.Hostname(), client.URL.Scheme+"://"+client.URL.Host)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	options, err := client.WebAuthnCreationOptions(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, client.URL.Hostname(), options.RelyingPartyID)
+	require.Empty(t, options.ExcludeCredentials)
+
+	clientDataJSON, attestationObject := authenticator.Create(t, options.Challenge)
+	credential, err := client.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+		Name:              "yubikey",
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "yubikey", credential.Name)
+
+	// Challenges can only be answered once.
+	_, err = client.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+		Name:              "yubikey",
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	options, err = client.WebAuthnCreationOptions(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{authenticator.CredentialID}, options.ExcludeCredentials)
+	clientDataJSON, attestationObject = authenticator.Create(t, options.Challenge)
+	_, err = client.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+		Name:              "yubikey",
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	_, err = client.LoginWithPassword(ctx, login)
+	require.True(t, codersdk.IsTwoFactorRequired(err))
+
+	request, err := client.WebAuthnRequestOptions(ctx, login)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{authenticator.CredentialID}, request.AllowCredentials)
+
+	clientDataJSON, authenticatorData, signature := authenticator.Get(t, request.Challenge)
+	login.WebAuthn = &codersdk.WebAuthnAssertion{
+		CredentialID:      authenticator.CredentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authenticatorData,
+		Signature:         signature,
+	}
+	_, err = client.LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+
+	// The challenge of the replayed assertion was already answered.
+	_, err = client.LoginWithPassword(ctx, login)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+	err = client.DeleteWebAuthnCredential(ctx, codersdk.Me, credential.ID)
+	require.NoError(t, err)
+	login.WebAuthn = nil
+	_, err = client.LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+}
+
+func TestTwoFactorWebAuthnReplay(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	login := codersdk.LoginWithPasswordRequest{
+		Email:    coderdtest.FirstUserParams.Email,
+		Password: coderdtest.FirstUserParams.Password,
+	}
+	// The signature counter doesn't stop replays of authenticators that
+	// don't count signatures.
+	authenticator := twofactortest.New(t, client.URL.Hostname(), client.URL.Scheme+"://"+client.URL.Host)
+	authenticator.NoSignCount = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	options, err := client.WebAuthnCreationOptions(ctx, codersdk.Me)
+	require.NoError(t, err)
+	clientDataJSON, attestationObject := authenticator.Create(t, options.Challenge)
+	_, err = client.CreateWebAuthnCredential(ctx, codersdk.Me, codersdk.CreateWebAuthnCredentialRequest{
+		Name:              "passkey",
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+	})
+	require.NoError(t, err)
+
+	request, err := client.WebAuthnRequestOptions(ctx, login)
+	require.NoError(t, err)
+	clientDataJSON, authenticatorData, signature := authenticator.Get(t, request.Challenge)
+	login.WebAuthn = &codersdk.WebAuthnAssertion{
+		CredentialID:      authenticator.CredentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authenticatorData,
+		Signature:         signature,
+	}
+
+	// Only one of the concurrent logins with the assertion succeeds.
+	require.Equal(t, 1, concurrentLogins(ctx, client, login))
+
+	// Later logins need a new challenge.
+	_, err = client.LoginWithPassword(ctx, login)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	request, err = client.WebAuthnRequestOptions(ctx, login)
+	require.NoError(t, err)
+	clientDataJSON, authenticatorData, signature = authenticator.Get(t, request.Challenge)
+	login.WebAuthn = &codersdk.WebAuthnAssertion{
+		CredentialID:      authenticator.CredentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authenticatorData,
+		Signature:         signature,
+	}
+	_, err = client.LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+}
+
+func TestResetTwoFactor(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	first := coderdtest.CreateFirstUser(t, client)
+	member, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	key, err := member.CreateTOTPKey(ctx, codersdk.Me)
+	require.NoError(t, err)
+	code, err := twofactor.TOTPCode(key.Secret, time.Now())
+	require.NoError(t, err)
+	_, err = member.ConfirmTOTP(ctx, codersdk.Me, codersdk.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+
+	// Admins can't set up second factors for other users.
+	_, err = client.CreateTOTPKey(ctx, user.ID.String())
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.ResetTwoFactor(ctx, user.ID.String())
+	require.NoError(t, err)
+
+	status, err := member.TwoFactor(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, status.TOTP)
+	require.Empty(t, status.WebAuthnCredentials)
+}
+
+func TestTwoFactorRequired(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.TwoFactorRequired = true
+	client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	_, err := client.OrganizationsByUser(ctx, codersdk.Me)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	_, err = client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	status, err := client.TwoFactor(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.True(t, status.Required)
+
+	key, err := client.CreateTOTPKey(ctx, codersdk.Me)
+	require.NoError(t, err)
+	code, err := twofactor.TOTPCode(key.Secret, time.Now())
+	require.NoError(t, err)
+	_, err = client.ConfirmTOTP(ctx, codersdk.Me, codersdk.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+
+	_, err = client.OrganizationsByUser(ctx, codersdk.Me)
+	require.NoError(t, err)
+}
+
+// concurrentLogins logs in with the same request from several goroutines at
+// once and returns how many of the logins succeeded.
+func concurrentLogins(ctx context.Context, client *codersdk.Client, login codersdk.LoginWithPasswordRequest) int {
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+	start := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := client.LoginWithPassword(ctx, login)
+			if err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return int(succeeded.Load())
+}
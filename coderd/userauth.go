@@ -9,11 +9,13 @@ import (
 	"net/mail"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/exp/slices"
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 
@@ -23,7 +25,9 @@ import (
 	"github.com/coder/coder/coderd/database/dbauthz"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/ldap"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/saml"
 	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/codersdk"
 )
@@ -70,13 +74,61 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 
 	aReq.UserID = user.ID
 
+	// Users that aren't known yet may be in the directory, which is also
+	// where the passwords of existing LDAP users are verified.
+	if api.LDAPConfig != nil && (user.ID == uuid.Nil || user.LoginType == database.LoginTypeLDAP) {
+		api.ldapLogin(rw, r, aReq, loginWithPassword)
+		return
+	}
+
+	roles, ok := api.verifyPasswordLogin(ctx, rw, user, loginWithPassword.Password)
+	if !ok {
+		return
+	}
+	if !api.verifySecondFactor(ctx, rw, user, loginWithPassword) {
+		return
+	}
+
+	userSubj := rbac.Subject{
+		ID:     user.ID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}
+
+	//nolint:gocritic // Creating the API key as the user instead of as system.
+	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, userSubj), createAPIKeyParams{
+		UserID:     user.ID,
+		LoginType:  database.LoginTypePassword,
+		RemoteAddr: r.RemoteAddr,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
+// verifyPasswordLogin checks the password of a user that's logging in, and
+// returns their roles. A response is written if the login isn't allowed.
+func (api *API) verifyPasswordLogin(ctx context.Context, rw http.ResponseWriter, user database.User, password string) (database.GetAuthorizationUserRolesRow, bool) {
 	// If the user doesn't exist, it will be a default struct.
-	equal, err := userpassword.Compare(string(user.HashedPassword), loginWithPassword.Password)
+	equal, err := userpassword.Compare(string(user.HashedPassword), password)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error.",
 		})
-		return
+		return database.GetAuthorizationUserRolesRow{}, false
 	}
 	if !equal {
 		// This message is the same as above to remove ease in detecting whether
@@ -84,7 +136,7 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
 			Message: "Incorrect email or password.",
 		})
-		return
+		return database.GetAuthorizationUserRolesRow{}, false
 	}
 
 	// If password authentication is disabled and the user does not have the
@@ -93,14 +145,14 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
 			Message: "Password authentication is disabled.",
 		})
-		return
+		return database.GetAuthorizationUserRolesRow{}, false
 	}
 
 	if user.LoginType != database.LoginTypePassword {
 		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
 			Message: fmt.Sprintf("Incorrect login type, attempting to use %q but user is of login type %q", database.LoginTypePassword, user.LoginType),
 		})
-		return
+		return database.GetAuthorizationUserRolesRow{}, false
 	}
 
 	//nolint:gocritic // System needs to fetch user roles in order to login user.
@@ -109,7 +161,7 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error.",
 		})
-		return
+		return database.GetAuthorizationUserRolesRow{}, false
 	}
 
 	// If the user logged into a suspended account, reject the login request.
@@ -117,37 +169,10 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
 			Message: "Your account is suspended. Contact an admin to reactivate your account.",
 		})
-		return
-	}
-
-	userSubj := rbac.Subject{
-		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
-		Groups: roles.Groups,
-		Scope:  rbac.ScopeAll,
-	}
-
-	//nolint:gocritic // Creating the API key as the user instead of as system.
-	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, userSubj), createAPIKeyParams{
-		UserID:     user.ID,
-		LoginType:  database.LoginTypePassword,
-		RemoteAddr: r.RemoteAddr,
-	})
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to create API key.",
-			Detail:  err.Error(),
-		})
-		return
+		return database.GetAuthorizationUserRolesRow{}, false
 	}
 
-	aReq.New = *key
-
-	http.SetCookie(rw, cookie)
-
-	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
-		SessionToken: cookie.Value,
-	})
+	return roles, true
 }
 
 // Clear the user's session cookie.
@@ -283,6 +308,15 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 		iconURL = api.OIDCConfig.IconURL
 	}
 
+	var samlAuthMethod codersdk.SAMLAuthMethod
+	if api.SAMLConfig != nil {
+		samlAuthMethod = codersdk.SAMLAuthMethod{
+			AuthMethod: codersdk.AuthMethod{Enabled: true},
+			SignInText: api.SAMLConfig.SignInText,
+			IconURL:    api.SAMLConfig.IconURL,
+		}
+	}
+
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.AuthMethods{
 		Password: codersdk.AuthMethod{
 			Enabled: !api.DeploymentValues.DisablePasswordAuth.Value(),
@@ -293,6 +327,8 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		SAML: samlAuthMethod,
+		LDAP: codersdk.AuthMethod{Enabled: api.LDAPConfig != nil},
 	})
 }
 
@@ -491,6 +527,18 @@ type OIDCConfig struct {
 	// to groups within Coder.
 	// map[oidcGroupName]coderGroupName
 	GroupMapping map[string]string
+	// UserRoleField selects the claim field to be used as the user's site
+	// roles. If the field is the empty string, then roles are assigned in
+	// Coder instead of by the OIDC provider.
+	UserRoleField string
+	// UserRoleMapping controls how values of the role claim get mapped to site
+	// roles within Coder. Values without a mapping grant no roles.
+	// map[oidcRoleValue][]coderRoleName
+	UserRoleMapping map[string][]string
+	// RefreshInterval is how often the tokens of OIDC users are refreshed, and
+	// users whose refresh tokens were revoked are suspended. Zero disables
+	// refreshing.
+	RefreshInterval time.Duration
 	// SignInText is the text to display on the OIDC login button
 	SignInText string
 	// IconURL points to the URL of an icon to display on the OIDC login button
@@ -676,6 +724,19 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var usingRoles bool
+	var roles []string
+	// If the UserRoleField is the empty string, then roles from OIDC are not
+	// used. This is so we can support manual role assignment.
+	if api.OIDCConfig.UserRoleField != "" {
+		usingRoles = true
+		roles = api.OIDCConfig.userRoles(claims)
+		api.Logger.Debug(ctx, "roles mapped from oidc claims",
+			slog.F("field", api.OIDCConfig.UserRoleField),
+			slog.F("roles", roles),
+		)
+	}
+
 	// The username is a required property in Coder. We make a best-effort
 	// attempt at using what the claims provide, but if that fails we will
 	// generate a random username.
@@ -733,6 +794,8 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		AvatarURL:    picture,
 		UsingGroups:  usingGroups,
 		Groups:       groups,
+		UsingRoles:   usingRoles,
+		Roles:        roles,
 	})
 	var httpErr httpError
 	if xerrors.As(err, &httpErr) {
@@ -761,6 +824,445 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
 }
 
+// userRoles maps the values of the role claim to site roles. The claim may be
+// a single value or a list of values.
+func (c *OIDCConfig) userRoles(claims map[string]interface{}) []string {
+	var values []string
+	switch raw := claims[c.UserRoleField].(type) {
+	case string:
+		values = append(values, raw)
+	case []interface{}:
+		for _, value := range raw {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	roles := []string{}
+	for _, value := range values {
+		for _, role := range c.UserRoleMapping[value] {
+			// The member role is implied for all users.
+			if role == rbac.RoleMember() || slices.Contains(roles, role) {
+				continue
+			}
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+type SAMLConfig struct {
+	ServiceProvider *saml.ServiceProvider
+	// EmailDomain are the domains to enforce when a user authenticates.
+	EmailDomain  []string
+	AllowSignups bool
+	// UsernameField selects the assertion attribute to be used as the created
+	// user's username. If the attribute is missing, the username is derived
+	// from the email.
+	UsernameField string
+	// EmailField selects the assertion attribute to be used as the created
+	// user's email.
+	EmailField string
+	// GroupField selects the assertion attribute to be used as the created
+	// user's groups. If the group field is the empty string, then no group
+	// updates will ever come from the SAML identity provider.
+	GroupField string
+	// GroupMapping controls how groups returned by the SAML identity provider
+	// get mapped to groups within Coder.
+	// map[samlGroupName]coderGroupName
+	GroupMapping map[string]string
+	// SignInText is the text to display on the SAML login button
+	SignInText string
+	// IconURL points to the URL of an icon to display on the SAML login button
+	IconURL string
+}
+
+// @Summary SAML service provider metadata
+// @ID saml-service-provider-metadata
+// @Security CoderSessionToken
+// @Tags Users
+// @Success 200
+// @Router /users/saml/metadata [get]
+func (api *API) userSAMLMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "SAML is not configured!",
+		})
+		return
+	}
+
+	metadata, err := api.SAMLConfig.ServiceProvider.Metadata()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating SAML metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.Header().Set("Content-Type", "application/samlmetadata+xml")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(metadata)
+}
+
+// @Summary SAML login
+// @ID saml-login
+// @Security CoderSessionToken
+// @Tags Users
+// @Param redirect query string false "Redirect after login"
+// @Success 307
+// @Router /users/saml/login [get]
+func (api *API) userSAMLLogin(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML is not configured!",
+		})
+		return
+	}
+
+	requestID, err := saml.NewRequestID()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating SAML request ID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Only relative redirects are kept to avoid sending users to arbitrary
+	// sites after they log in.
+	redirect := r.URL.Query().Get("redirect")
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") {
+		redirect = ""
+	}
+	authnURL, err := api.SAMLConfig.ServiceProvider.AuthnRequestURL(requestID, redirect)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating SAML request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	http.SetCookie(rw, api.samlRequestCookie(requestID, int((10*time.Minute).Seconds())))
+	http.Redirect(rw, r, authnURL, http.StatusTemporaryRedirect)
+}
+
+// samlRequestCookie stores the ID of the pending authentication request. The
+// identity provider posts its response cross-site, so the cookie can't be
+// restricted to same-site requests when the deployment is served over HTTPS.
+func (api *API) samlRequestCookie(requestID string, maxAge int) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     codersdk.SAMLRequestCookie,
+		Value:    requestID,
+		Path:     "/api/v2/users/saml",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   api.SecureAuthCookie,
+	}
+	if api.SecureAuthCookie {
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	return cookie
+}
+
+// @Summary SAML assertion consumer service
+// @ID saml-assertion-consumer-service
+// @Security CoderSessionToken
+// @Tags Users
+// @Param SAMLResponse formData string true "Base64 encoded SAML response"
+// @Param RelayState formData string false "Redirect after login"
+// @Success 303
+// @Router /users/saml/acs [post]
+func (api *API) userSAMLACS(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// userSAMLACS is a system function.
+		//nolint:gocritic
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if api.SAMLConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "SAML is not configured!",
+		})
+		return
+	}
+
+	requestCookie, err := r.Cookie(codersdk.SAMLRequestCookie)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: fmt.Sprintf("Cookie %q must be provided.", codersdk.SAMLRequestCookie),
+		})
+		return
+	}
+	// The request ID can only be used once.
+	http.SetCookie(rw, api.samlRequestCookie("", -1))
+
+	err = r.ParseForm()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid form.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	assertion, err := api.SAMLConfig.ServiceProvider.ParseResponse(r.PostForm.Get("SAMLResponse"), requestCookie.Value)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to verify SAML response.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	{
+		fields := make([]string, 0, len(assertion.Attributes))
+		for f := range assertion.Attributes {
+			fields = append(fields, f)
+		}
+
+		api.Logger.Debug(ctx, "got saml assertion",
+			slog.F("issuer", assertion.Issuer),
+			slog.F("attribute_fields", fields),
+		)
+	}
+
+	username := assertion.Attribute(api.SAMLConfig.UsernameField)
+	email := assertion.Attribute(api.SAMLConfig.EmailField)
+	if email == "" {
+		// Identity providers commonly use the email as the subject.
+		email = assertion.NameID
+	}
+	_, err = mail.ParseAddress(email)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("No email found in the %q attribute of the SAML assertion!", api.SAMLConfig.EmailField),
+		})
+		return
+	}
+
+	var usingGroups bool
+	var groups []string
+	// If the GroupField is the empty string, then groups from SAML are not
+	// used. This is so we can support manual group assignment.
+	if api.SAMLConfig.GroupField != "" {
+		usingGroups = true
+		for _, group := range assertion.Attributes[api.SAMLConfig.GroupField] {
+			if mappedGroup, ok := api.SAMLConfig.GroupMapping[group]; ok {
+				group = mappedGroup
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	// The username is a required property in Coder. We make a best-effort
+	// attempt at using what the assertion provides, but if that fails we
+	// will generate a username from the email.
+	if httpapi.NameValid(username) != nil {
+		if username == "" {
+			username = email
+		}
+		username = httpapi.UsernameFrom(username)
+	}
+
+	if len(api.SAMLConfig.EmailDomain) > 0 {
+		ok := false
+		for _, domain := range api.SAMLConfig.EmailDomain {
+			if strings.HasSuffix(strings.ToLower(email), strings.ToLower(domain)) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("Your email %q is not in domains %q !", email, api.SAMLConfig.EmailDomain),
+			})
+			return
+		}
+	}
+
+	user, link, err := findLinkedUser(ctx, api.Database, samlLinkedID(assertion), email)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	redirect := r.PostForm.Get("RelayState")
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") {
+		redirect = "/"
+	}
+	cookie, key, err := api.oauthLogin(r, oauthLoginParams{
+		User: user,
+		Link: link,
+		// SAML doesn't issue tokens, so the link has none to store.
+		State: httpmw.OAuth2State{
+			Token:    &oauth2.Token{},
+			Redirect: redirect,
+		},
+		LinkedID:     samlLinkedID(assertion),
+		LoginType:    database.LoginTypeSAML,
+		AllowSignups: api.SAMLConfig.AllowSignups,
+		Email:        email,
+		Username:     username,
+		UsingGroups:  usingGroups,
+		Groups:       groups,
+	})
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		httpapi.Write(ctx, rw, httpErr.code, codersdk.Response{
+			Message: httpErr.msg,
+			Detail:  httpErr.detail,
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process SAML login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	http.SetCookie(rw, cookie)
+	// The response was posted, so the browser must follow up with a GET.
+	http.Redirect(rw, r, redirect, http.StatusSeeOther)
+}
+
+type LDAPConfig struct {
+	Directory    *ldap.Config
+	AllowSignups bool
+	// GroupMapping controls how groups read from the directory get mapped to
+	// groups within Coder. Groups that aren't mapped use the value of the
+	// first component of their DN, e.g. "developers" for
+	// "cn=developers,ou=groups,dc=example,dc=com".
+	// map[ldapGroup]coderGroupName
+	GroupMapping map[string]string
+	// SyncInterval is how often the groups of LDAP users are synced from the
+	// directory, and users removed from it are suspended. Zero disables
+	// syncing.
+	SyncInterval time.Duration
+}
+
+// groups maps the groups of a directory user to groups within Coder.
+func (c *LDAPConfig) groups(user ldap.User) []string {
+	groups := make([]string, 0, len(user.Groups))
+	for _, group := range user.Groups {
+		if mappedGroup, ok := c.GroupMapping[group]; ok {
+			groups = append(groups, mappedGroup)
+			continue
+		}
+		groups = append(groups, ldap.GroupName(group))
+	}
+	return groups
+}
+
+// ldapLogin authenticates a user that submitted the password form against
+// the directory.
+func (api *API) ldapLogin(rw http.ResponseWriter, r *http.Request, aReq *audit.Request[database.APIKey], req codersdk.LoginWithPasswordRequest) {
+	ctx := r.Context()
+	entry, err := api.LDAPConfig.Directory.Authenticate(ctx, req.Email, req.Password)
+	if xerrors.Is(err, ldap.ErrInvalidCredentials) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Incorrect email or password.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to authenticate with the LDAP directory.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	email := entry.Email
+	if email == "" {
+		email = req.Email
+	}
+	username := entry.Username
+	// The username is a required property in Coder. We make a best-effort
+	// attempt at using what the directory provides, but if that fails we
+	// will generate a username from the email.
+	if httpapi.NameValid(username) != nil {
+		if username == "" {
+			username = email
+		}
+		username = httpapi.UsernameFrom(username)
+	}
+
+	//nolint:gocritic // In order to login, we need to get the user first!
+	user, link, err := findLinkedUser(dbauthz.AsSystemRestricted(ctx), api.Database, entry.DN, email)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if user.ID != uuid.Nil && user.Status != database.UserStatusActive {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account is suspended. Contact an admin to reactivate your account.",
+		})
+		return
+	}
+
+	cookie, key, err := api.oauthLogin(r, oauthLoginParams{
+		User: user,
+		Link: link,
+		// LDAP doesn't issue tokens, so the link has none to store.
+		State: httpmw.OAuth2State{
+			Token: &oauth2.Token{},
+		},
+		LinkedID:     entry.DN,
+		LoginType:    database.LoginTypeLDAP,
+		AllowSignups: api.LDAPConfig.AllowSignups,
+		Email:        email,
+		Username:     username,
+		// If no group attribute is read, then groups from the directory are
+		// not used. This is so we can support manual group assignment.
+		UsingGroups: api.LDAPConfig.Directory.GroupAttribute != "",
+		Groups:      api.LDAPConfig.groups(entry),
+	})
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		httpapi.Write(ctx, rw, httpErr.code, codersdk.Response{
+			Message: httpErr.msg,
+			Detail:  httpErr.detail,
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process LDAP login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
 type oauthLoginParams struct {
 	User      database.User
 	Link      database.UserLink
@@ -778,6 +1280,10 @@ type oauthLoginParams struct {
 	// to the Groups provided.
 	UsingGroups bool
 	Groups      []string
+	// If UsingRoles is true, then the site roles of the user will be replaced
+	// with the Roles provided.
+	UsingRoles bool
+	Roles      []string
 }
 
 type httpError struct {
@@ -925,6 +1431,18 @@ func (api *API) oauthLogin(r *http.Request, params oauthLoginParams) (*http.Cook
 			}
 		}
 
+		// Ensure roles are correct.
+		if params.UsingRoles {
+			//nolint:gocritic
+			user, err = tx.UpdateUserRoles(dbauthz.AsSystemRestricted(ctx), database.UpdateUserRolesParams{
+				GrantedRoles: params.Roles,
+				ID:           user.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("set user roles: %w", err)
+			}
+		}
+
 		needsUpdate := false
 		if user.AvatarURL.String != params.AvatarURL {
 			user.AvatarURL = sql.NullString{
@@ -995,6 +1513,11 @@ func oidcLinkedID(tok *oidc.IDToken) string {
 	return strings.Join([]string{tok.Issuer, tok.Subject}, "||")
 }
 
+// samlLinkedID returns the unique ID for a SAML user.
+func samlLinkedID(assertion *saml.Assertion) string {
+	return strings.Join([]string{assertion.Issuer, assertion.NameID}, "||")
+}
+
 // findLinkedUser tries to find a user by their unique OAuth-linked ID.
 // If it doesn't not find it, it returns the user by their email.
 func findLinkedUser(ctx context.Context, db database.Store, linkedID string, emails ...string) (database.User, database.UserLink, error) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -22,6 +23,13 @@ import (
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbgen"
+	"github.com/coder/coder/coderd/database/dbtestutil"
+	"github.com/coder/coder/coderd/ldap"
+	"github.com/coder/coder/coderd/ldap/ldaptest"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/saml"
+	"github.com/coder/coder/coderd/saml/samltest"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/testutil"
 )
@@ -739,6 +747,120 @@ func TestUserOIDC(t *testing.T) {
 		require.Equal(t, database.AuditActionLogin, auditor.AuditLogs()[numLogs-1].Action)
 	})
 
+	t.Run("UserRoleMapping", func(t *testing.T) {
+		t.Parallel()
+		conf := coderdtest.NewOIDCConfig(t, "")
+		config := conf.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+			cfg.AllowSignups = true
+			cfg.UserRoleField = "roles"
+			cfg.UserRoleMapping = map[string][]string{
+				"coder-admins":    {rbac.RoleOwner()},
+				"coder-templates": {rbac.RoleTemplateAdmin(), rbac.RoleMember()},
+			}
+		})
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: config,
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		login := func(roles ...string) []string {
+			t.Helper()
+			resp := oidcCallback(t, client, conf.EncodeClaims(t, jwt.MapClaims{
+				"email": "kyle@kwc.io",
+				"roles": roles,
+			}))
+			require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+			userClient := codersdk.New(client.URL)
+			userClient.SetSessionToken(authCookieValue(resp.Cookies()))
+			user, err := userClient.User(ctx, codersdk.Me)
+			require.NoError(t, err)
+			names := make([]string, 0, len(user.Roles))
+			for _, role := range user.Roles {
+				names = append(names, role.Name)
+			}
+			return names
+		}
+
+		require.ElementsMatch(t, []string{rbac.RoleOwner()}, login("coder-admins", "unmapped"))
+		// Roles are replaced on every login.
+		require.ElementsMatch(t, []string{rbac.RoleTemplateAdmin()}, login("coder-templates"))
+		require.Empty(t, login())
+	})
+
+	t.Run("SuspendRevoked", func(t *testing.T) {
+		t.Parallel()
+		conf := coderdtest.NewOIDCConfig(t, "")
+		config := conf.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+			cfg.AllowSignups = true
+			cfg.RefreshInterval = testutil.IntervalFast
+		})
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: config,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp := oidcCallback(t, client, conf.EncodeClaims(t, jwt.MapClaims{
+			"email": "kyle@kwc.io",
+		}))
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		userClient := codersdk.New(client.URL)
+		userClient.SetSessionToken(authCookieValue(resp.Cookies()))
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		conf.RevokeRefreshTokens()
+		require.Eventually(t, func() bool {
+			user, err = client.User(ctx, user.ID.String())
+			return err == nil && user.Status == codersdk.UserStatusSuspended
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		_, err = userClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+	})
+
+	t.Run("RotateAcrossReplicas", func(t *testing.T) {
+		t.Parallel()
+		conf := coderdtest.NewOIDCConfig(t, "")
+		conf.RotateRefreshTokens()
+		config := conf.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+			cfg.AllowSignups = true
+			cfg.RefreshInterval = testutil.IntervalFast
+		})
+		// Both replicas refresh the same users.
+		db, pubsub := dbtestutil.NewDB(t)
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database:   db,
+			Pubsub:     pubsub,
+			OIDCConfig: config,
+		})
+		_ = coderdtest.New(t, &coderdtest.Options{
+			Database:   db,
+			Pubsub:     pubsub,
+			OIDCConfig: config,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp := oidcCallback(t, client, conf.EncodeClaims(t, jwt.MapClaims{
+			"email": "kyle@kwc.io",
+		}))
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		userClient := codersdk.New(client.URL)
+		userClient.SetSessionToken(authCookieValue(resp.Cookies()))
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			user, err = client.User(ctx, user.ID.String())
+			if err != nil {
+				return false
+			}
+			return user.Status != codersdk.UserStatusActive || conf.RefreshTokenRotations() >= 10
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, codersdk.UserStatusActive, user.Status)
+	})
+
 	t.Run("Disabled", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -785,6 +907,412 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+func TestUserSAML(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, mutate func(cfg *coderd.SAMLConfig)) (*codersdk.Client, *samltest.IdentityProvider, *audit.MockAuditor) {
+		t.Helper()
+		idp := samltest.New(t)
+		config := &coderd.SAMLConfig{
+			ServiceProvider: &saml.ServiceProvider{
+				EntityID:         "https://coder.com/api/v2/users/saml/metadata",
+				IdentityProvider: idp.Config(),
+			},
+			AllowSignups:  true,
+			UsernameField: "uid",
+			EmailField:    "email",
+			GroupField:    "groups",
+		}
+		if mutate != nil {
+			mutate(config)
+		}
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor:    auditor,
+			SAMLConfig: config,
+		})
+		acsURL, err := client.URL.Parse("/api/v2/users/saml/acs")
+		require.NoError(t, err)
+		config.ServiceProvider.ACSURL = acsURL.String()
+		return client, idp, auditor
+	}
+
+	t.Run("AuthMethods", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, func(cfg *coderd.SAMLConfig) {
+			cfg.SignInText = "Sign in with Okta"
+		})
+
+		methods, err := client.AuthMethods(testutil.Context(t, testutil.WaitLong))
+		require.NoError(t, err)
+		require.True(t, methods.SAML.Enabled)
+		require.Equal(t, "Sign in with Okta", methods.SAML.SignInText)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, nil)
+
+		res, err := client.Request(testutil.Context(t, testutil.WaitLong), http.MethodGet, "/api/v2/users/saml/metadata", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "application/samlmetadata+xml", res.Header.Get("Content-Type"))
+		data, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "/api/v2/users/saml/acs")
+	})
+
+	t.Run("Signup", func(t *testing.T) {
+		t.Parallel()
+		client, idp, auditor := setup(t, nil)
+
+		resp := samlLogin(t, client, idp, "kyle", map[string][]string{
+			"uid":    {"kyle"},
+			"email":  {"kyle@coder.com"},
+			"groups": {"admins"},
+		})
+		require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+		require.Equal(t, "/workspaces", resp.Header.Get("Location"))
+
+		client.SetSessionToken(authCookieValue(resp.Cookies()))
+		user, err := client.User(testutil.Context(t, testutil.WaitLong), "me")
+		require.NoError(t, err)
+		require.Equal(t, "kyle", user.Username)
+		require.Equal(t, "kyle@coder.com", user.Email)
+
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		require.Equal(t, database.AuditActionLogin, logs[len(logs)-1].Action)
+		require.Equal(t, user.ID, logs[len(logs)-1].UserID)
+	})
+
+	t.Run("EmailFromSubject", func(t *testing.T) {
+		t.Parallel()
+		client, idp, _ := setup(t, nil)
+
+		resp := samlLogin(t, client, idp, "kyle@coder.com", nil)
+		require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+
+		client.SetSessionToken(authCookieValue(resp.Cookies()))
+		user, err := client.User(testutil.Context(t, testutil.WaitLong), "me")
+		require.NoError(t, err)
+		require.Equal(t, "kyle", user.Username)
+		require.Equal(t, "kyle@coder.com", user.Email)
+	})
+
+	t.Run("NoEmail", func(t *testing.T) {
+		t.Parallel()
+		client, idp, _ := setup(t, nil)
+
+		resp := samlLogin(t, client, idp, "kyle", nil)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("EmailDomain", func(t *testing.T) {
+		t.Parallel()
+		client, idp, _ := setup(t, func(cfg *coderd.SAMLConfig) {
+			cfg.EmailDomain = []string{"coder.com"}
+		})
+
+		resp := samlLogin(t, client, idp, "kyle", map[string][]string{
+			"email": {"kyle@example.com"},
+		})
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("BlockSignups", func(t *testing.T) {
+		t.Parallel()
+		client, idp, _ := setup(t, func(cfg *coderd.SAMLConfig) {
+			cfg.AllowSignups = false
+		})
+
+		resp := samlLogin(t, client, idp, "kyle", map[string][]string{
+			"email": {"kyle@coder.com"},
+		})
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("UntrustedIdentityProvider", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, nil)
+
+		resp := samlLogin(t, client, samltest.New(t), "kyle", map[string][]string{
+			"email": {"kyle@coder.com"},
+		})
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("NoRequestCookie", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, nil)
+
+		acsURL, err := client.URL.Parse("/api/v2/users/saml/acs")
+		require.NoError(t, err)
+		resp, err := client.HTTPClient.PostForm(acsURL.String(), url.Values{
+			"SAMLResponse": {"asdf"},
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+
+		methods, err := client.AuthMethods(testutil.Context(t, testutil.WaitLong))
+		require.NoError(t, err)
+		require.False(t, methods.SAML.Enabled)
+
+		res, err := client.Request(testutil.Context(t, testutil.WaitLong), http.MethodGet, "/api/v2/users/saml/metadata", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+
+	const kyleDN = "uid=kyle,ou=people," + ldaptest.BaseDN
+	setup := func(t *testing.T, mutate func(cfg *coderd.LDAPConfig)) (*codersdk.Client, *ldaptest.Directory, *audit.MockAuditor) {
+		t.Helper()
+		dir := ldaptest.New(t)
+		dir.Put(kyleDN, "password", map[string][]string{
+			"uid":  {"kyle"},
+			"mail": {"kyle@coder.com"},
+		})
+		config := &coderd.LDAPConfig{
+			Directory: &ldap.Config{
+				URL:               dir.URL,
+				BindDN:            ldaptest.BindDN,
+				BindPassword:      ldaptest.BindPassword,
+				UserBaseDN:        ldaptest.BaseDN,
+				UserFilter:        "(mail={email})",
+				UsernameAttribute: "uid",
+				EmailAttribute:    "mail",
+			},
+			AllowSignups: true,
+		}
+		if mutate != nil {
+			mutate(config)
+		}
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor:    auditor,
+			LDAPConfig: config,
+		})
+		return client, dir, auditor
+	}
+
+	t.Run("AuthMethods", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, nil)
+
+		methods, err := client.AuthMethods(testutil.Context(t, testutil.WaitLong))
+		require.NoError(t, err)
+		require.True(t, methods.LDAP.Enabled)
+		require.True(t, methods.Password.Enabled)
+	})
+
+	t.Run("Signup", func(t *testing.T) {
+		t.Parallel()
+		client, _, auditor := setup(t, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		require.NoError(t, err)
+		client.SetSessionToken(res.SessionToken)
+		user, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, "kyle", user.Username)
+		require.Equal(t, "kyle@coder.com", user.Email)
+
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		require.Equal(t, database.AuditActionLogin, logs[len(logs)-1].Action)
+		require.Equal(t, user.ID, logs[len(logs)-1].UserID)
+
+		// Logging in again links to the same user.
+		res, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		require.NoError(t, err)
+		client.SetSessionToken(res.SessionToken)
+		again, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, again.ID)
+	})
+
+	t.Run("EmailChanged", func(t *testing.T) {
+		t.Parallel()
+		client, dir, _ := setup(t, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		require.NoError(t, err)
+
+		dir.Put(kyleDN, "password", map[string][]string{
+			"uid":  {"kyle"},
+			"mail": {"kyle@example.com"},
+		})
+		res, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@example.com",
+			Password: "password",
+		})
+		require.NoError(t, err)
+		client.SetSessionToken(res.SessionToken)
+		user, err := client.User(ctx, "me")
+		require.NoError(t, err)
+		require.Equal(t, "kyle", user.Username)
+		require.Equal(t, "kyle@example.com", user.Email)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, nil)
+
+		_, err := client.LoginWithPassword(testutil.Context(t, testutil.WaitLong), codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "wrong",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("BlockSignups", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, func(cfg *coderd.LDAPConfig) {
+			cfg.AllowSignups = false
+		})
+
+		_, err := client.LoginWithPassword(testutil.Context(t, testutil.WaitLong), codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("PasswordUser", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		// Users with passwords stored in Coder aren't affected by LDAP.
+		_, err := client.LoginWithPassword(testutil.Context(t, testutil.WaitLong), codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("SuspendRemoved", func(t *testing.T) {
+		t.Parallel()
+		client, dir, _ := setup(t, func(cfg *coderd.LDAPConfig) {
+			cfg.SyncInterval = testutil.IntervalFast
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		require.NoError(t, err)
+		kyle := codersdk.New(client.URL)
+		kyle.SetSessionToken(res.SessionToken)
+		user, err := kyle.User(ctx, "me")
+		require.NoError(t, err)
+
+		dir.Delete(kyleDN)
+		require.Eventually(t, func() bool {
+			user, err = client.User(ctx, user.ID.String())
+			return err == nil && user.Status == codersdk.UserStatusSuspended
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		_, err = kyle.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("SyncPastUserErrors", func(t *testing.T) {
+		t.Parallel()
+		db, pubsub := dbtestutil.NewDB(t)
+		dir := ldaptest.New(t)
+		dir.Put(kyleDN, "password", map[string][]string{
+			"uid":  {"kyle"},
+			"mail": {"kyle@coder.com"},
+		})
+		client := coderdtest.New(t, &coderdtest.Options{
+			Database: db,
+			Pubsub:   pubsub,
+			LDAPConfig: &coderd.LDAPConfig{
+				Directory: &ldap.Config{
+					URL:               dir.URL,
+					BindDN:            ldaptest.BindDN,
+					BindPassword:      ldaptest.BindPassword,
+					UserBaseDN:        ldaptest.BaseDN,
+					UserFilter:        "(mail={email})",
+					UsernameAttribute: "uid",
+					EmailAttribute:    "mail",
+				},
+				AllowSignups: true,
+				SyncInterval: testutil.IntervalFast,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// A user without a link fails to sync before the others are synced.
+		broken := dbgen.User(t, db, database.User{
+			LoginType: database.LoginTypeLDAP,
+		})
+		res, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		require.NoError(t, err)
+		kyle := codersdk.New(client.URL)
+		kyle.SetSessionToken(res.SessionToken)
+		user, err := kyle.User(ctx, "me")
+		require.NoError(t, err)
+
+		dir.Delete(kyleDN)
+		require.Eventually(t, func() bool {
+			user, err = client.User(ctx, user.ID.String())
+			return err == nil && user.Status == codersdk.UserStatusSuspended
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		brokenUser, err := client.User(ctx, broken.ID.String())
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusActive, brokenUser.Status)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+
+		methods, err := client.AuthMethods(testutil.Context(t, testutil.WaitLong))
+		require.NoError(t, err)
+		require.False(t, methods.LDAP.Enabled)
+	})
+}
+
 func oauth2Callback(t *testing.T, client *codersdk.Client) *http.Response {
 	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
@@ -829,6 +1357,46 @@ func oidcCallback(t *testing.T, client *codersdk.Client, code string) *http.Resp
 	return res
 }
 
+// samlLogin starts a SAML login and posts the response of the identity
+// provider to the assertion consumer service.
+func samlLogin(t *testing.T, client *codersdk.Client, idp *samltest.IdentityProvider, nameID string, attributes map[string][]string) *http.Response {
+	t.Helper()
+	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	loginURL, err := client.URL.Parse("/api/v2/users/saml/login?redirect=%2Fworkspaces")
+	require.NoError(t, err)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loginURL.String(), nil)
+	require.NoError(t, err)
+	res, err := client.HTTPClient.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusTemporaryRedirect, res.StatusCode)
+	authnRequest := samltest.ParseRequest(t, res.Header.Get("Location"))
+
+	acsURL, err := client.URL.Parse("/api/v2/users/saml/acs")
+	require.NoError(t, err)
+	form := url.Values{
+		"SAMLResponse": {idp.Sign(t, authnRequest.RespondTo(nameID, attributes))},
+		"RelayState":   {authnRequest.RelayState},
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, acsURL.String(), strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, cookie := range res.Cookies() {
+		req.AddCookie(cookie)
+	}
+	res, err = client.HTTPClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	t.Log(string(data))
+	return res
+}
+
 func i64ptr(i int64) *int64 {
 	return &i
 }
@@ -284,9 +284,32 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loginType := database.LoginTypePassword
+	if req.ServiceAccount != nil {
+		loginType = database.LoginTypeNone
+		if req.Password != "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Service accounts cannot have a password.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "password",
+					Detail: "Must be empty for service accounts.",
+				}},
+			})
+			return
+		}
+		err := api.validateServiceAccountOwner(ctx, httpmw.UserAuthorization(r).Actor.ID, req.ServiceAccount)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid service account owner.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
 	// If password auth is disabled, don't allow new users to be
 	// created with a password!
-	if api.DeploymentValues.DisablePasswordAuth {
+	if loginType == database.LoginTypePassword && api.DeploymentValues.DisablePasswordAuth {
 		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
 			Message: "You cannot manually provision new users with password authentication disabled!",
 		})
@@ -328,21 +351,23 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = userpassword.Validate(req.Password)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Password not strong enough!",
-			Validations: []codersdk.ValidationError{{
-				Field:  "password",
-				Detail: err.Error(),
-			}},
-		})
-		return
+	if loginType == database.LoginTypePassword {
+		err = userpassword.Validate(req.Password)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Password not strong enough!",
+				Validations: []codersdk.ValidationError{{
+					Field:  "password",
+					Detail: err.Error(),
+				}},
+			})
+			return
+		}
 	}
 
 	user, _, err := api.CreateUser(ctx, api.Database, CreateUserRequest{
 		CreateUserRequest: req,
-		LoginType:         database.LoginTypePassword,
+		LoginType:         loginType,
 	})
 	if dbauthz.IsNotAuthorizedError(err) {
 		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
@@ -1036,6 +1061,22 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 		if err != nil {
 			return xerrors.Errorf("create organization member: %w", err)
 		}
+		if req.ServiceAccount != nil {
+			params := database.InsertServiceAccountParams{
+				UserID:    user.ID,
+				CreatedAt: database.Now(),
+			}
+			if req.ServiceAccount.OwnerUserID != nil {
+				params.OwnerUserID = uuid.NullUUID{UUID: *req.ServiceAccount.OwnerUserID, Valid: true}
+			}
+			if req.ServiceAccount.OwnerGroupID != nil {
+				params.OwnerGroupID = uuid.NullUUID{UUID: *req.ServiceAccount.OwnerGroupID, Valid: true}
+			}
+			_, err = tx.InsertServiceAccount(ctx, params)
+			if err != nil {
+				return xerrors.Errorf("insert service account: %w", err)
+			}
+		}
 		return nil
 	}, nil)
 }
@@ -1051,6 +1092,7 @@ func convertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User
 		OrganizationIDs: organizationIDs,
 		Roles:           make([]codersdk.Role, 0, len(user.RBACRoles)),
 		AvatarURL:       user.AvatarURL.String,
+		ServiceAccount:  user.LoginType == database.LoginTypeNone,
 	}
 
 	for _, roleName := range user.RBACRoles {
@@ -1103,5 +1145,6 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		Scope:           codersdk.APIKeyScope(k.Scope),
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
+		Scopes:          k.CustomScopes,
 	}
 }
@@ -0,0 +1,232 @@
+package coderd
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/tailnet"
+)
+
+// @Summary Get workspace agent peers
+// @ID get-workspace-agent-peers
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {array} agentsdk.Peer
+// @Router /workspaceagents/me/peers [get]
+func (api *API) workspaceAgentPeers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	workspace, ok := api.workspaceAgentPeeringWorkspace(rw, r, workspaceAgent)
+	if !ok {
+		return
+	}
+
+	peers, err := api.workspaceAgentPeerList(ctx, workspace, workspaceAgent)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent peers.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, peers)
+}
+
+// @Summary Coordinate workspace agent peer
+// @ID coordinate-workspace-agent-peer
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 101
+// @Router /workspaceagents/me/peers/{workspaceagent}/coordinate [get]
+func (api *API) workspaceAgentPeerCoordinate(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	workspace, ok := api.workspaceAgentPeeringWorkspace(rw, r, workspaceAgent)
+	if !ok {
+		return
+	}
+	peerID, err := uuid.Parse(chi.URLParam(r, "workspaceagent"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid workspace agent ID.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	peers, err := api.workspaceAgentPeerList(ctx, workspace, workspaceAgent)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent peers.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	allowed := false
+	for _, peer := range peers {
+		if peer.AgentID == peerID {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	// Peering must respect the same restrictions as any other client.
+	override := api.WorkspaceClientCoordinateOverride.Load()
+	if override != nil {
+		overrideFunc := *override
+		if overrideFunc != nil && overrideFunc(rw) {
+			return
+		}
+	}
+
+	api.WebsocketWaitMutex.Lock()
+	api.WebsocketWaitGroup.Add(1)
+	api.WebsocketWaitMutex.Unlock()
+	defer api.WebsocketWaitGroup.Done()
+
+	conn, err := websocket.Accept(rw, r, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	ctx, wsNetConn := websocketNetConn(ctx, conn, websocket.MessageBinary)
+	defer wsNetConn.Close()
+
+	go httpapi.Heartbeat(ctx, conn)
+
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), peerID)
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, err.Error())
+		return
+	}
+}
+
+// workspaceAgentPeeringWorkspace returns the workspace of the agent if both
+// the deployment and the workspace enabled peering. Otherwise, it writes an
+// error response.
+func (api *API) workspaceAgentPeeringWorkspace(rw http.ResponseWriter, r *http.Request, workspaceAgent database.WorkspaceAgent) (database.Workspace, bool) {
+	ctx := r.Context()
+	if !api.DeploymentValues.WorkspacePeering.Value() {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Workspace peering is disabled.",
+		})
+		return database.Workspace{}, false
+	}
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return database.Workspace{}, false
+	}
+	if !workspace.PeeringEnabled {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Workspace peering is disabled for this workspace.",
+		})
+		return database.Workspace{}, false
+	}
+	return workspace, true
+}
+
+// workspaceAgentPeerList returns the agents of the other running workspaces
+// that share an owner with the agent's workspace and enabled peering.
+func (api *API) workspaceAgentPeerList(ctx context.Context, workspace database.Workspace, workspaceAgent database.WorkspaceAgent) ([]agentsdk.Peer, error) {
+	// The agent is scoped to its own workspace, so listing the owner's other
+	// workspaces needs the system.
+	//nolint:gocritic // Peers are limited to workspaces of the same owner.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	workspaces, err := api.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		OwnerID: workspace.OwnerID,
+		Status:  string(database.WorkspaceStatusRunning),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get workspaces: %w", err)
+	}
+	workspaceNames := make(map[uuid.UUID]string, len(workspaces))
+	workspaceIDs := make([]uuid.UUID, 0, len(workspaces))
+	for _, ws := range workspaces {
+		if !ws.PeeringEnabled {
+			continue
+		}
+		workspaceNames[ws.ID] = ws.Name
+		workspaceIDs = append(workspaceIDs, ws.ID)
+	}
+	if len(workspaceIDs) == 0 {
+		return []agentsdk.Peer{}, nil
+	}
+
+	builds, err := api.Database.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, workspaceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace builds: %w", err)
+	}
+	jobWorkspaces := make(map[uuid.UUID]uuid.UUID, len(builds))
+	jobIDs := make([]uuid.UUID, 0, len(builds))
+	for _, build := range builds {
+		jobWorkspaces[build.JobID] = build.WorkspaceID
+		jobIDs = append(jobIDs, build.JobID)
+	}
+	resources, err := api.Database.GetWorkspaceResourcesByJobIDs(ctx, jobIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace resources: %w", err)
+	}
+	resourceWorkspaces := make(map[uuid.UUID]uuid.UUID, len(resources))
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceWorkspaces[resource.ID] = jobWorkspaces[resource.JobID]
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace agents: %w", err)
+	}
+
+	agentCounts := map[uuid.UUID]int{}
+	for _, agent := range agents {
+		agentCounts[resourceWorkspaces[agent.ResourceID]]++
+	}
+	peers := make([]agentsdk.Peer, 0, len(agents))
+	for _, agent := range agents {
+		if agent.ID == workspaceAgent.ID {
+			continue
+		}
+		workspaceID := resourceWorkspaces[agent.ResourceID]
+		workspaceName := workspaceNames[workspaceID]
+		hostnames := []string{agent.Name + "." + workspaceName + ".coder"}
+		if agentCounts[workspaceID] == 1 {
+			hostnames = append([]string{workspaceName + ".coder"}, hostnames...)
+		}
+		peers = append(peers, agentsdk.Peer{
+			AgentID:       agent.ID,
+			AgentName:     agent.Name,
+			WorkspaceName: workspaceName,
+			IP:            tailnet.IPFromUUID(agent.ID),
+			Hostnames:     hostnames,
+		})
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Hostnames[0] < peers[j].Hostnames[0]
+	})
+	return peers, nil
+}
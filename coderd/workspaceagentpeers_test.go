@@ -0,0 +1,87 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspaceAgentPeers(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	dv := coderdtest.DeploymentValues(t)
+	dv.WorkspacePeering = true
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		DeploymentValues:         dv,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	createWorkspace := func() (codersdk.Workspace, uuid.UUID, *agentsdk.Client) {
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		return workspace, build.Resources[0].Agents[0].ID, agentClient
+	}
+	frontend, frontendAgentID, frontendAgent := createWorkspace()
+	backend, backendAgentID, backendAgent := createWorkspace()
+	require.False(t, frontend.PeeringEnabled)
+
+	// Workspaces must opt in to peering.
+	_, err := frontendAgent.Peers(ctx)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.UpdateWorkspacePeering(ctx, frontend.ID, codersdk.UpdateWorkspacePeeringRequest{Enabled: true})
+	require.NoError(t, err)
+	frontend, err = client.Workspace(ctx, frontend.ID)
+	require.NoError(t, err)
+	require.True(t, frontend.PeeringEnabled)
+
+	// The backend hasn't opted in, so it isn't a peer.
+	peers, err := frontendAgent.Peers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, peers)
+	_, err = frontendAgent.ListenPeer(ctx, backendAgentID)
+	require.Error(t, err)
+
+	err = client.UpdateWorkspacePeering(ctx, backend.ID, codersdk.UpdateWorkspacePeeringRequest{Enabled: true})
+	require.NoError(t, err)
+	peers, err = frontendAgent.Peers(ctx)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	require.Equal(t, backendAgentID, peers[0].AgentID)
+	peers, err = backendAgent.Peers(ctx)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	require.Equal(t, frontendAgentID, peers[0].AgentID)
+
+	// Opting out again removes the workspace from the peers of others.
+	err = client.UpdateWorkspacePeering(ctx, frontend.ID, codersdk.UpdateWorkspacePeeringRequest{Enabled: false})
+	require.NoError(t, err)
+	peers, err = backendAgent.Peers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, peers)
+	_, err = backendAgent.ListenPeer(ctx, frontendAgentID)
+	require.Error(t, err)
+}
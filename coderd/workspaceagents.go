@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slices"
 	"golang.org/x/mod/semver"
+	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 	"tailscale.com/tailcfg"
@@ -175,6 +176,8 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		ShutdownScript:        apiAgent.ShutdownScript,
 		ShutdownScriptTimeout: time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
 		Metadata:              convertWorkspaceAgentMetadataDesc(metadata),
+		AgentID:               workspaceAgent.ID,
+		WorkspacePeering:      api.DeploymentValues.WorkspacePeering.Value(),
 	})
 }
 
@@ -805,16 +808,32 @@ func (api *API) workspaceAgentConnection(rw http.ResponseWriter, r *http.Request
 	})
 }
 
+// @Summary Get DERP map
+// @ID get-derp-map
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} tailcfg.DERPMap
+// @Router /derp-map [get]
+func (api *API) derpMap(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, api.DERPMap)
+}
+
 // @Summary Coordinate workspace agent via Tailnet
 // @Description It accepts a WebSocket connection to an agent that listens to
 // @Description incoming connections and publishes node updates.
 // @ID coordinate-workspace-agent-via-tailnet
 // @Security CoderSessionToken
 // @Tags Agents
+// @Param resume_version query int false "Highest node version received before reconnecting"
 // @Success 101
 // @Router /workspaceagents/me/coordinate [get]
 func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	resumeVersion, ok := parseResumeVersion(rw, r)
+	if !ok {
+		return
+	}
 
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Add(1)
@@ -997,6 +1016,11 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 		_ = conn.Close(websocket.StatusGoingAway, err.Error())
 		return
 	}
+	if !workspaceAgent.FirstConnectedAt.Valid {
+		// The agent was created when the build completed, so this is how
+		// long the workspace took to boot and run the agent.
+		api.recordAgentBuildTiming(ctx, workspaceAgent, database.WorkspaceBuildStageAgentConnect, workspaceAgent.CreatedAt, firstConnectedAt)
+	}
 	api.publishWorkspaceUpdate(ctx, build.WorkspaceID)
 
 	// End span so we don't get long lived trace data.
@@ -1011,9 +1035,14 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 	closeChan := make(chan struct{})
 	go func() {
 		defer close(closeChan)
-		err := (*api.TailnetCoordinator.Load()).ServeAgent(wsNetConn, workspaceAgent.ID,
-			fmt.Sprintf("%s-%s-%s", owner.Username, workspace.Name, workspaceAgent.Name),
-		)
+		name := fmt.Sprintf("%s-%s-%s", owner.Username, workspace.Name, workspaceAgent.Name)
+		var err error
+		coordinator := *api.TailnetCoordinator.Load()
+		if resuming, ok := coordinator.(tailnet.ResumingCoordinator); ok {
+			err = resuming.ServeAgentResume(wsNetConn, workspaceAgent.ID, name, resumeVersion)
+		} else {
+			err = coordinator.ServeAgent(wsNetConn, workspaceAgent.ID, name)
+		}
 		if err != nil {
 			api.Logger.Warn(ctx, "tailnet coordinator agent error", slog.Error(err))
 			_ = conn.Close(websocket.StatusInternalError, err.Error())
@@ -1077,6 +1106,7 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 // @Security CoderSessionToken
 // @Tags Agents
 // @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param resume_version query int false "Highest node version received before reconnecting"
 // @Success 101
 // @Router /workspaceagents/{workspaceagent}/coordinate [get]
 func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.Request) {
@@ -1095,6 +1125,10 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 			return
 		}
 	}
+	resumeVersion, ok := parseResumeVersion(rw, r)
+	if !ok {
+		return
+	}
 
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Add(1)
@@ -1116,13 +1150,40 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	go httpapi.Heartbeat(ctx, conn)
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+	coordinator := *api.TailnetCoordinator.Load()
+	if resuming, ok := coordinator.(tailnet.ResumingCoordinator); ok {
+		err = resuming.ServeClientResume(wsNetConn, uuid.New(), workspaceAgent.ID, resumeVersion)
+	} else {
+		err = coordinator.ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+	}
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
 	}
 }
 
+// parseResumeVersion extracts the node version a peer resumes from when it
+// reconnects to the coordinator. If an error is encountered, the error is
+// written to rw and ok is set to false.
+func parseResumeVersion(rw http.ResponseWriter, r *http.Request) (version int64, ok bool) {
+	parser := httpapi.NewQueryParamParser()
+	resumeVersion := parser.Int(r.URL.Query(), 0, "resume_version")
+	if resumeVersion < 0 {
+		parser.Errors = append(parser.Errors, codersdk.ValidationError{
+			Field:  "resume_version",
+			Detail: "Query param \"resume_version\" must not be negative",
+		})
+	}
+	if len(parser.Errors) > 0 {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return 0, false
+	}
+	return int64(resumeVersion), true
+}
+
 func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 	apps := make([]codersdk.WorkspaceApp, 0)
 	for _, dbApp := range dbApps {
@@ -1598,11 +1659,119 @@ func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Re
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+
+	now := database.Now()
+	switch lifecycleState {
+	case database.WorkspaceAgentLifecycleStateStarting:
+		api.recordAgentBuildTiming(ctx, workspaceAgent, database.WorkspaceBuildStageStartupScript, now, sql.NullTime{})
+	case database.WorkspaceAgentLifecycleStateReady,
+		database.WorkspaceAgentLifecycleStateStartTimeout,
+		database.WorkspaceAgentLifecycleStateStartError:
+		api.recordAgentBuildTiming(ctx, workspaceAgent, database.WorkspaceBuildStageStartupScript, now, sql.NullTime{Time: now, Valid: true})
+	}
 	api.publishWorkspaceUpdate(ctx, workspace.ID)
 
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// recordAgentBuildTiming records an agent stage against the build that
+// created the agent. Timings are informational, so failures are only logged.
+func (api *API) recordAgentBuildTiming(ctx context.Context, agent database.WorkspaceAgent, stage database.WorkspaceBuildStage, startedAt time.Time, endedAt sql.NullTime) {
+	//nolint:gocritic // Build timings are system data recorded on behalf of the agent.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	err := func() error {
+		resource, err := api.Database.GetWorkspaceResourceByID(ctx, agent.ResourceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace resource: %w", err)
+		}
+		build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+		if err != nil {
+			return xerrors.Errorf("get workspace build: %w", err)
+		}
+		err = api.Database.UpsertWorkspaceBuildTiming(ctx, database.UpsertWorkspaceBuildTimingParams{
+			WorkspaceBuildID: build.ID,
+			Stage:            stage,
+			StartedAt:        startedAt,
+			EndedAt:          endedAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert workspace build timing: %w", err)
+		}
+		if !endedAt.Valid {
+			return nil
+		}
+		return api.observeAgentBuildStage(ctx, build, stage)
+	}()
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to record workspace build timing",
+			slog.F("agent", agent.ID),
+			slog.F("stage", stage),
+			slog.Error(err),
+		)
+	}
+}
+
+// observeAgentBuildStage observes an agent stage of the build once every agent
+// has finished it. Agents can finish on different replicas at the same time,
+// so the stage is claimed in the database before it's observed.
+func (api *API) observeAgentBuildStage(ctx context.Context, build database.WorkspaceBuild, stage database.WorkspaceBuildStage) error {
+	if api.BuildStageMetrics == nil {
+		return nil
+	}
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(ctx, build.JobID)
+	if err != nil {
+		return xerrors.Errorf("get workspace resources: %w", err)
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		return xerrors.Errorf("get workspace agents: %w", err)
+	}
+	for _, agent := range agents {
+		if !agentFinishedBuildStage(agent, stage) {
+			return nil
+		}
+	}
+
+	timing, err := api.Database.UpdateWorkspaceBuildTimingObserved(ctx, database.UpdateWorkspaceBuildTimingObservedParams{
+		WorkspaceBuildID: build.ID,
+		Stage:            stage,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// Another agent of the build already observed the stage.
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("claim workspace build timing: %w", err)
+	}
+	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return xerrors.Errorf("get template: %w", err)
+	}
+	api.BuildStageMetrics.Observe(template.Name, stage, timing.StartedAt, timing.EndedAt.Time)
+	return nil
+}
+
+// agentFinishedBuildStage returns whether the agent is past an agent stage.
+func agentFinishedBuildStage(agent database.WorkspaceAgent, stage database.WorkspaceBuildStage) bool {
+	switch stage {
+	case database.WorkspaceBuildStageAgentConnect:
+		return agent.FirstConnectedAt.Valid
+	case database.WorkspaceBuildStageStartupScript:
+		return agent.LifecycleState != database.WorkspaceAgentLifecycleStateCreated &&
+			agent.LifecycleState != database.WorkspaceAgentLifecycleStateStarting
+	default:
+		return true
+	}
+}
+
 // @Summary Submit workspace agent application health
 // @ID submit-workspace-agent-application-health
 // @Security CoderSessionToken
@@ -1752,44 +1921,12 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 	// new token to be issued!
 	listen := r.URL.Query().Has("listen")
 
-	var gitAuthConfig *gitauth.Config
-	for _, gitAuth := range api.GitAuthConfigs {
-		matches := gitAuth.Regex.MatchString(gitURL)
-		if !matches {
-			continue
-		}
-		gitAuthConfig = gitAuth
-	}
-	if gitAuthConfig == nil {
-		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
-			Message: fmt.Sprintf("No git provider found for URL %q", gitURL),
-		})
-		return
-	}
-	workspaceAgent := httpmw.WorkspaceAgent(r)
-	// We must get the workspace to get the owner ID!
-	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to get workspace resource.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to get build.",
-			Detail:  err.Error(),
-		})
+	gitAuthConfig, ok := api.gitAuthConfigForURL(ctx, rw, gitURL)
+	if !ok {
 		return
 	}
-	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to get workspace.",
-			Detail:  err.Error(),
-		})
+	workspace, ok := api.workspaceAgentWorkspace(ctx, rw, httpmw.WorkspaceAgent(r))
+	if !ok {
 		return
 	}
 
@@ -1888,7 +2025,8 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 		}
 
 		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthResponse{
-			URL: redirectURL.String(),
+			URL:        redirectURL.String(),
+			DeviceFlow: gitAuthConfig.DeviceAuth != nil,
 		})
 		return
 	}
@@ -1903,13 +2041,265 @@ func (api *API) workspaceAgentsGitAuth(rw http.ResponseWriter, r *http.Request)
 	}
 	if !updated {
 		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthResponse{
-			URL: redirectURL.String(),
+			URL:        redirectURL.String(),
+			DeviceFlow: gitAuthConfig.DeviceAuth != nil,
 		})
 		return
 	}
 	httpapi.Write(ctx, rw, http.StatusOK, formatGitAuthAccessToken(gitAuthConfig.Type, gitAuthLink.OAuthAccessToken))
 }
 
+// workspaceAgentsGitAuthDevice begins the device flow for the Git provider
+// matching the URL. The user authorizes the device at the verification URI
+// instead of being redirected by a browser.
+//
+// @Summary Begin workspace agent Git auth device flow
+// @ID begin-workspace-agent-git-auth-device-flow
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param url query string true "Git URL" format(uri)
+// @Success 200 {object} codersdk.GitAuthDevice
+// @Router /workspaceagents/me/gitauth/device [get]
+func (api *API) workspaceAgentsGitAuthDevice(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	gitURL := r.URL.Query().Get("url")
+	if gitURL == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Missing 'url' query parameter!",
+		})
+		return
+	}
+	gitAuthConfig, ok := api.gitAuthConfigForURL(ctx, rw, gitURL)
+	if !ok {
+		return
+	}
+	if gitAuthConfig.DeviceAuth == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Git provider %q doesn't use the device flow.", gitAuthConfig.ID),
+		})
+		return
+	}
+
+	device, err := gitAuthConfig.DeviceAuth.AuthorizeDevice(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to authorize device.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, device)
+}
+
+// workspaceAgentsGitAuthDeviceExchange exchanges a device code for a token
+// once the user has authorized the device.
+//
+// @Summary Exchange workspace agent Git auth device code
+// @ID exchange-workspace-agent-git-auth-device-code
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Agents
+// @Param request body agentsdk.GitAuthDeviceExchange true "Device code"
+// @Success 200 {object} agentsdk.GitAuthResponse
+// @Success 202 {object} codersdk.Response
+// @Router /workspaceagents/me/gitauth/device [post]
+func (api *API) workspaceAgentsGitAuthDeviceExchange(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req agentsdk.GitAuthDeviceExchange
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	gitAuthConfig, ok := api.gitAuthConfigForURL(ctx, rw, req.URL)
+	if !ok {
+		return
+	}
+	if gitAuthConfig.DeviceAuth == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Git provider %q doesn't use the device flow.", gitAuthConfig.ID),
+		})
+		return
+	}
+	workspace, ok := api.workspaceAgentWorkspace(ctx, rw, httpmw.WorkspaceAgent(r))
+	if !ok {
+		return
+	}
+
+	token, err := gitAuthConfig.DeviceAuth.ExchangeDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		var deviceErr *gitauth.DeviceAuthError
+		switch {
+		case errors.As(err, &deviceErr) && deviceErr.SlowDown():
+			httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+				Message: "The device code is being exchanged too often.",
+				Detail:  err.Error(),
+			})
+		case errors.As(err, &deviceErr) && deviceErr.Pending():
+			httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.Response{
+				Message: "Waiting for the device to be authorized.",
+				Detail:  err.Error(),
+			})
+		case errors.As(err, &deviceErr):
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Failed to authorize device.",
+				Detail:  err.Error(),
+			})
+		default:
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to exchange device code.",
+				Detail:  err.Error(),
+			})
+		}
+		return
+	}
+
+	err = api.saveGitAuthLink(ctx, gitAuthConfig, workspace.OwnerID, token)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to save git auth link.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, formatGitAuthAccessToken(gitAuthConfig.Type, token.AccessToken))
+}
+
+// workspaceAgentsGitAuthAccessToken returns the access token of the
+// workspace owner for a Git provider, so it can be used for the provider's
+// API and other services that accept it, e.g. container registries.
+//
+// @Summary Get workspace agent Git auth access token
+// @ID get-workspace-agent-git-auth-access-token
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param gitauth path string true "Git auth provider ID"
+// @Success 200 {object} agentsdk.GitAuthAccessTokenResponse
+// @Router /workspaceagents/me/gitauth/{gitauth}/access-token [get]
+func (api *API) workspaceAgentsGitAuthAccessToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	providerID := chi.URLParam(r, "gitauth")
+	var gitAuthConfig *gitauth.Config
+	for _, gitAuth := range api.GitAuthConfigs {
+		if gitAuth.ID == providerID {
+			gitAuthConfig = gitAuth
+			break
+		}
+	}
+	if gitAuthConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("No git provider found with the ID %q", providerID),
+		})
+		return
+	}
+	workspace, ok := api.workspaceAgentWorkspace(ctx, rw, httpmw.WorkspaceAgent(r))
+	if !ok {
+		return
+	}
+
+	// This is the URL that will redirect the user with a state token.
+	redirectURL, err := api.AccessURL.Parse(fmt.Sprintf("/gitauth/%s", gitAuthConfig.ID))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to parse access URL.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	gitAuthLink, err := api.Database.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{
+		ProviderID: gitAuthConfig.ID,
+		UserID:     workspace.OwnerID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get git auth link.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthAccessTokenResponse{
+			URL: redirectURL.String(),
+		})
+		return
+	}
+
+	gitAuthLink, updated, err := gitAuthConfig.RefreshToken(ctx, api.Database, gitAuthLink)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to refresh git auth token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !updated {
+		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthAccessTokenResponse{
+			URL: redirectURL.String(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.GitAuthAccessTokenResponse{
+		AccessToken: gitAuthLink.OAuthAccessToken,
+		Expiry:      gitAuthLink.OAuthExpiry,
+	})
+}
+
+// gitAuthConfigForURL returns the Git provider matching the URL. If multiple
+// providers match, the last one is used.
+func (api *API) gitAuthConfigForURL(ctx context.Context, rw http.ResponseWriter, gitURL string) (*gitauth.Config, bool) {
+	var gitAuthConfig *gitauth.Config
+	for _, gitAuth := range api.GitAuthConfigs {
+		if gitAuth.Regex == nil {
+			continue
+		}
+		matches := gitAuth.Regex.MatchString(gitURL)
+		if !matches {
+			continue
+		}
+		gitAuthConfig = gitAuth
+	}
+	if gitAuthConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("No git provider found for URL %q", gitURL),
+		})
+		return nil, false
+	}
+	return gitAuthConfig, true
+}
+
+// workspaceAgentWorkspace returns the workspace of the agent, which Git
+// tokens are issued to the owner of.
+func (api *API) workspaceAgentWorkspace(ctx context.Context, rw http.ResponseWriter, workspaceAgent database.WorkspaceAgent) (database.Workspace, bool) {
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace resource.",
+			Detail:  err.Error(),
+		})
+		return database.Workspace{}, false
+	}
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get build.",
+			Detail:  err.Error(),
+		})
+		return database.Workspace{}, false
+	}
+	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace.",
+			Detail:  err.Error(),
+		})
+		return database.Workspace{}, false
+	}
+	return workspace, true
+}
+
 // Provider types have different username/password formats.
 func formatGitAuthAccessToken(typ codersdk.GitProvider, token string) agentsdk.GitAuthResponse {
 	var resp agentsdk.GitAuthResponse
@@ -1920,6 +2310,13 @@ func formatGitAuthAccessToken(typ codersdk.GitProvider, token string) agentsdk.G
 			Username: "oauth2",
 			Password: token,
 		}
+	case codersdk.GitProviderGitea:
+		// Gitea checks the password for an OAuth2 token when the username
+		// isn't a token itself.
+		resp = agentsdk.GitAuthResponse{
+			Username: "oauth2",
+			Password: token,
+		}
 	case codersdk.GitProviderBitBucket:
 		// https://support.atlassian.com/bitbucket-cloud/docs/use-oauth-on-bitbucket-cloud/#Cloning-a-repository-with-an-access-token
 		resp = agentsdk.GitAuthResponse{
@@ -1942,57 +2339,10 @@ func (api *API) gitAuthCallback(gitAuthConfig *gitauth.Config) http.HandlerFunc
 			apiKey = httpmw.APIKey(r)
 		)
 
-		_, err := api.Database.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{
-			ProviderID: gitAuthConfig.ID,
-			UserID:     apiKey.UserID,
-		})
-		if err != nil {
-			if !errors.Is(err, sql.ErrNoRows) {
-				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-					Message: "Failed to get git auth link.",
-					Detail:  err.Error(),
-				})
-				return
-			}
-
-			_, err = api.Database.InsertGitAuthLink(ctx, database.InsertGitAuthLinkParams{
-				ProviderID:        gitAuthConfig.ID,
-				UserID:            apiKey.UserID,
-				CreatedAt:         database.Now(),
-				UpdatedAt:         database.Now(),
-				OAuthAccessToken:  state.Token.AccessToken,
-				OAuthRefreshToken: state.Token.RefreshToken,
-				OAuthExpiry:       state.Token.Expiry,
-			})
-			if err != nil {
-				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-					Message: "Failed to insert git auth link.",
-					Detail:  err.Error(),
-				})
-				return
-			}
-		} else {
-			_, err = api.Database.UpdateGitAuthLink(ctx, database.UpdateGitAuthLinkParams{
-				ProviderID:        gitAuthConfig.ID,
-				UserID:            apiKey.UserID,
-				UpdatedAt:         database.Now(),
-				OAuthAccessToken:  state.Token.AccessToken,
-				OAuthRefreshToken: state.Token.RefreshToken,
-				OAuthExpiry:       state.Token.Expiry,
-			})
-			if err != nil {
-				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-					Message: "Failed to update git auth link.",
-					Detail:  err.Error(),
-				})
-				return
-			}
-		}
-
-		err = api.Pubsub.Publish("gitauth", []byte(fmt.Sprintf("%s|%s", gitAuthConfig.ID, apiKey.UserID)))
+		err := api.saveGitAuthLink(ctx, gitAuthConfig, apiKey.UserID, state.Token)
 		if err != nil {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-				Message: "Failed to publish auth update.",
+				Message: "Failed to save git auth link.",
 				Detail:  err.Error(),
 			})
 			return
@@ -2007,6 +2357,51 @@ func (api *API) gitAuthCallback(gitAuthConfig *gitauth.Config) http.HandlerFunc
 	}
 }
 
+// saveGitAuthLink stores the token of the user for the Git provider, and
+// notifies agents waiting for it.
+func (api *API) saveGitAuthLink(ctx context.Context, gitAuthConfig *gitauth.Config, userID uuid.UUID, token *oauth2.Token) error {
+	_, err := api.Database.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{
+		ProviderID: gitAuthConfig.ID,
+		UserID:     userID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get git auth link: %w", err)
+		}
+
+		_, err = api.Database.InsertGitAuthLink(ctx, database.InsertGitAuthLinkParams{
+			ProviderID:        gitAuthConfig.ID,
+			UserID:            userID,
+			CreatedAt:         database.Now(),
+			UpdatedAt:         database.Now(),
+			OAuthAccessToken:  token.AccessToken,
+			OAuthRefreshToken: token.RefreshToken,
+			OAuthExpiry:       token.Expiry,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert git auth link: %w", err)
+		}
+	} else {
+		_, err = api.Database.UpdateGitAuthLink(ctx, database.UpdateGitAuthLinkParams{
+			ProviderID:        gitAuthConfig.ID,
+			UserID:            userID,
+			UpdatedAt:         database.Now(),
+			OAuthAccessToken:  token.AccessToken,
+			OAuthRefreshToken: token.RefreshToken,
+			OAuthExpiry:       token.Expiry,
+		})
+		if err != nil {
+			return xerrors.Errorf("update git auth link: %w", err)
+		}
+	}
+
+	err = api.Pubsub.Publish("gitauth", []byte(fmt.Sprintf("%s|%s", gitAuthConfig.ID, userID)))
+	if err != nil {
+		return xerrors.Errorf("publish auth update: %w", err)
+	}
+	return nil
+}
+
 // wsNetConn wraps net.Conn created by websocket.NetConn(). Cancel func
 // is called if a read or write error is encountered.
 type wsNetConn struct {
@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -396,7 +397,7 @@ func TestWorkspaceAgentListen(t *testing.T) {
 		agentClient := agentsdk.New(client.URL)
 		agentClient.SetSessionToken(authToken)
 
-		_, err = agentClient.Listen(ctx)
+		_, err = agentClient.Listen(ctx, 0)
 		require.Error(t, err)
 		require.ErrorContains(t, err, "build is outdated")
 	})
@@ -1138,6 +1139,164 @@ func TestWorkspaceAgentsGitAuth(t *testing.T) {
 		token, err = agentClient.GitAuth(context.Background(), "github.com/asd/asd", false)
 		require.NoError(t, err)
 	})
+
+	t.Run("DeviceFlow", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		var authorized atomic.Bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/device":
+				_, _ = w.Write([]byte(`{"device_code":"device","user_code":"ABCD-1234","verification_uri":"https://example.com/device","expires_in":900,"interval":1}`))
+			case "/token":
+				assert.Equal(t, "device", r.FormValue("device_code"))
+				if !authorized.Load() {
+					_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"access_token":"device-token","token_type":"bearer"}`))
+			}
+		}))
+		defer srv.Close()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			GitAuthConfigs: []*gitauth.Config{{
+				OAuth2Config: &testutil.OAuth2Config{},
+				ID:           "github",
+				Regex:        regexp.MustCompile(`github\.com`),
+				Type:         codersdk.GitProviderGitHub,
+				DeviceAuth: &gitauth.DeviceAuth{
+					ClientID: "client",
+					CodeURL:  srv.URL + "/device",
+					TokenURL: srv.URL + "/token",
+				},
+			}},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		token, err := agentClient.GitAuth(ctx, "github.com/asd/asd", false)
+		require.NoError(t, err)
+		require.NotEmpty(t, token.URL)
+		require.True(t, token.DeviceFlow)
+
+		device, err := agentClient.GitAuthDevice(ctx, "github.com/asd/asd")
+		require.NoError(t, err)
+		require.Equal(t, "ABCD-1234", device.UserCode)
+		require.Equal(t, "https://example.com/device", device.VerificationURI)
+
+		exchange := agentsdk.GitAuthDeviceExchange{
+			URL:        "github.com/asd/asd",
+			DeviceCode: device.DeviceCode,
+		}
+		_, err = agentClient.GitAuthDeviceExchange(ctx, exchange)
+		require.ErrorIs(t, err, agentsdk.ErrGitAuthDevicePending)
+
+		authorized.Store(true)
+		token, err = agentClient.GitAuthDeviceExchange(ctx, exchange)
+		require.NoError(t, err)
+		require.Equal(t, "device-token", token.Username)
+
+		// The token is stored, so git no longer needs to authenticate.
+		token, err = agentClient.GitAuth(ctx, "github.com/asd/asd", false)
+		require.NoError(t, err)
+		require.Empty(t, token.URL)
+	})
+
+	t.Run("AccessToken", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			GitAuthConfigs: []*gitauth.Config{{
+				OAuth2Config: &testutil.OAuth2Config{},
+				ID:           "jira",
+				Type:         codersdk.GitProviderOAuth2,
+			}},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		// Providers without a regex are never used for Git.
+		_, err := agentClient.GitAuth(ctx, "jira.example.com", false)
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusNotFound, apiError.StatusCode())
+
+		_, err = agentClient.GitAuthAccessToken(ctx, "missing")
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusNotFound, apiError.StatusCode())
+
+		token, err := agentClient.GitAuthAccessToken(ctx, "jira")
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(token.URL, "/gitauth/jira"))
+		require.Empty(t, token.AccessToken)
+
+		resp := coderdtest.RequestGitAuthCallback(t, "jira", client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		token, err = agentClient.GitAuthAccessToken(ctx, "jira")
+		require.NoError(t, err)
+		require.Empty(t, token.URL)
+		require.Equal(t, "access_token", token.AccessToken)
+	})
+
+	t.Run("NoDeviceFlow", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			GitAuthConfigs: []*gitauth.Config{{
+				OAuth2Config: &testutil.OAuth2Config{},
+				ID:           "github",
+				Regex:        regexp.MustCompile(`github\.com`),
+				Type:         codersdk.GitProviderGitHub,
+			}},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		_, err := agentClient.GitAuthDevice(context.Background(), "github.com/asd/asd")
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+	})
 }
 
 func TestWorkspaceAgentReportStats(t *testing.T) {
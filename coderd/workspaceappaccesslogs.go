@@ -0,0 +1,299 @@
+package coderd
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tabbed/pqtype"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/workspaceapps"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get workspace app access logs
+// @ID get-workspace-app-access-logs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param app query string false "App slug or port"
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WorkspaceAppAccessLog
+// @Router /workspaces/{workspace}/app-access-logs [get]
+func (api *API) workspaceAppAccessLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	logs, err := api.Database.GetWorkspaceAppAccessLogs(ctx, database.GetWorkspaceAppAccessLogsParams{
+		WorkspaceID:   workspace.ID,
+		AppSlugOrPort: r.URL.Query().Get("app"),
+		OffsetOpt:     int32(page.Offset),
+		LimitOpt:      int32(page.Limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace app access logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.WorkspaceAppAccessLog, 0, len(logs))
+	for _, log := range logs {
+		converted = append(converted, convertWorkspaceAppAccessLog(log))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+func convertWorkspaceAppAccessLog(log database.GetWorkspaceAppAccessLogsRow) codersdk.WorkspaceAppAccessLog {
+	converted := codersdk.WorkspaceAppAccessLog{
+		ID:            log.ID,
+		CreatedAt:     log.CreatedAt,
+		Username:      log.UserUsername.String,
+		WorkspaceID:   log.WorkspaceID.UUID,
+		AgentID:       log.AgentID,
+		AppSlugOrPort: log.AppSlugOrPort,
+		AccessMethod:  log.AccessMethod,
+		SharingLevel:  codersdk.WorkspaceAppSharingLevel(log.SharingLevel),
+		Method:        log.Method,
+		Path:          log.Path,
+		StatusCode:    log.StatusCode,
+		BytesWritten:  log.BytesWritten,
+		DurationMS:    log.DurationMs,
+	}
+	if log.UserID.Valid {
+		converted.UserID = &log.UserID.UUID
+	}
+	if log.Ip.Valid {
+		converted.IP = log.Ip.IPNet.IP.String()
+	}
+	return converted
+}
+
+// insertWorkspaceAppAccessLog records a request that was proxied to a
+// workspace app. The log is inserted in the background, so the request
+// doesn't wait on the database.
+func (api *API) insertWorkspaceAppAccessLog(r *http.Request, ticket workspaceapps.Ticket, path string, sw *appAccessLogWriter, duration time.Duration) {
+	userID := uuid.NullUUID{}
+	if ticket.RequesterID != uuid.Nil {
+		userID = uuid.NullUUID{UUID: ticket.RequesterID, Valid: true}
+	}
+	// Tickets issued before access logs existed don't contain the sharing
+	// level of the app.
+	sharingLevel := ticket.AppSharingLevel
+	if sharingLevel == "" {
+		sharingLevel = database.AppSharingLevelOwner
+	}
+	status := sw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ip := net.ParseIP(r.RemoteAddr)
+	ipNet := pqtype.Inet{}
+	if ip != nil {
+		ipNet = pqtype.Inet{
+			IPNet: net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+			},
+			Valid: true,
+		}
+	}
+
+	api.appAccessLogs.add(database.InsertWorkspaceAppAccessLogParams{
+		ID:            uuid.New(),
+		CreatedAt:     database.Now(),
+		UserID:        userID,
+		WorkspaceID:   uuid.NullUUID{UUID: ticket.WorkspaceID, Valid: true},
+		AgentID:       ticket.AgentID,
+		AppSlugOrPort: ticket.AppSlugOrPort,
+		AccessMethod:  string(ticket.AccessMethod),
+		SharingLevel:  sharingLevel,
+		Ip:            ipNet,
+		Method:        r.Method,
+		Path:          path,
+		StatusCode:    int32(status),
+		BytesWritten:  sw.bytesWritten,
+		DurationMs:    duration.Milliseconds(),
+	})
+}
+
+const (
+	// appAccessLogBatchSize is the number of buffered access logs that
+	// triggers a flush before the interval elapses.
+	appAccessLogBatchSize = 1024
+	// appAccessLogMaxBuffered caps the access logs held in memory if the
+	// database can't keep up. Logs beyond it are dropped.
+	appAccessLogMaxBuffered = 16 * appAccessLogBatchSize
+)
+
+// appAccessLogBatcher buffers workspace app access logs and inserts them in
+// batches from a background goroutine.
+type appAccessLogBatcher struct {
+	db     database.Store
+	logger slog.Logger
+
+	mu      sync.Mutex
+	buf     []database.InsertWorkspaceAppAccessLogParams
+	dropped int
+
+	flush  chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newAppAccessLogBatcher(db database.Store, logger slog.Logger, interval time.Duration) *appAccessLogBatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &appAccessLogBatcher{
+		db:     db,
+		logger: logger,
+		flush:  make(chan struct{}, 1),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go b.run(ctx, interval)
+	return b
+}
+
+func (b *appAccessLogBatcher) add(log database.InsertWorkspaceAppAccessLogParams) {
+	b.mu.Lock()
+	if len(b.buf) >= appAccessLogMaxBuffered {
+		b.dropped++
+		b.mu.Unlock()
+		return
+	}
+	b.buf = append(b.buf, log)
+	full := len(b.buf) >= appAccessLogBatchSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *appAccessLogBatcher) run(ctx context.Context, interval time.Duration) {
+	defer close(b.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Insert the remaining logs before shutting down.
+			b.insert()
+			return
+		case <-ticker.C:
+		case <-b.flush:
+		}
+		b.insert()
+	}
+}
+
+// insert writes the buffered access logs one at a time, so a log that can't
+// be inserted, e.g. because its workspace was deleted in the meantime,
+// doesn't discard the rest of the batch. Failures are logged and otherwise
+// ignored, as the responses have already been written.
+func (b *appAccessLogBatcher) insert() {
+	b.mu.Lock()
+	logs, dropped := b.buf, b.dropped
+	b.buf, b.dropped = nil, 0
+	b.mu.Unlock()
+
+	//nolint:gocritic // Access logs are inserted by the system.
+	ctx := dbauthz.AsSystemRestricted(context.Background())
+	if dropped > 0 {
+		b.logger.Warn(ctx, "dropped workspace app access logs, buffer is full", slog.F("count", dropped))
+	}
+	failed := 0
+	var lastErr error
+	for _, log := range logs {
+		insertCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := b.db.InsertWorkspaceAppAccessLog(insertCtx, log)
+		cancel()
+		if err != nil {
+			failed++
+			lastErr = xerrors.Errorf("insert access log of workspace %s: %w", log.WorkspaceID.UUID, err)
+		}
+	}
+	if failed > 0 {
+		b.logger.Warn(ctx, "insert workspace app access logs",
+			slog.F("failed", failed),
+			slog.F("count", len(logs)),
+			slog.Error(lastErr),
+		)
+	}
+}
+
+// Close inserts the buffered access logs and stops the batcher.
+func (b *appAccessLogBatcher) Close() {
+	b.cancel()
+	<-b.done
+}
+
+var (
+	_ http.ResponseWriter = (*appAccessLogWriter)(nil)
+	_ http.Hijacker       = (*appAccessLogWriter)(nil)
+	_ http.Flusher        = (*appAccessLogWriter)(nil)
+)
+
+// appAccessLogWriter records the status and the number of bytes written for
+// the access log of a proxied request.
+type appAccessLogWriter struct {
+	http.ResponseWriter
+	status       int
+	bytesWritten int64
+}
+
+func (w *appAccessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *appAccessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytesWritten += int64(n)
+	return n, err
+}
+
+func (w *appAccessLogWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("%T is not a http.Hijacker", w.ResponseWriter)
+	}
+	// Hijacked connections are WebSocket upgrades that have been accepted by
+	// the app.
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+func (w *appAccessLogWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
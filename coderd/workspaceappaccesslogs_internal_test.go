@@ -0,0 +1,115 @@
+package coderd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
+	"github.com/coder/coder/testutil"
+)
+
+func TestAppAccessLogBatcher(t *testing.T) {
+	t.Parallel()
+
+	accessLog := func(workspaceID uuid.UUID) database.InsertWorkspaceAppAccessLogParams {
+		return database.InsertWorkspaceAppAccessLogParams{
+			ID:            uuid.New(),
+			CreatedAt:     database.Now(),
+			WorkspaceID:   uuid.NullUUID{UUID: workspaceID, Valid: true},
+			AgentID:       uuid.New(),
+			AppSlugOrPort: "code-server",
+			AccessMethod:  "path",
+			SharingLevel:  database.AppSharingLevelOwner,
+			Method:        "GET",
+			Path:          "/",
+			StatusCode:    200,
+		}
+	}
+
+	t.Run("FlushOnBatchSize", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		db := dbfake.New()
+		// The interval never elapses, so the batch size has to trigger the
+		// flush.
+		b := newAppAccessLogBatcher(db, slogtest.Make(t, nil), time.Hour)
+		defer b.Close()
+
+		workspaceID := uuid.New()
+		for i := 0; i < appAccessLogBatchSize; i++ {
+			b.add(accessLog(workspaceID))
+		}
+		require.Eventually(t, func() bool {
+			logs, err := db.GetWorkspaceAppAccessLogs(ctx, database.GetWorkspaceAppAccessLogsParams{
+				WorkspaceID: workspaceID,
+			})
+			return err == nil && len(logs) == appAccessLogBatchSize
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("FlushOnClose", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		db := dbfake.New()
+		b := newAppAccessLogBatcher(db, slogtest.Make(t, nil), time.Hour)
+
+		workspaceID := uuid.New()
+		b.add(accessLog(workspaceID))
+		b.Close()
+
+		logs, err := db.GetWorkspaceAppAccessLogs(ctx, database.GetWorkspaceAppAccessLogsParams{
+			WorkspaceID: workspaceID,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+	})
+	t.Run("FailedLog", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		workspaceID := uuid.New()
+		failing := accessLog(workspaceID)
+		db := &failingAccessLogStore{Store: dbfake.New(), failID: failing.ID}
+		b := newAppAccessLogBatcher(db, slogtest.Make(t, nil), time.Hour)
+
+		// A log that can't be inserted doesn't discard the others.
+		b.add(accessLog(workspaceID))
+		b.add(failing)
+		b.add(accessLog(workspaceID))
+		b.Close()
+
+		logs, err := db.GetWorkspaceAppAccessLogs(ctx, database.GetWorkspaceAppAccessLogsParams{
+			WorkspaceID: workspaceID,
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+	})
+}
+
+// failingAccessLogStore fails to insert the access log with failID.
+type failingAccessLogStore struct {
+	database.Store
+	failID uuid.UUID
+}
+
+func (s *failingAccessLogStore) InsertWorkspaceAppAccessLog(ctx context.Context, arg database.InsertWorkspaceAppAccessLogParams) error {
+	if arg.ID == s.failID {
+		return xerrors.New("insert failed")
+	}
+	return s.Store.InsertWorkspaceAppAccessLog(ctx, arg)
+}
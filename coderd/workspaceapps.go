@@ -9,6 +9,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -564,6 +565,31 @@ func (api *API) setWorkspaceAppCookie(rw http.ResponseWriter, r *http.Request, t
 func (api *API) proxyWorkspaceApplication(rw http.ResponseWriter, r *http.Request, ticket workspaceapps.Ticket, path string) {
 	ctx := r.Context()
 
+	// Record every request that reaches an app, including the ones that are
+	// rejected below, in the access log.
+	start := time.Now()
+	requestPath := path
+	logWriter := &appAccessLogWriter{ResponseWriter: rw}
+	rw = logWriter
+	defer func() {
+		api.insertWorkspaceAppAccessLog(r, ticket, requestPath, logWriter, time.Since(start))
+	}()
+
+	// Requests to shared apps from other users are subject to the limits of
+	// the app and workspace.
+	if retryAfter, ok := api.workspaceAppsLimiter.Allow(ticket); !ok {
+		rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusTooManyRequests,
+			Title:        "Too Many Requests",
+			Description:  "This application has received too many requests. Please try again later.",
+			RetryEnabled: true,
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+	rw = api.workspaceAppsLimiter.ResponseWriter(ctx, rw, ticket)
+
 	// Filter IP headers from untrusted origins.
 	httpmw.FilterUntrustedOriginHeaders(api.RealIPConfig, r)
 	// Ensure proper IP headers get sent to the forwarded application.
@@ -606,6 +632,29 @@ func (api *API) proxyWorkspaceApplication(rw http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	routing := ticket.Routing()
+	if !routing.MethodAllowed(r.Method) {
+		rw.Header().Set("Allow", strings.Join(routing.AllowedMethods, ", "))
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusMethodNotAllowed,
+			Title:        "Method Not Allowed",
+			Description:  fmt.Sprintf("The application does not allow %s requests.", r.Method),
+			RetryEnabled: false,
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+	if !routing.FilterWebsocketSubprotocols(r.Header) {
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusBadRequest,
+			Title:        "Bad Request",
+			Description:  fmt.Sprintf("The application only accepts the WebSocket subprotocols %s.", strings.Join(routing.WebsocketSubprotocols, ", ")),
+			RetryEnabled: false,
+			DashboardURL: api.AccessURL.String(),
+		})
+		return
+	}
+
 	// Ensure path and query parameter correctness.
 	if path == "" {
 		// Web applications typically request paths relative to the
@@ -625,9 +674,22 @@ func (api *API) proxyWorkspaceApplication(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Path-based apps that are served from a sub-path receive the full request
+	// path, including the base path of the app.
+	basePath := strings.TrimSuffix(ticket.BasePath, "/")
+	if ticket.AccessMethod == workspaceapps.AccessMethodPath && !routing.StripPrefix {
+		path = basePath + path
+	}
 	r.URL.Path = path
 	appURL.RawQuery = ""
 
+	if routing.BasePathHeader != "" && basePath != "" {
+		r.Header.Set(routing.BasePathHeader, basePath)
+	}
+	for name, value := range routing.Headers {
+		r.Header.Set(name, value)
+	}
+
 	proxy := httputil.NewSingleHostReverseProxy(appURL)
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
 		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
@@ -117,6 +117,12 @@ func (p *Provider) ResolveRequest(rw http.ResponseWriter, r *http.Request, appRe
 	ticket.WorkspaceID = dbReq.Workspace.ID
 	ticket.AgentID = dbReq.Agent.ID
 	ticket.AppURL = dbReq.AppURL
+	ticket.AppRouting = &dbReq.AppRouting
+	ticket.AppSharingLevel = dbReq.AppSharingLevel
+	ticket.TemplateLimits = dbReq.TemplateLimits
+	if apiKey != nil {
+		ticket.RequesterID = apiKey.UserID
+	}
 
 	// TODO(@deansheather): return an error if the agent is offline or the app
 	// is not running.
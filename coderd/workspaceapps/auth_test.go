@@ -20,7 +20,9 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/agent"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/coderd/workspaceapps"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/codersdk/agentsdk"
@@ -49,6 +51,11 @@ func Test_ResolveRequest(t *testing.T) {
 		appURL = "http://localhost:8080"
 	)
 	allApps := []string{appNameOwner, appNameAuthed, appNamePublic}
+	appSharingLevels := map[string]database.AppSharingLevel{
+		appNameOwner:  database.AppSharingLevelOwner,
+		appNameAuthed: database.AppSharingLevelAuthenticated,
+		appNamePublic: database.AppSharingLevelPublic,
+	}
 
 	// Start a listener for a server that always responds with 500 for the
 	// unhealthy app.
@@ -247,12 +254,15 @@ func Test_ResolveRequest(t *testing.T) {
 					_ = w.Body.Close()
 
 					require.Equal(t, &workspaceapps.Ticket{
-						Request:     req,
-						Expiry:      ticket.Expiry, // ignored to avoid flakiness
-						UserID:      me.ID,
-						WorkspaceID: workspace.ID,
-						AgentID:     agentID,
-						AppURL:      appURL,
+						Request:         req,
+						Expiry:          ticket.Expiry, // ignored to avoid flakiness
+						UserID:          me.ID,
+						WorkspaceID:     workspace.ID,
+						AgentID:         agentID,
+						AppURL:          appURL,
+						AppRouting:      &workspaceapps.DefaultRouting,
+						RequesterID:     me.ID,
+						AppSharingLevel: appSharingLevels[app],
 					}, ticket)
 					require.NotZero(t, ticket.Expiry)
 					require.InDelta(t, time.Now().Add(workspaceapps.TicketExpiry).Unix(), ticket.Expiry, time.Minute.Seconds())
@@ -560,6 +570,52 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Equal(t, "http://127.0.0.1:9090", ticket.AppURL)
 	})
 
+	t.Run("PortShare", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitMedium)
+		defer cancel()
+
+		req := workspaceapps.Request{
+			AccessMethod:      workspaceapps.AccessMethodSubdomain,
+			BasePath:          "/",
+			UsernameOrID:      me.Username,
+			WorkspaceNameOrID: workspace.Name,
+			AgentNameOrID:     agentName,
+			AppSlugOrPort:     "9091",
+		}
+		resolve := func() (*workspaceapps.Ticket, bool) {
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(codersdk.SessionTokenHeader, secondUserClient.SessionToken())
+			ticket, ok := api.WorkspaceAppsProvider.ResolveRequest(rw, r, req)
+			_ = rw.Result().Body.Close()
+			return ticket, ok
+		}
+
+		// Ports are private until they're shared.
+		_, ok := resolve()
+		require.False(t, ok)
+
+		_, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+			AgentName:  agentName,
+			Port:       9091,
+			ShareLevel: codersdk.WorkspaceAppSharingLevelAuthenticated,
+			ExpiresAt:  ptr.Ref(time.Now().Add(time.Hour)),
+		})
+		require.NoError(t, err)
+		ticket, ok := resolve()
+		require.True(t, ok)
+		require.Equal(t, "http://127.0.0.1:9091", ticket.AppURL)
+
+		err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+			AgentName: agentName,
+			Port:      9091,
+		})
+		require.NoError(t, err)
+		_, ok = resolve()
+		require.False(t, ok)
+	})
+
 	t.Run("Terminal", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,337 @@
+package workspaceapps
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// limiterIdleTimeout is how long the limiter of an app or workspace is kept
+// after its last request.
+const limiterIdleTimeout = 10 * time.Minute
+
+// Limits are the request rate and bandwidth limits the app proxy enforces on
+// requests to shared apps from users other than the workspace owner. Zero
+// values are unlimited.
+type Limits struct {
+	RequestsPerMinute       int64 `json:"requests_per_minute,omitempty"`
+	BandwidthBytesPerSecond int64 `json:"bandwidth_bytes_per_second,omitempty"`
+}
+
+// stricter returns the stricter of two limits, treating zero as unlimited.
+func stricter(a, b int64) int64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// LimiterOptions are the limits of shared apps. The limiter is created with
+// the deployment-wide limits, and templates can lower them for the apps in
+// their workspaces.
+type LimiterOptions struct {
+	// App is applied to every app and port.
+	App Limits `json:"app"`
+	// Workspace is applied to the sum of the requests to all apps and ports of
+	// a workspace.
+	Workspace Limits `json:"workspace"`
+}
+
+// LimitsFromTemplate returns the limits set on a template.
+func LimitsFromTemplate(limits database.TemplateAppLimit) LimiterOptions {
+	return LimiterOptions{
+		App: Limits{
+			RequestsPerMinute:       limits.AppRequestsPerMinute,
+			BandwidthBytesPerSecond: limits.AppBandwidthBytesPerSecond,
+		},
+		Workspace: Limits{
+			RequestsPerMinute:       limits.WorkspaceRequestsPerMinute,
+			BandwidthBytesPerSecond: limits.WorkspaceBandwidthBytesPerSecond,
+		},
+	}
+}
+
+// Limiter enforces request rate and bandwidth limits on requests to apps that
+// are shared with other users.
+type Limiter struct {
+	opts LimiterOptions
+
+	rateLimitedRequests *prometheus.CounterVec
+	throttledSeconds    *prometheus.CounterVec
+
+	mu        sync.Mutex
+	buckets   map[string]*limiterBucket
+	lastSweep time.Time
+}
+
+type limiterBucket struct {
+	requests  *rate.Limiter
+	bandwidth *rate.Limiter
+	lastUsed  time.Time
+}
+
+// limiterScope is an app or workspace whose requests share a bucket.
+type limiterScope struct {
+	name   string
+	key    string
+	limits Limits
+}
+
+// scopedLimiter is a bandwidth limiter and the name of its scope for metrics.
+type scopedLimiter struct {
+	scope   string
+	limiter *rate.Limiter
+}
+
+// NewLimiter creates a limiter that enforces the deployment-wide limits and
+// the limits of templates.
+func NewLimiter(registerer prometheus.Registerer, opts LimiterOptions) *Limiter {
+	l := &Limiter{
+		opts: opts,
+		rateLimitedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "workspace_apps",
+			Name:      "rate_limited_requests_total",
+			Help:      "The number of requests to shared workspace apps that were rejected by a rate limit.",
+		}, []string{"scope"}),
+		throttledSeconds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "workspace_apps",
+			Name:      "throttled_seconds_total",
+			Help:      "The total time responses from shared workspace apps were delayed by a bandwidth limit.",
+		}, []string{"scope"}),
+		buckets: map[string]*limiterBucket{},
+	}
+	registerer.MustRegister(l.rateLimitedRequests, l.throttledSeconds)
+	return l
+}
+
+// applies returns whether the limits apply to the requests the ticket was
+// issued for. The owner of the workspace is never limited.
+func (*Limiter) applies(ticket Ticket) bool {
+	switch ticket.AppSharingLevel {
+	case database.AppSharingLevelAuthenticated, database.AppSharingLevelPublic:
+		return ticket.RequesterID != ticket.UserID
+	default:
+		return false
+	}
+}
+
+func (l *Limiter) scopes(ticket Ticket) []limiterScope {
+	app, workspace := l.opts.App, l.opts.Workspace
+	if ticket.TemplateLimits != nil {
+		app.RequestsPerMinute = stricter(app.RequestsPerMinute, ticket.TemplateLimits.App.RequestsPerMinute)
+		app.BandwidthBytesPerSecond = stricter(app.BandwidthBytesPerSecond, ticket.TemplateLimits.App.BandwidthBytesPerSecond)
+		workspace.RequestsPerMinute = stricter(workspace.RequestsPerMinute, ticket.TemplateLimits.Workspace.RequestsPerMinute)
+		workspace.BandwidthBytesPerSecond = stricter(workspace.BandwidthBytesPerSecond, ticket.TemplateLimits.Workspace.BandwidthBytesPerSecond)
+	}
+	return []limiterScope{
+		{
+			name:   "app",
+			key:    "app:" + ticket.WorkspaceID.String() + "/" + ticket.AgentID.String() + "/" + ticket.AppSlugOrPort,
+			limits: app,
+		},
+		{
+			name:   "workspace",
+			key:    "workspace:" + ticket.WorkspaceID.String(),
+			limits: workspace,
+		},
+	}
+}
+
+// limiters returns the request and bandwidth limiters of a scope, which are
+// nil if the scope is unlimited.
+func (l *Limiter) limiters(scope limiterScope) (requests *rate.Limiter, bandwidth *rate.Limiter) {
+	if scope.limits == (Limits{}) {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.lastSweep) > limiterIdleTimeout {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.lastUsed) > limiterIdleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[scope.key]
+	if !ok {
+		bucket = &limiterBucket{}
+		l.buckets[scope.key] = bucket
+	}
+	bucket.lastUsed = now
+	// The limits of a scope change when the limits of the template change.
+	bucket.requests = updateLimiter(bucket.requests, rate.Limit(float64(scope.limits.RequestsPerMinute)/60), scope.limits.RequestsPerMinute)
+	bucket.bandwidth = updateLimiter(bucket.bandwidth, rate.Limit(scope.limits.BandwidthBytesPerSecond), scope.limits.BandwidthBytesPerSecond)
+	return bucket.requests, bucket.bandwidth
+}
+
+// updateLimiter returns nil if burst is zero, which is unlimited. Existing
+// limiters keep their tokens when their limits change.
+func updateLimiter(limiter *rate.Limiter, limit rate.Limit, burst int64) *rate.Limiter {
+	if burst <= 0 {
+		return nil
+	}
+	if limiter == nil {
+		return rate.NewLimiter(limit, int(burst))
+	}
+	if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	if limiter.Burst() != int(burst) {
+		limiter.SetBurst(int(burst))
+	}
+	return limiter
+}
+
+// Allow returns whether a request may be proxied to the app. If not, the
+// returned duration is how long the client should wait before retrying.
+func (l *Limiter) Allow(ticket Ticket) (time.Duration, bool) {
+	if !l.applies(ticket) {
+		return 0, true
+	}
+
+	reservations := make([]*rate.Reservation, 0, 2)
+	for _, scope := range l.scopes(ticket) {
+		requests, _ := l.limiters(scope)
+		if requests == nil {
+			continue
+		}
+		reservation := requests.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			// Give the tokens back so rejected requests don't count towards
+			// the limits.
+			reservation.Cancel()
+			for _, reserved := range reservations {
+				reserved.Cancel()
+			}
+			l.rateLimitedRequests.WithLabelValues(scope.name).Inc()
+			return delay, false
+		}
+		reservations = append(reservations, reservation)
+	}
+	return 0, true
+}
+
+// ResponseWriter returns a response writer that delays writes to stay within
+// the bandwidth limits of the app and workspace. The response writer is
+// returned as-is if the limits don't apply.
+func (l *Limiter) ResponseWriter(ctx context.Context, rw http.ResponseWriter, ticket Ticket) http.ResponseWriter {
+	if !l.applies(ticket) {
+		return rw
+	}
+
+	var limiters []scopedLimiter
+	for _, scope := range l.scopes(ticket) {
+		_, bandwidth := l.limiters(scope)
+		if bandwidth == nil {
+			continue
+		}
+		limiters = append(limiters, scopedLimiter{scope: scope.name, limiter: bandwidth})
+	}
+	if len(limiters) == 0 {
+		return rw
+	}
+	return &throttledWriter{
+		ResponseWriter: rw,
+		throttle: throttle{
+			ctx:       ctx,
+			limiters:  limiters,
+			throttled: l.throttledSeconds,
+		},
+	}
+}
+
+// throttle waits for bandwidth to become available in all limiters.
+type throttle struct {
+	ctx       context.Context
+	limiters  []scopedLimiter
+	throttled *prometheus.CounterVec
+}
+
+// write calls fn with chunks of b that are no larger than the burst of any
+// limiter, waiting for the bandwidth of each chunk first.
+func (t throttle) write(b []byte, fn func([]byte) (int, error)) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		size := len(b)
+		for _, l := range t.limiters {
+			if burst := l.limiter.Burst(); size > burst {
+				size = burst
+			}
+		}
+		for _, l := range t.limiters {
+			start := time.Now()
+			err := l.limiter.WaitN(t.ctx, size)
+			if err != nil {
+				return written, xerrors.Errorf("wait for bandwidth: %w", err)
+			}
+			t.throttled.WithLabelValues(l.scope).Add(time.Since(start).Seconds())
+		}
+
+		n, err := fn(b[:size])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[size:]
+	}
+	return written, nil
+}
+
+var (
+	_ http.ResponseWriter = (*throttledWriter)(nil)
+	_ http.Hijacker       = (*throttledWriter)(nil)
+	_ http.Flusher        = (*throttledWriter)(nil)
+)
+
+type throttledWriter struct {
+	http.ResponseWriter
+	throttle throttle
+}
+
+func (w *throttledWriter) Write(b []byte) (int, error) {
+	return w.throttle.write(b, w.ResponseWriter.Write)
+}
+
+func (w *throttledWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("%T is not a http.Hijacker", w.ResponseWriter)
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	// WebSocket traffic from the app is throttled as well. The buffered
+	// writer is only used for the upgrade response, so it isn't throttled.
+	return &throttledConn{Conn: conn, throttle: w.throttle}, brw, nil
+}
+
+func (w *throttledWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type throttledConn struct {
+	net.Conn
+	throttle throttle
+}
+
+func (c *throttledConn) Write(b []byte) (int, error) {
+	return c.throttle.write(b, c.Conn.Write)
+}
@@ -0,0 +1,132 @@
+package workspaceapps_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/workspaceapps"
+	"github.com/coder/coder/testutil"
+)
+
+func Test_LimiterAllow(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ownerID     = uuid.New()
+		workspaceID = uuid.New()
+		agentID     = uuid.New()
+	)
+	ticket := func(app string, level database.AppSharingLevel, requesterID uuid.UUID) workspaceapps.Ticket {
+		return workspaceapps.Ticket{
+			Request: workspaceapps.Request{
+				AccessMethod:  workspaceapps.AccessMethodSubdomain,
+				AppSlugOrPort: app,
+			},
+			UserID:          ownerID,
+			WorkspaceID:     workspaceID,
+			AgentID:         agentID,
+			RequesterID:     requesterID,
+			AppSharingLevel: level,
+		}
+	}
+
+	t.Run("App", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := workspaceapps.NewLimiter(prometheus.NewRegistry(), workspaceapps.LimiterOptions{
+			App: workspaceapps.Limits{RequestsPerMinute: 2},
+		})
+		shared := ticket("app", database.AppSharingLevelPublic, uuid.Nil)
+		for i := 0; i < 2; i++ {
+			_, ok := limiter.Allow(shared)
+			require.True(t, ok)
+		}
+		retryAfter, ok := limiter.Allow(shared)
+		require.False(t, ok)
+		require.Greater(t, retryAfter, time.Duration(0))
+
+		// Other apps and the owner aren't affected.
+		_, ok = limiter.Allow(ticket("other", database.AppSharingLevelPublic, uuid.Nil))
+		require.True(t, ok)
+		_, ok = limiter.Allow(ticket("app", database.AppSharingLevelPublic, ownerID))
+		require.True(t, ok)
+		_, ok = limiter.Allow(ticket("app", database.AppSharingLevelOwner, uuid.Nil))
+		require.True(t, ok)
+	})
+
+	t.Run("TemplateLimits", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := workspaceapps.NewLimiter(prometheus.NewRegistry(), workspaceapps.LimiterOptions{
+			App: workspaceapps.Limits{RequestsPerMinute: 100},
+		})
+		shared := ticket("app", database.AppSharingLevelAuthenticated, uuid.New())
+		shared.TemplateLimits = &workspaceapps.LimiterOptions{
+			App: workspaceapps.Limits{RequestsPerMinute: 1},
+		}
+		_, ok := limiter.Allow(shared)
+		require.True(t, ok)
+		_, ok = limiter.Allow(shared)
+		require.False(t, ok)
+
+		// Templates can't raise the deployment-wide limits.
+		limiter = workspaceapps.NewLimiter(prometheus.NewRegistry(), workspaceapps.LimiterOptions{
+			Workspace: workspaceapps.Limits{RequestsPerMinute: 1},
+		})
+		shared.TemplateLimits = &workspaceapps.LimiterOptions{
+			Workspace: workspaceapps.Limits{RequestsPerMinute: 100},
+		}
+		_, ok = limiter.Allow(shared)
+		require.True(t, ok)
+		_, ok = limiter.Allow(shared)
+		require.False(t, ok)
+	})
+
+	t.Run("Workspace", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := workspaceapps.NewLimiter(prometheus.NewRegistry(), workspaceapps.LimiterOptions{
+			Workspace: workspaceapps.Limits{RequestsPerMinute: 1},
+		})
+		_, ok := limiter.Allow(ticket("app", database.AppSharingLevelPublic, uuid.Nil))
+		require.True(t, ok)
+		_, ok = limiter.Allow(ticket("other", database.AppSharingLevelPublic, uuid.Nil))
+		require.False(t, ok)
+	})
+}
+
+func Test_LimiterResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	limiter := workspaceapps.NewLimiter(prometheus.NewRegistry(), workspaceapps.LimiterOptions{
+		App: workspaceapps.Limits{BandwidthBytesPerSecond: 1000},
+	})
+	shared := workspaceapps.Ticket{
+		Request:         workspaceapps.Request{AppSlugOrPort: "app"},
+		UserID:          uuid.New(),
+		WorkspaceID:     uuid.New(),
+		AppSharingLevel: database.AppSharingLevelPublic,
+	}
+
+	// The owner isn't throttled.
+	rec := httptest.NewRecorder()
+	owner := shared
+	owner.RequesterID = owner.UserID
+	require.Equal(t, rec, limiter.ResponseWriter(testutil.Context(t, testutil.WaitShort), rec, owner))
+
+	// The first second of bandwidth is available immediately, the rest of
+	// the response is delayed.
+	rw := limiter.ResponseWriter(testutil.Context(t, testutil.WaitShort), rec, shared)
+	start := time.Now()
+	n, err := rw.Write(make([]byte, 1500))
+	require.NoError(t, err)
+	require.Equal(t, 1500, n)
+	require.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	require.Equal(t, 1500, rec.Body.Len())
+}
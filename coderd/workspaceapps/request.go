@@ -119,8 +119,17 @@ type databaseRequest struct {
 	// database.WorkspaceAppHealthHealthy.
 	AppHealth database.WorkspaceAppHealth
 	// AppSharingLevel is the sharing level of the app. This is forced to be set
-	// to AppSharingLevelOwner if the access method is terminal.
+	// to AppSharingLevelOwner if the access method is terminal. For ports, this
+	// is the level of the port share, or AppSharingLevelOwner if the port isn't
+	// shared.
 	AppSharingLevel database.AppSharingLevel
+	// AppRouting is the routing rules of the app. For ports and terminal
+	// requests, this is always DefaultRouting.
+	AppRouting Routing
+	// TemplateLimits is the limits set on the template of the workspace, or
+	// nil if the template doesn't set any. Terminal requests never have
+	// limits.
+	TemplateLimits *LimiterOptions
 }
 
 // getDatabase does queries to get the owner user, workspace and agent
@@ -202,6 +211,7 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		agentNameOrID         = r.AgentNameOrID
 		appURL                string
 		appSharingLevel       database.AppSharingLevel
+		appRouting            = DefaultRouting
 		appHealth             = database.WorkspaceAppHealthDisabled
 		portUint, portUintErr = strconv.ParseUint(r.AppSlugOrPort, 10, 16)
 	)
@@ -249,6 +259,17 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 	if appURL == "" {
 		return nil, xerrors.Errorf("no app found with slug %q: %w", r.AppSlugOrPort, sql.ErrNoRows)
 	}
+	if portUintErr != nil {
+		templateRouting, err := db.GetTemplateAppRouting(ctx, database.GetTemplateAppRoutingParams{
+			TemplateID: workspace.TemplateID,
+			AppSlug:    r.AppSlugOrPort,
+		})
+		if err == nil {
+			appRouting = RoutingFromTemplate(templateRouting)
+		} else if !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get template app routing: %w", err)
+		}
+	}
 
 	// Finally, get agent.
 	var agent database.WorkspaceAgent
@@ -276,6 +297,32 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		}
 	}
 
+	// Ports that aren't apps are only accessible by the owner unless they
+	// have been shared. Expired shares are ignored.
+	if portUintErr == nil {
+		share, err := db.GetWorkspacePortShare(ctx, database.GetWorkspacePortShareParams{
+			WorkspaceID: workspace.ID,
+			AgentName:   agent.Name,
+			Port:        int32(portUint),
+		})
+		if err == nil {
+			if !share.ExpiresAt.Valid || share.ExpiresAt.Time.After(database.Now()) {
+				appSharingLevel = share.ShareLevel
+			}
+		} else if !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get workspace port share: %w", err)
+		}
+	}
+
+	var templateLimits *LimiterOptions
+	limits, err := db.GetTemplateAppLimits(ctx, workspace.TemplateID)
+	if err == nil {
+		options := LimitsFromTemplate(limits)
+		templateLimits = &options
+	} else if !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get template app limits: %w", err)
+	}
+
 	return &databaseRequest{
 		Request:         r,
 		User:            user,
@@ -284,6 +331,8 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		AppURL:          appURL,
 		AppHealth:       appHealth,
 		AppSharingLevel: appSharingLevel,
+		AppRouting:      appRouting,
+		TemplateLimits:  templateLimits,
 	}, nil
 }
 
@@ -337,5 +386,6 @@ func (r Request) getDatabaseTerminal(ctx context.Context, db database.Store) (*d
 		AppURL:          "",
 		AppHealth:       database.WorkspaceAppHealthHealthy,
 		AppSharingLevel: database.AppSharingLevelOwner,
+		AppRouting:      DefaultRouting,
 	}, nil
 }
@@ -0,0 +1,99 @@
+package workspaceapps
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// DefaultRouting is the routing used for apps without routing rules on their
+// template, ports and tickets that were issued before routing rules existed.
+var DefaultRouting = Routing{StripPrefix: true}
+
+// Routing contains the rules the app proxy applies to requests for a workspace
+// app. They're set for an app slug on the template through the API.
+type Routing struct {
+	// StripPrefix removes the base path of path-based apps before proxying the
+	// request. Apps that are configured to be served from a sub-path, like
+	// Jupyter with a base_url or Grafana with serve_from_sub_path, should
+	// disable this to receive the full request path.
+	StripPrefix bool `json:"strip_prefix"`
+	// BasePathHeader is the name of a request header that is set to the base
+	// path of the app, e.g. "X-Forwarded-Prefix".
+	BasePathHeader string `json:"base_path_header,omitempty"`
+	// Headers are static request headers injected into every proxied request.
+	Headers map[string]string `json:"headers,omitempty"`
+	// AllowedMethods are the HTTP methods that are allowed to reach the app.
+	// All methods are allowed if empty.
+	AllowedMethods []string `json:"allowed_methods,omitempty"`
+	// WebsocketSubprotocols are the WebSocket subprotocols that are passed
+	// through to the app. All subprotocols are passed through if empty.
+	WebsocketSubprotocols []string `json:"websocket_subprotocols,omitempty"`
+}
+
+// RoutingFromTemplate returns the routing rules set for an app slug on its
+// template. Empty rules are left nil so the routing survives a round trip
+// through a ticket.
+func RoutingFromTemplate(routing database.TemplateAppRouting) Routing {
+	r := Routing{
+		StripPrefix:    routing.StripPrefix,
+		BasePathHeader: routing.BasePathHeader,
+	}
+	if len(routing.Headers) != 0 {
+		r.Headers = routing.Headers
+	}
+	if len(routing.AllowedMethods) != 0 {
+		r.AllowedMethods = routing.AllowedMethods
+	}
+	if len(routing.WebsocketSubprotocols) != 0 {
+		r.WebsocketSubprotocols = routing.WebsocketSubprotocols
+	}
+	return r
+}
+
+// MethodAllowed returns whether requests with the given HTTP method may be
+// proxied to the app.
+func (r Routing) MethodAllowed(method string) bool {
+	if len(r.AllowedMethods) == 0 {
+		return true
+	}
+	for _, allowed := range r.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterWebsocketSubprotocols removes the WebSocket subprotocols that are not
+// passed through to the app from the request headers. It returns false if the
+// client requested subprotocols and none of them are allowed.
+func (r Routing) FilterWebsocketSubprotocols(header http.Header) bool {
+	if len(r.WebsocketSubprotocols) == 0 {
+		return true
+	}
+	values := header.Values("Sec-WebSocket-Protocol")
+	if len(values) == 0 {
+		return true
+	}
+
+	allowed := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, protocol := range strings.Split(value, ",") {
+			protocol = strings.TrimSpace(protocol)
+			for _, passthrough := range r.WebsocketSubprotocols {
+				if protocol == passthrough {
+					allowed = append(allowed, protocol)
+					break
+				}
+			}
+		}
+	}
+	header.Del("Sec-WebSocket-Protocol")
+	if len(allowed) == 0 {
+		return false
+	}
+	header.Set("Sec-WebSocket-Protocol", strings.Join(allowed, ", "))
+	return true
+}
@@ -0,0 +1,77 @@
+package workspaceapps_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/workspaceapps"
+)
+
+func Test_RoutingMethodAllowed(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, workspaceapps.DefaultRouting.MethodAllowed(http.MethodDelete))
+
+	routing := workspaceapps.Routing{
+		AllowedMethods: []string{http.MethodGet, "post"},
+	}
+	require.True(t, routing.MethodAllowed(http.MethodGet))
+	require.True(t, routing.MethodAllowed(http.MethodPost))
+	require.False(t, routing.MethodAllowed(http.MethodDelete))
+}
+
+func Test_RoutingFilterWebsocketSubprotocols(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		allowed   []string
+		requested []string
+		expected  []string
+		ok        bool
+	}{
+		{
+			name:      "PassthroughAll",
+			requested: []string{"foo, bar"},
+			expected:  []string{"foo, bar"},
+			ok:        true,
+		},
+		{
+			name:    "NoneRequested",
+			allowed: []string{"foo"},
+			ok:      true,
+		},
+		{
+			name:      "Filtered",
+			allowed:   []string{"bar", "baz"},
+			requested: []string{"foo, bar", "baz"},
+			expected:  []string{"bar, baz"},
+			ok:        true,
+		},
+		{
+			name:      "NoneAllowed",
+			allowed:   []string{"baz"},
+			requested: []string{"foo, bar"},
+			ok:        false,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			for _, v := range c.requested {
+				header.Add("Sec-WebSocket-Protocol", v)
+			}
+			routing := workspaceapps.Routing{
+				WebsocketSubprotocols: c.allowed,
+			}
+			require.Equal(t, c.ok, routing.FilterWebsocketSubprotocols(header))
+			require.Equal(t, c.expected, header.Values("Sec-WebSocket-Protocol"))
+		})
+	}
+}
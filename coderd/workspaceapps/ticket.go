@@ -7,6 +7,8 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"gopkg.in/square/go-jose.v2"
+
+	"github.com/coder/coder/coderd/database"
 )
 
 const ticketSigningAlgorithm = jose.HS512
@@ -26,6 +28,24 @@ type Ticket struct {
 	WorkspaceID uuid.UUID `json:"workspace_id"`
 	AgentID     uuid.UUID `json:"agent_id"`
 	AppURL      string    `json:"app_url"`
+	// AppRouting is nil for tickets issued before routing rules existed.
+	AppRouting *Routing `json:"app_routing,omitempty"`
+	// RequesterID is the ID of the user the ticket was issued to, which is
+	// uuid.Nil for unauthenticated requests to public apps. UserID is the
+	// owner of the workspace.
+	RequesterID     uuid.UUID                `json:"requester_id"`
+	AppSharingLevel database.AppSharingLevel `json:"app_sharing_level,omitempty"`
+	// TemplateLimits is nil if the template of the workspace doesn't set any
+	// limits.
+	TemplateLimits *LimiterOptions `json:"template_limits,omitempty"`
+}
+
+// Routing returns the routing rules the proxy should apply to the app.
+func (t Ticket) Routing() Routing {
+	if t.AppRouting == nil {
+		return DefaultRouting
+	}
+	return *t.AppRouting
 }
 
 func (t Ticket) MatchesRequest(req Request) bool {
@@ -159,6 +159,9 @@ func setupProxyTest(t *testing.T, opts *setupProxyTestOpts) (*codersdk.Client, c
 			_, err := r.Cookie(codersdk.SessionTokenCookie)
 			assert.ErrorIs(t, err, http.ErrNoCookie)
 			w.Header().Set("X-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+			w.Header().Set("X-Forwarded-Prefix", r.Header.Get("X-Forwarded-Prefix"))
+			w.Header().Set("X-Script-Name", r.Header.Get("X-Script-Name"))
+			w.Header().Set("X-Request-Path", r.URL.Path)
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte(proxyTestAppBody))
 		}),
@@ -439,6 +442,104 @@ func TestWorkspaceAppsProxyPath(t *testing.T) {
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
+	t.Run("TemplateRouting", func(t *testing.T) {
+		t.Parallel()
+		// Changing the routing of the template would affect other tests.
+		client, _, workspace, _ := setupProxyTest(t, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		stripPrefix := false
+		_, err := client.UpsertTemplateAppRouting(ctx, workspace.TemplateID, proxyTestAppNameOwner, codersdk.UpsertTemplateAppRoutingRequest{
+			StripPrefix:    &stripPrefix,
+			BasePathHeader: "X-Forwarded-Prefix",
+			Headers: map[string]string{
+				"X-Script-Name": "/owner",
+			},
+			AllowedMethods: []string{http.MethodGet},
+		})
+		require.NoError(t, err)
+
+		basePath := fmt.Sprintf("/@%s/%s/apps/%s", coderdtest.FirstUserParams.Username, workspace.Name, proxyTestAppNameOwner)
+		path := fmt.Sprintf("%s/lab?%s", basePath, proxyTestAppQuery)
+		resp, err := requestWithRetries(ctx, t, client, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, proxyTestAppBody, string(body))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		// The prefix isn't stripped, so the app receives the full path.
+		require.Equal(t, basePath+"/lab", resp.Header.Get("X-Request-Path"))
+		require.Equal(t, basePath, resp.Header.Get("X-Forwarded-Prefix"))
+		require.Equal(t, "/owner", resp.Header.Get("X-Script-Name"))
+
+		// Methods that aren't allowed never reach the app.
+		resp, err = requestWithRetries(ctx, t, client, http.MethodPost, path, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		require.Equal(t, http.MethodGet, resp.Header.Get("Allow"))
+
+		// Without template routing, the app uses the default routing.
+		err = client.DeleteTemplateAppRouting(ctx, workspace.TemplateID, proxyTestAppNameOwner)
+		require.NoError(t, err)
+		resp, err = requestWithRetries(ctx, t, client, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "/lab", resp.Header.Get("X-Request-Path"))
+		require.Empty(t, resp.Header.Get("X-Script-Name"))
+	})
+
+	t.Run("AccessLogs", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		me, err := client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		path := fmt.Sprintf("/@%s/%s/apps/%s/access-log", coderdtest.FirstUserParams.Username, workspace.Name, proxyTestAppNameOwner)
+		resp, err := requestWithRetries(ctx, t, client, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// The access log is written after the response, so it may not be
+		// visible right away.
+		var accessLog codersdk.WorkspaceAppAccessLog
+		require.Eventually(t, func() bool {
+			logs, err := client.WorkspaceAppAccessLogs(ctx, workspace.ID, codersdk.WorkspaceAppAccessLogsRequest{
+				App: proxyTestAppNameOwner,
+			})
+			if !assert.NoError(t, err) {
+				return false
+			}
+			for _, l := range logs {
+				if l.Path == "/access-log" {
+					accessLog = l
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		require.NotNil(t, accessLog.UserID)
+		require.Equal(t, me.ID, *accessLog.UserID)
+		require.Equal(t, me.Username, accessLog.Username)
+		require.Equal(t, proxyTestAppNameOwner, accessLog.AppSlugOrPort)
+		require.Equal(t, "path", accessLog.AccessMethod)
+		require.Equal(t, codersdk.WorkspaceAppSharingLevelOwner, accessLog.SharingLevel)
+		require.Equal(t, http.MethodGet, accessLog.Method)
+		require.EqualValues(t, http.StatusOK, accessLog.StatusCode)
+		require.EqualValues(t, len(body), accessLog.BytesWritten)
+	})
+
 	t.Run("RedirectsMe", func(t *testing.T) {
 		t.Parallel()
 
@@ -995,6 +1096,42 @@ func TestWorkspaceAppsProxySubdomain(t *testing.T) {
 		require.Contains(t, resBody.Message, "Coder reserves ports less than")
 	})
 
+	t.Run("TemplateLimits", func(t *testing.T) {
+		t.Parallel()
+		// Changing the limits of the template would affect other tests.
+		client, _, workspace, _ := setupProxyTest(t, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpdateTemplateAppLimits(ctx, workspace.TemplateID, codersdk.UpdateTemplateAppLimitsRequest{
+			AppRequestsPerMinute: 1,
+		})
+		require.NoError(t, err)
+
+		// The owner is never limited.
+		u := proxyURL(t, client, proxyTestAppNamePublic, "/", proxyTestAppQuery)
+		for i := 0; i < 2; i++ {
+			resp, err := requestWithRetries(ctx, t, client, http.MethodGet, u, nil)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		unauthenticatedClient := codersdk.New(client.URL)
+		unauthenticatedClient.HTTPClient.CheckRedirect = client.HTTPClient.CheckRedirect
+		unauthenticatedClient.HTTPClient.Transport = client.HTTPClient.Transport
+		resp, err := unauthenticatedClient.Request(ctx, http.MethodGet, u, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, err = unauthenticatedClient.Request(ctx, http.MethodGet, u, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.NotEmpty(t, resp.Header.Get("Retry-After"))
+	})
+
 	t.Run("SuffixWildcardOK", func(t *testing.T) {
 		t.Parallel()
 
@@ -73,6 +73,7 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.timings,
 		data.templateVersions[0],
 	)
 	if err != nil {
@@ -187,6 +188,7 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.timings,
 		data.templateVersions,
 	)
 	if err != nil {
@@ -275,6 +277,7 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.timings,
 		data.templateVersions[0],
 	)
 	if err != nil {
@@ -318,20 +321,32 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	switch createBuild.Transition {
 	case codersdk.WorkspaceTransitionDelete:
 		action = rbac.ActionDelete
-	case codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop:
-		action = rbac.ActionUpdate
+	case codersdk.WorkspaceTransitionStart:
+		action = rbac.ActionStart
+	case codersdk.WorkspaceTransitionStop:
+		action = rbac.ActionStop
 	default:
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: fmt.Sprintf("Transition %q not supported.", createBuild.Transition),
 		})
 		return
 	}
-	if !api.Authorize(r, action, workspace) {
+	// Start and stop builds are also allowed with ActionUpdate. Without it,
+	// the build may only run the transition and can't change the template
+	// version or the parameters of the workspace.
+	transitionOnly := action != rbac.ActionDelete && !api.Authorize(r, rbac.ActionUpdate, workspace)
+	if (action == rbac.ActionDelete || transitionOnly) && !api.Authorize(r, action, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	if transitionOnly && (len(createBuild.ParameterValues) > 0 || len(createBuild.RichParameterValues) > 0) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Changing the parameters of a workspace requires permission to update it.",
+		})
+		return
+	}
 
-	if createBuild.TemplateVersionID == uuid.Nil {
+	if createBuild.TemplateVersionID == uuid.Nil || transitionOnly {
 		latestBuild, latestBuildErr := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
 		if latestBuildErr != nil {
 			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -340,7 +355,15 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
-		createBuild.TemplateVersionID = latestBuild.TemplateVersionID
+		if createBuild.TemplateVersionID == uuid.Nil {
+			createBuild.TemplateVersionID = latestBuild.TemplateVersionID
+		}
+		if transitionOnly && createBuild.TemplateVersionID != latestBuild.TemplateVersionID {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "Changing the template version of a workspace requires permission to update it.",
+			})
+			return
+		}
 	}
 
 	templateVersion, err := api.Database.GetTemplateVersionByID(ctx, createBuild.TemplateVersionID)
@@ -594,18 +617,23 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			return xerrors.Errorf("marshal provision job: %w", err)
 		}
+		hasMatchingDaemon, err := provisionerdserver.HasMatchingDaemon(ctx, db, template.Provisioner, tags, database.Now())
+		if err != nil {
+			return xerrors.Errorf("check provisioner daemons: %w", err)
+		}
 		provisionerJob, err = db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
-			ID:             uuid.New(),
-			CreatedAt:      database.Now(),
-			UpdatedAt:      database.Now(),
-			InitiatorID:    apiKey.UserID,
-			OrganizationID: template.OrganizationID,
-			Provisioner:    template.Provisioner,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			StorageMethod:  templateVersionJob.StorageMethod,
-			FileID:         templateVersionJob.FileID,
-			Input:          input,
-			Tags:           tags,
+			ID:                uuid.New(),
+			CreatedAt:         database.Now(),
+			UpdatedAt:         database.Now(),
+			InitiatorID:       apiKey.UserID,
+			OrganizationID:    template.OrganizationID,
+			Provisioner:       template.Provisioner,
+			Type:              database.ProvisionerJobTypeWorkspaceBuild,
+			StorageMethod:     templateVersionJob.StorageMethod,
+			FileID:            templateVersionJob.FileID,
+			Input:             input,
+			Tags:              tags,
+			HasMatchingDaemon: hasMatchingDaemon,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
@@ -674,6 +702,7 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		[]database.WorkspaceResourceMetadatum{},
 		[]database.WorkspaceAgent{},
 		[]database.WorkspaceApp{},
+		[]database.WorkspaceBuildTiming{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -907,6 +936,7 @@ type workspaceBuildsData struct {
 	metadata         []database.WorkspaceResourceMetadatum
 	agents           []database.WorkspaceAgent
 	apps             []database.WorkspaceApp
+	timings          []database.WorkspaceBuildTiming
 }
 
 func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.Workspace, workspaceBuilds []database.WorkspaceBuild) (workspaceBuildsData, error) {
@@ -942,6 +972,17 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		return workspaceBuildsData{}, xerrors.Errorf("get template versions: %w", err)
 	}
 
+	buildIDs := make([]uuid.UUID, 0, len(workspaceBuilds))
+	for _, build := range workspaceBuilds {
+		buildIDs = append(buildIDs, build.ID)
+	}
+
+	// nolint:gocritic // Getting workspace build timings is a system function.
+	timings, err := api.Database.GetWorkspaceBuildTimingsByBuildIDs(dbauthz.AsSystemRestricted(ctx), buildIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return workspaceBuildsData{}, xerrors.Errorf("get workspace build timings: %w", err)
+	}
+
 	// nolint:gocritic // Getting workspace resources by job ID is a system function.
 	resources, err := api.Database.GetWorkspaceResourcesByJobIDs(dbauthz.AsSystemRestricted(ctx), jobIDs)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -953,6 +994,7 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 			users:            users,
 			jobs:             jobs,
 			templateVersions: templateVersions,
+			timings:          timings,
 		}, nil
 	}
 
@@ -980,6 +1022,7 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 			templateVersions: templateVersions,
 			resources:        resources,
 			metadata:         metadata,
+			timings:          timings,
 		}, nil
 	}
 
@@ -1002,6 +1045,7 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		metadata:         metadata,
 		agents:           agents,
 		apps:             apps,
+		timings:          timings,
 	}, nil
 }
 
@@ -1014,6 +1058,7 @@ func (api *API) convertWorkspaceBuilds(
 	resourceMetadata []database.WorkspaceResourceMetadatum,
 	resourceAgents []database.WorkspaceAgent,
 	agentApps []database.WorkspaceApp,
+	buildTimings []database.WorkspaceBuildTiming,
 	templateVersions []database.TemplateVersion,
 ) ([]codersdk.WorkspaceBuild, error) {
 	workspaceByID := map[uuid.UUID]database.Workspace{}
@@ -1054,6 +1099,7 @@ func (api *API) convertWorkspaceBuilds(
 			resourceMetadata,
 			resourceAgents,
 			agentApps,
+			buildTimings,
 			templateVersion,
 		)
 		if err != nil {
@@ -1075,6 +1121,7 @@ func (api *API) convertWorkspaceBuild(
 	resourceMetadata []database.WorkspaceResourceMetadatum,
 	resourceAgents []database.WorkspaceAgent,
 	agentApps []database.WorkspaceApp,
+	buildTimings []database.WorkspaceBuildTiming,
 	templateVersion database.TemplateVersion,
 ) (codersdk.WorkspaceBuild, error) {
 	userByID := map[uuid.UUID]database.User{}
@@ -1126,6 +1173,12 @@ func (api *API) convertWorkspaceBuild(
 		metadata := append(make([]database.WorkspaceResourceMetadatum, 0), metadataByResourceID[resource.ID]...)
 		apiResources = append(apiResources, convertWorkspaceResource(resource, apiAgents, metadata))
 	}
+	timings := make([]database.WorkspaceBuildTiming, 0)
+	for _, timing := range buildTimings {
+		if timing.WorkspaceBuildID == build.ID {
+			timings = append(timings, timing)
+		}
+	}
 	apiJob := convertProvisionerJob(job)
 	transition := codersdk.WorkspaceTransition(build.Transition)
 	return codersdk.WorkspaceBuild{
@@ -1149,9 +1202,35 @@ func (api *API) convertWorkspaceBuild(
 		Resources:           apiResources,
 		Status:              convertWorkspaceStatus(apiJob.Status, transition),
 		DailyCost:           build.DailyCost,
+		Timings:             convertWorkspaceBuildTimings(timings),
 	}, nil
 }
 
+func convertWorkspaceBuildTimings(timings []database.WorkspaceBuildTiming) []codersdk.WorkspaceBuildTiming {
+	// Timings are returned in the order the stages run.
+	order := map[database.WorkspaceBuildStage]int{}
+	for i, stage := range database.AllWorkspaceBuildStageValues() {
+		order[stage] = i
+	}
+	slices.SortFunc(timings, func(a, b database.WorkspaceBuildTiming) bool {
+		return order[a.Stage] < order[b.Stage]
+	})
+
+	apiTimings := make([]codersdk.WorkspaceBuildTiming, 0, len(timings))
+	for _, timing := range timings {
+		apiTiming := codersdk.WorkspaceBuildTiming{
+			Stage:     codersdk.WorkspaceBuildStage(timing.Stage),
+			StartedAt: timing.StartedAt,
+		}
+		if timing.EndedAt.Valid {
+			apiTiming.EndedAt = codersdk.NewNullTime(timing.EndedAt.Time, true)
+			apiTiming.DurationMS = timing.EndedAt.Time.Sub(timing.StartedAt).Milliseconds()
+		}
+		apiTimings = append(apiTimings, apiTiming)
+	}
+	return apiTimings
+}
+
 func convertWorkspaceResource(resource database.WorkspaceResource, agents []codersdk.WorkspaceAgent, metadata []database.WorkspaceResourceMetadatum) codersdk.WorkspaceResource {
 	var convertedMetadata []codersdk.WorkspaceResourceMetadata
 	for _, field := range metadata {
@@ -19,6 +19,7 @@ import (
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/codersdk/agentsdk"
 	"github.com/coder/coder/provisioner/echo"
 	"github.com/coder/coder/provisionersdk/proto"
 	"github.com/coder/coder/testutil"
@@ -488,6 +489,70 @@ func TestWorkspaceBuildResources(t *testing.T) {
 	})
 }
 
+func TestWorkspaceBuildTimings(t *testing.T) {
+	t.Parallel()
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	for _, state := range []codersdk.WorkspaceAgentLifecycle{
+		codersdk.WorkspaceAgentLifecycleStarting,
+		codersdk.WorkspaceAgentLifecycleReady,
+		// Reporting the end again doesn't observe the stage again.
+		codersdk.WorkspaceAgentLifecycleReady,
+	} {
+		err := agentClient.PostLifecycle(ctx, agentsdk.PostLifecycleRequest{State: state})
+		require.NoError(t, err)
+	}
+
+	build, err := client.WorkspaceBuild(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	stages := make([]codersdk.WorkspaceBuildStage, 0, len(build.Timings))
+	for _, timing := range build.Timings {
+		require.True(t, timing.EndedAt.Valid, "stage %q has not ended", timing.Stage)
+		require.GreaterOrEqual(t, timing.DurationMS, int64(0))
+		stages = append(stages, timing.Stage)
+	}
+	// The echo provisioner doesn't time its stages.
+	require.Equal(t, []codersdk.WorkspaceBuildStage{
+		codersdk.WorkspaceBuildStageQueued,
+		codersdk.WorkspaceBuildStageStartupScript,
+	}, stages)
+
+	metrics, err := api.PrometheusRegistry.Gather()
+	require.NoError(t, err)
+	observed := map[string]uint64{}
+	for _, family := range metrics {
+		if family.GetName() != "coderd_workspace_builds_stage_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.Metric {
+			for _, label := range metric.Label {
+				if label.GetName() == "stage" {
+					observed[label.GetValue()] = metric.Histogram.GetSampleCount()
+				}
+			}
+		}
+	}
+	require.Equal(t, map[string]uint64{
+		string(codersdk.WorkspaceBuildStageQueued):        1,
+		string(codersdk.WorkspaceBuildStageStartupScript): 1,
+	}, observed)
+}
+
 func TestWorkspaceBuildLogs(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
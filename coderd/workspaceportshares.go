@@ -0,0 +1,210 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/util/ptr"
+	"github.com/coder/coder/codersdk"
+)
+
+// @Summary Get workspace port shares
+// @ID get-workspace-port-shares
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspacePortShare
+// @Router /workspaces/{workspace}/port-shares [get]
+func (api *API) workspacePortShares(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	shares, err := api.Database.GetWorkspacePortSharesByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace port shares.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.WorkspacePortShare, 0, len(shares))
+	for _, share := range shares {
+		converted = append(converted, convertWorkspacePortShare(share))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Upsert workspace port share
+// @ID upsert-workspace-port-share
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpsertWorkspacePortShareRequest true "Port share request"
+// @Success 200 {object} codersdk.WorkspacePortShare
+// @Router /workspaces/{workspace}/port-shares [put]
+func (api *API) putWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpsertWorkspacePortShareRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Port < 1 || req.Port > 65535 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid port.",
+			Validations: []codersdk.ValidationError{
+				{Field: "port", Detail: "Must be between 1 and 65535."},
+			},
+		})
+		return
+	}
+	shareLevel := database.AppSharingLevel(req.ShareLevel)
+	if !shareLevel.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid share level.",
+			Validations: []codersdk.ValidationError{
+				{Field: "share_level", Detail: `Must be one of "owner", "authenticated" or "public".`},
+			},
+		})
+		return
+	}
+	now := database.Now()
+	expiresAt := sql.NullTime{}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid expiry.",
+				Validations: []codersdk.ValidationError{
+					{Field: "expires_at", Detail: "Must be in the future."},
+				},
+			})
+			return
+		}
+		expiresAt = sql.NullTime{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	// Shares are stored by agent name so they survive rebuilds, but the agent
+	// must exist when the port is shared.
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	found := false
+	for _, agent := range agents {
+		if agent.Name == req.AgentName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Agent not found.",
+			Validations: []codersdk.ValidationError{
+				{Field: "agent_name", Detail: "The latest build of the workspace has no agent with this name."},
+			},
+		})
+		return
+	}
+
+	share, err := api.Database.UpsertWorkspacePortShare(ctx, database.UpsertWorkspacePortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+		ShareLevel:  shareLevel,
+		CreatedAt:   now,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error sharing port.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspacePortShare(share))
+}
+
+// @Summary Delete workspace port share
+// @ID delete-workspace-port-share
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.DeleteWorkspacePortShareRequest true "Port share request"
+// @Success 204
+// @Router /workspaces/{workspace}/port-shares [delete]
+func (api *API) deleteWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.DeleteWorkspacePortShareRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	_, err := api.Database.GetWorkspacePortShare(ctx, database.GetWorkspacePortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching port share.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.DeleteWorkspacePortShare(ctx, database.DeleteWorkspacePortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking port share.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertWorkspacePortShare(share database.WorkspacePortShare) codersdk.WorkspacePortShare {
+	converted := codersdk.WorkspacePortShare{
+		WorkspaceID: share.WorkspaceID,
+		AgentName:   share.AgentName,
+		Port:        share.Port,
+		ShareLevel:  codersdk.WorkspaceAppSharingLevel(share.ShareLevel),
+		CreatedAt:   share.CreatedAt,
+	}
+	if share.ExpiresAt.Valid {
+		converted.ExpiresAt = ptr.Ref(share.ExpiresAt.Time)
+	}
+	return converted
+}
@@ -0,0 +1,107 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/util/ptr"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/provisioner/echo"
+	"github.com/coder/coder/testutil"
+)
+
+func TestWorkspacePortShares(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	share, err := client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  "example",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+		ExpiresAt:  ptr.Ref(expiresAt),
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAppSharingLevelPublic, share.ShareLevel)
+	require.NotNil(t, share.ExpiresAt)
+	require.WithinDuration(t, expiresAt, *share.ExpiresAt, time.Second)
+
+	// Sharing the same port again replaces the share.
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  "example",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelAuthenticated,
+	})
+	require.NoError(t, err)
+	shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	require.Equal(t, codersdk.WorkspaceAppSharingLevelAuthenticated, shares[0].ShareLevel)
+	require.Nil(t, shares[0].ExpiresAt)
+
+	var apiErr *codersdk.Error
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  "missing",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = client.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  "example",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+		ExpiresAt:  ptr.Ref(time.Now().Add(-time.Hour)),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Other users can't see or change the shares of the workspace.
+	_, err = otherClient.UpsertWorkspacePortShare(ctx, workspace.ID, codersdk.UpsertWorkspacePortShareRequest{
+		AgentName:  "example",
+		Port:       8081,
+		ShareLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+		AgentName: "example",
+		Port:      8080,
+	})
+	require.NoError(t, err)
+	shares, err = client.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, shares)
+
+	err = client.DeleteWorkspacePortShare(ctx, workspace.ID, codersdk.DeleteWorkspacePortShareRequest{
+		AgentName: "example",
+		Port:      8080,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -513,18 +513,23 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		if err != nil {
 			return xerrors.Errorf("marshal provision job: %w", err)
 		}
+		hasMatchingDaemon, err := provisionerdserver.HasMatchingDaemon(ctx, db, template.Provisioner, tags, now)
+		if err != nil {
+			return xerrors.Errorf("check provisioner daemons: %w", err)
+		}
 		provisionerJob, err = db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
-			ID:             uuid.New(),
-			CreatedAt:      now,
-			UpdatedAt:      now,
-			InitiatorID:    apiKey.UserID,
-			OrganizationID: template.OrganizationID,
-			Provisioner:    template.Provisioner,
-			Type:           database.ProvisionerJobTypeWorkspaceBuild,
-			StorageMethod:  templateVersionJob.StorageMethod,
-			FileID:         templateVersionJob.FileID,
-			Input:          input,
-			Tags:           tags,
+			ID:                uuid.New(),
+			CreatedAt:         now,
+			UpdatedAt:         now,
+			InitiatorID:       apiKey.UserID,
+			OrganizationID:    template.OrganizationID,
+			Provisioner:       template.Provisioner,
+			Type:              database.ProvisionerJobTypeWorkspaceBuild,
+			StorageMethod:     templateVersionJob.StorageMethod,
+			FileID:            templateVersionJob.FileID,
+			Input:             input,
+			Tags:              tags,
+			HasMatchingDaemon: hasMatchingDaemon,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
@@ -595,6 +600,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		[]database.WorkspaceResourceMetadatum{},
 		[]database.WorkspaceAgent{},
 		[]database.WorkspaceApp{},
+		[]database.WorkspaceBuildTiming{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -830,6 +836,54 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Update workspace peering by ID
+// @ID update-workspace-peering-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspacePeeringRequest true "Workspace peering update request"
+// @Success 204
+// @Router /workspaces/{workspace}/peering [put]
+func (api *API) putWorkspacePeering(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspacePeeringRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	err := api.Database.UpdateWorkspacePeering(ctx, database.UpdateWorkspacePeeringParams{
+		ID:             workspace.ID,
+		PeeringEnabled: req.Enabled,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace peering.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	newWorkspace := workspace
+	newWorkspace.PeeringEnabled = req.Enabled
+	aReq.New = newWorkspace
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Extend workspace deadline by ID
 // @ID extend-workspace-deadline-by-id
 // @Security CoderSessionToken
@@ -1069,6 +1123,7 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		data.metadata,
 		data.agents,
 		data.apps,
+		data.timings,
 		data.templateVersions,
 	)
 	if err != nil {
@@ -1160,6 +1215,7 @@ func convertWorkspace(
 		AutostartSchedule:                    autostartSchedule,
 		TTLMillis:                            ttlMillis,
 		LastUsedAt:                           workspace.LastUsedAt,
+		PeeringEnabled:                       workspace.PeeringEnabled,
 	}
 }
 
@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
@@ -219,7 +220,7 @@ func (c *client) Manifest(_ context.Context) (agentsdk.Manifest, error) {
 	return c.manifest, nil
 }
 
-func (c *client) Listen(_ context.Context) (net.Conn, error) {
+func (c *client) Listen(_ context.Context, _ int64) (net.Conn, error) {
 	clientConn, serverConn := net.Pipe()
 	closed := make(chan struct{})
 	c.t.Cleanup(func() {
@@ -257,3 +258,11 @@ func (*client) PostStartup(_ context.Context, _ agentsdk.PostStartupRequest) err
 func (*client) PatchStartupLogs(_ context.Context, _ agentsdk.PatchStartupLogs) error {
 	return nil
 }
+
+func (*client) Peers(_ context.Context) ([]agentsdk.Peer, error) {
+	return nil, nil
+}
+
+func (*client) ListenPeer(_ context.Context, _ uuid.UUID) (net.Conn, error) {
+	return nil, xerrors.New("not implemented")
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/cookiejar"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"time"
@@ -84,6 +85,7 @@ func (c *Client) PostMetadata(ctx context.Context, key string, req PostMetadataR
 }
 
 type Manifest struct {
+	AgentID uuid.UUID `json:"agent_id" format:"uuid"`
 	// GitAuthConfigs stores the number of Git configurations
 	// the Coder deployment has. If this number is >0, we
 	// set up special configuration in the workspace.
@@ -99,6 +101,10 @@ type Manifest struct {
 	ShutdownScript        string                                       `json:"shutdown_script"`
 	ShutdownScriptTimeout time.Duration                                `json:"shutdown_script_timeout"`
 	Metadata              []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	// WorkspacePeering is true when peering is enabled for the deployment.
+	// The agent may dial the agents of other workspaces returned by Peers
+	// once its workspace opts in, which can change while the agent runs.
+	WorkspacePeering bool `json:"workspace_peering"`
 }
 
 // Manifest fetches manifest for the currently authenticated workspace agent.
@@ -151,9 +157,52 @@ func (c *Client) Manifest(ctx context.Context) (Manifest, error) {
 }
 
 // Listen connects to the workspace agent coordinate WebSocket
-// that handles connection negotiation.
-func (c *Client) Listen(ctx context.Context) (net.Conn, error) {
-	coordinateURL, err := c.SDK.URL.Parse("/api/v2/workspaceagents/me/coordinate")
+// that handles connection negotiation. Coordinators that persist
+// nodes only send the client nodes newer than the resume version,
+// which is the highest version received before reconnecting.
+func (c *Client) Listen(ctx context.Context, resumeVersion int64) (net.Conn, error) {
+	path := "/api/v2/workspaceagents/me/coordinate"
+	if resumeVersion > 0 {
+		path += fmt.Sprintf("?resume_version=%d", resumeVersion)
+	}
+	return c.dialCoordinator(ctx, path)
+}
+
+// Peer is another workspace agent that the authenticated agent is allowed to
+// dial over the tailnet.
+type Peer struct {
+	AgentID       uuid.UUID `json:"agent_id" format:"uuid"`
+	AgentName     string    `json:"agent_name"`
+	WorkspaceName string    `json:"workspace_name"`
+	// IP is the tailnet address unique to the peer agent.
+	IP netip.Addr `json:"ip"`
+	// Hostnames are the names the peer is reachable at from inside the
+	// workspace, e.g. "backend.coder".
+	Hostnames []string `json:"hostnames"`
+}
+
+// Peers returns the workspace agents the authenticated agent may dial.
+func (c *Client) Peers(ctx context.Context) ([]Peer, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/peers", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	var peers []Peer
+	return peers, json.NewDecoder(res.Body).Decode(&peers)
+}
+
+// ListenPeer connects to the coordinator as a client of the peer agent. Node
+// updates of the peer are written to the returned connection.
+func (c *Client) ListenPeer(ctx context.Context, agentID uuid.UUID) (net.Conn, error) {
+	return c.dialCoordinator(ctx, fmt.Sprintf("/api/v2/workspaceagents/me/peers/%s/coordinate", agentID))
+}
+
+func (c *Client) dialCoordinator(ctx context.Context, path string) (net.Conn, error) {
+	coordinateURL, err := c.SDK.URL.Parse(path)
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
@@ -571,6 +620,9 @@ type GitAuthResponse struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	URL      string `json:"url"`
+	// DeviceFlow is true when the user must authenticate with the device
+	// flow instead of opening the URL.
+	DeviceFlow bool `json:"device_flow"`
 }
 
 // GitAuth submits a URL to fetch a GIT_ASKPASS username and password for.
@@ -594,6 +646,78 @@ func (c *Client) GitAuth(ctx context.Context, gitURL string, listen bool) (GitAu
 	return authResp, json.NewDecoder(res.Body).Decode(&authResp)
 }
 
+type GitAuthAccessTokenResponse struct {
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry" format:"date-time"`
+	// URL is set instead of the access token when the workspace owner must
+	// authenticate with the provider.
+	URL string `json:"url"`
+}
+
+// GitAuthAccessToken returns a fresh access token of the Git auth provider
+// with the ID, for use with the provider's API or other services that accept
+// it.
+func (c *Client) GitAuthAccessToken(ctx context.Context, providerID string) (GitAuthAccessTokenResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/me/gitauth/%s/access-token", providerID), nil)
+	if err != nil {
+		return GitAuthAccessTokenResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return GitAuthAccessTokenResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var token GitAuthAccessTokenResponse
+	return token, json.NewDecoder(res.Body).Decode(&token)
+}
+
+// ErrGitAuthDevicePending is returned while the user has yet to authorize
+// the device.
+var ErrGitAuthDevicePending = xerrors.New("device authorization is pending")
+
+type GitAuthDeviceExchange struct {
+	URL        string `json:"url"`
+	DeviceCode string `json:"device_code"`
+}
+
+// GitAuthDevice begins the device flow for the Git provider matching the URL.
+func (c *Client) GitAuthDevice(ctx context.Context, gitURL string) (codersdk.GitAuthDevice, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/gitauth/device?url="+url.QueryEscape(gitURL), nil)
+	if err != nil {
+		return codersdk.GitAuthDevice{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return codersdk.GitAuthDevice{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var device codersdk.GitAuthDevice
+	return device, json.NewDecoder(res.Body).Decode(&device)
+}
+
+// GitAuthDeviceExchange exchanges the device code for a GIT_ASKPASS username
+// and password. ErrGitAuthDevicePending is returned until the user authorizes
+// the device.
+func (c *Client) GitAuthDeviceExchange(ctx context.Context, req GitAuthDeviceExchange) (GitAuthResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/gitauth/device", req)
+	if err != nil {
+		return GitAuthResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusAccepted {
+		return GitAuthResponse{}, ErrGitAuthDevicePending
+	}
+	if res.StatusCode != http.StatusOK {
+		return GitAuthResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var authResp GitAuthResponse
+	return authResp, json.NewDecoder(res.Body).Decode(&authResp)
+}
+
 type closeFunc func() error
 
 func (c closeFunc) Close() error {
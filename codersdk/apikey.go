@@ -18,10 +18,12 @@ type APIKey struct {
 	ExpiresAt       time.Time   `json:"expires_at" validate:"required" format:"date-time"`
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
-	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token,saml,ldap"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,custom"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// Scopes are the fine-grained scope entries of keys with the custom scope.
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -32,6 +34,11 @@ const (
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
 	LoginTypeToken    LoginType = "token"
+	LoginTypeSAML     LoginType = "saml"
+	LoginTypeLDAP     LoginType = "ldap"
+	// LoginTypeNone is used by service accounts, which cannot log in and
+	// only authenticate with tokens.
+	LoginTypeNone LoginType = "none"
 )
 
 type APIKeyScope string
@@ -42,12 +49,19 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeCustom is a scope composed of fine-grained entries of the
+	// form "<resource type>:<action>[:<resource id>]", eg: "workspace:read",
+	// "audit_log:read" or "template:update:<template id>".
+	APIKeyScopeCustom APIKeyScope = "custom"
 )
 
 type CreateTokenRequest struct {
-	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
-	TokenName string        `json:"token_name"`
+	Lifetime time.Duration `json:"lifetime"`
+	Scope    APIKeyScope   `json:"scope" enums:"all,application_connect,custom"`
+	// Scopes are fine-grained scope entries. Setting them implies the custom
+	// scope.
+	Scopes    []string `json:"scopes,omitempty"`
+	TokenName string   `json:"token_name"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -37,6 +37,9 @@ const (
 	OAuth2StateCookie = "oauth_state"
 	// OAuth2RedirectCookie is the name of the cookie that stores the oauth2 redirect.
 	OAuth2RedirectCookie = "oauth_redirect"
+	// SAMLRequestCookie is the name of the cookie that stores the ID of the
+	// pending SAML authentication request.
+	SAMLRequestCookie = "coder_saml_request"
 
 	// DevURLSessionTokenCookie is the name of the cookie that stores a devurl
 	// token on app domains.
@@ -45,6 +45,7 @@ const (
 	FeatureExternalProvisionerDaemons FeatureName = "external_provisioner_daemons"
 	FeatureAppearance                 FeatureName = "appearance"
 	FeatureAdvancedTemplateScheduling FeatureName = "advanced_template_scheduling"
+	FeatureServiceAccounts            FeatureName = "service_accounts"
 )
 
 // FeatureNames must be kept in-sync with the Feature enum above.
@@ -59,6 +60,7 @@ var FeatureNames = []FeatureName{
 	FeatureExternalProvisionerDaemons,
 	FeatureAppearance,
 	FeatureAdvancedTemplateScheduling,
+	FeatureServiceAccounts,
 }
 
 // Humanize returns the feature name in a human-readable format.
@@ -81,6 +83,7 @@ func (n FeatureName) AlwaysEnable() bool {
 		FeatureMultipleGitAuth:            true,
 		FeatureExternalProvisionerDaemons: true,
 		FeatureAppearance:                 true,
+		FeatureServiceAccounts:            true,
 	}[n]
 }
 
@@ -132,6 +135,8 @@ type DeploymentValues struct {
 	PostgresURL                     clibase.String                  `json:"pg_connection_url,omitempty" typescript:",notnull"`
 	OAuth2                          OAuth2Config                    `json:"oauth2,omitempty" typescript:",notnull"`
 	OIDC                            OIDCConfig                      `json:"oidc,omitempty" typescript:",notnull"`
+	SAML                            SAMLConfig                      `json:"saml,omitempty" typescript:",notnull"`
+	LDAP                            LDAPConfig                      `json:"ldap,omitempty" typescript:",notnull"`
 	Telemetry                       TelemetryConfig                 `json:"telemetry,omitempty" typescript:",notnull"`
 	TLS                             TLSConfig                       `json:"tls,omitempty" typescript:",notnull"`
 	Trace                           TraceConfig                     `json:"trace,omitempty" typescript:",notnull"`
@@ -144,6 +149,7 @@ type DeploymentValues struct {
 	AgentFallbackTroubleshootingURL clibase.URL                     `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
 	AuditLogging                    clibase.Bool                    `json:"audit_logging,omitempty" typescript:",notnull"`
 	BrowserOnly                     clibase.Bool                    `json:"browser_only,omitempty" typescript:",notnull"`
+	WorkspacePeering                clibase.Bool                    `json:"workspace_peering,omitempty" typescript:",notnull"`
 	SCIMAPIKey                      clibase.String                  `json:"scim_api_key,omitempty" typescript:",notnull"`
 	Provisioner                     ProvisionerConfig               `json:"provisioner,omitempty" typescript:",notnull"`
 	RateLimit                       RateLimitConfig                 `json:"rate_limit,omitempty" typescript:",notnull"`
@@ -154,9 +160,11 @@ type DeploymentValues struct {
 	Logging                         LoggingConfig                   `json:"logging,omitempty" typescript:",notnull"`
 	Dangerous                       DangerousConfig                 `json:"dangerous,omitempty" typescript:",notnull"`
 	DisablePathApps                 clibase.Bool                    `json:"disable_path_apps,omitempty" typescript:",notnull"`
+	WorkspaceAppAccessLogRetention  clibase.Duration                `json:"workspace_app_access_log_retention,omitempty" typescript:",notnull"`
 	SessionDuration                 clibase.Duration                `json:"max_session_expiry,omitempty" typescript:",notnull"`
 	DisableSessionExpiryRefresh     clibase.Bool                    `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
 	DisablePasswordAuth             clibase.Bool                    `json:"disable_password_auth,omitempty" typescript:",notnull"`
+	TwoFactorRequired               clibase.Bool                    `json:"two_factor_required,omitempty" typescript:",notnull"`
 	Support                         SupportConfig                   `json:"support,omitempty" typescript:",notnull"`
 	GitAuthProviders                clibase.Struct[[]GitAuthConfig] `json:"git_auth,omitempty" typescript:",notnull"`
 	SSHConfig                       SSHConfig                       `json:"config_ssh,omitempty" typescript:",notnull"`
@@ -218,8 +226,9 @@ type DERPServerConfig struct {
 }
 
 type DERPConfig struct {
-	URL  clibase.String `json:"url" typescript:",notnull"`
-	Path clibase.String `json:"path" typescript:",notnull"`
+	URL                 clibase.String   `json:"url" typescript:",notnull"`
+	Path                clibase.String   `json:"path" typescript:",notnull"`
+	HealthCheckInterval clibase.Duration `json:"health_check_interval" typescript:",notnull"`
 }
 
 type PrometheusConfig struct {
@@ -247,20 +256,52 @@ type OAuth2GithubConfig struct {
 }
 
 type OIDCConfig struct {
-	AllowSignups        clibase.Bool                      `json:"allow_signups" typescript:",notnull"`
-	ClientID            clibase.String                    `json:"client_id" typescript:",notnull"`
-	ClientSecret        clibase.String                    `json:"client_secret" typescript:",notnull"`
-	EmailDomain         clibase.StringArray               `json:"email_domain" typescript:",notnull"`
-	IssuerURL           clibase.String                    `json:"issuer_url" typescript:",notnull"`
-	Scopes              clibase.StringArray               `json:"scopes" typescript:",notnull"`
-	IgnoreEmailVerified clibase.Bool                      `json:"ignore_email_verified" typescript:",notnull"`
-	UsernameField       clibase.String                    `json:"username_field" typescript:",notnull"`
-	EmailField          clibase.String                    `json:"email_field" typescript:",notnull"`
-	AuthURLParams       clibase.Struct[map[string]string] `json:"auth_url_params" typescript:",notnull"`
-	GroupField          clibase.String                    `json:"groups_field" typescript:",notnull"`
-	GroupMapping        clibase.Struct[map[string]string] `json:"group_mapping" typescript:",notnull"`
-	SignInText          clibase.String                    `json:"sign_in_text" typescript:",notnull"`
-	IconURL             clibase.URL                       `json:"icon_url" typescript:",notnull"`
+	AllowSignups        clibase.Bool                        `json:"allow_signups" typescript:",notnull"`
+	ClientID            clibase.String                      `json:"client_id" typescript:",notnull"`
+	ClientSecret        clibase.String                      `json:"client_secret" typescript:",notnull"`
+	EmailDomain         clibase.StringArray                 `json:"email_domain" typescript:",notnull"`
+	IssuerURL           clibase.String                      `json:"issuer_url" typescript:",notnull"`
+	Scopes              clibase.StringArray                 `json:"scopes" typescript:",notnull"`
+	IgnoreEmailVerified clibase.Bool                        `json:"ignore_email_verified" typescript:",notnull"`
+	UsernameField       clibase.String                      `json:"username_field" typescript:",notnull"`
+	EmailField          clibase.String                      `json:"email_field" typescript:",notnull"`
+	AuthURLParams       clibase.Struct[map[string]string]   `json:"auth_url_params" typescript:",notnull"`
+	GroupField          clibase.String                      `json:"groups_field" typescript:",notnull"`
+	GroupMapping        clibase.Struct[map[string]string]   `json:"group_mapping" typescript:",notnull"`
+	UserRoleField       clibase.String                      `json:"user_role_field" typescript:",notnull"`
+	UserRoleMapping     clibase.Struct[map[string][]string] `json:"user_role_mapping" typescript:",notnull"`
+	RefreshInterval     clibase.Duration                    `json:"refresh_interval" typescript:",notnull"`
+	SignInText          clibase.String                      `json:"sign_in_text" typescript:",notnull"`
+	IconURL             clibase.URL                         `json:"icon_url" typescript:",notnull"`
+}
+
+type SAMLConfig struct {
+	AllowSignups   clibase.Bool                      `json:"allow_signups" typescript:",notnull"`
+	EntityID       clibase.String                    `json:"entity_id" typescript:",notnull"`
+	IDPMetadataURL clibase.URL                       `json:"idp_metadata_url" typescript:",notnull"`
+	EmailDomain    clibase.StringArray               `json:"email_domain" typescript:",notnull"`
+	UsernameField  clibase.String                    `json:"username_field" typescript:",notnull"`
+	EmailField     clibase.String                    `json:"email_field" typescript:",notnull"`
+	GroupField     clibase.String                    `json:"groups_field" typescript:",notnull"`
+	GroupMapping   clibase.Struct[map[string]string] `json:"group_mapping" typescript:",notnull"`
+	SignInText     clibase.String                    `json:"sign_in_text" typescript:",notnull"`
+	IconURL        clibase.URL                       `json:"icon_url" typescript:",notnull"`
+}
+
+type LDAPConfig struct {
+	URL           clibase.URL                       `json:"url" typescript:",notnull"`
+	BindDN        clibase.String                    `json:"bind_dn" typescript:",notnull"`
+	BindPassword  clibase.String                    `json:"bind_password" typescript:",notnull"`
+	StartTLS      clibase.Bool                      `json:"start_tls" typescript:",notnull"`
+	CAFile        clibase.String                    `json:"ca_file" typescript:",notnull"`
+	UserBaseDN    clibase.String                    `json:"user_base_dn" typescript:",notnull"`
+	UserFilter    clibase.String                    `json:"user_filter" typescript:",notnull"`
+	UsernameField clibase.String                    `json:"username_field" typescript:",notnull"`
+	EmailField    clibase.String                    `json:"email_field" typescript:",notnull"`
+	GroupField    clibase.String                    `json:"groups_field" typescript:",notnull"`
+	GroupMapping  clibase.Struct[map[string]string] `json:"group_mapping" typescript:",notnull"`
+	AllowSignups  clibase.Bool                      `json:"allow_signups" typescript:",notnull"`
+	SyncInterval  clibase.Duration                  `json:"sync_interval" typescript:",notnull"`
 }
 
 type TelemetryConfig struct {
@@ -299,18 +340,33 @@ type GitAuthConfig struct {
 	Regex        string   `json:"regex"`
 	NoRefresh    bool     `json:"no_refresh"`
 	Scopes       []string `json:"scopes"`
+	// DeviceFlow authenticates with the OAuth2 device authorization grant
+	// instead of a browser redirect, so users on headless machines can
+	// authenticate from the terminal.
+	DeviceFlow    bool   `json:"device_flow"`
+	DeviceCodeURL string `json:"device_code_url"`
 }
 
 type ProvisionerConfig struct {
-	Daemons             clibase.Int64    `json:"daemons" typescript:",notnull"`
-	DaemonPollInterval  clibase.Duration `json:"daemon_poll_interval" typescript:",notnull"`
-	DaemonPollJitter    clibase.Duration `json:"daemon_poll_jitter" typescript:",notnull"`
-	ForceCancelInterval clibase.Duration `json:"force_cancel_interval" typescript:",notnull"`
+	Daemons             clibase.Int64       `json:"daemons" typescript:",notnull"`
+	DaemonPollInterval  clibase.Duration    `json:"daemon_poll_interval" typescript:",notnull"`
+	DaemonPollJitter    clibase.Duration    `json:"daemon_poll_jitter" typescript:",notnull"`
+	ForceCancelInterval clibase.Duration    `json:"force_cancel_interval" typescript:",notnull"`
+	ImportPolicyFiles   clibase.StringArray `json:"import_policy_files" typescript:",notnull"`
+	UnmatchedJobTimeout clibase.Duration    `json:"unmatched_job_timeout" typescript:",notnull"`
 }
 
 type RateLimitConfig struct {
 	DisableAll clibase.Bool  `json:"disable_all" typescript:",notnull"`
 	API        clibase.Int64 `json:"api" typescript:",notnull"`
+	// SharedApp and SharedWorkspace limit the requests per minute to apps and
+	// ports shared at the authenticated or public level.
+	SharedApp       clibase.Int64 `json:"shared_app" typescript:",notnull"`
+	SharedWorkspace clibase.Int64 `json:"shared_workspace" typescript:",notnull"`
+	// SharedAppBandwidth and SharedWorkspaceBandwidth limit the bytes per
+	// second of the responses of shared apps and ports.
+	SharedAppBandwidth       clibase.Int64 `json:"shared_app_bandwidth" typescript:",notnull"`
+	SharedWorkspaceBandwidth clibase.Int64 `json:"shared_workspace_bandwidth" typescript:",notnull"`
 }
 
 type SwaggerConfig struct {
@@ -415,6 +471,14 @@ func (c *DeploymentValues) Options() clibase.OptionSet {
 		deploymentGroupOIDC = clibase.Group{
 			Name: "OIDC",
 		}
+		deploymentGroupSAML = clibase.Group{
+			Name:        "SAML",
+			Description: `Configure login and user-provisioning with a SAML 2.0 identity provider.`,
+		}
+		deploymentGroupLDAP = clibase.Group{
+			Name:        "LDAP",
+			Description: `Configure login and user-provisioning with an LDAP directory, such as Active Directory.`,
+		}
 		deploymentGroupTelemetry = clibase.Group{
 			Name: "Telemetry",
 			Description: `Telemetry is critical to our ability to improve Coder. We strip all personal
@@ -680,6 +744,16 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupNetworkingDERP,
 			YAML:        "configPath",
 		},
+		{
+			Name:        "DERP Health Check Interval",
+			Description: "How often to probe every DERP region for the DERP health report and metrics. Set to 0 to disable.",
+			Flag:        "derp-health-check-interval",
+			Env:         "CODER_DERP_HEALTH_CHECK_INTERVAL",
+			Default:     time.Minute.String(),
+			Value:       &c.DERP.Config.HealthCheckInterval,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "healthCheckInterval",
+		},
 		// TODO: support Git Auth settings.
 		// Prometheus settings
 		{
@@ -906,6 +980,36 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "groupMapping",
 		},
+		{
+			Name:        "OIDC User Role Field",
+			Description: "This field must be set to sync the site roles of users from the OIDC provider on every login. The claim may be a string or a list of strings, and its values are mapped to roles with the user role mapping.",
+			Flag:        "oidc-user-role-field",
+			Env:         "CODER_OIDC_USER_ROLE_FIELD",
+			Default:     "",
+			Value:       &c.OIDC.UserRoleField,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "userRoleField",
+		},
+		{
+			Name:        "OIDC User Role Mapping",
+			Description: "A map of values of the user role field and the site roles in Coder they grant, e.g. {\"coder-admins\": [\"owner\"]}. Values without a mapping grant no roles.",
+			Flag:        "oidc-user-role-mapping",
+			Env:         "CODER_OIDC_USER_ROLE_MAPPING",
+			Default:     "{}",
+			Value:       &c.OIDC.UserRoleMapping,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "userRoleMapping",
+		},
+		{
+			Name:        "OIDC Refresh Interval",
+			Description: "How often the tokens of OIDC users are refreshed with their stored refresh tokens. Users whose refresh tokens were revoked are suspended, and roles are synced from the user info if the user role field is set. Set to 0 to disable refreshing.",
+			Flag:        "oidc-refresh-interval",
+			Env:         "CODER_OIDC_REFRESH_INTERVAL",
+			Default:     "0",
+			Value:       &c.OIDC.RefreshInterval,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "refreshInterval",
+		},
 		{
 			Name:        "OpenID Connect sign in text",
 			Description: "The text to show on the OpenID Connect sign in button.",
@@ -925,6 +1029,226 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "iconURL",
 		},
+		// SAML settings.
+		{
+			Name:        "SAML IdP Metadata URL",
+			Description: "URL of the SAML identity provider's metadata. Setting this enables Login with SAML.",
+			Flag:        "saml-idp-metadata-url",
+			Env:         "CODER_SAML_IDP_METADATA_URL",
+			Value:       &c.SAML.IDPMetadataURL,
+			Group:       &deploymentGroupSAML,
+			YAML:        "idpMetadataURL",
+		},
+		{
+			Name:        "SAML Entity ID",
+			Description: "Entity ID Coder identifies itself with to the SAML identity provider. Defaults to the URL of the service provider metadata.",
+			Flag:        "saml-entity-id",
+			Env:         "CODER_SAML_ENTITY_ID",
+			Value:       &c.SAML.EntityID,
+			Group:       &deploymentGroupSAML,
+			YAML:        "entityID",
+		},
+		{
+			Name:        "SAML Allow Signups",
+			Description: "Whether new users can sign up with SAML.",
+			Flag:        "saml-allow-signups",
+			Env:         "CODER_SAML_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.SAML.AllowSignups,
+			Group:       &deploymentGroupSAML,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "SAML Email Domain",
+			Description: "Email domains that clients logging in with SAML must match.",
+			Flag:        "saml-email-domain",
+			Env:         "CODER_SAML_EMAIL_DOMAIN",
+			Value:       &c.SAML.EmailDomain,
+			Group:       &deploymentGroupSAML,
+			YAML:        "emailDomain",
+		},
+		{
+			Name:        "SAML Username Field",
+			Description: "SAML assertion attribute to use as the username.",
+			Flag:        "saml-username-field",
+			Env:         "CODER_SAML_USERNAME_FIELD",
+			Default:     "uid",
+			Value:       &c.SAML.UsernameField,
+			Group:       &deploymentGroupSAML,
+			YAML:        "usernameField",
+		},
+		{
+			Name:        "SAML Email Field",
+			Description: "SAML assertion attribute to use as the email. Falls back to the subject of the assertion.",
+			Flag:        "saml-email-field",
+			Env:         "CODER_SAML_EMAIL_FIELD",
+			Default:     "email",
+			Value:       &c.SAML.EmailField,
+			Group:       &deploymentGroupSAML,
+			YAML:        "emailField",
+		},
+		{
+			Name:        "SAML Group Field",
+			Description: "SAML assertion attribute to sync groups from. Group sync is disabled when this is empty.",
+			Flag:        "saml-group-field",
+			Env:         "CODER_SAML_GROUP_FIELD",
+			Value:       &c.SAML.GroupField,
+			Group:       &deploymentGroupSAML,
+			YAML:        "groupField",
+		},
+		{
+			Name:        "SAML Group Mapping",
+			Description: "A map of SAML group names and the group in Coder it should map to.",
+			Flag:        "saml-group-mapping",
+			Env:         "CODER_SAML_GROUP_MAPPING",
+			Default:     "{}",
+			Value:       &c.SAML.GroupMapping,
+			Group:       &deploymentGroupSAML,
+			YAML:        "groupMapping",
+		},
+		{
+			Name:        "SAML Sign In Text",
+			Description: "The text to show on the SAML sign in button.",
+			Flag:        "saml-sign-in-text",
+			Env:         "CODER_SAML_SIGN_IN_TEXT",
+			Default:     "SAML",
+			Value:       &c.SAML.SignInText,
+			Group:       &deploymentGroupSAML,
+			YAML:        "signInText",
+		},
+		{
+			Name:        "SAML Icon URL",
+			Description: "URL pointing to the icon to use on the SAML login button.",
+			Flag:        "saml-icon-url",
+			Env:         "CODER_SAML_ICON_URL",
+			Value:       &c.SAML.IconURL,
+			Group:       &deploymentGroupSAML,
+			YAML:        "iconURL",
+		},
+		// LDAP settings.
+		{
+			Name:        "LDAP URL",
+			Description: "URL of the LDAP directory, e.g. ldaps://ldap.example.com. Setting this enables Login with LDAP on the password form.",
+			Flag:        "ldap-url",
+			Env:         "CODER_LDAP_URL",
+			Value:       &c.LDAP.URL,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "url",
+		},
+		{
+			Name:        "LDAP Bind DN",
+			Description: "DN to bind with when searching for users. Searches are anonymous when this is empty.",
+			Flag:        "ldap-bind-dn",
+			Env:         "CODER_LDAP_BIND_DN",
+			Value:       &c.LDAP.BindDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "bindDN",
+		},
+		{
+			Name:        "LDAP Bind Password",
+			Description: "Password of the bind DN.",
+			Flag:        "ldap-bind-password",
+			Env:         "CODER_LDAP_BIND_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(flagSecretKey, "true"),
+			Value:       &c.LDAP.BindPassword,
+			Group:       &deploymentGroupLDAP,
+		},
+		{
+			Name:        "LDAP StartTLS",
+			Description: "Whether to upgrade ldap:// connections to TLS with StartTLS.",
+			Flag:        "ldap-start-tls",
+			Env:         "CODER_LDAP_START_TLS",
+			Value:       &c.LDAP.StartTLS,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "startTLS",
+		},
+		{
+			Name:        "LDAP CA File",
+			Description: "Path to a PEM-encoded CA bundle to verify the certificate of the LDAP directory with. Defaults to the system roots.",
+			Flag:        "ldap-ca-file",
+			Env:         "CODER_LDAP_CA_FILE",
+			Value:       &c.LDAP.CAFile,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "caFile",
+		},
+		{
+			Name:        "LDAP User Base DN",
+			Description: "DN of the subtree to search for users in.",
+			Flag:        "ldap-user-base-dn",
+			Env:         "CODER_LDAP_USER_BASE_DN",
+			Value:       &c.LDAP.UserBaseDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userBaseDN",
+		},
+		{
+			Name:        "LDAP User Filter",
+			Description: "Filter that selects the user logging in. {email} is replaced with the email they entered.",
+			Flag:        "ldap-user-filter",
+			Env:         "CODER_LDAP_USER_FILTER",
+			Default:     "(mail={email})",
+			Value:       &c.LDAP.UserFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userFilter",
+		},
+		{
+			Name:        "LDAP Username Field",
+			Description: "LDAP attribute to use as the username, e.g. sAMAccountName for Active Directory.",
+			Flag:        "ldap-username-field",
+			Env:         "CODER_LDAP_USERNAME_FIELD",
+			Default:     "uid",
+			Value:       &c.LDAP.UsernameField,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "usernameField",
+		},
+		{
+			Name:        "LDAP Email Field",
+			Description: "LDAP attribute to use as the email.",
+			Flag:        "ldap-email-field",
+			Env:         "CODER_LDAP_EMAIL_FIELD",
+			Default:     "mail",
+			Value:       &c.LDAP.EmailField,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailField",
+		},
+		{
+			Name:        "LDAP Group Field",
+			Description: "LDAP attribute to sync groups from, e.g. memberOf. Group sync is disabled when this is empty.",
+			Flag:        "ldap-group-field",
+			Env:         "CODER_LDAP_GROUP_FIELD",
+			Value:       &c.LDAP.GroupField,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupField",
+		},
+		{
+			Name:        "LDAP Group Mapping",
+			Description: "A map of LDAP group DNs and the group in Coder it should map to. Unmapped groups use the value of the first component of their DN.",
+			Flag:        "ldap-group-mapping",
+			Env:         "CODER_LDAP_GROUP_MAPPING",
+			Default:     "{}",
+			Value:       &c.LDAP.GroupMapping,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupMapping",
+		},
+		{
+			Name:        "LDAP Allow Signups",
+			Description: "Whether new users can sign up with LDAP.",
+			Flag:        "ldap-allow-signups",
+			Env:         "CODER_LDAP_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.LDAP.AllowSignups,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "LDAP Sync Interval",
+			Description: "How often the groups of LDAP users are synced from the directory, and users removed from it are suspended. Set to 0 to disable syncing.",
+			Flag:        "ldap-sync-interval",
+			Env:         "CODER_LDAP_SYNC_INTERVAL",
+			Default:     time.Hour.String(),
+			Value:       &c.LDAP.SyncInterval,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "syncInterval",
+		},
 		// Telemetry settings
 		{
 			Name:        "Telemetry Enable",
@@ -1026,6 +1350,25 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupProvisioning,
 			YAML:        "forceCancelInterval",
 		},
+		{
+			Name:        "Import Policy Files",
+			Description: "Paths to Rego policy files evaluated against the Terraform plans of template version imports. Policies must be in the \"coder.template_import\" package and add messages to the \"deny\" set; any message fails the import.",
+			Flag:        "provisioner-import-policy-files",
+			Env:         "CODER_PROVISIONER_IMPORT_POLICY_FILES",
+			Value:       &c.Provisioner.ImportPolicyFiles,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "importPolicyFiles",
+		},
+		{
+			Name:        "Unmatched Job Timeout",
+			Description: "Fail provisioner jobs that have been pending for this long while no online provisioner daemon matches their provisioner and tags. Set to 0 to leave unmatched jobs pending indefinitely.",
+			Flag:        "provisioner-unmatched-job-timeout",
+			Env:         "CODER_PROVISIONER_UNMATCHED_JOB_TIMEOUT",
+			Default:     "0s",
+			Value:       &c.Provisioner.UnmatchedJobTimeout,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "unmatchedJobTimeout",
+		},
 		// RateLimit settings
 		{
 			Name:        "Disable All Rate Limits",
@@ -1282,6 +1625,15 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupNetworking,
 			YAML:        "browserOnly",
 		},
+		{
+			Name:        "Workspace Peering",
+			Description: "Allow workspaces to opt in to peering, which lets their agents dial the agents of the owner's other running workspaces that opted in. Peers are reachable from inside a workspace at \"<workspace>.coder\" and \"<agent>.<workspace>.coder\".",
+			Flag:        "workspace-peering",
+			Env:         "CODER_WORKSPACE_PEERING",
+			Value:       &c.WorkspacePeering,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "workspacePeering",
+		},
 		{
 			Name:        "SCIM API Key",
 			Description: "Enables SCIM and sets the authentication header for the built-in SCIM server. New users are automatically created with OIDC authentication.",
@@ -1300,6 +1652,51 @@ when required by your organization's security policy.`,
 			Value: &c.DisablePathApps,
 			YAML:  "disablePathApps",
 		},
+		{
+			Name:        "Workspace App Access Log Retention",
+			Description: "How long to keep the access logs of requests proxied to workspace apps and ports. Set to 0 to keep access logs forever.",
+			Flag:        "workspace-app-access-log-retention",
+			Env:         "CODER_WORKSPACE_APP_ACCESS_LOG_RETENTION",
+			Default:     (30 * 24 * time.Hour).String(),
+			Value:       &c.WorkspaceAppAccessLogRetention,
+			YAML:        "workspaceAppAccessLogRetention",
+		},
+		{
+			Name:        "Shared App Rate Limit",
+			Description: "Maximum number of requests per minute to each workspace app or port shared at the authenticated or public level. Requests from the workspace owner are not limited. Set to 0 to disable.",
+			Flag:        "shared-app-rate-limit",
+			Env:         "CODER_SHARED_APP_RATE_LIMIT",
+			Default:     "0",
+			Value:       &c.RateLimit.SharedApp,
+			YAML:        "sharedAppRateLimit",
+		},
+		{
+			Name:        "Shared Workspace Rate Limit",
+			Description: "Maximum number of requests per minute to all the shared apps and ports of a workspace combined. Requests from the workspace owner are not limited. Set to 0 to disable.",
+			Flag:        "shared-workspace-rate-limit",
+			Env:         "CODER_SHARED_WORKSPACE_RATE_LIMIT",
+			Default:     "0",
+			Value:       &c.RateLimit.SharedWorkspace,
+			YAML:        "sharedWorkspaceRateLimit",
+		},
+		{
+			Name:        "Shared App Bandwidth Limit",
+			Description: "Maximum bandwidth in bytes per second of the responses of each workspace app or port shared at the authenticated or public level. Responses to the workspace owner are not limited. Set to 0 to disable.",
+			Flag:        "shared-app-bandwidth-limit",
+			Env:         "CODER_SHARED_APP_BANDWIDTH_LIMIT",
+			Default:     "0",
+			Value:       &c.RateLimit.SharedAppBandwidth,
+			YAML:        "sharedAppBandwidthLimit",
+		},
+		{
+			Name:        "Shared Workspace Bandwidth Limit",
+			Description: "Maximum bandwidth in bytes per second of the responses of all the shared apps and ports of a workspace combined. Responses to the workspace owner are not limited. Set to 0 to disable.",
+			Flag:        "shared-workspace-bandwidth-limit",
+			Env:         "CODER_SHARED_WORKSPACE_BANDWIDTH_LIMIT",
+			Default:     "0",
+			Value:       &c.RateLimit.SharedWorkspaceBandwidth,
+			YAML:        "sharedWorkspaceBandwidthLimit",
+		},
 		{
 			Name:        "Session Duration",
 			Description: "The token expiry duration for browser sessions. Sessions may last longer if they are actively making requests, but this functionality can be disabled via --disable-session-expiry-refresh.",
@@ -1330,6 +1727,16 @@ when required by your organization's security policy.`,
 			Group: &deploymentGroupNetworkingHTTP,
 			YAML:  "disablePasswordAuth",
 		},
+		{
+			Name:        "Require Two-Factor Authentication",
+			Description: "Require users that log in with a password to set up two-factor authentication. Until they do, their sessions can only be used to set it up.",
+			Flag:        "two-factor-required",
+			Env:         "CODER_TWO_FACTOR_REQUIRED",
+
+			Value: &c.TwoFactorRequired,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "twoFactorRequired",
+		},
 		{
 			Name:          "Config Path",
 			Description:   `Specify a YAML file to load configuration from.`,
@@ -0,0 +1,74 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"tailscale.com/tailcfg"
+)
+
+// DERPHealthReport is the result of coderd probing every DERP region of the
+// deployment.
+type DERPHealthReport struct {
+	// Healthy is true when every region has at least one healthy node.
+	Healthy   bool               `json:"healthy"`
+	CheckedAt time.Time          `json:"checked_at" format:"date-time"`
+	Regions   []DERPRegionReport `json:"regions"`
+}
+
+type DERPRegionReport struct {
+	RegionID      int    `json:"region_id"`
+	RegionCode    string `json:"region_code"`
+	RegionName    string `json:"region_name"`
+	EmbeddedRelay bool   `json:"embedded_relay"`
+	// Healthy is true when at least one node of the region is healthy.
+	Healthy bool `json:"healthy"`
+	// LatencyMilliseconds is the lowest round trip latency of the healthy
+	// nodes in the region.
+	LatencyMilliseconds float64          `json:"latency_ms"`
+	Nodes               []DERPNodeReport `json:"nodes"`
+}
+
+type DERPNodeReport struct {
+	Name     string `json:"name"`
+	HostName string `json:"host_name"`
+	// Healthy is true when the node relays messages, or answers STUN requests
+	// if it's a STUN-only node.
+	Healthy bool `json:"healthy"`
+	// LatencyMilliseconds is the round trip latency of a DERP ping.
+	LatencyMilliseconds float64 `json:"latency_ms"`
+	Error               string  `json:"error,omitempty"`
+	STUNEnabled         bool    `json:"stun_enabled"`
+	STUNReachable       bool    `json:"stun_reachable"`
+	STUNError           string  `json:"stun_error,omitempty"`
+}
+
+// DebugDERP returns the latest DERP health report of the deployment.
+func (c *Client) DebugDERP(ctx context.Context) (DERPHealthReport, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/derp", nil)
+	if err != nil {
+		return DERPHealthReport{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return DERPHealthReport{}, ReadBodyAsError(res)
+	}
+	var report DERPHealthReport
+	return report, json.NewDecoder(res.Body).Decode(&report)
+}
+
+// DERPMap returns the DERP map that clients of the deployment connect with.
+func (c *Client) DERPMap(ctx context.Context) (*tailcfg.DERPMap, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/derp-map", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var derpMap tailcfg.DERPMap
+	return &derpMap, json.NewDecoder(res.Body).Decode(&derpMap)
+}
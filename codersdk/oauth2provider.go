@@ -0,0 +1,229 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// OAuth2ProviderApp is an app that users may grant scoped API keys to through
+// the OAuth2 authorization code flow.
+type OAuth2ProviderApp struct {
+	ID   uuid.UUID `json:"id" format:"uuid"`
+	Name string    `json:"name"`
+	Icon string    `json:"icon"`
+	// RedirectURIs are the only URIs authorization codes are sent to.
+	RedirectURIs []string `json:"redirect_uris"`
+	// Endpoints are included so admins can copy them into the configuration
+	// of the app.
+	Endpoints OAuth2AppEndpoints `json:"endpoints"`
+}
+
+// OAuth2AppEndpoints are the endpoints an app uses for the OAuth2 flow.
+type OAuth2AppEndpoints struct {
+	Authorization string `json:"authorization"`
+	Token         string `json:"token"`
+}
+
+type PostOAuth2ProviderAppRequest struct {
+	Name         string   `json:"name" validate:"required,oauth2_app_name"`
+	Icon         string   `json:"icon" validate:"omitempty"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1"`
+}
+
+type PutOAuth2ProviderAppRequest struct {
+	Name         string   `json:"name" validate:"required,oauth2_app_name"`
+	Icon         string   `json:"icon" validate:"omitempty"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1"`
+}
+
+// OAuth2ProviderAppFilter narrows down the listed apps.
+type OAuth2ProviderAppFilter struct {
+	// UserID only lists the apps that hold API keys of the user.
+	UserID uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+}
+
+// OAuth2ProviderAppSecret is a client secret of an app. The secret itself is
+// only returned once, when it's created.
+type OAuth2ProviderAppSecret struct {
+	ID                    uuid.UUID `json:"id" format:"uuid"`
+	LastUsedAt            NullTime  `json:"last_used_at" format:"date-time"`
+	ClientSecretTruncated string    `json:"client_secret_truncated"`
+}
+
+// OAuth2ProviderAppSecretFull is a newly created client secret.
+type OAuth2ProviderAppSecretFull struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	ClientSecretFull string    `json:"client_secret_full"`
+}
+
+// OAuth2ProviderGrantType is the grant_type parameter of token requests.
+type OAuth2ProviderGrantType string
+
+const (
+	OAuth2ProviderGrantTypeAuthorizationCode OAuth2ProviderGrantType = "authorization_code"
+	OAuth2ProviderGrantTypeRefreshToken      OAuth2ProviderGrantType = "refresh_token"
+)
+
+// OAuth2ProviderResponseType is the response_type parameter of authorization
+// requests.
+type OAuth2ProviderResponseType string
+
+const (
+	OAuth2ProviderResponseTypeCode OAuth2ProviderResponseType = "code"
+)
+
+// OAuth2ProviderToken is the response of the token endpoint (RFC 6749,
+// section 5.1). The access token is an API key of the user.
+type OAuth2ProviderToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the lifetime of the access token in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// OAuth2ProviderError is the error response of the token endpoint (RFC 6749,
+// section 5.2).
+type OAuth2ProviderError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuth2ProviderApps returns the registered apps. Users may list the apps that
+// hold their API keys with the UserID filter.
+func (c *Client) OAuth2ProviderApps(ctx context.Context, filter OAuth2ProviderAppFilter) ([]OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/oauth2-provider/apps", nil,
+		func(r *http.Request) {
+			if filter.UserID != uuid.Nil {
+				q := url.Values{}
+				q.Set("user_id", filter.UserID.String())
+				r.URL.RawQuery = q.Encode()
+			}
+		})
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var apps []OAuth2ProviderApp
+	return apps, json.NewDecoder(res.Body).Decode(&apps)
+}
+
+// OAuth2ProviderApp returns an app by ID.
+func (c *Client) OAuth2ProviderApp(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+// PostOAuth2ProviderApp registers an app.
+func (c *Client) PostOAuth2ProviderApp(ctx context.Context, req PostOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/oauth2-provider/apps", req)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+// PutOAuth2ProviderApp updates an app.
+func (c *Client) PutOAuth2ProviderApp(ctx context.Context, id uuid.UUID, req PutOAuth2ProviderAppRequest) (OAuth2ProviderApp, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), req)
+	if err != nil {
+		return OAuth2ProviderApp{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OAuth2ProviderApp{}, ReadBodyAsError(res)
+	}
+	var app OAuth2ProviderApp
+	return app, json.NewDecoder(res.Body).Decode(&app)
+}
+
+// DeleteOAuth2ProviderApp deletes an app along with the API keys it holds.
+func (c *Client) DeleteOAuth2ProviderApp(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s", id), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// OAuth2ProviderAppSecrets returns the truncated client secrets of an app.
+func (c *Client) OAuth2ProviderAppSecrets(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var secrets []OAuth2ProviderAppSecret
+	return secrets, json.NewDecoder(res.Body).Decode(&secrets)
+}
+
+// PostOAuth2ProviderAppSecret creates a client secret for an app.
+func (c *Client) PostOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID) (OAuth2ProviderAppSecretFull, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets", appID), nil)
+	if err != nil {
+		return OAuth2ProviderAppSecretFull{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return OAuth2ProviderAppSecretFull{}, ReadBodyAsError(res)
+	}
+	var secret OAuth2ProviderAppSecretFull
+	return secret, json.NewDecoder(res.Body).Decode(&secret)
+}
+
+// DeleteOAuth2ProviderAppSecret deletes a client secret of an app along with
+// the API keys issued with it.
+func (c *Client) DeleteOAuth2ProviderAppSecret(ctx context.Context, appID uuid.UUID, secretID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/secrets/%s", appID, secretID), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RevokeOAuth2ProviderApp deletes the API keys of the authenticated user that
+// an app holds.
+func (c *Client) RevokeOAuth2ProviderApp(ctx context.Context, appID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/oauth2-provider/apps/%s/tokens", appID), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -35,13 +35,36 @@ const (
 	LogLevelError LogLevel = "error"
 )
 
+// ProvisionerDaemonStatus is derived from the daemon's heartbeats.
+type ProvisionerDaemonStatus string
+
+const (
+	ProvisionerDaemonOffline ProvisionerDaemonStatus = "offline"
+	ProvisionerDaemonOnline  ProvisionerDaemonStatus = "online"
+	ProvisionerDaemonBusy    ProvisionerDaemonStatus = "busy"
+	// ProvisionerDaemonUnknown is the status of daemons that never sent a
+	// heartbeat, like daemons older than the server.
+	ProvisionerDaemonUnknown ProvisionerDaemonStatus = "unknown"
+)
+
 type ProvisionerDaemon struct {
-	ID           uuid.UUID         `json:"id" format:"uuid"`
-	CreatedAt    time.Time         `json:"created_at" format:"date-time"`
-	UpdatedAt    sql.NullTime      `json:"updated_at" format:"date-time"`
-	Name         string            `json:"name"`
-	Provisioners []ProvisionerType `json:"provisioners"`
-	Tags         map[string]string `json:"tags"`
+	ID           uuid.UUID               `json:"id" format:"uuid"`
+	CreatedAt    time.Time               `json:"created_at" format:"date-time"`
+	UpdatedAt    sql.NullTime            `json:"updated_at" format:"date-time"`
+	LastSeenAt   *time.Time              `json:"last_seen_at,omitempty" format:"date-time"`
+	Name         string                  `json:"name"`
+	Provisioners []ProvisionerType       `json:"provisioners"`
+	Tags         map[string]string       `json:"tags"`
+	Status       ProvisionerDaemonStatus `json:"status" enums:"offline,online,busy,unknown"`
+	Version      string                  `json:"version"`
+	// Hostname, OperatingSystem and Architecture describe the host the
+	// daemon runs on. They're empty until the first heartbeat.
+	Hostname        string `json:"hostname"`
+	OperatingSystem string `json:"operating_system"`
+	Architecture    string `json:"architecture"`
+	// Concurrency is the number of jobs the daemon can run at once.
+	Concurrency  int        `json:"concurrency"`
+	CurrentJobID *uuid.UUID `json:"current_job_id,omitempty" format:"uuid"`
 }
 
 // ProvisionerJobStatus represents the at-time state of a job.
@@ -69,8 +92,10 @@ const (
 type JobErrorCode string
 
 const (
-	MissingTemplateParameter  JobErrorCode = "MISSING_TEMPLATE_PARAMETER"
-	RequiredTemplateVariables JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES"
+	MissingTemplateParameter      JobErrorCode = "MISSING_TEMPLATE_PARAMETER"
+	RequiredTemplateVariables     JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES"
+	TemplateImportPolicyViolation JobErrorCode = "TEMPLATE_IMPORT_POLICY_VIOLATION"
+	NoMatchingProvisionerDaemon   JobErrorCode = "NO_MATCHING_PROVISIONER_DAEMON"
 )
 
 // ProvisionerJob describes the job executed by the provisioning daemon.
@@ -81,11 +106,15 @@ type ProvisionerJob struct {
 	CompletedAt *time.Time           `json:"completed_at,omitempty" format:"date-time"`
 	CanceledAt  *time.Time           `json:"canceled_at,omitempty" format:"date-time"`
 	Error       string               `json:"error,omitempty"`
-	ErrorCode   JobErrorCode         `json:"error_code,omitempty" enums:"MISSING_TEMPLATE_PARAMETER,REQUIRED_TEMPLATE_VARIABLES"`
+	ErrorCode   JobErrorCode         `json:"error_code,omitempty" enums:"MISSING_TEMPLATE_PARAMETER,REQUIRED_TEMPLATE_VARIABLES,TEMPLATE_IMPORT_POLICY_VIOLATION,NO_MATCHING_PROVISIONER_DAEMON"`
 	Status      ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
 	WorkerID    *uuid.UUID           `json:"worker_id,omitempty" format:"uuid"`
 	FileID      uuid.UUID            `json:"file_id" format:"uuid"`
 	Tags        map[string]string    `json:"tags"`
+	// HasMatchingDaemon is false when no online provisioner daemon can
+	// acquire the job, e.g. because none has all of the job's tags. It's
+	// only meaningful while the job is pending.
+	HasMatchingDaemon bool `json:"has_matching_daemon"`
 }
 
 // ProvisionerJobLog represents the provisioner log entry annotated with source and level.
@@ -96,6 +125,29 @@ type ProvisionerJobLog struct {
 	Level     LogLevel  `json:"log_level" enums:"trace,debug,info,warn,error"`
 	Stage     string    `json:"stage"`
 	Output    string    `json:"output"`
+	// ResourceProgress is set when the log line reports progress for a
+	// specific resource.
+	ResourceProgress *ProvisionerJobResourceProgress `json:"resource_progress,omitempty"`
+}
+
+// ProvisionerJobResourceStatus represents the state of an action being
+// performed on a single resource.
+type ProvisionerJobResourceStatus string
+
+const (
+	ProvisionerJobResourceStatusStarted  ProvisionerJobResourceStatus = "started"
+	ProvisionerJobResourceStatusProgress ProvisionerJobResourceStatus = "progress"
+	ProvisionerJobResourceStatusComplete ProvisionerJobResourceStatus = "complete"
+	ProvisionerJobResourceStatusErrored  ProvisionerJobResourceStatus = "errored"
+)
+
+// ProvisionerJobResourceProgress describes an action being performed on a
+// single resource while a job plans or applies, e.g. creating a container.
+type ProvisionerJobResourceProgress struct {
+	Address        string                       `json:"address"`
+	Action         string                       `json:"action"`
+	Status         ProvisionerJobResourceStatus `json:"status" enums:"started,progress,complete,errored"`
+	ElapsedSeconds int64                        `json:"elapsed_seconds"`
 }
 
 // provisionerJobLogsAfter streams logs that occurred after a specific time.
@@ -0,0 +1,44 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateServiceAccountRequest sets who manages the tokens of a new service
+// account. At most one owner may be set.
+type CreateServiceAccountRequest struct {
+	// OwnerUserID defaults to the user creating the service account if no
+	// owner group is set.
+	OwnerUserID  *uuid.UUID `json:"owner_user_id,omitempty" format:"uuid"`
+	OwnerGroupID *uuid.UUID `json:"owner_group_id,omitempty" format:"uuid"`
+}
+
+// ServiceAccount is a user that cannot log in and only authenticates with
+// tokens. Besides admins, the owner user or the members of the owner group
+// manage its tokens.
+type ServiceAccount struct {
+	UserID       uuid.UUID  `json:"user_id" format:"uuid"`
+	OwnerUserID  *uuid.UUID `json:"owner_user_id,omitempty" format:"uuid"`
+	OwnerGroupID *uuid.UUID `json:"owner_group_id,omitempty" format:"uuid"`
+	CreatedAt    time.Time  `json:"created_at" format:"date-time"`
+}
+
+// ServiceAccount returns the owners of a service account.
+func (c *Client) ServiceAccount(ctx context.Context, user string) (ServiceAccount, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/service-account", user), nil)
+	if err != nil {
+		return ServiceAccount{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ServiceAccount{}, ReadBodyAsError(res)
+	}
+	var account ServiceAccount
+	return account, json.NewDecoder(res.Body).Decode(&account)
+}
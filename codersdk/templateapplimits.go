@@ -0,0 +1,79 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// TemplateAppLimits are the request rate and bandwidth limits of apps and
+// ports shared at the authenticated or public level in the workspaces of a
+// template. They can only lower the deployment-wide limits. Zero values are
+// unlimited.
+type TemplateAppLimits struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	// AppRequestsPerMinute and AppBandwidthBytesPerSecond limit each app and
+	// port.
+	AppRequestsPerMinute       int64 `json:"app_requests_per_minute"`
+	AppBandwidthBytesPerSecond int64 `json:"app_bandwidth_bytes_per_second"`
+	// WorkspaceRequestsPerMinute and WorkspaceBandwidthBytesPerSecond limit
+	// all apps and ports of a workspace combined.
+	WorkspaceRequestsPerMinute       int64     `json:"workspace_requests_per_minute"`
+	WorkspaceBandwidthBytesPerSecond int64     `json:"workspace_bandwidth_bytes_per_second"`
+	UpdatedAt                        time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpdateTemplateAppLimitsRequest replaces the app limits of a template.
+type UpdateTemplateAppLimitsRequest struct {
+	AppRequestsPerMinute             int64 `json:"app_requests_per_minute,omitempty"`
+	AppBandwidthBytesPerSecond       int64 `json:"app_bandwidth_bytes_per_second,omitempty"`
+	WorkspaceRequestsPerMinute       int64 `json:"workspace_requests_per_minute,omitempty"`
+	WorkspaceBandwidthBytesPerSecond int64 `json:"workspace_bandwidth_bytes_per_second,omitempty"`
+}
+
+// TemplateAppLimits returns the app limits of a template.
+func (c *Client) TemplateAppLimits(ctx context.Context, templateID uuid.UUID) (TemplateAppLimits, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/app-limits", templateID), nil)
+	if err != nil {
+		return TemplateAppLimits{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateAppLimits{}, ReadBodyAsError(res)
+	}
+	var limits TemplateAppLimits
+	return limits, json.NewDecoder(res.Body).Decode(&limits)
+}
+
+// UpdateTemplateAppLimits sets the app limits of a template.
+func (c *Client) UpdateTemplateAppLimits(ctx context.Context, templateID uuid.UUID, req UpdateTemplateAppLimitsRequest) (TemplateAppLimits, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/app-limits", templateID), req)
+	if err != nil {
+		return TemplateAppLimits{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateAppLimits{}, ReadBodyAsError(res)
+	}
+	var limits TemplateAppLimits
+	return limits, json.NewDecoder(res.Body).Decode(&limits)
+}
+
+// DeleteTemplateAppLimits removes the app limits of a template, which leaves
+// only the deployment-wide limits.
+func (c *Client) DeleteTemplateAppLimits(ctx context.Context, templateID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/app-limits", templateID), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// TemplateAppRouting contains the rules the app proxy applies to requests for
+// an app in every workspace of a template. Apps are matched by slug.
+type TemplateAppRouting struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	AppSlug    string    `json:"app_slug"`
+	// StripPrefix removes the base path of path-based apps before proxying the
+	// request. Apps that are served from a sub-path, like Jupyter with a
+	// base_url, should disable this to receive the full request path.
+	StripPrefix bool `json:"strip_prefix"`
+	// BasePathHeader is the name of a request header that is set to the base
+	// path of path-based apps, e.g. "X-Forwarded-Prefix".
+	BasePathHeader string `json:"base_path_header"`
+	// Headers are static request headers set on every proxied request.
+	Headers map[string]string `json:"headers"`
+	// AllowedMethods are the HTTP methods that may reach the app. All methods
+	// are allowed if empty.
+	AllowedMethods []string `json:"allowed_methods"`
+	// WebsocketSubprotocols are the WebSocket subprotocols passed through to
+	// the app. All subprotocols are passed through if empty.
+	WebsocketSubprotocols []string  `json:"websocket_subprotocols"`
+	UpdatedAt             time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpsertTemplateAppRoutingRequest replaces the routing rules of an app.
+type UpsertTemplateAppRoutingRequest struct {
+	// StripPrefix defaults to true, which is how apps without routing rules
+	// are proxied.
+	StripPrefix           *bool             `json:"strip_prefix,omitempty"`
+	BasePathHeader        string            `json:"base_path_header,omitempty"`
+	Headers               map[string]string `json:"headers,omitempty"`
+	AllowedMethods        []string          `json:"allowed_methods,omitempty"`
+	WebsocketSubprotocols []string          `json:"websocket_subprotocols,omitempty"`
+}
+
+// TemplateAppRouting returns the routing rules of the apps of a template.
+func (c *Client) TemplateAppRouting(ctx context.Context, templateID uuid.UUID) ([]TemplateAppRouting, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/app-routing", templateID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var routing []TemplateAppRouting
+	return routing, json.NewDecoder(res.Body).Decode(&routing)
+}
+
+// UpsertTemplateAppRouting sets the routing rules of an app of a template.
+func (c *Client) UpsertTemplateAppRouting(ctx context.Context, templateID uuid.UUID, appSlug string, req UpsertTemplateAppRoutingRequest) (TemplateAppRouting, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/app-routing/%s", templateID, appSlug), req)
+	if err != nil {
+		return TemplateAppRouting{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateAppRouting{}, ReadBodyAsError(res)
+	}
+	var routing TemplateAppRouting
+	return routing, json.NewDecoder(res.Body).Decode(&routing)
+}
+
+// DeleteTemplateAppRouting removes the routing rules of an app of a template.
+func (c *Client) DeleteTemplateAppRouting(ctx context.Context, templateID uuid.UUID, appSlug string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/app-routing/%s", templateID, appSlug), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -23,11 +23,13 @@ type Template struct {
 	Provisioner     ProvisionerType `json:"provisioner" enums:"terraform"`
 	ActiveVersionID uuid.UUID       `json:"active_version_id" format:"uuid"`
 	// ActiveUserCount is set to -1 when loading.
-	ActiveUserCount  int                    `json:"active_user_count"`
-	BuildTimeStats   TemplateBuildTimeStats `json:"build_time_stats"`
-	Description      string                 `json:"description"`
-	Icon             string                 `json:"icon"`
-	DefaultTTLMillis int64                  `json:"default_ttl_ms"`
+	ActiveUserCount int                    `json:"active_user_count"`
+	BuildTimeStats  TemplateBuildTimeStats `json:"build_time_stats"`
+	// BuildStageStats breaks the start build time down by stage.
+	BuildStageStats  TemplateBuildStageStats `json:"build_stage_stats"`
+	Description      string                  `json:"description"`
+	Icon             string                  `json:"icon"`
+	DefaultTTLMillis int64                   `json:"default_ttl_ms"`
 	// MaxTTLMillis is an enterprise feature. It's value is only used if your
 	// license is entitled to use the advanced template scheduling feature.
 	MaxTTLMillis  int64     `json:"max_ttl_ms"`
@@ -44,6 +46,7 @@ type TransitionStats struct {
 
 type (
 	TemplateBuildTimeStats      map[WorkspaceTransition]TransitionStats
+	TemplateBuildStageStats     map[WorkspaceBuildStage]TransitionStats
 	UpdateActiveTemplateVersion struct {
 		ID uuid.UUID `json:"id" validate:"required" format:"uuid"`
 	}
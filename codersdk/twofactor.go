@@ -0,0 +1,235 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// TwoFactor describes the second factors a password user set up.
+type TwoFactor struct {
+	TOTP                   bool                 `json:"totp"`
+	RecoveryCodesRemaining int                  `json:"recovery_codes_remaining"`
+	WebAuthnCredentials    []WebAuthnCredential `json:"webauthn_credentials"`
+	// Required is true if the deployment requires password users to set up
+	// a second factor.
+	Required bool `json:"required"`
+}
+
+// TOTPKey is the secret an authenticator app is set up with. URL is commonly
+// displayed as a QR code.
+type TOTPKey struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+// ConfirmTOTPRequest finishes setting up TOTP with a code from the
+// authenticator app.
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TOTPRecoveryCodes can each be used once to log in without the
+// authenticator app. They are only returned when TOTP is set up.
+type TOTPRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// WebAuthnCredential is a security key registered by a user.
+type WebAuthnCredential struct {
+	ID         uuid.UUID `json:"id" format:"uuid"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at" format:"date-time"`
+	LastUsedAt time.Time `json:"last_used_at" format:"date-time"`
+}
+
+// WebAuthnCreationOptions are the options of navigator.credentials.create()
+// to register a security key with.
+type WebAuthnCreationOptions struct {
+	Challenge        []byte `json:"challenge"`
+	RelyingPartyID   string `json:"rp_id"`
+	RelyingPartyName string `json:"rp_name"`
+	UserID           []byte `json:"user_id"`
+	Username         string `json:"username"`
+	// Algorithms are the COSE identifiers of the supported public key
+	// algorithms.
+	Algorithms         []int64  `json:"algorithms"`
+	ExcludeCredentials [][]byte `json:"exclude_credentials"`
+	TimeoutMS          int64    `json:"timeout_ms"`
+}
+
+// WebAuthnRequestOptions are the options of navigator.credentials.get() to
+// log in with a security key.
+type WebAuthnRequestOptions struct {
+	Challenge        []byte   `json:"challenge"`
+	RelyingPartyID   string   `json:"rp_id"`
+	AllowCredentials [][]byte `json:"allow_credentials"`
+	TimeoutMS        int64    `json:"timeout_ms"`
+}
+
+// CreateWebAuthnCredentialRequest registers the security key that responded
+// to WebAuthnCreationOptions.
+type CreateWebAuthnCredentialRequest struct {
+	Name              string `json:"name" validate:"required"`
+	ClientDataJSON    []byte `json:"client_data_json" validate:"required"`
+	AttestationObject []byte `json:"attestation_object" validate:"required"`
+}
+
+// WebAuthnAssertion is the response of a security key to
+// WebAuthnRequestOptions.
+type WebAuthnAssertion struct {
+	CredentialID      []byte `json:"credential_id" validate:"required"`
+	ClientDataJSON    []byte `json:"client_data_json" validate:"required"`
+	AuthenticatorData []byte `json:"authenticator_data" validate:"required"`
+	Signature         []byte `json:"signature" validate:"required"`
+}
+
+// IsTwoFactorRequired returns true if a login failed because the user has
+// to provide a second factor.
+func IsTwoFactorRequired(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode() != http.StatusUnauthorized {
+		return false
+	}
+	for _, validation := range apiErr.Validations {
+		switch validation.Field {
+		case "totp_code", "recovery_code", "webauthn":
+			return true
+		}
+	}
+	return false
+}
+
+// TwoFactor returns the second factors of a user.
+func (c *Client) TwoFactor(ctx context.Context, user string) (TwoFactor, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/two-factor", user), nil)
+	if err != nil {
+		return TwoFactor{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TwoFactor{}, ReadBodyAsError(res)
+	}
+	var twoFactor TwoFactor
+	return twoFactor, json.NewDecoder(res.Body).Decode(&twoFactor)
+}
+
+// ResetTwoFactor removes all second factors of a user, e.g. when they lost
+// them.
+func (c *Client) ResetTwoFactor(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/two-factor", user), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// CreateTOTPKey starts setting up TOTP for a user. The key isn't accepted at
+// login until it's confirmed with ConfirmTOTP.
+func (c *Client) CreateTOTPKey(ctx context.Context, user string) (TOTPKey, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/two-factor/totp", user), nil)
+	if err != nil {
+		return TOTPKey{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPKey{}, ReadBodyAsError(res)
+	}
+	var key TOTPKey
+	return key, json.NewDecoder(res.Body).Decode(&key)
+}
+
+// ConfirmTOTP finishes setting up TOTP for a user, and returns their recovery
+// codes.
+func (c *Client) ConfirmTOTP(ctx context.Context, user string, req ConfirmTOTPRequest) (TOTPRecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/two-factor/totp", user), req)
+	if err != nil {
+		return TOTPRecoveryCodes{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TOTPRecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var codes TOTPRecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// DeleteTOTP removes TOTP and the recovery codes of a user.
+func (c *Client) DeleteTOTP(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/two-factor/totp", user), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebAuthnCreationOptions returns the options to register a security key of a
+// user with.
+func (c *Client) WebAuthnCreationOptions(ctx context.Context, user string) (WebAuthnCreationOptions, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/two-factor/webauthn/challenge", user), nil)
+	if err != nil {
+		return WebAuthnCreationOptions{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WebAuthnCreationOptions{}, ReadBodyAsError(res)
+	}
+	var options WebAuthnCreationOptions
+	return options, json.NewDecoder(res.Body).Decode(&options)
+}
+
+// CreateWebAuthnCredential registers a security key of a user.
+func (c *Client) CreateWebAuthnCredential(ctx context.Context, user string, req CreateWebAuthnCredentialRequest) (WebAuthnCredential, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/two-factor/webauthn", user), req)
+	if err != nil {
+		return WebAuthnCredential{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WebAuthnCredential{}, ReadBodyAsError(res)
+	}
+	var credential WebAuthnCredential
+	return credential, json.NewDecoder(res.Body).Decode(&credential)
+}
+
+// DeleteWebAuthnCredential removes a security key of a user.
+func (c *Client) DeleteWebAuthnCredential(ctx context.Context, user string, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/two-factor/webauthn/%s", user, id), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebAuthnRequestOptions returns the options to log in with a security key
+// after the email and password of the user are verified.
+func (c *Client) WebAuthnRequestOptions(ctx context.Context, req LoginWithPasswordRequest) (WebAuthnRequestOptions, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/webauthn", req)
+	if err != nil {
+		return WebAuthnRequestOptions{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WebAuthnRequestOptions{}, ReadBodyAsError(res)
+	}
+	var options WebAuthnRequestOptions
+	return options, json.NewDecoder(res.Body).Decode(&options)
+}
@@ -45,6 +45,9 @@ type User struct {
 	OrganizationIDs []uuid.UUID `json:"organization_ids" format:"uuid"`
 	Roles           []Role      `json:"roles"`
 	AvatarURL       string      `json:"avatar_url" format:"uri"`
+	// ServiceAccount is set for users that cannot log in and only
+	// authenticate with tokens.
+	ServiceAccount bool `json:"service_account,omitempty" table:"service account"`
 }
 
 type GetUsersResponse struct {
@@ -68,8 +71,11 @@ type CreateFirstUserResponse struct {
 type CreateUserRequest struct {
 	Email          string    `json:"email" validate:"required,email" format:"email"`
 	Username       string    `json:"username" validate:"required,username"`
-	Password       string    `json:"password" validate:"required"`
+	Password       string    `json:"password" validate:"required_without=ServiceAccount"`
 	OrganizationID uuid.UUID `json:"organization_id" validate:"required" format:"uuid"`
+	// ServiceAccount creates a user that cannot log in and only authenticates
+	// with tokens. The password must be empty.
+	ServiceAccount *CreateServiceAccountRequest `json:"service_account,omitempty"`
 }
 
 type UpdateUserProfileRequest struct {
@@ -94,6 +100,11 @@ type UserRoles struct {
 type LoginWithPasswordRequest struct {
 	Email    string `json:"email" validate:"required,email" format:"email"`
 	Password string `json:"password" validate:"required"`
+	// TOTPCode, RecoveryCode or WebAuthn is required if the user set up
+	// two-factor authentication.
+	TOTPCode     string             `json:"totp_code,omitempty"`
+	RecoveryCode string             `json:"recovery_code,omitempty"`
+	WebAuthn     *WebAuthnAssertion `json:"webauthn,omitempty"`
 }
 
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
@@ -110,6 +121,8 @@ type AuthMethods struct {
 	Password AuthMethod     `json:"password"`
 	Github   AuthMethod     `json:"github"`
 	OIDC     OIDCAuthMethod `json:"oidc"`
+	SAML     SAMLAuthMethod `json:"saml"`
+	LDAP     AuthMethod     `json:"ldap"`
 }
 
 type AuthMethod struct {
@@ -122,6 +135,12 @@ type OIDCAuthMethod struct {
 	IconURL    string `json:"iconUrl"`
 }
 
+type SAMLAuthMethod struct {
+	AuthMethod
+	SignInText string `json:"signInText"`
+	IconURL    string `json:"iconUrl"`
+}
+
 // HasFirstUser returns whether the first user has been created.
 func (c *Client) HasFirstUser(ctx context.Context) (bool, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/first", nil)
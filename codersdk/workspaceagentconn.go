@@ -19,6 +19,7 @@ import (
 	"golang.org/x/xerrors"
 	"tailscale.com/ipn/ipnstate"
 	"tailscale.com/net/speedtest"
+	"tailscale.com/tailcfg"
 
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/tailnet"
@@ -305,6 +306,38 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// WorkspaceAgentDebugInfo is collected by the agent for support bundles.
+// @typescript-ignore WorkspaceAgentDebugInfo
+type WorkspaceAgentDebugInfo struct {
+	Version         string           `json:"version"`
+	OperatingSystem string           `json:"operating_system"`
+	Architecture    string           `json:"architecture"`
+	Node            *tailnet.Node    `json:"node"`
+	DERPMap         *tailcfg.DERPMap `json:"derp_map"`
+	// Stats are the connection stats the agent last reported to coderd. It's
+	// raw JSON because the type is defined by agentsdk.
+	Stats json.RawMessage `json:"stats"`
+	// Logs has the end of every log file of the agent, keyed by file name.
+	Logs map[string]string `json:"logs"`
+}
+
+// DebugInfo returns the network state, stats and logs of the workspace agent.
+func (c *WorkspaceAgentConn) DebugInfo(ctx context.Context) (WorkspaceAgentDebugInfo, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/debug/info", nil)
+	if err != nil {
+		return WorkspaceAgentDebugInfo{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentDebugInfo{}, ReadBodyAsError(res)
+	}
+
+	var info WorkspaceAgentDebugInfo
+	return info, json.NewDecoder(res.Body).Decode(&info)
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -10,7 +10,9 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/netip"
+	"net/url"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -154,25 +156,62 @@ type DialWorkspaceAgentOptions struct {
 	BlockEndpoints bool
 }
 
-func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, options *DialWorkspaceAgentOptions) (agentConn *WorkspaceAgentConn, err error) {
-	if options == nil {
-		options = &DialWorkspaceAgentOptions{}
-	}
+// WorkspaceAgentConnectionInfo returns the information required to connect to
+// the workspace agent.
+func (c *Client) WorkspaceAgentConnectionInfo(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentConnectionInfo, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/connection", agentID), nil)
 	if err != nil {
-		return nil, err
+		return WorkspaceAgentConnectionInfo{}, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return nil, ReadBodyAsError(res)
+		return WorkspaceAgentConnectionInfo{}, ReadBodyAsError(res)
 	}
 	var connInfo WorkspaceAgentConnectionInfo
 	err = json.NewDecoder(res.Body).Decode(&connInfo)
 	if err != nil {
-		return nil, xerrors.Errorf("decode conn info: %w", err)
+		return WorkspaceAgentConnectionInfo{}, xerrors.Errorf("decode conn info: %w", err)
 	}
+	return connInfo, nil
+}
 
-	ip := tailnet.IP()
+// WorkspaceAgentCoordinate connects to the coordinator as a client of the
+// workspace agent. Node updates of the agent are written to the returned
+// connection, and the node of the client must be written to it.
+func (c *Client) WorkspaceAgentCoordinate(ctx context.Context, agentID uuid.UUID) (net.Conn, error) {
+	coordinateURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create cookie jar: %w", err)
+	}
+	jar.SetCookies(coordinateURL, []*http.Cookie{{
+		Name:  SessionTokenCookie,
+		Value: c.SessionToken(),
+	}})
+	// nolint:bodyclose
+	ws, res, err := websocket.Dial(ctx, coordinateURL.String(), &websocket.DialOptions{
+		HTTPClient: &http.Client{
+			Jar:       jar,
+			Transport: c.HTTPClient.Transport,
+		},
+		// Need to disable compression to avoid a data-race.
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		if res == nil {
+			return nil, err
+		}
+		return nil, ReadBodyAsError(res)
+	}
+	return websocket.NetConn(ctx, ws, websocket.MessageBinary), nil
+}
+
+// DERPHeader returns the headers sent with every request, so they can be
+// sent to DERP servers as well.
+func (c *Client) DERPHeader() *http.Header {
 	var header http.Header
 	headerTransport, ok := c.HTTPClient.Transport.(interface {
 		Header() http.Header
@@ -180,10 +219,23 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 	if ok {
 		header = headerTransport.Header()
 	}
+	return &header
+}
+
+func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, options *DialWorkspaceAgentOptions) (agentConn *WorkspaceAgentConn, err error) {
+	if options == nil {
+		options = &DialWorkspaceAgentOptions{}
+	}
+	connInfo, err := c.WorkspaceAgentConnectionInfo(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := tailnet.IP()
 	conn, err := tailnet.NewConn(&tailnet.Options{
 		Addresses:      []netip.Prefix{netip.PrefixFrom(ip, 128)},
 		DERPMap:        connInfo.DERPMap,
-		DERPHeader:     &header,
+		DERPHeader:     c.DERPHeader(),
 		Logger:         options.Logger,
 		BlockEndpoints: options.BlockEndpoints,
 	})
@@ -220,13 +272,21 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 	}()
 	closed := make(chan struct{})
 	first := make(chan error)
+	// resumeVersion is the highest version of the agent node received. The
+	// tailnet keeps the node when the coordinator reconnects, so it resumes
+	// from this version.
+	var resumeVersion atomic.Int64
 	go func() {
 		defer close(closed)
 		isFirst := true
 		for retrier := retry.New(50*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
-			options.Logger.Debug(ctx, "connecting")
+			options.Logger.Debug(ctx, "connecting", slog.F("resume_version", resumeVersion.Load()))
+			dialURL := *coordinateURL
+			if version := resumeVersion.Load(); version > 0 {
+				dialURL.RawQuery = url.Values{"resume_version": {strconv.FormatInt(version, 10)}}.Encode()
+			}
 			// nolint:bodyclose
-			ws, res, err := websocket.Dial(ctx, coordinateURL.String(), &websocket.DialOptions{
+			ws, res, err := websocket.Dial(ctx, dialURL.String(), &websocket.DialOptions{
 				HTTPClient: httpClient,
 				// Need to disable compression to avoid a data-race.
 				CompressionMode: websocket.CompressionDisabled,
@@ -246,8 +306,17 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 				options.Logger.Debug(ctx, "failed to dial", slog.Error(err))
 				continue
 			}
-			sendNode, errChan := tailnet.ServeCoordinator(websocket.NetConn(ctx, ws, websocket.MessageBinary), func(node []*tailnet.Node) error {
-				return conn.UpdateNodes(node, false)
+			sendNode, errChan := tailnet.ServeCoordinator(websocket.NetConn(ctx, ws, websocket.MessageBinary), func(nodes []*tailnet.Node) error {
+				err := conn.UpdateNodes(nodes, false)
+				if err != nil {
+					return err
+				}
+				for _, node := range nodes {
+					if node.Version > resumeVersion.Load() {
+						resumeVersion.Store(node.Version)
+					}
+				}
+				return nil
 			})
 			conn.SetNodeCallback(sendNode)
 			options.Logger.Debug(ctx, "serving coordinator")
@@ -421,6 +490,20 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentStartupLogs returns the startup logs the agent has sent so far.
+func (c *Client) WorkspaceAgentStartupLogs(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentStartupLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/startup-logs", agentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []WorkspaceAgentStartupLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
+
 func (c *Client) WorkspaceAgentStartupLogsAfter(ctx context.Context, agentID uuid.UUID, after int64) (<-chan []WorkspaceAgentStartupLog, io.Closer, error) {
 	afterQuery := ""
 	if after != 0 {
@@ -494,6 +577,10 @@ func (g GitProvider) Pretty() string {
 		return "GitLab"
 	case GitProviderBitBucket:
 		return "Bitbucket"
+	case GitProviderGitea:
+		return "Gitea"
+	case GitProviderOAuth2:
+		return "OAuth2"
 	default:
 		return string(g)
 	}
@@ -504,8 +591,26 @@ const (
 	GitProviderGitHub      GitProvider = "github"
 	GitProviderGitLab      GitProvider = "gitlab"
 	GitProviderBitBucket   GitProvider = "bitbucket"
+	GitProviderGitea       GitProvider = "gitea"
+	// GitProviderOAuth2 is a generic OAuth2 provider. It isn't matched
+	// against Git URLs unless a regex is configured, so its tokens are only
+	// used for the provider's API.
+	GitProviderOAuth2 GitProvider = "oauth2"
 )
 
+// GitAuthDevice is a pending device authorization. The user must enter the
+// user code at the verification URI before the device code can be exchanged
+// for a token.
+type GitAuthDevice struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	// ExpiresIn is the number of seconds until the device code expires.
+	ExpiresIn int `json:"expires_in"`
+	// Interval is the number of seconds to wait between exchange attempts.
+	Interval int `json:"interval"`
+}
+
 type WorkspaceAgentStartupLog struct {
 	ID        int64     `json:"id"`
 	CreatedAt time.Time `json:"created_at" format:"date-time"`
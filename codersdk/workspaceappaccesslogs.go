@@ -0,0 +1,65 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WorkspaceAppAccessLog is a single request that was proxied to a workspace
+// app or port.
+type WorkspaceAppAccessLog struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	// UserID and Username are empty for unauthenticated requests to public
+	// apps.
+	UserID        *uuid.UUID               `json:"user_id,omitempty" format:"uuid"`
+	Username      string                   `json:"username,omitempty"`
+	WorkspaceID   uuid.UUID                `json:"workspace_id" format:"uuid"`
+	AgentID       uuid.UUID                `json:"agent_id" format:"uuid"`
+	AppSlugOrPort string                   `json:"app_slug_or_port"`
+	AccessMethod  string                   `json:"access_method" enums:"path,subdomain,terminal"`
+	SharingLevel  WorkspaceAppSharingLevel `json:"sharing_level" enums:"owner,authenticated,public"`
+	IP            string                   `json:"ip"`
+	Method        string                   `json:"method"`
+	Path          string                   `json:"path"`
+	StatusCode    int32                    `json:"status_code"`
+	BytesWritten  int64                    `json:"bytes_written"`
+	DurationMS    int64                    `json:"duration_ms"`
+}
+
+type WorkspaceAppAccessLogsRequest struct {
+	// App filters the access logs to a single app slug or port.
+	App string `json:"app,omitempty"`
+	Pagination
+}
+
+// WorkspaceAppAccessLogs returns the access logs of the apps and ports in a
+// workspace, newest first.
+func (c *Client) WorkspaceAppAccessLogs(ctx context.Context, workspaceID uuid.UUID, req WorkspaceAppAccessLogsRequest) ([]WorkspaceAppAccessLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/app-access-logs", workspaceID), nil,
+		req.Pagination.asRequestOption(),
+		func(r *http.Request) {
+			if req.App == "" {
+				return
+			}
+			q := r.URL.Query()
+			q.Set("app", req.App)
+			r.URL.RawQuery = q.Encode()
+		},
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var logs []WorkspaceAppAccessLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
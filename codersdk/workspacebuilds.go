@@ -48,6 +48,33 @@ const (
 	BuildReasonAutostop BuildReason = "autostop"
 )
 
+// WorkspaceBuildStage is a timed stage of a workspace build.
+type WorkspaceBuildStage string
+
+const (
+	// "queued" is the time between the build being created and a
+	// provisioner daemon acquiring its job.
+	WorkspaceBuildStageQueued WorkspaceBuildStage = "queued"
+	WorkspaceBuildStageInit   WorkspaceBuildStage = "init"
+	WorkspaceBuildStagePlan   WorkspaceBuildStage = "plan"
+	WorkspaceBuildStageApply  WorkspaceBuildStage = "apply"
+	// "agent_connect" spans from the build creating the agents until the last
+	// agent first connects.
+	WorkspaceBuildStageAgentConnect WorkspaceBuildStage = "agent_connect"
+	// "startup_script" spans from the first agent starting its startup script
+	// until the last agent finishes it.
+	WorkspaceBuildStageStartupScript WorkspaceBuildStage = "startup_script"
+)
+
+// WorkspaceBuildTiming is how long a single stage of a workspace build took.
+type WorkspaceBuildTiming struct {
+	Stage     WorkspaceBuildStage `json:"stage" enums:"queued,init,plan,apply,agent_connect,startup_script"`
+	StartedAt time.Time           `json:"started_at" format:"date-time"`
+	// EndedAt is null while the stage is in progress.
+	EndedAt    NullTime `json:"ended_at,omitempty" format:"date-time"`
+	DurationMS int64    `json:"duration_ms"`
+}
+
 // WorkspaceBuild is an at-point representation of a workspace state.
 // BuildNumbers start at 1 and increase by 1 for each subsequent build
 type WorkspaceBuild struct {
@@ -71,6 +98,9 @@ type WorkspaceBuild struct {
 	MaxDeadline         NullTime            `json:"max_deadline,omitempty" format:"date-time"`
 	Status              WorkspaceStatus     `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
 	DailyCost           int32               `json:"daily_cost"`
+	// Timings are ordered by stage. Stages that haven't started yet, or that
+	// don't apply to the build, are omitted.
+	Timings []WorkspaceBuildTiming `json:"timings"`
 }
 
 // WorkspaceResource describes resources used to create a workspace, for instance:
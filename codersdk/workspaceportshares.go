@@ -0,0 +1,82 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WorkspacePortShare grants access to a port in a workspace that isn't
+// defined as an app. Shared ports are reached through port-based subdomain
+// URLs, e.g. "8080--main--backend--kyle.apps.example.com".
+type WorkspacePortShare struct {
+	WorkspaceID uuid.UUID                `json:"workspace_id" format:"uuid"`
+	AgentName   string                   `json:"agent_name"`
+	Port        int32                    `json:"port"`
+	ShareLevel  WorkspaceAppSharingLevel `json:"share_level" enums:"owner,authenticated,public"`
+	CreatedAt   time.Time                `json:"created_at" format:"date-time"`
+	// ExpiresAt is nil if the share never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
+}
+
+// UpsertWorkspacePortShareRequest shares a port, replacing the existing
+// share of the same port.
+type UpsertWorkspacePortShareRequest struct {
+	AgentName  string                   `json:"agent_name" validate:"required"`
+	Port       int32                    `json:"port" validate:"required"`
+	ShareLevel WorkspaceAppSharingLevel `json:"share_level" validate:"required" enums:"owner,authenticated,public"`
+	// ExpiresAt is optional. The share never expires if it is unset.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
+}
+
+type DeleteWorkspacePortShareRequest struct {
+	AgentName string `json:"agent_name" validate:"required"`
+	Port      int32  `json:"port" validate:"required"`
+}
+
+// WorkspacePortShares returns the ports shared in a workspace, including
+// expired shares.
+func (c *Client) WorkspacePortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShare, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-shares", workspaceID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var shares []WorkspacePortShare
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// UpsertWorkspacePortShare shares a port in a workspace.
+func (c *Client) UpsertWorkspacePortShare(ctx context.Context, workspaceID uuid.UUID, req UpsertWorkspacePortShareRequest) (WorkspacePortShare, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/port-shares", workspaceID), req)
+	if err != nil {
+		return WorkspacePortShare{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspacePortShare{}, ReadBodyAsError(res)
+	}
+	var share WorkspacePortShare
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// DeleteWorkspacePortShare revokes the share of a port in a workspace.
+func (c *Client) DeleteWorkspacePortShare(ctx context.Context, workspaceID uuid.UUID, req DeleteWorkspacePortShareRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/port-shares", workspaceID), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
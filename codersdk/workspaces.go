@@ -34,6 +34,9 @@ type Workspace struct {
 	AutostartSchedule                    *string        `json:"autostart_schedule,omitempty"`
 	TTLMillis                            *int64         `json:"ttl_ms,omitempty"`
 	LastUsedAt                           time.Time      `json:"last_used_at" format:"date-time"`
+	// PeeringEnabled is whether the agents of the workspace connect to the
+	// agents of the owner's other workspaces that enabled peering.
+	PeeringEnabled bool `json:"peering_enabled"`
 }
 
 type WorkspacesRequest struct {
@@ -253,6 +256,28 @@ func (c *Client) UpdateWorkspaceTTL(ctx context.Context, id uuid.UUID, req Updat
 	return nil
 }
 
+// UpdateWorkspacePeeringRequest is a request to opt a workspace in or out of
+// workspace peering.
+type UpdateWorkspacePeeringRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// UpdateWorkspacePeering sets whether the agents of the workspace may connect
+// to the agents of the owner's other workspaces. Workspace peering must also
+// be enabled for the deployment.
+func (c *Client) UpdateWorkspacePeering(ctx context.Context, id uuid.UUID, req UpdateWorkspacePeeringRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/peering", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace peering: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // PutExtendWorkspaceRequest is a request to extend the deadline of
 // the active workspace build.
 type PutExtendWorkspaceRequest struct {
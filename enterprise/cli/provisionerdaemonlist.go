@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/cli"
+	"github.com/coder/coder/cli/clibase"
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func (r *RootCmd) provisionerDaemonList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]provisionerDaemonTableRow{}, []string{"name", "status", "version", "current job", "tags", "last seen"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "list",
+		Short: "List provisioner daemons and their status",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			daemons, err := client.ProvisionerDaemons(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("get provisioner daemons: %w", err)
+			}
+
+			if len(daemons) == 0 {
+				_, _ = fmt.Fprintf(inv.Stderr, "%s No provisioner daemons found! Start one with:\n\n", agpl.Caret)
+				_, _ = fmt.Fprintln(inv.Stderr, "  $ coder provisionerd start")
+				return nil
+			}
+
+			out, err := formatter.Format(inv.Context(), provisionerDaemonsToRows(daemons...))
+			if err != nil {
+				return xerrors.Errorf("display provisioner daemons: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type provisionerDaemonTableRow struct {
+	// For json output:
+	codersdk.ProvisionerDaemon `table:"-"`
+
+	// For table output:
+	Name            string `json:"-" table:"name,default_sort"`
+	Status          string `json:"-" table:"status"`
+	Version         string `json:"-" table:"version"`
+	CurrentJob      string `json:"-" table:"current job"`
+	Tags            string `json:"-" table:"tags"`
+	LastSeen        string `json:"-" table:"last seen"`
+	Hostname        string `json:"-" table:"hostname"`
+	OperatingSystem string `json:"-" table:"os"`
+	Architecture    string `json:"-" table:"arch"`
+	Provisioners    string `json:"-" table:"provisioners"`
+	Concurrency     int    `json:"-" table:"concurrency"`
+}
+
+func provisionerDaemonsToRows(daemons ...codersdk.ProvisionerDaemon) []provisionerDaemonTableRow {
+	rows := make([]provisionerDaemonTableRow, 0, len(daemons))
+	for _, daemon := range daemons {
+		row := provisionerDaemonTableRow{
+			ProvisionerDaemon: daemon,
+			Name:              daemon.Name,
+			Status:            string(daemon.Status),
+			Version:           daemon.Version,
+			LastSeen:          "never",
+			Hostname:          daemon.Hostname,
+			OperatingSystem:   daemon.OperatingSystem,
+			Architecture:      daemon.Architecture,
+			Concurrency:       daemon.Concurrency,
+		}
+		if daemon.CurrentJobID != nil {
+			row.CurrentJob = daemon.CurrentJobID.String()
+		}
+		if daemon.LastSeenAt != nil {
+			row.LastSeen = daemon.LastSeenAt.Format(time.RFC3339)
+		}
+
+		tags := make([]string, 0, len(daemon.Tags))
+		for key, value := range daemon.Tags {
+			tags = append(tags, key+"="+value)
+		}
+		sort.Strings(tags)
+		row.Tags = strings.Join(tags, " ")
+
+		provisioners := make([]string, 0, len(daemon.Provisioners))
+		for _, provisioner := range daemon.Provisioners {
+			provisioners = append(provisioners, string(provisioner))
+		}
+		row.Provisioners = strings.Join(provisioners, ",")
+
+		rows = append(rows, row)
+	}
+	return rows
+}
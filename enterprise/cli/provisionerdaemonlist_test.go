@@ -0,0 +1,62 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/testutil"
+)
+
+func TestProvisionerDaemonList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{IncludeProvisionerDaemon: true},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, conf := newCLI(t, "provisionerd", "list", "--output", "json")
+		clitest.SetupConfig(t, client, conf)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var daemons []codersdk.ProvisionerDaemon
+		require.NoError(t, json.Unmarshal(out.Bytes(), &daemons))
+		require.Len(t, daemons, 1)
+		require.Contains(t, daemons[0].Provisioners, codersdk.ProvisionerTypeEcho)
+	})
+
+	t.Run("Table", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{IncludeProvisionerDaemon: true},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, conf := newCLI(t, "provisionerd", "list")
+		clitest.SetupConfig(t, client, conf)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "STATUS")
+		require.Contains(t, out.String(), "scope=organization")
+	})
+}
@@ -28,6 +28,7 @@ func (r *RootCmd) provisionerDaemons() *clibase.Cmd {
 		Use:   "provisionerd",
 		Short: "Manage provisioner daemons",
 		Children: []*clibase.Cmd{
+			r.provisionerDaemonList(),
 			r.provisionerDaemonStart(),
 		},
 	}
@@ -153,8 +153,17 @@ func New(ctx context.Context, options *Options) (*API, error) {
 				r.Get("/", api.scimGetUsers)
 				r.Post("/", api.scimPostUser)
 				r.Get("/{id}", api.scimGetUser)
+				r.Put("/{id}", api.scimPutUser)
 				r.Patch("/{id}", api.scimPatchUser)
 			})
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Put("/{id}", api.scimPutGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
 		})
 	}
 
@@ -324,7 +333,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 	if changed, enabled := featureChanged(codersdk.FeatureHighAvailability); changed {
 		coordinator := agpltailnet.NewCoordinator()
 		if enabled {
-			haCoordinator, err := tailnet.NewCoordinator(api.Logger, api.Pubsub)
+			haCoordinator, err := tailnet.NewCoordinator(api.Logger, api.Database, api.Pubsub, api.PrometheusRegistry)
 			if err != nil {
 				api.Logger.Error(ctx, "unable to set up high availability coordinator", slog.Error(err))
 				// If we try to setup the HA coordinator and it fails, nothing
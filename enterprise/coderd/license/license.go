@@ -52,6 +52,12 @@ func Entitlements(
 		return entitlements, xerrors.Errorf("query active user count: %w", err)
 	}
 
+	// nolint:gocritic // Getting active service account count is a system function.
+	activeServiceAccountCount, err := db.GetActiveServiceAccountCount(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return entitlements, xerrors.Errorf("query active service account count: %w", err)
+	}
+
 	allFeatures := false
 
 	// Here we loop through licenses to detect enabled features.
@@ -116,6 +122,13 @@ func Entitlements(
 		}
 	}
 
+	if entitlements.Features[codersdk.FeatureServiceAccounts].Entitlement != codersdk.EntitlementNotEntitled {
+		// Service accounts don't count towards the user limit of licenses
+		// that are entitled to them. The user limit feature refers to the
+		// count, so its actual value is updated as well.
+		activeUserCount -= activeServiceAccountCount
+	}
+
 	if entitlements.HasLicense {
 		userLimit := entitlements.Features[codersdk.FeatureUserLimit].Limit
 		if userLimit != nil && activeUserCount > *userLimit {
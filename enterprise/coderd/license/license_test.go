@@ -154,6 +154,33 @@ func TestEntitlements(t *testing.T) {
 		require.True(t, entitlements.HasLicense)
 		require.Contains(t, entitlements.Warnings, "Your deployment has 2 active users but is only licensed for 1.")
 	})
+	t.Run("ServiceAccountsExcluded", func(t *testing.T) {
+		t.Parallel()
+		db := dbfake.New()
+		db.InsertUser(context.Background(), database.InsertUserParams{
+			Username:  "test1",
+			LoginType: database.LoginTypePassword,
+		})
+		db.InsertUser(context.Background(), database.InsertUserParams{
+			Username:  "ci",
+			LoginType: database.LoginTypeNone,
+		})
+		db.InsertLicense(context.Background(), database.InsertLicenseParams{
+			JWT: coderdenttest.GenerateLicense(t, coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureUserLimit:       1,
+					codersdk.FeatureServiceAccounts: 1,
+				},
+			}),
+			Exp: time.Now().Add(time.Hour),
+		})
+		entitlements, err := license.Entitlements(context.Background(), db, slog.Logger{}, 1, 1, coderdenttest.Keys, empty)
+		require.NoError(t, err)
+		require.True(t, entitlements.HasLicense)
+		require.Empty(t, entitlements.Warnings)
+		require.Equal(t, int64(1), *entitlements.Features[codersdk.FeatureUserLimit].Actual)
+		require.True(t, entitlements.Features[codersdk.FeatureServiceAccounts].Enabled)
+	})
 	t.Run("MaximizeUserLimit", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
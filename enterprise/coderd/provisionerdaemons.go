@@ -81,9 +81,10 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	now := database.Now()
 	apiDaemons := make([]codersdk.ProvisionerDaemon, 0)
 	for _, daemon := range daemons {
-		apiDaemons = append(apiDaemons, convertProvisionerDaemon(daemon))
+		apiDaemons = append(apiDaemons, coderd.ConvertProvisionerDaemon(daemon, now))
 	}
 	httpapi.Write(ctx, rw, http.StatusOK, apiDaemons)
 }
@@ -228,6 +229,8 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		Telemetry:             api.Telemetry,
 		Auditor:               &api.AGPL.Auditor,
 		TemplateScheduleStore: &api.AGPL.TemplateScheduleStore,
+		ImportPolicy:          api.AGPL.TemplateImportPolicy,
+		BuildStageMetrics:     api.AGPL.BuildStageMetrics,
 		Logger:                api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
 		Tags:                  rawTags,
 	})
@@ -252,20 +255,6 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 	_ = conn.Close(websocket.StatusGoingAway, "")
 }
 
-func convertProvisionerDaemon(daemon database.ProvisionerDaemon) codersdk.ProvisionerDaemon {
-	result := codersdk.ProvisionerDaemon{
-		ID:        daemon.ID,
-		CreatedAt: daemon.CreatedAt,
-		UpdatedAt: daemon.UpdatedAt,
-		Name:      daemon.Name,
-		Tags:      daemon.Tags,
-	}
-	for _, provisionerType := range daemon.Provisioners {
-		result.Provisioners = append(result.Provisioners, codersdk.ProvisionerType(provisionerType))
-	}
-	return result
-}
-
 // wsNetConn wraps net.Conn created by websocket.NetConn(). Cancel func
 // is called if a read or write error is encountered.
 type wsNetConn struct {
@@ -1,16 +1,21 @@
 package coderd
 
 import (
+	"context"
 	"crypto/subtle"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/imulab/go-scim/pkg/v2/handlerutil"
-	scimjson "github.com/imulab/go-scim/pkg/v2/json"
-	"github.com/imulab/go-scim/pkg/v2/service"
 	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
 
 	agpl "github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/database"
@@ -40,58 +45,150 @@ func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
 	return len(api.SCIMAPIKey) != 0 && subtle.ConstantTimeCompare(hdr, api.SCIMAPIKey) == 1
 }
 
-// scimGetUsers intentionally always returns no users. This is done to always force
-// Okta to try and create each user individually, this way we don't need to
-// implement fetching users twice.
+// scimErrUnauthorized is returned for requests without the SCIM API key.
+var scimErrUnauthorized = xerrors.Errorf("invalid authorization: %w", &spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+
+// scimWrite writes the resource with the attributes requested by the
+// attributes or excludedAttributes parameters.
+func scimWrite(rw http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	projection, err := handlerutil.GetRequestProjection(r)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	resource, err := scimResource(v)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	scimProject(resource, projection)
+
+	httpapi.Write(r.Context(), rw, status, resource)
+}
+
+// scimWriteList writes the page of resources matching the filter of the
+// request.
+func scimWriteList[T any](rw http.ResponseWriter, r *http.Request, resources []T) {
+	query, err := handlerutil.QueryRequestFromGet(r)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	filter, err := compileSCIMFilter(query.Filter)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	matched := make([]map[string]interface{}, 0, len(resources))
+	for _, v := range resources {
+		resource, err := scimResource(v)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		ok, err := filter.match(resource)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		if ok {
+			matched = append(matched, resource)
+		}
+	}
+
+	res := SCIMListResponse{
+		Schemas:      []string{"urn:ietf:params:scim:api:messages:2.0:ListResponse"},
+		TotalResults: len(matched),
+		StartIndex:   1,
+		Resources:    []interface{}{},
+	}
+	page := matched
+	if query.Pagination != nil {
+		res.StartIndex = query.Pagination.StartIndex
+		if res.StartIndex > len(page) {
+			page = nil
+		} else {
+			page = page[res.StartIndex-1:]
+		}
+		// The count defaults to 0 if only the start index is set.
+		if r.URL.Query().Has("count") && query.Pagination.Count < len(page) {
+			page = page[:query.Pagination.Count]
+		}
+	}
+	for _, resource := range page {
+		scimProject(resource, query.Projection)
+		res.Resources = append(res.Resources, resource)
+	}
+	res.ItemsPerPage = len(res.Resources)
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, res)
+}
+
+// scimGetUsers returns the users matching the SCIM filter of the request.
+// Service accounts are not managed by the identity provider, and are never
+// returned.
 //
 // @Summary SCIM 2.0: Get users
 // @ID scim-get-users
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
-// @Success 200
+// @Param filter query string false "SCIM filter"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Success 200 {object} coderd.SCIMListResponse
 // @Router /scim/v2/Users [get]
 //
 //nolint:revive
 func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
 		return
 	}
 
-	_ = handlerutil.WriteSearchResultToResponse(rw, &service.QueryResponse{
-		TotalResults: 0,
-		StartIndex:   1,
-		ItemsPerPage: 0,
-		Resources:    []scimjson.Serializable{},
-	})
+	//nolint:gocritic // needed for SCIM
+	rows, err := api.Database.GetUsers(dbauthz.AsSystemRestricted(ctx), database.GetUsersParams{})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	sUsers := make([]SCIMUser, 0, len(rows))
+	for _, user := range database.ConvertUserRows(rows) {
+		if user.LoginType == database.LoginTypeNone {
+			continue
+		}
+		sUsers = append(sUsers, convertSCIMUser(user))
+	}
+
+	scimWriteList(rw, r, sUsers)
 }
 
-// scimGetUser intentionally always returns an error saying the user wasn't found.
-// This is done to always force Okta to try and create the user, this way we
-// don't need to implement fetching users twice.
-//
-// scimGetUsers intentionally always returns no users. This is done to always force
-// Okta to try and create each user individually, this way we don't need to
-// implement fetching users twice.
-//
 // @Summary SCIM 2.0: Get user by ID
 // @ID scim-get-user-by-id
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
 // @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} coderd.SCIMUser
 // @Failure 404
 // @Router /scim/v2/Users/{id} [get]
 //
 //nolint:revive
 func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
 	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
 		return
 	}
 
-	_ = handlerutil.WriteError(rw, spec.ErrNotFound)
+	user, ok := api.scimUser(rw, r)
+	if !ok {
+		return
+	}
+
+	scimWrite(rw, r, http.StatusOK, convertSCIMUser(user))
 }
 
 // We currently use our own struct instead of using the SCIM package. This was
@@ -119,7 +216,33 @@ type SCIMUser struct {
 	} `json:"meta"`
 }
 
-// scimPostUser creates a new user, or returns the existing user if it exists.
+// SCIMListResponse is a page of the resources matching a filter.
+//
+// See https://www.rfc-editor.org/rfc/rfc7644#section-3.4.2
+type SCIMListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// SCIMPatchRequest modifies the attributes of a resource. Operations without
+// a path set the attributes of their value.
+//
+// See https://www.rfc-editor.org/rfc/rfc7644#section-3.5.2
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string      `json:"op" enums:"add,remove,replace"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// scimPostUser creates a new user in the first organization.
 //
 // @Summary SCIM 2.0: Create new user
 // @ID scim-create-new-user
@@ -127,55 +250,114 @@ type SCIMUser struct {
 // @Produce json
 // @Tags Enterprise
 // @Param request body coderd.SCIMUser true "New user"
-// @Success 200 {object} coderd.SCIMUser
+// @Success 201 {object} coderd.SCIMUser
 // @Router /scim/v2/Users [post]
 func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
 		return
 	}
 
 	var sUser SCIMUser
 	err := json.NewDecoder(r.Body).Decode(&sUser)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, err)
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode user: %w", spec.ErrInvalidSyntax))
 		return
 	}
 
-	email := ""
-	for _, e := range sUser.Emails {
-		if e.Primary {
-			email = e.Value
-			break
-		}
-	}
-
+	email := scimPrimaryEmail(sUser)
 	if email == "" {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusBadRequest, Type: "invalidEmail"})
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("a primary email is required: %w", spec.ErrInvalidValue))
 		return
 	}
 
 	//nolint:gocritic // needed for SCIM
-	user, _, err := api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	user, _, err := api.AGPL.CreateUser(ctx, api.Database, agpl.CreateUserRequest{
 		CreateUserRequest: codersdk.CreateUserRequest{
-			Username: sUser.UserName,
-			Email:    email,
+			Username:       sUser.UserName,
+			Email:          email,
+			OrganizationID: organizationID,
 		},
 		LoginType: database.LoginTypeOIDC,
 	})
+	if database.IsUniqueViolation(err) {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("user %q already exists: %w", sUser.UserName, spec.ErrUniqueness))
+		return
+	}
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
 
-	sUser.ID = user.ID.String()
-	sUser.UserName = user.Username
+	if !sUser.Active {
+		user, err = api.Database.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, sUser)
+	scimWrite(rw, r, http.StatusCreated, convertSCIMUser(user))
 }
 
-// scimPatchUser supports suspending and activating users only.
+// scimPutUser replaces the email and status of a user. Other attributes are
+// not stored by Coder.
+//
+// @Summary SCIM 2.0: Replace user account
+// @ID scim-replace-user
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "User ID" format(uuid)
+// @Param request body coderd.SCIMUser true "Replace user request"
+// @Success 200 {object} coderd.SCIMUser
+// @Router /scim/v2/Users/{id} [put]
+func (api *API) scimPutUser(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
+		return
+	}
+
+	user, ok := api.scimUser(rw, r)
+	if !ok {
+		return
+	}
+
+	var sUser SCIMUser
+	err := json.NewDecoder(r.Body).Decode(&sUser)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode user: %w", spec.ErrInvalidSyntax))
+		return
+	}
+
+	email := scimPrimaryEmail(sUser)
+	if email == "" {
+		email = user.Email
+	}
+	user, err = api.scimUpdateUser(r.Context(), user, email, sUser.Active)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	scimWrite(rw, r, http.StatusOK, convertSCIMUser(user))
+}
+
+// scimPatchUser updates the email and status of a user. For compatibility,
+// a user resource may be sent instead of patch operations, in which case only
+// its status is updated.
 //
 // @Summary SCIM 2.0: Update user account
 // @ID scim-update-user-status
@@ -183,56 +365,218 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 // @Produce application/scim+json
 // @Tags Enterprise
 // @Param id path string true "User ID" format(uuid)
-// @Param request body coderd.SCIMUser true "Update user request"
-// @Success 200 {object} codersdk.User
+// @Param request body coderd.SCIMPatchRequest true "Update user request"
+// @Success 200 {object} coderd.SCIMUser
 // @Router /scim/v2/Users/{id} [patch]
 func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
 	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
 		return
 	}
 
-	id := chi.URLParam(r, "id")
+	user, ok := api.scimUser(rw, r)
+	if !ok {
+		return
+	}
 
-	var sUser SCIMUser
-	err := json.NewDecoder(r.Body).Decode(&sUser)
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
-	sUser.ID = id
-
-	uid, err := uuid.Parse(id)
+	var req SCIMPatchRequest
+	err = json.Unmarshal(body, &req)
 	if err != nil {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusBadRequest, Type: "invalidId"})
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode patch request: %w", spec.ErrInvalidSyntax))
 		return
 	}
 
-	//nolint:gocritic // needed for SCIM
-	dbUser, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), uid)
+	email, active := user.Email, user.Status == database.UserStatusActive
+	if !slices.Contains(req.Schemas, scimPatchOpSchema) {
+		var sUser SCIMUser
+		err = json.Unmarshal(body, &sUser)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("decode user: %w", spec.ErrInvalidSyntax))
+			return
+		}
+		active = sUser.Active
+	}
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		case "remove":
+			// The attributes Coder stores are required.
+			continue
+		default:
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("unsupported operation %q: %w", op.Op, spec.ErrInvalidSyntax))
+			return
+		}
+		values, err := scimPatchValues(op)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		for path, value := range values {
+			_, name, err := scimPath(path)
+			if err != nil {
+				_ = handlerutil.WriteError(rw, err)
+				return
+			}
+			// Other attributes, e.g. name and externalId, are not stored by
+			// Coder and are ignored.
+			switch name {
+			case "active":
+				active, err = scimBool(value)
+				if err != nil {
+					_ = handlerutil.WriteError(rw, err)
+					return
+				}
+			case "emails":
+				if value := scimPatchEmail(value); value != "" {
+					email = value
+				}
+			}
+		}
+	}
+
+	user, err = api.scimUpdateUser(r.Context(), user, email, active)
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
 
-	var status database.UserStatus
-	if sUser.Active {
-		status = database.UserStatusActive
-	} else {
-		status = database.UserStatusSuspended
+	scimWrite(rw, r, http.StatusOK, convertSCIMUser(user))
+}
+
+// scimUser returns the user of the id parameter, or writes an error if the
+// user does not exist.
+func (api *API) scimUser(rw http.ResponseWriter, r *http.Request) (database.User, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("user %q: %w", chi.URLParam(r, "id"), spec.ErrNotFound))
+		return database.User{}, false
 	}
 
 	//nolint:gocritic // needed for SCIM
-	_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
-		ID:        dbUser.ID,
-		Status:    status,
-		UpdatedAt: database.Now(),
-	})
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(r.Context()), id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (user.Deleted || user.LoginType == database.LoginTypeNone)) {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("user %q: %w", id, spec.ErrNotFound))
+		return database.User{}, false
+	}
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
-		return
+		return database.User{}, false
 	}
+	return user, true
+}
 
-	httpapi.Write(ctx, rw, http.StatusOK, sUser)
+// scimOrganizationID returns the organization of SCIM users and groups. Like
+// users that sign in with OIDC, they belong to the first organization.
+func (api *API) scimOrganizationID(ctx context.Context) (uuid.UUID, error) {
+	organizations, err := api.Database.GetOrganizations(ctx)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get organizations: %w", err)
+	}
+	if len(organizations) == 0 {
+		return uuid.Nil, xerrors.New("no organization exists")
+	}
+	return organizations[0].ID, nil
+}
+
+func (api *API) scimUpdateUser(ctx context.Context, user database.User, email string, active bool) (database.User, error) {
+	//nolint:gocritic // needed for SCIM
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var err error
+	if email != user.Email {
+		user, err = api.Database.UpdateUserProfile(ctx, database.UpdateUserProfileParams{
+			ID:        user.ID,
+			Email:     email,
+			Username:  user.Username,
+			AvatarURL: user.AvatarURL,
+			UpdatedAt: database.Now(),
+		})
+		if database.IsUniqueViolation(err) {
+			return database.User{}, xerrors.Errorf("email %q is taken: %w", email, spec.ErrUniqueness)
+		}
+		if err != nil {
+			return database.User{}, xerrors.Errorf("update email: %w", err)
+		}
+	}
+
+	status := database.UserStatusSuspended
+	if active {
+		status = database.UserStatusActive
+	}
+	if status != user.Status {
+		user, err = api.Database.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    status,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			return database.User{}, xerrors.Errorf("update status: %w", err)
+		}
+	}
+	return user, nil
+}
+
+func convertSCIMUser(user database.User) SCIMUser {
+	sUser := SCIMUser{
+		Schemas:  []string{scimUserSchema},
+		ID:       user.ID.String(),
+		UserName: user.Username,
+		Active:   user.Status == database.UserStatusActive,
+		Groups:   []interface{}{},
+	}
+	sUser.Emails = append(sUser.Emails, struct {
+		Primary bool   `json:"primary"`
+		Value   string `json:"value" format:"email"`
+		Type    string `json:"type"`
+		Display string `json:"display"`
+	}{
+		Primary: true,
+		Value:   user.Email,
+		Type:    "work",
+	})
+	sUser.Meta.ResourceType = "User"
+	return sUser
+}
+
+// scimPrimaryEmail returns the primary email of the user, or its only email.
+func scimPrimaryEmail(sUser SCIMUser) string {
+	for _, e := range sUser.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(sUser.Emails) == 1 {
+		return sUser.Emails[0].Value
+	}
+	return ""
+}
+
+// scimPatchEmail returns the email set by a patch operation. The value is
+// either an email, e.g. for the emails[type eq "work"].value path, or email
+// objects.
+func scimPatchEmail(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		email, _ := scimAttribute(value, "value").(string)
+		return email
+	case []interface{}:
+		var email string
+		for _, v := range value {
+			object, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if primary, _ := scimAttribute(object, "primary").(bool); primary || email == "" {
+				email, _ = scimAttribute(object, "value").(string)
+			}
+		}
+		return email
+	}
+	return ""
 }
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -96,7 +99,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", struct{}{})
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 		})
 
 		t.Run("OK", func(t *testing.T) {
@@ -119,7 +122,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
 
 			userRes, err := client.Users(ctx, codersdk.UsersRequest{Search: sUser.Emails[0].Value})
 			require.NoError(t, err)
@@ -172,7 +175,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "PATCH", "/scim/v2/Users/bob", struct{}{})
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 		})
 
 		t.Run("OK", func(t *testing.T) {
@@ -195,7 +198,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
 
 			err = json.NewDecoder(res.Body).Decode(&sUser)
 			require.NoError(t, err)
@@ -212,5 +215,290 @@ func TestScim(t *testing.T) {
 			require.Len(t, userRes.Users, 1)
 			assert.Equal(t, codersdk.UserStatusSuspended, userRes.Users[0].Status)
 		})
+
+		t.Run("PatchOp", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			scimAPIKey := []byte("hi")
+			client := coderdenttest.New(t, &coderdenttest.Options{SCIMAPIKey: scimAPIKey})
+			_ = coderdtest.CreateFirstUser(t, client)
+			coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+				AccountID: "coolin",
+				Features: license.Features{
+					codersdk.FeatureSCIM: 1,
+				},
+			})
+
+			sUser := makeScimUser(t)
+			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusCreated, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sUser)
+			require.NoError(t, err)
+
+			// Azure AD sends booleans as strings.
+			res, err = client.Request(ctx, "PATCH", "/scim/v2/Users/"+sUser.ID, coderd.SCIMPatchRequest{
+				Schemas: []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "Replace", Path: "active", Value: "False"},
+					{Op: "Replace", Path: `emails[type eq "work"].value`, Value: "new-" + sUser.Emails[0].Value},
+				},
+			}, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			user, err := client.User(ctx, sUser.ID)
+			require.NoError(t, err)
+			assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+			assert.Equal(t, "new-"+sUser.Emails[0].Value, user.Email)
+
+			// Okta sends operations without a path.
+			res, err = client.Request(ctx, "PATCH", "/scim/v2/Users/"+sUser.ID, coderd.SCIMPatchRequest{
+				Schemas: []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "replace", Value: map[string]interface{}{"active": true}},
+				},
+			}, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			user, err = client.User(ctx, sUser.ID)
+			require.NoError(t, err)
+			assert.Equal(t, codersdk.UserStatusActive, user.Status)
+		})
+	})
+
+	t.Run("getUsers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		scimAPIKey := []byte("hi")
+		client := coderdenttest.New(t, &coderdenttest.Options{SCIMAPIKey: scimAPIKey})
+		_ = coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			AccountID: "coolin",
+			Features: license.Features{
+				codersdk.FeatureSCIM: 1,
+			},
+		})
+
+		sUser := makeScimUser(t)
+		res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		err = json.NewDecoder(res.Body).Decode(&sUser)
+		require.NoError(t, err)
+
+		// Creating the same user again is a conflict.
+		res, err = client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusConflict, res.StatusCode)
+
+		getUsers := func(t *testing.T, query url.Values) coderd.SCIMListResponse {
+			t.Helper()
+			res, err := client.Request(ctx, "GET", "/scim/v2/Users?"+query.Encode(), nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			var list coderd.SCIMListResponse
+			err = json.NewDecoder(res.Body).Decode(&list)
+			require.NoError(t, err)
+			return list
+		}
+
+		list := getUsers(t, url.Values{})
+		require.Equal(t, 2, list.TotalResults)
+
+		for _, filter := range []string{
+			fmt.Sprintf("userName eq %q", sUser.UserName),
+			fmt.Sprintf("USERNAME eq %q and active eq true", strings.ToUpper(sUser.UserName)),
+			fmt.Sprintf("urn:ietf:params:scim:schemas:core:2.0:User:userName sw %q", sUser.UserName[:3]),
+			fmt.Sprintf(`emails[type eq "work" and value co %q]`, sUser.UserName),
+			fmt.Sprintf(`emails[primary eq true].value eq %q`, sUser.Emails[0].Value),
+			fmt.Sprintf(`not (userName ne %q)`, sUser.UserName),
+		} {
+			list = getUsers(t, url.Values{"filter": {filter}})
+			require.Equal(t, 1, list.TotalResults, filter)
+			require.Len(t, list.Resources, 1, filter)
+			resource, ok := list.Resources[0].(map[string]interface{})
+			require.True(t, ok)
+			require.Equal(t, sUser.ID, resource["id"], filter)
+		}
+
+		list = getUsers(t, url.Values{"filter": {`userName eq "nobody"`}})
+		require.Equal(t, 0, list.TotalResults)
+		require.Empty(t, list.Resources)
+
+		list = getUsers(t, url.Values{"startIndex": {"2"}, "count": {"1"}})
+		require.Equal(t, 2, list.TotalResults)
+		require.Equal(t, 2, list.StartIndex)
+		require.Equal(t, 1, list.ItemsPerPage)
+		require.Len(t, list.Resources, 1)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Users?"+url.Values{"filter": {"userName eq"}}.Encode(), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Users/"+sUser.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var got coderd.SCIMUser
+		err = json.NewDecoder(res.Body).Decode(&got)
+		require.NoError(t, err)
+		require.Equal(t, sUser.UserName, got.UserName)
+		require.True(t, got.Active)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Users/"+uuid.NewString(), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		scimAPIKey := []byte("hi")
+		client := coderdenttest.New(t, &coderdenttest.Options{SCIMAPIKey: scimAPIKey})
+		_ = coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			AccountID: "coolin",
+			Features: license.Features{
+				codersdk.FeatureSCIM:         1,
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		})
+
+		userIDs := make([]string, 0, 2)
+		for i := 0; i < 2; i++ {
+			sUser := makeScimUser(t)
+			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusCreated, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sUser)
+			require.NoError(t, err)
+			userIDs = append(userIDs, sUser.ID)
+		}
+
+		scimGroup := func(t *testing.T, res *http.Response, status int) coderd.SCIMGroup {
+			t.Helper()
+			defer res.Body.Close()
+			require.Equal(t, status, res.StatusCode)
+			var sGroup coderd.SCIMGroup
+			err := json.NewDecoder(res.Body).Decode(&sGroup)
+			require.NoError(t, err)
+			return sGroup
+		}
+		memberIDs := func(sGroup coderd.SCIMGroup) []string {
+			ids := make([]string, 0, len(sGroup.Members))
+			for _, member := range sGroup.Members {
+				ids = append(ids, member.Value)
+			}
+			return ids
+		}
+
+		res, err := client.Request(ctx, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "developers",
+			Members:     []coderd.SCIMGroupMember{{Value: userIDs[0]}},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup := scimGroup(t, res, http.StatusCreated)
+		require.Equal(t, "developers", sGroup.DisplayName)
+		require.ElementsMatch(t, userIDs[:1], memberIDs(sGroup))
+
+		// The Everyone group is not a SCIM group.
+		res, err = client.Request(ctx, "GET", "/scim/v2/Groups", nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var list coderd.SCIMListResponse
+		err = json.NewDecoder(res.Body).Decode(&list)
+		require.NoError(t, err)
+		require.Equal(t, 1, list.TotalResults)
+
+		query := url.Values{
+			"filter":             {fmt.Sprintf(`displayName eq "Developers" and members eq %q`, userIDs[0])},
+			"excludedAttributes": {"members"},
+		}
+		res, err = client.Request(ctx, "GET", "/scim/v2/Groups?"+query.Encode(), nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		err = json.NewDecoder(res.Body).Decode(&list)
+		require.NoError(t, err)
+		require.Equal(t, 1, list.TotalResults)
+		resource, ok := list.Resources[0].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, sGroup.ID, resource["id"])
+		require.NotContains(t, resource, "members")
+
+		res, err = client.Request(ctx, "PATCH", "/scim/v2/Groups/"+sGroup.ID, coderd.SCIMPatchRequest{
+			Schemas: []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+			Operations: []coderd.SCIMPatchOperation{
+				{Op: "add", Path: "members", Value: []map[string]string{{"value": userIDs[1]}}},
+				{Op: "remove", Path: fmt.Sprintf(`members[value eq %q]`, userIDs[0])},
+				{Op: "replace", Value: map[string]interface{}{"id": sGroup.ID, "displayName": "engineers"}},
+			},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup = scimGroup(t, res, http.StatusOK)
+		require.Equal(t, "engineers", sGroup.DisplayName)
+		require.ElementsMatch(t, userIDs[1:], memberIDs(sGroup))
+
+		// Azure AD removes members by value.
+		res, err = client.Request(ctx, "PATCH", "/scim/v2/Groups/"+sGroup.ID, coderd.SCIMPatchRequest{
+			Schemas: []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+			Operations: []coderd.SCIMPatchOperation{
+				{Op: "Remove", Path: "members", Value: []map[string]string{{"value": userIDs[1]}}},
+			},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup = scimGroup(t, res, http.StatusOK)
+		require.Empty(t, sGroup.Members)
+
+		group, err := client.Group(ctx, uuid.MustParse(sGroup.ID))
+		require.NoError(t, err)
+		require.Equal(t, "engineers", group.Name)
+		require.Empty(t, group.Members)
+
+		res, err = client.Request(ctx, "PUT", "/scim/v2/Groups/"+sGroup.ID, coderd.SCIMGroup{
+			DisplayName: "engineers",
+			Members:     []coderd.SCIMGroupMember{{Value: userIDs[0]}, {Value: userIDs[1]}},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		sGroup = scimGroup(t, res, http.StatusOK)
+		require.ElementsMatch(t, userIDs, memberIDs(sGroup))
+
+		res, err = client.Request(ctx, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "engineers",
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusConflict, res.StatusCode)
+
+		res, err = client.Request(ctx, "DELETE", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		res, err = client.Request(ctx, "GET", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
 	})
 }
@@ -0,0 +1,411 @@
+package coderd
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/imulab/go-scim/pkg/v2/crud"
+	"github.com/imulab/go-scim/pkg/v2/crud/expr"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/xerrors"
+)
+
+const (
+	scimUserSchema    = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimGroupSchema   = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimPatchOpSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+)
+
+func init() {
+	// Paths may be prefixed with the schema, e.g.
+	// urn:ietf:params:scim:schemas:core:2.0:User:userName. The dots in the
+	// schema are not path separators, so the compiler must know about them.
+	expr.RegisterURN(scimUserSchema)
+	expr.RegisterURN(scimGroupSchema)
+}
+
+// scimResource converts a SCIM resource to its JSON representation, which
+// filters and projections are evaluated against.
+func scimResource(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal resource: %w", err)
+	}
+	resource := map[string]interface{}{}
+	err = json.Unmarshal(data, &resource)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal resource: %w", err)
+	}
+	return resource, nil
+}
+
+// scimFilter is a compiled SCIM filter.
+type scimFilter struct {
+	root *expr.Expression
+	// valuePaths are the attribute paths with a value filter, e.g.
+	// emails[type eq "work"], by the placeholder attribute that replaces them
+	// in root. The filter compiler does not support them.
+	valuePaths map[string]*expr.Expression
+}
+
+// compileSCIMFilter compiles the filter. An empty filter matches all
+// resources.
+//
+// See https://www.rfc-editor.org/rfc/rfc7644#section-3.4.2.2
+func compileSCIMFilter(filter string) (*scimFilter, error) {
+	f := &scimFilter{
+		valuePaths: map[string]*expr.Expression{},
+	}
+	if strings.TrimSpace(filter) == "" {
+		return f, nil
+	}
+
+	var (
+		rewritten strings.Builder
+		quoted    bool
+		start     int
+	)
+	for i := 0; i < len(filter); i++ {
+		c := filter[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+			continue
+		case c == '"':
+			quoted = !quoted
+			continue
+		case quoted || c != '[':
+			continue
+		}
+
+		// Find the start of the attribute path and the end of the value
+		// filter, including any sub-attribute.
+		pathStart := i
+		for pathStart > start && isPathByte(filter[pathStart-1]) {
+			pathStart--
+		}
+		end := i + 1
+		for ; end < len(filter) && (quoted || filter[end] != ']'); end++ {
+			switch {
+			case quoted && filter[end] == '\\':
+				end++
+			case filter[end] == '"':
+				quoted = !quoted
+			}
+		}
+		if end >= len(filter) {
+			return nil, xerrors.Errorf("unterminated value filter: %w", spec.ErrInvalidFilter)
+		}
+		end++
+		subAttribute := end < len(filter) && filter[end] == '.'
+		for end < len(filter) && isPathByte(filter[end]) {
+			end++
+		}
+
+		path, err := expr.CompilePath(filter[pathStart:end])
+		if err != nil {
+			return nil, xerrors.Errorf("compile value path %q: %w", filter[pathStart:end], spec.ErrInvalidFilter)
+		}
+		placeholder := "scimValuePath" + strconv.Itoa(len(f.valuePaths))
+		f.valuePaths[placeholder] = path
+		rewritten.WriteString(filter[start:pathStart])
+		rewritten.WriteString(placeholder)
+		if !subAttribute {
+			// A value path without a sub-attribute matches if any value
+			// matches its filter.
+			rewritten.WriteString(" pr")
+		}
+		start = end
+		i = end - 1
+	}
+	rewritten.WriteString(filter[start:])
+
+	root, err := expr.CompileFilter(rewritten.String())
+	if err != nil {
+		return nil, xerrors.Errorf("compile filter %q: %w", filter, spec.ErrInvalidFilter)
+	}
+	f.root = root
+	return f, nil
+}
+
+// isPathByte returns whether the byte can be part of an attribute path.
+func isPathByte(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || strings.IndexByte(".:_-$", c) != -1
+}
+
+// match returns whether the resource matches the filter. Attribute names and
+// string comparisons are case-insensitive, and a multi-valued attribute
+// matches if any of its values match.
+func (f *scimFilter) match(resource map[string]interface{}) (bool, error) {
+	return f.matchExpression(resource, f.root)
+}
+
+func (f *scimFilter) matchExpression(resource map[string]interface{}, filter *expr.Expression) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+	if filter.IsLogicalOperator() {
+		left, err := f.matchExpression(resource, filter.Left())
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(filter.Token()) {
+		case expr.Not:
+			return !left, nil
+		case expr.And:
+			if !left {
+				return false, nil
+			}
+		case expr.Or:
+			if left {
+				return true, nil
+			}
+		}
+		return f.matchExpression(resource, filter.Right())
+	}
+	if !filter.IsRelationalOperator() || filter.Left() == nil {
+		return false, xerrors.Errorf("%q is not an operator: %w", filter.Token(), spec.ErrInvalidFilter)
+	}
+
+	path := filter.Left()
+	if valuePath, ok := f.valuePaths[path.Token()]; ok {
+		path = valuePath
+	}
+	values, err := f.values(resource, path)
+	if err != nil {
+		return false, err
+	}
+	op := strings.ToLower(filter.Token())
+	if op == expr.Pr {
+		for _, value := range values {
+			if value != nil && value != "" {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	if filter.Right() == nil {
+		return false, xerrors.Errorf("operator %q requires a value: %w", op, spec.ErrInvalidFilter)
+	}
+	var literal interface{}
+	err = json.Unmarshal([]byte(filter.Right().Token()), &literal)
+	if err != nil {
+		return false, xerrors.Errorf("invalid value %s: %w", filter.Right().Token(), spec.ErrInvalidFilter)
+	}
+
+	if op == expr.Ne {
+		matched, err := scimCompareAny(values, expr.Eq, literal)
+		return !matched, err
+	}
+	return scimCompareAny(values, op, literal)
+}
+
+// values returns the values at the path of the resource. Values of
+// multi-valued attributes are flattened, and value filters within the path
+// select matching values.
+func (f *scimFilter) values(resource map[string]interface{}, path *expr.Expression) ([]interface{}, error) {
+	values := []interface{}{resource}
+	for step := path; step != nil; step = step.Next() {
+		if step.IsRootOfFilter() {
+			var matched []interface{}
+			for _, value := range values {
+				object, ok := value.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				ok, err := f.matchExpression(object, step)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					matched = append(matched, value)
+				}
+			}
+			values = matched
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(step.Token()), "urn:") {
+			continue
+		}
+
+		var next []interface{}
+		for _, value := range values {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch value := scimAttribute(object, step.Token()).(type) {
+			case nil:
+			case []interface{}:
+				next = append(next, value...)
+			default:
+				next = append(next, value)
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// scimAttribute returns the attribute of the object by its case-insensitive
+// name.
+func scimAttribute(object map[string]interface{}, name string) interface{} {
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return nil
+}
+
+func scimCompareAny(values []interface{}, op string, literal interface{}) (bool, error) {
+	for _, value := range values {
+		matched, err := scimCompare(value, op, literal)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func scimCompare(value interface{}, op string, literal interface{}) (bool, error) {
+	// Complex attributes are compared by their value sub-attribute, e.g. in
+	// members eq "<user id>".
+	if object, ok := value.(map[string]interface{}); ok {
+		value = scimAttribute(object, "value")
+	}
+	switch literal := literal.(type) {
+	case string:
+		value, ok := value.(string)
+		if !ok {
+			return false, nil
+		}
+		value, literal = strings.ToLower(value), strings.ToLower(literal)
+		switch op {
+		case expr.Eq:
+			return value == literal, nil
+		case expr.Co:
+			return strings.Contains(value, literal), nil
+		case expr.Sw:
+			return strings.HasPrefix(value, literal), nil
+		case expr.Ew:
+			return strings.HasSuffix(value, literal), nil
+		case expr.Gt:
+			return value > literal, nil
+		case expr.Ge:
+			return value >= literal, nil
+		case expr.Lt:
+			return value < literal, nil
+		case expr.Le:
+			return value <= literal, nil
+		}
+	case float64:
+		value, ok := value.(float64)
+		if !ok {
+			return false, nil
+		}
+		switch op {
+		case expr.Eq:
+			return value == literal, nil
+		case expr.Gt:
+			return value > literal, nil
+		case expr.Ge:
+			return value >= literal, nil
+		case expr.Lt:
+			return value < literal, nil
+		case expr.Le:
+			return value <= literal, nil
+		}
+	case bool:
+		if op == expr.Eq {
+			return value == literal, nil
+		}
+	case nil:
+		if op == expr.Eq {
+			return value == nil, nil
+		}
+	}
+	return false, xerrors.Errorf("operator %q cannot compare %v: %w", op, literal, spec.ErrInvalidFilter)
+}
+
+// scimPath compiles the attribute path of a patch operation, and returns it
+// with the lowercase name of its attribute. The schema prefix is skipped.
+func scimPath(path string) (*expr.Expression, string, error) {
+	head, err := expr.CompilePath(path)
+	if err != nil {
+		return nil, "", xerrors.Errorf("compile path %q: %w", path, spec.ErrInvalidPath)
+	}
+	for head != nil && strings.HasPrefix(strings.ToLower(head.Token()), "urn:") {
+		head = head.Next()
+	}
+	if head == nil || !head.IsPath() {
+		return nil, "", xerrors.Errorf("path %q has no attribute: %w", path, spec.ErrInvalidPath)
+	}
+	return head, strings.ToLower(head.Token()), nil
+}
+
+// scimPatchValues returns the values a patch operation sets by attribute path.
+func scimPatchValues(op SCIMPatchOperation) (map[string]interface{}, error) {
+	if op.Path != "" {
+		return map[string]interface{}{op.Path: op.Value}, nil
+	}
+	values, ok := op.Value.(map[string]interface{})
+	if !ok {
+		return nil, xerrors.Errorf("an operation without a path requires an object value: %w", spec.ErrInvalidValue)
+	}
+	return values, nil
+}
+
+// scimBool parses a boolean value. Some identity providers, e.g. Azure AD,
+// send booleans as strings.
+func scimBool(value interface{}) (bool, error) {
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		b, err := strconv.ParseBool(value)
+		if err == nil {
+			return b, nil
+		}
+	}
+	return false, xerrors.Errorf("%v is not a boolean: %w", value, spec.ErrInvalidValue)
+}
+
+// scimProject removes the attributes of the resource that were not requested.
+// The id, schemas and meta attributes are always returned.
+//
+// See https://www.rfc-editor.org/rfc/rfc7644#section-3.4.2.5
+func scimProject(resource map[string]interface{}, projection *crud.Projection) {
+	if projection == nil {
+		return
+	}
+	names := func(paths []string) map[string]bool {
+		m := map[string]bool{}
+		for _, path := range paths {
+			path = strings.TrimSpace(path)
+			// Strip the schema prefix, e.g. in
+			// urn:ietf:params:scim:schemas:core:2.0:Group:members.
+			if i := strings.LastIndex(path, ":"); i != -1 {
+				path = path[i+1:]
+			}
+			// Sub-attributes select their parent attribute.
+			path, _, _ = strings.Cut(path, ".")
+			m[strings.ToLower(path)] = true
+		}
+		return m
+	}
+	attributes, excluded := names(projection.Attributes), names(projection.ExcludedAttributes)
+	for key := range resource {
+		switch key {
+		case "id", "schemas", "meta":
+			continue
+		}
+		if excluded[strings.ToLower(key)] || (len(attributes) > 0 && !attributes[strings.ToLower(key)]) {
+			delete(resource, key)
+		}
+	}
+}
@@ -0,0 +1,564 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/handlerutil"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
+)
+
+// SCIMGroup is a group of the first organization. The Everyone group is
+// managed by Coder, and is not a SCIM group.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	// Value is the ID of the user.
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "SCIM filter"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Param excludedAttributes query string false "Attributes to exclude, e.g. members"
+// @Success 200 {object} coderd.SCIMListResponse
+// @Router /scim/v2/Groups [get]
+//
+//nolint:revive
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
+		return
+	}
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	groups, err := api.Database.GetGroupsByOrganizationID(ctx, organizationID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	sGroups := make([]SCIMGroup, 0, len(groups))
+	for _, group := range groups {
+		if group.ID == group.OrganizationID {
+			continue
+		}
+		members, err := api.Database.GetGroupMembers(ctx, group.ID)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		sGroups = append(sGroups, convertSCIMGroup(group, members))
+	}
+
+	scimWriteList(rw, r, sGroups)
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param excludedAttributes query string false "Attributes to exclude, e.g. members"
+// @Success 200 {object} coderd.SCIMGroup
+// @Failure 404
+// @Router /scim/v2/Groups/{id} [get]
+//
+//nolint:revive
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
+		return
+	}
+
+	group, members, ok := api.scimGroup(rw, r)
+	if !ok {
+		return
+	}
+
+	scimWrite(rw, r, http.StatusOK, convertSCIMGroup(group, members))
+}
+
+// @Summary SCIM 2.0: Create new group
+// @ID scim-create-new-group
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+//
+//nolint:revive
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode group: %w", spec.ErrInvalidSyntax))
+		return
+	}
+	memberIDs, err := scimMemberIDs(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	err = scimValidateGroupName(sGroup.DisplayName)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var (
+		group   database.Group
+		members []database.User
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		var err error
+		group, err = tx.InsertGroup(ctx, database.InsertGroupParams{
+			ID:             uuid.New(),
+			Name:           sGroup.DisplayName,
+			OrganizationID: organizationID,
+		})
+		if database.IsUniqueViolation(err) {
+			return xerrors.Errorf("group %q already exists: %w", sGroup.DisplayName, spec.ErrUniqueness)
+		}
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		group, members, err = scimUpdateGroup(ctx, tx, group, group.Name, memberIDs)
+		return err
+	}, nil)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	scimWrite(rw, r, http.StatusCreated, convertSCIMGroup(group, members))
+}
+
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+//
+//nolint:revive
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
+		return
+	}
+
+	group, _, ok := api.scimGroup(rw, r)
+	if !ok {
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode group: %w", spec.ErrInvalidSyntax))
+		return
+	}
+	memberIDs, err := scimMemberIDs(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var members []database.User
+	err = api.Database.InTx(func(tx database.Store) error {
+		group, members, err = scimUpdateGroup(ctx, tx, group, sGroup.DisplayName, memberIDs)
+		return err
+	}, nil)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	scimWrite(rw, r, http.StatusOK, convertSCIMGroup(group, members))
+}
+
+// scimPatchGroup renames groups, and adds, removes or replaces their members.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchRequest true "Update group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+//
+//nolint:revive
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
+		return
+	}
+
+	group, members, ok := api.scimGroup(rw, r)
+	if !ok {
+		return
+	}
+
+	var req SCIMPatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode patch request: %w", spec.ErrInvalidSyntax))
+		return
+	}
+
+	name := group.Name
+	memberIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.ID)
+	}
+	for _, op := range req.Operations {
+		memberIDs, name, err = scimPatchGroupOperation(op, memberIDs, name)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		group, members, err = scimUpdateGroup(ctx, tx, group, name, memberIDs)
+		return err
+	}, nil)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	scimWrite(rw, r, http.StatusOK, convertSCIMGroup(group, members))
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+//
+//nolint:revive
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, scimErrUnauthorized)
+		return
+	}
+
+	group, _, ok := api.scimGroup(rw, r)
+	if !ok {
+		return
+	}
+
+	err := api.Database.DeleteGroupByID(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimGroup returns the group of the id parameter and its members, or writes
+// an error if the group does not exist.
+func (api *API) scimGroup(rw http.ResponseWriter, r *http.Request) (database.Group, []database.User, bool) {
+	//nolint:gocritic // needed for SCIM
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("group %q: %w", chi.URLParam(r, "id"), spec.ErrNotFound))
+		return database.Group{}, nil, false
+	}
+
+	group, err := api.Database.GetGroupByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && group.ID == group.OrganizationID) {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("group %q: %w", id, spec.ErrNotFound))
+		return database.Group{}, nil, false
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return database.Group{}, nil, false
+	}
+	members, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return database.Group{}, nil, false
+	}
+	return group, members, true
+}
+
+// scimPatchGroupOperation applies a patch operation to the members and name
+// of a group.
+func scimPatchGroupOperation(op SCIMPatchOperation, memberIDs []uuid.UUID, name string) ([]uuid.UUID, string, error) {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+	case "remove":
+		if op.Path == "" {
+			return nil, "", xerrors.Errorf("remove requires a path: %w", spec.ErrNoTarget)
+		}
+		head, attribute, err := scimPath(op.Path)
+		if err != nil {
+			return nil, "", err
+		}
+		if attribute != "members" {
+			return nil, "", xerrors.Errorf("cannot remove %q: %w", op.Path, spec.ErrMutability)
+		}
+
+		var remove func(id uuid.UUID) (bool, error)
+		switch filter := head.Next(); {
+		case filter != nil && filter.IsRootOfFilter():
+			// E.g. members[value eq "<user id>"].
+			remove = func(id uuid.UUID) (bool, error) {
+				return (&scimFilter{}).matchExpression(map[string]interface{}{
+					"value": id.String(),
+				}, filter)
+			}
+		case op.Value != nil:
+			// E.g. members with a value of [{"value": "<user id>"}].
+			removed, err := scimMemberValueIDs(op.Value)
+			if err != nil {
+				return nil, "", err
+			}
+			remove = func(id uuid.UUID) (bool, error) {
+				return slices.Contains(removed, id), nil
+			}
+		default:
+			remove = func(uuid.UUID) (bool, error) {
+				return true, nil
+			}
+		}
+		kept := make([]uuid.UUID, 0, len(memberIDs))
+		for _, id := range memberIDs {
+			removed, err := remove(id)
+			if err != nil {
+				return nil, "", err
+			}
+			if !removed {
+				kept = append(kept, id)
+			}
+		}
+		return kept, name, nil
+	default:
+		return nil, "", xerrors.Errorf("unsupported operation %q: %w", op.Op, spec.ErrInvalidSyntax)
+	}
+
+	values, err := scimPatchValues(op)
+	if err != nil {
+		return nil, "", err
+	}
+	for path, value := range values {
+		_, attribute, err := scimPath(path)
+		if err != nil {
+			return nil, "", err
+		}
+		// Other attributes, e.g. externalId, are not stored by Coder and
+		// are ignored.
+		switch attribute {
+		case "displayname":
+			displayName, ok := value.(string)
+			if !ok {
+				return nil, "", xerrors.Errorf("displayName must be a string: %w", spec.ErrInvalidValue)
+			}
+			name = displayName
+		case "members":
+			ids, err := scimMemberValueIDs(value)
+			if err != nil {
+				return nil, "", err
+			}
+			if strings.EqualFold(op.Op, "replace") {
+				memberIDs = nil
+			}
+			for _, id := range ids {
+				if !slices.Contains(memberIDs, id) {
+					memberIDs = append(memberIDs, id)
+				}
+			}
+		}
+	}
+	return memberIDs, name, nil
+}
+
+// scimUpdateGroup renames the group and replaces its members.
+func scimUpdateGroup(ctx context.Context, tx database.Store, group database.Group, name string, memberIDs []uuid.UUID) (database.Group, []database.User, error) {
+	if name != group.Name {
+		err := scimValidateGroupName(name)
+		if err != nil {
+			return database.Group{}, nil, err
+		}
+		group, err = tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+			ID:             group.ID,
+			Name:           name,
+			AvatarURL:      group.AvatarURL,
+			QuotaAllowance: group.QuotaAllowance,
+		})
+		if database.IsUniqueViolation(err) {
+			return database.Group{}, nil, xerrors.Errorf("group %q already exists: %w", name, spec.ErrUniqueness)
+		}
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("update group: %w", err)
+		}
+	}
+
+	members, err := tx.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		return database.Group{}, nil, xerrors.Errorf("get group members: %w", err)
+	}
+	currentIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		currentIDs = append(currentIDs, member.ID)
+		if slices.Contains(memberIDs, member.ID) {
+			continue
+		}
+		err = tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+			UserID:  member.ID,
+			GroupID: group.ID,
+		})
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("delete group member %q: %w", member.ID, err)
+		}
+	}
+	for _, id := range memberIDs {
+		if slices.Contains(currentIDs, id) {
+			continue
+		}
+		_, err = tx.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: group.OrganizationID,
+			UserID:         id,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Group{}, nil, xerrors.Errorf("user %q is not a member of the organization: %w", id, spec.ErrInvalidValue)
+		}
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("get organization member %q: %w", id, err)
+		}
+		err = tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+			UserID:  id,
+			GroupID: group.ID,
+		})
+		if err != nil {
+			return database.Group{}, nil, xerrors.Errorf("insert group member %q: %w", id, err)
+		}
+	}
+
+	members, err = tx.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		return database.Group{}, nil, xerrors.Errorf("get group members: %w", err)
+	}
+	return group, members, nil
+}
+
+func scimValidateGroupName(name string) error {
+	if name == "" {
+		return xerrors.Errorf("displayName is required: %w", spec.ErrInvalidValue)
+	}
+	if name == database.AllUsersGroup {
+		return xerrors.Errorf("%q is a reserved group name: %w", database.AllUsersGroup, spec.ErrInvalidValue)
+	}
+	return nil
+}
+
+// scimMemberIDs returns the user IDs of the members.
+func scimMemberIDs(members []SCIMGroupMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("member %q is not a user ID: %w", member.Value, spec.ErrInvalidValue)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// scimMemberValueIDs returns the user IDs of the members in the value of a
+// patch operation, which is a member or a list of members.
+func scimMemberValueIDs(value interface{}) ([]uuid.UUID, error) {
+	if _, ok := value.(map[string]interface{}); ok {
+		value = []interface{}{value}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal members: %w", err)
+	}
+	var members []SCIMGroupMember
+	err = json.Unmarshal(data, &members)
+	if err != nil {
+		return nil, xerrors.Errorf("members must be a list of members: %w", spec.ErrInvalidValue)
+	}
+	return scimMemberIDs(members)
+}
+
+func convertSCIMGroup(group database.Group, members []database.User) SCIMGroup {
+	sGroup := SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          group.ID.String(),
+		DisplayName: group.Name,
+		Members:     make([]SCIMGroupMember, 0, len(members)),
+	}
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	sGroup.Meta.ResourceType = "Group"
+	return sGroup
+}
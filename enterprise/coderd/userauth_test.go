@@ -14,6 +14,8 @@ import (
 
 	"github.com/coder/coder/coderd"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/ldap"
+	"github.com/coder/coder/coderd/ldap/ldaptest"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/testutil"
@@ -221,6 +223,84 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+	t.Run("Groups", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		const kyleDN = "uid=kyle,ou=people," + ldaptest.BaseDN
+		dir := ldaptest.New(t)
+		dir.Put(kyleDN, "password", map[string][]string{
+			"uid":      {"kyle"},
+			"mail":     {"kyle@coder.com"},
+			"memberOf": {"cn=bingbong,ou=groups," + ldaptest.BaseDN, "cn=pingpong,ou=groups," + ldaptest.BaseDN},
+		})
+
+		client := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				LDAPConfig: &coderd.LDAPConfig{
+					Directory: &ldap.Config{
+						URL:               dir.URL,
+						BindDN:            ldaptest.BindDN,
+						BindPassword:      ldaptest.BindPassword,
+						UserBaseDN:        ldaptest.BaseDN,
+						UserFilter:        "(mail={email})",
+						UsernameAttribute: "uid",
+						EmailAttribute:    "mail",
+						GroupAttribute:    "memberOf",
+					},
+					AllowSignups: true,
+					GroupMapping: map[string]string{
+						"cn=pingpong,ou=groups," + ldaptest.BaseDN: "dingdong",
+					},
+					SyncInterval: testutil.IntervalFast,
+				},
+			},
+		})
+		firstUser := coderdtest.CreateFirstUser(t, client)
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			AllFeatures: true,
+		})
+
+		bingbong, err := client.CreateGroup(ctx, firstUser.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "bingbong",
+		})
+		require.NoError(t, err)
+		dingdong, err := client.CreateGroup(ctx, firstUser.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "dingdong",
+		})
+		require.NoError(t, err)
+
+		_, err = codersdk.New(client.URL).LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "kyle@coder.com",
+			Password: "password",
+		})
+		require.NoError(t, err)
+
+		bingbong, err = client.Group(ctx, bingbong.ID)
+		require.NoError(t, err)
+		require.Len(t, bingbong.Members, 1)
+		dingdong, err = client.Group(ctx, dingdong.ID)
+		require.NoError(t, err)
+		require.Len(t, dingdong.Members, 1)
+
+		// Memberships removed in the directory are synced without a login.
+		dir.Put(kyleDN, "password", map[string][]string{
+			"uid":      {"kyle"},
+			"mail":     {"kyle@coder.com"},
+			"memberOf": {"cn=bingbong,ou=groups," + ldaptest.BaseDN},
+		})
+		require.Eventually(t, func() bool {
+			dingdong, err = client.Group(ctx, dingdong.ID)
+			return err == nil && len(dingdong.Members) == 0
+		}, testutil.WaitLong, testutil.IntervalFast)
+		bingbong, err = client.Group(ctx, bingbong.ID)
+		require.NoError(t, err)
+		require.Len(t, bingbong.Members, 1)
+	})
+}
+
 func oidcCallback(t *testing.T, client *codersdk.Client, code string) *http.Response {
 	t.Helper()
 	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
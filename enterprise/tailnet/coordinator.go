@@ -3,6 +3,7 @@ package tailnet
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,17 +15,35 @@ import (
 
 	"github.com/google/uuid"
 	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbauthz"
 	agpl "github.com/coder/coder/tailnet"
 )
 
+const (
+	// heartbeatInterval is how often the coordinator marks itself alive in
+	// the database.
+	heartbeatInterval = 10 * time.Second
+	// heartbeatTimeout is how long a coordinator can miss heartbeats before
+	// other replicas delete it and the nodes of its connections.
+	heartbeatTimeout = 3 * heartbeatInterval
+	// agentVersionRetention is how long the versions of agents that can't
+	// connect anymore are kept after their last update.
+	agentVersionRetention = 24 * time.Hour
+)
+
 // NewCoordinator creates a new high availability coordinator
-// that uses PostgreSQL pubsub to exchange handshakes.
-func NewCoordinator(logger slog.Logger, pubsub database.Pubsub) (agpl.Coordinator, error) {
-	ctx, cancelFunc := context.WithCancel(context.Background())
+// that uses PostgreSQL pubsub to exchange handshakes. Nodes are
+// persisted with a version, so peers that reconnect only receive
+// the nodes that changed since they were disconnected. The
+// registerer is optional.
+func NewCoordinator(logger slog.Logger, db database.Store, pubsub database.Pubsub, registerer prometheus.Registerer) (agpl.ResumingCoordinator, error) {
+	ctx, cancelFunc := context.WithCancel(dbauthz.AsSystemRestricted(context.Background())) //nolint:gocritic // The coordinator is a system component.
 
 	nameCache, err := lru.New[uuid.UUID, string](512)
 	if err != nil {
@@ -34,16 +53,52 @@ func NewCoordinator(logger slog.Logger, pubsub database.Pubsub) (agpl.Coordinato
 	coord := &haCoordinator{
 		id:                       uuid.New(),
 		log:                      logger,
+		db:                       db,
 		pubsub:                   pubsub,
+		ctx:                      ctx,
 		closeFunc:                cancelFunc,
 		close:                    make(chan struct{}),
 		nodes:                    map[uuid.UUID]*agpl.Node{},
 		agentSockets:             map[uuid.UUID]*agpl.TrackedConn{},
+		agentVersions:            map[uuid.UUID]*agentVersion{},
 		agentToConnectionSockets: map[uuid.UUID]map[uuid.UUID]*agpl.TrackedConn{},
 		agentNameCache:           nameCache,
+		registerer:               registerer,
+		resyncs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "tailnet_coordinator",
+			Name:      "resyncs_total",
+			Help:      "The number of node resyncs of connecting agents and clients, by peer and mode.",
+		}, []string{"peer", "mode"}),
+		resyncNodes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "tailnet_coordinator",
+			Name:      "resync_nodes_total",
+			Help:      "The number of nodes sent by resyncs of connecting agents and clients.",
+		}, []string{"peer"}),
+	}
+	if registerer != nil {
+		for _, collector := range []prometheus.Collector{coord.resyncs, coord.resyncNodes} {
+			err := registerer.Register(collector)
+			if err != nil {
+				cancelFunc()
+				coord.unregisterMetrics()
+				return nil, xerrors.Errorf("register metrics: %w", err)
+			}
+		}
 	}
 
+	_, err = db.UpsertTailnetCoordinator(ctx, coord.id)
+	if err != nil {
+		cancelFunc()
+		coord.unregisterMetrics()
+		return nil, xerrors.Errorf("insert coordinator: %w", err)
+	}
+	go coord.heartbeat()
+
 	if err := coord.runPubsub(ctx); err != nil {
+		cancelFunc()
+		coord.unregisterMetrics()
 		return nil, xerrors.Errorf("run coordinator pubsub: %w", err)
 	}
 
@@ -54,7 +109,9 @@ type haCoordinator struct {
 	id        uuid.UUID
 	log       slog.Logger
 	mutex     sync.RWMutex
+	db        database.Store
 	pubsub    database.Pubsub
+	ctx       context.Context
 	close     chan struct{}
 	closeFunc context.CancelFunc
 
@@ -62,6 +119,9 @@ type haCoordinator struct {
 	nodes map[uuid.UUID]*agpl.Node
 	// agentSockets maps agent IDs to their open websocket.
 	agentSockets map[uuid.UUID]*agpl.TrackedConn
+	// agentVersions maps agent IDs of open websockets to the version of the
+	// latest client node written to them.
+	agentVersions map[uuid.UUID]*agentVersion
 	// agentToConnectionSockets maps agent IDs to connection IDs of conns that
 	// are subscribed to updates for that agent.
 	agentToConnectionSockets map[uuid.UUID]map[uuid.UUID]*agpl.TrackedConn
@@ -69,6 +129,17 @@ type haCoordinator struct {
 	// agentNameCache holds a cache of agent names. If one of them disappears,
 	// it's helpful to have a name cached for debugging.
 	agentNameCache *lru.Cache[uuid.UUID, string]
+
+	registerer  prometheus.Registerer
+	resyncs     *prometheus.CounterVec
+	resyncNodes *prometheus.CounterVec
+}
+
+// agentVersion tracks the client nodes written to an agent. Writes are
+// serialized by mu, so nodes are written in version order.
+type agentVersion struct {
+	mu      sync.Mutex
+	version int64
 }
 
 // Node returns an in-memory node by ID.
@@ -82,6 +153,13 @@ func (c *haCoordinator) Node(id uuid.UUID) *agpl.Node {
 // ServeClient accepts a WebSocket connection that wants to connect to an agent
 // with the specified ID.
 func (c *haCoordinator) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error {
+	return c.ServeClientResume(conn, id, agent, 0)
+}
+
+// ServeClientResume accepts a WebSocket connection that wants to connect to an
+// agent with the specified ID. The agent node is only sent if its version is
+// newer than the given version.
+func (c *haCoordinator) ServeClientResume(conn net.Conn, id uuid.UUID, agent uuid.UUID, version int64) error {
 	c.mutex.Lock()
 	connectionSockets, ok := c.agentToConnectionSockets[agent]
 	if !ok {
@@ -102,16 +180,33 @@ func (c *haCoordinator) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID
 	// node of the agent. This allows the connection to establish.
 	node, ok := c.nodes[agent]
 	c.mutex.Unlock()
-	if ok {
-		data, err := json.Marshal([]*agpl.Node{node})
-		if err != nil {
-			return xerrors.Errorf("marshal node: %w", err)
+	if !ok {
+		dbNode, err := c.db.GetTailnetAgentNode(c.ctx, agent)
+		if err == nil {
+			node, err = nodeFromDatabase(dbNode)
 		}
-		_, err = conn.Write(data)
-		if err != nil {
-			return xerrors.Errorf("write nodes: %w", err)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			c.log.Warn(c.ctx, "get agent node", slog.F("agent_id", agent), slog.Error(err))
+		}
+		ok = err == nil
+	}
+	if ok {
+		c.countResync("client", version, 0)
+		if node.Version == 0 || node.Version > version {
+			data, err := json.Marshal([]*agpl.Node{node})
+			if err != nil {
+				return xerrors.Errorf("marshal node: %w", err)
+			}
+			_, err = conn.Write(data)
+			if err != nil {
+				return xerrors.Errorf("write nodes: %w", err)
+			}
+			c.resyncNodes.WithLabelValues("client").Inc()
 		}
 	} else {
+		// The agent isn't connected, or is connected to a replica that doesn't
+		// persist nodes. Ask for its node when it's available.
+		c.countResync("client", 0, 0)
 		err := c.publishClientHello(agent)
 		if err != nil {
 			return xerrors.Errorf("publish client hello: %w", err)
@@ -119,6 +214,7 @@ func (c *haCoordinator) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID
 	}
 
 	defer func() {
+		c.deleteNode(id)
 		c.mutex.Lock()
 		defer c.mutex.Unlock()
 		// Clean all traces of this connection from the map.
@@ -154,13 +250,31 @@ func (c *haCoordinator) handleNextClientMessage(id, agent uuid.UUID, decoder *js
 		return xerrors.Errorf("read json: %w", err)
 	}
 
+	node.Version = 0
+	data, err := json.Marshal(&node)
+	if err != nil {
+		return xerrors.Errorf("marshal node: %w", err)
+	}
+	dbNode, err := c.db.UpsertTailnetClientNode(c.ctx, database.UpsertTailnetClientNodeParams{
+		AgentID:       agent,
+		ID:            id,
+		CoordinatorID: c.id,
+		Node:          data,
+	})
+	if err != nil {
+		// The node is still sent without a version, so the agent receives it
+		// but can't resume from it.
+		c.log.Warn(c.ctx, "persist client node", slog.F("client_id", id), slog.Error(err))
+	} else {
+		node.Version = dbNode.Version
+	}
+
 	c.mutex.Lock()
 	// Update the node of this client in our in-memory map. If an agent entirely
 	// shuts down and reconnects, it needs to be aware of all clients attempting
 	// to establish connections.
 	c.nodes[id] = &node
-	// Write the new node from this client to the actively connected agent.
-	agentSocket, ok := c.agentSockets[agent]
+	_, ok := c.agentSockets[agent]
 	c.mutex.Unlock()
 	if !ok {
 		// If we don't own the agent locally, send it over pubsub to a node that
@@ -174,11 +288,64 @@ func (c *haCoordinator) handleNextClientMessage(id, agent uuid.UUID, decoder *js
 
 	// Write the new node from this client to the actively
 	// connected agent.
-	data, err := json.Marshal([]*agpl.Node{&node})
+	return c.writeNodesToAgent(agent, []*agpl.Node{&node})
+}
+
+// writeNodesToAgent writes client nodes to the agent if it's connected to this
+// coordinator. Nodes the agent already received are skipped. If a version is
+// missing, e.g. because a pubsub message was dropped or arrived out of order,
+// the nodes that changed since the last version written are read from the
+// database instead.
+func (c *haCoordinator) writeNodesToAgent(agent uuid.UUID, nodes []*agpl.Node) error {
+	c.mutex.Lock()
+	agentSocket, ok := c.agentSockets[agent]
+	state := c.agentVersions[agent]
+	c.mutex.Unlock()
+	if !ok || state == nil {
+		return nil
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	slices.SortFunc(nodes, func(a, b *agpl.Node) bool {
+		return a.Version < b.Version
+	})
+	send := make([]*agpl.Node, 0, len(nodes))
+	for _, node := range nodes {
+		switch {
+		case node.Version == 0:
+			// Sent by a replica that doesn't persist nodes.
+			send = append(send, node)
+		case node.Version <= state.version:
+		case node.Version == state.version+1:
+			send = append(send, node)
+			state.version = node.Version
+		default:
+			missed, err := c.clientNodesAfterVersion(agent, state.version)
+			if err != nil {
+				c.log.Warn(c.ctx, "get missed client nodes", slog.F("agent_id", agent), slog.Error(err))
+				send = append(send, node)
+				state.version = node.Version
+				continue
+			}
+			c.countResync("agent", state.version, len(missed))
+			for _, missedNode := range missed {
+				if missedNode.Version > state.version {
+					state.version = missedNode.Version
+				}
+			}
+			send = append(send, missed...)
+		}
+	}
+	if len(send) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(send)
 	if err != nil {
 		return xerrors.Errorf("marshal nodes: %w", err)
 	}
-
 	_, err = agentSocket.Write(data)
 	if err != nil {
 		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
@@ -186,33 +353,31 @@ func (c *haCoordinator) handleNextClientMessage(id, agent uuid.UUID, decoder *js
 		}
 		return xerrors.Errorf("write json: %w", err)
 	}
-
 	return nil
 }
 
 // ServeAgent accepts a WebSocket connection to an agent that listens to
 // incoming connections and publishes node updates.
 func (c *haCoordinator) ServeAgent(conn net.Conn, id uuid.UUID, name string) error {
-	c.agentNameCache.Add(id, name)
+	return c.ServeAgentResume(conn, id, name, 0)
+}
 
-	// Publish all nodes on this instance that want to connect to this agent.
-	nodes := c.nodesSubscribedToAgent(id)
-	if len(nodes) > 0 {
-		data, err := json.Marshal(nodes)
-		if err != nil {
-			return xerrors.Errorf("marshal json: %w", err)
-		}
-		_, err = conn.Write(data)
-		if err != nil {
-			return xerrors.Errorf("write nodes: %w", err)
-		}
-	}
+// ServeAgentResume accepts a WebSocket connection to an agent that listens to
+// incoming connections and publishes node updates. Only client nodes with a
+// version newer than the given version are sent.
+func (c *haCoordinator) ServeAgentResume(conn net.Conn, id uuid.UUID, name string, version int64) error {
+	c.agentNameCache.Add(id, name)
 
 	// This uniquely identifies a connection that belongs to this goroutine.
 	unique := uuid.New()
 	now := time.Now().Unix()
 	overwrites := int64(0)
 
+	// Client nodes that arrive while the agent is synced wait for the state,
+	// so they're deduplicated against the synced nodes.
+	state := &agentVersion{version: version}
+	state.mu.Lock()
+
 	// If an old agent socket is connected, we close it
 	// to avoid any leaks. This shouldn't ever occur because
 	// we expect one agent to be running.
@@ -231,29 +396,35 @@ func (c *haCoordinator) ServeAgent(conn net.Conn, id uuid.UUID, name string) err
 		LastWrite:  now,
 		Overwrites: overwrites,
 	}
+	c.agentVersions[id] = state
 	c.mutex.Unlock()
 
-	// Tell clients on other instances to send a callmemaybe to us.
-	err := c.publishAgentHello(id)
-	if err != nil {
-		return xerrors.Errorf("publish agent hello: %w", err)
-	}
-
 	defer func() {
 		c.mutex.Lock()
-		defer c.mutex.Unlock()
-
 		// Only delete the connection if it's ours. It could have been
 		// overwritten.
-		if idConn, ok := c.agentSockets[id]; ok && idConn.ID == unique {
+		idConn, ok := c.agentSockets[id]
+		ours := ok && idConn.ID == unique
+		if ours {
 			delete(c.agentSockets, id)
+			delete(c.agentVersions, id)
 			delete(c.nodes, id)
 		}
+		c.mutex.Unlock()
+		if ours {
+			c.deleteNode(id)
+		}
 	}()
 
+	err := c.syncAgent(conn, id, state)
+	state.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
 	decoder := json.NewDecoder(conn)
 	for {
-		node, err := c.handleAgentUpdate(id, decoder)
+		node, err := c.handleNextAgentMessage(id, decoder)
 		if err != nil {
 			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, context.Canceled) {
 				return nil
@@ -268,6 +439,99 @@ func (c *haCoordinator) ServeAgent(conn net.Conn, id uuid.UUID, name string) err
 	}
 }
 
+// syncAgent writes the client nodes that changed since the version of the
+// state to a newly connected agent. state.mu must be held.
+func (c *haCoordinator) syncAgent(conn net.Conn, id uuid.UUID, state *agentVersion) error {
+	resumeVersion := state.version
+	nodes, err := c.clientNodesAfterVersion(id, resumeVersion)
+	if err != nil {
+		// Fall back to the nodes of this replica, and ask other replicas for
+		// theirs.
+		c.log.Warn(c.ctx, "get client nodes", slog.F("agent_id", id), slog.Error(err))
+		nodes = c.nodesSubscribedToAgent(id)
+		resumeVersion = 0
+	}
+	c.countResync("agent", resumeVersion, len(nodes))
+	for _, node := range nodes {
+		if node.Version > state.version {
+			state.version = node.Version
+		}
+	}
+	if len(nodes) > 0 {
+		data, err := json.Marshal(nodes)
+		if err != nil {
+			return xerrors.Errorf("marshal json: %w", err)
+		}
+		_, err = conn.Write(data)
+		if err != nil {
+			return xerrors.Errorf("write nodes: %w", err)
+		}
+	}
+
+	// The persisted nodes are complete unless replicas that don't persist
+	// nodes are running, so only a full sync asks other replicas to send a
+	// callmemaybe to us. Nodes the agent already received are skipped.
+	if resumeVersion == 0 {
+		err := c.publishAgentHello(id)
+		if err != nil {
+			return xerrors.Errorf("publish agent hello: %w", err)
+		}
+	}
+	return nil
+}
+
+// clientNodesAfterVersion reads the nodes of the clients of the agent with a
+// version newer than the given version.
+func (c *haCoordinator) clientNodesAfterVersion(agent uuid.UUID, version int64) ([]*agpl.Node, error) {
+	dbNodes, err := c.db.GetTailnetClientNodesAfterVersion(c.ctx, database.GetTailnetClientNodesAfterVersionParams{
+		AgentID:      agent,
+		AfterVersion: version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*agpl.Node, 0, len(dbNodes))
+	for _, dbNode := range dbNodes {
+		node, err := nodeFromDatabase(dbNode)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func nodeFromDatabase(dbNode database.TailnetNode) (*agpl.Node, error) {
+	var node agpl.Node
+	err := json.Unmarshal(dbNode.Node, &node)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal node %s: %w", dbNode.ID, err)
+	}
+	node.Version = dbNode.Version
+	return &node, nil
+}
+
+// countResync records a resync of a peer. A version of zero is a full resync.
+func (c *haCoordinator) countResync(peer string, version int64, nodes int) {
+	mode := "incremental"
+	if version == 0 {
+		mode = "full"
+	}
+	c.resyncs.WithLabelValues(peer, mode).Inc()
+	c.resyncNodes.WithLabelValues(peer).Add(float64(nodes))
+}
+
+// deleteNode removes the persisted node of a connection that closed.
+func (c *haCoordinator) deleteNode(id uuid.UUID) {
+	err := c.db.DeleteTailnetNode(c.ctx, database.DeleteTailnetNodeParams{
+		ID:            id,
+		CoordinatorID: c.id,
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		c.log.Warn(c.ctx, "delete node", slog.F("id", id), slog.Error(err))
+	}
+}
+
 func (c *haCoordinator) nodesSubscribedToAgent(agentID uuid.UUID) []*agpl.Node {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -298,29 +562,55 @@ func (c *haCoordinator) handleClientHello(id uuid.UUID) error {
 	return c.publishAgentToNodes(id, node)
 }
 
-func (c *haCoordinator) handleAgentUpdate(id uuid.UUID, decoder *json.Decoder) (*agpl.Node, error) {
+// handleNextAgentMessage persists the next node of an agent connected to this
+// coordinator, so it's versioned before it's sent to clients.
+func (c *haCoordinator) handleNextAgentMessage(id uuid.UUID, decoder *json.Decoder) (*agpl.Node, error) {
 	var node agpl.Node
 	err := decoder.Decode(&node)
 	if err != nil {
 		return nil, xerrors.Errorf("read json: %w", err)
 	}
 
+	node.Version = 0
+	data, err := json.Marshal(&node)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal node: %w", err)
+	}
+	dbNode, err := c.db.UpsertTailnetAgentNode(c.ctx, database.UpsertTailnetAgentNodeParams{
+		AgentID:       id,
+		CoordinatorID: c.id,
+		Node:          data,
+	})
+	if err != nil {
+		c.log.Warn(c.ctx, "persist agent node", slog.F("agent_id", id), slog.Error(err))
+	} else {
+		node.Version = dbNode.Version
+	}
+	return c.handleAgentUpdate(id, &node)
+}
+
+func (c *haCoordinator) handleAgentUpdate(id uuid.UUID, node *agpl.Node) (*agpl.Node, error) {
 	c.mutex.Lock()
 	oldNode := c.nodes[id]
 	if oldNode != nil {
-		if oldNode.AsOf.After(node.AsOf) {
+		if oldNode.Version != 0 && node.Version != 0 {
+			if oldNode.Version >= node.Version {
+				c.mutex.Unlock()
+				return oldNode, nil
+			}
+		} else if oldNode.AsOf.After(node.AsOf) {
 			c.mutex.Unlock()
 			return oldNode, nil
 		}
 	}
-	c.nodes[id] = &node
+	c.nodes[id] = node
 	connectionSockets, ok := c.agentToConnectionSockets[id]
 	if !ok {
 		c.mutex.Unlock()
-		return &node, nil
+		return node, nil
 	}
 
-	data, err := json.Marshal([]*agpl.Node{&node})
+	data, err := json.Marshal([]*agpl.Node{node})
 	if err != nil {
 		c.mutex.Unlock()
 		return nil, xerrors.Errorf("marshal nodes: %w", err)
@@ -339,7 +629,45 @@ func (c *haCoordinator) handleAgentUpdate(id uuid.UUID, decoder *json.Decoder) (
 	}
 	c.mutex.Unlock()
 	wg.Wait()
-	return &node, nil
+	return node, nil
+}
+
+// heartbeat marks the coordinator alive until it's closed, and deletes
+// coordinators that stopped without cleaning up, along with their nodes, and
+// the versions of agents that can't connect anymore.
+func (c *haCoordinator) heartbeat() {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, err := c.db.UpsertTailnetCoordinator(c.ctx, c.id)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				c.log.Warn(c.ctx, "update coordinator heartbeat", slog.Error(err))
+			}
+			continue
+		}
+		err = c.db.DeleteTailnetCoordinatorsHeartbeatBefore(c.ctx, database.Now().Add(-heartbeatTimeout))
+		if err != nil && !errors.Is(err, context.Canceled) {
+			c.log.Warn(c.ctx, "delete expired coordinators", slog.Error(err))
+		}
+		err = c.db.DeleteTailnetAgentVersionsUpdatedBefore(c.ctx, database.Now().Add(-agentVersionRetention))
+		if err != nil && !errors.Is(err, context.Canceled) {
+			c.log.Warn(c.ctx, "delete expired agent versions", slog.Error(err))
+		}
+	}
+}
+
+func (c *haCoordinator) unregisterMetrics() {
+	if c.registerer == nil {
+		return
+	}
+	c.registerer.Unregister(c.resyncs)
+	c.registerer.Unregister(c.resyncNodes)
 }
 
 // Close closes all of the open connections in the coordinator and stops the
@@ -354,6 +682,17 @@ func (c *haCoordinator) Close() error {
 	}
 	close(c.close)
 	c.closeFunc()
+	c.unregisterMetrics()
+	// The connections of this coordinator are closing, so delete their nodes
+	// at once instead of one by one. The context of the coordinator is
+	// canceled, so use a new one.
+	//nolint:gocritic // The coordinator is a system component.
+	ctx, cancel := context.WithTimeout(dbauthz.AsSystemRestricted(context.Background()), 5*time.Second)
+	defer cancel()
+	err := c.db.DeleteTailnetCoordinator(ctx, c.id)
+	if err != nil {
+		c.log.Warn(ctx, "delete coordinator", slog.Error(err))
+	}
 
 	wg := sync.WaitGroup{}
 
@@ -501,19 +840,20 @@ func (c *haCoordinator) handlePubsubMessage(ctx context.Context, message []byte)
 		}
 
 		c.mutex.Lock()
-		agentSocket, ok := c.agentSockets[agentUUID]
+		_, ok := c.agentSockets[agentUUID]
+		c.mutex.Unlock()
 		if !ok {
-			c.mutex.Unlock()
 			return
 		}
-		c.mutex.Unlock()
 
-		// We get a single node over pubsub, so turn into an array.
-		_, err = agentSocket.Write(nodeJSON)
+		var nodes []*agpl.Node
+		err = json.Unmarshal(nodeJSON, &nodes)
+		if err != nil {
+			c.log.Error(ctx, "invalid nodes JSON", slog.F("id", agentID), slog.Error(err), slog.F("node", string(nodeJSON)))
+			return
+		}
+		err = c.writeNodesToAgent(agentUUID, nodes)
 		if err != nil {
-			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
-				return
-			}
 			c.log.Error(ctx, "send callmemaybe to agent", slog.Error(err))
 			return
 		}
@@ -551,8 +891,13 @@ func (c *haCoordinator) handlePubsubMessage(ctx context.Context, message []byte)
 			return
 		}
 
-		decoder := json.NewDecoder(bytes.NewReader(nodeJSON))
-		_, err = c.handleAgentUpdate(agentUUID, decoder)
+		var node agpl.Node
+		err = json.Unmarshal(nodeJSON, &node)
+		if err != nil {
+			c.log.Error(ctx, "invalid node JSON", slog.F("id", agentID), slog.Error(err), slog.F("node", string(nodeJSON)))
+			return
+		}
+		_, err = c.handleAgentUpdate(agentUUID, &node)
 		if err != nil {
 			c.log.Error(ctx, "handle agent update", slog.Error(err))
 			return
@@ -1,6 +1,7 @@
 package tailnet_test
 
 import (
+	"context"
 	"net"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/dbfake"
 	"github.com/coder/coder/coderd/database/dbtestutil"
 	"github.com/coder/coder/enterprise/tailnet"
 	agpl "github.com/coder/coder/tailnet"
@@ -21,7 +23,7 @@ func TestCoordinatorSingle(t *testing.T) {
 	t.Parallel()
 	t.Run("ClientWithoutAgent", func(t *testing.T) {
 		t.Parallel()
-		coordinator, err := tailnet.NewCoordinator(slogtest.Make(t, nil), database.NewPubsubInMemory())
+		coordinator, err := tailnet.NewCoordinator(slogtest.Make(t, nil), dbfake.New(), database.NewPubsubInMemory(), nil)
 		require.NoError(t, err)
 		defer coordinator.Close()
 
@@ -49,7 +51,7 @@ func TestCoordinatorSingle(t *testing.T) {
 
 	t.Run("AgentWithoutClients", func(t *testing.T) {
 		t.Parallel()
-		coordinator, err := tailnet.NewCoordinator(slogtest.Make(t, nil), database.NewPubsubInMemory())
+		coordinator, err := tailnet.NewCoordinator(slogtest.Make(t, nil), dbfake.New(), database.NewPubsubInMemory(), nil)
 		require.NoError(t, err)
 		defer coordinator.Close()
 
@@ -77,7 +79,7 @@ func TestCoordinatorSingle(t *testing.T) {
 	t.Run("AgentWithClient", func(t *testing.T) {
 		t.Parallel()
 
-		coordinator, err := tailnet.NewCoordinator(slogtest.Make(t, nil), database.NewPubsubInMemory())
+		coordinator, err := tailnet.NewCoordinator(slogtest.Make(t, nil), dbfake.New(), database.NewPubsubInMemory(), nil)
 		require.NoError(t, err)
 		defer coordinator.Close()
 
@@ -168,9 +170,9 @@ func TestCoordinatorHA(t *testing.T) {
 	t.Run("AgentWithClient", func(t *testing.T) {
 		t.Parallel()
 
-		_, pubsub := dbtestutil.NewDB(t)
+		db, pubsub := dbtestutil.NewDB(t)
 
-		coordinator1, err := tailnet.NewCoordinator(slogtest.Make(t, nil), pubsub)
+		coordinator1, err := tailnet.NewCoordinator(slogtest.Make(t, nil), db, pubsub, nil)
 		require.NoError(t, err)
 		defer coordinator1.Close()
 
@@ -193,7 +195,7 @@ func TestCoordinatorHA(t *testing.T) {
 			return coordinator1.Node(agentID) != nil
 		}, testutil.WaitShort, testutil.IntervalFast)
 
-		coordinator2, err := tailnet.NewCoordinator(slogtest.Make(t, nil), pubsub)
+		coordinator2, err := tailnet.NewCoordinator(slogtest.Make(t, nil), db, pubsub, nil)
 		require.NoError(t, err)
 		defer coordinator2.Close()
 
@@ -258,4 +260,99 @@ func TestCoordinatorHA(t *testing.T) {
 		<-clientErrChan
 		<-closeClientChan
 	})
+	t.Run("AgentResume", func(t *testing.T) {
+		t.Parallel()
+
+		db, pubsub := dbtestutil.NewDB(t)
+
+		coordinator1, err := tailnet.NewCoordinator(slogtest.Make(t, nil), db, pubsub, nil)
+		require.NoError(t, err)
+		defer coordinator1.Close()
+		coordinator2, err := tailnet.NewCoordinator(slogtest.Make(t, nil), db, pubsub, nil)
+		require.NoError(t, err)
+		defer coordinator2.Close()
+
+		agentWS, agentServerWS := net.Pipe()
+		defer agentWS.Close()
+		agentNodeChan := make(chan []*agpl.Node)
+		sendAgentNode, agentErrChan := agpl.ServeCoordinator(agentWS, func(nodes []*agpl.Node) error {
+			agentNodeChan <- nodes
+			return nil
+		})
+		agentID := uuid.New()
+		closeAgentChan := make(chan struct{})
+		go func() {
+			err := coordinator1.ServeAgent(agentServerWS, agentID, "")
+			assert.NoError(t, err)
+			close(closeAgentChan)
+		}()
+		sendAgentNode(&agpl.Node{})
+		require.Eventually(t, func() bool {
+			return coordinator1.Node(agentID) != nil
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Connect two clients to the other coordinator, so their nodes are
+		// sent to the agent over pubsub.
+		sendClientNodes := make([]func(*agpl.Node), 0, 2)
+		for i := 0; i < 2; i++ {
+			clientWS, clientServerWS := net.Pipe()
+			defer clientWS.Close()
+			defer clientServerWS.Close()
+			clientNodeChan := make(chan []*agpl.Node)
+			sendClientNode, _ := agpl.ServeCoordinator(clientWS, func(nodes []*agpl.Node) error {
+				clientNodeChan <- nodes
+				return nil
+			})
+			go func() {
+				_ = coordinator2.ServeClient(clientServerWS, uuid.New(), agentID)
+			}()
+			agentNodes := <-clientNodeChan
+			require.Len(t, agentNodes, 1)
+			require.NotZero(t, agentNodes[0].Version)
+
+			sendClientNode(&agpl.Node{})
+			clientNodes := <-agentNodeChan
+			require.Len(t, clientNodes, 1)
+			require.EqualValues(t, i+1, clientNodes[0].Version)
+			sendClientNodes = append(sendClientNodes, sendClientNode)
+		}
+
+		// Disconnect the agent, and update the node of the second client
+		// while it's away.
+		require.NoError(t, agentWS.Close())
+		require.NoError(t, agentServerWS.Close())
+		<-agentErrChan
+		<-closeAgentChan
+		sendClientNodes[1](&agpl.Node{})
+		require.Eventually(t, func() bool {
+			nodes, err := db.GetTailnetClientNodesAfterVersion(context.Background(), database.GetTailnetClientNodesAfterVersionParams{
+				AgentID:      agentID,
+				AfterVersion: 2,
+			})
+			return err == nil && len(nodes) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// The agent resumes from the last version it received, so it only
+		// receives the updated node.
+		agentWS, agentServerWS = net.Pipe()
+		defer agentWS.Close()
+		agentNodeChan = make(chan []*agpl.Node)
+		_, agentErrChan = agpl.ServeCoordinator(agentWS, func(nodes []*agpl.Node) error {
+			agentNodeChan <- nodes
+			return nil
+		})
+		closeAgentChan = make(chan struct{})
+		go func() {
+			err := coordinator1.ServeAgentResume(agentServerWS, agentID, "", 2)
+			assert.NoError(t, err)
+			close(closeAgentChan)
+		}()
+		clientNodes := <-agentNodeChan
+		require.Len(t, clientNodes, 1)
+		require.EqualValues(t, 3, clientNodes[0].Version)
+
+		require.NoError(t, agentWS.Close())
+		<-agentErrChan
+		<-closeAgentChan
+	})
 }
@@ -223,10 +223,20 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 	if err != nil {
 		return nil, xerrors.Errorf("terraform plan: %w", err)
 	}
-	state, err := e.planResources(ctx, killCtx, planfilePath)
+	plan, err := e.showPlan(ctx, killCtx, planfilePath)
+	if err != nil {
+		return nil, xerrors.Errorf("show terraform plan file: %w", err)
+	}
+	state, err := e.planResources(ctx, killCtx, plan)
 	if err != nil {
 		return nil, err
 	}
+	// The JSON representation of the plan is used by coderd to evaluate
+	// template import policies.
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal terraform plan: %w", err)
+	}
 	planFileByt, err := os.ReadFile(planfilePath)
 	if err != nil {
 		return nil, err
@@ -238,18 +248,14 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 				Resources:        state.Resources,
 				GitAuthProviders: state.GitAuthProviders,
 				Plan:             planFileByt,
+				PlanJson:         planJSON,
 			},
 		},
 	}, nil
 }
 
 // planResources must only be called while the lock is held.
-func (e *executor) planResources(ctx, killCtx context.Context, planfilePath string) (*State, error) {
-	plan, err := e.showPlan(ctx, killCtx, planfilePath)
-	if err != nil {
-		return nil, xerrors.Errorf("show terraform plan file: %w", err)
-	}
-
+func (e *executor) planResources(ctx, killCtx context.Context, plan *tfjson.Plan) (*State, error) {
 	rawGraph, err := e.graph(ctx, killCtx)
 	if err != nil {
 		return nil, xerrors.Errorf("graph: %w", err)
@@ -529,7 +535,11 @@ func provisionReadAndLog(sink logSink, r io.Reader, done chan<- any) {
 		}
 
 		logLevel := convertTerraformLogLevel(log.Level, sink)
-		sink.Log(&proto.Log{Level: logLevel, Output: log.Message})
+		sink.Log(&proto.Log{
+			Level:            logLevel,
+			Output:           log.Message,
+			ResourceProgress: convertTerraformResourceProgress(log),
+		})
 
 		// If the diagnostic is provided, let's provide a bit more info!
 		if log.Diagnostic == nil {
@@ -563,11 +573,57 @@ func convertTerraformLogLevel(logLevel string, sink logSink) proto.LogLevel {
 	}
 }
 
+// convertTerraformResourceProgress returns the resource progress described by
+// a hook message in Terraform's machine-readable UI, or nil if the log isn't
+// a resource hook.
+// See: https://developer.hashicorp.com/terraform/internals/machine-readable-ui
+func convertTerraformResourceProgress(log terraformProvisionLog) *proto.ResourceProgress {
+	if log.Hook == nil || log.Hook.Resource.Addr == "" {
+		return nil
+	}
+	progress := &proto.ResourceProgress{
+		Address:        log.Hook.Resource.Addr,
+		Action:         log.Hook.Action,
+		ElapsedSeconds: log.Hook.ElapsedSeconds,
+	}
+	switch log.Type {
+	case "apply_start":
+		progress.Status = proto.ResourceProgress_STARTED
+	case "apply_progress":
+		progress.Status = proto.ResourceProgress_PROGRESS
+	case "apply_complete":
+		progress.Status = proto.ResourceProgress_COMPLETE
+	case "apply_errored":
+		progress.Status = proto.ResourceProgress_ERRORED
+	case "refresh_start":
+		progress.Action = "read"
+		progress.Status = proto.ResourceProgress_STARTED
+	case "refresh_complete":
+		progress.Action = "read"
+		progress.Status = proto.ResourceProgress_COMPLETE
+	default:
+		return nil
+	}
+	return progress
+}
+
 type terraformProvisionLog struct {
 	Level   string `json:"@level"`
 	Message string `json:"@message"`
+	Type    string `json:"type"`
 
 	Diagnostic *tfjson.Diagnostic `json:"diagnostic,omitempty"`
+	Hook       *terraformHook     `json:"hook,omitempty"`
+}
+
+// terraformHook is the payload of the resource hook messages ("apply_start",
+// "apply_progress", "refresh_complete", etc.) emitted with -json.
+type terraformHook struct {
+	Resource struct {
+		Addr string `json:"addr"`
+	} `json:"resource"`
+	Action         string `json:"action"`
+	ElapsedSeconds int64  `json:"elapsed_seconds"`
 }
 
 // syncWriter wraps an io.Writer in a sync.Mutex.
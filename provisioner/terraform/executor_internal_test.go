@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	protobuf "google.golang.org/protobuf/proto"
 
 	"github.com/coder/coder/provisionersdk/proto"
 )
@@ -41,3 +42,78 @@ From standing in the English rain`))
 	}
 	require.Equal(t, expected, logr.logs)
 }
+
+func TestProvisionLogWriter_ResourceProgress(t *testing.T) {
+	t.Parallel()
+
+	logr := &mockLogger{}
+	writer, doneLogging := provisionLogWriter(logr)
+
+	_, err := writer.Write([]byte(`{"@level":"info","@message":"Terraform 1.3.4","type":"version"}
+{"@level":"info","@message":"coder_agent.dev: Refreshing state... [id=1234]","hook":{"resource":{"addr":"coder_agent.dev"},"id_key":"id","id_value":"1234"},"type":"refresh_start"}
+{"@level":"info","@message":"docker_container.workspace[0]: Creating...","hook":{"resource":{"addr":"docker_container.workspace[0]"},"action":"create"},"type":"apply_start"}
+{"@level":"info","@message":"docker_container.workspace[0]: Still creating... [10s elapsed]","hook":{"resource":{"addr":"docker_container.workspace[0]"},"action":"create","elapsed_seconds":10},"type":"apply_progress"}
+{"@level":"info","@message":"docker_container.workspace[0]: Creation complete after 12s [id=abcd]","hook":{"resource":{"addr":"docker_container.workspace[0]"},"action":"create","id_key":"id","id_value":"abcd","elapsed_seconds":12},"type":"apply_complete"}
+{"@level":"error","@message":"docker_volume.home: Creation errored after 1s","hook":{"resource":{"addr":"docker_volume.home"},"action":"create","elapsed_seconds":1},"type":"apply_errored"}
+`))
+	require.NoError(t, err)
+	err = writer.Close()
+	require.NoError(t, err)
+	<-doneLogging
+
+	expected := []*proto.Log{
+		{Level: proto.LogLevel_INFO, Output: "Terraform 1.3.4"},
+		{
+			Level:  proto.LogLevel_INFO,
+			Output: "coder_agent.dev: Refreshing state... [id=1234]",
+			ResourceProgress: &proto.ResourceProgress{
+				Address: "coder_agent.dev",
+				Action:  "read",
+				Status:  proto.ResourceProgress_STARTED,
+			},
+		},
+		{
+			Level:  proto.LogLevel_INFO,
+			Output: "docker_container.workspace[0]: Creating...",
+			ResourceProgress: &proto.ResourceProgress{
+				Address: "docker_container.workspace[0]",
+				Action:  "create",
+				Status:  proto.ResourceProgress_STARTED,
+			},
+		},
+		{
+			Level:  proto.LogLevel_INFO,
+			Output: "docker_container.workspace[0]: Still creating... [10s elapsed]",
+			ResourceProgress: &proto.ResourceProgress{
+				Address:        "docker_container.workspace[0]",
+				Action:         "create",
+				Status:         proto.ResourceProgress_PROGRESS,
+				ElapsedSeconds: 10,
+			},
+		},
+		{
+			Level:  proto.LogLevel_INFO,
+			Output: "docker_container.workspace[0]: Creation complete after 12s [id=abcd]",
+			ResourceProgress: &proto.ResourceProgress{
+				Address:        "docker_container.workspace[0]",
+				Action:         "create",
+				Status:         proto.ResourceProgress_COMPLETE,
+				ElapsedSeconds: 12,
+			},
+		},
+		{
+			Level:  proto.LogLevel_ERROR,
+			Output: "docker_volume.home: Creation errored after 1s",
+			ResourceProgress: &proto.ResourceProgress{
+				Address:        "docker_volume.home",
+				Action:         "create",
+				Status:         proto.ResourceProgress_ERRORED,
+				ElapsedSeconds: 1,
+			},
+		},
+	}
+	require.Len(t, logr.logs, len(expected))
+	for i := range expected {
+		require.True(t, protobuf.Equal(expected[i], logr.logs[i]), "log %d: expected %v, got %v", i, expected[i], logr.logs[i])
+	}
+}
@@ -122,6 +122,7 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 	}
 
 	s.logger.Debug(ctx, "running initialization")
+	initStarted := time.Now()
 	err = e.init(ctx, killCtx, sink)
 	if err != nil {
 		if ctx.Err() != nil {
@@ -136,6 +137,7 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 		return xerrors.Errorf("initialize terraform: %w", err)
 	}
 	s.logger.Debug(ctx, "ran initialization")
+	initTiming := stageTiming("init", initStarted)
 	env, err := provisionEnv(config, request.GetPlan().GetParameterValues(), request.GetPlan().GetRichParameterValues(), request.GetPlan().GetGitAuthProviders())
 	if err != nil {
 		return err
@@ -148,6 +150,7 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 			return err
 		}
 
+		planStarted := time.Now()
 		resp, err = e.plan(
 			ctx, killCtx, env, vars, sink,
 			config.Metadata.WorkspaceTransition == proto.WorkspaceTransition_DESTROY,
@@ -164,9 +167,14 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 			}
 			return xerrors.Errorf("plan terraform: %w", err)
 		}
+		if complete := resp.GetComplete(); complete != nil {
+			complete.Timings = append(complete.Timings, initTiming, stageTiming("plan", planStarted))
+		}
 		return stream.Send(resp)
 	}
-	// Must be apply
+	// Must be apply. Apply re-runs init against the directory that planning
+	// already initialized, so only the plan reports an init timing.
+	applyStarted := time.Now()
 	resp, err = e.apply(
 		ctx, killCtx, applyRequest.Plan, env, sink,
 	)
@@ -178,15 +186,29 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 		return stream.Send(&proto.Provision_Response{
 			Type: &proto.Provision_Response_Complete{
 				Complete: &proto.Provision_Complete{
-					State: stateData,
-					Error: errorMessage,
+					State:   stateData,
+					Error:   errorMessage,
+					Timings: []*proto.Timing{stageTiming("apply", applyStarted)},
 				},
 			},
 		})
 	}
+	if complete := resp.GetComplete(); complete != nil {
+		complete.Timings = append(complete.Timings, stageTiming("apply", applyStarted))
+	}
 	return stream.Send(resp)
 }
 
+// stageTiming returns a timing for a stage that started at the given time
+// and has just ended.
+func stageTiming(stage string, startedAt time.Time) *proto.Timing {
+	return &proto.Timing{
+		Stage:     stage,
+		StartedAt: startedAt.UnixMilli(),
+		EndedAt:   time.Now().UnixMilli(),
+	}
+}
+
 func planVars(plan *proto.Provision_Plan) ([]string, error) {
 	vars := []string{}
 	for _, param := range plan.ParameterValues {
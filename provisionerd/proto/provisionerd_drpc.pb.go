@@ -43,6 +43,7 @@ type DRPCProvisionerDaemonClient interface {
 	UpdateJob(ctx context.Context, in *UpdateJobRequest) (*UpdateJobResponse, error)
 	FailJob(ctx context.Context, in *FailedJob) (*Empty, error)
 	CompleteJob(ctx context.Context, in *CompletedJob) (*Empty, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest) (*Empty, error)
 }
 
 type drpcProvisionerDaemonClient struct {
@@ -100,12 +101,22 @@ func (c *drpcProvisionerDaemonClient) CompleteJob(ctx context.Context, in *Compl
 	return out, nil
 }
 
+func (c *drpcProvisionerDaemonClient) Heartbeat(ctx context.Context, in *HeartbeatRequest) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/provisionerd.ProvisionerDaemon/Heartbeat", drpcEncoding_File_provisionerd_proto_provisionerd_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCProvisionerDaemonServer interface {
 	AcquireJob(context.Context, *Empty) (*AcquiredJob, error)
 	CommitQuota(context.Context, *CommitQuotaRequest) (*CommitQuotaResponse, error)
 	UpdateJob(context.Context, *UpdateJobRequest) (*UpdateJobResponse, error)
 	FailJob(context.Context, *FailedJob) (*Empty, error)
 	CompleteJob(context.Context, *CompletedJob) (*Empty, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*Empty, error)
 }
 
 type DRPCProvisionerDaemonUnimplementedServer struct{}
@@ -130,9 +141,13 @@ func (s *DRPCProvisionerDaemonUnimplementedServer) CompleteJob(context.Context,
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCProvisionerDaemonUnimplementedServer) Heartbeat(context.Context, *HeartbeatRequest) (*Empty, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCProvisionerDaemonDescription struct{}
 
-func (DRPCProvisionerDaemonDescription) NumMethods() int { return 5 }
+func (DRPCProvisionerDaemonDescription) NumMethods() int { return 6 }
 
 func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -181,6 +196,15 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 						in1.(*CompletedJob),
 					)
 			}, DRPCProvisionerDaemonServer.CompleteJob, true
+	case 5:
+		return "/provisionerd.ProvisionerDaemon/Heartbeat", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCProvisionerDaemonServer).
+					Heartbeat(
+						ctx,
+						in1.(*HeartbeatRequest),
+					)
+			}, DRPCProvisionerDaemonServer.Heartbeat, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -269,3 +293,19 @@ func (x *drpcProvisionerDaemon_CompleteJobStream) SendAndClose(m *Empty) error {
 	}
 	return x.CloseSend()
 }
+
+type DRPCProvisionerDaemon_HeartbeatStream interface {
+	drpc.Stream
+	SendAndClose(*Empty) error
+}
+
+type drpcProvisionerDaemon_HeartbeatStream struct {
+	drpc.Stream
+}
+
+func (x *drpcProvisionerDaemon_HeartbeatStream) SendAndClose(m *Empty) error {
+	if err := x.MsgSend(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"reflect"
+	"runtime"
 	"sync"
 	"time"
 
@@ -21,6 +23,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/buildinfo"
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/cryptorand"
 	"github.com/coder/coder/provisionerd/proto"
@@ -54,7 +57,10 @@ type Options struct {
 	JobPollInterval     time.Duration
 	JobPollJitter       time.Duration
 	JobPollDebounce     time.Duration
-	Provisioners        Provisioners
+	// HeartbeatInterval is how often the daemon reports its status
+	// to coderd.
+	HeartbeatInterval time.Duration
+	Provisioners      Provisioners
 	// WorkDirectory must not be used by multiple processes at once.
 	WorkDirectory string
 }
@@ -76,6 +82,9 @@ func New(clientDialer Dialer, opts *Options) *Server {
 	if opts.ForceCancelInterval == 0 {
 		opts.ForceCancelInterval = 10 * time.Minute
 	}
+	if opts.HeartbeatInterval == 0 {
+		opts.HeartbeatInterval = 15 * time.Second
+	}
 	if opts.LogBufferInterval == 0 {
 		opts.LogBufferInterval = 50 * time.Millisecond
 	}
@@ -248,6 +257,55 @@ func (p *Server) connect(ctx context.Context) {
 			}
 		}
 	}()
+
+	go func() {
+		if p.isClosed() {
+			return
+		}
+		ticker := time.NewTicker(p.opts.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			client, ok := p.client()
+			if !ok {
+				return
+			}
+			p.heartbeat(ctx, client)
+			select {
+			case <-p.closeContext.Done():
+				return
+			case <-client.DRPCConn().Closed():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// heartbeat reports the daemon's host information and current job to coderd.
+func (p *Server) heartbeat(ctx context.Context, client proto.DRPCProvisionerDaemonClient) {
+	p.mutex.Lock()
+	var currentJobID string
+	if p.isRunningJob() {
+		currentJobID = p.activeJob.JobID()
+	}
+	p.mutex.Unlock()
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		p.opts.Logger.Debug(ctx, "get hostname", slog.Error(err))
+	}
+	_, err = client.Heartbeat(ctx, &proto.HeartbeatRequest{
+		Version:         buildinfo.Version(),
+		Hostname:        hostname,
+		OperatingSystem: runtime.GOOS,
+		Architecture:    runtime.GOARCH,
+		// Each daemon runs a single job at a time.
+		Concurrency:  1,
+		CurrentJobId: currentJobID,
+	})
+	if err != nil && !retryable(err) {
+		p.opts.Logger.Warn(ctx, "send heartbeat", slog.Error(err))
+	}
 }
 
 func (p *Server) nextInterval() time.Duration {
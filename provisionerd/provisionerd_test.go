@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -107,6 +108,42 @@ func TestProvisionerd(t *testing.T) {
 		require.NoError(t, closer.Close())
 	})
 
+	t.Run("Heartbeat", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		heartbeats := make(chan *proto.HeartbeatRequest, 1)
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					return &proto.AcquiredJob{}, nil
+				},
+				heartbeat: func(ctx context.Context, req *proto.HeartbeatRequest) (*proto.Empty, error) {
+					select {
+					case heartbeats <- req:
+					default:
+					}
+					return &proto.Empty{}, nil
+				},
+				updateJob: noopUpdateJob,
+			}), nil
+		}, provisionerd.Provisioners{})
+
+		var req *proto.HeartbeatRequest
+		select {
+		case req = <-heartbeats:
+		case <-time.After(testutil.WaitShort):
+			t.Fatal("timed out waiting for heartbeat")
+		}
+		require.Equal(t, runtime.GOOS, req.OperatingSystem)
+		require.Equal(t, runtime.GOARCH, req.Architecture)
+		require.EqualValues(t, 1, req.Concurrency)
+		require.Empty(t, req.CurrentJobId)
+		require.NoError(t, closer.Close())
+	})
+
 	t.Run("CloseCancelsJob", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
@@ -1233,6 +1270,7 @@ type provisionerDaemonTestServer struct {
 	updateJob   func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error)
 	failJob     func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error)
 	completeJob func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error)
+	heartbeat   func(ctx context.Context, req *proto.HeartbeatRequest) (*proto.Empty, error)
 }
 
 func (p *provisionerDaemonTestServer) AcquireJob(ctx context.Context, empty *proto.Empty) (*proto.AcquiredJob, error) {
@@ -1259,3 +1297,10 @@ func (p *provisionerDaemonTestServer) FailJob(ctx context.Context, job *proto.Fa
 func (p *provisionerDaemonTestServer) CompleteJob(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
 	return p.completeJob(ctx, job)
 }
+
+func (p *provisionerDaemonTestServer) Heartbeat(ctx context.Context, req *proto.HeartbeatRequest) (*proto.Empty, error) {
+	if p.heartbeat == nil {
+		return &proto.Empty{}, nil
+	}
+	return p.heartbeat(ctx, req)
+}
@@ -241,6 +241,11 @@ func (r *Runner) Cancel() {
 	r.cancel()
 }
 
+// JobID returns the ID of the job being run.
+func (r *Runner) JobID() string {
+	return r.job.JobId
+}
+
 func (r *Runner) Done() <-chan struct{} {
 	return r.done
 }
@@ -640,6 +645,8 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 				StopResources:    stopProvision.Resources,
 				RichParameters:   startProvision.Parameters,
 				GitAuthProviders: startProvision.GitAuthProviders,
+				StartPlanJson:    startProvision.PlanJSON,
+				StopPlanJson:     stopProvision.PlanJSON,
 			},
 		},
 	}, nil
@@ -694,6 +701,7 @@ type templateImportProvision struct {
 	Resources        []*sdkproto.Resource
 	Parameters       []*sdkproto.RichParameter
 	GitAuthProviders []string
+	PlanJSON         []byte
 }
 
 // Performs a dry-run provision when importing a template.
@@ -769,6 +777,8 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(ctx context.Contex
 				CreatedAt: time.Now().UnixMilli(),
 				Output:    msgType.Log.Output,
 				Stage:     stage,
+
+				ResourceProgress: msgType.Log.ResourceProgress,
 			})
 		case *sdkproto.Provision_Response_Complete:
 			if msgType.Complete.Error != "" {
@@ -789,6 +799,7 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(ctx context.Contex
 				Resources:        msgType.Complete.Resources,
 				Parameters:       msgType.Complete.Parameters,
 				GitAuthProviders: msgType.Complete.GitAuthProviders,
+				PlanJSON:         msgType.Complete.PlanJson,
 			}, nil
 		default:
 			return nil, xerrors.Errorf("invalid message type %q received from provisioner",
@@ -897,6 +908,8 @@ func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto
 				CreatedAt: time.Now().UnixMilli(),
 				Output:    msgType.Log.Output,
 				Stage:     stage,
+
+				ResourceProgress: msgType.Log.ResourceProgress,
 			})
 		case *sdkproto.Provision_Response_Complete:
 			if msgType.Complete.Error != "" {
@@ -1054,6 +1067,7 @@ func (r *Runner) runWorkspaceBuild(ctx context.Context) (*proto.CompletedJob, *p
 			WorkspaceBuild: &proto.CompletedJob_WorkspaceBuild{
 				State:     completedApply.GetState(),
 				Resources: completedApply.GetResources(),
+				Timings:   append(completedPlan.GetTimings(), completedApply.GetTimings()...),
 			},
 		},
 	}, nil
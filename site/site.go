@@ -42,6 +42,11 @@ var (
 	errorHTML string
 
 	errorTemplate *htmltemplate.Template
+
+	//go:embed static/oauth2allow.html
+	oauthAllowHTML string
+
+	oauthAllowTemplate *htmltemplate.Template
 )
 
 func init() {
@@ -50,6 +55,10 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	oauthAllowTemplate, err = htmltemplate.New("oauth2allow").Parse(oauthAllowHTML)
+	if err != nil {
+		panic(err)
+	}
 }
 
 // Handler returns an HTTP handler for serving the static site.
@@ -743,6 +752,34 @@ func RenderStaticErrorPage(rw http.ResponseWriter, r *http.Request, data ErrorPa
 	}
 }
 
+// RenderOAuthAllowData contains the variables that are found in
+// site/static/oauth2allow.html.
+type RenderOAuthAllowData struct {
+	AppName  string
+	AppIcon  string
+	Username string
+	Scope    string
+	// CancelURI is a registered redirect URI, which native apps may register
+	// with custom schemes that html/template would otherwise reject.
+	CancelURI htmltemplate.URL
+}
+
+// RenderOAuthAllowPage renders the page users authorize OAuth2 apps on. The
+// form posts back to the URL of the page, query parameters included.
+func RenderOAuthAllowPage(rw http.ResponseWriter, r *http.Request, data RenderOAuthAllowData) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// The allow button must not be clickable from pages framing this one.
+	rw.Header().Set("X-Frame-Options", "DENY")
+
+	err := oauthAllowTemplate.Execute(rw, data)
+	if err != nil {
+		httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to render oauth page: " + err.Error(),
+		})
+		return
+	}
+}
+
 type binHashCache struct {
 	binFS http.FileSystem
 
@@ -12,6 +12,7 @@ export interface APIKey {
   readonly scope: APIKeyScope
   readonly token_name: string
   readonly lifetime_seconds: number
+  readonly scopes?: string[]
 }
 
 // From codersdk/apikey.go
@@ -107,6 +108,8 @@ export interface AuthMethods {
   readonly password: AuthMethod
   readonly github: AuthMethod
   readonly oidc: OIDCAuthMethod
+  readonly saml: SAMLAuthMethod
+  readonly ldap: AuthMethod
 }
 
 // From codersdk/authorization.go
@@ -144,6 +147,11 @@ export interface ComputedParameter extends Parameter {
   readonly default_source_value: boolean
 }
 
+// From codersdk/twofactor.go
+export interface ConfirmTOTPRequest {
+  readonly code: string
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string
@@ -179,6 +187,12 @@ export interface CreateParameterRequest {
   readonly destination_scheme: ParameterDestinationScheme
 }
 
+// From codersdk/serviceaccounts.go
+export interface CreateServiceAccountRequest {
+  readonly owner_user_id?: string
+  readonly owner_group_id?: string
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
   readonly name: string
@@ -228,6 +242,7 @@ export interface CreateTokenRequest {
   // This is likely an enum in an external package ("time.Duration")
   readonly lifetime: number
   readonly scope: APIKeyScope
+  readonly scopes?: string[]
   readonly token_name: string
 }
 
@@ -237,6 +252,14 @@ export interface CreateUserRequest {
   readonly username: string
   readonly password: string
   readonly organization_id: string
+  readonly service_account?: CreateServiceAccountRequest
+}
+
+// From codersdk/twofactor.go
+export interface CreateWebAuthnCredentialRequest {
+  readonly name: string
+  readonly client_data_json: string
+  readonly attestation_object: string
 }
 
 // From codersdk/workspaces.go
@@ -277,6 +300,26 @@ export interface DERP {
 export interface DERPConfig {
   readonly url: string
   readonly path: string
+  readonly health_check_interval: number
+}
+
+// From codersdk/derphealth.go
+export interface DERPHealthReport {
+  readonly healthy: boolean
+  readonly checked_at: string
+  readonly regions: DERPRegionReport[]
+}
+
+// From codersdk/derphealth.go
+export interface DERPNodeReport {
+  readonly name: string
+  readonly host_name: string
+  readonly healthy: boolean
+  readonly latency_ms: number
+  readonly error?: string
+  readonly stun_enabled: boolean
+  readonly stun_reachable: boolean
+  readonly stun_error?: string
 }
 
 // From codersdk/workspaceagents.go
@@ -285,6 +328,17 @@ export interface DERPRegion {
   readonly latency_ms: number
 }
 
+// From codersdk/derphealth.go
+export interface DERPRegionReport {
+  readonly region_id: number
+  readonly region_code: string
+  readonly region_name: string
+  readonly embedded_relay: boolean
+  readonly healthy: boolean
+  readonly latency_ms: number
+  readonly nodes: DERPNodeReport[]
+}
+
 // From codersdk/deployment.go
 export interface DERPServerConfig {
   readonly enable: boolean
@@ -302,6 +356,12 @@ export interface DangerousConfig {
   readonly allow_path_app_site_owner_access: boolean
 }
 
+// From codersdk/workspaceportshares.go
+export interface DeleteWorkspacePortShareRequest {
+  readonly agent_name: string
+  readonly port: number
+}
+
 // From codersdk/deployment.go
 export interface DeploymentDAUsResponse {
   readonly entries: DAUEntry[]
@@ -336,6 +396,8 @@ export interface DeploymentValues {
   readonly pg_connection_url?: string
   readonly oauth2?: OAuth2Config
   readonly oidc?: OIDCConfig
+  readonly saml?: SAMLConfig
+  readonly ldap?: LDAPConfig
   readonly telemetry?: TelemetryConfig
   readonly tls?: TLSConfig
   readonly trace?: TraceConfig
@@ -349,6 +411,7 @@ export interface DeploymentValues {
   readonly agent_fallback_troubleshooting_url?: string
   readonly audit_logging?: boolean
   readonly browser_only?: boolean
+  readonly workspace_peering?: boolean
   readonly scim_api_key?: string
   readonly provisioner?: ProvisionerConfig
   readonly rate_limit?: RateLimitConfig
@@ -360,9 +423,11 @@ export interface DeploymentValues {
   readonly logging?: LoggingConfig
   readonly dangerous?: DangerousConfig
   readonly disable_path_apps?: boolean
+  readonly workspace_app_access_log_retention?: number
   readonly max_session_expiry?: number
   readonly disable_session_expiry_refresh?: boolean
   readonly disable_password_auth?: boolean
+  readonly two_factor_required?: boolean
   readonly support?: SupportConfig
   // Named type "github.com/coder/coder/cli/clibase.Struct[[]github.com/coder/coder/codersdk.GitAuthConfig]" unknown, using "any"
   // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
@@ -419,6 +484,17 @@ export interface GitAuthConfig {
   readonly regex: string
   readonly no_refresh: boolean
   readonly scopes: string[]
+  readonly device_flow: boolean
+  readonly device_code_url: string
+}
+
+// From codersdk/workspaceagents.go
+export interface GitAuthDevice {
+  readonly device_code: string
+  readonly user_code: string
+  readonly verification_uri: string
+  readonly expires_in: number
+  readonly interval: number
 }
 
 // From codersdk/gitsshkey.go
@@ -446,6 +522,25 @@ export interface Healthcheck {
   readonly threshold: number
 }
 
+// From codersdk/deployment.go
+export interface LDAPConfig {
+  readonly url: string
+  readonly bind_dn: string
+  readonly bind_password: string
+  readonly start_tls: boolean
+  readonly ca_file: string
+  readonly user_base_dn: string
+  readonly user_filter: string
+  readonly username_field: string
+  readonly email_field: string
+  readonly groups_field: string
+  // Named type "github.com/coder/coder/cli/clibase.Struct[map[string]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly group_mapping: any
+  readonly allow_signups: boolean
+  readonly sync_interval: number
+}
+
 // From codersdk/licenses.go
 export interface License {
   readonly id: number
@@ -474,6 +569,9 @@ export interface LoggingConfig {
 export interface LoginWithPasswordRequest {
   readonly email: string
   readonly password: string
+  readonly totp_code?: string
+  readonly recovery_code?: string
+  readonly webauthn?: WebAuthnAssertion
 }
 
 // From codersdk/users.go
@@ -481,6 +579,12 @@ export interface LoginWithPasswordResponse {
   readonly session_token: string
 }
 
+// From codersdk/oauth2provider.go
+export interface OAuth2AppEndpoints {
+  readonly authorization: string
+  readonly token: string
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig
@@ -499,6 +603,47 @@ export interface OAuth2GithubConfig {
   readonly enterprise_base_url: string
 }
 
+// From codersdk/oauth2provider.go
+export interface OAuth2ProviderApp {
+  readonly id: string
+  readonly name: string
+  readonly icon: string
+  readonly redirect_uris: string[]
+  readonly endpoints: OAuth2AppEndpoints
+}
+
+// From codersdk/oauth2provider.go
+export interface OAuth2ProviderAppFilter {
+  readonly user_id?: string
+}
+
+// From codersdk/oauth2provider.go
+export interface OAuth2ProviderAppSecret {
+  readonly id: string
+  readonly last_used_at?: string
+  readonly client_secret_truncated: string
+}
+
+// From codersdk/oauth2provider.go
+export interface OAuth2ProviderAppSecretFull {
+  readonly id: string
+  readonly client_secret_full: string
+}
+
+// From codersdk/oauth2provider.go
+export interface OAuth2ProviderError {
+  readonly error: string
+  readonly error_description?: string
+}
+
+// From codersdk/oauth2provider.go
+export interface OAuth2ProviderToken {
+  readonly access_token: string
+  readonly token_type: string
+  readonly refresh_token: string
+  readonly expires_in: number
+}
+
 // From codersdk/users.go
 export interface OIDCAuthMethod extends AuthMethod {
   readonly signInText: string
@@ -525,6 +670,11 @@ export interface OIDCConfig {
   // Named type "github.com/coder/coder/cli/clibase.Struct[map[string]string]" unknown, using "any"
   // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
   readonly group_mapping: any
+  readonly user_role_field: string
+  // Named type "github.com/coder/coder/cli/clibase.Struct[map[string][]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly user_role_mapping: any
+  readonly refresh_interval: number
   readonly sign_in_text: string
   readonly icon_url: string
 }
@@ -600,6 +750,13 @@ export interface PatchTemplateVersionRequest {
   readonly name: string
 }
 
+// From codersdk/oauth2provider.go
+export interface PostOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly icon: string
+  readonly redirect_uris: string[]
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean
@@ -622,6 +779,8 @@ export interface ProvisionerConfig {
   readonly daemon_poll_interval: number
   readonly daemon_poll_jitter: number
   readonly force_cancel_interval: number
+  readonly import_policy_files: string[]
+  readonly unmatched_job_timeout: number
 }
 
 // From codersdk/provisionerdaemons.go
@@ -629,9 +788,17 @@ export interface ProvisionerDaemon {
   readonly id: string
   readonly created_at: string
   readonly updated_at?: string
+  readonly last_seen_at?: string
   readonly name: string
   readonly provisioners: ProvisionerType[]
   readonly tags: Record<string, string>
+  readonly status: ProvisionerDaemonStatus
+  readonly version: string
+  readonly hostname: string
+  readonly operating_system: string
+  readonly architecture: string
+  readonly concurrency: number
+  readonly current_job_id?: string
 }
 
 // From codersdk/provisionerdaemons.go
@@ -647,6 +814,7 @@ export interface ProvisionerJob {
   readonly worker_id?: string
   readonly file_id: string
   readonly tags: Record<string, string>
+  readonly has_matching_daemon: boolean
 }
 
 // From codersdk/provisionerdaemons.go
@@ -657,6 +825,15 @@ export interface ProvisionerJobLog {
   readonly log_level: LogLevel
   readonly stage: string
   readonly output: string
+  readonly resource_progress?: ProvisionerJobResourceProgress
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobResourceProgress {
+  readonly address: string
+  readonly action: string
+  readonly status: ProvisionerJobResourceStatus
+  readonly elapsed_seconds: number
 }
 
 // From codersdk/workspaces.go
@@ -664,10 +841,21 @@ export interface PutExtendWorkspaceRequest {
   readonly deadline: string
 }
 
+// From codersdk/oauth2provider.go
+export interface PutOAuth2ProviderAppRequest {
+  readonly name: string
+  readonly icon: string
+  readonly redirect_uris: string[]
+}
+
 // From codersdk/deployment.go
 export interface RateLimitConfig {
   readonly disable_all: boolean
   readonly api: number
+  readonly shared_app: number
+  readonly shared_workspace: number
+  readonly shared_app_bandwidth: number
+  readonly shared_workspace_bandwidth: number
 }
 
 // From codersdk/replicas.go
@@ -694,6 +882,29 @@ export interface Role {
   readonly display_name: string
 }
 
+// From codersdk/users.go
+export interface SAMLAuthMethod extends AuthMethod {
+  readonly signInText: string
+  readonly iconUrl: string
+}
+
+// From codersdk/deployment.go
+export interface SAMLConfig {
+  readonly allow_signups: boolean
+  readonly entity_id: string
+  readonly idp_metadata_url: string
+  // This is likely an enum in an external package ("github.com/coder/coder/cli/clibase.StringArray")
+  readonly email_domain: string[]
+  readonly username_field: string
+  readonly email_field: string
+  readonly groups_field: string
+  // Named type "github.com/coder/coder/cli/clibase.Struct[map[string]string]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly group_mapping: any
+  readonly sign_in_text: string
+  readonly icon_url: string
+}
+
 // From codersdk/deployment.go
 export interface SSHConfig {
   readonly DeploymentName: string
@@ -715,6 +926,14 @@ export interface ServerSentEvent {
   readonly data: any
 }
 
+// From codersdk/serviceaccounts.go
+export interface ServiceAccount {
+  readonly user_id: string
+  readonly owner_user_id?: string
+  readonly owner_group_id?: string
+  readonly created_at: string
+}
+
 // From codersdk/deployment.go
 export interface ServiceBannerConfig {
   readonly enabled: boolean
@@ -760,6 +979,17 @@ export interface TLSConfig {
   readonly client_key_file: string
 }
 
+// From codersdk/twofactor.go
+export interface TOTPKey {
+  readonly secret: string
+  readonly url: string
+}
+
+// From codersdk/twofactor.go
+export interface TOTPRecoveryCodes {
+  readonly recovery_codes: string[]
+}
+
 // From codersdk/deployment.go
 export interface TelemetryConfig {
   readonly enable: boolean
@@ -779,6 +1009,7 @@ export interface Template {
   readonly active_version_id: string
   readonly active_user_count: number
   readonly build_time_stats: TemplateBuildTimeStats
+  readonly build_stage_stats: TemplateBuildStageStats
   readonly description: string
   readonly icon: string
   readonly default_ttl_ms: number
@@ -794,6 +1025,34 @@ export interface TemplateACL {
   readonly group: TemplateGroup[]
 }
 
+// From codersdk/templateapplimits.go
+export interface TemplateAppLimits {
+  readonly template_id: string
+  readonly app_requests_per_minute: number
+  readonly app_bandwidth_bytes_per_second: number
+  readonly workspace_requests_per_minute: number
+  readonly workspace_bandwidth_bytes_per_second: number
+  readonly updated_at: string
+}
+
+// From codersdk/templateapprouting.go
+export interface TemplateAppRouting {
+  readonly template_id: string
+  readonly app_slug: string
+  readonly strip_prefix: boolean
+  readonly base_path_header: string
+  readonly headers: Record<string, string>
+  readonly allowed_methods: string[]
+  readonly websocket_subprotocols: string[]
+  readonly updated_at: string
+}
+
+// From codersdk/templates.go
+export type TemplateBuildStageStats = Record<
+  WorkspaceBuildStage,
+  TransitionStats
+>
+
 // From codersdk/templates.go
 export type TemplateBuildTimeStats = Record<
   WorkspaceTransition,
@@ -914,6 +1173,14 @@ export interface TransitionStats {
   readonly P95?: number
 }
 
+// From codersdk/twofactor.go
+export interface TwoFactor {
+  readonly totp: boolean
+  readonly recovery_codes_remaining: number
+  readonly webauthn_credentials: WebAuthnCredential[]
+  readonly required: boolean
+}
+
 // From codersdk/templates.go
 export interface UpdateActiveTemplateVersion {
   readonly id: string
@@ -943,6 +1210,14 @@ export interface UpdateTemplateACL {
   readonly group_perms?: Record<string, TemplateRole>
 }
 
+// From codersdk/templateapplimits.go
+export interface UpdateTemplateAppLimitsRequest {
+  readonly app_requests_per_minute?: number
+  readonly app_bandwidth_bytes_per_second?: number
+  readonly workspace_requests_per_minute?: number
+  readonly workspace_bandwidth_bytes_per_second?: number
+}
+
 // From codersdk/templates.go
 export interface UpdateTemplateMeta {
   readonly name?: string
@@ -970,6 +1245,11 @@ export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspacePeeringRequest {
+  readonly enabled: boolean
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceRequest {
   readonly name?: string
@@ -985,6 +1265,23 @@ export interface UploadResponse {
   readonly hash: string
 }
 
+// From codersdk/templateapprouting.go
+export interface UpsertTemplateAppRoutingRequest {
+  readonly strip_prefix?: boolean
+  readonly base_path_header?: string
+  readonly headers?: Record<string, string>
+  readonly allowed_methods?: string[]
+  readonly websocket_subprotocols?: string[]
+}
+
+// From codersdk/workspaceportshares.go
+export interface UpsertWorkspacePortShareRequest {
+  readonly agent_name: string
+  readonly port: number
+  readonly share_level: WorkspaceAppSharingLevel
+  readonly expires_at?: string
+}
+
 // From codersdk/users.go
 export interface User {
   readonly id: string
@@ -996,6 +1293,7 @@ export interface User {
   readonly organization_ids: string[]
   readonly roles: Role[]
   readonly avatar_url: string
+  readonly service_account?: boolean
 }
 
 // From codersdk/users.go
@@ -1021,6 +1319,42 @@ export interface VariableValue {
   readonly value: string
 }
 
+// From codersdk/twofactor.go
+export interface WebAuthnAssertion {
+  readonly credential_id: string
+  readonly client_data_json: string
+  readonly authenticator_data: string
+  readonly signature: string
+}
+
+// From codersdk/twofactor.go
+export interface WebAuthnCreationOptions {
+  readonly challenge: string
+  readonly rp_id: string
+  readonly rp_name: string
+  readonly user_id: string
+  readonly username: string
+  readonly algorithms: number[]
+  readonly exclude_credentials: string[]
+  readonly timeout_ms: number
+}
+
+// From codersdk/twofactor.go
+export interface WebAuthnCredential {
+  readonly id: string
+  readonly name: string
+  readonly created_at: string
+  readonly last_used_at: string
+}
+
+// From codersdk/twofactor.go
+export interface WebAuthnRequestOptions {
+  readonly challenge: string
+  readonly rp_id: string
+  readonly allow_credentials: string[]
+  readonly timeout_ms: number
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string
@@ -1040,6 +1374,7 @@ export interface Workspace {
   readonly autostart_schedule?: string
   readonly ttl_ms?: number
   readonly last_used_at: string
+  readonly peering_enabled: boolean
 }
 
 // From codersdk/workspaceagents.go
@@ -1131,6 +1466,30 @@ export interface WorkspaceApp {
   readonly health: WorkspaceAppHealth
 }
 
+// From codersdk/workspaceappaccesslogs.go
+export interface WorkspaceAppAccessLog {
+  readonly id: string
+  readonly created_at: string
+  readonly user_id?: string
+  readonly username?: string
+  readonly workspace_id: string
+  readonly agent_id: string
+  readonly app_slug_or_port: string
+  readonly access_method: string
+  readonly sharing_level: WorkspaceAppSharingLevel
+  readonly ip: string
+  readonly method: string
+  readonly path: string
+  readonly status_code: number
+  readonly bytes_written: number
+  readonly duration_ms: number
+}
+
+// From codersdk/workspaceappaccesslogs.go
+export interface WorkspaceAppAccessLogsRequest extends Pagination {
+  readonly app?: string
+}
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceBuild {
   readonly id: string
@@ -1153,6 +1512,7 @@ export interface WorkspaceBuild {
   readonly max_deadline?: string
   readonly status: WorkspaceStatus
   readonly daily_cost: number
+  readonly timings: WorkspaceBuildTiming[]
 }
 
 // From codersdk/workspacebuilds.go
@@ -1161,6 +1521,14 @@ export interface WorkspaceBuildParameter {
   readonly value: string
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildTiming {
+  readonly stage: WorkspaceBuildStage
+  readonly started_at: string
+  readonly ended_at?: string
+  readonly duration_ms: number
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceBuildsRequest extends Pagination {
   readonly WorkspaceID: string
@@ -1195,6 +1563,16 @@ export interface WorkspaceOptions {
   readonly include_deleted?: boolean
 }
 
+// From codersdk/workspaceportshares.go
+export interface WorkspacePortShare {
+  readonly workspace_id: string
+  readonly agent_name: string
+  readonly port: number
+  readonly share_level: WorkspaceAppSharingLevel
+  readonly created_at: string
+  readonly expires_at?: string
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceQuota {
   readonly credits_consumed: number
@@ -1235,8 +1613,12 @@ export interface WorkspacesResponse {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect"
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"]
+export type APIKeyScope = "all" | "application_connect" | "custom"
+export const APIKeyScopes: APIKeyScope[] = [
+  "all",
+  "application_connect",
+  "custom",
+]
 
 // From codersdk/audit.go
 export type AuditAction =
@@ -1287,6 +1669,7 @@ export type FeatureName =
   | "high_availability"
   | "multiple_git_auth"
   | "scim"
+  | "service_accounts"
   | "template_rbac"
   | "user_limit"
 export const FeatureNames: FeatureName[] = [
@@ -1298,26 +1681,39 @@ export const FeatureNames: FeatureName[] = [
   "high_availability",
   "multiple_git_auth",
   "scim",
+  "service_accounts",
   "template_rbac",
   "user_limit",
 ]
 
 // From codersdk/workspaceagents.go
-export type GitProvider = "azure-devops" | "bitbucket" | "github" | "gitlab"
+export type GitProvider =
+  | "azure-devops"
+  | "bitbucket"
+  | "gitea"
+  | "github"
+  | "gitlab"
+  | "oauth2"
 export const GitProviders: GitProvider[] = [
   "azure-devops",
   "bitbucket",
+  "gitea",
   "github",
   "gitlab",
+  "oauth2",
 ]
 
 // From codersdk/provisionerdaemons.go
 export type JobErrorCode =
   | "MISSING_TEMPLATE_PARAMETER"
+  | "NO_MATCHING_PROVISIONER_DAEMON"
   | "REQUIRED_TEMPLATE_VARIABLES"
+  | "TEMPLATE_IMPORT_POLICY_VIOLATION"
 export const JobErrorCodes: JobErrorCode[] = [
   "MISSING_TEMPLATE_PARAMETER",
+  "NO_MATCHING_PROVISIONER_DAEMON",
   "REQUIRED_TEMPLATE_VARIABLES",
+  "TEMPLATE_IMPORT_POLICY_VIOLATION",
 ]
 
 // From codersdk/provisionerdaemons.go
@@ -1329,8 +1725,36 @@ export type LogSource = "provisioner" | "provisioner_daemon"
 export const LogSources: LogSource[] = ["provisioner", "provisioner_daemon"]
 
 // From codersdk/apikey.go
-export type LoginType = "github" | "oidc" | "password" | "token"
-export const LoginTypes: LoginType[] = ["github", "oidc", "password", "token"]
+export type LoginType =
+  | "github"
+  | "ldap"
+  | "none"
+  | "oidc"
+  | "password"
+  | "saml"
+  | "token"
+export const LoginTypes: LoginType[] = [
+  "github",
+  "ldap",
+  "none",
+  "oidc",
+  "password",
+  "saml",
+  "token",
+]
+
+// From codersdk/oauth2provider.go
+export type OAuth2ProviderGrantType = "authorization_code" | "refresh_token"
+export const OAuth2ProviderGrantTypes: OAuth2ProviderGrantType[] = [
+  "authorization_code",
+  "refresh_token",
+]
+
+// From codersdk/oauth2provider.go
+export type OAuth2ProviderResponseType = "code"
+export const OAuth2ProviderResponseTypes: OAuth2ProviderResponseType[] = [
+  "code",
+]
 
 // From codersdk/parameters.go
 export type ParameterDestinationScheme =
@@ -1359,6 +1783,28 @@ export const ParameterSourceSchemes: ParameterSourceScheme[] = ["data", "none"]
 export type ParameterTypeSystem = "hcl" | "none"
 export const ParameterTypeSystems: ParameterTypeSystem[] = ["hcl", "none"]
 
+// From codersdk/provisionerdaemons.go
+export type ProvisionerDaemonStatus = "busy" | "offline" | "online" | "unknown"
+export const ProvisionerDaemonStatuses: ProvisionerDaemonStatus[] = [
+  "busy",
+  "offline",
+  "online",
+  "unknown",
+]
+
+// From codersdk/provisionerdaemons.go
+export type ProvisionerJobResourceStatus =
+  | "complete"
+  | "errored"
+  | "progress"
+  | "started"
+export const ProvisionerJobResourceStatuses: ProvisionerJobResourceStatus[] = [
+  "complete",
+  "errored",
+  "progress",
+  "started",
+]
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1491,6 +1937,23 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ]
 
+// From codersdk/workspacebuilds.go
+export type WorkspaceBuildStage =
+  | "agent_connect"
+  | "apply"
+  | "init"
+  | "plan"
+  | "queued"
+  | "startup_script"
+export const WorkspaceBuildStages: WorkspaceBuildStage[] = [
+  "agent_connect",
+  "apply",
+  "init",
+  "plan",
+  "queued",
+  "startup_script",
+]
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
@@ -116,6 +116,11 @@ export const AuditLogRow: React.FC<AuditLogRowProps> = ({
                       <>{t("table.logRow.deletedLabel")}</>
                     </span>
                   )}
+                  {auditLog.user?.service_account && (
+                    <span className={styles.deletedLabel}>
+                      <>{t("table.logRow.serviceAccountLabel")}</>
+                    </span>
+                  )}
                   <span className={styles.auditLogTime}>
                     {new Date(auditLog.time).toLocaleTimeString()}
                   </span>
@@ -7,6 +7,7 @@ import GitHub from "@material-ui/icons/GitHub"
 import * as TypesGen from "api/typesGenerated"
 import { AzureDevOpsIcon } from "components/Icons/AzureDevOpsIcon"
 import { BitbucketIcon } from "components/Icons/BitbucketIcon"
+import { GitIcon } from "components/Icons/GitIcon"
 import { GitlabIcon } from "components/Icons/GitlabIcon"
 import { Typography } from "components/Typography/Typography"
 import { FC } from "react"
@@ -39,6 +40,10 @@ export const GitAuth: FC<GitAuthProps> = ({
       prettyName = "Bitbucket"
       Icon = BitbucketIcon
       break
+    case "gitea":
+      prettyName = "Gitea"
+      Icon = GitIcon
+      break
     case "github":
       prettyName = "GitHub"
       Icon = GitHub
@@ -47,6 +52,10 @@ export const GitAuth: FC<GitAuthProps> = ({
       prettyName = "GitLab"
       Icon = GitlabIcon
       break
+    case "oauth2":
+      prettyName = "OAuth2"
+      Icon = GitIcon
+      break
     default:
       throw new Error("invalid git provider: " + type)
   }
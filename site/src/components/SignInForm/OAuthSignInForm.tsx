@@ -77,6 +77,35 @@ export const OAuthSignInForm: FC<OAuthSignInFormProps> = ({
           </Button>
         </Link>
       )}
+
+      {authMethods?.saml.enabled && (
+        <Link
+          underline="none"
+          href={`/api/v2/users/saml/login?redirect=${encodeURIComponent(
+            redirectTo,
+          )}`}
+        >
+          <Button
+            startIcon={
+              authMethods.saml.iconUrl ? (
+                <img
+                  alt="SAML icon"
+                  src={authMethods.saml.iconUrl}
+                  className={styles.buttonIcon}
+                />
+              ) : (
+                <KeyIcon className={styles.buttonIcon} />
+              )
+            }
+            disabled={isSigningIn}
+            fullWidth
+            type="submit"
+            variant="outlined"
+          >
+            {authMethods.saml.signInText || Language.samlSignIn}
+          </Button>
+        </Link>
+      )}
     </Box>
   )
 }
@@ -31,6 +31,8 @@ SigningIn.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    saml: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 }
 
@@ -58,6 +60,8 @@ WithGithub.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    saml: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 }
 
@@ -68,6 +72,8 @@ WithOIDC.args = {
     password: { enabled: true },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    saml: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 }
 
@@ -78,6 +84,20 @@ WithOIDCWithoutPassword.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    saml: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
+  },
+}
+
+export const WithLDAPWithoutPassword = Template.bind({})
+WithLDAPWithoutPassword.args = {
+  ...SignedOut.args,
+  authMethods: {
+    password: { enabled: false },
+    github: { enabled: false },
+    oidc: { enabled: false, signInText: "", iconUrl: "" },
+    saml: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: true },
   },
 }
 
@@ -88,6 +108,8 @@ WithoutAny.args = {
     password: { enabled: false },
     github: { enabled: false },
     oidc: { enabled: false, signInText: "", iconUrl: "" },
+    saml: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 }
 
@@ -98,5 +120,7 @@ WithGithubAndOIDC.args = {
     password: { enabled: true },
     github: { enabled: true },
     oidc: { enabled: true, signInText: "", iconUrl: "" },
+    saml: { enabled: false, signInText: "", iconUrl: "" },
+    ldap: { enabled: false },
   },
 }
@@ -19,6 +19,7 @@ export const Language = {
   passwordSignIn: "Sign In",
   githubSignIn: "GitHub",
   oidcSignIn: "OpenID Connect",
+  samlSignIn: "SAML",
 }
 
 const useStyles = makeStyles((theme) => ({
@@ -83,9 +84,13 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
   initialTouched,
 }) => {
   const oAuthEnabled = Boolean(
-    authMethods?.github.enabled || authMethods?.oidc.enabled,
+    authMethods?.github.enabled ||
+      authMethods?.oidc.enabled ||
+      authMethods?.saml.enabled,
   )
-  const passwordEnabled = authMethods?.password.enabled ?? true
+  // LDAP users sign in with their directory password on the same form.
+  const passwordEnabled =
+    (authMethods?.password.enabled || authMethods?.ldap.enabled) ?? true
   // Hide password auth by default if any OAuth method is enabled
   const [showPasswordAuth, setShowPasswordAuth] = useState(!oAuthEnabled)
   const styles = useStyles()
@@ -66,6 +66,8 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      saml: { enabled: false, signInText: "", iconUrl: "" },
+      ldap: { enabled: false },
     }
 
     // Given
@@ -108,6 +110,8 @@ describe("LoginPage", () => {
       password: { enabled: true },
       github: { enabled: true },
       oidc: { enabled: true, signInText: "", iconUrl: "" },
+      saml: { enabled: false, signInText: "", iconUrl: "" },
+      ldap: { enabled: false },
     }
 
     // Given
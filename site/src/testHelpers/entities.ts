@@ -199,6 +199,12 @@ export const MockProvisioner: TypesGen.ProvisionerDaemon = {
   name: "Test Provisioner",
   provisioners: ["echo"],
   tags: {},
+  status: "online",
+  version: "v0.0.0",
+  hostname: "",
+  operating_system: "",
+  architecture: "",
+  concurrency: 1,
 }
 
 export const MockProvisionerJob: TypesGen.ProvisionerJob = {
@@ -208,6 +214,7 @@ export const MockProvisionerJob: TypesGen.ProvisionerJob = {
   file_id: MockOrganization.id,
   completed_at: "2022-05-17T17:39:01.382927298Z",
   tags: {},
+  has_matching_daemon: true,
 }
 
 export const MockFailedProvisionerJob: TypesGen.ProvisionerJob = {
@@ -289,6 +296,14 @@ export const MockTemplate: TypesGen.Template = {
       P95: 1500,
     },
   },
+  build_stage_stats: {
+    queued: {},
+    init: {},
+    plan: {},
+    apply: {},
+    agent_connect: {},
+    startup_script: {},
+  },
   description: "This is a test description.",
   default_ttl_ms: 24 * 60 * 60 * 1000,
   max_ttl_ms: 2 * 24 * 60 * 60 * 1000,
@@ -607,6 +622,7 @@ export const MockWorkspaceBuild: TypesGen.WorkspaceBuild = {
   resources: [MockWorkspaceResource],
   status: "running",
   daily_cost: 20,
+  timings: [],
 }
 
 export const MockFailedWorkspaceBuild = (
@@ -631,6 +647,7 @@ export const MockFailedWorkspaceBuild = (
   resources: [],
   status: "running",
   daily_cost: 20,
+  timings: [],
 })
 
 export const MockWorkspaceBuildStop: TypesGen.WorkspaceBuild = {
@@ -670,6 +687,7 @@ export const MockWorkspace: TypesGen.Workspace = {
   ttl_ms: 2 * 60 * 60 * 1000,
   latest_build: MockWorkspaceBuild,
   last_used_at: "2022-05-16T15:29:10.302441433Z",
+  peering_enabled: false,
 }
 
 export const MockStoppedWorkspace: TypesGen.Workspace = {
@@ -912,6 +930,8 @@ export const MockAuthMethods: TypesGen.AuthMethods = {
   password: { enabled: true },
   github: { enabled: false },
   oidc: { enabled: false, signInText: "", iconUrl: "" },
+  saml: { enabled: false, signInText: "", iconUrl: "" },
+  ldap: { enabled: false },
 }
 
 export const MockGitSSHKey: TypesGen.GitSSHKey = {
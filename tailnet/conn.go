@@ -16,6 +16,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/google/uuid"
 	"go4.org/netipx"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
 	"tailscale.com/hostinfo"
@@ -23,6 +24,7 @@ import (
 	"tailscale.com/net/connstats"
 	"tailscale.com/net/dns"
 	"tailscale.com/net/netns"
+	"tailscale.com/net/tsaddr"
 	"tailscale.com/net/tsdial"
 	"tailscale.com/net/tstun"
 	"tailscale.com/tailcfg"
@@ -59,6 +61,23 @@ type Options struct {
 	// If so, only DERPs can establish connections.
 	BlockEndpoints bool
 	Logger         slog.Logger
+
+	// TUNName is the name of a TUN device to create. If set, the tailnet
+	// address range is routed to the device so any process on the machine
+	// can reach peers. This requires root. All networking happens in
+	// userspace if it's empty.
+	TUNName string
+
+	// ListenPort is the UDP port Wireguard listens on. If ListenPortEnd is
+	// set, the first free port from ListenPort to ListenPortEnd is used.
+	// A random port is used if it's zero or no port of the range is free.
+	ListenPort    uint16
+	ListenPortEnd uint16
+	// StaticEndpoints are advertised to peers ahead of the endpoints found
+	// with STUN, e.g. the address of a load balancer or node port that
+	// forwards to ListenPort. This allows direct connections through
+	// networks that NAT traversal can't get through.
+	StaticEndpoints []netip.AddrPort
 }
 
 // NewConn constructs a new Wireguard server that will accept connections from the addresses provided.
@@ -134,10 +153,39 @@ func NewConn(options *Options) (conn *Conn, err error) {
 	dialer := &tsdial.Dialer{
 		Logf: Logger(options.Logger.Named("tsdial")),
 	}
-	wireguardEngine, err := wgengine.NewUserspaceEngine(Logger(options.Logger.Named("wgengine")), wgengine.Config{
+	engineConfig := wgengine.Config{
 		LinkMonitor: wireguardMonitor,
 		Dialer:      dialer,
-	})
+	}
+	// The engine rebinds magicsock to its configured port whenever peers
+	// change, so the port has to be chosen before the engine is created.
+	// magicsock falls back to a random port if the port can't be bound.
+	if options.ListenPort != 0 {
+		port, ok := freeListenPort(options.ListenPort, options.ListenPortEnd)
+		if !ok {
+			options.Logger.Warn(context.Background(), "no free udp port in range, using a random port",
+				slog.F("listen_port", options.ListenPort), slog.F("listen_port_end", options.ListenPortEnd))
+		}
+		engineConfig.ListenPort = port
+	}
+	routerConfig := &router.Config{
+		LocalAddrs: netMap.Addresses,
+	}
+	if options.TUNName != "" {
+		tun, tunName, err := tstun.New(Logger(options.Logger.Named("tun")), options.TUNName)
+		if err != nil {
+			return nil, xerrors.Errorf("create tun device %q: %w", options.TUNName, err)
+		}
+		osRouter, err := router.New(Logger(options.Logger.Named("router")), tun, wireguardMonitor)
+		if err != nil {
+			_ = tun.Close()
+			return nil, xerrors.Errorf("create router for tun device %q: %w", tunName, err)
+		}
+		engineConfig.Tun = tun
+		engineConfig.Router = osRouter
+		routerConfig.Routes = []netip.Prefix{tsaddr.TailscaleULARange()}
+	}
+	wireguardEngine, err := wgengine.NewUserspaceEngine(Logger(options.Logger.Named("wgengine")), engineConfig)
 	if err != nil {
 		return nil, xerrors.Errorf("create wgengine: %w", err)
 	}
@@ -181,6 +229,11 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		return netStack.DialContextTCP(ctx, dst)
 	}
 	netStack.ProcessLocalIPs = true
+	if engineConfig.ListenPort != 0 && magicConn.LocalPort() != engineConfig.ListenPort {
+		// Another process bound the port after it was checked.
+		options.Logger.Warn(context.Background(), "udp listen port was taken, using a random port",
+			slog.F("listen_port", engineConfig.ListenPort), slog.F("local_port", magicConn.LocalPort()))
+	}
 	wireguardEngine = wgengine.NewWatchdog(wireguardEngine)
 	wireguardEngine.SetDERPMap(options.DERPMap)
 	netMapCopy := *netMap
@@ -198,6 +251,7 @@ func NewConn(options *Options) (conn *Conn, err error) {
 	dialContext, dialCancel := context.WithCancel(context.Background())
 	server := &Conn{
 		blockEndpoints:           options.BlockEndpoints,
+		staticEndpoints:          options.StaticEndpoints,
 		dialContext:              dialContext,
 		dialCancel:               dialCancel,
 		closed:                   make(chan struct{}),
@@ -211,10 +265,8 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		netMap:                   netMap,
 		netStack:                 netStack,
 		wireguardMonitor:         wireguardMonitor,
-		wireguardRouter: &router.Config{
-			LocalAddrs: netMap.Addresses,
-		},
-		wireguardEngine: wireguardEngine,
+		wireguardRouter:          routerConfig,
+		wireguardEngine:          wireguardEngine,
 	}
 	defer func() {
 		if err != nil {
@@ -274,13 +326,36 @@ func NewConn(options *Options) (conn *Conn, err error) {
 	return server, nil
 }
 
+// freeListenPort returns the first port from start to end that UDP can be
+// bound on. Only start is tried if end is zero. Zero is returned if no port of
+// the range is free, which makes magicsock pick a random port.
+func freeListenPort(start, end uint16) (uint16, bool) {
+	if end < start {
+		end = start
+	}
+	for port := uint32(start); port <= uint32(end); port++ {
+		udp, err := net.ListenUDP("udp4", &net.UDPAddr{Port: int(port)})
+		if err != nil {
+			continue
+		}
+		_ = udp.Close()
+		return uint16(port), true
+	}
+	return 0, false
+}
+
 // IP generates a new IP with a static service prefix.
 func IP() netip.Addr {
+	return IPFromUUID(uuid.New())
+}
+
+// IPFromUUID generates the IP with a static service prefix for the given UUID.
+// The same UUID always maps to the same IP.
+func IPFromUUID(uid uuid.UUID) netip.Addr {
 	// This is Tailscale's ephemeral service prefix.
 	// This can be changed easily later-on, because
 	// all of our nodes are ephemeral.
 	// fd7a:115c:a1e0
-	uid := uuid.New()
 	uid[0] = 0xfd
 	uid[1] = 0x7a
 	uid[2] = 0x11
@@ -298,6 +373,8 @@ type Conn struct {
 	closed         chan struct{}
 	logger         slog.Logger
 	blockEndpoints bool
+	// staticEndpoints are advertised ahead of discovered endpoints.
+	staticEndpoints []netip.AddrPort
 
 	dialer             *tsdial.Dialer
 	tunDevice          *tstun.Wrapper
@@ -661,8 +738,14 @@ func (c *Conn) Node() *Node {
 }
 
 func (c *Conn) selfNode() *Node {
-	endpoints := make([]string, 0, len(c.lastEndpoints))
+	endpoints := make([]string, 0, len(c.staticEndpoints)+len(c.lastEndpoints))
+	for _, addr := range c.staticEndpoints {
+		endpoints = append(endpoints, addr.String())
+	}
 	for _, addr := range c.lastEndpoints {
+		if slices.Contains(c.staticEndpoints, addr.Addr) {
+			continue
+		}
 		endpoints = append(endpoints, addr.Addr.String())
 	}
 	var preferredDERP int
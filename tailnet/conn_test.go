@@ -2,6 +2,7 @@ package tailnet_test
 
 import (
 	"context"
+	"net"
 	"net/netip"
 	"testing"
 
@@ -97,6 +98,102 @@ func TestTailnet(t *testing.T) {
 		w2.Close()
 	})
 
+	t.Run("ListenPortAndStaticEndpoints", func(t *testing.T) {
+		t.Parallel()
+		udp, err := net.ListenUDP("udp", &net.UDPAddr{})
+		require.NoError(t, err)
+		port := uint16(udp.LocalAddr().(*net.UDPAddr).Port)
+		_ = udp.Close()
+
+		staticEndpoint := netip.MustParseAddrPort("203.0.113.1:30641")
+		conn, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:       []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+			Logger:          logger.Named("w1"),
+			DERPMap:         derpMap,
+			ListenPort:      port,
+			ListenPortEnd:   port + 10,
+			StaticEndpoints: []netip.AddrPort{staticEndpoint},
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// The static endpoint is advertised first, followed by the endpoints
+		// on the listen port.
+		require.Eventually(t, func() bool {
+			endpoints := conn.Node().Endpoints
+			if len(endpoints) < 2 || endpoints[0] != staticEndpoint.String() {
+				return false
+			}
+			for _, endpoint := range endpoints[1:] {
+				addrPort, err := netip.ParseAddrPort(endpoint)
+				if err == nil && addrPort.Port() == port {
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("ListenPortInUse", func(t *testing.T) {
+		t.Parallel()
+		udp, err := net.ListenUDP("udp", &net.UDPAddr{})
+		require.NoError(t, err)
+		defer udp.Close()
+		port := uint16(udp.LocalAddr().(*net.UDPAddr).Port)
+
+		conn, err := tailnet.NewConn(&tailnet.Options{
+			Addresses:     []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+			Logger:        logger.Named("w1"),
+			DERPMap:       derpMap,
+			ListenPort:    port,
+			ListenPortEnd: port + 10,
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// The next free port of the range is used.
+		var listenPort uint16
+		require.Eventually(t, func() bool {
+			for _, endpoint := range conn.Node().Endpoints {
+				addrPort, err := netip.ParseAddrPort(endpoint)
+				if err == nil && addrPort.Port() > port && addrPort.Port() <= port+10 {
+					listenPort = addrPort.Port()
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Adding a peer reconfigures the engine, which must keep the port.
+		peerIP := tailnet.IP()
+		peer, err := tailnet.NewConn(&tailnet.Options{
+			Addresses: []netip.Prefix{netip.PrefixFrom(peerIP, 128)},
+			Logger:    logger.Named("w2"),
+			DERPMap:   derpMap,
+		})
+		require.NoError(t, err)
+		defer peer.Close()
+		conn.SetNodeCallback(func(node *tailnet.Node) {
+			err := peer.UpdateNodes([]*tailnet.Node{node}, false)
+			assert.NoError(t, err)
+		})
+		peer.SetNodeCallback(func(node *tailnet.Node) {
+			err := conn.UpdateNodes([]*tailnet.Node{node}, false)
+			assert.NoError(t, err)
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		require.True(t, conn.AwaitReachable(ctx, peerIP))
+
+		// The port is still bound by the connection and advertised.
+		_, err = net.ListenUDP("udp4", &net.UDPAddr{Port: int(listenPort)})
+		require.Error(t, err)
+		for _, endpoint := range conn.Node().Endpoints {
+			addrPort, err := netip.ParseAddrPort(endpoint)
+			require.NoError(t, err)
+			require.Equal(t, listenPort, addrPort.Port())
+		}
+	})
+
 	t.Run("ForcesWebSockets", func(t *testing.T) {
 		t.Parallel()
 		ctx := testutil.Context(t, testutil.WaitLong)
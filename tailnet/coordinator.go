@@ -43,6 +43,19 @@ type Coordinator interface {
 	Close() error
 }
 
+// ResumingCoordinator is a Coordinator that persists versioned nodes, so
+// reconnecting peers only receive the nodes that changed since the last
+// version they saw instead of a full resync.
+type ResumingCoordinator interface {
+	Coordinator
+	// ServeClientResume is like ServeClient, but only sends the agent node if
+	// its version is newer than the given version.
+	ServeClientResume(conn net.Conn, id uuid.UUID, agent uuid.UUID, version int64) error
+	// ServeAgentResume is like ServeAgent, but only sends the client nodes
+	// with a version newer than the given version.
+	ServeAgentResume(conn net.Conn, id uuid.UUID, name string, version int64) error
+}
+
 // Node represents a node in the network.
 type Node struct {
 	// ID is used to identify the connection.
@@ -71,6 +84,11 @@ type Node struct {
 	// Endpoints are ip:port combinations that can be used to establish
 	// peer-to-peer connections.
 	Endpoints []string `json:"endpoints"`
+	// Version is set by coordinators that persist nodes. It increases with
+	// every update of the nodes sent to an agent, and of the agent's node.
+	// Peers send the highest version they received when they reconnect to
+	// resume from it.
+	Version int64 `json:"version,omitempty"`
 }
 
 // ServeCoordinator matches the RW structure of a coordinator to exchange node messages.
@@ -0,0 +1,113 @@
+package tailnet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// DNSLookupFunc returns the addresses of a fully qualified hostname without
+// the trailing dot, e.g. "main.backend.kyle.coder". The hostname is always
+// lowercase.
+type DNSLookupFunc func(hostname string) []netip.Addr
+
+// ServeDNS answers A and AAAA queries read from conn with the addresses
+// returned by lookup until the context is canceled or conn is closed. Names
+// that lookup doesn't know are answered with NXDOMAIN, so clients don't wait
+// for a timeout.
+func ServeDNS(ctx context.Context, logger slog.Logger, conn net.PacketConn, lookup DNSLookupFunc) error {
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return xerrors.Errorf("read dns query: %w", err)
+		}
+		res, err := answerDNS(buf[:n], lookup)
+		if err != nil {
+			logger.Debug(ctx, "answer dns query", slog.F("from", addr), slog.Error(err))
+			continue
+		}
+		_, err = conn.WriteTo(res, addr)
+		if err != nil {
+			logger.Debug(ctx, "write dns response", slog.F("to", addr), slog.Error(err))
+		}
+	}
+}
+
+func answerDNS(query []byte, lookup DNSLookupFunc) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, xerrors.Errorf("parse header: %w", err)
+	}
+	if header.Response {
+		return nil, xerrors.New("message is a response")
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, xerrors.Errorf("parse question: %w", err)
+	}
+
+	resHeader := dnsmessage.Header{
+		ID:                 header.ID,
+		Response:           true,
+		Authoritative:      true,
+		RecursionDesired:   header.RecursionDesired,
+		RecursionAvailable: false,
+	}
+	hostname := strings.ToLower(strings.TrimSuffix(question.Name.String(), "."))
+	addrs := lookup(hostname)
+	if len(addrs) == 0 {
+		resHeader.RCode = dnsmessage.RCodeNameError
+	}
+
+	builder := dnsmessage.NewBuilder(make([]byte, 0, 512), resHeader)
+	builder.EnableCompression()
+	err = builder.StartQuestions()
+	if err != nil {
+		return nil, err
+	}
+	err = builder.Question(question)
+	if err != nil {
+		return nil, err
+	}
+	err = builder.StartAnswers()
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		resource := dnsmessage.ResourceHeader{
+			Name:  question.Name,
+			Class: dnsmessage.ClassINET,
+			// Workspaces come and go, so answers shouldn't be cached for long.
+			TTL: 10,
+		}
+		switch {
+		case question.Type == dnsmessage.TypeA && addr.Is4():
+			err = builder.AResource(resource, dnsmessage.AResource{A: addr.As4()})
+		case question.Type == dnsmessage.TypeAAAA && addr.Is6():
+			err = builder.AAAAResource(resource, dnsmessage.AAAAResource{AAAA: addr.As16()})
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return builder.Finish()
+}
@@ -0,0 +1,59 @@
+package tailnet_test
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/tailnet"
+	"github.com/coder/coder/testutil"
+)
+
+func TestServeDNS(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ipv4 := netip.MustParseAddr("127.42.0.1")
+	ipv6 := tailnet.IP()
+	done := make(chan error, 1)
+	go func() {
+		done <- tailnet.ServeDNS(ctx, slogtest.Make(t, nil).Leveled(slog.LevelDebug), conn, func(hostname string) []netip.Addr {
+			if hostname != "main.backend.kyle.coder" {
+				return nil
+			}
+			return []netip.Addr{ipv4, ipv6}
+		})
+	}()
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+	addrs, err := resolver.LookupNetIP(ctx, "ip4", "main.backend.kyle.coder")
+	require.NoError(t, err)
+	require.Equal(t, []netip.Addr{ipv4}, addrs)
+
+	// Hostnames are case-insensitive.
+	addrs, err = resolver.LookupNetIP(ctx, "ip6", "MAIN.backend.kyle.coder")
+	require.NoError(t, err)
+	require.Equal(t, []netip.Addr{ipv6}, addrs)
+
+	_, err = resolver.LookupNetIP(ctx, "ip", "frontend.kyle.coder")
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+	require.True(t, dnsErr.IsNotFound)
+
+	cancel()
+	require.NoError(t, <-done)
+}